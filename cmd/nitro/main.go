@@ -8,7 +8,15 @@ import (
 
 func main() {
 	// execute the nitro root command
-	if err := nitro.NewCommand().Execute(); err != nil {
+	err := nitro.NewCommand().Execute()
+
+	// show the execution time when debugging
+	nitro.ShowExecutionTime()
+
+	// let the user know when there is a new version
+	nitro.ShowUpdateNotice()
+
+	if err != nil {
 		os.Exit(1)
 	}
 }
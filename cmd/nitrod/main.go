@@ -4,11 +4,17 @@ import (
 	"flag"
 	"log"
 	"net"
+	"net/http"
 	"os"
+	"strings"
 
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
 	"google.golang.org/grpc"
 
 	"github.com/craftcms/nitro/pkg/api"
+	"github.com/craftcms/nitro/pkg/dashboard"
+	"github.com/craftcms/nitro/pkg/metrics"
 	"github.com/craftcms/nitro/protob"
 )
 
@@ -30,12 +36,35 @@ func main() {
 	// create the grpc server
 	s := grpc.NewServer()
 
-	protob.RegisterNitroServer(s, api.NewService(*addr))
+	svc := api.NewService(*addr)
 
-	log.Println("gRPC API listening on port", *port)
+	protob.RegisterNitroServer(s, svc)
+
+	log.Println("gRPC API and metrics listening on port", *port)
+
+	// serve the dashboard, caddy proxies the requests for the dashboard hostname to it
+	go func() {
+		if err := http.ListenAndServe(dashboard.Addr, svc.Dashboard); err != nil {
+			log.Println("error when running the dashboard", err)
+		}
+	}()
+
+	// serve the metrics on the same port as the grpc service, the grpc requests use
+	// HTTP/2 without TLS so both are served by the same handler
+	mux := http.NewServeMux()
+	mux.Handle(metrics.Path, svc.Metrics)
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ProtoMajor == 2 && strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc") {
+			s.ServeHTTP(w, r)
+			return
+		}
+
+		mux.ServeHTTP(w, r)
+	})
 
 	// server the grpc service
-	if err := s.Serve(lis); err != nil {
+	if err := http.Serve(lis, h2c.NewHandler(handler, &http2.Server{})); err != nil {
 		log.Fatal("error when running the gRPC API", err)
 	}
 }
@@ -11,6 +11,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/spf13/cobra"
 
+	"github.com/craftcms/nitro/pkg/clipboard"
 	"github.com/craftcms/nitro/pkg/envedit"
 	"github.com/craftcms/nitro/pkg/pathexists"
 	"github.com/craftcms/nitro/pkg/prompt"
@@ -21,7 +22,10 @@ const exampleText = `  # add the current directory as a site
   nitro add
 
   # add a directory as the site
-  nitro add my-project`
+  nitro add my-project
+
+  # add the site and copy its URL to the clipboard
+  nitro add --copy`
 
 // NewCommand returns the command to add a site to the nitro config.
 func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outputer) *cobra.Command {
@@ -63,7 +67,8 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 
 			output.Info("Adding site…")
 
-			if _, err := prompt.CreateSite(home, dir, output); err != nil {
+			site, err := prompt.CreateSite(home, dir, output)
+			if err != nil {
 				return err
 			}
 
@@ -155,9 +160,22 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 
 			output.Info("New site added! 🎉")
 
+			url := "https://" + site.Hostname
+			output.Info("Your site will be available at", terminal.Hyperlink(url, url))
+
+			if copyURL, _ := cmd.Flags().GetBool("copy"); copyURL {
+				if err := clipboard.Copy(url); err != nil {
+					return err
+				}
+
+				output.Info("Copied the site URL to the clipboard.")
+			}
+
 			return nil
 		},
 	}
 
+	cmd.Flags().Bool("copy", false, "copy the site URL to the clipboard")
+
 	return cmd
 }
@@ -0,0 +1,328 @@
+package adopt
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+	"github.com/spf13/cobra"
+
+	"github.com/craftcms/nitro/command/internal/service"
+	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/prompt"
+	"github.com/craftcms/nitro/pkg/terminal"
+	"github.com/craftcms/nitro/pkg/validate"
+	"github.com/craftcms/nitro/pkg/webroot"
+)
+
+const exampleText = `  # manage an existing container with nitro
+  nitro adopt my-elasticsearch
+
+  # adopt a container without stopping it
+  nitro adopt my-mysql --keep-running`
+
+var (
+	// ErrManaged is returned when the container was created by nitro
+	ErrManaged = fmt.Errorf("the container is already managed by Nitro")
+
+	// invalidName matches the characters that are not allowed in a custom container name
+	invalidName = regexp.MustCompile(`[^a-z0-9-]+`)
+)
+
+// adoption is the config inferred from an existing container. Only one of the
+// site, database, service, or container is set.
+type adoption struct {
+	Site      *config.Site
+	Database  *config.Database
+	Service   string
+	Container *config.Container
+
+	// Env are the environment variables for a custom container
+	Env []string
+}
+
+// Kind returns the type of resource the container is adopted as.
+func (a adoption) Kind() string {
+	switch {
+	case a.Site != nil:
+		return "site"
+	case a.Database != nil:
+		return "database"
+	case a.Service != "":
+		return "service"
+	default:
+		return "container"
+	}
+}
+
+// NewCommand returns the command to adopt a container that was not created by nitro. Docker
+// does not allow the labels of an existing container to be changed, so the container is
+// inspected and added to the config as a site, database, service, or custom container and
+// apply creates a labeled container to replace it. The original container is stopped but
+// not removed, and data in its volumes is not copied.
+func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outputer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "adopt CONTAINER",
+		Short:   "Adds an existing container to Nitro.",
+		Example: exampleText,
+		Args:    cobra.ExactArgs(1),
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return prompt.VerifyInit(cmd, args, home, output)
+		},
+		PostRunE: func(cmd *cobra.Command, args []string) error {
+			return prompt.RunApply(cmd, args, false, output)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			cfg, err := config.Load(home)
+			if err != nil {
+				return err
+			}
+
+			details, err := docker.ContainerInspect(ctx, args[0])
+			if err != nil {
+				return fmt.Errorf("unable to find the container %q, %w", args[0], err)
+			}
+
+			if _, ok := details.Config.Labels[containerlabels.Nitro]; ok {
+				return ErrManaged
+			}
+
+			a, err := infer(details, cfg.GetTLD())
+			if err != nil {
+				return err
+			}
+
+			name := strings.TrimLeft(details.Name, "/")
+
+			output.Info(fmt.Sprintf("Adopting %s as a %s…", name, a.Kind()))
+
+			switch {
+			case a.Site != nil:
+				hostname, err := output.Ask("Enter the hostname", a.Site.Hostname, ":", &validate.HostnameValidator{})
+				if err != nil {
+					return err
+				}
+
+				a.Site.Hostname = hostname
+
+				// use the same web root detection as adding a site
+				if found, _ := webroot.Find(a.Site.Path); found != "" {
+					a.Site.Webroot = found
+				}
+
+				a.Site.Path = strings.Replace(a.Site.Path, home, "~", 1)
+
+				if err := cfg.AddSite(*a.Site); err != nil {
+					return err
+				}
+			case a.Database != nil:
+				if err := cfg.AddDatabase(*a.Database); err != nil {
+					return err
+				}
+			case a.Service != "":
+				if err := cfg.Services.Set(a.Service, true); err != nil {
+					return err
+				}
+			default:
+				// only keep the environment variables that are not set by the image
+				if image, _, err := docker.ImageInspectWithRaw(ctx, details.Image); err == nil && image.Config != nil {
+					a.Env = without(a.Env, image.Config.Env)
+				}
+
+				if len(a.Env) > 0 {
+					file := filepath.Join(home, config.DirectoryName, "."+a.Container.Name)
+					if err := ioutil.WriteFile(file, []byte(strings.Join(a.Env, "\n")+"\n"), 0644); err != nil {
+						return fmt.Errorf("unable to create environment file: %w", err)
+					}
+
+					_, a.Container.EnvFile = filepath.Split(file)
+				}
+
+				if err := cfg.AddContainer(*a.Container); err != nil {
+					return err
+				}
+			}
+
+			if err := cfg.Save(); err != nil {
+				return err
+			}
+
+			output.Info(fmt.Sprintf("Added the %s to %s", a.Kind(), cfg.GetFile()))
+
+			// the original container would conflict with the ports of the new container
+			if details.State != nil && details.State.Running && cmd.Flag("keep-running").Value.String() != "true" {
+				output.Pending("stopping", name)
+
+				timeout := 10 * time.Second
+				if err := docker.ContainerStop(ctx, details.ID, &timeout); err != nil {
+					output.Warning()
+					return fmt.Errorf("unable to stop the container, %w", err)
+				}
+
+				output.Done()
+			}
+
+			// the site container is named after the hostname
+			if a.Site != nil && a.Site.Hostname == name {
+				if err := docker.ContainerRename(ctx, details.ID, name+"-original"); err != nil {
+					return fmt.Errorf("unable to rename the container, %w", err)
+				}
+
+				name = name + "-original"
+			}
+
+			switch a.Kind() {
+			case "database":
+				output.Info("The data in the original container is not copied, use `nitro db import` with a backup once the database is created.")
+			case "container":
+				if len(a.Container.Volumes) > 0 {
+					output.Info("The data in the original volumes is not copied to the new volumes.")
+				}
+			}
+
+			output.Info(fmt.Sprintf("Remove the original container with `docker rm %s` once the adopted %s is working.", name, a.Kind()))
+
+			return nil
+		},
+	}
+
+	cmd.Flags().Bool("keep-running", false, "do not stop the original container")
+
+	return cmd
+}
+
+// infer uses the image, mounts, and ports of the container to decide how it should be added
+// to the config. Craft images with a mounted project are sites, the official database
+// images are databases, the service images are services, and anything else is a custom
+// container.
+func infer(details types.ContainerJSON, tld string) (*adoption, error) {
+	if details.Config == nil || details.HostConfig == nil {
+		return nil, fmt.Errorf("unable to inspect the container configuration")
+	}
+
+	repo, tag := reference(details.Config.Image)
+	name := strings.TrimLeft(details.Name, "/")
+
+	// check for a service before the databases, redis is not a database
+	for _, s := range service.Services {
+		if r, _ := reference(s.Image); r == repo {
+			return &adoption{Service: s.Name}, nil
+		}
+	}
+
+	switch repo {
+	case "craftcms/nginx", "craftcms/php-fpm":
+		var path string
+		for _, m := range details.Mounts {
+			if m.Destination == "/app" {
+				path = m.Source
+			}
+		}
+
+		if path == "" {
+			return nil, fmt.Errorf("unable to find the project mounted at /app in %s", name)
+		}
+
+		hostname := name
+		if !strings.Contains(hostname, ".") {
+			hostname = fmt.Sprintf("%s.%s", hostname, tld)
+		}
+
+		return &adoption{Site: &config.Site{
+			Hostname: hostname,
+			Path:     path,
+			Version:  strings.TrimSuffix(tag, "-dev"),
+			Webroot:  "web",
+		}}, nil
+	case "mysql", "mariadb", "postgres":
+		if tag == "latest" {
+			return nil, fmt.Errorf("unable to determine the version of %s from the image %s, use an image with a version tag", name, details.Config.Image)
+		}
+
+		internal := "3306"
+		if repo == "postgres" {
+			internal = "5432"
+		}
+
+		port := internal
+		for p, bindings := range details.HostConfig.PortBindings {
+			if p.Port() == internal && len(bindings) > 0 && bindings[0].HostPort != "" {
+				port = bindings[0].HostPort
+			}
+		}
+
+		return &adoption{Database: &config.Database{Engine: repo, Version: tag, Port: port}}, nil
+	}
+
+	c := &config.Container{
+		Name:  strings.Trim(invalidName.ReplaceAllString(strings.ToLower(name), "-"), "-"),
+		Image: repo,
+		Tag:   tag,
+	}
+
+	for p, bindings := range details.HostConfig.PortBindings {
+		if p.Proto() != "tcp" || len(bindings) == 0 || bindings[0].HostPort == "" {
+			continue
+		}
+
+		c.Ports = append(c.Ports, fmt.Sprintf("%s:%s", bindings[0].HostPort, p.Port()))
+	}
+
+	for _, m := range details.Mounts {
+		if m.Type == "volume" {
+			c.Volumes = append(c.Volumes, m.Destination)
+		}
+	}
+
+	// the port bindings are a map, so sort them for a predictable config
+	sort.Strings(c.Ports)
+	sort.Strings(c.Volumes)
+
+	return &adoption{Container: c, Env: details.Config.Env}, nil
+}
+
+// reference returns the repository, without the default registry, and the tag of an image.
+func reference(image string) (string, string) {
+	repo, tag := image, "latest"
+
+	// remove the digest
+	if i := strings.Index(repo, "@"); i != -1 {
+		repo = repo[:i]
+	}
+
+	// the tag is after the last colon, unless the colon is for a registry port
+	if i := strings.LastIndex(repo, ":"); i != -1 && !strings.Contains(repo[i:], "/") {
+		repo, tag = repo[:i], repo[i+1:]
+	}
+
+	repo = strings.TrimPrefix(repo, "docker.io/")
+	repo = strings.TrimPrefix(repo, "library/")
+
+	return repo, tag
+}
+
+// without returns the values that are not in exclude.
+func without(values, exclude []string) []string {
+	excluded := map[string]bool{}
+	for _, e := range exclude {
+		excluded[e] = true
+	}
+
+	var remaining []string
+	for _, v := range values {
+		if !excluded[v] {
+			remaining = append(remaining, v)
+		}
+	}
+
+	return remaining
+}
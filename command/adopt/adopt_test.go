@@ -0,0 +1,117 @@
+package adopt
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/go-connections/nat"
+
+	"github.com/craftcms/nitro/pkg/config"
+)
+
+func TestInfer(t *testing.T) {
+	tests := []struct {
+		name    string
+		details types.ContainerJSON
+		want    adoption
+		wantErr bool
+	}{
+		{
+			name: "craft images with a mounted project are sites",
+			details: details("/demo", "docker.io/craftcms/nginx:8.0-dev", nil, []types.MountPoint{
+				{Type: mount.TypeBind, Source: "/home/user/dev/demo", Destination: "/app"},
+			}),
+			want: adoption{Site: &config.Site{Hostname: "demo.nitro", Path: "/home/user/dev/demo", Version: "8.0", Webroot: "web"}},
+		},
+		{
+			name:    "sites require a mounted project",
+			details: details("/demo", "craftcms/nginx:8.0-dev", nil, nil),
+			wantErr: true,
+		},
+		{
+			name: "database images use the host port",
+			details: details("/db", "mysql:5.7", nat.PortMap{
+				"3306/tcp": {{HostIP: "127.0.0.1", HostPort: "33306"}},
+			}, nil),
+			want: adoption{Database: &config.Database{Engine: "mysql", Version: "5.7", Port: "33306"}},
+		},
+		{
+			name:    "database images require a version",
+			details: details("/db", "postgres", nil, nil),
+			wantErr: true,
+		},
+		{
+			name:    "service images are services",
+			details: details("/cache", "redis:6", nil, nil),
+			want:    adoption{Service: "redis"},
+		},
+		{
+			name: "other images are custom containers",
+			details: details("/My_Search", "docker.elastic.co/elasticsearch/elasticsearch:7.10.1", nat.PortMap{
+				"9300/tcp": {{HostPort: "9300"}},
+				"9200/tcp": {{HostPort: "9201"}},
+				"9400/udp": {{HostPort: "9400"}},
+			}, []types.MountPoint{
+				{Type: mount.TypeVolume, Name: "es", Destination: "/usr/share/elasticsearch/data"},
+				{Type: mount.TypeBind, Source: "/tmp", Destination: "/tmp"},
+			}),
+			want: adoption{
+				Container: &config.Container{
+					Name:    "my-search",
+					Image:   "docker.elastic.co/elasticsearch/elasticsearch",
+					Tag:     "7.10.1",
+					Ports:   []string{"9201:9200", "9300:9300"},
+					Volumes: []string{"/usr/share/elasticsearch/data"},
+				},
+				Env: []string{"PATH=/usr/bin", "discovery.type=single-node"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := infer(tt.details, "nitro")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("infer() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			if !reflect.DeepEqual(*got, tt.want) {
+				t.Errorf("infer() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReference(t *testing.T) {
+	tests := []struct {
+		image, repo, tag string
+	}{
+		{"mysql", "mysql", "latest"},
+		{"docker.io/library/postgres:13", "postgres", "13"},
+		{"localhost:5000/app", "localhost:5000/app", "latest"},
+		{"localhost:5000/app:v1@sha256:abc", "localhost:5000/app", "v1"},
+	}
+	for _, tt := range tests {
+		repo, tag := reference(tt.image)
+		if repo != tt.repo || tag != tt.tag {
+			t.Errorf("reference(%q) = %q, %q, want %q, %q", tt.image, repo, tag, tt.repo, tt.tag)
+		}
+	}
+}
+
+func details(name, image string, ports nat.PortMap, mounts []types.MountPoint) types.ContainerJSON {
+	return types.ContainerJSON{
+		ContainerJSONBase: &types.ContainerJSONBase{
+			Name:       name,
+			HostConfig: &container.HostConfig{PortBindings: ports},
+		},
+		Config: &container.Config{Image: image, Env: []string{"PATH=/usr/bin", "discovery.type=single-node"}},
+		Mounts: mounts,
+	}
+}
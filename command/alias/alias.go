@@ -78,7 +78,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 					site, _ = cfg.FindSiteByHostName(options[0])
 				default:
 					// prompt for the site to alias
-					selected, err := output.Select(cmd.InOrStdin(), "Select a site: ", options)
+					selected, err := prompt.Select(cmd.InOrStdin(), "Select a site: ", options, output)
 					if err != nil {
 						return err
 					}
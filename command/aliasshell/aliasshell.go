@@ -0,0 +1,292 @@
+package aliasshell
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/spf13/cobra"
+
+	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/pathexists"
+	"github.com/craftcms/nitro/pkg/terminal"
+)
+
+const (
+	exampleText = `  # install the shell functions for the current shell
+  nitro alias-shell
+
+  # install the shell functions for zsh
+  nitro alias-shell zsh
+
+  # load the shell functions without installing them
+  eval "$(nitro alias-shell bash --print)"
+
+  # remove the shell functions
+  nitro alias-shell --remove`
+
+	startText = "# <nitro-aliases>"
+	endText   = "# </nitro-aliases>"
+)
+
+var (
+	// Commands are the commands that are proxied to nitro in a project directory
+	Commands = []string{"composer", "craft", "npm"}
+
+	// ErrNotProject is returned by check when the working directory is not in a site
+	ErrNotProject = fmt.Errorf("the current directory is not part of a site")
+
+	scripts = map[string]*template.Template{
+		"bash": template.Must(template.New("bash").Parse(posixTemplate)),
+		"zsh":  template.Must(template.New("zsh").Parse(posixTemplate)),
+		"fish": template.Must(template.New("fish").Parse(fishTemplate)),
+	}
+)
+
+const posixTemplate = `# nitro shell functions, installed by nitro alias-shell
+{{- range .}}
+{{.}}() {
+  if command nitro alias-shell check >/dev/null 2>&1; then
+    command nitro {{.}} "$@"
+  else
+    command {{.}} "$@"
+  fi
+}
+{{- end}}
+`
+
+const fishTemplate = `# nitro shell functions, installed by nitro alias-shell
+{{- range .}}
+function {{.}}
+    if command nitro alias-shell check >/dev/null 2>&1
+        command nitro {{.}} $argv
+    else
+        command {{.}} $argv
+    end
+end
+{{- end}}
+`
+
+// NewCommand returns the command to install shell functions that run the composer, craft, and
+// npm commands with nitro when the working directory is in a site, and run the installed
+// commands everywhere else. The functions are written to the nitro directory and sourced
+// from the startup file of the shell.
+func NewCommand(home string, output terminal.Outputer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:       "alias-shell [bash|zsh|fish]",
+		Short:     "Installs shell functions for Nitro commands.",
+		Example:   exampleText,
+		Args:      cobra.MaximumNArgs(1),
+		ValidArgs: []string{"bash", "zsh", "fish"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			shell := filepath.Base(os.Getenv("SHELL"))
+			if len(args) > 0 {
+				shell = args[0]
+			}
+
+			if _, ok := scripts[shell]; !ok {
+				return fmt.Errorf("unsupported shell %q, use bash, zsh, or fish", shell)
+			}
+
+			script, err := Script(shell)
+			if err != nil {
+				return err
+			}
+
+			if cmd.Flag("print").Value.String() == "true" {
+				fmt.Fprint(cmd.OutOrStdout(), script)
+
+				return nil
+			}
+
+			file := filepath.Join(home, config.DirectoryName, "aliases."+shell)
+			rc := startupFile(home, shell)
+
+			if cmd.Flag("remove").Value.String() == "true" {
+				if err := removeBlock(rc); err != nil {
+					return err
+				}
+
+				if err := os.RemoveAll(file); err != nil {
+					return err
+				}
+
+				output.Info(fmt.Sprintf("Removed the shell functions from %s", rc))
+
+				return nil
+			}
+
+			if err := ioutil.WriteFile(file, []byte(script), 0644); err != nil {
+				return fmt.Errorf("unable to write the shell functions, %w", err)
+			}
+
+			if err := addBlock(rc, shell, file); err != nil {
+				return err
+			}
+
+			output.Info(fmt.Sprintf("Added the shell functions for %s to %s", strings.Join(Commands, ", "), rc))
+			output.Info("Open a new terminal or run `source " + rc + "` to use them.")
+
+			return nil
+		},
+	}
+
+	cmd.Flags().Bool("print", false, "show the shell functions without installing them")
+	cmd.Flags().Bool("remove", false, "remove the shell functions")
+
+	cmd.AddCommand(checkCommand(home))
+
+	return cmd
+}
+
+// checkCommand returns the hidden command the shell functions use to decide if the
+// working directory is in a site. It returns an error when it is not.
+func checkCommand(home string) *cobra.Command {
+	return &cobra.Command{
+		Use:           "check",
+		Hidden:        true,
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			wd, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+
+			cfg, err := config.Load(home)
+			if err != nil {
+				return err
+			}
+
+			if !inProject(cfg, home, wd) {
+				return ErrNotProject
+			}
+
+			return nil
+		},
+	}
+}
+
+// Script returns the shell functions for the shell.
+func Script(shell string) (string, error) {
+	tmpl, ok := scripts[shell]
+	if !ok {
+		return "", fmt.Errorf("unsupported shell %q", shell)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := tmpl.Execute(buf, Commands); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+// inProject returns true if the working directory is the path of a site or one of its
+// subdirectories.
+func inProject(cfg *config.Config, home, wd string) bool {
+	for _, s := range cfg.Sites {
+		p, err := s.GetAbsPath(home)
+		if err != nil {
+			continue
+		}
+
+		rel, err := filepath.Rel(p, wd)
+		if err != nil {
+			continue
+		}
+
+		if rel != ".." && !strings.HasPrefix(rel, ".."+string(os.PathSeparator)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// startupFile returns the file the shell runs for new interactive shells.
+func startupFile(home, shell string) string {
+	switch shell {
+	case "zsh":
+		if dir := os.Getenv("ZDOTDIR"); dir != "" {
+			return filepath.Join(dir, ".zshrc")
+		}
+
+		return filepath.Join(home, ".zshrc")
+	case "fish":
+		return filepath.Join(home, ".config", "fish", "config.fish")
+	default:
+		return filepath.Join(home, ".bashrc")
+	}
+}
+
+// addBlock adds the lines to source the shell functions to the startup file, unless the
+// file already sources them.
+func addBlock(rc, shell, file string) error {
+	var content []byte
+	if pathexists.IsFile(rc) {
+		b, err := ioutil.ReadFile(rc)
+		if err != nil {
+			return err
+		}
+
+		content = b
+	}
+
+	if bytes.Contains(content, []byte(startText)) {
+		return nil
+	}
+
+	source := fmt.Sprintf("[ -f %q ] && . %q", file, file)
+	if shell == "fish" {
+		source = fmt.Sprintf("test -f %q; and source %q", file, file)
+	}
+
+	if len(content) > 0 && !bytes.HasSuffix(content, []byte("\n")) {
+		content = append(content, '\n')
+	}
+
+	content = append(content, []byte(fmt.Sprintf("\n%s\n%s\n%s\n", startText, source, endText))...)
+
+	if err := os.MkdirAll(filepath.Dir(rc), 0755); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(rc, content, 0644)
+}
+
+// removeBlock removes the lines added by addBlock from the startup file.
+func removeBlock(rc string) error {
+	if !pathexists.IsFile(rc) {
+		return nil
+	}
+
+	content, err := ioutil.ReadFile(rc)
+	if err != nil {
+		return err
+	}
+
+	var lines []string
+	inBlock := false
+	for _, l := range strings.Split(string(content), "\n") {
+		switch {
+		case l == startText:
+			inBlock = true
+
+			// remove the empty line added before the block
+			if len(lines) > 0 && lines[len(lines)-1] == "" {
+				lines = lines[:len(lines)-1]
+			}
+		case l == endText:
+			inBlock = false
+		case !inBlock:
+			lines = append(lines, l)
+		}
+	}
+
+	return ioutil.WriteFile(rc, []byte(strings.Join(lines, "\n")), 0644)
+}
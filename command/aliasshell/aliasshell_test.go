@@ -0,0 +1,95 @@
+package aliasshell
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/craftcms/nitro/pkg/config"
+)
+
+func TestInProject(t *testing.T) {
+	cfg := &config.Config{Sites: []config.Site{{Hostname: "craft.nitro", Path: "~/dev/craft"}}}
+	home := filepath.FromSlash("/home/user")
+
+	tests := []struct {
+		wd   string
+		want bool
+	}{
+		{wd: "/home/user/dev/craft", want: true},
+		{wd: "/home/user/dev/craft/templates", want: true},
+		{wd: "/home/user/dev/craft-two", want: false},
+		{wd: "/home/user/dev", want: false},
+	}
+	for _, tt := range tests {
+		if got := inProject(cfg, home, filepath.FromSlash(tt.wd)); got != tt.want {
+			t.Errorf("inProject(%q) = %v, want %v", tt.wd, got, tt.want)
+		}
+	}
+}
+
+func TestAddAndRemoveBlock(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nitro-alias-shell")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	rc := filepath.Join(dir, ".bashrc")
+	original := "export PATH=$PATH:/usr/local/bin\n"
+	if err := ioutil.WriteFile(rc, []byte(original), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// adding twice only adds the block once
+	for i := 0; i < 2; i++ {
+		if err := addBlock(rc, "bash", "/home/user/.nitro/aliases.bash"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	content, err := ioutil.ReadFile(rc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n := strings.Count(string(content), startText); n != 1 {
+		t.Errorf("expected the block to be added once, got %d\n%s", n, content)
+	}
+
+	if !strings.Contains(string(content), `[ -f "/home/user/.nitro/aliases.bash" ] && . "/home/user/.nitro/aliases.bash"`) {
+		t.Errorf("expected the block to source the script, got\n%s", content)
+	}
+
+	if err := removeBlock(rc); err != nil {
+		t.Fatal(err)
+	}
+
+	content, err = ioutil.ReadFile(rc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(content) != original {
+		t.Errorf("expected the file to be restored, got %q", content)
+	}
+}
+
+func TestScript(t *testing.T) {
+	script, err := Script("zsh")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, s := range []string{"craft() {", `command nitro craft "$@"`, `command composer "$@"`} {
+		if !strings.Contains(script, s) {
+			t.Errorf("expected the script to contain %q, got\n%s", s, script)
+		}
+	}
+
+	if _, err := Script("powershell"); err == nil {
+		t.Errorf("expected an error for an unsupported shell")
+	}
+}
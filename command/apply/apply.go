@@ -8,6 +8,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"sort"
 	"strings"
 	"time"
 
@@ -16,22 +17,34 @@ import (
 	"github.com/docker/docker/client"
 	"github.com/spf13/cobra"
 
-	"github.com/craftcms/nitro/command/apply/internal/customcontainer"
-	"github.com/craftcms/nitro/command/apply/internal/databasecontainer"
-	"github.com/craftcms/nitro/command/apply/internal/sitecontainer"
+	"github.com/craftcms/nitro/command/internal/backupcontainer"
+	"github.com/craftcms/nitro/command/internal/customcontainer"
+	"github.com/craftcms/nitro/command/internal/databasecontainer"
+	"github.com/craftcms/nitro/command/internal/hook"
+	"github.com/craftcms/nitro/command/internal/plan"
+	"github.com/craftcms/nitro/command/internal/preflight"
+	"github.com/craftcms/nitro/command/internal/queuecontainer"
+	"github.com/craftcms/nitro/command/internal/service"
+	"github.com/craftcms/nitro/command/internal/sitecontainer"
+	"github.com/craftcms/nitro/command/internal/snapshot"
 	"github.com/craftcms/nitro/pkg/backup"
 	"github.com/craftcms/nitro/pkg/config"
 	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/database"
+	"github.com/craftcms/nitro/pkg/environment"
+	"github.com/craftcms/nitro/pkg/health"
 	"github.com/craftcms/nitro/pkg/wsl"
 
 	"github.com/craftcms/nitro/pkg/datetime"
+	"github.com/craftcms/nitro/pkg/dockercontext"
 	"github.com/craftcms/nitro/pkg/hostedit"
+	"github.com/craftcms/nitro/pkg/logconfig"
+	"github.com/craftcms/nitro/pkg/mutagen"
+	"github.com/craftcms/nitro/pkg/netdrive"
+	"github.com/craftcms/nitro/pkg/offline"
 	"github.com/craftcms/nitro/pkg/proxycontainer"
+	"github.com/craftcms/nitro/pkg/proxyroutes"
 	"github.com/craftcms/nitro/pkg/sudo"
-	"github.com/craftcms/nitro/pkg/svc/dynamodb"
-	"github.com/craftcms/nitro/pkg/svc/mailhog"
-	"github.com/craftcms/nitro/pkg/svc/minio"
-	"github.com/craftcms/nitro/pkg/svc/redis"
 	"github.com/craftcms/nitro/pkg/terminal"
 	"github.com/craftcms/nitro/protob"
 )
@@ -42,12 +55,36 @@ var (
 	isWSL       = false
 )
 
+// result is the JSON output of the command.
+type result struct {
+	Hostnames []string `json:"hostnames"`
+	Removed   []string `json:"removed"`
+}
+
 const exampleText = `  # apply changes from a config
   nitro apply
 
   # skip editing the hosts file
   nitro apply --skip-hosts
 
+  # show the changes without applying them
+  nitro apply --dry-run
+
+  # show the changes as JSON
+  nitro apply --dry-run --json
+
+  # restore the containers from before the last apply
+  nitro apply --rollback
+
+  # apply the config each time the config file is saved
+  nitro apply --watch
+
+  # use the next free ports when the ports in the config are in use
+  nitro apply --auto-ports
+
+  # apply a config file that is not in ~/.nitro, it uses the environment named after the file
+  nitro apply --config ./environments/staging-clone.yaml
+
   # you can also set the environment variable "NITRO_EDIT_HOSTS" to "false"`
 
 // NewCommand returns the command used to apply configuration file changes to a nitro environment.
@@ -65,6 +102,17 @@ func NewCommand(home string, docker client.CommonAPIClient, nitrod protob.NitroC
 			return nil
 		},
 		PostRunE: func(cmd *cobra.Command, args []string) error {
+			// the plan includes the containers that would be removed and a rollback
+			// restores the containers that are not in the config
+			if cmd.Flag("dry-run").Value.String() == "true" || cmd.Flag("rollback").Value.String() == "true" {
+				return nil
+			}
+
+			// the containers are cleaned up after each apply while watching
+			if cmd.Flag("watch").Value.String() == "true" {
+				return nil
+			}
+
 			ctx := cmd.Context()
 			if ctx == nil {
 				c, cancel := context.WithTimeout(context.Background(), time.Minute*5)
@@ -72,529 +120,818 @@ func NewCommand(home string, docker client.CommonAPIClient, nitrod protob.NitroC
 				ctx = c
 			}
 
-			// load the config
-			cfg, err := config.Load(home)
-			if err != nil {
-				return err
+			return cleanup(ctx, cmd, home, docker, output)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if cmd.Flag("dry-run").Value.String() == "true" {
+				return dryRun(cmd, home, docker, output)
 			}
 
-			// store all of the known container names
-			names := map[string]bool{}
-
-			// get all of the sites as hostnames
-			for _, s := range cfg.Sites {
-				names[s.Hostname] = true
+			if cmd.Flag("rollback").Value.String() == "true" {
+				return rollback(cmd, home, docker, output)
 			}
 
-			// get the containers as hostnames
-			for _, c := range cfg.Containers {
-				names[fmt.Sprintf("%s%s", c.Name, customcontainer.Suffix)] = true
-			}
+			skipHosts := cmd.Flag("skip-hosts").Value.String() == "true"
+			autoPorts := cmd.Flag("auto-ports").Value.String() == "true"
 
-			// get all of the databases
-			for _, d := range cfg.Databases {
-				h, _ := d.GetHostname()
-				names[h] = true
-			}
+			if cmd.Flag("watch").Value.String() == "true" {
+				if terminal.JSON {
+					return fmt.Errorf("the --watch flag can not be used with --json")
+				}
 
-			// is dynamodb enabled
-			if cfg.Services.DynamoDB {
-				names[dynamodb.Host] = true
+				return watchConfig(cmd, home, docker, nitrod, output, skipHosts, autoPorts)
 			}
 
-			// is mailhog enabled
-			if cfg.Services.Mailhog {
-				names[mailhog.Host] = true
-			}
+			if err := Run(cmd.Root().Context(), home, docker, nitrod, output, skipHosts, autoPorts); err != nil {
+				if _, serr := snapshot.Load(home); serr == nil {
+					output.Info("Run `nitro apply --rollback` to restore the containers from before the changes.")
+				}
 
-			// is minio enabled
-			if cfg.Services.Minio {
-				names[minio.Host] = true
+				return err
 			}
 
-			// is redis enabled
-			if cfg.Services.Redis {
-				names[redis.Host] = true
-			}
+			return nil
+		},
+	}
 
-			// create a filter for the environment
-			filter := filters.NewArgs()
-			filter.Add("label", containerlabels.Nitro+"=true")
+	// add flag to skip pulling images
+	cmd.Flags().Bool("skip-hosts", false, "skip modifying the hosts file")
+	cmd.Flags().Bool("dry-run", false, "show the changes without applying them")
+	cmd.Flags().Bool("rollback", false, "restore the containers from before the last apply")
+	cmd.Flags().Bool("watch", false, "apply the config each time the config file changes")
+	cmd.Flags().Bool("auto-ports", false, "use the next free port for each host port that is in use")
 
-			// look for a container for the site
-			containers, err := docker.ContainerList(ctx, types.ContainerListOptions{All: true, Filters: filter})
-			if err != nil {
-				return fmt.Errorf("error getting a list of containers")
-			}
+	return cmd
+}
 
-			if len(containers) > 0 {
-				output.Info("Cleaning up…")
-			}
+// cleanup removes the containers of the environment that are not in the config, the
+// databases are backed up before the database containers are removed.
+func cleanup(ctx context.Context, cmd *cobra.Command, home string, docker client.CommonAPIClient, output terminal.Outputer) error {
+	// load the config
+	cfg, err := config.Load(home)
+	if err != nil {
+		return err
+	}
+
+	// store all of the known container names
+	names := map[string]bool{}
+
+	// get all of the sites as hostnames
+	for _, s := range cfg.Sites {
+		names[s.Hostname] = true
+	}
+
+	// get the containers as hostnames
+	for _, c := range cfg.Containers {
+		for _, h := range c.GetHostnames() {
+			names[h] = true
+		}
+	}
 
-			for _, c := range containers {
-				// start the container if not running
-				if c.State != "running" {
-					for _, command := range cmd.Root().Commands() {
-						if command.Use == "start" {
-							if err := command.RunE(cmd, []string{}); err != nil {
-								return err
-							}
-						}
+	// get all of the databases
+	for _, d := range cfg.Databases {
+		h, _ := d.GetHostname()
+		names[h] = true
+	}
+
+	// get all of the enabled services
+	for _, h := range service.Hostnames(cfg) {
+		names[h] = true
+	}
+
+	// keep the container for the scheduled backups
+	if cfg.Backups.Enabled() {
+		names[backupcontainer.Name] = true
+	}
+
+	// create a filter for the environment
+	filter := filters.NewArgs()
+	filter.Add("label", containerlabels.Nitro+"="+environment.Label())
+
+	// look for a container for the site
+	containers, err := docker.ContainerList(ctx, types.ContainerListOptions{All: true, Filters: filter})
+	if err != nil {
+		return fmt.Errorf("error getting a list of containers")
+	}
+
+	if len(containers) > 0 {
+		output.Info("Cleaning up…")
+	}
+
+	res := result{Hostnames: []string{}, Removed: []string{}}
+	for n := range names {
+		res.Hostnames = append(res.Hostnames, n)
+	}
+
+	sort.Strings(res.Hostnames)
+
+	for _, c := range containers {
+		// start the container if not running
+		if c.State != "running" {
+			for _, command := range cmd.Root().Commands() {
+				if command.Use == "start" {
+					if err := command.RunE(cmd, []string{}); err != nil {
+						return err
 					}
 				}
+			}
+		}
 
-				// skip the proxy container
-				if c.Labels[containerlabels.Proxy] != "" {
-					continue
+		// skip the proxy container and share tunnels
+		if c.Labels[containerlabels.Proxy] != "" || c.Labels[containerlabels.Type] == "share" {
+			continue
+		}
+
+		// set the container name
+		name := strings.TrimLeft(c.Names[0], "/")
+
+		// check if this is a known container
+		if _, ok := names[name]; !ok {
+			// don't remove the proxy container
+
+			output.Pending("removing", name)
+
+			// only perform a backup if the container is for databases
+			if c.Labels[containerlabels.DatabaseEngine] != "" {
+				// get all of the databases
+				databases, err := backup.Databases(ctx, docker, c.ID, c.Labels[containerlabels.DatabaseCompatibility])
+				if err != nil {
+					output.Warning()
+					output.Info("Unable to get the databases from", name, err.Error())
+					break
 				}
 
-				// set the container name
-				name := strings.TrimLeft(c.Names[0], "/")
-
-				// check if this is a known container
-				if _, ok := names[name]; !ok {
-					// don't remove the proxy container
-
-					output.Pending("removing", name)
-
-					// only perform a backup if the container is for databases
-					if c.Labels[containerlabels.DatabaseEngine] != "" {
-						// get all of the databases
-						databases, err := backup.Databases(ctx, docker, c.ID, c.Labels[containerlabels.DatabaseCompatibility])
-						if err != nil {
-							output.Warning()
-							output.Info("Unable to get the databases from", name, err.Error())
-							break
-						}
-
-						// backup each database
-						for _, db := range databases {
-							// create the database specific backup options
-							opts := &backup.Options{
-								BackupName:    fmt.Sprintf("%s-%s.sql", db, datetime.Parse(time.Now())),
-								ContainerID:   c.ID,
-								ContainerName: name,
-								Database:      db,
-								Home:          home,
-							}
-
-							// create the backup command based on the compatibility type
-							switch c.Labels[containerlabels.DatabaseCompatibility] {
-							case "postgres":
-								opts.Commands = []string{"pg_dump", "--username=nitro", db, "-f", "/tmp/" + opts.BackupName}
-							default:
-								opts.Commands = []string{"/usr/bin/mysqldump", "-h", "127.0.0.1", "-unitro", "--password=nitro", db, "--result-file=" + "/tmp/" + opts.BackupName}
-							}
-
-							output.Pending("creating backup", opts.BackupName)
-
-							// backup the container
-							if err := backup.Perform(ctx, docker, opts); err != nil {
-								output.Warning()
-								output.Info("Unable to backup database", db, err.Error())
-								break
-							}
-
-							output.Done()
-						}
-
-						// show where all backups are saved for this container
-						output.Info("Backups saved in", filepath.Join(home, config.DirectoryName, name), "💾")
+				// backup each database
+				for _, db := range databases {
+					// create the database specific backup options
+					opts := &backup.Options{
+						BackupName:    fmt.Sprintf("%s-%s.sql", db, datetime.Parse(time.Now())),
+						ContainerID:   c.ID,
+						ContainerName: name,
+						Database:      db,
+						Home:          home,
 					}
 
-					// stop and remove a container we don't know about
-					if err := docker.ContainerStop(ctx, c.ID, nil); err != nil {
-						return err
+					// create the backup command based on the compatibility type
+					switch c.Labels[containerlabels.DatabaseCompatibility] {
+					case "postgres":
+						opts.Commands = []string{"pg_dump", "--username=nitro", db, "-f", "/tmp/" + opts.BackupName}
+					default:
+						opts.Commands = []string{"/usr/bin/" + database.DumpCommand(c.Labels[containerlabels.DatabaseEngine], c.Labels[containerlabels.DatabaseVersion]), "-h", "127.0.0.1", "-unitro", "--password=nitro", db, "--result-file=" + "/tmp/" + opts.BackupName}
 					}
 
-					// remove container
-					if err := docker.ContainerRemove(ctx, c.ID, types.ContainerRemoveOptions{}); err != nil {
-						return err
+					output.Pending("creating backup", opts.BackupName)
+
+					// backup the container
+					if err := backup.Perform(ctx, docker, opts); err != nil {
+						output.Warning()
+						output.Info("Unable to backup database", db, err.Error())
+						break
 					}
 
 					output.Done()
 				}
-			}
 
-			if isWSL {
-				output.Info(fmt.Sprintf("For your hostnames to work, add the following to `%s`:", `C:\Windows\System32\Drivers\etc\hosts`))
-				output.Info("---- COPY BELOW ----")
-				output.Info(fmt.Sprintf(`# <nitro>
-%s %s
-# </nitro>`, "127.0.0.1", strings.Join(hostnames, " ")))
-				output.Info("---- COPY ABOVE ----")
+				// show where all backups are saved for this container
+				output.Info("Backups saved in", filepath.Join(home, config.DirectoryName, name), "💾")
 			}
 
-			output.Info("Nitro is up and running 😃")
-
-			return nil
-		},
-		RunE: func(cmd *cobra.Command, args []string) error {
-			ctx := cmd.Root().Context()
+			// stop and remove a container we don't know about
+			if err := docker.ContainerStop(ctx, c.ID, nil); err != nil {
+				return err
+			}
 
-			// load the config
-			cfg, err := config.Load(home)
-			if err != nil {
+			// remove container
+			if err := docker.ContainerRemove(ctx, c.ID, types.ContainerRemoveOptions{}); err != nil {
 				return err
 			}
 
-			// create a filter for the environment
-			filter := filters.NewArgs()
-			filter.Add("label", containerlabels.Nitro+"=true")
+			res.Removed = append(res.Removed, name)
 
-			// add the filter for the network name
-			filter.Add("name", "nitro-network")
+			output.Done()
+		}
+	}
 
-			output.Info("Checking network…")
+	if terminal.JSON {
+		return output.JSON(res)
+	}
 
-			// check the network
-			var network types.NetworkResource
-			networks, err := docker.NetworkList(ctx, types.NetworkListOptions{Filters: filter})
-			if err != nil {
-				return fmt.Errorf("unable to list docker networks\n%w", err)
-			}
+	if isWSL {
+		output.Info(fmt.Sprintf("For your hostnames to work, add the following to `%s`:", `C:\Windows\System32\Drivers\etc\hosts`))
+		output.Info("---- COPY BELOW ----")
+		output.Info(fmt.Sprintf(`# <nitro>
+%s %s
+# </nitro>`, dockercontext.Current.Address(), strings.Join(hostnames, " ")))
+		output.Info("---- COPY ABOVE ----")
+	}
 
-			// get the network for the environment
-			for _, n := range networks {
-				if n.Name == "nitro-network" {
-					network = n
-					break
-				}
-			}
+	output.Info("Nitro is up and running 😃")
 
-			// if the network is not found
-			if network.ID == "" {
-				return fmt.Errorf("No network was found…\nrun `nitro init` to get started")
-			}
+	return nil
+}
 
-			// remove the filter
-			filter.Del("name", "nitro-network")
+// CheckPorts checks each host port the containers of the environment bind and returns an
+// error with every port that is in use. When autoPorts is true, the next free ports are
+// used instead and saved in the config.
+func CheckPorts(ctx context.Context, docker client.ContainerAPIClient, cfg *config.Config, autoPorts bool, output terminal.Outputer) error {
+	changed, err := preflight.Ports(ctx, docker, cfg, autoPorts)
+	for _, c := range changed {
+		output.Info(fmt.Sprintf("The port %s for %s is in use, using port %s instead", c.Port, c.Container, c.Next))
+	}
 
-			output.Success("network ready")
+	if err != nil {
+		return err
+	}
 
-			output.Info("Checking proxy…")
+	if len(changed) == 0 {
+		return nil
+	}
 
-			// check the proxy and ensure its started
-			_, err = proxycontainer.FindAndStart(ctx, docker)
-			if errors.Is(err, proxycontainer.ErrNoProxyContainer) {
-				// create the proxy
-				if err := proxycontainer.Create(ctx, docker, output, network.ID); err != nil {
-					output.Info("unable to find the nitro proxy…\n run `nitro init` to resolve")
-					return err
-				}
-			}
-			if err != nil && !errors.Is(err, proxycontainer.ErrNoProxyContainer) {
-				return err
-			}
+	return cfg.Save()
+}
 
-			output.Success("proxy ready")
+// networkDrives returns a warning for each site that is mounted from a network drive.
+// Sites that sync the path into a volume are not mounted from the drive.
+func networkDrives(home string, cfg *config.Config) []string {
+	var warnings []string
+	for _, site := range cfg.Sites {
+		if site.UsesSync() {
+			continue
+		}
 
-			output.Info("Checking databases…")
+		path, err := site.GetAbsPath(home)
+		if err != nil {
+			continue
+		}
 
-			// check the databases
-			for _, db := range cfg.Databases {
-				n, _ := db.GetHostname()
-				output.Pending("checking", n)
+		if fs, ok := netdrive.Detect(path); ok {
+			warnings = append(warnings, fmt.Sprintf("%s is on a network drive (%s), %s.", site.Hostname, fs, netdrive.Warning))
+		}
+	}
 
-				// start or create the database
-				_, hostname, err := databasecontainer.StartOrCreate(ctx, docker, network.ID, db, output)
-				if err != nil {
-					output.Warning()
-					return err
-				}
+	return warnings
+}
 
-				// add the hostname to the hosts files
-				hostnames = append(hostnames, hostname)
+// images returns the images that are needed for the config.
+func images(cfg *config.Config) []string {
+	images := []string{proxycontainer.ProxyImage}
 
-				output.Done()
-			}
+	for _, db := range cfg.Databases {
+		images = append(images, fmt.Sprintf(databasecontainer.DatabaseImage, db.Engine, db.Version))
+	}
 
-			output.Info("Checking services…")
+	for _, s := range service.Services {
+		if cfg.Services.IsEnabled(s.Name) {
+			images = append(images, s.Image)
+		}
+	}
 
-			// check dynamodb service
-			switch cfg.Services.DynamoDB {
-			case false:
-				output.Pending("checking dynamodb")
+	for _, c := range cfg.Containers {
+		images = append(images, fmt.Sprintf("%s:%s", c.Image, c.Tag))
+	}
 
-				if err := dynamodb.VerifyRemoved(ctx, docker, output); err != nil {
-					output.Warning()
-					return err
-				}
+	for _, s := range cfg.Sites {
+		images = append(images, fmt.Sprintf(sitecontainer.NginxImage, s.Version))
+	}
 
-				output.Done()
-			default:
-				output.Pending("checking dynamodb")
+	if cfg.Backups.Enabled() {
+		images = append(images, backupcontainer.Image)
+	}
 
-				_, hostname, err := dynamodb.VerifyCreated(ctx, docker, network.ID, output)
-				if err != nil {
-					return err
-				}
+	return images
+}
 
-				if hostname != "" {
-					hostnames = append(hostnames, hostname)
-				}
+// Run applies the config to the environment by starting or creating the network, proxy,
+// databases, services, custom containers, and sites. It is used by init to apply the
+// config after the proxy is created. When autoPorts is true, the next free port is used
+// for each host port that is in use.
+func Run(ctx context.Context, home string, docker client.CommonAPIClient, nitrod protob.NitroClient, output terminal.Outputer, skipHosts, autoPorts bool) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
 
-				output.Done()
-			}
+	start := time.Now()
 
-			// check mailhog service
-			switch cfg.Services.Mailhog {
-			case false:
-				output.Pending("checking mailhog")
+	// load the config
+	cfg, err := config.Load(home)
+	if err != nil {
+		return err
+	}
 
-				// make sure the service container is removed
-				if err := mailhog.VerifyRemoved(ctx, docker, output); err != nil {
-					return err
-				}
+	// the site paths are on the machine running docker when it is remote
+	dir := home
+	if dockercontext.Current.Remote() {
+		dir = ""
+	}
 
-				output.Done()
-			default:
-				output.Pending("checking mailhog")
+	// check the whole config before making changes
+	if err := cfg.Validate(dir); err != nil {
+		return err
+	}
 
-				// verify the mailhog container is created
-				_, hostname, err := mailhog.VerifyCreated(ctx, docker, network.ID, output)
-				if err != nil {
-					return err
-				}
+	// make sure the sites can be synced before making changes
+	if err := mutagen.Check(cfg); err != nil {
+		return err
+	}
 
-				if hostname != "" {
-					hostnames = append(hostnames, hostname)
-				}
+	// list every host port that is in use before creating containers
+	if err := CheckPorts(ctx, docker, cfg, autoPorts, output); err != nil {
+		return err
+	}
 
-				output.Done()
-			}
+	// list every missing image before making changes
+	if offline.Enabled {
+		if err := offline.Check(ctx, docker, images(cfg)); err != nil {
+			return err
+		}
+	}
 
-			// check minio service
-			switch cfg.Services.Minio {
-			case false:
-				// make sure the service container is removed
-				err := minio.VerifyRemoved(ctx, docker, output)
-				if err != nil {
-					return err
-				}
-			default:
-				output.Pending("checking minio")
+	// the hooks are passed the changes apply is going to make
+	var p *plan.Plan
+	if hook.Configured(cfg) {
+		if p, err = plan.Build(ctx, docker, home, cfg); err != nil {
+			return err
+		}
+	}
 
-				// verify the minio container is created
-				_, hostname, err := minio.VerifyCreated(ctx, docker, network.ID, output)
-				if err != nil {
-					return err
-				}
+	if err := hook.Run(ctx, docker, cfg, hook.PreApply, changes(p), output); err != nil {
+		return err
+	}
 
-				if hostname != "" {
-					hostnames = append(hostnames, hostname)
-				}
+	// record the containers before making changes, so the changes can be rolled back
+	if err := snapshot.Record(ctx, docker, home); err != nil {
+		output.Info("Unable to record the containers for a rollback,", err.Error())
+	}
 
-				output.Done()
-			}
+	// create a filter for the environment
+	filter := filters.NewArgs()
+	filter.Add("label", containerlabels.Nitro+"="+environment.Label())
 
-			// check redis service
-			switch cfg.Services.Redis {
-			case false:
-				output.Pending("checking redis")
+	// add the filter for the network name
+	filter.Add("name", environment.Network())
 
-				if err := redis.VerifyRemoved(ctx, docker, output); err != nil {
-					return err
-				}
+	output.Info("Checking network…")
 
-				output.Done()
-			default:
-				output.Pending("checking redis")
+	// check the network
+	var network types.NetworkResource
+	networks, err := docker.NetworkList(ctx, types.NetworkListOptions{Filters: filter})
+	if err != nil {
+		return fmt.Errorf("unable to list docker networks\n%w", err)
+	}
 
-				_, hostname, err := redis.VerifyCreated(ctx, docker, network.ID, output)
-				if err != nil {
-					return err
-				}
+	// get the network for the environment
+	for _, n := range networks {
+		if n.Name == environment.Network() {
+			network = n
+			break
+		}
+	}
 
-				if hostname != "" {
-					hostnames = append(hostnames, hostname)
-				}
+	// if the network is not found
+	if network.ID == "" {
+		return fmt.Errorf("No network was found…\nrun `nitro init` to get started")
+	}
 
-				output.Done()
-			}
+	// remove the filter
+	filter.Del("name", environment.Network())
 
-			if len(cfg.Containers) > 0 {
-				// get all of the containers
-				output.Info("Checking containers…")
+	output.Success("network ready")
 
-				for _, c := range cfg.Containers {
-					output.Pending("checking", fmt.Sprintf("%s.containers.nitro", c.Name))
+	output.Info("Checking proxy…")
 
-					// start, update or create the custom container
-					_, err := customcontainer.StartOrCreate(ctx, docker, home, network.ID, c)
-					if err != nil {
-						output.Warning()
-						return err
-					}
+	// check the proxy and ensure its started
+	proxy, err := proxycontainer.FindAndStart(ctx, docker)
+	if errors.Is(err, proxycontainer.ErrNoProxyContainer) {
+		// create the proxy
+		if err := proxycontainer.Create(ctx, docker, output, network.ID, filepath.Join(home, config.DirectoryName)); err != nil {
+			output.Info("unable to find the nitro proxy…\n run `nitro init` to resolve")
+			return err
+		}
+	}
+	if err != nil && !errors.Is(err, proxycontainer.ErrNoProxyContainer) {
+		return err
+	}
 
-					output.Done()
-				}
-			}
+	// make sure the proxy is not used by nitro on the other side of WSL with another config
+	if err := proxycontainer.VerifyPlatform(proxy, filepath.Join(home, config.DirectoryName)); err != nil {
+		return err
+	}
 
-			if len(cfg.Sites) > 0 {
-				// get all of the sites, their local path, the php version, and the type of project (nginx or PHP-FPM)
-				output.Info("Checking sites…")
+	output.Success("proxy ready")
 
-				// get the envs for the sites
-				for _, site := range cfg.Sites {
-					output.Pending("checking", site.Hostname)
+	output.Info("Checking databases…")
 
-					// start, update or create the site container
-					_, err := sitecontainer.StartOrCreate(ctx, docker, home, network.ID, site, cfg)
-					if err != nil {
-						output.Warning()
-						return err
-					}
+	// check the databases
+	databases := map[string]string{}
+	for _, db := range cfg.Databases {
+		n, _ := db.GetHostname()
+		output.Pending("checking", n)
 
-					output.Done()
-				}
-			}
+		// start or create the database
+		id, hostname, err := databasecontainer.StartOrCreate(ctx, docker, network.ID, db, logconfig.New(cfg.Logs), output)
+		if err != nil {
+			output.Warning()
+			return err
+		}
 
-			output.Info("Checking proxy…")
+		// add the hostname to the hosts files
+		hostnames = append(hostnames, hostname)
+		databases[hostname] = id
 
-			output.Pending("updating proxy")
+		output.Done()
+	}
 
-			if err := updateProxy(ctx, docker, nitrod, cfg); err != nil {
+	// the sites are seeded once their containers are running
+	created, err := siteDatabases(ctx, docker, cfg, databases, output)
+	if err != nil {
+		return err
+	}
+
+	output.Info("Checking services…")
+
+	// create or remove the containers for each service
+	services, err := service.Reconcile(ctx, docker, network.ID, cfg, output)
+	if err != nil {
+		return err
+	}
+
+	hostnames = append(hostnames, services...)
+
+	if len(cfg.Containers) > 0 {
+		// get all of the containers
+		output.Info("Checking containers…")
+
+		for _, c := range cfg.Containers {
+			output.Pending("checking", fmt.Sprintf("%s.containers.nitro", c.Name))
+
+			// start, update or create the custom container
+			_, err := customcontainer.StartOrCreate(ctx, docker, home, network.ID, c, logconfig.New(cfg.Logs))
+			if err != nil {
 				output.Warning()
 				return err
 			}
 
 			output.Done()
+		}
+	}
 
-			// should we update the hosts file?
-			if os.Getenv("NITRO_EDIT_HOSTS") == "false" || cmd.Flag("skip-hosts").Value.String() == "true" {
-				// skip updating the hosts file
-				return nil
-			}
+	if len(cfg.Sites) > 0 {
+		// get all of the sites, their local path, the php version, and the type of project (nginx or PHP-FPM)
+		output.Info("Checking sites…")
 
-			// get all possible hostnames
-			for _, s := range cfg.Sites {
-				hostnames = append(hostnames, s.Hostname)
-				hostnames = append(hostnames, s.Aliases...)
+		// the bind mounts use the paths on the machine running the docker daemon
+		if dockercontext.Current.Remote() {
+			output.Info(fmt.Sprintf("  Docker is running on %s, the sites are mounted from the same paths on that machine. Make sure the projects are available there, for example with a shared folder.", dockercontext.Current))
+		} else {
+			for _, w := range networkDrives(home, cfg) {
+				output.Info("  " + w)
 			}
+		}
+
+		// get the envs for the sites
+		for _, site := range cfg.Sites {
+			output.Pending("checking", site.Hostname)
 
-			// get custom container hostnames
-			for _, c := range cfg.Containers {
-				hostnames = append(hostnames, fmt.Sprintf("%s.containers.nitro", c.Name))
+			// start, update or create the site container
+			_, err := sitecontainer.StartOrCreate(ctx, docker, home, network.ID, site, cfg, output)
+			if err != nil {
+				output.Warning()
+				return err
 			}
 
-			if len(hostnames) > 0 {
-				// is this wsl?
-				isWSL = wsl.IsWSL()
+			output.Done()
+		}
+	}
+
+	// create or remove the queue workers of the sites
+	if err := queuecontainer.Reconcile(ctx, docker, home, network.ID, cfg); err != nil {
+		return err
+	}
 
-				// set the hosts file based on the OS
-				if runtime.GOOS == "windows" {
-					defaultFile = `C:\Windows\System32\Drivers\etc\hosts`
-				}
+	// create or remove the container for the scheduled database backups
+	if err := backupcontainer.Reconcile(ctx, docker, home, network.ID, cfg); err != nil {
+		return err
+	}
 
-				// check if hosts is already up to date
-				updated, err := hostedit.IsUpdated(defaultFile, "127.0.0.1", hostnames...)
-				if err != nil {
-					return err
-				}
+	// sync the paths of the sites that use mutagen into their volumes
+	if err := mutagen.Reconcile(ctx, home, cfg, output); err != nil {
+		return err
+	}
 
-				// if the hosts file is not updated
-				if !updated {
-					// get the executable
-					nitro, err := os.Executable()
-					if err != nil {
-						return fmt.Errorf("unable to locate the nitro path, %w", err)
-					}
+	// seed the databases that were created for the sites
+	if err := seedSites(ctx, docker, home, cfg, created, output); err != nil {
+		return err
+	}
 
-					// run the hosts command
-					switch runtime.GOOS {
-					case "windows":
-						// windows users should be running as admin, so just execute the hosts command
-						// as is
-						c := exec.Command(nitro, "hosts", "--hostnames="+strings.Join(hostnames, ","))
+	output.Info("Checking proxy…")
 
-						c.Stdout = os.Stdout
-						c.Stderr = os.Stderr
+	output.Pending("waiting for proxy")
 
-						if c.Run() != nil {
-							return err
-						}
-					default:
-						output.Info("Updating hosts file (you might be prompted for your password)")
+	// wait for the proxy api before checking the certificates and updating the routes
+	if err := proxycontainer.WaitForAPI(ctx, docker, nitrod, proxycontainer.Timeout); err != nil {
+		output.Warning()
+		return err
+	}
 
-						// add the hosts
-						if err := sudo.Run(nitro, "nitro", "hosts", "--hostnames="+strings.Join(hostnames, ",")); err != nil {
-							return err
-						}
-					}
-				}
-			}
+	output.Done()
 
-			return nil
-		},
+	// renew the site certificates that are about to expire
+	if proxy.ID != "" {
+		if err := renewCertificates(ctx, docker, nitrod, proxy.ID, cfg, output); err != nil {
+			return err
+		}
 	}
 
-	// add flag to skip pulling images
-	cmd.Flags().Bool("skip-hosts", false, "skip modifying the hosts file")
+	output.Pending("updating proxy")
 
-	return cmd
+	if err := proxyroutes.Update(ctx, nitrod, proxyroutes.Sites(cfg), proxyroutes.Dashboard(cfg)); err != nil {
+		output.Warning()
+		return err
+	}
+
+	output.Done()
+
+	// the databases may still be initializing, so wait for the containers before the hooks run
+	if err := waitHealthy(ctx, docker, cfg, output); err != nil {
+		return err
+	}
+
+	// the containers match the config, the next apply records a new snapshot
+	if err := snapshot.Complete(home); err != nil && !errors.Is(err, snapshot.ErrNoSnapshot) {
+		return err
+	}
+
+	// run the hooks for the sites and databases apply created
+	if err := postCreateHooks(ctx, docker, home, cfg, p, output); err != nil {
+		return err
+	}
+
+	if err := editHosts(cfg, skipHosts, output); err != nil {
+		return err
+	}
+
+	report(ctx, docker, nitrod, time.Since(start))
+
+	return hook.Run(ctx, docker, cfg, hook.PostApply, changes(p), output)
 }
 
-func updateProxy(ctx context.Context, docker client.ContainerAPIClient, nitrod protob.NitroClient, cfg *config.Config) error {
-	// convert the sites into the gRPC API Apply request
-	sites := make(map[string]*protob.Site)
+// waitHealthy waits for the proxy, database, and site containers to pass their health
+// checks. The sites can fail the health check because of the project, such as when craft is
+// not installed yet, so an unhealthy site is shown as a warning instead of an error.
+func waitHealthy(ctx context.Context, docker client.CommonAPIClient, cfg *config.Config, output terminal.Outputer) error {
+	containers := []string{environment.Proxy()}
+	for _, db := range cfg.Databases {
+		h, _ := db.GetHostname()
+		containers = append(containers, h)
+	}
+
+	spinner := terminal.NewSpinner("waiting for containers to be healthy")
+	progress := func(waiting []string) {
+		spinner.Update("waiting for " + strings.Join(waiting, ", ") + " to be healthy")
+	}
+
+	if err := health.Wait(ctx, docker, containers, health.Timeout, progress); err != nil {
+		spinner.Stop(false)
+		return err
+	}
+
+	var sites []string
 	for _, s := range cfg.Sites {
-		// create the site
-		sites[s.Hostname] = &protob.Site{
-			Hostname: s.Hostname,
-			Aliases:  strings.Join(s.Aliases, ","),
-			Port:     8080,
-		}
+		sites = append(sites, s.Hostname)
+	}
+
+	if err := health.Wait(ctx, docker, sites, health.Timeout, progress); err != nil {
+		spinner.Stop(false)
+		output.Info("  " + err.Error())
+		return nil
+	}
+
+	spinner.Update("waiting for containers to be healthy")
+	spinner.Stop(true)
+
+	return nil
+}
+
+// report sends the duration of the apply and the state of the containers to the proxy for
+// the metrics. The metrics are optional, so errors are ignored.
+func report(ctx context.Context, docker client.CommonAPIClient, nitrod protob.NitroClient, duration time.Duration) {
+	filter := filters.NewArgs()
+	filter.Add("label", containerlabels.Nitro+"="+environment.Label())
+
+	containers, err := docker.ContainerList(ctx, types.ContainerListOptions{All: true, Filters: filter})
+	if err != nil {
+		return
 	}
 
-	// check the mailhog service
-	if cfg.Services.Mailhog {
-		sites["mailhog.service.nitro"] = &protob.Site{
-			Hostname: "mailhog.service.nitro",
-			Port:     8025,
+	req := &protob.ReportRequest{Duration: duration.Seconds()}
+	for _, c := range containers {
+		details, err := docker.ContainerInspect(ctx, c.ID)
+		if err != nil {
+			continue
 		}
+
+		req.Containers = append(req.Containers, &protob.ContainerState{
+			Name:     strings.TrimLeft(details.Name, "/"),
+			State:    c.State,
+			Restarts: int32(details.RestartCount),
+		})
 	}
 
-	// check the minio service
-	if cfg.Services.Minio {
-		sites["minio.service.nitro"] = &protob.Site{
-			Hostname: "minio.service.nitro",
-			Port:     9000,
+	_, _ = nitrod.Report(ctx, req)
+}
+
+// siteDatabases offers to create the database of each site when it does not exist in the
+// database engine of the site, so the sites do not fail to connect when they are used. The
+// containers are the IDs of the database containers by hostname. It returns the hostnames
+// of the sites a database was created for.
+func siteDatabases(ctx context.Context, docker client.CommonAPIClient, cfg *config.Config, containers map[string]string, output terminal.Outputer) ([]string, error) {
+	var created []string
+	for _, site := range cfg.Sites {
+		if site.Database == "" {
+			continue
+		}
+
+		db, err := cfg.SiteDatabase(site)
+		if err != nil {
+			return nil, err
+		}
+
+		hostname, _ := db.GetHostname()
+		id, ok := containers[hostname]
+		if !ok {
+			continue
+		}
+
+		exists, err := databasecontainer.Exists(ctx, docker, id, *db, site.Database)
+		if err != nil {
+			return nil, err
 		}
+
+		if exists {
+			continue
+		}
+
+		create, err := output.Confirm(fmt.Sprintf("The database %s for %s does not exist in %s, create it?", site.Database, site.Hostname, hostname), true, "")
+		if err != nil {
+			return nil, err
+		}
+
+		if !create {
+			output.Info(fmt.Sprintf("  Skipped creating %s, %s will not be able to connect to it.", site.Database, site.Hostname))
+			continue
+		}
+
+		output.Pending("creating database", site.Database)
+
+		if err := databasecontainer.Create(ctx, docker, id, *db, site.Database); err != nil {
+			output.Warning()
+			return nil, err
+		}
+
+		output.Done()
+
+		created = append(created, site.Hostname)
 	}
 
-	// add any custom containers that need to be proxied
-	for _, c := range cfg.Containers {
-		if c.WebGui != 0 {
-			sites[fmt.Sprintf("%s.containers.nitro", c.Name)] = &protob.Site{
-				Hostname: fmt.Sprintf("%s.containers.nitro", c.Name),
-				Port:     int32(c.WebGui),
+	return created, nil
+}
+
+// editHosts adds the hostnames to the hosts file when they are missing, unless editing the
+// hosts file is disabled.
+func editHosts(cfg *config.Config, skipHosts bool, output terminal.Outputer) error {
+	// should we update the hosts file?
+	if os.Getenv("NITRO_EDIT_HOSTS") == "false" || skipHosts {
+		// skip updating the hosts file
+		return nil
+	}
+
+	// get all possible hostnames
+	hostnames = append(hostnames, cfg.Hostnames()...)
+
+	if len(hostnames) > 0 {
+		// is this wsl?
+		isWSL = wsl.IsWSL()
+
+		// set the hosts file based on the OS
+		defaultFile = hostedit.File(runtime.GOOS)
+
+		// check if hosts is already up to date
+		updated, err := hostedit.IsUpdated(defaultFile, dockercontext.Current.Address(), hostnames...)
+		if err != nil {
+			return err
+		}
+
+		// if the hosts file is not updated
+		if !updated {
+			// get the executable
+			nitro, err := os.Executable()
+			if err != nil {
+				return fmt.Errorf("unable to locate the nitro path, %w", err)
+			}
+
+			// run the hosts command
+			switch runtime.GOOS {
+			case "windows":
+				// windows users should be running as admin, so just execute the hosts command
+				// as is
+				c := exec.Command(nitro, hostsArgs(hostnames)...)
+
+				c.Stdout = os.Stdout
+				c.Stderr = os.Stderr
+
+				if c.Run() != nil {
+					return err
+				}
+			default:
+				output.Info("Updating hosts file (you might be prompted for your password)")
+
+				// add the hosts
+				if err := sudo.Run(nitro, append([]string{"nitro"}, hostsArgs(hostnames)...)...); err != nil {
+					return err
+				}
 			}
 		}
 	}
 
-	// if there are no sites, we are done
-	if len(sites) == 0 {
-		return nil
+	return nil
+}
+
+// hostsArgs returns the arguments to run the hosts command with the hostnames. When the
+// output is JSON, the hosts command is run with --json so it does not add to the output.
+func hostsArgs(hostnames []string) []string {
+	args := []string{"hosts", "--hostnames=" + strings.Join(hostnames, ",")}
+	if dockercontext.Current.Remote() {
+		args = append(args, "--address="+dockercontext.Current.Address())
 	}
 
-	// wait for the api to be ready
-	for {
-		_, err := nitrod.Ping(ctx, &protob.PingRequest{})
-		if err == nil {
-			break
+	if terminal.JSON {
+		args = append(args, "--json")
+	}
+
+	return append(args, environment.Args()...)
+}
+
+// dryRun compares the config to the environment and shows the changes apply would make.
+func dryRun(cmd *cobra.Command, home string, docker client.CommonAPIClient, output terminal.Outputer) error {
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	cfg, err := config.Load(home)
+	if err != nil {
+		return err
+	}
+
+	p, err := plan.Build(ctx, docker, home, cfg)
+	if err != nil {
+		return err
+	}
+
+	if terminal.JSON {
+		if p.Steps == nil {
+			p.Steps = []plan.Step{}
 		}
+
+		return output.JSON(p)
+	}
+
+	p.Print(cmd.OutOrStdout())
+
+	return nil
+}
+
+// rollback recreates the containers from the snapshot that was recorded before the last apply.
+func rollback(cmd *cobra.Command, home string, docker client.CommonAPIClient, output terminal.Outputer) error {
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
 	}
 
-	// configure the proxy with the sites
-	resp, err := nitrod.Apply(ctx, &protob.ApplyRequest{Sites: sites})
+	s, err := snapshot.Load(home)
 	if err != nil {
 		return err
 	}
 
-	if resp.Error {
-		return fmt.Errorf("unable to update the proxy, %s", resp.GetMessage())
+	output.Info("Restoring the containers from", s.Created.Format(time.RFC1123)+"…")
+
+	if err := snapshot.Restore(ctx, docker, s, output); err != nil {
+		return err
+	}
+
+	// the restored containers are the new snapshot
+	current, err := snapshot.Take(ctx, docker)
+	if err != nil {
+		return err
 	}
 
+	current.Completed = true
+
+	if err := current.Save(home); err != nil {
+		return err
+	}
+
+	output.Info("The containers are restored, undo the changes to the config before running `nitro apply` again.")
+
 	return nil
 }
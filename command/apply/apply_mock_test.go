@@ -0,0 +1,504 @@
+package apply
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/network"
+	volumetypes "github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/client"
+	"github.com/docker/go-connections/nat"
+	specs "github.com/opencontainers/image-spec/specs-go/v1"
+	"google.golang.org/grpc"
+
+	"github.com/craftcms/nitro/pkg/terminal"
+	"github.com/craftcms/nitro/protob"
+)
+
+// errKilled is returned by the fake daemon for each call after apply was killed.
+var errKilled = errors.New("apply was killed")
+
+// fakeDaemon keeps the networks, volumes, and containers in memory like the docker daemon,
+// so apply can be run more than once against the same state. When killAt is set, the daemon
+// completes that mutating call and then fails every call, as if apply was killed right after
+// the daemon made the change.
+type fakeDaemon struct {
+	client.CommonAPIClient
+
+	mu         sync.Mutex
+	networks   map[string]types.NetworkResource
+	volumes    map[string]*types.Volume
+	containers map[string]*fakeContainer
+	execs      map[string]bool
+	nextID     int
+
+	killAt    int
+	mutations int
+	killed    bool
+}
+
+type fakeContainer struct {
+	id       string
+	name     string
+	state    string
+	config   *container.Config
+	host     *container.HostConfig
+	networks map[string]string
+}
+
+func newFakeDaemon() *fakeDaemon {
+	return &fakeDaemon{
+		networks:   make(map[string]types.NetworkResource),
+		volumes:    make(map[string]*types.Volume),
+		containers: make(map[string]*fakeContainer),
+		execs:      make(map[string]bool),
+	}
+}
+
+// mutate counts a call that changes the state, it returns true when apply is killed after
+// the change is made.
+func (d *fakeDaemon) mutate() bool {
+	d.mutations++
+
+	if d.killAt > 0 && d.mutations == d.killAt {
+		d.killed = true
+		return true
+	}
+
+	return false
+}
+
+func (d *fakeDaemon) id(prefix string) string {
+	d.nextID++
+
+	// docker IDs are hex, so they are not mistaken for names
+	return fmt.Sprintf("%x%012x", prefix, d.nextID)
+}
+
+func (d *fakeDaemon) find(ref string) (*fakeContainer, error) {
+	if c, ok := d.containers[ref]; ok {
+		return c, nil
+	}
+
+	for _, c := range d.containers {
+		if c.name == strings.TrimPrefix(ref, "/") {
+			return c, nil
+		}
+	}
+
+	return nil, fmt.Errorf("Error: No such container: %s", ref)
+}
+
+// matches returns true when the labels and name match the label and name filters.
+func matches(args filters.Args, labels map[string]string, name string) bool {
+	for _, l := range args.Get("label") {
+		parts := strings.SplitN(l, "=", 2)
+		v, ok := labels[parts[0]]
+		if !ok || (len(parts) == 2 && v != parts[1]) {
+			return false
+		}
+	}
+
+	for _, n := range args.Get("name") {
+		if ok, _ := regexp.MatchString(n, name); !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (d *fakeDaemon) summary(c *fakeContainer) types.Container {
+	settings := &types.SummaryNetworkSettings{Networks: make(map[string]*network.EndpointSettings)}
+	for name, id := range c.networks {
+		settings.Networks[name] = &network.EndpointSettings{NetworkID: id}
+	}
+
+	var ports []types.Port
+	if c.host != nil && c.state == "running" {
+		for p, bindings := range c.host.PortBindings {
+			for _, b := range bindings {
+				public, _ := nat.ParsePort(b.HostPort)
+				ports = append(ports, types.Port{IP: b.HostIP, PrivatePort: uint16(p.Int()), PublicPort: uint16(public), Type: p.Proto()})
+			}
+		}
+	}
+
+	return types.Container{
+		ID:              c.id,
+		Names:           []string{"/" + c.name},
+		Image:           c.config.Image,
+		Labels:          c.config.Labels,
+		State:           c.state,
+		Ports:           ports,
+		NetworkSettings: settings,
+	}
+}
+
+func (d *fakeDaemon) ContainerList(ctx context.Context, options types.ContainerListOptions) ([]types.Container, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.killed {
+		return nil, errKilled
+	}
+
+	var containers []types.Container
+	for _, c := range d.containers {
+		if !options.All && c.state != "running" {
+			continue
+		}
+
+		if matches(options.Filters, c.config.Labels, c.name) {
+			containers = append(containers, d.summary(c))
+		}
+	}
+
+	sort.Slice(containers, func(i, j int) bool { return containers[i].ID < containers[j].ID })
+
+	return containers, nil
+}
+
+func (d *fakeDaemon) ContainerInspect(ctx context.Context, ref string) (types.ContainerJSON, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.killed {
+		return types.ContainerJSON{}, errKilled
+	}
+
+	c, err := d.find(ref)
+	if err != nil {
+		return types.ContainerJSON{}, err
+	}
+
+	endpoints := make(map[string]*network.EndpointSettings)
+	for name, id := range c.networks {
+		endpoints[name] = &network.EndpointSettings{NetworkID: id}
+	}
+
+	return types.ContainerJSON{
+		ContainerJSONBase: &types.ContainerJSONBase{
+			ID:         c.id,
+			Name:       "/" + c.name,
+			State:      &types.ContainerState{Status: c.state, Running: c.state == "running"},
+			HostConfig: c.host,
+		},
+		Config:          c.config,
+		NetworkSettings: &types.NetworkSettings{Networks: endpoints},
+	}, nil
+}
+
+func (d *fakeDaemon) ContainerCreate(ctx context.Context, config *container.Config, hostConfig *container.HostConfig, networkingConfig *network.NetworkingConfig, platform *specs.Platform, name string) (container.ContainerCreateCreatedBody, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.killed {
+		return container.ContainerCreateCreatedBody{}, errKilled
+	}
+
+	for _, c := range d.containers {
+		if name != "" && c.name == name {
+			return container.ContainerCreateCreatedBody{}, fmt.Errorf("Conflict. The container name %q is already in use by container %q", "/"+name, c.id)
+		}
+	}
+
+	c := &fakeContainer{id: d.id("container"), name: name, state: "created", config: config, host: hostConfig, networks: make(map[string]string)}
+	if c.name == "" {
+		c.name = c.id
+	}
+
+	if networkingConfig != nil {
+		for n, e := range networkingConfig.EndpointsConfig {
+			c.networks[n] = e.NetworkID
+		}
+	}
+
+	d.containers[c.id] = c
+
+	if d.mutate() {
+		return container.ContainerCreateCreatedBody{}, errKilled
+	}
+
+	return container.ContainerCreateCreatedBody{ID: c.id}, nil
+}
+
+func (d *fakeDaemon) ContainerStart(ctx context.Context, ref string, options types.ContainerStartOptions) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.killed {
+		return errKilled
+	}
+
+	c, err := d.find(ref)
+	if err != nil {
+		return err
+	}
+
+	c.state = "running"
+
+	if d.mutate() {
+		return errKilled
+	}
+
+	return nil
+}
+
+func (d *fakeDaemon) ContainerStop(ctx context.Context, ref string, timeout *time.Duration) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.killed {
+		return errKilled
+	}
+
+	c, err := d.find(ref)
+	if err != nil {
+		return err
+	}
+
+	c.state = "exited"
+
+	if d.mutate() {
+		return errKilled
+	}
+
+	return nil
+}
+
+func (d *fakeDaemon) ContainerRemove(ctx context.Context, ref string, options types.ContainerRemoveOptions) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.killed {
+		return errKilled
+	}
+
+	c, err := d.find(ref)
+	if err != nil {
+		return err
+	}
+
+	if c.state == "running" && !options.Force {
+		return fmt.Errorf("You cannot remove a running container %s", c.id)
+	}
+
+	delete(d.containers, c.id)
+
+	if d.mutate() {
+		return errKilled
+	}
+
+	return nil
+}
+
+func (d *fakeDaemon) NetworkList(ctx context.Context, options types.NetworkListOptions) ([]types.NetworkResource, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.killed {
+		return nil, errKilled
+	}
+
+	var networks []types.NetworkResource
+	for _, n := range d.networks {
+		if matches(options.Filters, n.Labels, n.Name) {
+			networks = append(networks, n)
+		}
+	}
+
+	return networks, nil
+}
+
+func (d *fakeDaemon) NetworkConnect(ctx context.Context, networkID, ref string, config *network.EndpointSettings) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.killed {
+		return errKilled
+	}
+
+	c, err := d.find(ref)
+	if err != nil {
+		return err
+	}
+
+	for _, n := range d.networks {
+		if n.ID == networkID {
+			c.networks[n.Name] = n.ID
+		}
+	}
+
+	if d.mutate() {
+		return errKilled
+	}
+
+	return nil
+}
+
+func (d *fakeDaemon) VolumeList(ctx context.Context, filter filters.Args) (volumetypes.VolumeListOKBody, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.killed {
+		return volumetypes.VolumeListOKBody{}, errKilled
+	}
+
+	var volumes []*types.Volume
+	for _, v := range d.volumes {
+		if matches(filter, v.Labels, v.Name) {
+			v := *v
+			volumes = append(volumes, &v)
+		}
+	}
+
+	return volumetypes.VolumeListOKBody{Volumes: volumes}, nil
+}
+
+func (d *fakeDaemon) VolumeCreate(ctx context.Context, options volumetypes.VolumeCreateBody) (types.Volume, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.killed {
+		return types.Volume{}, errKilled
+	}
+
+	// like docker, creating a volume that exists returns the volume
+	v, ok := d.volumes[options.Name]
+	if !ok {
+		v = &types.Volume{Name: options.Name, Driver: options.Driver, Labels: options.Labels}
+		d.volumes[options.Name] = v
+	}
+
+	if d.mutate() {
+		return types.Volume{}, errKilled
+	}
+
+	return *v, nil
+}
+
+func (d *fakeDaemon) ImageList(ctx context.Context, options types.ImageListOptions) ([]types.ImageSummary, error) {
+	if d.isKilled() {
+		return nil, errKilled
+	}
+
+	return []types.ImageSummary{{ID: "image"}}, nil
+}
+
+func (d *fakeDaemon) ImagePull(ctx context.Context, ref string, options types.ImagePullOptions) (io.ReadCloser, error) {
+	if d.isKilled() {
+		return nil, errKilled
+	}
+
+	return ioutil.NopCloser(strings.NewReader(`{"status":"Status: Image is up to date for ` + ref + `"}`)), nil
+}
+
+func (d *fakeDaemon) ContainerExecCreate(ctx context.Context, ref string, config types.ExecConfig) (types.IDResponse, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.killed {
+		return types.IDResponse{}, errKilled
+	}
+
+	if _, err := d.find(ref); err != nil {
+		return types.IDResponse{}, err
+	}
+
+	id := d.id("exec")
+	d.execs[id] = false
+
+	return types.IDResponse{ID: id}, nil
+}
+
+func (d *fakeDaemon) ContainerExecAttach(ctx context.Context, execID string, config types.ExecStartCheck) (types.HijackedResponse, error) {
+	if d.isKilled() {
+		return types.HijackedResponse{}, errKilled
+	}
+
+	conn, _ := net.Pipe()
+
+	return types.HijackedResponse{Conn: conn, Reader: bufio.NewReader(strings.NewReader(""))}, nil
+}
+
+func (d *fakeDaemon) ContainerExecStart(ctx context.Context, execID string, config types.ExecStartCheck) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.killed {
+		return errKilled
+	}
+
+	d.execs[execID] = true
+
+	if d.mutate() {
+		return errKilled
+	}
+
+	return nil
+}
+
+func (d *fakeDaemon) ContainerExecInspect(ctx context.Context, execID string) (types.ContainerExecInspect, error) {
+	if d.isKilled() {
+		return types.ContainerExecInspect{}, errKilled
+	}
+
+	return types.ContainerExecInspect{ExecID: execID}, nil
+}
+
+func (d *fakeDaemon) isKilled() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.killed
+}
+
+// fakeNitrod is the API of a proxy that is ready.
+type fakeNitrod struct {
+	protob.NitroClient
+}
+
+func (n *fakeNitrod) Ping(ctx context.Context, in *protob.PingRequest, opts ...grpc.CallOption) (*protob.PingResponse, error) {
+	return &protob.PingResponse{Pong: "pong"}, nil
+}
+
+func (n *fakeNitrod) Apply(ctx context.Context, in *protob.ApplyRequest, opts ...grpc.CallOption) (*protob.ApplyResponse, error) {
+	return &protob.ApplyResponse{}, nil
+}
+
+func (n *fakeNitrod) Certificates(ctx context.Context, in *protob.CertificatesRequest, opts ...grpc.CallOption) (*protob.CertificatesResponse, error) {
+	return &protob.CertificatesResponse{}, nil
+}
+
+func (n *fakeNitrod) Report(ctx context.Context, in *protob.ReportRequest, opts ...grpc.CallOption) (*protob.ReportResponse, error) {
+	return &protob.ReportResponse{}, nil
+}
+
+// quietOutputer ignores the output and accepts the default of each prompt.
+type quietOutputer struct {
+	terminal.Outputer
+}
+
+func (o quietOutputer) Confirm(message string, fallback bool, sep string) (bool, error) {
+	return fallback, nil
+}
+
+func (o quietOutputer) Info(s ...string)    {}
+func (o quietOutputer) Success(s ...string) {}
+func (o quietOutputer) Pending(s ...string) {}
+func (o quietOutputer) Warning()            {}
+func (o quietOutputer) Done()               {}
@@ -0,0 +1,219 @@
+package apply
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/go-connections/nat"
+
+	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/environment"
+	"github.com/craftcms/nitro/pkg/ownership"
+)
+
+// TestRun_Interrupted kills apply after each change it makes to the containers, networks,
+// and volumes, and verifies the next apply recovers and ends with the same containers as an
+// apply that was not interrupted.
+func TestRun_Interrupted(t *testing.T) {
+	home := newHome(t)
+
+	// apply once without interruptions to count the changes and get the wanted state
+	docker := seededDaemon()
+	if err := Run(context.Background(), home, docker, &fakeNitrod{}, quietOutputer{}, true, false); err != nil {
+		t.Fatalf("unable to apply without interruptions, %v", err)
+	}
+
+	want := docker.state()
+	changes := docker.mutations
+
+	if len(want) != 3 {
+		t.Fatalf("expected the proxy, database, and site containers, got %v", want)
+	}
+
+	for kill := 1; kill <= changes; kill++ {
+		t.Run(fmt.Sprintf("killed after change %d of %d", kill, changes), func(t *testing.T) {
+			docker := seededDaemon()
+			docker.killAt = kill
+
+			if err := Run(context.Background(), home, docker, &fakeNitrod{}, quietOutputer{}, true, false); !errors.Is(err, errKilled) {
+				t.Fatalf("expected apply to be killed, got %v", err)
+			}
+
+			// run apply again, as the user would
+			docker.killAt, docker.killed = 0, false
+
+			if err := Run(context.Background(), home, docker, &fakeNitrod{}, quietOutputer{}, true, false); err != nil {
+				t.Fatalf("expected the next apply to recover, got %v", err)
+			}
+
+			if got := docker.state(); !reflect.DeepEqual(got, want) {
+				t.Errorf("expected the containers to match an apply that was not interrupted\ngot:  %v\nwant: %v", got, want)
+			}
+		})
+	}
+}
+
+// TestRun_Environments applies the same site and database in two environments on the same
+// docker daemon and verifies each environment gets its own containers and volumes.
+func TestRun_Environments(t *testing.T) {
+	defer func() { environment.Name = environment.Default }()
+
+	home := newHome(t)
+
+	// the agency environment uses the same config as the default environment
+	cfg, err := ioutil.ReadFile(filepath.Join(home, ".nitro", "nitro.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(home, ".nitro", "agency.yaml"), cfg, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	d := newFakeDaemon()
+	docker := ownership.New(d)
+
+	for _, env := range []string{environment.Default, "agency"} {
+		environment.Name = env
+		seedEnvironment(d)
+
+		if err := Run(context.Background(), home, docker, &fakeNitrod{}, quietOutputer{}, true, false); err != nil {
+			t.Fatalf("unable to apply the %s environment, %v", env, err)
+		}
+	}
+
+	var sites, databases []string
+	for _, c := range d.containers {
+		switch containerlabels.Identify(types.Container{Labels: c.config.Labels}) {
+		case "site":
+			sites = append(sites, c.name)
+		case "database":
+			databases = append(databases, c.name)
+		}
+	}
+	sort.Strings(sites)
+	sort.Strings(databases)
+
+	if want := []string{"agency.mysite.nitro", "mysite.nitro"}; !reflect.DeepEqual(sites, want) {
+		t.Errorf("expected a site container for each environment, got %v want %v", sites, want)
+	}
+
+	if len(databases) != 2 || databases[0] != "agency."+databases[1] {
+		t.Errorf("expected a database container for each environment, got %v", databases)
+	}
+
+	for _, db := range databases {
+		if _, ok := d.volumes[db]; !ok {
+			t.Errorf("expected the volume %s for the database, got %v", db, d.volumes)
+		}
+	}
+}
+
+// newHome creates the nitro directory with a config that has a database and a site.
+func newHome(t *testing.T) string {
+	t.Helper()
+
+	home, err := ioutil.TempDir("", "nitro-apply")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(home) })
+
+	if err := os.MkdirAll(filepath.Join(home, ".nitro"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.MkdirAll(filepath.Join(home, "dev", "mysite"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	// use a free port for the database so the port check passes
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := strconv.Itoa(lis.Addr().(*net.TCPAddr).Port)
+	lis.Close()
+
+	cfg := `databases:
+  - engine: mysql
+    version: "8.0"
+    port: "` + port + `"
+sites:
+  - hostname: mysite.nitro
+    path: ~/dev/mysite
+    version: "8.0"
+    webroot: web
+`
+	if err := ioutil.WriteFile(filepath.Join(home, ".nitro", "nitro.yaml"), []byte(cfg), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	return home
+}
+
+// seededDaemon returns a daemon with the network and proxy that init creates.
+func seededDaemon() *fakeDaemon {
+	d := newFakeDaemon()
+	seedEnvironment(d)
+
+	return d
+}
+
+// seedEnvironment adds the network and proxy that init creates for the current environment.
+func seedEnvironment(d *fakeDaemon) {
+	d.networks[environment.Network()] = types.NetworkResource{
+		ID:     environment.Network(),
+		Name:   environment.Network(),
+		Labels: map[string]string{containerlabels.Nitro: environment.Label(), containerlabels.Network: environment.Network()},
+	}
+
+	bindings := nat.PortMap{}
+	for _, p := range []int{environment.Ports.HTTP, environment.Ports.HTTPS, environment.Ports.API, environment.Ports.Node, environment.Ports.AltNode} {
+		bindings[nat.Port(strconv.Itoa(p)+"/tcp")] = []nat.PortBinding{{HostIP: "0.0.0.0", HostPort: strconv.Itoa(p)}}
+	}
+
+	d.containers[environment.Proxy()] = &fakeContainer{
+		id:    environment.Proxy(),
+		name:  environment.Proxy(),
+		state: "running",
+		config: &container.Config{
+			Image:  "craftcms/nitro-proxy:develop",
+			Labels: map[string]string{containerlabels.Nitro: environment.Label(), containerlabels.Type: "proxy", containerlabels.Proxy: "true"},
+		},
+		host:     &container.HostConfig{PortBindings: bindings},
+		networks: map[string]string{environment.Network(): environment.Network()},
+	}
+}
+
+// state returns the name, state, and networks of each container, sorted by name.
+func (d *fakeDaemon) state() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var state []string
+	for _, c := range d.containers {
+		var networks []string
+		for n := range c.networks {
+			networks = append(networks, n)
+		}
+		sort.Strings(networks)
+
+		state = append(state, fmt.Sprintf("%s %s %v", c.name, c.state, networks))
+	}
+
+	sort.Strings(state)
+
+	return state
+}
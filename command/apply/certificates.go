@@ -0,0 +1,63 @@
+package apply
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/client"
+
+	"github.com/craftcms/nitro/pkg/certificate"
+	nitroclient "github.com/craftcms/nitro/pkg/client"
+	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/terminal"
+	"github.com/craftcms/nitro/protob"
+)
+
+// renewCertificates removes the site certificates that are about to expire from the proxy
+// and restarts the proxy so new certificates are issued when the routes are updated. The
+// expiry dates come from the proxy API, or from the proxy container for older proxies.
+func renewCertificates(ctx context.Context, docker client.CommonAPIClient, nitrod protob.NitroClient, proxyID string, cfg *config.Config, output terminal.Outputer) error {
+	var hostnames []string
+	for _, s := range cfg.Sites {
+		hostnames = append(hostnames, s.Hostname)
+		hostnames = append(hostnames, s.Aliases...)
+	}
+
+	if len(hostnames) == 0 {
+		return nil
+	}
+
+	output.Info("Checking certificates…")
+
+	expiries, err := nitroclient.Expiries(ctx, nitrod, hostnames)
+	if nitroclient.IsUnimplemented(err) {
+		expiries, err = certificate.Expiries(ctx, docker, proxyID, hostnames)
+	}
+	if err != nil {
+		return err
+	}
+
+	expiring := certificate.ExpiringSoon(expiries, time.Now())
+	if len(expiring) == 0 {
+		output.Success("certificates ready")
+		return nil
+	}
+
+	output.Pending("renewing", strings.Join(expiring, ", "))
+
+	if err := certificate.Remove(ctx, docker, proxyID, expiring); err != nil {
+		output.Warning()
+		return err
+	}
+
+	// the proxy issues new certificates after it restarts
+	if err := docker.ContainerRestart(ctx, proxyID, nil); err != nil {
+		output.Warning()
+		return err
+	}
+
+	output.Done()
+
+	return nil
+}
@@ -0,0 +1,100 @@
+package apply
+
+import (
+	"context"
+	"strings"
+
+	"github.com/docker/docker/client"
+
+	"github.com/craftcms/nitro/command/internal/hook"
+	"github.com/craftcms/nitro/command/internal/plan"
+	"github.com/craftcms/nitro/command/internal/seed"
+	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/terminal"
+)
+
+// changes returns the names of the resources apply creates, updates, and removes as the
+// environment variables for the apply hooks (e.g. NITRO_CREATED=craft.nitro,redis.service.nitro).
+func changes(p *plan.Plan) map[string]string {
+	if p == nil {
+		return nil
+	}
+
+	names := map[plan.Action][]string{}
+	for _, s := range p.Steps {
+		names[s.Action] = append(names[s.Action], s.Name)
+	}
+
+	return map[string]string{
+		"NITRO_CREATED": strings.Join(names[plan.Create], ","),
+		"NITRO_UPDATED": strings.Join(names[plan.Update], ","),
+		"NITRO_REMOVED": strings.Join(names[plan.Remove], ","),
+	}
+}
+
+// postCreateHooks runs the post-site-create hooks for each site and the post-db-create
+// hooks for each database the plan created.
+func postCreateHooks(ctx context.Context, docker client.ContainerAPIClient, home string, cfg *config.Config, p *plan.Plan, output terminal.Outputer) error {
+	if p == nil {
+		return nil
+	}
+
+	for _, s := range p.Steps {
+		if s.Action != plan.Create {
+			continue
+		}
+
+		switch s.Resource {
+		case "site":
+			site, err := cfg.FindSiteByHostName(s.Name)
+			if err != nil {
+				continue
+			}
+
+			env := map[string]string{"NITRO_SITE": site.Hostname, "NITRO_SITE_VERSION": site.Version}
+			if path, err := site.GetAbsPath(home); err == nil {
+				env["NITRO_SITE_PATH"] = path
+			}
+
+			if err := hook.Run(ctx, docker, cfg, hook.PostSiteCreate, env, output); err != nil {
+				return err
+			}
+		case "database":
+			for _, db := range cfg.Databases {
+				if h, _ := db.GetHostname(); h != s.Name {
+					continue
+				}
+
+				env := map[string]string{
+					"NITRO_DATABASE":         s.Name,
+					"NITRO_DATABASE_ENGINE":  db.Engine,
+					"NITRO_DATABASE_VERSION": db.Version,
+					"NITRO_DATABASE_PORT":    db.Port,
+				}
+
+				if err := hook.Run(ctx, docker, cfg, hook.PostDBCreate, env, output); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// seedSites seeds the databases apply created for the sites, the hostnames are the sites
+// the databases were created for.
+func seedSites(ctx context.Context, docker client.ContainerAPIClient, home string, cfg *config.Config, hostnames []string, output terminal.Outputer) error {
+	for _, h := range hostnames {
+		site, err := cfg.FindSiteByHostName(h)
+		if err != nil {
+			continue
+		}
+
+		if err := seed.Run(ctx, docker, home, cfg, *site, output); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package apply
+
+import (
+	"context"
+	"time"
+
+	"github.com/docker/docker/client"
+	"github.com/spf13/cobra"
+
+	"github.com/craftcms/nitro/command/internal/plan"
+	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/dockercache"
+	"github.com/craftcms/nitro/pkg/terminal"
+	"github.com/craftcms/nitro/pkg/watcher"
+	"github.com/craftcms/nitro/protob"
+)
+
+// watchConfig applies the config once and then each time the config file changes until the
+// command is interrupted. The changes are shown before each apply, and a config that is not
+// valid is reported without stopping the watch so it can be fixed.
+func watchConfig(cmd *cobra.Command, home string, docker client.CommonAPIClient, nitrod protob.NitroClient, output terminal.Outputer, skipHosts, autoPorts bool) error {
+	file, err := config.IsEmpty(home)
+	if err != nil {
+		return err
+	}
+
+	apply := func(ctx context.Context) {
+		// the containers can change between applies, so the cached lists are cleared
+		dockercache.Invalidate(docker)
+
+		if err := reconcile(ctx, cmd, home, docker, nitrod, output, skipHosts, autoPorts); err != nil {
+			output.Info(err.Error())
+		}
+
+		output.Info("Watching", file, "for changes, press Ctrl+C to stop")
+	}
+
+	apply(cmd.Context())
+
+	return watcher.Watch(cmd.Context(), file, watcher.Delay, func(ctx context.Context) {
+		output.Info("The config file changed at", time.Now().Format("15:04:05"))
+
+		apply(ctx)
+	})
+}
+
+// reconcile shows the changes to the environment and applies the config, the containers
+// that are not in the config are removed the same way as after apply.
+func reconcile(ctx context.Context, cmd *cobra.Command, home string, docker client.CommonAPIClient, nitrod protob.NitroClient, output terminal.Outputer, skipHosts, autoPorts bool) error {
+	cfg, err := config.Load(home)
+	if err != nil {
+		return err
+	}
+
+	p, err := plan.Build(ctx, docker, home, cfg)
+	if err != nil {
+		return err
+	}
+
+	// the hosts file and proxy routes are not part of the plan, so the config is applied
+	// even when there are no changes to the containers
+	p.Print(cmd.OutOrStdout())
+
+	if err := Run(ctx, home, docker, nitrod, output, skipHosts, autoPorts); err != nil {
+		return err
+	}
+
+	return cleanup(ctx, cmd, home, docker, output)
+}
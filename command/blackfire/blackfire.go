@@ -11,13 +11,18 @@ import (
 const exampleText = `  # enable blackfire for a site
   nitro blackfire on
 
+  # enable blackfire for a specific site
+  nitro blackfire on tutorial.nitro
+
   # disable blackfire for a site
   nitro blackfire off`
 
+// NewCommand returns the command to toggle Blackfire profiling for sites. Enabling a site
+// also enables the blackfire service which runs the agent for the PHP probe.
 func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outputer) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "blackfire",
-		Short:   "Manages Blackfire credentials.",
+		Short:   "Manages Blackfire profiling.",
 		Example: exampleText,
 		PreRunE: func(cmd *cobra.Command, args []string) error {
 			return prompt.VerifyInit(cmd, args, home, output)
@@ -104,7 +104,7 @@ func offCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 			case true:
 				switch len(sites) {
 				case 0:
-					selected, err := output.Select(cmd.InOrStdin(), "Select a site: ", options)
+					selected, err := prompt.Select(cmd.InOrStdin(), "Select a site: ", options, output)
 					if err != nil {
 						return err
 					}
@@ -115,7 +115,7 @@ func offCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 
 					site = &sites[0]
 				default:
-					selected, err := output.Select(cmd.InOrStdin(), "Select a site: ", options)
+					selected, err := prompt.Select(cmd.InOrStdin(), "Select a site: ", options, output)
 					if err != nil {
 						return err
 					}
@@ -46,8 +46,9 @@ func onCommand(home string, docker client.CommonAPIClient, output terminal.Outpu
 				return err
 			}
 
-			// ensure the blackfire credentials are set
-			if cfg.Blackfire.ServerID == "" {
+			// ensure the blackfire credentials are set in the config or environment
+			creds := cfg.Blackfire.Credentials()
+			if creds.ServerID == "" {
 				// ask for the server id
 				id, err := output.Ask("Enter your Blackfire Server ID", "", ":", nil)
 				if err != nil {
@@ -63,7 +64,7 @@ func onCommand(home string, docker client.CommonAPIClient, output terminal.Outpu
 			}
 
 			// ensure the blackfire credentials are set
-			if cfg.Blackfire.ServerToken == "" {
+			if creds.ServerToken == "" {
 				// ask for the server token
 				token, err := output.Ask("Enter your Blackfire Server Token", "", ":", nil)
 				if err != nil {
@@ -104,7 +105,7 @@ func onCommand(home string, docker client.CommonAPIClient, output terminal.Outpu
 			case true:
 				switch len(sites) {
 				case 0:
-					selected, err := output.Select(cmd.InOrStdin(), "Select a site: ", options)
+					selected, err := prompt.Select(cmd.InOrStdin(), "Select a site: ", options, output)
 					if err != nil {
 						return err
 					}
@@ -115,7 +116,7 @@ func onCommand(home string, docker client.CommonAPIClient, output terminal.Outpu
 
 					site = &sites[0]
 				default:
-					selected, err := output.Select(cmd.InOrStdin(), "Select a site: ", options)
+					selected, err := prompt.Select(cmd.InOrStdin(), "Select a site: ", options, output)
 					if err != nil {
 						return err
 					}
@@ -137,11 +138,14 @@ func onCommand(home string, docker client.CommonAPIClient, output terminal.Outpu
 				}
 			}
 
-			// disable blackfire for the sites hostname
+			// enable blackfire for the sites hostname
 			if err := cfg.EnableBlackfire(site.Hostname); err != nil {
 				return err
 			}
 
+			// the agent for the probes runs as a service
+			cfg.Services.Blackfire = true
+
 			// save the config
 			if err := cfg.Save(); err != nil {
 				return err
@@ -18,6 +18,8 @@ import (
 
 	"github.com/craftcms/nitro/pkg/config"
 	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/environment"
+	"github.com/craftcms/nitro/pkg/prompt"
 	"github.com/craftcms/nitro/pkg/terminal"
 )
 
@@ -82,7 +84,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 			}
 
 			// prompt for which interface to use
-			selected, err := output.Select(cmd.InOrStdin(), "Which IP address should we use for the bridge? ", interfaces)
+			selected, err := prompt.Select(cmd.InOrStdin(), "Which IP address should we use for the bridge? ", interfaces, output)
 			if err != nil {
 				return err
 			}
@@ -109,7 +111,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 
 			// create a filter for the environment
 			filter := filters.NewArgs()
-			filter.Add("label", containerlabels.Nitro)
+			filter.Add("label", containerlabels.Nitro+"="+environment.Label())
 
 			// get a context aware list of sites
 			sites := cfg.ListOfSitesByDirectory(home, wd)
@@ -137,7 +139,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 				switch len(sites) {
 				case 0:
 					// prompt for the site to ssh into
-					selected, err := output.Select(cmd.InOrStdin(), "Select a site: ", options)
+					selected, err := prompt.Select(cmd.InOrStdin(), "Select a site: ", options, output)
 					if err != nil {
 						return err
 					}
@@ -161,7 +163,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 					}
 				default:
 					// prompt for the site to ssh into
-					selected, err := output.Select(cmd.InOrStdin(), "Select a site: ", options)
+					selected, err := prompt.Select(cmd.InOrStdin(), "Select a site: ", options, output)
 					if err != nil {
 						return err
 					}
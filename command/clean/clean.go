@@ -1,81 +1,278 @@
 package clean
 
 import (
+	"fmt"
+	"sort"
+	"strings"
+
 	"github.com/docker/docker/api/types"
-	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/client"
+	"github.com/rodaine/table"
 	"github.com/spf13/cobra"
 
+	"github.com/craftcms/nitro/command/internal/environments"
+	"github.com/craftcms/nitro/pkg/config"
 	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/environment"
+	"github.com/craftcms/nitro/pkg/pathexists"
+	"github.com/craftcms/nitro/pkg/proxycontainer"
 	"github.com/craftcms/nitro/pkg/terminal"
 )
 
-const exampleText = `  # remove unused containers
-  nitro clean`
+const exampleText = `  # remove the containers, volumes, and images that are no longer used
+  nitro clean
+
+  # remove the resources without a confirmation
+  nitro clean --force`
 
-// NewCommand returns the command that is used to clean containers that do not exist in a specified
-// environment. It will also perform the backup for database containers.
+// helpers are the types of the containers that are created to run a single command, they
+// are orphaned once the command has finished.
+var helpers = map[string]bool{
+	"composer": true,
+	"logs":     true,
+	"npm":      true,
+	"php":      true,
+	"scan":     true,
+}
+
+// resource is a container, volume, or image that is no longer used by the environment.
+type resource struct {
+	Kind   string `json:"kind"`
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+	Size   int64  `json:"size"`
+	ID     string `json:"-"`
+}
+
+// result is the JSON output of the command.
+type result struct {
+	Removed   []resource `json:"removed"`
+	Reclaimed int64      `json:"reclaimed"`
+}
+
+// NewCommand returns the command that is used to remove the containers, volumes, and images
+// that are not referenced by the config, such as the containers of removed sites, the cache
+// volumes of composer and npm for paths that no longer exist, and old proxy images.
 func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outputer) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:        "clean",
-		Deprecated: "Use the `apply` command instead",
-		Short:      "Removes unused containers.",
-		Example:    exampleText,
+		Use:     "clean",
+		Short:   "Removes unused containers, volumes, and images.",
+		Example: exampleText,
+		Args:    cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			output.Info("Cleaning up…")
-
-			output.Pending("gathering details")
+			ctx := cmd.Context()
 
-			// get all of the containers for the environment
-			filter := filters.NewArgs()
-			filter.Add("label", containerlabels.Nitro+"=true")
-			containers, err := docker.ContainerList(cmd.Context(), types.ContainerListOptions{All: true, Filters: filter})
+			cfg, err := config.Load(home)
 			if err != nil {
 				return err
 			}
 
-			// check if each container exists
-			toRemove := []types.Container{}
-			for _, c := range containers {
-				// we should remove the container if it is a composer or npm container
-				if c.Labels[containerlabels.Type] == "composer" || c.Labels[containerlabels.Type] == "npm" {
-					toRemove = append(toRemove, c)
-				}
+			output.Pending("gathering details")
+
+			usage, err := docker.DiskUsage(ctx)
+			if err != nil {
+				output.Warning()
+				return fmt.Errorf("unable to get the containers, volumes, and images, %w", err)
 			}
 
 			output.Done()
 
-			// if there is nothing to remove don't remove it
-			if len(toRemove) == 0 {
+			orphans := find(cfg, usage, pathexists.IsDirectory)
+
+			res := result{Removed: []resource{}}
+
+			if len(orphans) == 0 {
+				if terminal.JSON {
+					return output.JSON(res)
+				}
+
 				output.Info("Nothing to remove 😅")
 
 				return nil
 			}
 
-			// remove each of the containers
-			for _, c := range toRemove {
-				// stop the container
-				if err := docker.ContainerStop(cmd.Context(), c.ID, nil); err != nil {
-					output.Warning()
-					output.Info(err.Error())
-					break
+			if !terminal.JSON {
+				tbl := table.New("Kind", "Name", "Size", "Reason").WithWriter(cmd.OutOrStdout()).WithPadding(2)
+				for _, o := range orphans {
+					tbl.AddRow(o.Kind, o.Name, size(o.Size), o.Reason)
+				}
+
+				tbl.Print()
+			}
+
+			if force, _ := cmd.Flags().GetBool("force"); !force {
+				confirm, err := output.Confirm(fmt.Sprintf("Remove %d unused resources and reclaim %s?", len(orphans), size(total(orphans))), false, "")
+				if err != nil {
+					return err
 				}
 
-				// remove the container
-				if err := docker.ContainerRemove(cmd.Context(), c.ID, types.ContainerRemoveOptions{RemoveVolumes: true}); err != nil {
+				if !confirm {
+					output.Info("Skipping clean, the resources will remain")
+
+					return nil
+				}
+			}
+
+			// the containers are removed first so the volumes and images are no longer in use
+			for _, o := range orphans {
+				output.Pending("removing", o.Kind, o.Name)
+
+				if err := remove(cmd, docker, o); err != nil {
 					output.Warning()
-					output.Info(err.Error())
-					break
+					output.Info(fmt.Sprintf("Unable to remove the %s %s, %s", o.Kind, o.Name, err))
+					continue
 				}
 
+				res.Removed = append(res.Removed, o)
+				res.Reclaimed += o.Size
+
 				output.Done()
 			}
 
-			output.Info("Cleanup completed 🛁")
+			if terminal.JSON {
+				return output.JSON(res)
+			}
+
+			output.Info("Cleanup completed, reclaimed", size(res.Reclaimed), "🛁")
 
 			return nil
 		},
 	}
 
+	cmd.Flags().BoolP("force", "f", false, "skip the confirmation")
+
 	return cmd
 }
+
+// find returns the containers, volumes, and images in the disk usage that are not used by the
+// config. The database containers are kept because apply backs up the databases before the
+// containers are removed, and the volumes that are protected or used by a container are kept.
+func find(cfg *config.Config, usage types.DiskUsage, exists func(string) bool) []resource {
+	known := make(map[string]bool)
+	for _, h := range environments.Hostnames(cfg) {
+		known[h] = true
+	}
+
+	var orphans []resource
+	for _, c := range usage.Containers {
+		if c == nil || c.Labels[containerlabels.Nitro] != environment.Label() || len(c.Names) == 0 {
+			continue
+		}
+
+		name := strings.TrimLeft(c.Names[0], "/")
+		kind := c.Labels[containerlabels.Type]
+
+		switch {
+		case c.Labels[containerlabels.Proxy] != "", kind == "share", kind == "database":
+			continue
+		case helpers[kind]:
+			if c.State != "running" {
+				orphans = append(orphans, resource{Kind: "container", Name: name, Reason: fmt.Sprintf("finished %s container", kind), Size: c.SizeRw, ID: c.ID})
+			}
+		case !known[name]:
+			orphans = append(orphans, resource{Kind: "container", Name: name, Reason: "not in the config", Size: c.SizeRw, ID: c.ID})
+		}
+	}
+
+	// the volumes of the databases and containers in the config are kept even when the
+	// container was removed, so apply can recreate the container with the data
+	protected := cfg.ProtectedVolumes()
+	for h := range known {
+		protected[h] = true
+	}
+
+	for _, ct := range cfg.Containers {
+		for _, v := range ct.Volumes {
+			protected[ct.GetVolumeName(v)] = true
+		}
+	}
+
+	for _, v := range usage.Volumes {
+		if v == nil || protected[v.Name] || v.Name == environment.Volume() {
+			continue
+		}
+
+		if v.UsageData != nil && v.UsageData.RefCount > 0 {
+			continue
+		}
+
+		var sz int64
+		if v.UsageData != nil && v.UsageData.Size > 0 {
+			sz = v.UsageData.Size
+		}
+
+		switch kind := v.Labels[containerlabels.Type]; {
+		case kind == "composer" || kind == "npm":
+			// the cache volumes are shared by the environments and are named after the path
+			if p := v.Labels[containerlabels.Path]; p != "" && !exists(p) {
+				orphans = append(orphans, resource{Kind: "volume", Name: v.Name, Reason: fmt.Sprintf("the %s path no longer exists", kind), Size: sz, ID: v.Name})
+			}
+		case v.Labels[containerlabels.Nitro] == environment.Label():
+			orphans = append(orphans, resource{Kind: "volume", Name: v.Name, Reason: "not used by a container", Size: sz, ID: v.Name})
+		}
+	}
+
+	for _, i := range usage.Images {
+		if i == nil || i.Containers > 0 {
+			continue
+		}
+
+		for _, tag := range i.RepoTags {
+			if strings.HasPrefix(tag, "craftcms/nitro-proxy:") && tag != proxycontainer.ProxyImage {
+				orphans = append(orphans, resource{Kind: "image", Name: tag, Reason: "superseded proxy version", Size: i.Size, ID: i.ID})
+				break
+			}
+		}
+	}
+
+	kinds := map[string]int{"container": 0, "volume": 1, "image": 2}
+	sort.SliceStable(orphans, func(i, j int) bool {
+		if kinds[orphans[i].Kind] != kinds[orphans[j].Kind] {
+			return kinds[orphans[i].Kind] < kinds[orphans[j].Kind]
+		}
+
+		return orphans[i].Name < orphans[j].Name
+	})
+
+	return orphans
+}
+
+// remove removes the container, volume, or image.
+func remove(cmd *cobra.Command, docker client.CommonAPIClient, r resource) error {
+	ctx := cmd.Context()
+
+	switch r.Kind {
+	case "container":
+		return docker.ContainerRemove(ctx, r.ID, types.ContainerRemoveOptions{Force: true, RemoveVolumes: true})
+	case "volume":
+		return docker.VolumeRemove(ctx, r.ID, false)
+	default:
+		_, err := docker.ImageRemove(ctx, r.ID, types.ImageRemoveOptions{PruneChildren: true})
+		return err
+	}
+}
+
+func total(resources []resource) int64 {
+	var t int64
+	for _, r := range resources {
+		t += r.Size
+	}
+
+	return t
+}
+
+// size returns the bytes in a human readable size (e.g. 1.5 MB).
+func size(b int64) string {
+	const unit = 1024
+	if b < unit {
+		return fmt.Sprintf("%d B", b)
+	}
+
+	div, exp := int64(unit), 0
+	for n := b / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %cB", float64(b)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,78 @@
+package clean
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+
+	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/proxycontainer"
+)
+
+func TestFind(t *testing.T) {
+	cfg := &config.Config{
+		Sites:     []config.Site{{Hostname: "craft.nitro"}},
+		Databases: []config.Database{{Engine: "mysql", Version: "8.0", Port: "3306"}},
+	}
+
+	dbHostname, err := cfg.Databases[0].GetHostname()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	labels := func(kv ...string) map[string]string {
+		l := map[string]string{containerlabels.Nitro: "true"}
+		for i := 0; i < len(kv); i += 2 {
+			l[kv[i]] = kv[i+1]
+		}
+
+		return l
+	}
+
+	usage := types.DiskUsage{
+		Containers: []*types.Container{
+			{ID: "site", Names: []string{"/craft.nitro"}, Labels: labels()},
+			{ID: "removed", Names: []string{"/old.nitro"}, Labels: labels(), SizeRw: 10},
+			{ID: "proxy", Names: []string{"/nitro-proxy"}, Labels: labels(containerlabels.Proxy, "true")},
+			{ID: "db", Names: []string{"/removed-db"}, Labels: labels(containerlabels.Type, "database")},
+			{ID: "composer", Names: []string{"/composer"}, Labels: labels(containerlabels.Type, "composer"), State: "exited"},
+			{ID: "npm", Names: []string{"/npm"}, Labels: labels(containerlabels.Type, "npm"), State: "running"},
+			{ID: "other", Names: []string{"/other"}, Labels: map[string]string{containerlabels.Nitro: "client-a"}},
+		},
+		Volumes: []*types.Volume{
+			{Name: "nitro", Labels: labels()},
+			{Name: dbHostname, Labels: labels()},
+			{Name: "unused", Labels: labels(), UsageData: &types.VolumeUsageData{Size: 20}},
+			{Name: "used", Labels: labels(), UsageData: &types.VolumeUsageData{RefCount: 1}},
+			{Name: "gone", Labels: map[string]string{containerlabels.Type: "composer", containerlabels.Path: "/gone"}},
+			{Name: "here", Labels: map[string]string{containerlabels.Type: "npm", containerlabels.Path: "/here"}},
+			{Name: "unrelated"},
+		},
+		Images: []*types.ImageSummary{
+			{ID: "old", RepoTags: []string{"craftcms/nitro-proxy:1.0.0"}, Size: 30},
+			{ID: "current", RepoTags: []string{proxycontainer.ProxyImage}},
+			{ID: "running", RepoTags: []string{"craftcms/nitro-proxy:0.9.0"}, Containers: 1},
+		},
+	}
+
+	exists := func(p string) bool { return p == "/here" }
+
+	var got []string
+	for _, r := range find(cfg, usage, exists) {
+		got = append(got, r.Kind+" "+r.Name)
+	}
+
+	want := []string{
+		"container composer",
+		"container old.nitro",
+		"volume gone",
+		"volume unused",
+		"image craftcms/nitro-proxy:1.0.0",
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("find() = %v, want %v", got, want)
+	}
+}
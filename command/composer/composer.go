@@ -1,12 +1,12 @@
 package composer
 
 import (
-	"bytes"
 	"context"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/filters"
@@ -16,11 +16,16 @@ import (
 	"github.com/docker/docker/pkg/stdcopy"
 	"github.com/spf13/cobra"
 
+	"github.com/craftcms/nitro/command/internal/ephemeral"
 	"github.com/craftcms/nitro/pkg/composer"
+	"github.com/craftcms/nitro/pkg/config"
 	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/environment"
+	"github.com/craftcms/nitro/pkg/imagepull"
 	"github.com/craftcms/nitro/pkg/pathexists"
 	"github.com/craftcms/nitro/pkg/terminal"
 	"github.com/craftcms/nitro/pkg/volumename"
+	"github.com/craftcms/nitro/pkg/workspace"
 )
 
 var (
@@ -32,12 +37,15 @@ const exampleText = `  # run composer install in a current directory using a con
   nitro composer install
 
   # use composer (without local installation) to create a new project
-  nitro composer create-project craftcms/craft my-project`
+  nitro composer create-project craftcms/craft my-project
+
+  # run composer install for every repository in the workspaces
+  nitro composer install --all`
 
 // NewCommand returns a new command that runs composer install or update for a directory.
 // This command allows users to skip installing composer on the host machine and will run
 // all the commands in a disposable docker container.
-func NewCommand(docker client.CommonAPIClient, output terminal.Outputer) *cobra.Command {
+func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outputer) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:                "composer",
 		Short:              "Runs a Composer command.",
@@ -48,6 +56,9 @@ func NewCommand(docker client.CommonAPIClient, output terminal.Outputer) *cobra.
 			var version string
 			version, args = versionFromArgs(args)
 
+			var all bool
+			all, args = allFromArgs(args)
+
 			ctx := cmd.Context()
 			if ctx == nil {
 				// when we call commands from other commands (e.g. create)
@@ -68,166 +79,216 @@ func NewCommand(docker client.CommonAPIClient, output terminal.Outputer) *cobra.
 				return fmt.Errorf("unable to find the absolute path, %w", err)
 			}
 
-			// determine the default action
-			action := args[0]
-			// if this is not a create project request, check for a composer.json
-			if action != "create-project" {
-				// get the full file path
-				composerPath := filepath.Join(path, "composer.json")
+			// run composer for each repository in the workspaces
+			if all {
+				cfg, err := config.Load(home)
+				if err != nil {
+					return err
+				}
+
+				repos, err := workspace.AllRepos(home, cfg)
+				if err != nil {
+					return err
+				}
+
+				for _, r := range repos {
+					if !pathexists.IsFile(filepath.Join(r, "composer.json")) {
+						continue
+					}
 
-				output.Pending("checking", composerPath)
+					output.Info("Running composer for", r)
 
-				// see if the file exists
-				if exists := pathexists.IsFile(composerPath); !exists {
-					output.Warning()
-					return fmt.Errorf("unable to find file %s", composerPath)
+					if err := run(ctx, docker, output, r, version, args); err != nil {
+						return err
+					}
 				}
 
-				output.Done()
+				return nil
 			}
 
-			image := fmt.Sprintf("docker.io/craftcms/%s:%s-dev", "cli", version)
+			return run(ctx, docker, output, path, version, args)
+		},
+	}
 
-			// filter for the image ref
-			filter := filters.NewArgs()
-			filter.Add("reference", image)
+	// set flags for the command
+	cmd.Flags().String("php-version", "7.4", "which php version to use")
+	cmd.Flags().Bool("all", false, "run the command for every repository in the workspaces")
 
-			// look for the image
-			images, err := docker.ImageList(ctx, types.ImageListOptions{Filters: filter})
-			if err != nil {
-				return fmt.Errorf("unable to get a list of images, %w", err)
-			}
+	return cmd
+}
 
-			// if we don't have the image, pull it
-			if len(images) == 0 {
-				rdr, err := docker.ImagePull(ctx, image, types.ImagePullOptions{All: false})
-				if err != nil {
-					return fmt.Errorf("unable to pull the docker image, %w", err)
-				}
+func run(ctx context.Context, docker client.CommonAPIClient, output terminal.Outputer, path, version string, args []string) error {
+	// determine the default action
+	action := args[0]
+	// if this is not a create project request, check for a composer.json
+	if action != "create-project" {
+		// get the full file path
+		composerPath := filepath.Join(path, "composer.json")
 
-				buf := &bytes.Buffer{}
-				if _, err := buf.ReadFrom(rdr); err != nil {
-					return fmt.Errorf("unable to read the output from pulling the image, %w", err)
-				}
-			}
+		output.Pending("checking", composerPath)
 
-			// remove the image ref filter
-			filter.Del("reference", image)
+		// see if the file exists
+		if exists := pathexists.IsFile(composerPath); !exists {
+			output.Warning()
+			return fmt.Errorf("unable to find file %s", composerPath)
+		}
 
-			// find the network
-			networkFilter := filters.NewArgs()
-			networkFilter.Add("name", "nitro-network")
+		output.Done()
+	}
 
-			// check if the network needs to be created
-			networks, err := docker.NetworkList(ctx, types.NetworkListOptions{Filters: networkFilter})
-			if err != nil {
-				return fmt.Errorf("unable to list the docker networks, %w", err)
-			}
+	image := fmt.Sprintf("docker.io/craftcms/%s:%s-dev", "cli", version)
 
-			var networkID string
-			for _, n := range networks {
-				if n.Name == "nitro-network" || strings.TrimLeft(n.Name, "/") == "nitro-network" {
-					networkID = n.ID
-				}
-			}
+	// filter for the image ref
+	filter := filters.NewArgs()
+	filter.Add("reference", image)
 
-			// add filters for the volume
-			filter.Add("label", containerlabels.Type+"=composer")
-			filter.Add("label", containerlabels.Path+"="+path)
+	// look for the image
+	images, err := docker.ImageList(ctx, types.ImageListOptions{Filters: filter})
+	if err != nil {
+		return fmt.Errorf("unable to get a list of images, %w", err)
+	}
 
-			// check if there is an existing volume
-			volumes, err := docker.VolumeList(ctx, filter)
-			if err != nil {
-				return err
-			}
+	// if we don't have the image, pull it
+	if len(images) == 0 {
+		output.Pending("pulling", image)
 
-			// set the volume name
-			volumeName := volumename.FromPath(strings.Join([]string{path, version}, string(os.PathSeparator)))
-
-			var pathVolume types.Volume
-			switch len(volumes.Volumes) {
-			case 1:
-				pathVolume = *volumes.Volumes[0]
-			case 0:
-				// create the volume if it does not exist
-				volume, err := docker.VolumeCreate(ctx, volumetypes.VolumeCreateBody{
-					Driver: "local",
-					Name:   volumeName,
-					Labels: map[string]string{
-						containerlabels.Type: "composer",
-						containerlabels.Path: path,
-					},
-				})
-				if err != nil {
-					return fmt.Errorf("unable to create the volume, %w", err)
-				}
+		if err := imagepull.Pull(ctx, docker, image, output); err != nil {
+			output.Warning()
+			return err
+		}
 
-				pathVolume = volume
-			}
+		output.Done()
+	}
 
-			// build the container options
-			opts := &composer.Options{
-				Image:    image,
-				Commands: args,
-				Labels: map[string]string{
-					containerlabels.Nitro: "true",
-					containerlabels.Type:  "composer",
-					containerlabels.Path:  path,
-				},
-				Volume: &pathVolume,
-				Path:   path,
-				NetworkConfig: &network.NetworkingConfig{
-					EndpointsConfig: map[string]*network.EndpointSettings{
-						"nitro-network": {
-							NetworkID: networkID,
-						},
-					},
-				},
-			}
+	// remove the image ref filter
+	filter.Del("reference", image)
 
-			// create the container
-			container, err := composer.CreateContainer(ctx, docker, opts)
-			if err != nil {
-				return fmt.Errorf("unable to create the composer container\n%w", err)
-			}
+	// find the network
+	networkFilter := filters.NewArgs()
+	networkFilter.Add("name", environment.Network())
 
-			// attach to the container
-			stream, err := docker.ContainerAttach(ctx, container.ID, types.ContainerAttachOptions{
-				Stream: true,
-				Stdout: true,
-				Stderr: true,
-				Logs:   true,
-			})
-			if err != nil {
-				return fmt.Errorf("unable to attach to container, %w", err)
-			}
-			defer stream.Close()
+	// check if the network needs to be created
+	networks, err := docker.NetworkList(ctx, types.NetworkListOptions{Filters: networkFilter})
+	if err != nil {
+		return fmt.Errorf("unable to list the docker networks, %w", err)
+	}
 
-			// run the container
-			if err := docker.ContainerStart(ctx, container.ID, types.ContainerStartOptions{}); err != nil {
-				return fmt.Errorf("unable to start the container, %w", err)
-			}
+	var networkID string
+	for _, n := range networks {
+		if n.Name == environment.Network() || strings.TrimLeft(n.Name, "/") == environment.Network() {
+			networkID = n.ID
+		}
+	}
 
-			// show the output to stdout and stderr
-			if _, err := stdcopy.StdCopy(os.Stdout, os.Stderr, stream.Reader); err != nil {
-				return fmt.Errorf("unable to copy the output of the container logs, %w", err)
-			}
+	// add filters for the volume
+	filter.Add("label", containerlabels.Type+"=composer")
+	filter.Add("label", containerlabels.Path+"="+path)
 
-			output.Info("composer", action, "completed 🤘")
+	// check if there is an existing volume
+	volumes, err := docker.VolumeList(ctx, filter)
+	if err != nil {
+		return err
+	}
 
-			// remove the container
-			if err := docker.ContainerRemove(ctx, container.ID, types.ContainerRemoveOptions{}); err != nil {
-				return err
-			}
+	// set the volume name
+	volumeName := volumename.FromPath(strings.Join([]string{path, version}, string(os.PathSeparator)))
+
+	var pathVolume types.Volume
+	switch len(volumes.Volumes) {
+	case 1:
+		pathVolume = *volumes.Volumes[0]
+	case 0:
+		// create the volume if it does not exist
+		volume, err := docker.VolumeCreate(ctx, volumetypes.VolumeCreateBody{
+			Driver: "local",
+			Name:   volumeName,
+			Labels: map[string]string{
+				containerlabels.Type: "composer",
+				containerlabels.Path: path,
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("unable to create the volume, %w", err)
+		}
+
+		pathVolume = volume
+	}
 
-			return nil
+	// build the container options
+	opts := &composer.Options{
+		Image:    image,
+		Commands: args,
+		Labels: ephemeral.Label(map[string]string{
+			containerlabels.Nitro: environment.Label(),
+			containerlabels.Type:  "composer",
+			containerlabels.Path:  path,
+		}, time.Now()),
+		Volume: &pathVolume,
+		Path:   path,
+		NetworkConfig: &network.NetworkingConfig{
+			EndpointsConfig: map[string]*network.EndpointSettings{
+				environment.Network(): {
+					NetworkID: networkID,
+				},
+			},
 		},
 	}
 
-	// set flags for the command
-	cmd.Flags().String("php-version", "7.4", "which php version to use")
+	// remove the containers left behind when a previous command was interrupted
+	_, _ = ephemeral.Collect(ctx, docker, time.Now())
 
-	return cmd
+	// create the container
+	container, err := composer.CreateContainer(ctx, docker, opts)
+	if err != nil {
+		return fmt.Errorf("unable to create the composer container\n%w", err)
+	}
+
+	// attach to the container
+	stream, err := docker.ContainerAttach(ctx, container.ID, types.ContainerAttachOptions{
+		Stream: true,
+		Stdout: true,
+		Stderr: true,
+		Logs:   true,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to attach to container, %w", err)
+	}
+	defer stream.Close()
+
+	// run the container
+	if err := docker.ContainerStart(ctx, container.ID, types.ContainerStartOptions{}); err != nil {
+		return fmt.Errorf("unable to start the container, %w", err)
+	}
+
+	// show the output to stdout and stderr
+	if _, err := stdcopy.StdCopy(os.Stdout, os.Stderr, stream.Reader); err != nil {
+		return fmt.Errorf("unable to copy the output of the container logs, %w", err)
+	}
+
+	output.Info("composer", action, "completed 🤘")
+
+	// remove the container
+	if err := docker.ContainerRemove(ctx, container.ID, types.ContainerRemoveOptions{}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// allFromArgs removes the --all flag from the args and returns true if it was set.
+func allFromArgs(args []string) (bool, []string) {
+	var all bool
+	var newArgs []string
+	for _, a := range args {
+		if a == "--all" {
+			all = true
+			continue
+		}
+
+		newArgs = append(newArgs, a)
+	}
+
+	return all, newArgs
 }
 
 func versionFromArgs(args []string) (string, []string) {
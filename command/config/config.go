@@ -0,0 +1,28 @@
+package config
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/craftcms/nitro/pkg/terminal"
+)
+
+const exampleText = `  # show the JSON schema for the config file
+  nitro config schema`
+
+// NewCommand returns the config commands for working with the config file.
+func NewCommand(home string, output terminal.Outputer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "config",
+		Short:   "Manages the config file.",
+		Example: exampleText,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(
+		schemaCommand(home, output),
+	)
+
+	return cmd
+}
@@ -0,0 +1,55 @@
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/environment"
+	"github.com/craftcms/nitro/pkg/terminal"
+)
+
+var schemaExampleText = `  # show the JSON schema for the config file
+  nitro config schema
+
+  # save the schema to use with an editor
+  nitro config schema --output nitro.schema.json`
+
+// schemaCommand prints the JSON schema for the config file. The schema is also written next
+// to the config file each time it is saved, which the config file references for editors.
+func schemaCommand(home string, output terminal.Outputer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "schema",
+		Short:   "Shows the JSON schema for the config.",
+		Example: schemaExampleText,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			schema, err := config.Schema()
+			if err != nil {
+				return err
+			}
+
+			file := cmd.Flag("output").Value.String()
+			if file == "" {
+				fmt.Fprintln(cmd.OutOrStdout(), string(schema))
+
+				return nil
+			}
+
+			if err := ioutil.WriteFile(file, schema, 0644); err != nil {
+				return fmt.Errorf("unable to write the schema, %w", err)
+			}
+
+			output.Info("Schema saved to", file)
+			output.Info("The config file", filepath.Join(home, config.DirectoryName, environment.ConfigFile()), "already references the schema for editors that use the YAML language server.")
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringP("output", "o", "", "file to save the schema to")
+
+	return cmd
+}
@@ -70,7 +70,7 @@ func newCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 			}
 
 			// prompt for the image we found
-			selection, err := output.Select(cmd.InOrStdin(), "Which image should we use?", options)
+			selection, err := prompt.Select(cmd.InOrStdin(), "Which image should we use?", options, output)
 			if err != nil {
 				return err
 			}
@@ -153,7 +153,7 @@ func newCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 				switch len(opts) == 0 {
 				case false:
 					// prompt the user for the port
-					selected, err := output.Select(cmd.InOrStdin(), "Which port should we use for the UI?", opts)
+					selected, err := prompt.Select(cmd.InOrStdin(), "Which port should we use for the UI?", opts, output)
 					if err != nil {
 						return err
 					}
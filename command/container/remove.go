@@ -16,7 +16,10 @@ func removeCommand(home string, docker client.CommonAPIClient, output terminal.O
 		Use:   "remove",
 		Short: "Removes a custom container.",
 		Example: `  # remove a custom container from the config
-  nitro container remove`,
+  nitro container remove
+
+  # remove a custom container that is marked as protected
+  nitro container remove --include-protected`,
 		Aliases: []string{"rm"},
 		PostRunE: func(cmd *cobra.Command, args []string) error {
 			return prompt.RunApply(cmd, args, false, output)
@@ -41,7 +44,7 @@ func removeCommand(home string, docker client.CommonAPIClient, output terminal.O
 			}
 
 			// prompt for the container to remove
-			selected, err := output.Select(cmd.InOrStdin(), "Select the custom container to remove: ", options)
+			selected, err := prompt.Select(cmd.InOrStdin(), "Select the custom container to remove: ", options, output)
 			if err != nil {
 				return err
 			}
@@ -51,6 +54,11 @@ func removeCommand(home string, docker client.CommonAPIClient, output terminal.O
 				return err
 			}
 
+			// protected containers require an extra flag
+			if container.Protected && cmd.Flag("include-protected").Value.String() != "true" {
+				return fmt.Errorf("the container %s is protected, use --include-protected to remove it", container.Name)
+			}
+
 			// remove the container
 			if err := cfg.RemoveContainer(container); err != nil {
 				return err
@@ -65,5 +73,7 @@ func removeCommand(home string, docker client.CommonAPIClient, output terminal.O
 		},
 	}
 
+	cmd.Flags().Bool("include-protected", false, "allow removing a protected container")
+
 	return cmd
 }
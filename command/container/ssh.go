@@ -7,6 +7,8 @@ import (
 	"strings"
 
 	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/environment"
+	"github.com/craftcms/nitro/pkg/prompt"
 	"github.com/craftcms/nitro/pkg/terminal"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/filters"
@@ -25,7 +27,7 @@ func sshCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// add filters to show only the environment and database containers
 			filter := filters.NewArgs()
-			filter.Add("label", containerlabels.Nitro)
+			filter.Add("label", containerlabels.Nitro+"="+environment.Label())
 			filter.Add("label", containerlabels.Type+"=custom")
 
 			// get a list of all the containers
@@ -57,7 +59,7 @@ func sshCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 			}
 
 			// prompt for the container to ssh into
-			selected, err := output.Select(cmd.InOrStdin(), "Select a container to connect to: ", containerList)
+			selected, err := prompt.Select(cmd.InOrStdin(), "Select a container to connect to: ", containerList, output)
 			if err != nil {
 				return err
 			}
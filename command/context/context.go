@@ -4,11 +4,17 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/client"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
 
+	"github.com/craftcms/nitro/command/internal/service"
 	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/dockercontext"
+	nitroenv "github.com/craftcms/nitro/pkg/environment"
 	"github.com/craftcms/nitro/pkg/terminal"
 )
 
@@ -16,8 +22,38 @@ const exampleText = `  # view all resources for the environment
   nitro context
 
   # show only the config file
-  nitro context --yaml`
+  nitro context --yaml
 
+  # show the environment as JSON to include in a support request
+  nitro context --json`
+
+// environment is the resolved state of the environment, it is also the JSON output of
+// the command.
+type environment struct {
+	Version       string         `json:"version"`
+	Environment   string         `json:"environment,omitempty"`
+	ConfigFile    string         `json:"config_file"`
+	DockerHost    string         `json:"docker_host"`
+	DockerContext string         `json:"docker_context,omitempty"`
+	DockerError   string         `json:"docker_error,omitempty"`
+	Owner         string         `json:"owner,omitempty"`
+	ProxyVersion  string         `json:"proxy_version"`
+	ProxyStatus   string         `json:"proxy_status"`
+	Resources     []resource     `json:"resources"`
+	Config        *config.Config `json:"config"`
+}
+
+// resource is a site, database, service, or custom container in the config and the
+// status of its container.
+type resource struct {
+	Name   string `json:"name"`
+	Type   string `json:"type"`
+	Status string `json:"status"`
+}
+
+// NewCommand returns the command to show the resolved configuration, the Docker endpoint,
+// the proxy version, and the status of the container for each site, database, service,
+// and custom container in one view.
 func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outputer) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "context",
@@ -30,14 +66,57 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 				return err
 			}
 
+			redact(cfg)
+
 			// if they are asking for yaml, show only the yaml
 			if cmd.Flag("yaml").Value.String() == "true" {
 				return yamlFmt(cfg)
 			}
 
-			output.Info("Craft Nitro", cmd.Root().Version)
+			env := environment{
+				Version:       cmd.Root().Version,
+				Environment:   cfg.Name,
+				ConfigFile:    cfg.GetFile(),
+				DockerHost:    docker.DaemonHost(),
+				DockerContext: dockercontext.Current.Name,
+				Owner:         nitroenv.Owner,
+				Config:        cfg,
+			}
+
+			// the config is still shown when docker is not running
+			filter := filters.NewArgs()
+			filter.Add("label", containerlabels.Nitro+"="+nitroenv.Label())
+
+			containers, err := docker.ContainerList(cmd.Context(), types.ContainerListOptions{All: true, Filters: filter})
+			if err != nil {
+				env.DockerError = err.Error()
+			}
+
+			env.ProxyVersion, env.ProxyStatus = proxy(containers, err == nil)
+			env.Resources = resources(cfg, containers, err == nil)
+
+			if terminal.JSON {
+				return output.JSON(env)
+			}
+
+			output.Info("Craft Nitro", env.Version)
 			output.Info("")
-			output.Info("Configuration:\t", cfg.File)
+			if env.Environment != "" {
+				output.Info("Environment:\t", env.Environment)
+			}
+			output.Info("Configuration:\t", env.ConfigFile)
+			if env.DockerContext != "" && env.DockerContext != dockercontext.Default {
+				output.Info("Docker:\t", env.DockerHost, "(context "+env.DockerContext+")")
+			} else {
+				output.Info("Docker:\t", env.DockerHost)
+			}
+			if env.DockerError != "" {
+				output.Info("Docker error:\t", env.DockerError)
+			}
+			if env.Owner != "" {
+				output.Info("Owner:\t", env.Owner, "(shared daemon)")
+			}
+			output.Info("Proxy:\t", env.ProxyVersion, "("+env.ProxyStatus+")")
 			output.Info("")
 
 			output.Info(`Sites:`)
@@ -49,6 +128,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 				output.Info("  php:\t", site.Version)
 				output.Info("  webroot:\t", site.Webroot)
 				output.Info("  path:\t", site.Path)
+				output.Info("  status:\t", statusOf(env.Resources, site.Hostname))
 				output.Info("  ---")
 			}
 
@@ -58,9 +138,24 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 				output.Info("  engine:\t", db.Engine, db.Version, "\thostname:", hostname)
 				output.Info("  username:\t", "nitro", "\tpassword:", "nitro")
 				output.Info("  port:\t", db.Port)
+				output.Info("  status:\t", statusOf(env.Resources, hostname))
 				output.Info("  ---")
 			}
 
+			var others []resource
+			for _, r := range env.Resources {
+				if r.Type == "service" || r.Type == "container" {
+					others = append(others, r)
+				}
+			}
+
+			if len(others) > 0 {
+				output.Info(`Services and containers:`)
+				for _, r := range others {
+					output.Info(fmt.Sprintf("  %s:\t", r.Name), r.Status)
+				}
+			}
+
 			return nil
 		},
 	}
@@ -70,16 +165,106 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 	return cmd
 }
 
-func yamlFmt(cfg *config.Config) error {
-	// redact blackfire credentials
+// proxy returns the version and status of the proxy container.
+func proxy(containers []types.Container, listed bool) (string, string) {
+	if !listed {
+		return "unknown", "unknown"
+	}
+
+	for _, c := range containers {
+		if c.Labels[containerlabels.Type] == "proxy" {
+			return c.Labels[containerlabels.ProxyVersion], state(c)
+		}
+	}
+
+	return "none", "not created"
+}
+
+// resources returns the sites, databases, services, and custom containers in the config
+// with the status of the container for each of them. Containers are named after the
+// hostname of the resource.
+func resources(cfg *config.Config, containers []types.Container, listed bool) []resource {
+	states := map[string]string{}
+	for _, c := range containers {
+		states[strings.TrimLeft(c.Names[0], "/")] = state(c)
+	}
+
+	status := func(name string) string {
+		if !listed {
+			return "unknown"
+		}
+
+		if s, ok := states[name]; ok {
+			return s
+		}
+
+		return "not created"
+	}
+
+	var list []resource
+	for _, s := range cfg.Sites {
+		list = append(list, resource{Name: s.Hostname, Type: "site", Status: status(s.Hostname)})
+	}
+
+	for _, db := range cfg.Databases {
+		h, err := db.GetHostname()
+		if err != nil {
+			continue
+		}
+
+		list = append(list, resource{Name: h, Type: "database", Status: status(h)})
+	}
+
+	for _, h := range service.Hostnames(cfg) {
+		list = append(list, resource{Name: h, Type: "service", Status: status(h)})
+	}
+
+	for _, c := range cfg.Containers {
+		for _, h := range c.GetHostnames() {
+			list = append(list, resource{Name: h, Type: "container", Status: status(h)})
+		}
+	}
+
+	if list == nil {
+		return []resource{}
+	}
+
+	return list
+}
+
+// statusOf returns the status of the resource with the name.
+func statusOf(list []resource, name string) string {
+	for _, r := range list {
+		if r.Name == name {
+			return r.Status
+		}
+	}
+
+	return "unknown"
+}
+
+// state returns the state of the container, stopped containers are shown as stopped
+// instead of exited.
+func state(c types.Container) string {
+	if c.State == "exited" {
+		return "stopped"
+	}
+
+	return c.State
+}
+
+// redact removes the blackfire credentials from the config.
+func redact(cfg *config.Config) {
 	if cfg.Blackfire.ServerID != "" {
 		cfg.Blackfire.ServerID = "****************"
 	}
 	if cfg.Blackfire.ServerToken != "" {
 		cfg.Blackfire.ServerToken = "********************************"
 	}
+}
 
-	// marshal into the struct version so we can remove the blackfire credentials
+// yamlFmt shows the config as it is saved in the config file.
+func yamlFmt(cfg *config.Config) error {
 	data, err := yaml.Marshal(cfg)
 	if err != nil {
 		return err
@@ -0,0 +1,66 @@
+package context
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+
+	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/containerlabels"
+)
+
+func TestResources(t *testing.T) {
+	cfg := &config.Config{
+		Sites:      []config.Site{{Hostname: "craft.nitro"}, {Hostname: "new.nitro"}},
+		Databases:  []config.Database{{Engine: "mysql", Version: "8.0", Port: "3306"}},
+		Containers: []config.Container{{Name: "search"}},
+	}
+
+	containers := []types.Container{
+		{Names: []string{"/craft.nitro"}, State: "running"},
+		{Names: []string{"/mysql-8.0-3306.database.nitro"}, State: "exited"},
+		{Names: []string{"/nitro-proxy"}, State: "running", Labels: map[string]string{containerlabels.Type: "proxy", containerlabels.ProxyVersion: "2.0.0"}},
+	}
+
+	want := []resource{
+		{Name: "craft.nitro", Type: "site", Status: "running"},
+		{Name: "new.nitro", Type: "site", Status: "not created"},
+		{Name: "mysql-8.0-3306.database.nitro", Type: "database", Status: "stopped"},
+		{Name: "search.containers.nitro", Type: "container", Status: "not created"},
+	}
+
+	if got := resources(cfg, containers, true); !reflect.DeepEqual(got, want) {
+		t.Errorf("resources() = %v, want %v", got, want)
+	}
+
+	if version, status := proxy(containers, true); version != "2.0.0" || status != "running" {
+		t.Errorf("proxy() = %s, %s, want 2.0.0, running", version, status)
+	}
+
+	// when docker is not available the status is unknown
+	for _, r := range resources(cfg, nil, false) {
+		if r.Status != "unknown" {
+			t.Errorf("expected the status of %s to be unknown, got %s", r.Name, r.Status)
+		}
+	}
+}
+
+func TestRedact(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Blackfire.ServerID = "id"
+	cfg.Blackfire.ServerToken = "secret"
+
+	redact(cfg)
+
+	data, err := json.Marshal(environment{Config: cfg})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(string(data), "secret") || strings.Contains(string(data), `"id"`) {
+		t.Errorf("expected the blackfire credentials to be redacted, got %s", data)
+	}
+}
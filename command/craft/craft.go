@@ -1,19 +1,19 @@
 package craft
 
 import (
-	"context"
 	"fmt"
 	"os"
-	"os/exec"
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/client"
-	"github.com/docker/docker/pkg/stdcopy"
 	"github.com/spf13/cobra"
 
 	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/containerexec"
 	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/environment"
+	"github.com/craftcms/nitro/pkg/prompt"
 	"github.com/craftcms/nitro/pkg/terminal"
 )
 
@@ -24,7 +24,10 @@ const exampleText = `  # run craft console command in a sites container
   nitro craft
 
   # enter the craft shell
-  nitro craft shell`
+  nitro craft shell
+
+  # apply the project config each time the files in config/project change
+  nitro craft project-config/apply --watch`
 
 // NewCommand returns the craft command which allows users to pass craft specific commands to a sites
 // container. Its context aware and will prompt the user for the site if its not in a directory.
@@ -35,6 +38,9 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 		DisableFlagParsing: true,
 		Example:            exampleText,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			// check if the project config should be applied on changes
+			args, watching := watchArgs(args)
+
 			// get the current working directory
 			wd, err := os.Getwd()
 			if err != nil {
@@ -49,7 +55,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 
 			// create a filter for the environment
 			filter := filters.NewArgs()
-			filter.Add("label", containerlabels.Nitro)
+			filter.Add("label", containerlabels.Nitro+"="+environment.Label())
 
 			// get a context aware list of sites
 			sites := cfg.ListOfSitesByDirectory(home, wd)
@@ -64,7 +70,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 			switch len(sites) {
 			case 0:
 				// prompt for the site
-				selected, err := output.Select(cmd.InOrStdin(), "Select a site: ", options)
+				selected, err := prompt.Select(cmd.InOrStdin(), "Select a site: ", options, output)
 				if err != nil {
 					return err
 				}
@@ -84,7 +90,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 				filter.Add("label", containerlabels.Host+"="+sites[0].Hostname)
 			default:
 				// prompt for the site to ssh into
-				selected, err := output.Select(cmd.InOrStdin(), "Select a site: ", options)
+				selected, err := prompt.Select(cmd.InOrStdin(), "Select a site: ", options, output)
 				if err != nil {
 					return err
 				}
@@ -119,7 +125,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 			}
 
 			// create the command for running the craft console
-			cmds := []string{"exec", "-it", containers[0].ID, "php"}
+			cmds := []string{"php"}
 
 			// get the container path
 			path := site.GetContainerPath()
@@ -138,24 +144,24 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 				cmds = append(cmds, args...)
 			}
 
-			// find the docker executable
-			cli, err := exec.LookPath("docker")
-			if err != nil {
-				return err
-			}
-
-			// create the command
-			c := exec.Command(cli, cmds...)
+			if watching {
+				dir, err := projectConfigDir(home, site)
+				if err != nil {
+					return err
+				}
 
-			c.Stdin = cmd.InOrStdin()
-			c.Stderr = cmd.ErrOrStderr()
-			c.Stdout = cmd.OutOrStdout()
+				return watchProjectConfig(cmd.Context(), docker, containers[0].ID, dir, cmds, output)
+			}
 
-			if err := c.Run(); err != nil {
+			// run the command as the containers default user
+			code, err := containerexec.Interactive(cmd.Context(), docker, containers[0].ID, "", cmds, cmd.InOrStdin(), cmd.OutOrStdout(), cmd.ErrOrStderr())
+			if err != nil {
 				return err
 			}
 
-			// attach
+			if code != 0 {
+				return fmt.Errorf("craft exited with code %d", code)
+			}
 
 			return nil
 		},
@@ -163,48 +169,3 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 
 	return cmd
 }
-
-func execCreate(ctx context.Context, docker client.ContainerAPIClient, containerID string, cmds []string, show bool) (bool, error) {
-	// create the exec
-	e, err := docker.ContainerExecCreate(ctx, containerID, types.ExecConfig{
-		AttachStdout: true,
-		AttachStderr: true,
-		Tty:          false,
-		Cmd:          cmds,
-	})
-	if err != nil {
-		return false, err
-	}
-
-	// attach to the container
-	resp, err := docker.ContainerExecAttach(ctx, e.ID, types.ExecStartCheck{
-		Tty: false,
-	})
-	defer resp.Close()
-
-	// should we display output?
-	if show {
-		// show the output to stdout and stderr
-		if _, err := stdcopy.StdCopy(os.Stdout, os.Stderr, resp.Reader); err != nil {
-			return false, fmt.Errorf("unable to copy the output of container, %w", err)
-		}
-	}
-
-	// start the exec
-	if err := docker.ContainerExecStart(ctx, e.ID, types.ExecStartCheck{}); err != nil {
-		return false, fmt.Errorf("unable to start the container, %w", err)
-	}
-
-	// wait for the container exec to complete
-	waiting := true
-	for waiting {
-		resp, err := docker.ContainerExecInspect(ctx, e.ID)
-		if err != nil {
-			return false, err
-		}
-
-		waiting = resp.Running
-	}
-
-	return true, nil
-}
@@ -0,0 +1,85 @@
+package craft
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/docker/client"
+
+	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/containerexec"
+	"github.com/craftcms/nitro/pkg/terminal"
+	"github.com/craftcms/nitro/pkg/watcher"
+)
+
+// watchFlag is removed from the arguments to craft to watch the project config.
+const watchFlag = "--watch"
+
+// watchArgs returns true and the arguments for craft without the watch flag when the
+// arguments apply the project config with the watch flag. Both project-config/apply and
+// project-config apply are supported.
+func watchArgs(args []string) ([]string, bool) {
+	var rest []string
+	watch := false
+	for _, a := range args {
+		if a == watchFlag {
+			watch = true
+			continue
+		}
+
+		rest = append(rest, a)
+	}
+
+	if len(rest) >= 2 && rest[0] == "project-config" && rest[1] == "apply" {
+		rest = append([]string{"project-config/apply"}, rest[2:]...)
+	}
+
+	if !watch || len(rest) == 0 || rest[0] != "project-config/apply" {
+		return args, false
+	}
+
+	return rest, true
+}
+
+// projectConfigDir returns the project config directory of the site on the host, the site
+// path is mounted in the container at /app.
+func projectConfigDir(home string, site config.Site) (string, error) {
+	path, err := site.GetAbsPath(home)
+	if err != nil {
+		return "", err
+	}
+
+	root := strings.TrimPrefix(site.GetContainerPath(), "/app")
+
+	return filepath.Join(path, filepath.FromSlash(root), "config", "project"), nil
+}
+
+// watchProjectConfig applies the project config in the container each time the files in
+// the project config directory of the site change until the command is interrupted.
+func watchProjectConfig(ctx context.Context, docker client.CommonAPIClient, containerID, dir string, cmds []string, output terminal.Outputer) error {
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return fmt.Errorf("unable to find the project config directory %s", dir)
+	}
+
+	output.Info("Watching", dir, "for changes, press Ctrl+C to stop")
+
+	return watcher.Watch(ctx, dir, watcher.Delay, func(ctx context.Context) {
+		output.Pending("applying project config")
+
+		out, err := containerexec.Run(ctx, docker, containerID, cmds)
+		if err != nil {
+			output.Warning()
+			output.Info(err.Error())
+			return
+		}
+
+		output.Done()
+
+		if out != "" {
+			output.Info(out)
+		}
+	})
+}
@@ -0,0 +1,48 @@
+package craft
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/craftcms/nitro/pkg/config"
+)
+
+func Test_watchArgs(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		want      []string
+		wantWatch bool
+	}{
+		{name: "project config apply with watch", args: []string{"project-config/apply", "--watch"}, want: []string{"project-config/apply"}, wantWatch: true},
+		{name: "spaces are converted to the command", args: []string{"project-config", "apply", "--watch", "--force"}, want: []string{"project-config/apply", "--force"}, wantWatch: true},
+		{name: "apply without watch is passed through", args: []string{"project-config/apply"}, want: []string{"project-config/apply"}},
+		{name: "watch for other commands is passed through", args: []string{"queue/listen", "--watch"}, want: []string{"queue/listen", "--watch"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, watch := watchArgs(tt.args)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("watchArgs() got = %v, want %v", got, tt.want)
+			}
+
+			if watch != tt.wantWatch {
+				t.Errorf("watchArgs() watch = %v, want %v", watch, tt.wantWatch)
+			}
+		})
+	}
+}
+
+func Test_projectConfigDir(t *testing.T) {
+	home := filepath.Join(string(filepath.Separator), "home", "nitro")
+
+	got, err := projectConfigDir(home, config.Site{Path: "~/dev/craft", Webroot: "/app/project/web"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := filepath.Join(home, "dev", "craft", "project", "config", "project"); got != want {
+		t.Errorf("projectConfigDir() = %q, want %q", got, want)
+	}
+}
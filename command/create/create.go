@@ -3,6 +3,7 @@ package create
 import (
 	"fmt"
 	"io"
+	"io/ioutil"
 	"net/url"
 	"os"
 	"path/filepath"
@@ -12,6 +13,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/craftcms/nitro/command/create/internal/urlgen"
+	"github.com/craftcms/nitro/pkg/config"
 	"github.com/craftcms/nitro/pkg/directory"
 	"github.com/craftcms/nitro/pkg/downloader"
 	"github.com/craftcms/nitro/pkg/envedit"
@@ -20,7 +22,7 @@ import (
 	"github.com/craftcms/nitro/pkg/terminal"
 )
 
-const exampleText = `  # create a new default craft project (similar to "composer create-project craftcms/craft my-project")
+const exampleText = `  # create a new craft project with "composer create-project craftcms/craft", add the site, and apply
   nitro create my-project
 
   # bring your own git repo
@@ -30,7 +32,9 @@ const exampleText = `  # create a new default craft project (similar to "compose
   nitro create craftcms/demo my-project`
 
 // NewCommand returns the create command to automate the process of setting up a new Craft project.
-// It also allows you to pass an option argument that is a URL to your own github repo.
+// The project is created with composer create-project craftcms/craft, or is downloaded from the
+// URL of a github repo when it is passed. The directory is added as a site, a database can be
+// created, the .env is updated with the database credentials and the site URL, and apply runs.
 func NewCommand(home string, docker client.CommonAPIClient, getter downloader.Getter, output terminal.Outputer) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "create",
@@ -41,7 +45,7 @@ func NewCommand(home string, docker client.CommonAPIClient, getter downloader.Ge
 			return prompt.RunApply(cmd, args, true, output)
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			// get the url from args or the default
+			// get the url of the repository from args, the default project is created with composer
 			var download *url.URL
 			var dir string
 
@@ -57,62 +61,63 @@ func NewCommand(home string, docker client.CommonAPIClient, getter downloader.Ge
 
 				dir = filepath.Join(args[1])
 			default:
-				// only the directory was provided, download craft to that directory
-				u, err := urlgen.Generate("")
-				if err != nil {
-					return err
-				}
-
-				download = u
-
 				dir = filepath.Join(args[0])
 			}
 
+			// composer runs in the directory, so the path is made absolute first
+			dir, err := filepath.Abs(dir)
+			if err != nil {
+				return err
+			}
+
 			// check if the directory already exists
 			if pathexists.IsDirectory(dir) && !directory.IsEmpty(dir) {
 				return fmt.Errorf("directory %q already exists", dir)
 			}
 
-			output.Info("Downloading", download.String(), "...")
+			if download != nil {
+				output.Info("Downloading", download.String(), "...")
 
-			output.Pending("setting up project")
+				output.Pending("setting up project")
 
-			// download the file
-			if err := getter.Get(download.String(), dir); err != nil {
-				return err
-			}
-
-			output.Done()
+				// download the file
+				if err := getter.Get(download.String(), dir); err != nil {
+					return err
+				}
 
-			output.Info("New site downloaded 🤓")
+				output.Done()
 
-			// --- done with download
+				output.Info("New site downloaded 🤓")
+			} else {
+				output.Info("Creating the project with composer…")
 
-			exampleEnv := filepath.Join(dir, ".env.example")
+				if err := os.MkdirAll(dir, 0755); err != nil {
+					return err
+				}
 
-			// check if the directory has a .env-example
-			if pathexists.IsFile(exampleEnv) {
-				// open the example
-				example, err := os.Open(exampleEnv)
-				if err != nil {
-					output.Info("unable to open the file", exampleEnv)
+				if err := runComposer(cmd, home, dir, "create-project", "craftcms/craft", ".", "--ignore-platform-reqs"); err != nil {
+					return err
 				}
-				defer example.Close()
+			}
 
-				// create the env file
-				env, err := os.Create(filepath.Join(dir, ".env"))
-				if err != nil {
-					output.Info("unable to create the file", filepath.Join(dir, ".env"))
+			// --- done with the project files
+
+			envFilePath := filepath.Join(dir, ".env")
+
+			// copy the example env when the project did not create one
+			for _, name := range []string{".env.example.dev", ".env.example"} {
+				exampleEnv := filepath.Join(dir, name)
+				if !pathexists.IsFile(exampleEnv) || pathexists.IsFile(envFilePath) {
+					continue
 				}
-				defer env.Close()
 
-				if _, err := io.Copy(env, example); err != nil {
-					output.Info("unable to copy the example env")
+				if err := copyFile(exampleEnv, envFilePath); err != nil {
+					output.Info("unable to copy the example env,", err.Error())
 				}
 			}
 
 			// walk the user through the site
-			_, err := prompt.CreateSite(home, dir, output)
+			site, err := prompt.CreateSite(home, dir, output)
 			if err != nil {
 				return err
 			}
@@ -123,71 +128,39 @@ func NewCommand(home string, docker client.CommonAPIClient, getter downloader.Ge
 				return err
 			}
 
-			envFilePath := filepath.Join(dir, ".env")
-
-			// if the wanted a new database edit the env
-			if database && pathexists.IsFile(envFilePath) {
-				// ask the user if we should update the .env?
-				updateEnv, err := output.Confirm("Should we update the env file?", true, "")
-				if err != nil {
-					return err
+			// set the database credentials and site url in the env
+			if pathexists.IsFile(envFilePath) {
+				var key string
+				if !envedit.EnvExists(envFilePath, "SECURITY_KEY") && !envedit.EnvExists(envFilePath, "CRAFT_SECURITY_KEY") {
+					key = uuid.New().String()
 				}
 
-				if updateEnv {
-					key := uuid.New()
-
-					// update the env
-					update, err := envedit.Edit(envFilePath, map[string]string{
-						"SECURITY_KEY": key.String(),
-						"DB_SERVER":    dbhost,
-						"DB_DATABASE":  dbname,
-						"DB_PORT":      port,
-						"DB_DRIVER":    driver,
-						"DB_USER":      "nitro",
-						"DB_PASSWORD":  "nitro",
-					})
-					if err != nil {
-						output.Info("unable to edit the env")
-					}
-
-					// open the file
-					f, err := os.OpenFile(envFilePath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0755)
-					if err != nil {
-						return err
-					}
-					defer f.Close()
-
-					// truncate the file
-					if err := f.Truncate(0); err != nil {
-						return err
-					}
-
-					// write the new contents
-					if _, err := f.Write([]byte(update)); err != nil {
-						return err
+				vars := envVars(site.Hostname, key)
+				if database {
+					for k, v := range dbEnvVars(dbhost, dbname, port, driver) {
+						vars[k] = v
 					}
+				}
 
-					output.Info(".env updated!")
+				update, err := envedit.Edit(envFilePath, vars)
+				if err != nil {
+					return err
+				}
 
-					output.Info("Preparing composer...")
+				if err := ioutil.WriteFile(envFilePath, []byte(update), 0644); err != nil {
+					return err
 				}
+
+				output.Info(".env updated!")
 			}
 
-			// run the composer install command
-			for _, c := range cmd.Parent().Commands() {
-				if c.Use == "composer" {
-					// change into the projects new directory for the composer install
-					if err := os.Chdir(filepath.Join(dir)); err != nil {
-						break
-					}
+			// install the dependencies of the repository
+			if download != nil {
+				output.Info("Preparing composer...")
 
-					// run composer install using the new directory
-					// we pass the command itself instead of the parent
-					// command
-					if err := c.RunE(c, []string{"create-project", "--ignore-platform-reqs"}); err != nil {
-						output.Info(err.Error())
-						break
-					}
+				// create-project without a package installs the composer.json and runs the project scripts
+				if err := runComposer(cmd, home, dir, "create-project", "--ignore-platform-reqs"); err != nil {
+					output.Info(err.Error())
 				}
 			}
 
@@ -197,3 +170,81 @@ func NewCommand(home string, docker client.CommonAPIClient, getter downloader.Ge
 
 	return cmd
 }
+
+// runComposer runs the composer command in the directory using the PHP version from the
+// config defaults, the composer command uses the current directory as the project.
+func runComposer(cmd *cobra.Command, home, dir string, args ...string) error {
+	if cfg, err := config.Load(home); err == nil && cfg.Defaults.PHP != "" {
+		args = append(args, "--php-version="+cfg.Defaults.PHP)
+	}
+
+	for _, c := range cmd.Root().Commands() {
+		if c.Use != "composer" {
+			continue
+		}
+
+		wd, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+		defer os.Chdir(wd)
+
+		if err := os.Chdir(dir); err != nil {
+			return err
+		}
+
+		return c.RunE(c, args)
+	}
+
+	return fmt.Errorf("unable to find the composer command")
+}
+
+// envVars returns the env values for the site url, the nitro database user, and the
+// security key when it is not empty.
+func envVars(hostname, key string) map[string]string {
+	values := map[string]string{"DB_USER": "nitro", "DB_PASSWORD": "nitro"}
+	if key != "" {
+		values["SECURITY_KEY"] = key
+	}
+
+	vars := craftVars(values)
+	vars["PRIMARY_SITE_URL"] = "https://" + hostname
+	vars["DEFAULT_SITE_URL"] = "https://" + hostname
+
+	return vars
+}
+
+// dbEnvVars returns the env values for the database that was created for the site.
+func dbEnvVars(host, name, port, driver string) map[string]string {
+	return craftVars(map[string]string{"DB_SERVER": host, "DB_DATABASE": name, "DB_PORT": port, "DB_DRIVER": driver})
+}
+
+// craftVars returns the values with the names used by Craft 3 and Craft 4 (prefixed with
+// CRAFT_), only the names that are in the env file are updated.
+func craftVars(values map[string]string) map[string]string {
+	vars := make(map[string]string)
+	for k, v := range values {
+		vars[k] = v
+		vars["CRAFT_"+k] = v
+	}
+
+	return vars
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	_, err = io.Copy(out, in)
+
+	return err
+}
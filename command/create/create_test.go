@@ -0,0 +1,39 @@
+package create
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/craftcms/nitro/pkg/envedit"
+)
+
+func TestEnvVars(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, ".env")
+
+	// craft 4 prefixes the names with CRAFT_, the names that are not in the file are not added
+	env := "CRAFT_ENVIRONMENT=dev\nCRAFT_SECURITY_KEY=\nCRAFT_DB_SERVER=127.0.0.1\nCRAFT_DB_USER=root\nPRIMARY_SITE_URL=\n"
+	if err := ioutil.WriteFile(file, []byte(env), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	vars := envVars("craft.nitro", "the-key")
+	for k, v := range dbEnvVars("mysql-8.0-3306.database.nitro", "craft", "3306", "mysql") {
+		vars[k] = v
+	}
+
+	got, err := envedit.Edit(file, vars)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "CRAFT_ENVIRONMENT=dev\nCRAFT_SECURITY_KEY=the-key\nCRAFT_DB_SERVER=mysql-8.0-3306.database.nitro\nCRAFT_DB_USER=nitro\nPRIMARY_SITE_URL=https://craft.nitro\n"
+	if got != want {
+		t.Errorf("expected the env to be updated, got:\n%s\nwant:\n%s", got, want)
+	}
+
+	if _, ok := envVars("craft.nitro", "")["SECURITY_KEY"]; ok {
+		t.Errorf("expected the existing security key to be kept")
+	}
+}
@@ -2,7 +2,6 @@ package database
 
 import (
 	"fmt"
-	"os"
 	"sort"
 	"strings"
 
@@ -10,19 +9,24 @@ import (
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/client"
 	"github.com/spf13/cobra"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
 
+	"github.com/craftcms/nitro/pkg/clipboard"
+	"github.com/craftcms/nitro/pkg/containerexec"
 	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/database"
+	"github.com/craftcms/nitro/pkg/environment"
+	"github.com/craftcms/nitro/pkg/prompt"
 	"github.com/craftcms/nitro/pkg/terminal"
 	"github.com/craftcms/nitro/pkg/validate"
-	"github.com/craftcms/nitro/protob"
 )
 
 var addExampleTest = `  # add a new database
-  nitro db add`
+  nitro db add
 
-func addCommand(docker client.CommonAPIClient, nitrod protob.NitroClient, output terminal.Outputer) *cobra.Command {
+  # add a new database and copy the connection URL to the clipboard
+  nitro db add --copy`
+
+func addCommand(docker client.CommonAPIClient, output terminal.Outputer) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "add",
 		Short:   "Adds a new database.",
@@ -30,7 +34,7 @@ func addCommand(docker client.CommonAPIClient, nitrod protob.NitroClient, output
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// add filters to show only the environment and database containers
 			filter := filters.NewArgs()
-			filter.Add("label", containerlabels.Nitro)
+			filter.Add("label", containerlabels.Nitro+"="+environment.Label())
 			filter.Add("label", containerlabels.Type+"=database")
 
 			// get a list of all the databases
@@ -44,14 +48,8 @@ func addCommand(docker client.CommonAPIClient, nitrod protob.NitroClient, output
 				return containers[i].Names[0] < containers[j].Names[0]
 			})
 
-			// get all of the containers as a list
-			var engineOpts []string
-			for _, c := range containers {
-				engineOpts = append(engineOpts, strings.TrimLeft(c.Names[0], "/"))
-			}
-
 			// prompt the user for the engine to add the database
-			selected, err := output.Select(os.Stdin, "Select the database engine: ", engineOpts)
+			selected, err := prompt.SelectContainer(cmd.InOrStdin(), "Select the database engine: ", containers, output)
 			if err != nil {
 				return err
 			}
@@ -68,77 +66,58 @@ func addCommand(docker client.CommonAPIClient, nitrod protob.NitroClient, output
 				return err
 			}
 
-			output.Pending("creating database", db)
-
-			// wait for the api to be ready
-			for {
-				_, err := nitrod.Ping(cmd.Context(), &protob.PingRequest{})
-				if err == nil {
-					break
-				}
-			}
-
 			// get the containers details
-			engine := info.Config.Labels[containerlabels.DatabaseCompatibility]
-			hostname := strings.TrimLeft(info.Name, "/")
+			compatibility := info.Config.Labels[containerlabels.DatabaseCompatibility]
+			engine := info.Config.Labels[containerlabels.DatabaseEngine]
 			version := info.Config.Labels[containerlabels.DatabaseVersion]
-			var port string
-			// get the port from the container info
-			for p, bind := range info.HostConfig.PortBindings {
+			hostname := strings.TrimLeft(info.Name, "/")
+
+			// get the port on the host from the container info
+			var hostPort string
+			for _, bind := range info.HostConfig.PortBindings {
 				for _, v := range bind {
 					if v.HostPort != "" {
-						port = p.Port()
+						hostPort = v.HostPort
 					}
 				}
 			}
 
-			// create the database
-			resp, err := nitrod.AddDatabase(cmd.Context(), &protob.AddDatabaseRequest{
-				Database: &protob.DatabaseInfo{
-					Engine:   engine,
-					Hostname: hostname,
-					Version:  version,
-					Port:     port,
-					Database: db,
-				},
-			})
-			// check if the error code is unimplemented
-			if code := status.Code(err); code == codes.Unimplemented {
-				output.Warning()
+			output.Pending("creating database", db)
 
-				// ask if the update command should run
-				confirm, err := output.Confirm("The API does not appear to be updated. Run `nitro update` now?", true, "")
-				if err != nil {
-					return err
+			// create the database and grant the nitro user access
+			for _, c := range database.CreateCommands(engine, version, db) {
+				if _, err := containerexec.Run(cmd.Context(), docker, info.ID, c); err != nil {
+					output.Warning()
+					return fmt.Errorf("unable to create the database, %w", err)
 				}
+			}
 
-				if !confirm {
-					output.Info("Skipping the update command; you need to update before using this command.")
+			output.Done()
 
-					return nil
-				}
+			output.Info(fmt.Sprintf("Database %q added to %q successfully 💪", db, hostname))
 
-				// run the update command
-				for _, c := range cmd.Parent().Commands() {
-					// set the update command
-					if c.Use == "update" {
-						if err := c.RunE(c, args); err != nil {
-							return err
-						}
-					}
-				}
-			}
-			if err != nil {
-				return err
-			}
+			// show the credentials for connecting from the host
+			url := database.ConnectionURL(compatibility, hostPort, db)
+			output.Info("Host: 127.0.0.1")
+			output.Info("Port:", hostPort)
+			output.Info("Database:", db)
+			output.Info("Username: nitro")
+			output.Info("Password: nitro")
+			output.Info("URL:", terminal.Hyperlink(url, url))
 
-			output.Done()
+			if copyURL, _ := cmd.Flags().GetBool("copy"); copyURL {
+				if err := clipboard.Copy(url); err != nil {
+					return err
+				}
 
-			output.Info(fmt.Sprintf("%s 💪", resp.Message))
+				output.Info("Copied the connection URL to the clipboard.")
+			}
 
 			return nil
 		},
 	}
 
+	cmd.Flags().Bool("copy", false, "copy the connection URL to the clipboard")
+
 	return cmd
 }
@@ -16,12 +16,20 @@ import (
 	"github.com/craftcms/nitro/pkg/backup"
 	"github.com/craftcms/nitro/pkg/config"
 	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/database"
 	"github.com/craftcms/nitro/pkg/datetime"
+	"github.com/craftcms/nitro/pkg/environment"
 	"github.com/craftcms/nitro/pkg/terminal"
 )
 
 var backupExampleText = `  # backup a database
-  nitro db backup`
+  nitro db backup
+
+  # backup a postgres database using the custom format for pg_restore
+  nitro db backup --format custom
+
+  # backup every database
+  nitro db backup --all`
 
 // backupCommand is the command for backing up an individual database or
 func backupCommand(home string, docker client.CommonAPIClient, output terminal.Outputer) *cobra.Command {
@@ -32,9 +40,16 @@ func backupCommand(home string, docker client.CommonAPIClient, output terminal.O
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
 
+			format := cmd.Flag("format").Value.String()
+			switch format {
+			case "plain", "custom":
+			default:
+				return fmt.Errorf("unknown backup format %q, must be plain or custom", format)
+			}
+
 			// add filters to show only the environment and database containers
 			filter := filters.NewArgs()
-			filter.Add("label", containerlabels.Nitro)
+			filter.Add("label", containerlabels.Nitro+"="+environment.Label())
 			filter.Add("label", containerlabels.Type+"=database")
 
 			// get a list of all the databases
@@ -54,49 +69,101 @@ func backupCommand(home string, docker client.CommonAPIClient, output terminal.O
 				containerList = append(containerList, strings.TrimLeft(c.Names[0], "/"))
 			}
 
+			// backup every database in every container
+			if cmd.Flag("all").Value.String() == "true" {
+				output.Info("Backing up all databases…")
+
+				for _, c := range containers {
+					name := strings.TrimLeft(c.Names[0], "/")
+
+					databases, err := backup.Databases(ctx, docker, c.ID, c.Labels[containerlabels.DatabaseCompatibility])
+					if err != nil {
+						output.Info("unable to get the databases from", name, err.Error())
+
+						continue
+					}
+
+					for _, db := range databases {
+						if err := perform(cmd, docker, output, home, c, db, format); err != nil {
+							return err
+						}
+					}
+				}
+
+				output.Info("Backups saved in", filepath.Join(home, config.DirectoryName, "backups"), "💾")
+
+				return nil
+			}
+
 			output.Info("Getting ready to backup…")
 
 			// get the container id, name, and database from the user
-			containerID, containerName, compatibility, db, err := backup.Prompt(ctx, os.Stdin, docker, output, containers, containerList)
+			containerID, _, _, db, err := backup.Prompt(ctx, os.Stdin, docker, output, containers, containerList)
 			if err != nil {
 				return err
 			}
 
-			output.Info("Preparing backup…")
+			for _, c := range containers {
+				if c.ID != containerID {
+					continue
+				}
 
-			// create the options for the backup
-			opts := &backup.Options{
-				BackupName:    fmt.Sprintf("%s-%s.sql", db, datetime.Parse(time.Now())),
-				ContainerID:   containerID,
-				ContainerName: containerName,
-				Database:      db,
-				Home:          home,
-			}
+				output.Info("Preparing backup…")
 
-			// create the backup command based on the compatibility type
-			switch compatibility {
-			case "postgres":
-				opts.Commands = []string{"pg_dump", "--username=nitro", db, "-f", "/tmp/" + opts.BackupName}
-			default:
-				opts.Commands = []string{"mysqldump", "--user=nitro", "-pnitro", db, "--result-file=" + "/tmp/" + opts.BackupName}
+				if err := perform(cmd, docker, output, home, c, db, format); err != nil {
+					return err
+				}
+
+				output.Info("Backup saved in", filepath.Join(home, config.DirectoryName, "backups", strings.TrimLeft(c.Names[0], "/")), "💾")
 			}
 
-			output.Pending("creating backup", opts.BackupName)
+			return nil
+		},
+	}
 
-			// perform the backup
-			if err := backup.Perform(ctx, docker, opts); err != nil {
-				output.Warning()
+	cmd.Flags().String("format", "plain", "the backup format for postgres databases (plain or custom)")
+	cmd.Flags().Bool("all", false, "backup every database without prompting")
 
-				return fmt.Errorf("unable to backup the database, %w", err)
-			}
+	return cmd
+}
 
-			output.Done()
+// perform creates the backup for a single database in the container using the format.
+func perform(cmd *cobra.Command, docker client.CommonAPIClient, output terminal.Outputer, home string, c types.Container, db, format string) error {
+	// create the options for the backup
+	opts := &backup.Options{
+		BackupName:    fmt.Sprintf("%s-%s.sql", db, datetime.Parse(time.Now())),
+		ContainerID:   c.ID,
+		ContainerName: strings.TrimLeft(c.Names[0], "/"),
+		Database:      db,
+		Home:          home,
+	}
 
-			output.Info("Backup saved in", filepath.Join(opts.Home, config.DirectoryName, "backups", opts.ContainerName), "💾")
+	// create the backup command based on the compatibility type
+	switch c.Labels[containerlabels.DatabaseCompatibility] {
+	case "postgres":
+		if format == "custom" {
+			opts.BackupName = strings.TrimSuffix(opts.BackupName, ".sql") + ".dump"
+			opts.Commands = []string{"pg_dump", "--username=nitro", "-Fc", db, "-f", "/tmp/" + opts.BackupName}
+		} else {
+			opts.Commands = []string{"pg_dump", "--username=nitro", db, "-f", "/tmp/" + opts.BackupName}
+		}
+	default:
+		// use the dump tool that matches the engine and version
+		tool := database.DumpCommand(c.Labels[containerlabels.DatabaseEngine], c.Labels[containerlabels.DatabaseVersion])
+
+		opts.Commands = []string{tool, "--user=nitro", "-pnitro", db, "--result-file=" + "/tmp/" + opts.BackupName}
+	}
 
-			return nil
-		},
+	output.Pending("creating backup", opts.BackupName)
+
+	// perform the backup
+	if err := backup.Perform(cmd.Context(), docker, opts); err != nil {
+		output.Warning()
+
+		return fmt.Errorf("unable to backup the database, %w", err)
 	}
 
-	return cmd
+	output.Done()
+
+	return nil
 }
@@ -0,0 +1,156 @@
+package database
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+	"github.com/spf13/cobra"
+
+	"github.com/craftcms/nitro/pkg/backup"
+	"github.com/craftcms/nitro/pkg/containerexec"
+	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/database"
+	"github.com/craftcms/nitro/pkg/environment"
+	"github.com/craftcms/nitro/pkg/prompt"
+	"github.com/craftcms/nitro/pkg/terminal"
+)
+
+var compareExampleText = `  # compare the schema of two databases
+  nitro db compare
+
+  # compare a database with a copy of production
+  nitro db compare craft craft_production
+
+  # exit with an error when the schemas are different
+  nitro db compare craft craft_production --exit-code`
+
+// ErrSchemasDiffer is returned by compare with the exit code flag when the schemas are different
+var ErrSchemasDiffer = fmt.Errorf("the database schemas are different")
+
+// compareCommand is the command for comparing the tables and columns of two databases in the
+// same database container. The schemas are read from the information schema, so the data
+// in the tables is not compared.
+func compareCommand(docker client.CommonAPIClient, output terminal.Outputer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "compare [BASE] [TARGET]",
+		Short:   "Compares the schema of two databases.",
+		Args:    cobra.MaximumNArgs(2),
+		Example: compareExampleText,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			// add filters to show only the environment and database containers
+			filter := filters.NewArgs()
+			filter.Add("label", containerlabels.Nitro+"="+environment.Label())
+			filter.Add("label", containerlabels.Type+"=database")
+
+			// get a list of all the databases
+			containers, err := docker.ContainerList(ctx, types.ContainerListOptions{Filters: filter})
+			if err != nil {
+				return err
+			}
+
+			// sort containers by the name
+			sort.SliceStable(containers, func(i, j int) bool {
+				return containers[i].Names[0] < containers[j].Names[0]
+			})
+
+			selected, err := prompt.SelectContainer(cmd.InOrStdin(), "Select a database engine:", containers, output)
+			if err != nil {
+				return err
+			}
+
+			container := containers[selected]
+			engine := container.Labels[containerlabels.DatabaseEngine]
+			version := container.Labels[containerlabels.DatabaseVersion]
+			compatibility := container.Labels[containerlabels.DatabaseCompatibility]
+
+			databases, err := backup.Databases(ctx, docker, container.ID, compatibility)
+			if err != nil {
+				return err
+			}
+
+			// prompt for the databases that were not provided
+			names := make([]string, 2)
+			copy(names, args)
+			for i, msg := range []string{"Select the base database:", "Select the database to compare:"} {
+				if names[i] != "" {
+					continue
+				}
+
+				if len(databases) == 0 {
+					return fmt.Errorf("there are no databases in the container")
+				}
+
+				n, err := prompt.Select(cmd.InOrStdin(), msg, databases, output)
+				if err != nil {
+					return err
+				}
+
+				names[i] = databases[n]
+			}
+
+			schemas := make([]database.Schema, 2)
+			for i, db := range names {
+				out, err := containerexec.Run(ctx, docker, container.ID, database.SchemaCommand(engine, version, db))
+				if err != nil {
+					return fmt.Errorf("unable to read the schema of %s, %w", db, err)
+				}
+
+				schemas[i] = database.ParseSchema(out)
+			}
+
+			changes := database.CompareSchemas(schemas[0], schemas[1])
+			if len(changes) == 0 {
+				output.Info(fmt.Sprintf("The schemas of %s and %s are the same", names[0], names[1]))
+
+				return nil
+			}
+
+			output.Info(fmt.Sprintf("Comparing %s to %s…\n", names[0], names[1]))
+
+			for _, c := range changes {
+				fmt.Fprintln(cmd.OutOrStdout(), formatChange(c))
+			}
+
+			if cmd.Flag("exit-code").Value.String() == "true" {
+				return ErrSchemasDiffer
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().Bool("exit-code", false, "return an error when the schemas are different")
+
+	return cmd
+}
+
+// formatChange returns the change as a colored line prefixed with + for additions, - for
+// removals, and ~ for changed columns.
+func formatChange(c database.SchemaChange) string {
+	name := c.Table
+	if c.Column != "" {
+		name = c.Table + "." + c.Column
+	}
+
+	switch c.Action {
+	case database.SchemaAdded:
+		if c.Column == "" {
+			return terminal.Color(terminal.Green, fmt.Sprintf("+ table %s", name))
+		}
+
+		return terminal.Color(terminal.Green, fmt.Sprintf("+ %s %s", name, c.After))
+	case database.SchemaRemoved:
+		if c.Column == "" {
+			return terminal.Color(terminal.Red, fmt.Sprintf("- table %s", name))
+		}
+
+		return terminal.Color(terminal.Red, fmt.Sprintf("- %s %s", name, c.Before))
+	default:
+		return terminal.Color(terminal.Yellow, fmt.Sprintf("~ %s %s -> %s", name, c.Before, c.After))
+	}
+}
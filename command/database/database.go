@@ -14,8 +14,17 @@ const exampleText = `  # import a database from a backup
   # backup a database
   nitro db backup
 
+  # export a database as a compressed sql file
+  nitro db export
+
   # add a new database
-  nitro db add`
+  nitro db add
+
+  # compare the schema of two databases
+  nitro db compare
+
+  # reset and seed the database of a site
+  nitro db reset`
 
 // NewCommand returns the db commands for importing, backing up, and adding databases
 func NewCommand(home string, docker client.CommonAPIClient, nitrod protob.NitroClient, output terminal.Outputer) *cobra.Command {
@@ -30,13 +39,16 @@ func NewCommand(home string, docker client.CommonAPIClient, nitrod protob.NitroC
 	}
 
 	cmd.AddCommand(
-		importCommand(home, docker, nitrod, output),
+		importCommand(home, docker, output),
 		backupCommand(home, docker, output),
-		addCommand(docker, nitrod, output),
+		exportCommand(home, docker, output),
+		addCommand(docker, output),
 		sshCommand(home, docker, output),
-		removeCommand(docker, nitrod, output),
+		removeCommand(docker, output),
 		newCommand(home, docker, output),
 		destroyCommand(home, docker, output),
+		compareCommand(docker, output),
+		resetCommand(home, docker, output),
 	)
 
 	return cmd
@@ -1,6 +1,8 @@
 package database
 
 import (
+	"fmt"
+
 	"github.com/docker/docker/client"
 	"github.com/spf13/cobra"
 
@@ -14,7 +16,10 @@ func destroyCommand(home string, docker client.CommonAPIClient, output terminal.
 		Use:   "destroy",
 		Short: "Destroys a database engine.",
 		Example: `  # remove a database engine from the config
-  nitro db destroy`,
+  nitro db destroy
+
+  # remove a database engine that is marked as protected
+  nitro db destroy --include-protected`,
 		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 			cfg, err := config.Load(home)
 			if err != nil {
@@ -50,7 +55,7 @@ func destroyCommand(home string, docker client.CommonAPIClient, output terminal.
 			}
 
 			// prompt for the database
-			selected, err := output.Select(cmd.InOrStdin(), "Select database to destroy: ", options)
+			selected, err := prompt.Select(cmd.InOrStdin(), "Select database to destroy: ", options, output)
 			if err != nil {
 				return err
 			}
@@ -58,6 +63,11 @@ func destroyCommand(home string, docker client.CommonAPIClient, output terminal.
 			db := dbs[selected]
 			hostname, _ := db.GetHostname()
 
+			// protected databases require an extra flag
+			if db.Protected && cmd.Flag("include-protected").Value.String() != "true" {
+				return fmt.Errorf("the database %s is protected, use --include-protected to destroy it", hostname)
+			}
+
 			output.Info("Removing", hostname)
 
 			// remove the engine
@@ -74,5 +84,7 @@ func destroyCommand(home string, docker client.CommonAPIClient, output terminal.
 		},
 	}
 
+	cmd.Flags().Bool("include-protected", false, "allow destroying a protected database engine")
+
 	return cmd
 }
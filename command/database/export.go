@@ -0,0 +1,134 @@
+package database
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+	"github.com/spf13/cobra"
+
+	"github.com/craftcms/nitro/pkg/backup"
+	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/database"
+	"github.com/craftcms/nitro/pkg/datetime"
+	"github.com/craftcms/nitro/pkg/environment"
+	"github.com/craftcms/nitro/pkg/terminal"
+)
+
+var exportExampleText = `  # export a database as a compressed sql file
+  nitro db export
+
+  # export a database into a specific directory
+  nitro db export --dir ~/backups`
+
+// exportCommand is the command for exporting a database into a gzip compressed file. The
+// dump is streamed from the container so the file is never created in the container.
+func exportCommand(home string, docker client.CommonAPIClient, output terminal.Outputer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "export",
+		Short:   "Exports a database.",
+		Example: exportExampleText,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			// add filters to show only the environment and database containers
+			filter := filters.NewArgs()
+			filter.Add("label", containerlabels.Nitro+"="+environment.Label())
+			filter.Add("label", containerlabels.Type+"=database")
+
+			// get a list of all the databases
+			containers, err := docker.ContainerList(ctx, types.ContainerListOptions{Filters: filter})
+			if err != nil {
+				return err
+			}
+
+			if len(containers) == 0 {
+				return fmt.Errorf("there are no running database containers")
+			}
+
+			// sort containers by the name
+			sort.SliceStable(containers, func(i, j int) bool {
+				return containers[i].Names[0] < containers[j].Names[0]
+			})
+
+			// generate a list of engines for the prompt
+			var containerList []string
+			for _, c := range containers {
+				containerList = append(containerList, strings.TrimLeft(c.Names[0], "/"))
+			}
+
+			// get the container id, name, and database from the user
+			containerID, name, compatibility, db, err := backup.Prompt(ctx, cmd.InOrStdin(), docker, output, containers, containerList)
+			if err != nil {
+				return err
+			}
+
+			name = strings.TrimLeft(name, "/")
+
+			// create the dump command based on the compatibility type
+			var commands []string
+			switch compatibility {
+			case "postgres":
+				commands = []string{"pg_dump", "--username=nitro", db}
+			default:
+				var engine, version string
+				for _, c := range containers {
+					if c.ID == containerID {
+						engine = c.Labels[containerlabels.DatabaseEngine]
+						version = c.Labels[containerlabels.DatabaseVersion]
+					}
+				}
+
+				// use the dump tool that matches the engine and version
+				commands = []string{database.DumpCommand(engine, version), "--user=nitro", "-pnitro", "--single-transaction", db}
+			}
+
+			// default to the backups directory for the container
+			dir := cmd.Flag("dir").Value.String()
+			if dir == "" {
+				dir = filepath.Join(home, config.DirectoryName, "backups", name)
+			}
+
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				return err
+			}
+
+			file := filepath.Join(dir, fmt.Sprintf("%s-%s.sql.gz", db, datetime.Parse(time.Now())))
+
+			f, err := os.Create(file)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+
+			output.Pending("exporting", db)
+
+			if err := backup.Export(ctx, docker, containerID, commands, f); err != nil {
+				output.Warning()
+
+				// remove the partial export
+				f.Close()
+				os.Remove(file)
+
+				return fmt.Errorf("unable to export the database, %w", err)
+			}
+
+			output.Done()
+
+			output.Info("Export saved as", file, "💾")
+
+			return nil
+		},
+	}
+
+	cmd.Flags().String("dir", "", "the directory to save the export in (defaults to the backups directory)")
+
+	return cmd
+}
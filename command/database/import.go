@@ -1,11 +1,14 @@
 package database
 
 import (
-	"bufio"
+	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
+	"path/filepath"
 	"sort"
 	"strings"
 	"time"
@@ -14,16 +17,18 @@ import (
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/client"
 	"github.com/spf13/cobra"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
 
+	"github.com/craftcms/nitro/command/internal/databasecontainer"
+	"github.com/craftcms/nitro/pkg/containerexec"
 	"github.com/craftcms/nitro/pkg/containerlabels"
 	"github.com/craftcms/nitro/pkg/database"
+	"github.com/craftcms/nitro/pkg/environment"
 	"github.com/craftcms/nitro/pkg/filetype"
+	"github.com/craftcms/nitro/pkg/jobs"
 	"github.com/craftcms/nitro/pkg/pathexists"
+	"github.com/craftcms/nitro/pkg/prompt"
 	"github.com/craftcms/nitro/pkg/terminal"
 	"github.com/craftcms/nitro/pkg/validate"
-	"github.com/craftcms/nitro/protob"
 )
 
 var importExampleText = `  # import a sql file into a database
@@ -33,12 +38,15 @@ var importExampleText = `  # import a sql file into a database
   nitro db import ~/Desktop/backup.sql
 
   # use an absolute path
-  nitro db import /Users/oli/Desktop/backup.sql`
+  nitro db import /Users/oli/Desktop/backup.sql
+
+  # import in the background and check the progress with nitro jobs
+  nitro db import backup.sql --background`
 
 var nameFlag string
 
 // importCommand is the command for creating new development environments
-func importCommand(home string, docker client.CommonAPIClient, nitrod protob.NitroClient, output terminal.Outputer) *cobra.Command {
+func importCommand(home string, docker client.CommonAPIClient, output terminal.Outputer) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "import",
 		Short: "Imports a database dump.",
@@ -72,18 +80,25 @@ func importCommand(home string, docker client.CommonAPIClient, nitrod protob.Nit
 				path = strings.Replace(path, "~", home, 1)
 			}
 
-			// check if this is a zip file
-			var compressed bool
-			kind, err := filetype.Determine(path)
+			// postgres custom format backups are binary, so check for them first
+			custom, err := database.IsPostgresCustomFormat(path)
 			if err != nil {
 				return err
 			}
 
-			var compressionType string
+			// check if this is a zip file
+			var compressed bool
+			kind := "custom"
+			if !custom {
+				kind, err = filetype.Determine(path)
+				if err != nil {
+					return err
+				}
+			}
+
 			switch kind {
 			case "zip", "tar":
 				compressed = true
-				compressionType = kind
 			}
 
 			// detect the type of backup if not compressed
@@ -106,7 +121,7 @@ func importCommand(home string, docker client.CommonAPIClient, nitrod protob.Nit
 
 			// add filters to show only the environment and database containers
 			filter := filters.NewArgs()
-			filter.Add("label", containerlabels.Nitro)
+			filter.Add("label", containerlabels.Nitro+"="+environment.Label())
 			filter.Add("label", containerlabels.Type+"=database")
 
 			// if we detected the engine type, add the compatibility label to the filter
@@ -146,7 +161,7 @@ func importCommand(home string, docker client.CommonAPIClient, nitrod protob.Nit
 
 			// prompt the user for the engine to import the backup into
 			var containerID string
-			selected, err := output.Select(os.Stdin, "Select a database engine: ", options)
+			selected, err := prompt.Select(os.Stdin, "Select a database engine: ", options, output)
 			if err != nil {
 				return err
 			}
@@ -195,152 +210,133 @@ func importCommand(home string, docker client.CommonAPIClient, nitrod protob.Nit
 				return err
 			}
 
-			// get the database compatability from the container labelsmake l
-			detected = info.Config.Labels[containerlabels.DatabaseCompatibility]
-			hostname := strings.TrimLeft(info.Name, "/")
-			version := info.Config.Labels[containerlabels.DatabaseVersion]
-
-			var port string
-			// get the port from the container info
-			for p, bind := range info.HostConfig.PortBindings {
-				for _, v := range bind {
-					if v.HostPort != "" {
-						port = p.Port()
-					}
-				}
+			parallel, _ := cmd.Flags().GetInt("jobs")
+
+			// run the import in the database container without blocking the terminal
+			if background, _ := cmd.Flags().GetBool("background"); background {
+				return importInBackground(cmd.Context(), docker, home, info, path, db, custom, parallel, output)
 			}
 
-			stream, err := nitrod.ImportDatabase(cmd.Context())
-			// check if the error code is unimplemented
-			if code := status.Code(err); code == codes.Unimplemented {
-				output.Warning()
+			return importStream(cmd.Context(), docker, info, path, db, custom, parallel, output)
+		},
+	}
 
-				// ask if the update command should run
-				confirm, err := output.Confirm("The API does not appear to be updated. Run `nitro update` now?", true, "")
-				if err != nil {
-					return err
-				}
+	cmd.Flags().StringVar(&nameFlag, "name", "", "The database name to import into")
+	cmd.Flags().Bool("background", false, "import in the background, use `nitro jobs` to check the progress")
+	cmd.Flags().Int("jobs", 0, "the number of parallel jobs to restore postgres custom format backups (defaults to the number of CPUs)")
 
-				if !confirm {
-					output.Info("Skipping the update command; you need to update before using this command.")
+	return cmd
+}
 
-					return nil
-				}
+// importStream streams the backup into the client of the database engine in the container.
+// Compressed backups are decompressed while they are streamed, so the backup is not copied
+// into the container first and large backups do not use more memory or disk space. Postgres
+// custom format backups are copied into the container and restored with parallel jobs.
+func importStream(ctx context.Context, docker client.ContainerAPIClient, info types.ContainerJSON, path, db string, custom bool, parallel int, output terminal.Outputer) error {
+	hostname := strings.TrimLeft(info.Name, "/")
+	engine := info.Config.Labels[containerlabels.DatabaseEngine]
+	version := info.Config.Labels[containerlabels.DatabaseVersion]
+
+	if custom && engine != "postgres" {
+		return fmt.Errorf("%s is a postgres custom format backup and can only be imported into a postgres database", filepath.Base(path))
+	}
 
-				// run the update command
-				for _, c := range cmd.Parent().Commands() {
-					// set the update command
-					if c.Use == "update" {
-						if err := c.RunE(c, args); err != nil {
-							return err
-						}
-					}
-				}
-			}
-			if err != nil {
-				return err
-			}
+	start := time.Now()
 
-			// create a request with the database information to populate the database info for the import
-			err = stream.Send(&protob.ImportDatabaseRequest{
-				Payload: &protob.ImportDatabaseRequest_Database{
-					Database: &protob.DatabaseInfo{
-						Compressed:      compressed,
-						CompressionType: compressionType,
-						Database:        db,
-						Engine:          detected,
-						Hostname:        hostname,
-						Port:            port,
-						Version:         version,
-					},
-				},
-			})
-			// check if the error code is unimplemented
-			if code := status.Code(err); code == codes.Unimplemented {
-				output.Warning()
+	output.Pending(fmt.Sprintf("importing database %q into %q", db, hostname))
 
-				// ask if the update command should run
-				confirm, err := output.Confirm("The API does not appear to be updated. Run `nitro update` now?", true, "")
-				if err != nil {
-					return err
-				}
+	// do not exit on error with the create commands - the error could be that the database already exists
+	for _, c := range database.CreateCommands(engine, version, db) {
+		_, _ = containerexec.Run(ctx, docker, info.ID, c)
+	}
 
-				if !confirm {
-					output.Info("Skipping the update command; you need to update before using this command.")
+	if custom {
+		if err := databasecontainer.Restore(ctx, docker, info.ID, path, db, parallel); err != nil {
+			output.Warning()
+			return err
+		}
 
-					return nil
-				}
+		output.Done()
 
-				// run the update command
-				for _, c := range cmd.Parent().Commands() {
-					// set the update command
-					if c.Use == "update" {
-						if err := c.RunE(c, args); err != nil {
-							return err
-						}
-					}
-				}
-			}
-			if err != nil {
-				return stream.RecvMsg(nil)
-			}
+		output.Info(fmt.Sprintf("Imported %s into %s in %.2f seconds 💪", filepath.Base(path), db, time.Since(start).Seconds()))
 
-			// create a timer
-			start := time.Now()
+		return nil
+	}
 
-			// open the file
-			file, err := os.Open(path)
-			if err != nil {
-				return err
-			}
+	backup, err := database.Open(path)
+	if err != nil {
+		output.Warning()
+		return err
+	}
+	defer backup.Close()
 
-			// create a buffer to handle large files more gracefully
-			buffer := make([]byte, 1024*20)
-			reader := bufio.NewReader(file)
+	stderr := &bytes.Buffer{}
+	code, err := containerexec.Interactive(ctx, docker, info.ID, "", database.StdinImportCommand(engine, version, db), backup, ioutil.Discard, stderr)
+	if err != nil {
+		output.Warning()
+		return fmt.Errorf("unable to import the backup, %w", err)
+	}
 
-			output.Pending(fmt.Sprintf("importing database %q into %q", db, hostname))
+	if code != 0 {
+		output.Warning()
+		return fmt.Errorf("unable to import the backup into %s, %s", db, strings.TrimSpace(stderr.String()))
+	}
 
-			// stream to backup file to the api
-			for {
-				n, err := reader.Read(buffer)
-				if err == io.EOF {
-					break
-				}
-				if err != nil {
-					output.Warning()
+	output.Done()
 
-					return stream.RecvMsg(nil)
-				}
+	output.Info(fmt.Sprintf("Imported %s into %s in %.2f seconds 💪", filepath.Base(path), db, time.Since(start).Seconds()))
 
-				// send the chunked file data in pieces
-				if err := stream.Send(&protob.ImportDatabaseRequest{
-					Payload: &protob.ImportDatabaseRequest_Data{
-						Data: buffer[:n],
-					},
-				}); err != nil {
-					output.Warning()
+	return nil
+}
 
-					return err
-				}
-			}
+// importInBackground copies the backup into the database container and starts a job that
+// imports the backup, so large imports do not block the terminal.
+func importInBackground(ctx context.Context, docker client.CommonAPIClient, home string, info types.ContainerJSON, path, db string, custom bool, parallel int, output terminal.Outputer) error {
+	hostname := strings.TrimLeft(info.Name, "/")
+
+	output.Pending("copying", filepath.Base(path), "to", hostname)
+
+	// decompress the backup and copy it into the container, custom format backups are copied as is
+	var (
+		rdr  io.Reader
+		name string
+		err  error
+	)
+	if custom {
+		name = filepath.Base(path)
+		rdr, err = database.Archive(path, name)
+	} else {
+		rdr, name, err = database.PrepareArchiveFromPath(path)
+	}
+	if err != nil {
+		output.Warning()
+		return err
+	}
 
-			// handle the response
-			reply, err := stream.CloseAndRecv()
-			if err != nil {
-				output.Warning()
+	if err := docker.CopyToContainer(ctx, info.ID, "/tmp", rdr, types.CopyToContainerOptions{}); err != nil {
+		output.Warning()
+		return fmt.Errorf("unable to copy the backup to the container, %w", err)
+	}
 
-				return stream.RecvMsg(nil)
-			}
+	output.Done()
 
-			output.Done()
+	job, err := jobs.New(info.ID, hostname, fmt.Sprintf("import %s into %s", filepath.Base(path), db))
+	if err != nil {
+		return err
+	}
 
-			output.Info(fmt.Sprintf("%s in %.2f seconds 💪", reply.Message, time.Since(start).Seconds()))
+	file := "/tmp/" + name
 
-			return nil
-		},
+	var cmds []string
+	for _, c := range database.ImportCommands(info.Config.Labels[containerlabels.DatabaseEngine], info.Config.Labels[containerlabels.DatabaseVersion], db, file, custom, parallel) {
+		cmds = append(cmds, jobs.Command(c...))
 	}
 
-	cmd.Flags().StringVar(&nameFlag, "name", "", "The database name to import into")
+	if err := jobs.Start(ctx, docker, home, job, cmds, jobs.Command("rm", "-f", file)); err != nil {
+		return err
+	}
 
-	return cmd
+	output.Info(fmt.Sprintf("Started job %s, run `nitro jobs` to check the progress or `nitro jobs logs %s` to see the output.", job.ID, job.ID))
+
+	return nil
 }
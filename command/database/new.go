@@ -7,6 +7,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/database"
 	"github.com/craftcms/nitro/pkg/portavail"
 	"github.com/craftcms/nitro/pkg/prompt"
 	"github.com/craftcms/nitro/pkg/terminal"
@@ -35,7 +36,7 @@ func newCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 
 			// define the options
 			var options []string
-			switch runtime.GOOS {
+			switch runtime.GOARCH {
 			case "arm64", "arm":
 				options = []string{"mariadb", "postgres"}
 			default:
@@ -43,7 +44,7 @@ func newCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 			}
 
 			// prompt for the engine
-			selection, err := output.Select(cmd.InOrStdin(), "Which database engine should we use?", options)
+			selection, err := prompt.Select(cmd.InOrStdin(), "Which database engine should we use?", options, output)
 			if err != nil {
 				return err
 			}
@@ -51,12 +52,15 @@ func newCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 			// get the engine
 			engine := options[selection]
 
-			// ask for the version
-			version, err := output.Ask("Which version should we use?", "", "", nil)
+			// prompt for the version
+			versions := database.Versions[engine]
+			selected, err := prompt.Select(cmd.InOrStdin(), "Which version should we use?", versions, output)
 			if err != nil {
 				return err
 			}
 
+			version := versions[selected]
+
 			// set the default port
 			var defaultPort string
 			switch engine {
@@ -79,11 +83,9 @@ func newCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 			}
 
 			// add the database to the config
-			cfg.Databases = append(cfg.Databases, config.Database{
-				Engine:  engine,
-				Version: version,
-				Port:    port,
-			})
+			if err := cfg.AddDatabase(config.Database{Engine: engine, Version: version, Port: port}); err != nil {
+				return err
+			}
 
 			// save the config
 			if err := cfg.Save(); err != nil {
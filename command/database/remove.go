@@ -9,19 +9,20 @@ import (
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/client"
 	"github.com/spf13/cobra"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
 
 	"github.com/craftcms/nitro/pkg/backup"
+	"github.com/craftcms/nitro/pkg/containerexec"
 	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/database"
+	"github.com/craftcms/nitro/pkg/environment"
+	"github.com/craftcms/nitro/pkg/prompt"
 	"github.com/craftcms/nitro/pkg/terminal"
-	"github.com/craftcms/nitro/protob"
 )
 
 var removeExampleText = `  # remove a database
   nitro db remove`
 
-func removeCommand(docker client.CommonAPIClient, nitrod protob.NitroClient, output terminal.Outputer) *cobra.Command {
+func removeCommand(docker client.CommonAPIClient, output terminal.Outputer) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "remove",
 		Short:   "Removes a database.",
@@ -30,7 +31,7 @@ func removeCommand(docker client.CommonAPIClient, nitrod protob.NitroClient, out
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// add filters to show only the environment and database containers
 			filter := filters.NewArgs()
-			filter.Add("label", containerlabels.Nitro)
+			filter.Add("label", containerlabels.Nitro+"="+environment.Label())
 			filter.Add("label", containerlabels.Type+"=database")
 
 			// get a list of all the databases
@@ -44,105 +45,51 @@ func removeCommand(docker client.CommonAPIClient, nitrod protob.NitroClient, out
 				return containers[i].Names[0] < containers[j].Names[0]
 			})
 
-			// generate a list of engines for the prompt
-			var containerList []string
-			for _, c := range containers {
-				containerList = append(containerList, strings.TrimLeft(c.Names[0], "/"))
-			}
-
-			// prompt the user for which database to backup
-			selectedEngine, err := output.Select(cmd.InOrStdin(), "Which database engine? ", containerList)
-			if err != nil {
-				return err
-			}
-
-			// get the containers info
-			info, err := docker.ContainerInspect(cmd.Context(), containers[selectedEngine].ID)
+			// prompt the user for the engine to remove the database from
+			selectedEngine, err := prompt.SelectContainer(cmd.InOrStdin(), "Which database engine? ", containers, output)
 			if err != nil {
 				return err
 			}
 
 			// get the containers details
-			engine := info.Config.Labels[containerlabels.DatabaseCompatibility]
-			hostname := strings.TrimLeft(info.Name, "/")
-			version := info.Config.Labels[containerlabels.DatabaseVersion]
-			var port string
-			// get the port from the container info
-			for p, bind := range info.HostConfig.PortBindings {
-				for _, v := range bind {
-					if v.HostPort != "" {
-						port = p.Port()
-					}
-				}
-			}
+			c := containers[selectedEngine]
+			compatibility := c.Labels[containerlabels.DatabaseCompatibility]
+			engine := c.Labels[containerlabels.DatabaseEngine]
+			version := c.Labels[containerlabels.DatabaseVersion]
+			hostname := strings.TrimLeft(c.Names[0], "/")
 
 			// get all of the databases
-			databases, err := backup.Databases(cmd.Context(), docker, info.ID, engine)
+			databases, err := backup.Databases(cmd.Context(), docker, c.ID, compatibility)
 			if err != nil {
 				return err
 			}
 
+			if len(databases) == 0 {
+				output.Info("There are no databases to remove from", hostname)
+				return nil
+			}
+
 			// ask the user which database
-			selected, err := output.Select(cmd.InOrStdin(), "Which database should we remove? ", databases)
+			selected, err := prompt.Select(cmd.InOrStdin(), "Which database should we remove? ", databases, output)
 			if err != nil {
 				return err
 			}
 
 			db := databases[selected]
 
-			// wait for the api to be ready
-			for {
-				_, err := nitrod.Ping(cmd.Context(), &protob.PingRequest{})
-				if err == nil {
-					break
-				}
-			}
-
 			output.Pending("removing", db)
 
 			// remove the database
-			resp, err := nitrod.RemoveDatabase(cmd.Context(), &protob.RemoveDatabaseRequest{
-				Database: &protob.DatabaseInfo{
-					Engine:   engine,
-					Hostname: hostname,
-					Version:  version,
-					Port:     port,
-					Database: db,
-				},
-			})
-			// check if the error code is unimplemented
-			if code := status.Code(err); code == codes.Unimplemented {
-				output.Warning()
-
-				// ask if the update command should run
-				confirm, err := output.Confirm("The API does not appear to be updated. Run `nitro update` now?", true, "")
-				if err != nil {
-					return err
-				}
-
-				if !confirm {
-					output.Info("Skipping the update command; you need to update before using this command.")
-
-					return nil
+			for _, command := range database.DropCommands(engine, version, db) {
+				if _, err := containerexec.Run(cmd.Context(), docker, c.ID, command); err != nil {
+					output.Warning()
+					return fmt.Errorf("unable to remove the database, %w", err)
 				}
-
-				// run the update command
-				for _, c := range cmd.Parent().Commands() {
-					// set the update command
-					if c.Use == "update" {
-						if err := c.RunE(c, args); err != nil {
-							return err
-						}
-					}
-				}
-			}
-			if err != nil {
-				return err
 			}
 
 			output.Done()
 
-			output.Info(fmt.Sprintf("%s 💪", resp.Message))
+			output.Info(fmt.Sprintf("Removed %q from %q successfully 💪", db, hostname))
 
 			return nil
 		},
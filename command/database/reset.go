@@ -0,0 +1,151 @@
+package database
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/docker/docker/client"
+	"github.com/spf13/cobra"
+
+	"github.com/craftcms/nitro/command/internal/databasecontainer"
+	"github.com/craftcms/nitro/command/internal/seed"
+	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/prompt"
+	"github.com/craftcms/nitro/pkg/terminal"
+)
+
+var resetExampleText = `  # remove all of the data in the database of the current site and seed it
+  nitro db reset
+
+  # reset the database of a site without confirming
+  nitro db reset tutorial.nitro --force`
+
+func resetCommand(home string, docker client.CommonAPIClient, output terminal.Outputer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "reset",
+		Short:   "Resets the database of a site.",
+		Example: resetExampleText,
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			cfg, err := config.Load(home)
+			if err != nil {
+				return nil, cobra.ShellCompDirectiveDefault
+			}
+
+			var options []string
+			for _, s := range cfg.Sites {
+				if s.Database != "" {
+					options = append(options, s.Hostname)
+				}
+			}
+
+			return options, cobra.ShellCompDirectiveDefault
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			// load the config
+			cfg, err := config.Load(home)
+			if err != nil {
+				return err
+			}
+
+			site, err := resetSite(cmd, home, cfg, args, output)
+			if err != nil {
+				return err
+			}
+
+			if site.Database == "" {
+				return fmt.Errorf("the site %s does not have a database, set the database of the site to reset it", site.Hostname)
+			}
+
+			db, err := cfg.SiteDatabase(*site)
+			if err != nil {
+				return err
+			}
+
+			hostname, _ := db.GetHostname()
+
+			// protected databases require an extra flag
+			if db.Protected && cmd.Flag("include-protected").Value.String() != "true" {
+				return fmt.Errorf("the database %s is protected, use --include-protected to reset %s", hostname, site.Database)
+			}
+
+			if force, _ := cmd.Flags().GetBool("force"); !force {
+				confirm, err := output.Confirm(fmt.Sprintf("Reset the database %s of %s? All of the data in the database will be removed", site.Database, site.Hostname), false, "")
+				if err != nil {
+					return err
+				}
+
+				if !confirm {
+					return nil
+				}
+			}
+
+			details, err := docker.ContainerInspect(ctx, hostname)
+			if err != nil {
+				return fmt.Errorf("unable to find the database container %s, %w", hostname, err)
+			}
+
+			if details.State == nil || !details.State.Running {
+				return fmt.Errorf("the database container %s is not running, start it with `nitro start` to reset the database %s", hostname, site.Database)
+			}
+
+			output.Pending("resetting database", site.Database)
+
+			if err := databasecontainer.Drop(ctx, docker, details.ID, *db, site.Database); err != nil {
+				output.Warning()
+				return err
+			}
+
+			if err := databasecontainer.Create(ctx, docker, details.ID, *db, site.Database); err != nil {
+				output.Warning()
+				return err
+			}
+
+			output.Done()
+
+			return seed.Run(ctx, docker, home, cfg, *site, output)
+		},
+	}
+
+	cmd.Flags().BoolP("force", "f", false, "reset the database without confirming")
+	cmd.Flags().Bool("include-protected", false, "allow resetting a database in a protected database engine")
+
+	return cmd
+}
+
+// resetSite returns the site from the argument, or the site in the current directory. The
+// user is prompted for the site when there are more sites in the current directory.
+func resetSite(cmd *cobra.Command, home string, cfg *config.Config, args []string, output terminal.Outputer) (*config.Site, error) {
+	if len(args) > 0 {
+		return cfg.FindSiteByHostName(strings.TrimSpace(args[0]))
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+
+	// get a context aware list of sites
+	sites := cfg.ListOfSitesByDirectory(home, wd)
+	if len(sites) == 0 {
+		return nil, fmt.Errorf("there are no sites in the config")
+	}
+
+	if len(sites) == 1 {
+		return &sites[0], nil
+	}
+
+	var options []string
+	for _, s := range sites {
+		options = append(options, s.Hostname)
+	}
+
+	selected, err := prompt.Select(cmd.InOrStdin(), "Select a site: ", options, output)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sites[selected], nil
+}
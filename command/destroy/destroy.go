@@ -11,13 +11,18 @@ import (
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/mount"
 	"github.com/docker/docker/client"
 	"github.com/spf13/cobra"
 
 	"github.com/craftcms/nitro/pkg/backup"
+	"github.com/craftcms/nitro/pkg/certinstall"
 	"github.com/craftcms/nitro/pkg/config"
 	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/database"
 	"github.com/craftcms/nitro/pkg/datetime"
+	"github.com/craftcms/nitro/pkg/environment"
+	"github.com/craftcms/nitro/pkg/pathexists"
 	"github.com/craftcms/nitro/pkg/sudo"
 	"github.com/craftcms/nitro/pkg/terminal"
 )
@@ -34,11 +39,19 @@ var (
 )
 
 const exampleText = `  # remove all resources (networks, containers, and volumes)
-  nitro destroy`
+  nitro destroy
+
+  # also remove volumes that are marked as protected in the config
+  nitro destroy --include-protected
+
+  # remove everything except the database volumes without a confirmation
+  nitro destroy --keep-data --force`
 
 // NewCommand is used to destroy all resources for an environment. It will prompt for
 // user verification and defaults to no. Part of the destroy process is to
-// perform a backup for all databases in each container database.
+// perform a backup for all databases in each container database, unless the
+// database volumes are kept. The root certificate is removed from the system
+// since the proxy creates a new one the next time it is created.
 func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outputer) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "destroy",
@@ -51,20 +64,29 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 				return err
 			}
 
+			keepData := cmd.Flag("keep-data").Value.String() == "true"
+
 			// prompt the user for confirmation
-			confirm, err := output.Confirm("Are you sure? (This will remove all containers, volumes, and networks.)", false, "")
-			if err != nil {
-				return err
-			}
+			if cmd.Flag("force").Value.String() != "true" {
+				msg := "Are you sure? (This will remove all containers, volumes, networks, and certificates.)"
+				if keepData {
+					msg = "Are you sure? (This will remove all containers, networks, certificates, and volumes except for databases.)"
+				}
+
+				confirm, err := output.Confirm(msg, false, "")
+				if err != nil {
+					return err
+				}
 
-			if !confirm {
-				output.Info("skipping destroy, all resources will remain 😅")
+				if !confirm {
+					output.Info("skipping destroy, all resources will remain 😅")
 
-				return nil
+					return nil
+				}
 			}
 
 			filter := filters.NewArgs()
-			filter.Add("label", containerlabels.Nitro)
+			filter.Add("label", containerlabels.Nitro+"="+environment.Label())
 
 			// get all related containers
 			containers, err := docker.ContainerList(ctx, types.ContainerListOptions{
@@ -86,6 +108,9 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 				return err
 			}
 
+			// get the volumes that should not be removed
+			protected := keep(cfg, containers, cmd.Flag("include-protected").Value.String() == "true", keepData)
+
 			// make sure there are volumes
 			if len(volumes.Volumes) == 0 {
 				output.Info(ErrNoVolumes.Error())
@@ -111,8 +136,8 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 				for _, c := range containers {
 					name := strings.TrimLeft(c.Names[0], "/")
 
-					// only perform a backup if the container is for databases
-					if c.Labels[containerlabels.DatabaseEngine] != "" {
+					// only perform a backup if the container is for databases and the data is removed
+					if c.Labels[containerlabels.DatabaseEngine] != "" && !keepData {
 						// this container needs to be running before we can backup the system
 						if c.State != "running" {
 							if err := docker.ContainerStart(ctx, c.ID, types.ContainerStartOptions{}); err != nil {
@@ -145,7 +170,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 							case "postgres":
 								opts.Commands = []string{"pg_dump", "--username=nitro", db, "-f", "/tmp/" + opts.BackupName}
 							default:
-								opts.Commands = []string{"/usr/bin/mysqldump", "-h", "127.0.0.1", "-unitro", "--password=nitro", db, "--result-file=" + "/tmp/" + opts.BackupName}
+								opts.Commands = []string{"/usr/bin/" + database.DumpCommand(c.Labels[containerlabels.DatabaseEngine], c.Labels[containerlabels.DatabaseVersion]), "-h", "127.0.0.1", "-unitro", "--password=nitro", db, "--result-file=" + "/tmp/" + opts.BackupName}
 							}
 
 							output.Pending("creating backup", opts.BackupName)
@@ -187,6 +212,12 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 				output.Info("Removing volumes…")
 
 				for _, v := range volumes.Volumes {
+					// skip volumes that are protected
+					if protected[v.Name] {
+						output.Info("  - keeping volume", v.Name)
+						continue
+					}
+
 					output.Pending("removing", v.Name)
 
 					// remove the volume
@@ -221,6 +252,23 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 				}
 			}
 
+			// remove the root certificate, the proxy volume with the certificate authority was removed
+			if cert := filepath.Join(home, config.DirectoryName, "nitro.crt"); pathexists.IsFile(cert) {
+				output.Info("Removing certificate (you might be prompted for your password)")
+
+				if err := certinstall.Uninstall(cert, runtime.GOOS); err != nil {
+					output.Info("Unable to remove the certificate,", err.Error())
+				}
+
+				if err := certinstall.UninstallNSS(certinstall.NSSDatabases(home, runtime.GOOS)); err != nil {
+					output.Info("Unable to remove the certificate from Firefox,", err.Error())
+				}
+
+				if err := os.Remove(cert); err != nil {
+					output.Info("Unable to remove", cert)
+				}
+			}
+
 			// remove nitro hosts entries
 
 			// get the executable
@@ -245,7 +293,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 				output.Info("Updating hosts file (you might be prompted for your password)")
 
 				// add the hosts
-				if err := sudo.Run(nitro, "nitro", "hosts", "remove"); err != nil {
+				if err := sudo.Run(nitro, append([]string{"nitro", "hosts", "remove"}, environment.Args()...)...); err != nil {
 					return err
 				}
 			}
@@ -258,6 +306,35 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 
 	// add flags to the command
 	cmd.Flags().Bool("clean", false, "remove configuration file")
+	cmd.Flags().Bool("include-protected", false, "remove volumes marked as protected")
+	cmd.Flags().Bool("keep-data", false, "keep the database volumes")
+	cmd.Flags().BoolP("force", "f", false, "skip the confirmation")
 
 	return cmd
 }
+
+// keep returns the names of the volumes destroy should not remove. The volumes marked as
+// protected in the config are kept unless includeProtected is true, and the volumes mounted
+// by database containers are kept when keepData is true.
+func keep(cfg *config.Config, containers []types.Container, includeProtected, keepData bool) map[string]bool {
+	volumes := map[string]bool{}
+	if !includeProtected {
+		volumes = cfg.ProtectedVolumes()
+	}
+
+	if keepData {
+		for _, c := range containers {
+			if c.Labels[containerlabels.DatabaseEngine] == "" {
+				continue
+			}
+
+			for _, m := range c.Mounts {
+				if m.Type == mount.TypeVolume {
+					volumes[m.Name] = true
+				}
+			}
+		}
+	}
+
+	return volumes
+}
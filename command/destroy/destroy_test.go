@@ -0,0 +1,57 @@
+package destroy
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/mount"
+
+	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/containerlabels"
+)
+
+func TestKeep(t *testing.T) {
+	cfg := &config.Config{
+		Databases: []config.Database{
+			{Engine: "mysql", Version: "8.0", Port: "3306", Protected: true},
+			{Engine: "postgres", Version: "13", Port: "5432"},
+		},
+	}
+
+	containers := []types.Container{
+		{ID: "mysql", Labels: map[string]string{containerlabels.DatabaseEngine: "mysql"}, Mounts: []types.MountPoint{{Type: mount.TypeVolume, Name: "mysql-8.0-3306.database.nitro"}}},
+		{ID: "postgres", Labels: map[string]string{containerlabels.DatabaseEngine: "postgres"}, Mounts: []types.MountPoint{{Type: mount.TypeVolume, Name: "postgres-13-5432.database.nitro"}}},
+		{ID: "proxy", Labels: map[string]string{containerlabels.Proxy: "true"}, Mounts: []types.MountPoint{{Type: mount.TypeVolume, Name: "nitro"}}},
+	}
+
+	tests := []struct {
+		name             string
+		includeProtected bool
+		keepData         bool
+		want             map[string]bool
+	}{
+		{
+			name: "protected volumes are kept",
+			want: map[string]bool{"mysql-8.0-3306.database.nitro": true},
+		},
+		{
+			name:             "protected volumes can be removed",
+			includeProtected: true,
+			want:             map[string]bool{},
+		},
+		{
+			name:             "database volumes are kept with keep data",
+			includeProtected: true,
+			keepData:         true,
+			want:             map[string]bool{"mysql-8.0-3306.database.nitro": true, "postgres-13-5432.database.nitro": true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := keep(cfg, containers, tt.includeProtected, tt.keepData); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("keep() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -6,8 +6,10 @@ import (
 	"github.com/docker/docker/client"
 	"github.com/spf13/cobra"
 
+	"github.com/craftcms/nitro/command/internal/service"
 	"github.com/craftcms/nitro/pkg/config"
 	"github.com/craftcms/nitro/pkg/terminal"
+	"github.com/craftcms/nitro/protob"
 )
 
 var (
@@ -25,11 +27,14 @@ const exampleText = `  # disable services
   nitro disable minio
 
   # disable dynamodb
-  nitro disable dynamodb`
+  nitro disable dynamodb
+
+  # disable the redisinsight web interface and keep redis
+  nitro disable redisinsight`
 
 // NewCommand returns the command to enable common nitro services. These services are provided as containers
 // and do not require a user to configure the ports/volumes or images.
-func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outputer) *cobra.Command {
+func NewCommand(home string, docker client.CommonAPIClient, nitrod protob.NitroClient, output terminal.Outputer) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "disable",
 		Short: "Disables a service.",
@@ -42,42 +47,23 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 
 			return nil
 		},
-		ValidArgs: []string{"dynamodb", "mailhog", "minio", "redis"},
+		ValidArgs: config.ServiceNames,
 		Example:   exampleText,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			// make sure the service exists
+			if _, err := service.Find(args[0]); err != nil {
+				return ErrUnknownService
+			}
+
 			// load the configuration
 			cfg, err := config.Load(home)
 			if err != nil {
 				return err
 			}
 
-			// disable the service
-			switch args[0] {
-			case "dynamodb":
-				cfg.Services.DynamoDB = false
-			case "mailhog":
-				cfg.Services.Mailhog = false
-			case "minio":
-				cfg.Services.Minio = false
-			case "redis":
-				cfg.Services.Redis = false
-			default:
-				return ErrUnknownService
-			}
-
-			// save the config file
-			if err := cfg.Save(); err != nil {
-				return fmt.Errorf("unable to save config, %w", err)
-			}
-
-			// run the apply command
-			for _, c := range cmd.Parent().Commands() {
-				// set the apply command
-				if c.Use == "apply" {
-					if err := c.RunE(c, args); err != nil {
-						return err
-					}
-				}
+			// disable the service and update its container
+			if err := service.Toggle(cmd.Context(), docker, nitrod, cfg, args[0], false, output); err != nil {
+				return err
 			}
 
 			return nil
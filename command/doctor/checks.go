@@ -0,0 +1,326 @@
+package doctor
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+
+	"github.com/craftcms/nitro/pkg/certificate"
+	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/dockercontext"
+	"github.com/craftcms/nitro/pkg/environment"
+	"github.com/craftcms/nitro/pkg/portavail"
+)
+
+const (
+	pass = "pass"
+	fail = "fail"
+	skip = "skip"
+)
+
+// volumeLimit is the size of the nitro volumes that is reported as a problem.
+const volumeLimit = 20 * 1024 * 1024 * 1024
+
+var (
+	// lookupHost resolves the hostnames of the sites, it is a variable for testing.
+	lookupHost = net.LookupHost
+
+	// checkPort checks if a port is available, it is a variable for testing.
+	checkPort = portavail.Check
+
+	// systemRoots returns the trusted certificates of the system, it is a variable for testing.
+	systemRoots = x509.SystemCertPool
+)
+
+// result is the result of a check, it is also the JSON output of the command.
+type result struct {
+	Check   string `json:"check"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+	Hint    string `json:"hint,omitempty"`
+}
+
+// port is a port on the host that is published by the proxy container.
+type port struct {
+	Name     string
+	Env      string
+	Default  string
+	Internal string
+}
+
+// ports returns the ports the proxy publishes for the environment, they can be changed with
+// environment variables.
+func ports() []port {
+	return []port{
+		{Name: "HTTP", Env: "NITRO_HTTP_PORT", Default: strconv.Itoa(environment.Ports.HTTP), Internal: "80"},
+		{Name: "HTTPS", Env: "NITRO_HTTPS_PORT", Default: strconv.Itoa(environment.Ports.HTTPS), Internal: "443"},
+		{Name: "API", Env: "NITRO_API_PORT", Default: strconv.Itoa(environment.Ports.API), Internal: "5000"},
+	}
+}
+
+// run runs each of the checks and returns the results. The checks that need Docker are
+// skipped when Docker is not running, and the checks that need the proxy are skipped when
+// the proxy is not running.
+func run(ctx context.Context, docker client.CommonAPIClient, cfg *config.Config) []result {
+	results := []result{dockerCheck(ctx, docker)}
+	if results[0].Status != pass {
+		return append(results, result{Check: "proxy", Status: skip, Message: "Docker is not running"})
+	}
+
+	proxy, res := proxyCheck(ctx, docker)
+	results = append(results, res)
+	results = append(results, portChecks(proxy)...)
+	results = append(results, dnsChecks(cfg)...)
+
+	if proxy == nil {
+		results = append(results, result{Check: "certificate", Status: skip, Message: "the proxy is not running"})
+	} else {
+		results = append(results, trustCheck(ctx, docker, proxy.ID))
+	}
+
+	return append(results, diskCheck(ctx, docker))
+}
+
+// dockerCheck checks that the Docker daemon is reachable.
+func dockerCheck(ctx context.Context, docker client.CommonAPIClient) result {
+	ping, err := docker.Ping(ctx)
+	if err != nil {
+		return result{
+			Check:   "docker",
+			Status:  fail,
+			Message: fmt.Sprintf("unable to connect to Docker at %s", docker.DaemonHost()),
+			Hint:    "Start Docker, or check DOCKER_HOST and the --docker-context flag.",
+		}
+	}
+
+	return result{Check: "docker", Status: pass, Message: fmt.Sprintf("Docker is running (API version %s)", ping.APIVersion)}
+}
+
+// proxyCheck checks that the proxy container is running and healthy. It returns the
+// details of the proxy container when it is running.
+func proxyCheck(ctx context.Context, docker client.CommonAPIClient) (*types.ContainerJSON, result) {
+	filter := filters.NewArgs()
+	filter.Add("label", containerlabels.Nitro+"="+environment.Label())
+	filter.Add("label", containerlabels.Proxy)
+
+	containers, err := docker.ContainerList(ctx, types.ContainerListOptions{All: true, Filters: filter})
+	if err != nil {
+		return nil, result{Check: "proxy", Status: fail, Message: fmt.Sprintf("unable to list the containers, %s", err)}
+	}
+
+	if len(containers) == 0 {
+		return nil, result{Check: "proxy", Status: fail, Message: "the proxy container does not exist", Hint: "Run `nitro apply` to create the proxy."}
+	}
+
+	details, err := docker.ContainerInspect(ctx, containers[0].ID)
+	if err != nil {
+		return nil, result{Check: "proxy", Status: fail, Message: fmt.Sprintf("unable to inspect the proxy, %s", err)}
+	}
+
+	if !details.State.Running {
+		return nil, result{Check: "proxy", Status: fail, Message: "the proxy container is not running", Hint: "Run `nitro start` to start the proxy."}
+	}
+
+	if details.State.Health != nil && details.State.Health.Status != types.Healthy {
+		return &details, result{
+			Check:   "proxy",
+			Status:  fail,
+			Message: fmt.Sprintf("the proxy container is %s", details.State.Health.Status),
+			Hint:    "Check the output of the proxy with `nitro logs proxy`.",
+		}
+	}
+
+	return &details, result{Check: "proxy", Status: pass, Message: fmt.Sprintf("the proxy is running (version %s)", details.Config.Labels[containerlabels.ProxyVersion])}
+}
+
+// portChecks checks that the ports of the proxy are available, or published by the proxy
+// when it is running.
+func portChecks(proxy *types.ContainerJSON) []result {
+	var results []result
+	for _, p := range ports() {
+		number := p.Default
+		if v, ok := os.LookupEnv(p.Env); ok {
+			number = v
+		}
+
+		check := fmt.Sprintf("port %s", number)
+
+		if proxy != nil {
+			if published(proxy, p.Internal, number) {
+				results = append(results, result{Check: check, Status: pass, Message: fmt.Sprintf("the %s port %s is used by the proxy", p.Name, number)})
+			} else {
+				results = append(results, result{
+					Check:   check,
+					Status:  fail,
+					Message: fmt.Sprintf("the proxy does not publish the %s port %s", p.Name, number),
+					Hint:    fmt.Sprintf("The proxy was created with a different %s, run `nitro update` to recreate the proxy.", p.Env),
+				})
+			}
+
+			continue
+		}
+
+		if err := checkPort("", number); err != nil {
+			results = append(results, result{
+				Check:   check,
+				Status:  fail,
+				Message: fmt.Sprintf("the %s port %s is used by another application", p.Name, number),
+				Hint:    fmt.Sprintf("Stop the application using the port, or set %s to use another port.", p.Env),
+			})
+
+			continue
+		}
+
+		results = append(results, result{Check: check, Status: pass, Message: fmt.Sprintf("the %s port %s is available", p.Name, number)})
+	}
+
+	return results
+}
+
+// published checks if the proxy publishes the internal port on the host port.
+func published(proxy *types.ContainerJSON, internal, host string) bool {
+	if proxy.HostConfig == nil {
+		return false
+	}
+
+	for p, bindings := range proxy.HostConfig.PortBindings {
+		if p.Port() != internal {
+			continue
+		}
+
+		for _, b := range bindings {
+			if b.HostPort == host {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// dnsChecks checks that the hostnames of the sites resolve to the machine running the proxy.
+func dnsChecks(cfg *config.Config) []result {
+	if cfg == nil {
+		return nil
+	}
+
+	address := dockercontext.Current.Address()
+
+	var results []result
+	for _, s := range cfg.Sites {
+		for _, h := range append([]string{s.Hostname}, s.Aliases...) {
+			addrs, err := lookupHost(h)
+			switch {
+			case err != nil:
+				results = append(results, result{Check: "dns " + h, Status: fail, Message: fmt.Sprintf("%s does not resolve", h), Hint: "Run `nitro hosts` to add the hostnames to the hosts file."})
+			case !contains(addrs, address):
+				results = append(results, result{
+					Check:   "dns " + h,
+					Status:  fail,
+					Message: fmt.Sprintf("%s resolves to %s instead of %s", h, strings.Join(addrs, ", "), address),
+					Hint:    "Run `nitro hosts` to update the hosts file, or check for other entries for the hostname.",
+				})
+			default:
+				results = append(results, result{Check: "dns " + h, Status: pass, Message: fmt.Sprintf("%s resolves to %s", h, address)})
+			}
+		}
+	}
+
+	return results
+}
+
+// trustCheck checks that the root certificate of the proxy is trusted by the system.
+func trustCheck(ctx context.Context, docker client.ContainerAPIClient, proxyID string) result {
+	data, err := certificate.Read(ctx, docker, proxyID, certificate.RootPath)
+	if err != nil {
+		return result{Check: "certificate", Status: fail, Message: "unable to find the root certificate in the proxy", Hint: "Run `nitro apply` to create the certificate."}
+	}
+
+	return trusted(data)
+}
+
+// trusted checks if the PEM encoded root certificate is trusted by the system.
+func trusted(data []byte) result {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return result{Check: "certificate", Status: fail, Message: "the root certificate is not valid", Hint: "Run `nitro update` to recreate the proxy."}
+	}
+
+	root, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return result{Check: "certificate", Status: fail, Message: "the root certificate is not valid", Hint: "Run `nitro update` to recreate the proxy."}
+	}
+
+	pool, err := systemRoots()
+	if err != nil || pool == nil {
+		return result{Check: "certificate", Status: skip, Message: "unable to read the trusted certificates of the system"}
+	}
+
+	if _, err := root.Verify(x509.VerifyOptions{Roots: pool}); err != nil {
+		return result{Check: "certificate", Status: fail, Message: "the root certificate is not trusted", Hint: "Run `nitro trust` to trust the certificate."}
+	}
+
+	return result{Check: "certificate", Status: pass, Message: "the root certificate is trusted"}
+}
+
+// diskCheck checks the disk space used by the volumes of the databases, services, and sites.
+func diskCheck(ctx context.Context, docker client.CommonAPIClient) result {
+	usage, err := docker.DiskUsage(ctx)
+	if err != nil {
+		return result{Check: "volumes", Status: skip, Message: fmt.Sprintf("unable to get the disk usage, %s", err)}
+	}
+
+	var total int64
+	var count int
+	for _, v := range usage.Volumes {
+		if v.Labels[containerlabels.Nitro] == "" || v.UsageData == nil || v.UsageData.Size < 0 {
+			continue
+		}
+
+		total += v.UsageData.Size
+		count++
+	}
+
+	msg := fmt.Sprintf("%d volumes use %s", count, size(total))
+	if total > volumeLimit {
+		return result{Check: "volumes", Status: fail, Message: msg, Hint: "Remove unused databases with `nitro db remove`, or unused volumes with `docker volume prune`."}
+	}
+
+	return result{Check: "volumes", Status: pass, Message: msg}
+}
+
+// size returns the number of bytes in a readable format (e.g. 1.5 GB).
+func size(b int64) string {
+	const unit = 1024
+	if b < unit {
+		return fmt.Sprintf("%d B", b)
+	}
+
+	div, exp := int64(unit), 0
+	for n := b / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %cB", float64(b)/float64(div), "KMGTPE"[exp])
+}
+
+func contains(list []string, s string) bool {
+	for _, l := range list {
+		if l == s {
+			return true
+		}
+	}
+
+	return false
+}
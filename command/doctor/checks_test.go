@@ -0,0 +1,101 @@
+package doctor
+
+import (
+	"errors"
+	"net"
+	"os"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/go-connections/nat"
+
+	"github.com/craftcms/nitro/pkg/config"
+)
+
+func TestPortChecks(t *testing.T) {
+	os.Setenv("NITRO_HTTP_PORT", "8080")
+	defer os.Unsetenv("NITRO_HTTP_PORT")
+
+	proxy := &types.ContainerJSON{
+		ContainerJSONBase: &types.ContainerJSONBase{
+			HostConfig: &container.HostConfig{
+				PortBindings: nat.PortMap{
+					"80/tcp":   []nat.PortBinding{{HostPort: "8080"}},
+					"443/tcp":  []nat.PortBinding{{HostPort: "443"}},
+					"5000/tcp": []nat.PortBinding{{HostPort: "5001"}},
+				},
+			},
+		},
+	}
+
+	got := portChecks(proxy)
+	want := []string{pass, pass, fail}
+	for i, r := range got {
+		if r.Status != want[i] {
+			t.Errorf("portChecks()[%d] = %s, want %s (%s)", i, r.Status, want[i], r.Message)
+		}
+	}
+
+	// without the proxy, the ports are checked for other applications
+	defer func(fn func(string, string) error) { checkPort = fn }(checkPort)
+	checkPort = func(host, port string) error {
+		if port == "443" {
+			return errors.New("in use")
+		}
+
+		return nil
+	}
+
+	got = portChecks(nil)
+	want = []string{pass, fail, pass}
+	for i, r := range got {
+		if r.Status != want[i] {
+			t.Errorf("portChecks()[%d] = %s, want %s (%s)", i, r.Status, want[i], r.Message)
+		}
+	}
+}
+
+func TestDNSChecks(t *testing.T) {
+	defer func(fn func(string) ([]string, error)) { lookupHost = fn }(lookupHost)
+	lookupHost = func(host string) ([]string, error) {
+		switch host {
+		case "craft.nitro":
+			return []string{"127.0.0.1"}, nil
+		case "alias.nitro":
+			return []string{"10.0.0.5"}, nil
+		}
+
+		return nil, &net.DNSError{Err: "no such host", Name: host}
+	}
+
+	cfg := &config.Config{Sites: []config.Site{
+		{Hostname: "craft.nitro", Aliases: []string{"alias.nitro"}},
+		{Hostname: "missing.nitro"},
+	}}
+
+	got := dnsChecks(cfg)
+	want := []string{pass, fail, fail}
+	if len(got) != len(want) {
+		t.Fatalf("dnsChecks() returned %d results, want %d", len(got), len(want))
+	}
+
+	for i, r := range got {
+		if r.Status != want[i] {
+			t.Errorf("dnsChecks()[%d] = %s, want %s (%s)", i, r.Status, want[i], r.Message)
+		}
+	}
+}
+
+func TestSize(t *testing.T) {
+	tests := map[int64]string{
+		512:                    "512 B",
+		1536:                   "1.5 KB",
+		3 * 1024 * 1024 * 1024: "3.0 GB",
+	}
+	for b, want := range tests {
+		if got := size(b); got != want {
+			t.Errorf("size(%d) = %s, want %s", b, got, want)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package doctor
+
+import (
+	"fmt"
+
+	"github.com/docker/docker/client"
+	"github.com/spf13/cobra"
+
+	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/terminal"
+)
+
+const exampleText = `  # check Docker, the proxy, ports, DNS, certificates, and volumes
+  nitro doctor
+
+  # show the results of the checks as JSON
+  nitro doctor --json
+
+  # check the permissions of the writable directories for a site
+  nitro doctor permissions`
+
+// NewCommand returns the doctor commands, which check for common problems with an
+// environment and offer to fix them. Without a subcommand, it runs each of the checks
+// and shows a hint to fix the checks that fail.
+func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outputer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "doctor",
+		Short:   "Checks for common problems.",
+		Example: exampleText,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// the checks for the sites are skipped without a config
+			cfg, _ := config.Load(home)
+
+			results := run(cmd.Context(), docker, cfg)
+
+			if terminal.JSON {
+				return output.JSON(results)
+			}
+
+			failed := 0
+			for _, r := range results {
+				switch r.Status {
+				case pass:
+					output.Success(r.Message)
+				case skip:
+					output.Info("  -", r.Check, "skipped,", r.Message)
+				default:
+					failed++
+					output.Info("  ✗", r.Message)
+					if r.Hint != "" {
+						output.Info("     ", r.Hint)
+					}
+				}
+			}
+
+			if failed > 0 {
+				return fmt.Errorf("%d checks failed", failed)
+			}
+
+			output.Info("Everything looks good 👍")
+
+			return nil
+		},
+	}
+
+	cmd.AddCommand(permissionsCommand(home, docker, output))
+
+	return cmd
+}
@@ -0,0 +1,237 @@
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+	"github.com/spf13/cobra"
+
+	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/containerexec"
+	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/prompt"
+	"github.com/craftcms/nitro/pkg/terminal"
+)
+
+const permissionsExampleText = `  # check the permissions for the site in the current directory
+  nitro doctor permissions
+
+  # check the permissions for a specific site
+  nitro doctor permissions tutorial.nitro
+
+  # fix the permissions without a prompt
+  nitro doctor permissions tutorial.nitro --fix`
+
+// directory is the owner of a directory in the site container and if the user of the
+// container is able to write to it.
+type directory struct {
+	Path     string
+	Exists   bool
+	Owner    string
+	Writable bool
+}
+
+// permissionsCommand returns the command to check that the directories Craft writes to are
+// writable by the user inside of the site container. On Linux, the files in the bind mount
+// keep the UID and GID of the host user, which often does not match the user of the
+// container. The fix changes the owner of the directories to the user of the container.
+func permissionsCommand(home string, docker client.CommonAPIClient, output terminal.Outputer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "permissions [SITE]",
+		Short:   "Checks the permissions of writable directories.",
+		Args:    cobra.MaximumNArgs(1),
+		Example: permissionsExampleText,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			cfg, err := config.Load(home)
+			if err != nil {
+				return err
+			}
+
+			site, err := findSite(cmd, home, cfg, args, output)
+			if err != nil {
+				return err
+			}
+
+			filter := filters.NewArgs()
+			filter.Add("label", containerlabels.Host+"="+site.Hostname)
+
+			containers, err := docker.ContainerList(ctx, types.ContainerListOptions{Filters: filter})
+			if err != nil {
+				return err
+			}
+
+			if len(containers) == 0 {
+				return fmt.Errorf("the container for %s is not running, run `nitro start` to start it", site.Hostname)
+			}
+
+			id := containers[0].ID
+
+			// get the user the container runs php as
+			user, err := containerexec.Run(ctx, docker, id, []string{"id", "-un"})
+			if err != nil {
+				return err
+			}
+
+			uid, err := containerexec.Run(ctx, docker, id, []string{"id", "-u"})
+			if err != nil {
+				return err
+			}
+
+			gid, err := containerexec.Run(ctx, docker, id, []string{"id", "-g"})
+			if err != nil {
+				return err
+			}
+
+			owner := uid + ":" + gid
+
+			output.Info(fmt.Sprintf("Checking the permissions for %s as %s (%s)…", site.Hostname, user, owner))
+
+			var dirs []directory
+			for _, p := range writable(*site) {
+				dirs = append(dirs, inspect(ctx, docker, id, p))
+			}
+
+			for _, d := range dirs {
+				switch {
+				case !d.Exists:
+					output.Info("  -", d.Path, "does not exist")
+				case d.Writable && d.Owner == owner:
+					output.Success(d.Path, "is writable")
+				case d.Writable:
+					output.Success(d.Path, "is writable (owned by "+d.Owner+")")
+				default:
+					output.Info(fmt.Sprintf("  ✗ %s is not writable, it is owned by %s instead of %s", d.Path, d.Owner, owner))
+				}
+			}
+
+			broken := problems(dirs)
+			if len(broken) == 0 {
+				output.Info("The permissions for", site.Hostname, "are correct 👍")
+
+				return nil
+			}
+
+			fix := cmd.Flag("fix").Value.String() == "true"
+			if !fix {
+				output.Info("")
+				output.Info("The directories are owned by a different UID or GID than the user in the container.")
+				output.Info("Fixing the permissions changes the owner of the directories, including on the host, to " + owner + ".")
+
+				fix, err = output.Confirm("Fix the permissions", false, "?")
+				if err != nil {
+					return err
+				}
+			}
+
+			if !fix {
+				output.Info("To fix the permissions yourself, run `nitro ssh --root` and make the directories writable by", user)
+
+				return nil
+			}
+
+			cmds := []string{"chown", "-R", owner}
+			for _, d := range broken {
+				cmds = append(cmds, d.Path)
+			}
+
+			output.Pending("fixing permissions")
+
+			if _, err := containerexec.RunAs(ctx, docker, id, "root", cmds); err != nil {
+				output.Warning()
+				return fmt.Errorf("unable to fix the permissions, %w", err)
+			}
+
+			output.Done()
+
+			return nil
+		},
+	}
+
+	cmd.Flags().Bool("fix", false, "fix the permissions without a prompt")
+
+	return cmd
+}
+
+// writable returns the directories in the container that Craft needs to write to.
+func writable(site config.Site) []string {
+	root := path.Join("/app", site.GetContainerPath())
+
+	return []string{
+		path.Join(root, "storage"),
+		path.Join("/app", strings.TrimRight(site.Webroot, "/"), "cpresources"),
+	}
+}
+
+// inspect returns the owner of the directory and if the containers default user can write
+// to it. The owner is read as root, so it is available when the user has no access.
+func inspect(ctx context.Context, docker client.ContainerAPIClient, containerID, p string) directory {
+	d := directory{Path: p}
+
+	owner, err := containerexec.RunAs(ctx, docker, containerID, "root", []string{"stat", "-c", "%u:%g", p})
+	if err != nil {
+		return d
+	}
+
+	d.Exists = true
+	d.Owner = owner
+
+	if _, err := containerexec.Run(ctx, docker, containerID, []string{"test", "-w", p}); err == nil {
+		d.Writable = true
+	}
+
+	return d
+}
+
+// problems returns the directories that exist but are not writable.
+func problems(dirs []directory) []directory {
+	var broken []directory
+	for _, d := range dirs {
+		if d.Exists && !d.Writable {
+			broken = append(broken, d)
+		}
+	}
+
+	return broken
+}
+
+// findSite returns the site from the argument, the current directory, or prompts the user.
+func findSite(cmd *cobra.Command, home string, cfg *config.Config, args []string, output terminal.Outputer) (*config.Site, error) {
+	if len(args) > 0 {
+		return cfg.FindSiteByHostName(strings.TrimSpace(args[0]))
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+
+	// get a context aware list of sites
+	sites := cfg.ListOfSitesByDirectory(home, wd)
+	if len(sites) == 0 {
+		return nil, fmt.Errorf("there are no sites in the config")
+	}
+
+	if len(sites) == 1 {
+		return &sites[0], nil
+	}
+
+	var options []string
+	for _, s := range sites {
+		options = append(options, s.Hostname)
+	}
+
+	selected, err := prompt.Select(cmd.InOrStdin(), "Select a site: ", options, output)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sites[selected], nil
+}
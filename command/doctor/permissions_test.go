@@ -0,0 +1,47 @@
+package doctor
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/craftcms/nitro/pkg/config"
+)
+
+func TestWritable(t *testing.T) {
+	tests := []struct {
+		name string
+		site config.Site
+		want []string
+	}{
+		{
+			name: "sites use the storage and cpresources directories",
+			site: config.Site{Webroot: "web"},
+			want: []string{"/app/storage", "/app/web/cpresources"},
+		},
+		{
+			name: "nested web roots use the project directory",
+			site: config.Site{Webroot: "craft/web/"},
+			want: []string{"/app/craft/storage", "/app/craft/web/cpresources"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := writable(tt.site); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("writable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProblems(t *testing.T) {
+	dirs := []directory{
+		{Path: "/app/storage", Exists: true, Owner: "1000:1000"},
+		{Path: "/app/web/cpresources", Exists: true, Owner: "82:82", Writable: true},
+		{Path: "/app/missing"},
+	}
+
+	want := []directory{dirs[0]}
+	if got := problems(dirs); !reflect.DeepEqual(got, want) {
+		t.Errorf("problems() = %v, want %v", got, want)
+	}
+}
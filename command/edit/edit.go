@@ -1,35 +1,202 @@
 package edit
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
 	"github.com/docker/docker/client"
 	"github.com/spf13/cobra"
 
 	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/dockercontext"
 	"github.com/craftcms/nitro/pkg/editor"
+	"github.com/craftcms/nitro/pkg/prompt"
 	"github.com/craftcms/nitro/pkg/terminal"
 )
 
 const exampleText = `  # edit the config file
-  nitro edit`
+  nitro edit
+
+  # edit the config file and apply the changes without asking
+  nitro edit --apply`
 
 // NewCommand returns the command to edit a config file with the users default editor as defined by the
-// $EDITOR variable.
+// $EDITOR variable. The changes are validated before they are saved, and the changes are shown before
+// offering to apply them.
 func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outputer) *cobra.Command {
+	// changed is set when the config file was saved with changes
+	var changed bool
+
 	cmd := &cobra.Command{
 		Use:     "edit",
 		Short:   "Opens Nitro’s config in the default editor.",
 		Example: exampleText,
+		PostRunE: func(cmd *cobra.Command, args []string) error {
+			if !changed {
+				return nil
+			}
+
+			force, _ := cmd.Flags().GetBool("apply")
+
+			return prompt.RunApply(cmd, args, force, output)
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cfg, err := config.Load(home)
 			if err != nil {
 				return err
 			}
 
-			_, err = editor.CaptureInputFromEditor(cfg.GetFile(), editor.GetPreferredEditorFromEnvironment)
+			file := cfg.GetFile()
+
+			before, err := ioutil.ReadFile(file)
+			if err != nil {
+				return err
+			}
+
+			// the changes are made to a copy next to the config file, so the config is not
+			// saved until it is valid and the schema is still found by the editor
+			tmp, err := ioutil.TempFile(filepath.Dir(file), ".edit-*.yaml")
+			if err != nil {
+				return fmt.Errorf("unable to create a copy of the config file, %w", err)
+			}
+			defer os.Remove(tmp.Name())
+
+			if _, err := tmp.Write(before); err != nil {
+				tmp.Close()
+				return err
+			}
+
+			if err := tmp.Close(); err != nil {
+				return err
+			}
+
+			// the site paths are on the machine running docker when it is remote
+			dir := home
+			if dockercontext.Current.Remote() {
+				dir = ""
+			}
+
+			for {
+				after, err := editor.CaptureInputFromEditor(tmp.Name(), editor.GetPreferredEditorFromEnvironment)
+				if err != nil {
+					return err
+				}
+
+				if bytes.Equal(before, after) {
+					output.Info("The config file was not changed.")
+					return nil
+				}
+
+				verr := validate(tmp.Name(), file, dir)
+				if verr == nil {
+					lines := diff(string(before), string(after))
+
+					output.Info("Changes to", file)
+					for _, l := range lines {
+						output.Info("  " + l)
+					}
+
+					if err := ioutil.WriteFile(file, after, 0644); err != nil {
+						return fmt.Errorf("unable to save the config file, %w", err)
+					}
 
-			return err
+					changed = true
+
+					return nil
+				}
+
+				output.Info(verr.Error())
+
+				again, err := output.Confirm("Edit the config file again?", true, "")
+				if err != nil {
+					return err
+				}
+
+				if !again {
+					return fmt.Errorf("the changes to the config file were not saved")
+				}
+			}
 		},
 	}
 
+	cmd.Flags().Bool("apply", false, "apply the changes without asking")
+
 	return cmd
 }
+
+// validate loads the edited copy of the config file and returns the problems with it, the
+// problems refer to the config file instead of the copy.
+func validate(edited, file, home string) error {
+	cfg, err := config.LoadFile(edited)
+	if err != nil {
+		return fmt.Errorf("unable to read the config file, %w", err)
+	}
+
+	err = cfg.Validate(home)
+
+	var verr *config.ValidationError
+	if errors.As(err, &verr) {
+		verr.File = file
+	}
+
+	return err
+}
+
+// diff returns the lines that were removed from before, prefixed with "-", and the lines
+// that were added in after, prefixed with "+", in the order of the file. The lines are
+// compared with the longest common subsequence, config files are small so it is fine to
+// compare every line.
+func diff(before, after string) []string {
+	a := strings.Split(strings.TrimRight(before, "\n"), "\n")
+	b := strings.Split(strings.TrimRight(after, "\n"), "\n")
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			switch {
+			case a[i] == b[j]:
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var lines []string
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, "- "+a[i])
+			i++
+		default:
+			lines = append(lines, "+ "+b[j])
+			j++
+		}
+	}
+
+	for ; i < len(a); i++ {
+		lines = append(lines, "- "+a[i])
+	}
+
+	for ; j < len(b); j++ {
+		lines = append(lines, "+ "+b[j])
+	}
+
+	return lines
+}
@@ -0,0 +1,20 @@
+package edit
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	before := "sites:\n  - hostname: craft.nitro\n    version: \"7.4\"\n    xdebug: false\n"
+	after := "sites:\n  - hostname: craft.nitro\n    version: \"8.0\"\n    xdebug: false\n    blackfire: true\n"
+
+	want := []string{`-     version: "7.4"`, `+     version: "8.0"`, "+     blackfire: true"}
+	if got := diff(before, after); !reflect.DeepEqual(got, want) {
+		t.Errorf("diff() = %q, want %q", got, want)
+	}
+
+	if got := diff(before, before); len(got) != 0 {
+		t.Errorf("expected no changes, got %q", got)
+	}
+}
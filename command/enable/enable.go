@@ -6,8 +6,10 @@ import (
 	"github.com/docker/docker/client"
 	"github.com/spf13/cobra"
 
+	"github.com/craftcms/nitro/command/internal/service"
 	"github.com/craftcms/nitro/pkg/config"
 	"github.com/craftcms/nitro/pkg/terminal"
+	"github.com/craftcms/nitro/protob"
 )
 
 var (
@@ -28,11 +30,17 @@ const exampleText = `  # enable services
   nitro enable minio
 
   # enable dynamodb for local noSQL
-  nitro enable dynamodb`
+  nitro enable dynamodb
+
+  # enable gotenberg for html to pdf and screenshot generation
+  nitro enable gotenberg
+
+  # enable redis with the redisinsight web interface on redisinsight.<tld>
+  nitro enable redisinsight`
 
 // NewCommand returns the command to enable common nitro services. These services are provided as containers
 // and do not require a user to configure the ports/volumes or images.
-func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outputer) *cobra.Command {
+func NewCommand(home string, docker client.CommonAPIClient, nitrod protob.NitroClient, output terminal.Outputer) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "enable",
 		Short: "Enables a service.",
@@ -45,42 +53,23 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 
 			return nil
 		},
-		ValidArgs: []string{"dynamodb", "mailhog", "minio", "redis"},
+		ValidArgs: config.ServiceNames,
 		Example:   exampleText,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			// make sure the service exists
+			if _, err := service.Find(args[0]); err != nil {
+				return ErrUnknownService
+			}
+
 			// load the configuration
 			cfg, err := config.Load(home)
 			if err != nil {
 				return err
 			}
 
-			// enable the service
-			switch args[0] {
-			case "dynamodb":
-				cfg.Services.DynamoDB = true
-			case "mailhog":
-				cfg.Services.Mailhog = true
-			case "minio":
-				cfg.Services.Minio = true
-			case "redis":
-				cfg.Services.Redis = true
-			default:
-				return ErrUnknownService
-			}
-
-			// save the config file
-			if err := cfg.Save(); err != nil {
-				return fmt.Errorf("unable to save config, %w", err)
-			}
-
-			// run the apply command
-			for _, c := range cmd.Parent().Commands() {
-				// set the apply command
-				if c.Use == "apply" {
-					if err := c.RunE(c, args); err != nil {
-						return err
-					}
-				}
+			// enable the service and update its container
+			if err := service.Toggle(cmd.Context(), docker, nitrod, cfg, args[0], true, output); err != nil {
+				return err
 			}
 
 			return nil
@@ -0,0 +1,265 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+	"github.com/rodaine/table"
+	"github.com/spf13/cobra"
+
+	"github.com/craftcms/nitro/command/internal/sitecontainer"
+	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/envedit"
+	"github.com/craftcms/nitro/pkg/environment"
+	"github.com/craftcms/nitro/pkg/prompt"
+	"github.com/craftcms/nitro/pkg/terminal"
+)
+
+// variable is an env var in the JSON output of the diff command, the values are empty when the
+// env var is not set.
+type variable struct {
+	Name   string `json:"name"`
+	DotEnv string `json:"env_file,omitempty"`
+	Config string `json:"config,omitempty"`
+	Site   string `json:"site,omitempty"`
+	Queue  string `json:"queue,omitempty"`
+	Note   string `json:"note,omitempty"`
+}
+
+// values are the env vars from each of the places they are set, a nil map means the place was
+// not checked (e.g. the site does not have queue workers).
+type values struct {
+	dotenv, config, site, queue map[string]string
+}
+
+// diffCommand returns the command to compare the env vars in the containers of a site with the
+// .env file of the project and the config.
+func diffCommand(home string, docker client.CommonAPIClient, output terminal.Outputer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff",
+		Short: "Compares the env vars of a site.",
+		Example: `  # compare the env vars of the site in the current directory
+  nitro env diff
+
+  # show every env var, not only the ones that differ
+  nitro env diff tutorial.nitro --all`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load(home)
+			if err != nil {
+				return err
+			}
+
+			site, err := diffSite(cmd, home, cfg, args, output)
+			if err != nil {
+				return err
+			}
+
+			v := values{config: kv(sitecontainer.Envs(*site, cfg))}
+
+			// the .env file is loaded by craft when the site runs
+			if path, err := site.GetAbsPath(home); err == nil {
+				v.dotenv, err = envedit.Parse(filepath.Join(path, ".env"))
+				if err != nil && !errors.Is(err, envedit.ErrNoEnvFile) {
+					return err
+				}
+			}
+
+			if v.site, err = containerEnv(cmd, docker, containerlabels.Host, site.Hostname); err != nil {
+				return err
+			}
+
+			if v.site == nil {
+				return fmt.Errorf("unable to find the container for %s, run `nitro apply` to create it", site.Hostname)
+			}
+
+			if v.queue, err = containerEnv(cmd, docker, containerlabels.Queue, site.Hostname); err != nil {
+				return err
+			}
+
+			all, _ := cmd.Flags().GetBool("all")
+			vars := compare(v, all)
+
+			if terminal.JSON {
+				return output.JSON(vars)
+			}
+
+			if len(vars) == 0 {
+				output.Info("The env vars of", site.Hostname, "match the .env file and the config.")
+				return nil
+			}
+
+			headers := []interface{}{"Variable", ".env", "Config", "Site"}
+			if v.queue != nil {
+				headers = append(headers, "Queue")
+			}
+			headers = append(headers, "Note")
+
+			tbl := table.New(headers...).WithWriter(cmd.OutOrStdout()).WithPadding(2)
+			for _, e := range vars {
+				row := []interface{}{e.Name, dash(e.DotEnv), dash(e.Config), dash(e.Site)}
+				if v.queue != nil {
+					row = append(row, dash(e.Queue))
+				}
+
+				tbl.AddRow(append(row, e.Note)...)
+			}
+
+			tbl.Print()
+
+			return nil
+		},
+	}
+
+	cmd.Flags().Bool("all", false, "show every env var in the .env file and the config")
+
+	return cmd
+}
+
+// compare returns the env vars in the .env file and the config with the value from each place
+// and a note when the values differ or the env var is missing. Only the env vars with a note are
+// returned unless all is true.
+func compare(v values, all bool) []variable {
+	names := map[string]bool{}
+	for n := range v.dotenv {
+		names[n] = true
+	}
+	for n := range v.config {
+		names[n] = true
+	}
+
+	var vars []variable
+	for n := range names {
+		e := variable{Name: n, DotEnv: v.dotenv[n], Config: v.config[n], Site: v.site[n], Queue: v.queue[n]}
+
+		_, inDotEnv := v.dotenv[n]
+		_, inConfig := v.config[n]
+		_, inSite := v.site[n]
+		_, inQueue := v.queue[n]
+
+		switch {
+		case inConfig && !inSite:
+			e.Note = "missing in the site container, run nitro apply"
+		case inConfig && e.Site != e.Config:
+			e.Note = "the site container is outdated, run nitro apply"
+		case inDotEnv && inSite && e.Site != e.DotEnv:
+			// env vars set on the container take precedence over the .env file
+			e.Note = "the site container overrides the .env file"
+		case v.queue != nil && inSite != inQueue:
+			e.Note = "only set in one of the site and queue containers"
+		case v.queue != nil && e.Site != e.Queue:
+			e.Note = "the queue container differs from the site container"
+		}
+
+		if all || e.Note != "" {
+			vars = append(vars, e)
+		}
+	}
+
+	sort.Slice(vars, func(i, j int) bool {
+		return vars[i].Name < vars[j].Name
+	})
+
+	return vars
+}
+
+// containerEnv returns the env vars of the first container with the label for the hostname,
+// or nil when there is no container.
+func containerEnv(cmd *cobra.Command, docker client.CommonAPIClient, label, hostname string) (map[string]string, error) {
+	filter := filters.NewArgs()
+	filter.Add("label", containerlabels.Nitro+"="+environment.Label())
+	filter.Add("label", label+"="+hostname)
+
+	containers, err := docker.ContainerList(cmd.Context(), types.ContainerListOptions{Filters: filter, All: true})
+	if err != nil {
+		return nil, fmt.Errorf("unable to list the containers, %w", err)
+	}
+
+	if len(containers) == 0 {
+		return nil, nil
+	}
+
+	// use the first replica of the queue workers
+	sort.Slice(containers, func(i, j int) bool {
+		a, _ := strconv.Atoi(containers[i].Labels[containerlabels.Replica])
+		b, _ := strconv.Atoi(containers[j].Labels[containerlabels.Replica])
+
+		return a < b
+	})
+
+	details, err := docker.ContainerInspect(cmd.Context(), containers[0].ID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to inspect the container, %w", err)
+	}
+
+	if details.Config == nil {
+		return map[string]string{}, nil
+	}
+
+	return kv(details.Config.Env), nil
+}
+
+// diffSite returns the site from the argument, or the site in the current directory. The user
+// is prompted for the site when there are more sites in the current directory.
+func diffSite(cmd *cobra.Command, home string, cfg *config.Config, args []string, output terminal.Outputer) (*config.Site, error) {
+	if len(args) > 0 {
+		return cfg.FindSiteByHostName(strings.TrimSpace(args[0]))
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+
+	// get a context aware list of sites
+	sites := cfg.ListOfSitesByDirectory(home, wd)
+	switch len(sites) {
+	case 0:
+		return nil, fmt.Errorf("there are no sites in the config")
+	case 1:
+		return &sites[0], nil
+	}
+
+	var options []string
+	for _, s := range sites {
+		options = append(options, s.Hostname)
+	}
+
+	selected, err := prompt.Select(cmd.InOrStdin(), "Select a site: ", options, output)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sites[selected], nil
+}
+
+// kv returns the env vars in the KEY=value format as a map.
+func kv(envs []string) map[string]string {
+	m := make(map[string]string, len(envs))
+	for _, e := range envs {
+		sp := strings.SplitN(e, "=", 2)
+		if len(sp) == 2 {
+			m[sp[0]] = sp[1]
+		}
+	}
+
+	return m
+}
+
+// dash returns the value or a dash when the value is empty.
+func dash(v string) string {
+	if v == "" {
+		return "-"
+	}
+
+	return v
+}
@@ -0,0 +1,36 @@
+package env
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	v := values{
+		dotenv: map[string]string{"DB_SERVER": "mysql-8.0-3306.database.nitro", "APP_ID": "CraftCMS", "ENVIRONMENT": "dev"},
+		config: map[string]string{"PHP_MEMORY_LIMIT": "512M", "XDEBUG_MODE": "debug"},
+		site:   map[string]string{"PHP_MEMORY_LIMIT": "512M", "XDEBUG_MODE": "off", "ENVIRONMENT": "production", "DB_SERVER": "mysql-8.0-3306.database.nitro"},
+		queue:  map[string]string{"PHP_MEMORY_LIMIT": "256M", "XDEBUG_MODE": "off", "ENVIRONMENT": "production"},
+	}
+
+	want := []variable{
+		{Name: "DB_SERVER", DotEnv: "mysql-8.0-3306.database.nitro", Site: "mysql-8.0-3306.database.nitro", Note: "only set in one of the site and queue containers"},
+		{Name: "ENVIRONMENT", DotEnv: "dev", Site: "production", Queue: "production", Note: "the site container overrides the .env file"},
+		{Name: "PHP_MEMORY_LIMIT", Config: "512M", Site: "512M", Queue: "256M", Note: "the queue container differs from the site container"},
+		{Name: "XDEBUG_MODE", Config: "debug", Site: "off", Queue: "off", Note: "the site container is outdated, run nitro apply"},
+	}
+	if got := compare(v, false); !reflect.DeepEqual(got, want) {
+		t.Errorf("compare() = %v, want %v", got, want)
+	}
+
+	// every env var is returned with all
+	if got := compare(v, true); len(got) != 5 || got[0].Name != "APP_ID" || got[0].Note != "" {
+		t.Errorf("expected every env var with all, got %v", got)
+	}
+
+	// the queue is not compared when the site does not have queue workers
+	v.queue = nil
+	if got := compare(v, false); len(got) != 2 {
+		t.Errorf("expected only the site container to be compared, got %v", got)
+	}
+}
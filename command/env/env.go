@@ -0,0 +1,42 @@
+package env
+
+import (
+	"github.com/docker/docker/client"
+	"github.com/spf13/cobra"
+
+	"github.com/craftcms/nitro/pkg/terminal"
+)
+
+const exampleText = `  # rename the environment
+  nitro env rename craft-dev
+
+  # show the environments
+  nitro env list
+
+  # use the client-a environment for the commands
+  nitro env use client-a
+
+  # compare the env vars of a site with its .env file
+  nitro env diff tutorial.nitro`
+
+// NewCommand returns the command to manage the environment in the config, to switch between
+// the environments, and to compare the env vars of a site.
+func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outputer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "env",
+		Short:   "Manages the environments.",
+		Example: exampleText,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(
+		diffCommand(home, docker, output),
+		listCommand(home, output),
+		renameCommand(home, output),
+		useCommand(home, output),
+	)
+
+	return cmd
+}
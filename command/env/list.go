@@ -0,0 +1,93 @@
+package env
+
+import (
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/rodaine/table"
+	"github.com/spf13/cobra"
+
+	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/environment"
+	"github.com/craftcms/nitro/pkg/terminal"
+)
+
+// listed is an environment in the JSON output of the list command.
+type listed struct {
+	Environment string              `json:"environment"`
+	Name        string              `json:"name,omitempty"`
+	File        string              `json:"file"`
+	Ports       environment.PortSet `json:"ports,omitempty"`
+	Sites       int                 `json:"sites"`
+	Current     bool                `json:"current"`
+}
+
+// listCommand returns the command to show the environments, each config file in the nitro
+// directory is an environment named after the file.
+func listCommand(home string, output terminal.Outputer) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "Shows the environments.",
+		Example: `  # show the environments and which one is used
+  nitro env list`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			files, err := config.Files(home)
+			if err != nil {
+				return err
+			}
+
+			envs := []listed{}
+			for _, f := range files {
+				cfg, err := config.LoadFile(f)
+				if err != nil {
+					return err
+				}
+
+				name := strings.TrimSuffix(filepath.Base(f), filepath.Ext(f))
+
+				ports := cfg.Proxy.Ports
+				if ports.IsZero() && name == environment.Default {
+					ports = environment.DefaultPorts
+				}
+
+				envs = append(envs, listed{
+					Environment: name,
+					Name:        cfg.Name,
+					File:        f,
+					Ports:       ports,
+					Sites:       len(cfg.Sites),
+					Current:     name == environment.Name,
+				})
+			}
+
+			if terminal.JSON {
+				return output.JSON(envs)
+			}
+
+			tbl := table.New("", "Environment", "Name", "Sites", "HTTP", "HTTPS", "API").WithWriter(cmd.OutOrStdout()).WithPadding(2)
+			for _, e := range envs {
+				current := ""
+				if e.Current {
+					current = "*"
+				}
+
+				tbl.AddRow(current, e.Environment, e.Name, e.Sites, port(e.Ports.HTTP), port(e.Ports.HTTPS), port(e.Ports.API))
+			}
+
+			tbl.Print()
+
+			return nil
+		},
+	}
+}
+
+// port returns the port or a dash when the port is not set.
+func port(p int) string {
+	if p == 0 {
+		return "-"
+	}
+
+	return strconv.Itoa(p)
+}
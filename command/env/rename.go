@@ -0,0 +1,70 @@
+package env
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/spf13/cobra"
+
+	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/terminal"
+)
+
+var validName = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// renameCommand returns the command to change the name of the environment. The network and
+// proxy are named after the config file of the environment and the containers are named after
+// the sites, databases, and services, so only the name in the config changes and no
+// containers are recreated.
+func renameCommand(home string, output terminal.Outputer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rename [OLD] NEW",
+		Short: "Renames the environment.",
+		Example: `  # rename the environment
+  nitro env rename craft-dev
+
+  # rename the environment if it is named nitro-dev
+  nitro env rename nitro-dev craft-dev`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load(home)
+			if err != nil {
+				return err
+			}
+
+			name := args[len(args)-1]
+
+			// make sure the environment has the expected name
+			if len(args) == 2 && args[0] != cfg.Name {
+				return fmt.Errorf("the environment is named %q, not %q", cfg.Name, args[0])
+			}
+
+			if !validName.MatchString(name) {
+				return fmt.Errorf("the name %q must only contain lowercase letters, numbers, and dashes", name)
+			}
+
+			if name == cfg.Name {
+				output.Info(fmt.Sprintf("The environment is already named %s", name))
+
+				return nil
+			}
+
+			old := cfg.Name
+			cfg.Name = name
+
+			if err := cfg.Save(); err != nil {
+				return fmt.Errorf("unable to save the config, %w", err)
+			}
+
+			if old == "" {
+				output.Info(fmt.Sprintf("Named the environment %s", name))
+			} else {
+				output.Info(fmt.Sprintf("Renamed the environment %s to %s", old, name))
+			}
+
+			return nil
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,56 @@
+package env
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/terminal"
+)
+
+func TestRename(t *testing.T) {
+	home, err := ioutil.TempDir("", "nitro-env")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(home)
+
+	if err := os.MkdirAll(filepath.Join(home, config.DirectoryName), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	file := filepath.Join(home, config.DirectoryName, config.FileName)
+	if err := ioutil.WriteFile(file, []byte("name: nitro-dev\nsites:\n- hostname: craft.nitro\n  path: ~/dev/craft\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := renameCommand(home, terminal.New())
+
+	// the old name must match
+	if err := cmd.RunE(cmd, []string{"other", "craft-dev"}); err == nil {
+		t.Errorf("expected an error when the old name does not match")
+	}
+
+	if err := cmd.RunE(cmd, []string{"Craft Dev"}); err == nil {
+		t.Errorf("expected an error for an invalid name")
+	}
+
+	if err := cmd.RunE(cmd, []string{"nitro-dev", "craft-dev"}); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := config.Load(home)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.Name != "craft-dev" {
+		t.Errorf("expected the name to be craft-dev, got %q", cfg.Name)
+	}
+
+	if len(cfg.Sites) != 1 {
+		t.Errorf("expected the sites to be kept, got %v", cfg.Sites)
+	}
+}
@@ -0,0 +1,49 @@
+package env
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/environment"
+	"github.com/craftcms/nitro/pkg/pathexists"
+	"github.com/craftcms/nitro/pkg/terminal"
+)
+
+// useCommand returns the command to change the environment that is used when the
+// --environment flag and NITRO_ENVIRONMENT are not set.
+func useCommand(home string, output terminal.Outputer) *cobra.Command {
+	return &cobra.Command{
+		Use:   "use ENVIRONMENT",
+		Short: "Changes the default environment.",
+		Example: `  # use the client-a environment for the commands
+  nitro env use client-a
+
+  # go back to the default environment
+  nitro env use nitro`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+
+			if err := environment.Validate(name); err != nil {
+				return err
+			}
+
+			dir := filepath.Join(home, config.DirectoryName)
+
+			if name != environment.Default && !pathexists.IsFile(filepath.Join(dir, name+".yaml")) {
+				return fmt.Errorf("there is no config file for the environment %s, run `nitro init --environment %s` to create it", name, name)
+			}
+
+			if err := environment.Use(dir, name); err != nil {
+				return fmt.Errorf("unable to save the environment, %w", err)
+			}
+
+			output.Info(fmt.Sprintf("Using the environment %s", name))
+
+			return nil
+		},
+	}
+}
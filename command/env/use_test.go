@@ -0,0 +1,55 @@
+package env
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/environment"
+	"github.com/craftcms/nitro/pkg/terminal"
+)
+
+func TestUse(t *testing.T) {
+	defer func() { environment.Name = environment.Default }()
+
+	home, err := ioutil.TempDir("", "nitro-env")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(home)
+
+	dir := filepath.Join(home, config.DirectoryName)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := useCommand(home, terminal.New())
+
+	// the environment must have a config file
+	if err := cmd.RunE(cmd, []string{"client-a"}); err == nil {
+		t.Errorf("expected an error for an environment without a config file")
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "client-a.yaml"), []byte("name: client-a\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := cmd.RunE(cmd, []string{"client-a"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if name, _ := environment.Resolve(dir, ""); name != "client-a" {
+		t.Errorf("expected the environment to be client-a, got %q", name)
+	}
+
+	// the default environment does not need a config file
+	if err := cmd.RunE(cmd, []string{environment.Default}); err != nil {
+		t.Fatal(err)
+	}
+
+	if name, _ := environment.Resolve(dir, ""); name != environment.Default {
+		t.Errorf("expected the default environment, got %q", name)
+	}
+}
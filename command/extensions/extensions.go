@@ -12,6 +12,8 @@ import (
 
 	"github.com/craftcms/nitro/pkg/config"
 	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/environment"
+	"github.com/craftcms/nitro/pkg/phpextensions"
 	"github.com/craftcms/nitro/pkg/prompt"
 	"github.com/craftcms/nitro/pkg/terminal"
 )
@@ -50,7 +52,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 
 			// create a filter for the environment
 			filter := filters.NewArgs()
-			filter.Add("label", containerlabels.Nitro)
+			filter.Add("label", containerlabels.Nitro+"="+environment.Label())
 
 			// get a context aware list of sites
 			sites := cfg.ListOfSitesByDirectory(home, wd)
@@ -66,7 +68,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 			switch len(sites) {
 			case 0:
 				// prompt for the site to ssh into
-				selected, err := output.Select(cmd.InOrStdin(), "Select a site: ", options)
+				selected, err := prompt.Select(cmd.InOrStdin(), "Select a site: ", options, output)
 				if err != nil {
 					return err
 				}
@@ -80,7 +82,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 				filter.Add("label", containerlabels.Host+"="+sites[0].Hostname)
 			default:
 				// prompt for the site to ssh into
-				selected, err := output.Select(cmd.InOrStdin(), "Select a site: ", options)
+				selected, err := prompt.Select(cmd.InOrStdin(), "Select a site: ", options, output)
 				if err != nil {
 					return err
 				}
@@ -114,43 +116,10 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 			// set the hostname of the site based on the container name
 			hostname := strings.TrimLeft(containers[0].Names[0], "/")
 
-			extensions := []string{
-				"bcmath",
-				"bz2",
-				"calendar",
-				"dba",
-				"enchant",
-				"exif",
-				"gettext",
-				"gmp",
-				"imap",
-				"interbase",
-				"ldap",
-				"mysqli",
-				"oci8",
-				"odbc",
-				"pcntl",
-				"pdo_dblib",
-				"pdo_firebird",
-				"pdo_oci",
-				"pdo_odbc",
-				"pdo_sqlite",
-				"recode",
-				"shmop",
-				"snmp",
-				"sockets",
-				"sysvmsg",
-				"sysvsem",
-				"sysvshm",
-				"tidy",
-				"wddx",
-				"xmlrpc",
-				"xsl",
-				"zend_test",
-			}
+			extensions := phpextensions.Installable
 
 			// which extensions to add
-			selected, err := output.Select(cmd.InOrStdin(), "Which PHP extension would you like to enable for "+hostname+"? ", extensions)
+			selected, err := prompt.Select(cmd.InOrStdin(), "Which PHP extension would you like to enable for "+hostname+"? ", extensions, output)
 			if err != nil {
 				return err
 			}
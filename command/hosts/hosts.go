@@ -9,82 +9,165 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/craftcms/nitro/command/internal/service"
+	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/dockercontext"
+	"github.com/craftcms/nitro/pkg/environment"
 	"github.com/craftcms/nitro/pkg/hostedit"
+	"github.com/craftcms/nitro/pkg/sudo"
 	"github.com/craftcms/nitro/pkg/terminal"
 )
 
 const exampleText = `  # modify hosts file to match sites and aliases
-  nitro hosts`
+  nitro hosts
 
-// New returns a command used to modify the hosts file to point sites to the nitro proxy.
+  # show the changes without modifying the hosts file
+  nitro hosts --preview
+
+  # set specific hostnames
+  nitro hosts --hostnames=craft.nitro,www.craft.nitro`
+
+// New returns a command used to modify the hosts file to point sites to the nitro proxy. The
+// hostnames default to the sites, aliases, databases, services, and custom containers in the
+// config, and are kept in a block that is managed by nitro.
 func NewCommand(home string, output terminal.Outputer) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "hosts",
 		Short:   "Modifies hosts file.",
 		Example: exampleText,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			hosts := cmd.Flag("hostnames").Value.String()
-			var preview bool
-			previewFlag := cmd.Flag("preview").Value.String()
-			if previewFlag == "true" {
-				preview = true
+			hostnames, err := cmd.Flags().GetStringSlice("hostnames")
+			if err != nil {
+				return err
+			}
+
+			preview := cmd.Flag("preview").Value.String() == "true"
+
+			// point the hostnames to the machine running the docker daemon
+			address := cmd.Flag("address").Value.String()
+			if address == "" {
+				address = dockercontext.Current.Address()
+			}
+
+			// use the hostnames from the config
+			if len(hostnames) == 0 {
+				cfg, err := config.Load(home)
+				if err != nil {
+					return err
+				}
+
+				hostnames = Hostnames(cfg)
 			}
 
-			// remove [ and ] from the string
-			hosts = strings.Replace(hosts, "[", "", 1)
-			hosts = strings.Replace(hosts, "]", "", 1)
+			if len(hostnames) == 0 {
+				output.Info("There are no hostnames to add to the hosts file")
 
-			var hostnames []string
-			hostnames = append(hostnames, strings.Split(hosts, ",")...)
+				return nil
+			}
 
 			// set the file based on the OS
-			defaultFile := "/etc/hosts"
-			if runtime.GOOS == "windows" {
-				defaultFile = `C:\Windows\System32\Drivers\etc\hosts`
+			file := hostedit.File(runtime.GOOS)
+
+			original, err := ioutil.ReadFile(file)
+			if err != nil {
+				return err
 			}
 
 			// add the hosts
-			updated, err := hostedit.Update(defaultFile, "127.0.0.1", hostnames...)
+			updated, err := hostedit.Update(file, address, hostnames...)
 			if err != nil {
 				return err
 			}
 
-			// if we are previewing, show the hosts file without saving
-			if preview {
-				output.Info("Previewing changes to hosts file…\n")
-
-				output.Info(updated)
+			if updated == string(original) {
+				output.Info("The hosts file is up to date")
 
 				return nil
-			} else {
-				output.Info("Adding sites to hosts file…")
 			}
 
-			// check if we are the root user
-			uid := os.Geteuid()
-			if (uid != 0) && (uid != -1) {
-				return fmt.Errorf("you do not appear to be running this command as root, so we cannot modify your hosts file")
-			}
-
-			output.Pending("modifying hosts file")
-
-			// save the file
-			if err := ioutil.WriteFile(defaultFile, []byte(updated), 0644); err != nil {
-				return err
+			// if we are previewing, show the changes without saving
+			if preview {
+				return printChanges(cmd, file, string(original), updated)
 			}
 
-			output.Done()
+			output.Info("Adding sites to hosts file…")
 
-			return nil
+			return save(file, updated, []string{"--hostnames=" + strings.Join(hostnames, ","), "--address=" + address}, output)
 		},
 	}
 
 	// set flags for the command
-	cmd.Flags().StringSlice("hostnames", nil, "list of hostnames to set")
-	cmd.MarkFlagRequired("hostnames")
+	cmd.Flags().StringSlice("hostnames", nil, "list of hostnames to set, defaults to the hostnames in the config")
 	cmd.Flags().Bool("preview", false, "preview hosts file change")
+	cmd.Flags().String("address", "", "the IP address for the hostnames, defaults to 127.0.0.1 or the remote docker host")
 
 	cmd.AddCommand(removeCommand(home, output))
 
 	return cmd
 }
+
+// Hostnames returns the hostnames from the config that should point to the nitro proxy.
+func Hostnames(cfg *config.Config) []string {
+	hostnames := cfg.Hostnames()
+
+	for _, db := range cfg.Databases {
+		if h, err := db.GetHostname(); err == nil {
+			hostnames = append(hostnames, h)
+		}
+	}
+
+	return append(hostnames, service.Hostnames(cfg)...)
+}
+
+// printChanges shows the lines that would be removed and added to the hosts file.
+func printChanges(cmd *cobra.Command, file, original, updated string) error {
+	fmt.Fprintf(cmd.OutOrStdout(), "Previewing changes to %s…\n\n", file)
+
+	for _, c := range hostedit.Changes(original, updated) {
+		color := terminal.Green
+		if strings.HasPrefix(c, "-") {
+			color = terminal.Red
+		}
+
+		fmt.Fprintln(cmd.OutOrStdout(), terminal.Color(color, c))
+	}
+
+	return nil
+}
+
+// save writes the content to the hosts file. Modifying the hosts file requires elevated
+// permissions, so when nitro is not running as root the hosts command is run again with
+// sudo and the args. Windows users need to run nitro as an administrator.
+func save(file, content string, args []string, output terminal.Outputer) error {
+	uid := os.Geteuid()
+	if runtime.GOOS != "windows" && uid != 0 && uid != -1 {
+		nitro, err := os.Executable()
+		if err != nil {
+			return fmt.Errorf("unable to locate the nitro path, %w", err)
+		}
+
+		output.Info("Updating hosts file (you might be prompted for your password)")
+
+		if err := sudo.Run(nitro, append(append([]string{"nitro", "hosts"}, args...), environment.Args()...)...); err != nil {
+			return fmt.Errorf("unable to modify the hosts file with sudo, %w", err)
+		}
+
+		return nil
+	}
+
+	output.Pending("modifying hosts file")
+
+	if err := ioutil.WriteFile(file, []byte(content), 0644); err != nil {
+		output.Warning()
+
+		if os.IsPermission(err) && runtime.GOOS == "windows" {
+			return fmt.Errorf("unable to modify the hosts file, run the command in a terminal as an administrator")
+		}
+
+		return err
+	}
+
+	output.Done()
+
+	return nil
+}
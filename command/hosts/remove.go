@@ -2,9 +2,7 @@ package hosts
 
 import (
 	"errors"
-	"fmt"
 	"io/ioutil"
-	"os"
 	"runtime"
 
 	"github.com/craftcms/nitro/pkg/hostedit"
@@ -20,20 +18,18 @@ func removeCommand(home string, output terminal.Outputer) *cobra.Command {
 		Example: `  # remove nitro entries from your hosts file
   nitro hosts remove`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			var preview bool
-			previewFlag := cmd.Flag("preview").Value.String()
-			if previewFlag == "true" {
-				preview = true
-			}
+			preview := cmd.Flag("preview").Value.String() == "true"
 
 			// set the file based on the OS
-			defaultFile := "/etc/hosts"
-			if runtime.GOOS == "windows" {
-				defaultFile = `C:\Windows\System32\Drivers\etc\hosts`
+			file := hostedit.File(runtime.GOOS)
+
+			original, err := ioutil.ReadFile(file)
+			if err != nil {
+				return err
 			}
 
-			// add the hosts
-			updated, err := hostedit.Remove(defaultFile)
+			// remove the hosts
+			updated, err := hostedit.Remove(file)
 			if errors.Is(err, hostedit.ErrNotNitroEntries) {
 				output.Info("There are no entries to remove from the hosts file...")
 
@@ -43,33 +39,14 @@ func removeCommand(home string, output terminal.Outputer) *cobra.Command {
 				return err
 			}
 
-			// if we are previewing, show the hosts file without saving
+			// if we are previewing, show the changes without saving
 			if preview {
-				output.Info("Previewing changes to hosts file…\n")
-
-				output.Info(updated)
-
-				return nil
-			} else {
-				output.Info("Adding sites to hosts file…")
-			}
-
-			// check if we are the root user
-			uid := os.Geteuid()
-			if (uid != 0) && (uid != -1) {
-				return fmt.Errorf("you do not appear to be running this command as root, so we cannot modify your hosts file")
-			}
-
-			output.Pending("modifying hosts file")
-
-			// save the file
-			if err := ioutil.WriteFile(defaultFile, []byte(updated), 0644); err != nil {
-				return err
+				return printChanges(cmd, file, string(original), updated)
 			}
 
-			output.Done()
+			output.Info("Removing sites from hosts file…")
 
-			return nil
+			return save(file, updated, []string{"remove"}, output)
 		},
 	}
 
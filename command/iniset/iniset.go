@@ -13,6 +13,7 @@ import (
 
 	"github.com/craftcms/nitro/pkg/config"
 	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/environment"
 	"github.com/craftcms/nitro/pkg/prompt"
 	"github.com/craftcms/nitro/pkg/terminal"
 	"github.com/craftcms/nitro/pkg/validate"
@@ -66,7 +67,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 
 			// create a filter for the environment
 			filter := filters.NewArgs()
-			filter.Add("label", containerlabels.Nitro)
+			filter.Add("label", containerlabels.Nitro+"="+environment.Label())
 
 			// create the options for the sites
 			var options []string
@@ -94,7 +95,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 				switch len(sites) {
 				case 0:
 					// prompt for the site to ssh into
-					selected, err := output.Select(cmd.InOrStdin(), "Select a site: ", options)
+					selected, err := prompt.Select(cmd.InOrStdin(), "Select a site: ", options, output)
 					if err != nil {
 						return err
 					}
@@ -108,7 +109,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 					filter.Add("label", containerlabels.Host+"="+sites[0].Hostname)
 				default:
 					// prompt for the site to ssh into
-					selected, err := output.Select(cmd.InOrStdin(), "Select a site: ", options)
+					selected, err := prompt.Select(cmd.InOrStdin(), "Select a site: ", options, output)
 					if err != nil {
 						return err
 					}
@@ -158,7 +159,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 			}
 
 			// which setting to change
-			selected, err := output.Select(cmd.InOrStdin(), "Which PHP setting would you like to change for "+hostname+"?", settings)
+			selected, err := prompt.Select(cmd.InOrStdin(), "Which PHP setting would you like to change for "+hostname+"?", settings, output)
 			if err != nil {
 				return err
 			}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"path/filepath"
 	"strings"
 
 	"github.com/docker/docker/api/types"
@@ -11,20 +13,41 @@ import (
 	"github.com/docker/docker/client"
 	"github.com/spf13/cobra"
 
+	"github.com/craftcms/nitro/command/apply"
+	"github.com/craftcms/nitro/command/trust"
 	"github.com/craftcms/nitro/pkg/config"
 	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/environment"
+	"github.com/craftcms/nitro/pkg/health"
 	"github.com/craftcms/nitro/pkg/proxycontainer"
 	"github.com/craftcms/nitro/pkg/setup"
 	"github.com/craftcms/nitro/pkg/terminal"
+	"github.com/craftcms/nitro/protob"
 )
 
 const exampleText = `  # setup nitro
-  nitro init`
+  nitro init
 
-var skipApply, skipTrust bool
+  # setup nitro and show the result as JSON
+  nitro init --json
+
+  # setup another environment with its own network and proxy
+  nitro init --environment client-a
+
+  # use the next free ports when the ports in the config are in use
+  nitro init --auto-ports`
+
+var skipApply, skipTrust, autoPorts bool
+
+// result is the JSON output of the command.
+type result struct {
+	Network string `json:"network"`
+	Applied bool   `json:"applied"`
+	Trusted bool   `json:"trusted"`
+}
 
 // NewCommand takes a docker client and returns the init command for creating a new environment
-func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outputer) *cobra.Command {
+func NewCommand(home string, docker client.CommonAPIClient, nitrod protob.NitroClient, output terminal.Outputer) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:           "init",
 		Short:         "Performs Nitro’s initial setup.",
@@ -39,103 +62,148 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 			return nil
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			ctx := cmd.Context()
-			if ctx == nil {
-				ctx = context.Background()
-			}
+			return Run(cmd.Context(), home, docker, nitrod, cmd.InOrStdin(), output)
+		},
+	}
 
-			// check if there is a config file
-			_, err := config.Load(home)
-			if errors.Is(err, config.ErrNoConfigFile) {
-				// walk the user through the first time setup
-				if err := setup.FirstTime(home, cmd.InOrStdin(), output); err != nil {
-					return err
-				}
-			}
+	// set flags for the command
+	cmd.Flags().BoolVar(&skipApply, "skip-apply", false, "skip applying changes")
+	cmd.Flags().BoolVar(&skipTrust, "skip-trust", false, "skip trusting the root certificate")
+	cmd.Flags().BoolVar(&autoPorts, "auto-ports", false, "use the next free port for each host port that is in use")
 
-			output.Info("Checking Nitro…")
+	return cmd
+}
 
-			// create filters for the development environment
-			filter := filters.NewArgs()
-			filter.Add("name", "nitro-network")
+// Run creates the network and proxy for the environment, then applies the config and trusts the
+// root certificate unless they are skipped. When there is no config file, the first time setup
+// reads the answers from the input. It is also used when nitro runs for the first time.
+func Run(ctx context.Context, home string, docker client.CommonAPIClient, nitrod protob.NitroClient, input io.Reader, output terminal.Outputer) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
 
-			// check if the network needs to be created
-			networks, err := docker.NetworkList(ctx, types.NetworkListOptions{Filters: filter})
-			if err != nil {
-				return fmt.Errorf("unable to list the docker networks, %w", err)
-			}
+	// check if there is a config file
+	cfg, err := config.Load(home)
+	if errors.Is(err, config.ErrNoConfigFile) {
+		// walk the user through the first time setup
+		if err := setup.FirstTime(home, input, output); err != nil {
+			return err
+		}
 
-			// since the filter is fuzzy, do an exact match (e.g. filtering for
-			// `nitro-dev` will also return `nitro-dev-host`
-			var skipNetwork bool
-			var networkID string
-			for _, n := range networks {
-				if n.Name == "nitro-network" || strings.TrimLeft(n.Name, "/") == "nitro-network" {
-					skipNetwork = true
-					networkID = n.ID
-				}
-			}
+		cfg, err = config.Load(home)
+	}
 
-			// create the network needs to be created
-			switch skipNetwork {
-			case true:
-				output.Success("network ready")
-			default:
-				output.Pending("creating network")
-
-				resp, err := docker.NetworkCreate(ctx, "nitro-network", types.NetworkCreate{
-					Driver:     "bridge",
-					Attachable: true,
-					Labels: map[string]string{
-						containerlabels.Nitro:   "true",
-						containerlabels.Network: "true",
-					},
-				})
-				if err != nil {
-					return fmt.Errorf("unable to create the network, %w", err)
-				}
-
-				// set the newly created network
-				networkID = resp.ID
-
-				output.Done()
-			}
+	// list every host port that is in use before creating the proxy
+	if err == nil {
+		if err := apply.CheckPorts(ctx, docker, cfg, autoPorts, output); err != nil {
+			return err
+		}
+	}
 
-			// create the proxy container
-			if err := proxycontainer.Create(cmd.Context(), docker, output, networkID); err != nil {
-				return err
-			}
+	output.Info("Checking Nitro…")
 
-			// run the follow up commands
-			for _, c := range cmd.Root().Commands() {
-				// should we run the apply command
-				if !skipApply {
-					if c.Use == "apply" {
-						if err := c.RunE(c, args); err != nil {
-							return err
-						}
-					}
-				}
-
-				if !skipTrust {
-					// should we run the trust command
-					if c.Use == "trust" {
-						if err := c.RunE(c, args); err != nil {
-							return err
-						}
-					}
-				}
-			}
+	// create filters for the development environment
+	filter := filters.NewArgs()
+	filter.Add("name", environment.Network())
 
-			output.Info("Nitro is ready! 🚀")
+	// check if the network needs to be created
+	networks, err := docker.NetworkList(ctx, types.NetworkListOptions{Filters: filter})
+	if err != nil {
+		return fmt.Errorf("unable to list the docker networks, %w", err)
+	}
 
-			return nil
-		},
+	// since the filter is fuzzy, do an exact match (e.g. filtering for
+	// `nitro-network` will also return `nitro-network-host`
+	var skipNetwork bool
+	var networkID string
+	for _, n := range networks {
+		if n.Name == environment.Network() || strings.TrimLeft(n.Name, "/") == environment.Network() {
+			skipNetwork = true
+			networkID = n.ID
+		}
 	}
 
-	// set flags for the command
-	cmd.Flags().BoolVar(&skipApply, "skip-apply", false, "skip applying changes")
-	cmd.Flags().BoolVar(&skipTrust, "skip-trust", false, "skip trusting the root certificate")
+	// create the network needs to be created
+	switch skipNetwork {
+	case true:
+		output.Success("network ready")
+	default:
+		output.Pending("creating network")
+
+		resp, err := docker.NetworkCreate(ctx, environment.Network(), types.NetworkCreate{
+			Driver:     "bridge",
+			Attachable: true,
+			Labels: map[string]string{
+				containerlabels.Nitro:   environment.Label(),
+				containerlabels.Network: "true",
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("unable to create the network, %w", err)
+		}
+
+		// set the newly created network
+		networkID = resp.ID
+
+		output.Done()
+	}
 
-	return cmd
+	// create the proxy container
+	if err := proxycontainer.Create(ctx, docker, output, networkID, filepath.Join(home, config.DirectoryName)); err != nil {
+		return err
+	}
+
+	if skipApply && skipTrust {
+		// apply waits for the containers, so only the proxy is left to wait for
+		spinner := terminal.NewSpinner("waiting for proxy to be healthy")
+		if err := health.Wait(ctx, docker, []string{environment.Proxy()}, health.Timeout, nil); err != nil {
+			spinner.Stop(false)
+			return err
+		}
+
+		spinner.Stop(true)
+
+		return ready(networkID, output)
+	}
+
+	// wait for the proxy before applying changes or trusting the certificate
+	output.Pending("waiting for proxy")
+
+	if err := proxycontainer.WaitForAPI(ctx, docker, nitrod, proxycontainer.Timeout); err != nil {
+		output.Warning()
+		return err
+	}
+
+	output.Done()
+
+	// should we apply the config
+	if !skipApply {
+		if err := apply.Run(ctx, home, docker, nitrod, output, false, autoPorts); err != nil {
+			return err
+		}
+	}
+
+	// should we trust the root certificate
+	if !skipTrust {
+		if err := trust.Run(ctx, home, docker, nitrod, output, false); err != nil {
+			return err
+		}
+	}
+
+	return ready(networkID, output)
+}
+
+// ready shows that the environment is ready, or the result when the output is JSON.
+func ready(networkID string, output terminal.Outputer) error {
+	if terminal.JSON {
+		return output.JSON(result{Network: networkID, Applied: !skipApply, Trusted: !skipTrust})
+	}
+
+	if !environment.IsDefault() {
+		output.Info(fmt.Sprintf("The %s environment uses port %s for HTTP and %s for HTTPS", environment.Name, environment.Port("NITRO_HTTP_PORT", environment.Ports.HTTP), environment.Port("NITRO_HTTPS_PORT", environment.Ports.HTTPS)))
+	}
+
+	output.Info("Nitro is ready! 🚀")
+
+	return nil
 }
@@ -43,6 +43,10 @@ func (spy spyOutputer) Warning() {
 
 }
 
+func (spy spyOutputer) JSON(v interface{}) error {
+	return nil
+}
+
 func (spy spyOutputer) Success(s ...string) {
 	fmt.Printf("  \u2713 %s\n", strings.Join(s, " "))
 }
@@ -151,6 +155,12 @@ func (c *mockDockerClient) ContainerStart(ctx context.Context, container string,
 	return c.mockError
 }
 
+func (c *mockDockerClient) ContainerInspect(ctx context.Context, containerID string) (types.ContainerJSON, error) {
+	state := &types.ContainerState{Status: "running", Running: true, Health: &types.Health{Status: types.Healthy}}
+
+	return types.ContainerJSON{ContainerJSONBase: &types.ContainerJSONBase{Name: "/" + containerID, State: state}}, c.mockError
+}
+
 func (c *mockDockerClient) ContainerRestart(ctx context.Context, container string, timeout *time.Duration) error {
 	c.containerRestartRequests = append(c.containerRestartRequests, container)
 	return c.mockError
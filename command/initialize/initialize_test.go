@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/proxycontainer"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/mount"
@@ -70,10 +71,12 @@ func TestInitFromFreshCreatesNewResources(t *testing.T) {
 				containerlabels.Proxy:        "true",
 				containerlabels.ProxyVersion: "develop",
 			},
-			Env: []string{"PGPASSWORD=nitro", "PGUSER=nitro", "NITRO_VERSION=develop"},
+			Env:         []string{"PGPASSWORD=nitro", "PGUSER=nitro", "NITRO_VERSION=develop"},
+			Healthcheck: proxycontainer.HealthCheck(),
 		},
 		HostConfig: &container.HostConfig{
 			NetworkMode: "default",
+			ExtraHosts:  proxycontainer.ExtraHosts(),
 			Mounts: []mount.Mount{
 				{
 					Type:   mount.TypeVolume,
@@ -127,7 +130,9 @@ func TestInitFromFreshCreatesNewResources(t *testing.T) {
 	containerStartRequest := types.ContainerStartOptions{}
 
 	// Act
-	cmd := NewCommand(home, mock, spyOutputer{})
+	cmd := NewCommand(home, mock, nil, spyOutputer{})
+	cmd.Flags().Set("skip-apply", "true")
+	cmd.Flags().Set("skip-trust", "true")
 	err := cmd.RunE(cmd, os.Args)
 
 	// Assert
@@ -0,0 +1,225 @@
+package backupcontainer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/client"
+
+	"github.com/craftcms/nitro/command/internal/match"
+	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/database"
+	"github.com/craftcms/nitro/pkg/environment"
+)
+
+const (
+	// Name is the name of the container that backs up the databases on a schedule
+	Name = "backups.service.nitro"
+
+	// Image has the docker CLI and crond, the backups run the dump tools in the database
+	// containers so the tools always match the version of the database
+	Image = "docker.io/library/docker:cli"
+
+	// Label is the type label of the backups container
+	Label = "backups"
+
+	// Socket is the docker socket mounted into the container to run the dump tools
+	Socket = "/var/run/docker.sock"
+)
+
+// setup writes the script and the crontab when the container starts and runs crond in the
+// foreground, so the output of the backups is in the logs of the container.
+const setup = `printf '%s\n' "$NITRO_BACKUP_SCRIPT" > /usr/local/bin/nitro-backup && chmod +x /usr/local/bin/nitro-backup && echo "$NITRO_BACKUP_SCHEDULE /usr/local/bin/nitro-backup" > /etc/crontabs/root && exec crond -f -l 8`
+
+// Dir returns the directory on the host the scheduled backups are saved in, each database
+// container has a directory with the backups of its databases.
+func Dir(home string) string {
+	return filepath.Join(home, config.DirectoryName, "backups", "scheduled")
+}
+
+// Script returns the shell script that backs up every database in each database container
+// in the config. Only the newest backups of each database are kept when the config sets
+// the number of backups to keep.
+func Script(cfg *config.Config) string {
+	b := &strings.Builder{}
+
+	b.WriteString(`#!/bin/sh
+stamp=$(date +%Y-%m-%d-%H%M%S)
+
+# save runs the dump command in the container and writes the backup of the database
+save() {
+	container=$1 db=$2
+	shift 2
+	mkdir -p "/backups/$container"
+	file="/backups/$container/$db-$stamp.sql"
+	if docker exec "$container" "$@" > "$file" 2>/dev/null; then
+		echo "backed up $db in $container"
+	else
+		rm -f "$file"
+		echo "unable to back up $db in $container"
+	fi
+`)
+
+	if cfg.Backups.Keep > 0 {
+		fmt.Fprintf(b, "\tls -1t \"/backups/$container/$db\"-[0-9]*.sql 2>/dev/null | tail -n +%d | xargs -r rm -f\n", cfg.Backups.Keep+1)
+	}
+
+	b.WriteString("}\n")
+
+	for _, db := range cfg.Databases {
+		h, err := db.GetHostname()
+		if err != nil {
+			continue
+		}
+
+		b.WriteString("\n")
+
+		switch db.Engine {
+		case "postgres":
+			fmt.Fprintf(b, `for db in $(docker exec %s psql --username=nitro -At --command "SELECT datname FROM pg_database WHERE datistemplate = false;" 2>/dev/null); do
+	save %s "$db" pg_dump --username=nitro "$db"
+done
+`, h, h)
+		default:
+			fmt.Fprintf(b, `for db in $(docker exec %s %s -unitro -pnitro -N -e "SHOW DATABASES;" 2>/dev/null | grep -Ev "^(information_schema|mysql|performance_schema|sys)$"); do
+	save %s "$db" %s -unitro -pnitro "$db"
+done
+`, h, database.ClientCommand(db.Engine, db.Version), h, database.DumpCommand(db.Engine, db.Version))
+		}
+	}
+
+	return b.String()
+}
+
+// Env returns the environment of the container, it contains the schedule and the script.
+func Env(cfg *config.Config) []string {
+	return []string{
+		"NITRO_BACKUP_SCHEDULE=" + cfg.Backups.Schedule,
+		"NITRO_BACKUP_SCRIPT=" + Script(cfg),
+	}
+}
+
+// Changes returns the differences between the backups container and the config.
+func Changes(cfg *config.Config, details types.ContainerJSON) []match.Change {
+	env := map[string]string{}
+	if details.Config != nil {
+		for _, e := range details.Config.Env {
+			if parts := strings.SplitN(e, "=", 2); len(parts) == 2 {
+				env[parts[0]] = parts[1]
+			}
+		}
+	}
+
+	var changes []match.Change
+	if env["NITRO_BACKUP_SCHEDULE"] != cfg.Backups.Schedule {
+		changes = append(changes, match.Change{Name: "schedule", Expected: cfg.Backups.Schedule, Actual: env["NITRO_BACKUP_SCHEDULE"]})
+	}
+
+	if env["NITRO_BACKUP_SCRIPT"] != Script(cfg) {
+		changes = append(changes, match.Change{Name: "databases", Expected: "updated backup script", Actual: "previous backup script"})
+	}
+
+	return changes
+}
+
+// Reconcile creates the backups container when the config has a backup schedule and
+// recreates it when the schedule or the databases change. The container is removed when
+// the config does not have a schedule, the backups on the host are kept.
+func Reconcile(ctx context.Context, docker client.CommonAPIClient, home, networkID string, cfg *config.Config) error {
+	filter := filters.NewArgs()
+	filter.Add("label", containerlabels.Nitro+"="+environment.Label())
+	filter.Add("label", containerlabels.Type+"="+Label)
+
+	containers, err := docker.ContainerList(ctx, types.ContainerListOptions{All: true, Filters: filter})
+	if err != nil {
+		return fmt.Errorf("unable to list the backups container, %w", err)
+	}
+
+	for _, c := range containers {
+		if cfg.Backups.Enabled() {
+			details, err := docker.ContainerInspect(ctx, c.ID)
+			if err != nil {
+				return fmt.Errorf("unable to inspect the backups container, %w", err)
+			}
+
+			if len(Changes(cfg, details)) == 0 {
+				if c.State == "running" {
+					return nil
+				}
+
+				return docker.ContainerStart(ctx, c.ID, types.ContainerStartOptions{})
+			}
+		}
+
+		if err := docker.ContainerRemove(ctx, c.ID, types.ContainerRemoveOptions{Force: true}); err != nil {
+			return fmt.Errorf("unable to remove the backups container, %w", err)
+		}
+	}
+
+	if !cfg.Backups.Enabled() {
+		return nil
+	}
+
+	if err := os.MkdirAll(Dir(home), 0755); err != nil {
+		return fmt.Errorf("unable to create the backups directory, %w", err)
+	}
+
+	// pull the image
+	r, err := docker.ImagePull(ctx, Image, types.ImagePullOptions{})
+	if err != nil {
+		return fmt.Errorf("unable to pull the image %s, %w", Image, err)
+	}
+
+	buf := &bytes.Buffer{}
+	if _, err := buf.ReadFrom(r); err != nil {
+		return fmt.Errorf("unable to read output while pulling image, %w", err)
+	}
+
+	resp, err := docker.ContainerCreate(
+		ctx,
+		&container.Config{
+			Image:      Image,
+			Entrypoint: []string{"sh", "-c"},
+			Cmd:        []string{setup},
+			Env:        Env(cfg),
+			Labels: map[string]string{
+				containerlabels.Nitro: environment.Label(),
+				containerlabels.Type:  Label,
+			},
+		},
+		&container.HostConfig{
+			Mounts: []mount.Mount{
+				{Type: mount.TypeBind, Source: Socket, Target: Socket},
+				{Type: mount.TypeBind, Source: Dir(home), Target: "/backups"},
+			},
+		},
+		&network.NetworkingConfig{
+			EndpointsConfig: map[string]*network.EndpointSettings{
+				environment.Network(): {
+					NetworkID: networkID,
+				},
+			},
+		},
+		nil,
+		Name,
+	)
+	if err != nil {
+		return fmt.Errorf("unable to create the backups container, %w", err)
+	}
+
+	if err := docker.ContainerStart(ctx, resp.ID, types.ContainerStartOptions{}); err != nil {
+		return fmt.Errorf("unable to start the backups container, %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,62 @@
+package backupcontainer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+
+	"github.com/craftcms/nitro/pkg/config"
+)
+
+func TestScript(t *testing.T) {
+	cfg := &config.Config{
+		Backups: config.Backups{Schedule: "0 */6 * * *", Keep: 3},
+		Databases: []config.Database{
+			{Engine: "mysql", Version: "8.0", Port: "3306"},
+			{Engine: "mariadb", Version: "10.6", Port: "3307"},
+			{Engine: "postgres", Version: "14", Port: "5432"},
+		},
+	}
+
+	got := Script(cfg)
+
+	for _, want := range []string{
+		`docker exec mysql-8.0-3306.database.nitro mysql -unitro -pnitro -N -e "SHOW DATABASES;"`,
+		`save mysql-8.0-3306.database.nitro "$db" mysqldump -unitro -pnitro "$db"`,
+		`docker exec mariadb-10.6-3307.database.nitro mariadb -unitro -pnitro -N -e "SHOW DATABASES;"`,
+		`save mariadb-10.6-3307.database.nitro "$db" mariadb-dump -unitro -pnitro "$db"`,
+		`save postgres-14-5432.database.nitro "$db" pg_dump --username=nitro "$db"`,
+		`tail -n +4 | xargs -r rm -f`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected the script to contain %q, got:\n%s", want, got)
+		}
+	}
+
+	cfg.Backups.Keep = 0
+	if strings.Contains(Script(cfg), "xargs -r rm") {
+		t.Errorf("expected every backup to be kept when keep is not set")
+	}
+}
+
+func TestChanges(t *testing.T) {
+	cfg := &config.Config{
+		Backups:   config.Backups{Schedule: "0 0 * * *"},
+		Databases: []config.Database{{Engine: "mysql", Version: "8.0", Port: "3306"}},
+	}
+
+	details := types.ContainerJSON{Config: &container.Config{Env: Env(cfg)}}
+	if changes := Changes(cfg, details); len(changes) != 0 {
+		t.Errorf("expected no changes, got %v", changes)
+	}
+
+	cfg.Backups.Schedule = "0 12 * * *"
+	cfg.Databases = append(cfg.Databases, config.Database{Engine: "postgres", Version: "14", Port: "5432"})
+
+	changes := Changes(cfg, details)
+	if len(changes) != 2 || changes[0].Name != "schedule" || changes[1].Name != "databases" {
+		t.Errorf("expected the schedule and databases to change, got %v", changes)
+	}
+}
@@ -6,12 +6,15 @@ import (
 	"fmt"
 	"io/ioutil"
 	"path/filepath"
+	"strconv"
 	"strings"
 
-	"github.com/craftcms/nitro/command/apply/internal/match"
+	"github.com/craftcms/nitro/command/internal/match"
 	"github.com/craftcms/nitro/pkg/config"
 	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/environment"
 	"github.com/craftcms/nitro/pkg/pathexists"
+	"github.com/craftcms/nitro/pkg/reconcile"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/filters"
@@ -24,10 +27,13 @@ import (
 
 const Suffix = ".containers.nitro"
 
-func StartOrCreate(ctx context.Context, docker client.CommonAPIClient, home, networkID string, c config.Container) (hostname string, err error) {
+// StartOrCreate makes sure each of the replicas for a custom container are running and match the
+// config. Replicas that are no longer needed are removed. It returns the ID of the first replica.
+// The log config is set when a replica is created.
+func StartOrCreate(ctx context.Context, docker client.CommonAPIClient, home, networkID string, c config.Container, logs container.LogConfig) (hostname string, err error) {
 	// set filters for the container
 	filter := filters.NewArgs()
-	filter.Add("label", containerlabels.Nitro+"=true")
+	filter.Add("label", containerlabels.Nitro+"="+environment.Label())
 	filter.Add("label", containerlabels.NitroContainer+"="+c.Name)
 
 	// look for a container for the site
@@ -36,23 +42,69 @@ func StartOrCreate(ctx context.Context, docker client.CommonAPIClient, home, net
 		return "", fmt.Errorf("error getting a list of containers")
 	}
 
-	// if there are no containers we need to create one
-	if len(containers) == 0 {
-		return create(ctx, docker, home, networkID, c)
+	// make sure existing containers are started and on the network
+	containers, err = reconcile.Containers(ctx, docker, networkID, containers)
+	if err != nil {
+		return "", err
 	}
 
-	// there is a container, so inspect it and make sure it matched
-	container := containers[0]
+	// group the containers by the replica and remove the replicas that are not needed
+	replicas := make(map[int]types.Container)
+	for _, container := range containers {
+		n := Replica(container.Labels)
+		if n <= c.GetReplicas() {
+			replicas[n] = container
+			continue
+		}
+
+		if err := docker.ContainerStop(ctx, container.ID, nil); err != nil {
+			return "", err
+		}
 
-	// start the container if not running
-	if container.State != "running" {
-		if err := docker.ContainerStart(ctx, container.ID, types.ContainerStartOptions{}); err != nil {
+		if err := docker.ContainerRemove(ctx, container.ID, types.ContainerRemoveOptions{}); err != nil {
 			return "", err
 		}
 	}
 
+	var id string
+	for n := 1; n <= c.GetReplicas(); n++ {
+		var existing *types.Container
+		if container, ok := replicas[n]; ok {
+			existing = &container
+		}
+
+		replicaID, err := startOrCreateReplica(ctx, docker, home, networkID, c, logs, n, existing)
+		if err != nil {
+			return "", err
+		}
+
+		if n == 1 {
+			id = replicaID
+		}
+	}
+
+	return id, nil
+}
+
+// Replica returns the replica number from the labels, containers created
+// before replicas were supported do not have the label and are the first.
+func Replica(labels map[string]string) int {
+	n, err := strconv.Atoi(labels[containerlabels.Replica])
+	if err != nil || n < 1 {
+		return 1
+	}
+
+	return n
+}
+
+func startOrCreateReplica(ctx context.Context, docker client.CommonAPIClient, home, networkID string, c config.Container, logs container.LogConfig, n int, existing *types.Container) (string, error) {
+	// if there are no containers we need to create one
+	if existing == nil {
+		return create(ctx, docker, home, networkID, c, logs, n)
+	}
+
 	// get the containers details that include environment variables
-	details, err := docker.ContainerInspect(ctx, container.ID)
+	details, err := docker.ContainerInspect(ctx, existing.ID)
 	if err != nil {
 		return "", err
 	}
@@ -63,22 +115,22 @@ func StartOrCreate(ctx context.Context, docker client.CommonAPIClient, home, net
 		fmt.Print("- updating… ")
 
 		// stop container
-		if err := docker.ContainerStop(ctx, container.ID, nil); err != nil {
+		if err := docker.ContainerStop(ctx, existing.ID, nil); err != nil {
 			return "", err
 		}
 
 		// remove container
-		if err := docker.ContainerRemove(ctx, container.ID, types.ContainerRemoveOptions{}); err != nil {
+		if err := docker.ContainerRemove(ctx, existing.ID, types.ContainerRemoveOptions{}); err != nil {
 			return "", err
 		}
 
-		return create(ctx, docker, home, networkID, c)
+		return create(ctx, docker, home, networkID, c, logs, n)
 	}
 
-	return container.ID, nil
+	return existing.ID, nil
 }
 
-func create(ctx context.Context, docker client.CommonAPIClient, home, networkID string, c config.Container) (string, error) {
+func create(ctx context.Context, docker client.CommonAPIClient, home, networkID string, c config.Container, logs container.LogConfig, replica int) (string, error) {
 	// create the container
 	image := fmt.Sprintf("%s:%s", c.Image, c.Tag)
 
@@ -118,6 +170,11 @@ func create(ctx context.Context, docker client.CommonAPIClient, home, networkID
 
 	labels := containerlabels.ForCustomContainer(c)
 
+	// number the replicas after the first
+	if replica > 1 {
+		labels[containerlabels.Replica] = strconv.Itoa(replica)
+	}
+
 	config := &container.Config{
 		Image:  image,
 		Labels: labels,
@@ -132,7 +189,7 @@ func create(ctx context.Context, docker client.CommonAPIClient, home, networkID
 	if len(c.Volumes) > 0 {
 		for _, v := range c.Volumes {
 			// generate the volume name
-			name := fmt.Sprintf("nitro_%s_%s", c.Name, strings.Replace(v, "/", "_", -1))
+			name := c.GetVolumeName(v)
 
 			// filter for the volume
 			volFilter := filters.NewArgs()
@@ -196,16 +253,19 @@ func create(ctx context.Context, docker client.CommonAPIClient, home, networkID
 		&container.HostConfig{
 			Mounts:       mounts,
 			PortBindings: portBindings,
+			LogConfig:    logs,
 		},
 		&network.NetworkingConfig{
 			EndpointsConfig: map[string]*network.EndpointSettings{
-				"nitro-network": {
+				environment.Network(): {
 					NetworkID: networkID,
+					// every replica answers to the containers hostname
+					Aliases: []string{c.Name + Suffix},
 				},
 			},
 		},
 		nil,
-		fmt.Sprintf("%s%s", c.Name, Suffix),
+		c.GetReplicaHostname(replica),
 	)
 	if err != nil {
 		return "", fmt.Errorf("unable to create the container, %w", err)
@@ -0,0 +1,526 @@
+package databasecontainer
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/containerexec"
+	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/database"
+	"github.com/craftcms/nitro/pkg/environment"
+	"github.com/craftcms/nitro/pkg/reconcile"
+	"github.com/craftcms/nitro/pkg/terminal"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/network"
+	volumetypes "github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/docker/go-connections/nat"
+	_ "github.com/go-sql-driver/mysql"
+)
+
+var (
+	// DatabaseImage is used for determining the engine and version
+	DatabaseImage = "%s:%s"
+)
+
+// StartOrCreate is used to find a specific database and start the container. If there is no container for the database,
+// it will create a new volume and container for the database.
+func StartOrCreate(ctx context.Context, docker client.CommonAPIClient, networkID string, db config.Database, logs container.LogConfig, output terminal.Outputer) (string, string, error) {
+	// create the filters for the database
+	filter := filters.NewArgs()
+	filter.Add("label", containerlabels.Nitro+"="+environment.Label())
+	filter.Add("label", containerlabels.DatabaseEngine+"="+db.Engine)
+	filter.Add("label", containerlabels.DatabaseVersion+"="+db.Version)
+	filter.Add("label", containerlabels.DatabasePort+"="+db.Port)
+	filter.Add("label", containerlabels.Type+"=database")
+
+	hostname, err := db.GetHostname()
+	if err != nil {
+		return "", "", err
+	}
+
+	// set the container database compatibility
+	if db.Engine == "mariadb" || db.Engine == "mysql" {
+		filter.Add("label", containerlabels.DatabaseCompatibility+"=mysql")
+	} else {
+		filter.Add("label", containerlabels.DatabaseCompatibility+"=postgres")
+	}
+
+	// get the containers for the database
+	containers, err := docker.ContainerList(ctx, types.ContainerListOptions{All: true, Filters: filter})
+	if err != nil {
+		return "", "", fmt.Errorf("error getting a list of containers")
+	}
+
+	// make sure existing containers are started and on the network
+	containers, err = reconcile.Containers(ctx, docker, networkID, containers)
+	if err != nil {
+		return "", "", err
+	}
+
+	// if there is a container, we should return it
+	if len(containers) == 1 {
+
+		// make sure the roles and extensions exist for postgres
+		if err := configurePostgres(ctx, docker, containers[0].ID, db); err != nil {
+			return "", "", err
+		}
+
+		return containers[0].ID, hostname, nil
+	}
+
+	// create the database labels for the new container
+	labels := map[string]string{
+		containerlabels.Nitro:           environment.Label(),
+		containerlabels.DatabaseEngine:  db.Engine,
+		containerlabels.DatabaseVersion: db.Version,
+		containerlabels.Type:            "database",
+		containerlabels.DatabasePort:    db.Port,
+	}
+
+	// if the database is mysql or mariadb, mark them as
+	// mysql compatible (used for importing backups)
+	if db.Engine == "mariadb" || db.Engine == "mysql" {
+		labels[containerlabels.DatabaseCompatibility] = "mysql"
+	}
+
+	// if the database is postgres, mark it as compatible
+	// with postgres. This is not needed but a place holder
+	// if cockroachdb is ever supported by craft.
+	if db.Engine == "postgres" {
+		labels[containerlabels.DatabaseCompatibility] = "postgres"
+	}
+
+	// create the volume
+	volume, err := docker.VolumeCreate(ctx, volumetypes.VolumeCreateBody{Driver: "local", Name: hostname, Labels: labels})
+	if err != nil {
+		return "", "", fmt.Errorf("unable to create the volume, %w", err)
+	}
+
+	// determine the image name
+	image := fmt.Sprintf(DatabaseImage, db.Engine, db.Version)
+
+	// set mounts and environment based on the database type
+	target := "/var/lib/mysql"
+	var envs []string
+	if strings.Contains(image, "postgres") {
+		target = "/var/lib/postgresql/data"
+		envs = []string{"POSTGRES_USER=nitro", "POSTGRES_DB=nitro", "POSTGRES_PASSWORD=nitro"}
+	} else {
+		envs = []string{"MYSQL_ROOT_PASSWORD=nitro", "MYSQL_DATABASE=nitro", "MYSQL_USER=nitro", "MYSQL_PASSWORD=nitro"}
+	}
+
+	// check if there is an image
+
+	// filter for the image ref
+	imageFilter := filters.NewArgs()
+	imageFilter.Add("reference", image)
+
+	// look for the image
+	images, err := docker.ImageList(ctx, types.ImageListOptions{Filters: imageFilter, All: true})
+	if err != nil {
+		return "", "", fmt.Errorf("unable to get a list of images, %w", err)
+	}
+
+	// if there are no images, pull one
+	if len(images) == 0 {
+		output.Pending("downloading", image)
+
+		// pull the image
+		rdr, err := docker.ImagePull(ctx, image, types.ImagePullOptions{All: false})
+		if err != nil {
+			output.Warning()
+
+			return "", "", fmt.Errorf("unable to pull image %s, %w", image, err)
+		}
+
+		// read the output to pull the image
+		buf := &bytes.Buffer{}
+		if _, err := buf.ReadFrom(rdr); err != nil {
+			output.Warning()
+			return "", "", fmt.Errorf("unable to read output from pulling image %s, %w", image, err)
+		}
+	}
+
+	// get the default port for the database
+	var port nat.Port
+	switch db.Engine {
+	case "postgres":
+		port, err = nat.NewPort("tcp", "5432")
+		if err != nil {
+			return "", "", fmt.Errorf("unable to create the port, %w", err)
+		}
+	default:
+		port, err = nat.NewPort("tcp", "3306")
+		if err != nil {
+			return "", "", fmt.Errorf("unable to create the port, %w", err)
+		}
+	}
+
+	containerConfig := &container.Config{
+		Image:  image,
+		Labels: labels,
+		ExposedPorts: nat.PortSet{
+			port: struct{}{},
+		},
+		Env: envs,
+		Healthcheck: &container.HealthConfig{
+			Test:        database.HealthCheck(db.Engine, db.Version),
+			Interval:    5 * time.Second,
+			Timeout:     5 * time.Second,
+			StartPeriod: 10 * time.Second,
+			Retries:     12,
+		},
+	}
+
+	// if the mysql or mariadb engine is being used, override the cmd
+	if db.Engine == "mysql" || db.Engine == "mariadb" {
+		containerConfig.Cmd = []string{"--character-set-server=utf8mb4", "--collation-server=utf8mb4_unicode_ci"}
+	}
+
+	hostConfig := &container.HostConfig{
+		CapAdd:    []string{"SYS_NICE"},
+		LogConfig: logs,
+		Mounts: []mount.Mount{
+			{
+				Type:   mount.TypeVolume,
+				Source: volume.Name,
+				Target: target,
+			},
+		},
+		PortBindings: map[nat.Port][]nat.PortBinding{
+			port: {
+				{
+					HostIP:   "127.0.0.1",
+					HostPort: db.Port,
+				},
+			},
+		},
+	}
+
+	networkConfig := &network.NetworkingConfig{
+		EndpointsConfig: map[string]*network.EndpointSettings{
+			environment.Network(): {
+				NetworkID: networkID,
+			},
+		},
+	}
+
+	// create the container for the database
+	resp, err := docker.ContainerCreate(ctx, containerConfig, hostConfig, networkConfig, nil, hostname)
+	if err != nil {
+		return "", "", fmt.Errorf("unable to create the container, %w", err)
+	}
+
+	// start the container
+	if err := docker.ContainerStart(ctx, resp.ID, types.ContainerStartOptions{}); err != nil {
+		return "", "", fmt.Errorf("unable to start the container, %w", err)
+	}
+
+	// if the container is mysql compatible
+	if db.Engine == "mysql" || db.Engine == "mariadb" {
+		if err := waitForMySQLContainer(ctx, docker, resp.ID, db); err != nil {
+			return "", "", err
+		}
+	}
+
+	// create the roles and extensions for postgres
+	if err := configurePostgres(ctx, docker, resp.ID, db); err != nil {
+		return "", "", err
+	}
+
+	return resp.ID, hostname, nil
+}
+
+// configurePostgres creates the roles and extensions defined in the config for a postgres
+// database. Extensions are created in the template1 database so new databases include them.
+func configurePostgres(ctx context.Context, docker client.CommonAPIClient, containerID string, d config.Database) error {
+	if d.Engine != "postgres" || (len(d.Roles) == 0 && len(d.Extensions) == 0) {
+		return nil
+	}
+
+	if err := waitForPostgresContainer(ctx, docker, containerID); err != nil {
+		return err
+	}
+
+	var commands [][]string
+	for _, role := range d.Roles {
+		commands = append(commands, []string{"psql", "--username=nitro", "--dbname=nitro", fmt.Sprintf(`--command=DO $$ BEGIN IF NOT EXISTS (SELECT FROM pg_roles WHERE rolname = %s) THEN CREATE ROLE %s LOGIN PASSWORD 'nitro'; END IF; END $$;`, quoteLiteral(role), quoteIdentifier(role))})
+	}
+
+	for _, ext := range d.Extensions {
+		for _, db := range []string{"template1", "nitro"} {
+			commands = append(commands, []string{"psql", "--username=nitro", "--dbname=" + db, fmt.Sprintf(`--command=CREATE EXTENSION IF NOT EXISTS %s;`, quoteIdentifier(ext))})
+		}
+	}
+
+	return execCommands(ctx, docker, containerID, commands)
+}
+
+// quoteIdentifier quotes the name of a role or extension for a postgres statement, the quotes
+// in the name are doubled.
+func quoteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// quoteLiteral quotes a string value for a postgres statement, the quotes in the value are
+// doubled.
+func quoteLiteral(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+func waitForPostgresContainer(ctx context.Context, docker client.CommonAPIClient, containerID string) error {
+	// try for 30 seconds
+	for i := 0; i < 30; i++ {
+		exec, err := docker.ContainerExecCreate(ctx, containerID, types.ExecConfig{
+			Cmd: []string{"pg_isready", "--username=nitro"},
+		})
+		if err != nil {
+			return err
+		}
+
+		if err := docker.ContainerExecStart(ctx, exec.ID, types.ExecStartCheck{}); err != nil {
+			return fmt.Errorf("unable to start the exec, %w", err)
+		}
+
+		resp, err := waitForExec(ctx, docker, exec.ID)
+		if err != nil {
+			return err
+		}
+
+		if resp.ExitCode == 0 {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Second):
+		}
+	}
+
+	return fmt.Errorf("timed out waiting for the postgres database to be ready")
+}
+
+func waitForMySQLContainer(ctx context.Context, docker client.CommonAPIClient, containerID string, d config.Database) error {
+	// wait for the database to accept connections
+	if err := waitForHealthy(ctx, docker, containerID, 2*time.Minute); err != nil {
+		return err
+	}
+
+	// connect to the database
+	db, err := sql.Open("mysql", fmt.Sprintf("root:nitro@tcp(127.0.0.1:%s)/nitro", d.Port))
+	if err != nil {
+		return fmt.Errorf("error opening connection: %w", err)
+	}
+
+	// set the connection time to 5 seconds
+	db.SetConnMaxLifetime(5 * time.Second)
+	db.SetMaxIdleConns(0)
+	db.SetMaxOpenConns(151)
+
+	// newer mariadb images only provide the mariadb client
+	client := database.ClientCommand(d.Engine, d.Version)
+
+	// setup the commands
+	commands := [][]string{
+		{client, "-uroot", "-pnitro", fmt.Sprintf(`-e CREATE USER IF NOT EXISTS '%s'@'%s' IDENTIFIED BY 'nitro';`, "nitro", "localhost")},
+		{client, "-uroot", "-pnitro", fmt.Sprintf(`-e GRANT ALL PRIVILEGES ON *.* TO '%s'@'%s' WITH GRANT OPTION;`, "nitro", "%")},
+		{client, "-uroot", "-pnitro", fmt.Sprintf(`-e GRANT ALL PRIVILEGES ON *.* TO '%s'@'%s' WITH GRANT OPTION;`, "nitro", "localhost")},
+		{client, "-uroot", "-pnitro", `-e FLUSH PRIVILEGES;`},
+	}
+
+	// for mysql 8.0 images
+	// ALTER USER ‘username’@‘ip_address’ IDENTIFIED WITH mysql_native_password BY ‘password’
+	if d.Engine == "mysql" && strings.Contains(d.Version, "8.0") {
+		commands = append(commands, []string{client, "-uroot", "-pnitro", fmt.Sprintf(`-e ALTER USER '%s'@'%s' IDENTIFIED WITH mysql_native_password BY 'nitro';`, "nitro", "%")})
+	}
+
+	if err := execCommands(ctx, docker, containerID, commands); err != nil {
+		return err
+	}
+
+	db.Close()
+
+	return nil
+}
+
+// waitForHealthy waits for the health check of the container to pass. Containers created
+// before health checks were added do not have a health status and are returned as is.
+func waitForHealthy(ctx context.Context, docker client.CommonAPIClient, containerID string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		info, err := docker.ContainerInspect(ctx, containerID)
+		if err != nil {
+			return err
+		}
+
+		if info.State == nil || info.State.Health == nil {
+			return nil
+		}
+
+		switch info.State.Health.Status {
+		case types.Healthy:
+			return nil
+		case types.Unhealthy:
+			return fmt.Errorf("the database container is unhealthy, run `nitro logs` to see why")
+		}
+
+		time.Sleep(time.Second)
+	}
+
+	return fmt.Errorf("timed out waiting for the database container to be healthy")
+}
+
+// execCommands runs each of the commands in the container and waits for them to complete.
+// It stops at the first command that exits with an error and returns the error output.
+func execCommands(ctx context.Context, docker client.CommonAPIClient, containerID string, commands [][]string) error {
+	for _, c := range commands {
+		if err := execCommand(ctx, docker, containerID, c); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func execCommand(ctx context.Context, docker client.CommonAPIClient, containerID string, command []string) error {
+	// create the exec
+	exec, err := docker.ContainerExecCreate(ctx, containerID, types.ExecConfig{
+		AttachStdout: true,
+		AttachStderr: true,
+		Tty:          false,
+		Cmd:          command,
+	})
+	if err != nil {
+		return err
+	}
+
+	// attach to the container
+	resp, err := docker.ContainerExecAttach(ctx, exec.ID, types.ExecStartCheck{
+		Tty: false,
+	})
+	if err != nil {
+		return err
+	}
+	defer resp.Close()
+
+	// start the exec
+	if err := docker.ContainerExecStart(ctx, exec.ID, types.ExecStartCheck{}); err != nil {
+		return fmt.Errorf("unable to start the container, %w", err)
+	}
+
+	// keep stderr for errors
+	stderr := new(bytes.Buffer)
+	if _, err := stdcopy.StdCopy(ioutil.Discard, stderr, resp.Reader); err != nil {
+		return err
+	}
+
+	info, err := waitForExec(ctx, docker, exec.ID)
+	if err != nil {
+		return err
+	}
+
+	if info.ExitCode != 0 {
+		return fmt.Errorf("the command %s exited with code %d, %s", command[0], info.ExitCode, strings.TrimSpace(stderr.String()))
+	}
+
+	return nil
+}
+
+// waitForExec waits for the exec to complete and returns the exit code of the exec.
+func waitForExec(ctx context.Context, docker client.CommonAPIClient, execID string) (types.ContainerExecInspect, error) {
+	for {
+		info, err := docker.ContainerExecInspect(ctx, execID)
+		if err != nil {
+			return info, err
+		}
+
+		if !info.Running {
+			return info, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return info, ctx.Err()
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+}
+
+// Exists returns true when the database exists in the database container.
+func Exists(ctx context.Context, docker client.CommonAPIClient, containerID string, db config.Database, name string) (bool, error) {
+	out, err := containerexec.Run(ctx, docker, containerID, database.ExistsCommand(db.Engine, db.Version, name))
+	if err != nil {
+		return false, fmt.Errorf("unable to check for the database %s, %w", name, err)
+	}
+
+	// the output can contain warnings from the client
+	for _, line := range strings.Split(out, "\n") {
+		if strings.TrimSpace(line) == name {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// Create creates the database in the database container with the default character set
+// of the engine and grants the nitro user access to it.
+func Create(ctx context.Context, docker client.CommonAPIClient, containerID string, db config.Database, name string) error {
+	for _, c := range database.CreateCommands(db.Engine, db.Version, name) {
+		if _, err := containerexec.Run(ctx, docker, containerID, c); err != nil {
+			return fmt.Errorf("unable to create the database %s, %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// Drop removes the database from the database container.
+func Drop(ctx context.Context, docker client.CommonAPIClient, containerID string, db config.Database, name string) error {
+	for _, c := range database.DropCommands(db.Engine, db.Version, name) {
+		if _, err := containerexec.Run(ctx, docker, containerID, c); err != nil {
+			return fmt.Errorf("unable to remove the database %s, %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// Restore copies the postgres custom format backup into the database container and restores
+// it into the database with pg_restore. pg_restore can only restore in parallel from a file,
+// so the backup is not streamed. The copy is removed once the restore is done.
+func Restore(ctx context.Context, docker client.ContainerAPIClient, containerID, path, name string, jobs int) error {
+	file := "nitro-restore-" + filepath.Base(path)
+
+	rdr, err := database.Archive(path, file)
+	if err != nil {
+		return err
+	}
+	defer rdr.Close()
+
+	if err := docker.CopyToContainer(ctx, containerID, "/tmp", rdr, types.CopyToContainerOptions{}); err != nil {
+		return fmt.Errorf("unable to copy the backup to the container, %w", err)
+	}
+
+	defer containerexec.Run(ctx, docker, containerID, []string{"rm", "-f", "/tmp/" + file})
+
+	cmd := append([]string{database.PostgresRestoreCommand}, database.RestoreCommand(&database.ImportOptions{DatabaseName: name, File: "/tmp/" + file, Jobs: jobs})...)
+	if _, err := containerexec.Run(ctx, docker, containerID, cmd); err != nil {
+		return fmt.Errorf("unable to restore the backup into %s, %w", name, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,100 @@
+package databasecontainer
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+func TestQuote(t *testing.T) {
+	tests := []struct {
+		name           string
+		value          string
+		wantIdentifier string
+		wantLiteral    string
+	}{
+		{name: "names are quoted", value: "craft", wantIdentifier: `"craft"`, wantLiteral: `'craft'`},
+		{name: "hyphens are kept in the quotes", value: "uuid-ossp", wantIdentifier: `"uuid-ossp"`, wantLiteral: `'uuid-ossp'`},
+		{name: "quotes are doubled", value: `a"b'c`, wantIdentifier: `"a""b'c"`, wantLiteral: `'a"b''c'`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := quoteIdentifier(tt.value); got != tt.wantIdentifier {
+				t.Errorf("quoteIdentifier() = %v, want %v", got, tt.wantIdentifier)
+			}
+
+			if got := quoteLiteral(tt.value); got != tt.wantLiteral {
+				t.Errorf("quoteLiteral() = %v, want %v", got, tt.wantLiteral)
+			}
+		})
+	}
+}
+
+func TestExecCommands(t *testing.T) {
+	tests := []struct {
+		name     string
+		spy      *mockClient
+		wantErr  string
+		wantRuns int
+	}{
+		{name: "commands that succeed are all run", spy: &mockClient{}, wantRuns: 2},
+		{name: "failed commands return the error output", spy: &mockClient{exitCode: 1, stderr: `ERROR:  extension "postgis" is not available`}, wantErr: `extension "postgis" is not available`, wantRuns: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := execCommands(context.Background(), tt.spy, "postgres", [][]string{{"psql", "--command=CREATE EXTENSION postgis;"}, {"psql", "--command=SELECT 1;"}})
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("expected the error to contain %q, got %v", tt.wantErr, err)
+			}
+
+			if tt.spy.runs != tt.wantRuns {
+				t.Errorf("expected %d commands to run, got %d", tt.wantRuns, tt.spy.runs)
+			}
+		})
+	}
+}
+
+type mockClient struct {
+	client.CommonAPIClient
+
+	stderr   string
+	exitCode int
+	runs     int
+}
+
+func (c *mockClient) ContainerExecCreate(ctx context.Context, container string, config types.ExecConfig) (types.IDResponse, error) {
+	return types.IDResponse{ID: "exec-id"}, nil
+}
+
+func (c *mockClient) ContainerExecAttach(ctx context.Context, execID string, config types.ExecStartCheck) (types.HijackedResponse, error) {
+	// multiplex the output the same way the docker API does
+	buf := new(bytes.Buffer)
+	if c.stderr != "" {
+		stdcopy.NewStdWriter(buf, stdcopy.Stderr).Write([]byte(c.stderr))
+	}
+
+	conn, _ := net.Pipe()
+
+	return types.HijackedResponse{Conn: conn, Reader: bufio.NewReader(buf)}, nil
+}
+
+func (c *mockClient) ContainerExecStart(ctx context.Context, execID string, config types.ExecStartCheck) error {
+	c.runs++
+
+	return nil
+}
+
+func (c *mockClient) ContainerExecInspect(ctx context.Context, execID string) (types.ContainerExecInspect, error) {
+	return types.ContainerExecInspect{ExitCode: c.exitCode}, nil
+}
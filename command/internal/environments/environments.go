@@ -0,0 +1,216 @@
+package environments
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/docker/docker/api/types"
+	"github.com/spf13/cobra"
+
+	"github.com/craftcms/nitro/command/internal/backupcontainer"
+	"github.com/craftcms/nitro/command/internal/queuecontainer"
+	"github.com/craftcms/nitro/command/internal/service"
+	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/environment"
+	"github.com/craftcms/nitro/pkg/terminal"
+)
+
+// All is set by the --all-envs flag to run the lifecycle commands for every environment.
+var All bool
+
+// Label returns the label filter for the containers of the command. Every nitro container is
+// included when the command runs for every environment, otherwise only the containers of the
+// current environment are included.
+func Label() string {
+	if All {
+		return containerlabels.Nitro
+	}
+
+	return containerlabels.Nitro + "=" + environment.Label()
+}
+
+// Environment is a config file in the nitro directory.
+type Environment struct {
+	Name   string
+	File   string
+	Config *config.Config
+}
+
+// List returns the environment for each config file in the nitro directory. Environments
+// without a name are named after the config file.
+func List(home string) ([]Environment, error) {
+	files, err := config.Files(home)
+	if err != nil {
+		return nil, err
+	}
+
+	var envs []Environment
+	for _, f := range files {
+		cfg, err := config.LoadFile(f)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load the config %s, %w", f, err)
+		}
+
+		name := cfg.Name
+		if name == "" {
+			name = strings.TrimSuffix(filepath.Base(f), filepath.Ext(f))
+		}
+
+		envs = append(envs, Environment{Name: name, File: f, Config: cfg})
+	}
+
+	return envs, nil
+}
+
+// Hostnames returns the names of the containers for the sites, queue workers, databases,
+// services, scheduled backups, and custom containers in the config.
+func Hostnames(cfg *config.Config) []string {
+	var names []string
+	for _, s := range cfg.Sites {
+		names = append(names, s.Hostname)
+
+		for n := 1; n <= int(s.Queue); n++ {
+			names = append(names, queuecontainer.Name(s.Hostname, n))
+		}
+	}
+
+	for _, db := range cfg.Databases {
+		if h, err := db.GetHostname(); err == nil {
+			names = append(names, h)
+		}
+	}
+
+	names = append(names, service.Hostnames(cfg)...)
+
+	if cfg.Backups.Enabled() {
+		names = append(names, backupcontainer.Name)
+	}
+
+	for _, c := range cfg.Containers {
+		names = append(names, c.GetHostnames()...)
+	}
+
+	return names
+}
+
+// Partition returns the containers of each environment, by the name of the environment, and
+// the containers that do not belong to one of the environments. The containers are grouped
+// by the nitro label, so each environment gets its own proxy, sites, and services.
+func Partition(envs []Environment, containers []types.Container) (map[string][]types.Container, []types.Container) {
+	owners := make(map[string]string)
+	for _, e := range envs {
+		// the labels use the name of the config file, not the name in the config
+		owners[label(environment.NameFromFile(e.File))] = e.Name
+	}
+
+	owned := make(map[string][]types.Container)
+	var shared []types.Container
+	for _, c := range containers {
+		owner, ok := owners[c.Labels[containerlabels.Nitro]]
+		if !ok {
+			shared = append(shared, c)
+			continue
+		}
+
+		owned[owner] = append(owned[owner], c)
+	}
+
+	return owned, shared
+}
+
+// label returns the value of the nitro label for the environment, the default environment
+// uses "true" like environment.Label.
+func label(name string) string {
+	if name == environment.Default {
+		return "true"
+	}
+
+	return name
+}
+
+// Args returns the positional args check for the lifecycle commands, a site cannot be used
+// with the --all-envs flag. The verb is used in the error (e.g. started).
+func Args(verb string) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		if All && len(args) > 0 {
+			return fmt.Errorf("a site cannot be %s with --all-envs", verb)
+		}
+
+		return nil
+	}
+}
+
+// Run calls fn for each environment in parallel and waits for them to finish. The output
+// passed to fn prefixes each line with the name of the environment. The errors of the
+// environments are returned together once every environment has finished.
+func Run(envs []Environment, output terminal.Outputer, fn func(env Environment, output terminal.Outputer) error) error {
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		failed []string
+	)
+
+	lock := &sync.Mutex{}
+	for _, e := range envs {
+		wg.Add(1)
+
+		go func(e Environment) {
+			defer wg.Done()
+
+			if err := fn(e, &prefixed{Outputer: output, lock: lock, prefix: "[" + e.Name + "]"}); err != nil {
+				mu.Lock()
+				failed = append(failed, fmt.Sprintf("%s: %s", e.Name, err))
+				mu.Unlock()
+			}
+		}(e)
+	}
+
+	wg.Wait()
+
+	if len(failed) > 0 {
+		return fmt.Errorf("unable to complete the command for every environment:\n  %s", strings.Join(failed, "\n  "))
+	}
+
+	return nil
+}
+
+// prefixed is the output for an environment that is running in parallel with other
+// environments. Pending lines are only shown once they are done, so the lines of the
+// environments are not mixed.
+type prefixed struct {
+	terminal.Outputer
+	lock    *sync.Mutex
+	prefix  string
+	pending []string
+}
+
+func (p *prefixed) Info(s ...string) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.Outputer.Info(append([]string{p.prefix}, s...)...)
+}
+
+func (p *prefixed) Success(s ...string) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.Outputer.Success(append([]string{p.prefix}, s...)...)
+}
+
+func (p *prefixed) Pending(s ...string) {
+	p.pending = s
+}
+
+func (p *prefixed) Done() {
+	p.Success(p.pending...)
+	p.pending = nil
+}
+
+func (p *prefixed) Warning() {
+	p.Info(append([]string{"✗"}, p.pending...)...)
+	p.pending = nil
+}
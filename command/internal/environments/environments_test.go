@@ -0,0 +1,71 @@
+package environments
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+
+	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/terminal"
+)
+
+func TestPartition(t *testing.T) {
+	envs := []Environment{
+		{Name: "nitro", File: "/home/oli/.nitro/nitro.yaml", Config: &config.Config{Sites: []config.Site{{Hostname: "craft.nitro", Queue: 1}}}},
+		{Name: "agency-dev", File: "/home/oli/.nitro/agency.yaml", Config: &config.Config{Sites: []config.Site{{Hostname: "agency.nitro"}, {Hostname: "craft.nitro"}}}},
+	}
+
+	containers := []types.Container{
+		{ID: "proxy", Names: []string{"/nitro-proxy"}, Labels: map[string]string{containerlabels.Nitro: "true"}},
+		{ID: "craft", Names: []string{"/craft.nitro"}, Labels: map[string]string{containerlabels.Nitro: "true"}},
+		{ID: "queue", Names: []string{"/craft.nitro-queue-1"}, Labels: map[string]string{containerlabels.Nitro: "true"}},
+		{ID: "agency-proxy", Names: []string{"/agency-proxy"}, Labels: map[string]string{containerlabels.Nitro: "agency"}},
+		{ID: "agency-craft", Names: []string{"/agency.craft.nitro"}, Labels: map[string]string{containerlabels.Nitro: "agency"}},
+		{ID: "removed", Names: []string{"/removed-proxy"}, Labels: map[string]string{containerlabels.Nitro: "removed"}},
+	}
+
+	owned, shared := Partition(envs, containers)
+
+	ids := func(containers []types.Container) string {
+		var ids []string
+		for _, c := range containers {
+			ids = append(ids, c.ID)
+		}
+
+		return strings.Join(ids, ",")
+	}
+
+	if got := ids(owned["nitro"]); got != "proxy,craft,queue" {
+		t.Errorf("expected the default environment to own its proxy, site, and queue worker, got %q", got)
+	}
+
+	if got := ids(owned["agency-dev"]); got != "agency-proxy,agency-craft" {
+		t.Errorf("expected the agency environment to own its proxy and site, got %q", got)
+	}
+
+	if got := ids(shared); got != "removed" {
+		t.Errorf("expected the containers of unknown environments to be returned separately, got %q", got)
+	}
+}
+
+func TestRun(t *testing.T) {
+	envs := []Environment{{Name: "nitro-dev"}, {Name: "agency"}, {Name: "client"}}
+
+	err := Run(envs, terminal.New(), func(env Environment, output terminal.Outputer) error {
+		if env.Name == "agency" {
+			return fmt.Errorf("docker is not running")
+		}
+
+		return nil
+	})
+	if err == nil {
+		t.Fatal("expected an error when an environment fails")
+	}
+
+	if !strings.Contains(err.Error(), "agency: docker is not running") || strings.Contains(err.Error(), "client") {
+		t.Errorf("expected only the failed environment in the error, got %q", err)
+	}
+}
@@ -0,0 +1,66 @@
+package ephemeral
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+
+	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/environment"
+)
+
+// MaxAge is how long a container that runs a single command is kept once it has exited. The
+// commands remove their container when they finish, so the containers that are older were
+// left behind when nitro was interrupted or crashed.
+var MaxAge = time.Hour
+
+// Label adds the ephemeral label with the time to the labels of a container and returns the
+// labels.
+func Label(labels map[string]string, now time.Time) map[string]string {
+	labels[containerlabels.Ephemeral] = strconv.FormatInt(now.Unix(), 10)
+
+	return labels
+}
+
+// Collect removes the ephemeral containers of the environment that are not running and were
+// created more than MaxAge before now. The containers that were created but never started are
+// removed as well. It returns the names of the removed containers.
+func Collect(ctx context.Context, docker client.ContainerAPIClient, now time.Time) ([]string, error) {
+	filter := filters.NewArgs()
+	filter.Add("label", containerlabels.Nitro+"="+environment.Label())
+	filter.Add("label", containerlabels.Ephemeral)
+	filter.Add("status", "created")
+	filter.Add("status", "exited")
+	filter.Add("status", "dead")
+
+	containers, err := docker.ContainerList(ctx, types.ContainerListOptions{All: true, Filters: filter})
+	if err != nil {
+		return nil, err
+	}
+
+	var removed []string
+	for _, c := range containers {
+		created, err := strconv.ParseInt(c.Labels[containerlabels.Ephemeral], 10, 64)
+		if err != nil || now.Sub(time.Unix(created, 0)) < MaxAge {
+			continue
+		}
+
+		if err := docker.ContainerRemove(ctx, c.ID, types.ContainerRemoveOptions{}); err != nil {
+			return removed, err
+		}
+
+		name := c.ID
+		if len(c.Names) > 0 {
+			name = strings.TrimLeft(c.Names[0], "/")
+		}
+
+		removed = append(removed, name)
+	}
+
+	return removed, nil
+}
@@ -0,0 +1,68 @@
+package ephemeral
+
+import (
+	"context"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+
+	"github.com/craftcms/nitro/pkg/containerlabels"
+)
+
+func TestCollect(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	created := func(ago time.Duration) map[string]string {
+		return map[string]string{containerlabels.Ephemeral: strconv.FormatInt(now.Add(-ago).Unix(), 10)}
+	}
+
+	spy := &mockClient{containers: []types.Container{
+		{ID: "old", Names: []string{"/old-composer"}, Labels: created(2 * time.Hour)},
+		{ID: "recent", Names: []string{"/recent-npm"}, Labels: created(time.Minute)},
+		{ID: "invalid", Names: []string{"/invalid"}, Labels: map[string]string{containerlabels.Ephemeral: "yesterday"}},
+	}}
+
+	removed, err := Collect(context.Background(), spy, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(removed, []string{"old-composer"}) || !reflect.DeepEqual(spy.removed, []string{"old"}) {
+		t.Errorf("expected only the old container to be removed, got %v and %v", removed, spy.removed)
+	}
+
+	if !spy.options.All || !spy.options.Filters.ExactMatch("status", "exited") || !spy.options.Filters.ExactMatch("status", "created") {
+		t.Errorf("expected the containers that are not running to be listed, got %v", spy.options)
+	}
+}
+
+func TestLabel(t *testing.T) {
+	labels := Label(map[string]string{containerlabels.Type: "composer"}, time.Unix(1600000000, 0))
+
+	if labels[containerlabels.Ephemeral] != "1600000000" || labels[containerlabels.Type] != "composer" {
+		t.Errorf("expected the time to be added to the labels, got %v", labels)
+	}
+}
+
+type mockClient struct {
+	client.ContainerAPIClient
+
+	containers []types.Container
+	options    types.ContainerListOptions
+	removed    []string
+}
+
+func (c *mockClient) ContainerList(ctx context.Context, options types.ContainerListOptions) ([]types.Container, error) {
+	c.options = options
+
+	return c.containers, nil
+}
+
+func (c *mockClient) ContainerRemove(ctx context.Context, containerID string, options types.ContainerRemoveOptions) error {
+	c.removed = append(c.removed, containerID)
+
+	return nil
+}
@@ -0,0 +1,148 @@
+package hook
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/docker/docker/client"
+
+	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/containerexec"
+	"github.com/craftcms/nitro/pkg/terminal"
+)
+
+const (
+	// PreApply runs before apply makes any changes
+	PreApply = "pre-apply"
+
+	// PostApply runs once apply has made every change
+	PostApply = "post-apply"
+
+	// PostSiteCreate runs for each site apply created
+	PostSiteCreate = "post-site-create"
+
+	// PostDBCreate runs for each database apply created
+	PostDBCreate = "post-db-create"
+)
+
+// Hooks returns the hooks in the config for the event.
+func Hooks(cfg *config.Config, event string) []config.Hook {
+	switch event {
+	case PreApply:
+		return cfg.Hooks.PreApply
+	case PostApply:
+		return cfg.Hooks.PostApply
+	case PostSiteCreate:
+		return cfg.Hooks.PostSiteCreate
+	case PostDBCreate:
+		return cfg.Hooks.PostDBCreate
+	}
+
+	return nil
+}
+
+// Run runs the hooks for the event in order and stops at the first hook that fails. The
+// event and the env are passed to each command as environment variables (e.g.
+// NITRO_EVENT=post-site-create and NITRO_SITE=craft.nitro). Hooks without a container
+// run with the shell on the host and hooks with a container run with sh in the container.
+func Run(ctx context.Context, docker client.ContainerAPIClient, cfg *config.Config, event string, env map[string]string, output terminal.Outputer) error {
+	hooks := Hooks(cfg, event)
+	if len(hooks) == 0 {
+		return nil
+	}
+
+	vars := Env(cfg, event, env)
+
+	output.Info(fmt.Sprintf("Running %s hooks…", event))
+
+	for _, h := range hooks {
+		if h.Container == "" {
+			output.Pending("running", h.Run)
+
+			if err := host(ctx, h.Run, vars); err != nil {
+				output.Warning()
+				return fmt.Errorf("the %s hook %q failed, %w", event, h.Run, err)
+			}
+
+			output.Done()
+
+			continue
+		}
+
+		output.Pending("running", h.Run, "in", h.Container)
+
+		out, err := inContainer(ctx, docker, h, vars)
+		if err != nil {
+			output.Warning()
+			return fmt.Errorf("the %s hook %q failed in %s, %w", event, h.Run, h.Container, err)
+		}
+
+		output.Done()
+
+		if out != "" {
+			output.Info(out)
+		}
+	}
+
+	return nil
+}
+
+// Env returns the environment variables for the hooks of the event, sorted by name.
+func Env(cfg *config.Config, event string, env map[string]string) []string {
+	vars := []string{"NITRO_EVENT=" + event}
+	if cfg.File != "" {
+		vars = append(vars, "NITRO_CONFIG="+cfg.File)
+	}
+
+	for k, v := range env {
+		vars = append(vars, k+"="+v)
+	}
+
+	sort.Strings(vars)
+
+	return vars
+}
+
+// host runs the command with the shell on the host, the output of the command is shown.
+func host(ctx context.Context, command string, vars []string) error {
+	name, args := "sh", []string{"-c", command}
+	if runtime.GOOS == "windows" {
+		name, args = "cmd", []string{"/C", command}
+	}
+
+	c := exec.CommandContext(ctx, name, args...)
+	c.Env = append(os.Environ(), vars...)
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+
+	return c.Run()
+}
+
+// inContainer runs the command with sh in the container and returns the output.
+func inContainer(ctx context.Context, docker client.ContainerAPIClient, h config.Hook, vars []string) (string, error) {
+	details, err := docker.ContainerInspect(ctx, h.Container)
+	if err != nil {
+		return "", fmt.Errorf("unable to find the container, %w", err)
+	}
+
+	if details.State == nil || !details.State.Running {
+		return "", fmt.Errorf("the container is not running")
+	}
+
+	cmds := append([]string{"env"}, vars...)
+	cmds = append(cmds, "sh", "-c", h.Run)
+
+	out, err := containerexec.Run(ctx, docker, details.ID, cmds)
+
+	return strings.TrimSpace(out), err
+}
+
+// Configured returns true if the config has hooks for any of the events.
+func Configured(cfg *config.Config) bool {
+	return len(cfg.Hooks.PreApply)+len(cfg.Hooks.PostApply)+len(cfg.Hooks.PostSiteCreate)+len(cfg.Hooks.PostDBCreate) > 0
+}
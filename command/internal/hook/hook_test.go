@@ -0,0 +1,76 @@
+package hook
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/terminal"
+)
+
+func TestEnv(t *testing.T) {
+	cfg := &config.Config{File: "/home/nitro/.nitro/nitro.yaml"}
+
+	got := Env(cfg, PostSiteCreate, map[string]string{"NITRO_SITE": "craft.nitro"})
+	want := []string{"NITRO_CONFIG=/home/nitro/.nitro/nitro.yaml", "NITRO_EVENT=post-site-create", "NITRO_SITE=craft.nitro"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Env() = %v, want %v", got, want)
+	}
+}
+
+func TestRun(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the hooks in the test use sh")
+	}
+
+	dir, err := ioutil.TempDir("", "nitro-hook")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "hook.txt")
+
+	cfg := &config.Config{Hooks: config.Hooks{
+		PostApply: []config.Hook{
+			{Run: `echo "$NITRO_EVENT $NITRO_CREATED" > ` + file},
+			{Run: "exit 3"},
+			{Run: "echo skipped >> " + file},
+		},
+	}}
+
+	err = Run(context.Background(), nil, cfg, PostApply, map[string]string{"NITRO_CREATED": "craft.nitro"}, terminal.New())
+	if err == nil || !strings.Contains(err.Error(), "exit 3") {
+		t.Errorf("expected the failed hook to be returned, got %v", err)
+	}
+
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := string(content); got != "post-apply craft.nitro\n" {
+		t.Errorf("expected the hooks to stop at the failure and have the env, got %q", got)
+	}
+
+	// events without hooks do nothing
+	if err := Run(context.Background(), nil, cfg, PreApply, nil, terminal.New()); err != nil {
+		t.Errorf("expected no error without hooks, got %v", err)
+	}
+}
+
+func TestConfigured(t *testing.T) {
+	if Configured(&config.Config{}) {
+		t.Errorf("expected a config without hooks to not be configured")
+	}
+
+	if !Configured(&config.Config{Hooks: config.Hooks{PostDBCreate: []config.Hook{{Run: "true"}}}}) {
+		t.Errorf("expected a config with a hook to be configured")
+	}
+}
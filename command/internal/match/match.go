@@ -0,0 +1,269 @@
+package match
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+
+	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/limits"
+)
+
+var (
+	ErrMisMatchedImage  = fmt.Errorf("container image does not match")
+	ErrMisMatchedLabel  = fmt.Errorf("container label does not match")
+	ErrEnvFileNotFound  = fmt.Errorf("unable to find the containers env file")
+	ErrMisMatchedEnvVar = fmt.Errorf("container environment variables do not match")
+)
+
+// Container checks if a custom container is up to date with the configuration
+func Container(home string, container config.Container, details types.ContainerJSON) error {
+	changes := ContainerChanges(home, container, details)
+	if len(changes) == 0 {
+		return nil
+	}
+
+	switch name := changes[0].Name; {
+	case name == "image":
+		return ErrMisMatchedImage
+	case name == "env file":
+		return ErrEnvFileNotFound
+	case strings.HasPrefix(name, "env "):
+		return ErrMisMatchedEnvVar
+	default:
+		return ErrMisMatchedLabel
+	}
+}
+
+// ContainerChanges takes the home directory, custom container, and the container details
+// and returns the changes that require the container to be recreated. If the container
+// matches the config, the returned changes are empty.
+func ContainerChanges(home string, container config.Container, details types.ContainerJSON) []Change {
+	var changes []Change
+
+	// check if the image does not match - this uses the image name, not ref
+	if image := fmt.Sprintf("%s:%s", container.Image, container.Tag); image != details.Config.Image {
+		changes = append(changes, Change{Name: "image", Expected: image, Actual: details.Config.Image})
+	}
+
+	// check the name has been changed
+	if details.Config.Labels[containerlabels.NitroContainer] != container.Name {
+		changes = append(changes, Change{Name: "label " + containerlabels.NitroContainer, Expected: container.Name, Actual: details.Config.Labels[containerlabels.NitroContainer]})
+	}
+
+	if container.EnvFile != "" {
+		customEnvs := make(map[string]string)
+
+		content, err := ioutil.ReadFile(filepath.Join(home, config.DirectoryName, "."+container.Name))
+		if err != nil {
+			return append(changes, Change{Name: "env file", Expected: filepath.Join(home, config.DirectoryName, "."+container.Name), Actual: "does not exist"})
+		}
+
+		for _, line := range strings.Split(string(content), "\n") {
+			parts := strings.Split(line, "=")
+			if len(parts) > 2 {
+				customEnvs[parts[0]] = parts[1]
+			}
+		}
+
+		// check the containers env against the file and merge
+		for _, e := range details.Config.Env {
+			parts := strings.Split(e, "=")
+			env := parts[0]
+			val := parts[1]
+
+			// is there a custom env val for the variable?
+			if custom, ok := customEnvs[env]; ok {
+				if val != custom {
+					changes = append(changes, Change{Name: "env " + env, Expected: custom, Actual: val})
+				}
+			}
+		}
+	}
+
+	// TODO(jasonmccallister) check the port mappings
+	// TODO(jasonmccallister) check the volumes
+
+	return changes
+}
+
+// Change describes a value on a container that does not match the value
+// expected by the configuration.
+type Change struct {
+	Name     string `json:"name"`
+	Expected string `json:"expected"`
+	Actual   string `json:"actual"`
+}
+
+// Site takes the home directory, site, and a container to determine if they
+// match whats expected.
+func Site(home string, site config.Site, container types.ContainerJSON, blackfire config.Blackfire) bool {
+	return len(SiteChanges(home, site, container, blackfire)) == 0
+}
+
+// SiteChanges takes the home directory, site, and a container and returns the
+// changes that require the container to be recreated. If the container matches
+// the site, the returned changes are empty.
+func SiteChanges(home string, site config.Site, container types.ContainerJSON, blackfire config.Blackfire) []Change {
+	var changes []Change
+
+	// check if the image does not match - this uses the image name, not ref
+	if image := fmt.Sprintf("docker.io/craftcms/nginx:%s-dev", site.Version); image != container.Config.Image {
+		changes = append(changes, Change{Name: "image", Expected: image, Actual: container.Config.Image})
+	}
+
+	// check the web root is defined and they match
+	if container.Config.Labels[containerlabels.Webroot] != site.Webroot {
+		changes = append(changes, Change{Name: "label " + containerlabels.Webroot, Expected: site.Webroot, Actual: container.Config.Labels[containerlabels.Webroot]})
+	}
+
+	// check the sites hostname using the label
+	if container.Config.Labels[containerlabels.Host] != site.Hostname {
+		changes = append(changes, Change{Name: "label " + containerlabels.Host, Expected: site.Hostname, Actual: container.Config.Labels[containerlabels.Host]})
+	}
+
+	// get the main site path (e.g. ~/dev/craft-dev)
+	path, err := site.GetAbsPath(home)
+	if err != nil {
+		return append(changes, Change{Name: "path", Expected: site.Path, Actual: err.Error()})
+	}
+
+	// check if the path exists
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return append(changes, Change{Name: "path", Expected: path, Actual: "does not exist"})
+	}
+
+	// check the path
+	if change, ok := Mount(path, site, container.Mounts); !ok {
+		changes = append(changes, change)
+	}
+
+	// TODO(jasonmccallister) check the labels for php extensions and write tests
+	if extensions := strings.Join(site.Extensions, ","); container.Config.Labels[containerlabels.Extensions] != extensions {
+		changes = append(changes, Change{Name: "label " + containerlabels.Extensions, Expected: extensions, Actual: container.Config.Labels[containerlabels.Extensions]})
+	}
+
+	// check the resource limits of the container
+	if r := site.Resources(); limits.Changed(r, container) {
+		changes = append(changes, Change{Name: "resources", Expected: fmt.Sprintf("cpus %v memory %d", r.CPUs, r.MemoryBytes()), Actual: limits.String(container)})
+	}
+
+	// run the final check on the environment variables
+	return append(changes, envChanges(site, blackfire, container.Config.Env)...)
+}
+
+// samePath returns true when the paths are the same directory. A path with a symlink and
+// its real path are the same, so changing resolve_symlinks for a site or mounting the
+// site behind a symlink does not recreate the container.
+func samePath(a, b string) bool {
+	if a == b {
+		return true
+	}
+
+	ra, err := filepath.EvalSymlinks(a)
+	if err != nil {
+		return false
+	}
+
+	rb, err := filepath.EvalSymlinks(b)
+	if err != nil {
+		return false
+	}
+
+	return ra == rb
+}
+
+// Mount checks the mount of the site path in a container. Sites that use a sync mode mount
+// the sync volume instead of the path. It returns false and the change when the mount does
+// not match.
+func Mount(path string, site config.Site, mounts []types.MountPoint) (Change, bool) {
+	if len(mounts) == 0 {
+		return Change{}, true
+	}
+
+	switch {
+	case site.UsesSync() && mounts[0].Name != site.SyncVolume():
+		return Change{Name: "mount", Expected: "volume " + site.SyncVolume(), Actual: mounts[0].Source}, false
+	case !site.UsesSync() && !samePath(mounts[0].Source, path):
+		return Change{Name: "mount", Expected: path, Actual: mounts[0].Source}, false
+	}
+
+	return Change{}, true
+}
+
+func checkEnvs(site config.Site, blackfire config.Blackfire, envs []string) bool {
+	return len(envChanges(site, blackfire, envs)) == 0
+}
+
+// envChanges checks the environment variables of a container and returns a change
+// for each variable that does not match the site.
+func envChanges(site config.Site, blackfire config.Blackfire, envs []string) []Change {
+	// get the expected values to show in the changes
+	expected := map[string]string{
+		"BLACKFIRE_SERVER_ID":    blackfire.ServerID,
+		"BLACKFIRE_SERVER_TOKEN": blackfire.ServerToken,
+	}
+	for _, e := range site.AsEnvs("host.docker.internal") {
+		sp := strings.SplitN(e, "=", 2)
+		expected[sp[0]] = sp[1]
+	}
+
+	var changes []Change
+	changed := func(env, val string) {
+		want, ok := expected[env]
+		if !ok {
+			want = config.DefaultEnvs[env]
+		}
+
+		changes = append(changes, Change{Name: "env " + env, Expected: want, Actual: val})
+	}
+
+	// check the environment variables
+	for _, e := range envs {
+		sp := strings.Split(e, "=")
+		env := sp[0]
+		val := sp[1]
+
+		// TODO(jasonmccallister) consider adding checks for if blackfire is
+		// enabled for this site
+		if env == "BLACKFIRE_SERVER_ID" && blackfire.ServerID != val {
+			changed(env, val)
+		}
+		if env == "BLACKFIRE_SERVER_TOKEN" && blackfire.ServerToken != val {
+			changed(env, val)
+		}
+
+		// show only the environment variables we know about/support
+		if _, ok := config.DefaultEnvs[sp[0]]; ok {
+			// check the value of each environment variable we want to ensure the php config is not the "default" value and that the
+			// current value from the container match
+			switch env {
+			case "PHP_DISPLAY_ERRORS", "PHP_MEMORY_LIMIT", "PHP_MAX_EXECUTION_TIME", "PHP_UPLOAD_MAX_FILESIZE", "PHP_MAX_INPUT_VARS", "PHP_POST_MAX_SIZE", "PHP_OPCACHE_ENABLE", "PHP_OPCACHE_REVALIDATE_FREQ", "PHP_OPCACHE_VALIDATE_TIMESTAMPS":
+				// the php settings are compared with the values from the site config
+				if val != expected[env] {
+					changed(env, val)
+				}
+			case "XDEBUG_CONFIG":
+				// the client host and port can change while xdebug is enabled
+				if site.Xdebug {
+					for _, want := range site.AsEnvs("host.docker.internal") {
+						if strings.HasPrefix(want, "XDEBUG_CONFIG=") && want != e {
+							changed(env, strings.TrimPrefix(e, "XDEBUG_CONFIG="))
+						}
+					}
+				}
+			case "XDEBUG_MODE":
+				if (site.Xdebug && val == config.DefaultEnvs[env]) || (!site.Xdebug && val != config.DefaultEnvs[env]) {
+					changed(env, val)
+				}
+			}
+		}
+	}
+
+	return changes
+}
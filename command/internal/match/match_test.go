@@ -1,8 +1,10 @@
 package match
 
 import (
+	"io/ioutil"
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
 
 	"github.com/craftcms/nitro/pkg/config"
@@ -228,6 +230,22 @@ func Test_checkEnvs(t *testing.T) {
 			},
 			want: false,
 		},
+		{
+			name: "xdebug client host change returns false",
+			args: args{
+				site: config.Site{
+					Version: "7.4",
+					Xdebug:  true,
+					XdebugClient: config.XdebugClient{
+						Host: "192.168.1.20",
+					},
+				},
+				envs: []string{
+					"XDEBUG_CONFIG=client_host=host.docker.internal client_port=9003",
+				},
+			},
+			want: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -392,3 +410,143 @@ func TestSite(t *testing.T) {
 		})
 	}
 }
+
+func TestSiteChanges(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	site := config.Site{
+		Hostname: "newname",
+		Path:     "testdata/example-site",
+		Version:  "8.0",
+		Webroot:  "web",
+		PHP: config.PHP{
+			MemoryLimit: "512M",
+		},
+	}
+
+	details := types.ContainerJSON{
+		Config: &container.Config{
+			Image: "docker.io/craftcms/nginx:7.4-dev",
+			Labels: map[string]string{
+				containerlabels.Host:    "newname",
+				containerlabels.Webroot: "web",
+			},
+			Env: []string{"PHP_MEMORY_LIMIT=256M"},
+		},
+		Mounts: []types.MountPoint{
+			{
+				Source: filepath.Join(wd, "testdata", "example-site"),
+			},
+		},
+	}
+
+	want := []Change{
+		{Name: "image", Expected: "docker.io/craftcms/nginx:8.0-dev", Actual: "docker.io/craftcms/nginx:7.4-dev"},
+		{Name: "env PHP_MEMORY_LIMIT", Expected: "512M", Actual: "256M"},
+	}
+
+	got := SiteChanges("testdata/example-site", site, details, config.Blackfire{})
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SiteChanges() = %v, want %v", got, want)
+	}
+}
+
+func TestSiteChanges_PHPSettings(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	site := config.Site{
+		Hostname: "newname",
+		Path:     "testdata/example-site",
+		Version:  "8.0",
+		Webroot:  "web",
+		PHP: config.PHP{
+			UploadMaxFileSize:         "128M",
+			OpcacheValidateTimestamps: true,
+		},
+	}
+
+	details := types.ContainerJSON{
+		Config: &container.Config{
+			Image: "docker.io/craftcms/nginx:8.0-dev",
+			Labels: map[string]string{
+				containerlabels.Host:    "newname",
+				containerlabels.Webroot: "web",
+			},
+			Env: []string{"PHP_UPLOAD_MAX_FILESIZE=512M", "PHP_OPCACHE_VALIDATE_TIMESTAMPS=0", "PHP_POST_MAX_SIZE=512M"},
+		},
+		Mounts: []types.MountPoint{
+			{
+				Source: filepath.Join(wd, "testdata", "example-site"),
+			},
+		},
+	}
+
+	want := []Change{
+		{Name: "env PHP_UPLOAD_MAX_FILESIZE", Expected: "128M", Actual: "512M"},
+		{Name: "env PHP_OPCACHE_VALIDATE_TIMESTAMPS", Expected: "1", Actual: "0"},
+	}
+
+	got := SiteChanges("testdata/example-site", site, details, config.Blackfire{})
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SiteChanges() = %v, want %v", got, want)
+	}
+}
+
+func TestMount(t *testing.T) {
+	site := config.Site{Hostname: "craft.nitro"}
+	synced := config.Site{Hostname: "craft.nitro", Sync: config.SyncMutagen}
+
+	bind := []types.MountPoint{{Type: "bind", Source: "/home/nitro/craft"}}
+	volume := []types.MountPoint{{Type: "volume", Name: "nitro-sync-craft_nitro", Source: "/var/lib/docker/volumes/nitro-sync-craft_nitro/_data"}}
+
+	if _, ok := Mount("/home/nitro/craft", site, bind); !ok {
+		t.Errorf("expected the bind mount of the path to match")
+	}
+
+	if _, ok := Mount("/home/nitro/craft", synced, volume); !ok {
+		t.Errorf("expected the sync volume to match")
+	}
+
+	if change, ok := Mount("/home/nitro/craft", synced, bind); ok || change.Expected != "volume nitro-sync-craft_nitro" {
+		t.Errorf("expected a change when the synced site uses a bind mount, got %+v", change)
+	}
+
+	if _, ok := Mount("/home/nitro/craft", site, volume); ok {
+		t.Errorf("expected a change when the site uses the sync volume")
+	}
+}
+
+func TestMount_Symlinks(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nitro-mount")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	real := filepath.Join(dir, "craft")
+	if err := os.Mkdir(real, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	link := filepath.Join(dir, "link")
+	if err := os.Symlink(real, link); err != nil {
+		t.Skip("unable to create a symlink:", err)
+	}
+
+	site := config.Site{Hostname: "craft.nitro"}
+	bind := []types.MountPoint{{Type: "bind", Source: link}}
+
+	if _, ok := Mount(real, site, bind); !ok {
+		t.Errorf("expected the mount of the symlink to match the real path")
+	}
+
+	if _, ok := Mount(dir, site, bind); ok {
+		t.Errorf("expected a change when the mount is another directory")
+	}
+}
@@ -0,0 +1,173 @@
+package nginx
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/craftcms/nitro/pkg/config"
+)
+
+var conf = `server {
+    listen      8080 default_server;
+    listen      [::]:8080 default_server;%s
+    server_name _;
+    set         $base /app;
+    root        $base/%s;
+
+    proxy_send_timeout 240s;
+    proxy_read_timeout 240s;
+    fastcgi_send_timeout 240s;
+    fastcgi_read_timeout 240s;
+
+    # security
+    include     craftcms/security.conf;
+
+    # include custom conf files
+    include     /app/*nitro.conf;
+%s
+    # index.php
+    index       index.php;
+
+    # index.php fallback
+    location / {
+        try_files $uri $uri/ /index.php?$query_string;
+    }
+
+    # additional config
+    include craftcms/general.conf;
+
+    # handle .php
+    location ~ \.php$ {
+        include craftcms/php_fastcgi.conf;
+    }
+
+    # Allow fpm ping and status from localhost
+    location ~ ^/(fpm-status|fpm-ping)$ {
+        access_log off;
+        allow 127.0.0.1;
+        deny all;
+        fastcgi_param SCRIPT_FILENAME $document_root$fastcgi_script_name;
+        include fastcgi_params;
+        fastcgi_pass 127.0.0.1:9000;
+    }
+}`
+
+var banner = `
+    # nitro debug banner
+    sub_filter '</body>' '<div style="position:fixed;bottom:0;right:0;z-index:2147483647;padding:4px 8px;background:#e5422b;color:#fff;font:12px/1.5 sans-serif">%s &middot; PHP %s</div></body>';
+    sub_filter_once on;
+`
+
+var websocket = `
+    # websocket
+    location %s {
+        proxy_pass %s;
+        proxy_http_version 1.1;
+        proxy_set_header Upgrade $http_upgrade;
+        proxy_set_header Connection "upgrade";
+        proxy_set_header Host $host;
+        proxy_read_timeout 3600s;
+    }
+`
+
+// Options are used to generate the nginx config for a site.
+type Options struct {
+	// Root is the web root of the site, it defaults to web
+	Root string
+
+	// Banner adds a banner with the hostname and PHP version to each HTML page
+	Banner   bool
+	Hostname string
+	Version  string
+
+	// Nginx are the options from the nginx block of the site
+	Nginx config.Nginx
+}
+
+// NewOptions returns the options to generate the nginx config for the site.
+func NewOptions(site config.Site, banner bool) Options {
+	return Options{
+		Root:     site.Webroot,
+		Banner:   banner,
+		Hostname: site.Hostname,
+		Version:  site.Version,
+		Nginx:    site.Nginx,
+	}
+}
+
+// IsDefault returns true when the options generate the default config of the image.
+func (o Options) IsDefault() bool {
+	return (o.Root == "" || o.Root == "web") && !o.Banner && o.Nginx.IsEmpty()
+}
+
+// Fingerprint returns a short hash of the nginx options, it is empty when the options
+// are not set. The fingerprint is used to recreate the container when the options change.
+func Fingerprint(n config.Nginx) string {
+	if n.IsEmpty() {
+		return ""
+	}
+
+	b, err := json.Marshal(n)
+	if err != nil {
+		return ""
+	}
+
+	return fmt.Sprintf("%x", sha256.Sum256(b))[:12]
+}
+
+// Generate takes the options and generates a nginx configuration file
+func Generate(opts Options) string {
+	// if the root was not provided, default to web
+	root := opts.Root
+	if root == "" {
+		root = "web"
+	}
+
+	var listen string
+	if opts.Nginx.UsesHTTP2() {
+		listen = fmt.Sprintf("\n    listen      %d http2;", config.NginxHTTP2Port)
+	}
+
+	var extra strings.Builder
+	if opts.Banner {
+		extra.WriteString(fmt.Sprintf(banner, opts.Hostname, opts.Version))
+	}
+
+	if gzip := opts.Nginx.Gzip; gzip.Enabled {
+		extra.WriteString("\n    # gzip\n    gzip on;\n    gzip_vary on;\n    gzip_proxied any;\n")
+
+		if gzip.Level > 0 {
+			extra.WriteString(fmt.Sprintf("    gzip_comp_level %d;\n", gzip.Level))
+		}
+
+		if len(gzip.Types) > 0 {
+			extra.WriteString(fmt.Sprintf("    gzip_types %s;\n", strings.Join(gzip.Types, " ")))
+		}
+	}
+
+	for _, ws := range opts.Nginx.Websockets {
+		upstream := ws.Upstream
+		if !strings.Contains(upstream, "://") {
+			upstream = "http://" + upstream
+		}
+
+		extra.WriteString(fmt.Sprintf(websocket, ws.Path, upstream))
+	}
+
+	if len(opts.Nginx.Directives) > 0 {
+		extra.WriteString("\n    # custom directives\n")
+
+		for _, d := range opts.Nginx.Directives {
+			d = strings.TrimSpace(d)
+			if !strings.HasSuffix(d, ";") && !strings.HasSuffix(d, "}") {
+				d += ";"
+			}
+
+			extra.WriteString("    " + d + "\n")
+		}
+	}
+
+	return fmt.Sprintf(conf, listen, root, extra.String())
+}
@@ -0,0 +1,155 @@
+package nginx
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/craftcms/nitro/pkg/config"
+)
+
+func TestGenerate(t *testing.T) {
+	type args struct {
+		root string
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "defaults to web",
+			args: args{
+				root: "",
+			},
+			want: defaultConf,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Generate(Options{Root: tt.args.root}); got != tt.want {
+				t.Errorf("Generate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+var defaultConf = `server {
+    listen      8080 default_server;
+    listen      [::]:8080 default_server;
+    server_name _;
+    set         $base /app;
+    root        $base/web;
+
+    proxy_send_timeout 240s;
+    proxy_read_timeout 240s;
+    fastcgi_send_timeout 240s;
+    fastcgi_read_timeout 240s;
+
+    # security
+    include     craftcms/security.conf;
+
+    # include custom conf files
+    include     /app/*nitro.conf;
+
+    # index.php
+    index       index.php;
+
+    # index.php fallback
+    location / {
+        try_files $uri $uri/ /index.php?$query_string;
+    }
+
+    # additional config
+    include craftcms/general.conf;
+
+    # handle .php
+    location ~ \.php$ {
+        include craftcms/php_fastcgi.conf;
+    }
+
+    # Allow fpm ping and status from localhost
+    location ~ ^/(fpm-status|fpm-ping)$ {
+        access_log off;
+        allow 127.0.0.1;
+        deny all;
+        fastcgi_param SCRIPT_FILENAME $document_root$fastcgi_script_name;
+        include fastcgi_params;
+        fastcgi_pass 127.0.0.1:9000;
+    }
+}`
+
+func TestGenerateWithBanner(t *testing.T) {
+	got := Generate(Options{Banner: true, Hostname: "tutorial.nitro", Version: "7.4"})
+
+	if !strings.Contains(got, "root        $base/web;") {
+		t.Errorf("expected the root to default to web, got %v", got)
+	}
+
+	if !strings.Contains(got, `sub_filter '</body>'`) || !strings.Contains(got, "tutorial.nitro &middot; PHP 7.4") {
+		t.Errorf("expected the banner to be included, got %v", got)
+	}
+}
+
+func TestGenerateWithNginxOptions(t *testing.T) {
+	got := Generate(Options{Nginx: config.Nginx{
+		HTTP2: true,
+		Gzip:  config.Gzip{Enabled: true, Level: 5, Types: []string{"text/css", "application/javascript"}},
+		Websockets: []config.Websocket{
+			{Path: "/socket", Upstream: "node.containers.nitro:6001"},
+		},
+		Directives: []string{"client_max_body_size 256m", "add_header X-Frame-Options SAMEORIGIN;"},
+	}})
+
+	for _, want := range []string{
+		"gzip on;",
+		"gzip_comp_level 5;",
+		"gzip_types text/css application/javascript;",
+		"location /socket {",
+		"proxy_pass http://node.containers.nitro:6001;",
+		`proxy_set_header Connection "upgrade";`,
+		"    client_max_body_size 256m;\n",
+		"    add_header X-Frame-Options SAMEORIGIN;\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in the config, got %v", want, got)
+		}
+	}
+
+	// websockets require HTTP/1.1 from the proxy
+	if strings.Contains(got, "http2") {
+		t.Errorf("expected HTTP/2 to be ignored for sites with websockets, got %v", got)
+	}
+
+	got = Generate(Options{Nginx: config.Nginx{HTTP2: true}})
+	if !strings.Contains(got, "listen      8081 http2;") {
+		t.Errorf("expected the HTTP/2 listener, got %v", got)
+	}
+}
+
+func TestOptions_IsDefault(t *testing.T) {
+	site := config.Site{Hostname: "tutorial.nitro", Webroot: "web", Version: "8.0"}
+	if !NewOptions(site, false).IsDefault() {
+		t.Errorf("expected the options for a site without changes to be the default")
+	}
+
+	if NewOptions(site, true).IsDefault() {
+		t.Errorf("expected the banner to change the config")
+	}
+
+	site.Nginx.Directives = []string{"client_max_body_size 256m"}
+	if NewOptions(site, false).IsDefault() {
+		t.Errorf("expected the nginx options to change the config")
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	if got := Fingerprint(config.Nginx{}); got != "" {
+		t.Errorf("expected no fingerprint without options, got %q", got)
+	}
+
+	a := Fingerprint(config.Nginx{Directives: []string{"client_max_body_size 256m"}})
+	b := Fingerprint(config.Nginx{Directives: []string{"client_max_body_size 512m"}})
+	if a == "" || a == b {
+		t.Errorf("expected the fingerprint to change with the options, got %q and %q", a, b)
+	}
+}
@@ -0,0 +1,335 @@
+package plan
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+
+	"github.com/craftcms/nitro/command/internal/backupcontainer"
+	"github.com/craftcms/nitro/command/internal/customcontainer"
+	"github.com/craftcms/nitro/command/internal/match"
+	"github.com/craftcms/nitro/command/internal/service"
+	"github.com/craftcms/nitro/command/internal/sitecontainer"
+	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/environment"
+	"github.com/craftcms/nitro/pkg/terminal"
+)
+
+// Action is the change apply will make to a resource.
+type Action string
+
+const (
+	// Create is used when the resource does not exist
+	Create Action = "create"
+
+	// Update is used when the resource exists but does not match the config
+	Update Action = "update"
+
+	// Remove is used when the resource exists but is not in the config
+	Remove Action = "remove"
+)
+
+// Step is a single change apply will make to the environment.
+type Step struct {
+	Action   Action         `json:"action"`
+	Resource string         `json:"resource"`
+	Name     string         `json:"name"`
+	Changes  []match.Change `json:"changes,omitempty"`
+}
+
+// Plan is the list of changes required to make the environment match the config.
+type Plan struct {
+	Steps []Step `json:"steps"`
+}
+
+// Build compares the config against the networks and containers in docker and returns
+// the plan apply would perform. It does not make any changes to the environment.
+func Build(ctx context.Context, docker client.CommonAPIClient, home string, cfg *config.Config) (*Plan, error) {
+	p := &Plan{}
+
+	// find the network
+	filter := filters.NewArgs()
+	filter.Add("name", environment.Network())
+
+	networks, err := docker.NetworkList(ctx, types.NetworkListOptions{Filters: filter})
+	if err != nil {
+		return nil, fmt.Errorf("unable to list docker networks, %w", err)
+	}
+
+	var networkID string
+	for _, n := range networks {
+		if n.Name == environment.Network() {
+			networkID = n.ID
+		}
+	}
+
+	if networkID == "" {
+		p.add(Step{Action: Create, Resource: "network", Name: environment.Network()})
+	}
+
+	// get all of the containers for the environment
+	filter = filters.NewArgs()
+	filter.Add("label", containerlabels.Nitro+"="+environment.Label())
+
+	containers, err := docker.ContainerList(ctx, types.ContainerListOptions{All: true, Filters: filter})
+	if err != nil {
+		return nil, fmt.Errorf("unable to list the containers, %w", err)
+	}
+
+	// track the containers that are part of the plan
+	known := map[string]bool{}
+	find := func(labels map[string]string) *types.Container {
+		for i, c := range containers {
+			if matches(c.Labels, labels) {
+				known[c.ID] = true
+				return &containers[i]
+			}
+		}
+
+		return nil
+	}
+
+	// check the proxy
+	if c := find(map[string]string{containerlabels.Proxy: "true"}); c == nil {
+		p.add(Step{Action: Create, Resource: "proxy", Name: environment.Proxy()})
+	} else {
+		p.update("proxy", environment.Proxy(), status(*c, networkID))
+	}
+
+	// check the databases
+	for _, db := range cfg.Databases {
+		hostname, err := db.GetHostname()
+		if err != nil {
+			return nil, err
+		}
+
+		c := find(map[string]string{
+			containerlabels.Type:            "database",
+			containerlabels.DatabaseEngine:  db.Engine,
+			containerlabels.DatabaseVersion: db.Version,
+			containerlabels.DatabasePort:    db.Port,
+		})
+		if c == nil {
+			p.add(Step{Action: Create, Resource: "database", Name: hostname})
+			continue
+		}
+
+		p.update("database", hostname, status(*c, networkID))
+	}
+
+	// check the services
+	for _, s := range service.Services {
+		c := find(map[string]string{containerlabels.Type: s.Label})
+
+		switch enabled := cfg.Services.IsEnabled(s.Name); {
+		case enabled && c == nil:
+			p.add(Step{Action: Create, Resource: "service", Name: s.Host})
+		case enabled:
+			p.update("service", s.Host, status(*c, networkID))
+		case c != nil:
+			p.add(Step{Action: Remove, Resource: "service", Name: s.Host})
+		}
+	}
+
+	// check the custom containers and each replica
+	for _, cc := range cfg.Containers {
+		replicas := map[int]*types.Container{}
+		for i, c := range containers {
+			if c.Labels[containerlabels.NitroContainer] != cc.Name {
+				continue
+			}
+
+			known[c.ID] = true
+
+			// remove the replicas that are not needed
+			n := customcontainer.Replica(c.Labels)
+			if n > cc.GetReplicas() {
+				p.add(Step{Action: Remove, Resource: "container", Name: name(c)})
+				continue
+			}
+
+			replicas[n] = &containers[i]
+		}
+
+		for n := 1; n <= cc.GetReplicas(); n++ {
+			hostname := cc.GetReplicaHostname(n)
+
+			c, ok := replicas[n]
+			if !ok {
+				p.add(Step{Action: Create, Resource: "container", Name: hostname})
+				continue
+			}
+
+			details, err := docker.ContainerInspect(ctx, c.ID)
+			if err != nil {
+				return nil, fmt.Errorf("unable to inspect the container %s, %w", hostname, err)
+			}
+
+			p.update("container", hostname, append(status(*c, networkID), match.ContainerChanges(home, cc, details)...))
+		}
+	}
+
+	// check the sites
+	for _, site := range cfg.Sites {
+		c := find(map[string]string{containerlabels.Host: site.Hostname})
+		if c == nil {
+			p.add(Step{Action: Create, Resource: "site", Name: site.Hostname})
+			continue
+		}
+
+		details, err := docker.ContainerInspect(ctx, c.ID)
+		if err != nil {
+			return nil, fmt.Errorf("unable to inspect the container %s, %w", site.Hostname, err)
+		}
+
+		p.update("site", site.Hostname, append(status(*c, networkID), sitecontainer.Changes(home, site, details, cfg)...))
+	}
+
+	// check the container for the scheduled backups
+	switch c := find(map[string]string{containerlabels.Type: backupcontainer.Label}); {
+	case cfg.Backups.Enabled() && c == nil:
+		p.add(Step{Action: Create, Resource: "service", Name: backupcontainer.Name})
+	case cfg.Backups.Enabled():
+		details, err := docker.ContainerInspect(ctx, c.ID)
+		if err != nil {
+			return nil, fmt.Errorf("unable to inspect the container %s, %w", backupcontainer.Name, err)
+		}
+
+		p.update("service", backupcontainer.Name, append(status(*c, networkID), backupcontainer.Changes(cfg, details)...))
+	case c != nil:
+		p.add(Step{Action: Remove, Resource: "service", Name: backupcontainer.Name})
+	}
+
+	// containers that are not in the config are removed, except for share tunnels
+	for _, c := range containers {
+		if known[c.ID] || c.Labels[containerlabels.Type] == "share" {
+			continue
+		}
+
+		p.add(Step{Action: Remove, Resource: "container", Name: name(c)})
+	}
+
+	return p, nil
+}
+
+// Count returns the number of steps for the action.
+func (p *Plan) Count(action Action) int {
+	count := 0
+	for _, s := range p.Steps {
+		if s.Action == action {
+			count++
+		}
+	}
+
+	return count
+}
+
+// Print writes the plan to w. Removed values are shown in red and the values from
+// the config in green. Colors are disabled with --no-color or the NO_COLOR environment variable.
+func (p *Plan) Print(w io.Writer) {
+	if len(p.Steps) == 0 {
+		fmt.Fprintln(w, "No changes, the environment matches the config.")
+		return
+	}
+
+	fmt.Fprintln(w, "Apply will make the following changes:")
+	fmt.Fprintln(w, "")
+
+	for _, s := range p.Steps {
+		switch s.Action {
+		case Create:
+			fmt.Fprintf(w, "  %s\n", terminal.Color(terminal.Green, fmt.Sprintf("+ create %s %s", s.Resource, s.Name)))
+		case Remove:
+			fmt.Fprintf(w, "  %s\n", terminal.Color(terminal.Red, fmt.Sprintf("- remove %s %s", s.Resource, s.Name)))
+		default:
+			fmt.Fprintf(w, "  %s\n", terminal.Color(terminal.Yellow, fmt.Sprintf("~ update %s %s", s.Resource, s.Name)))
+		}
+
+		for _, c := range s.Changes {
+			actual, expected := c.Actual, c.Expected
+
+			// do not show credentials in the output
+			if strings.Contains(c.Name, "TOKEN") {
+				actual, expected = mask(actual), mask(expected)
+			}
+
+			fmt.Fprintf(w, "      %s:\n", c.Name)
+			fmt.Fprintf(w, "        %s\n", terminal.Color(terminal.Red, "- "+actual))
+			fmt.Fprintf(w, "        %s\n", terminal.Color(terminal.Green, "+ "+expected))
+		}
+	}
+
+	fmt.Fprintln(w, "")
+	fmt.Fprintf(w, "Plan: %d to create, %d to update, %d to remove.\n", p.Count(Create), p.Count(Update), p.Count(Remove))
+}
+
+func (p *Plan) add(s Step) {
+	p.Steps = append(p.Steps, s)
+}
+
+// update adds an update step when there are changes for the resource.
+func (p *Plan) update(resource, name string, changes []match.Change) {
+	if len(changes) == 0 {
+		return
+	}
+
+	p.add(Step{Action: Update, Resource: resource, Name: name, Changes: changes})
+}
+
+// status returns the changes required for the container to be running and
+// attached to the network, which apply reconciles before checking the config.
+func status(c types.Container, networkID string) []match.Change {
+	var changes []match.Change
+
+	if c.State != "running" {
+		changes = append(changes, match.Change{Name: "state", Expected: "running", Actual: c.State})
+	}
+
+	if networkID != "" && c.NetworkSettings != nil {
+		connected := false
+		for _, n := range c.NetworkSettings.Networks {
+			if n != nil && n.NetworkID == networkID {
+				connected = true
+			}
+		}
+
+		if !connected {
+			changes = append(changes, match.Change{Name: "network", Expected: environment.Network(), Actual: "not connected"})
+		}
+	}
+
+	return changes
+}
+
+// matches returns true when all of the wanted labels are set.
+func matches(labels, want map[string]string) bool {
+	for k, v := range want {
+		if labels[k] != v {
+			return false
+		}
+	}
+
+	return true
+}
+
+func name(c types.Container) string {
+	if len(c.Names) == 0 {
+		return c.ID
+	}
+
+	return strings.TrimLeft(c.Names[0], "/")
+}
+
+func mask(s string) string {
+	if s == "" {
+		return s
+	}
+
+	return "********"
+}
@@ -0,0 +1,136 @@
+package plan
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/client"
+
+	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/containerlabels"
+)
+
+func TestBuild(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nitro-plan")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	attached := &types.SummaryNetworkSettings{
+		Networks: map[string]*network.EndpointSettings{
+			"nitro-network": {NetworkID: "network-id"},
+		},
+	}
+
+	cfg := &config.Config{
+		Databases: []config.Database{
+			{Engine: "mysql", Version: "8.0", Port: "3306"},
+			{Engine: "postgres", Version: "13", Port: "5432"},
+		},
+		Services: config.Services{Redis: true},
+		Sites: []config.Site{
+			{Hostname: "current.nitro", Path: dir, Version: "8.0"},
+			{Hostname: "new.nitro", Path: dir, Version: "8.0"},
+		},
+	}
+
+	spy := &mockClient{
+		networks: []types.NetworkResource{{ID: "network-id", Name: "nitro-network"}},
+		containers: []types.Container{
+			{ID: "proxy", Names: []string{"/nitro-proxy"}, State: "running", NetworkSettings: attached, Labels: map[string]string{containerlabels.Nitro: "true", containerlabels.Proxy: "true"}},
+			{ID: "mysql", Names: []string{"/mysql-8.0-3306.database.nitro"}, State: "exited", NetworkSettings: attached, Labels: map[string]string{containerlabels.Nitro: "true", containerlabels.Type: "database", containerlabels.DatabaseEngine: "mysql", containerlabels.DatabaseVersion: "8.0", containerlabels.DatabasePort: "3306"}},
+			{ID: "mailhog", Names: []string{"/mailhog.service.nitro"}, State: "running", NetworkSettings: attached, Labels: map[string]string{containerlabels.Nitro: "true", containerlabels.Type: "mailhog"}},
+			{ID: "site", Names: []string{"/current.nitro"}, State: "running", NetworkSettings: attached, Labels: map[string]string{containerlabels.Nitro: "true", containerlabels.Host: "current.nitro"}},
+			{ID: "old", Names: []string{"/old.nitro"}, State: "running", NetworkSettings: attached, Labels: map[string]string{containerlabels.Nitro: "true", containerlabels.Host: "old.nitro"}},
+		},
+		details: map[string]types.ContainerJSON{
+			"site": {
+				Config: &container.Config{
+					Image:  "docker.io/craftcms/nginx:7.4-dev",
+					Labels: map[string]string{containerlabels.Host: "current.nitro"},
+				},
+			},
+		},
+	}
+
+	p, err := Build(context.Background(), spy, dir, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, s := range p.Steps {
+		got = append(got, string(s.Action)+" "+s.Resource+" "+s.Name)
+	}
+
+	want := []string{
+		"update database mysql-8.0-3306.database.nitro",
+		"create database postgres-13-5432.database.nitro",
+		"remove service mailhog.service.nitro",
+		"create service redis.service.nitro",
+		"update site current.nitro",
+		"create site new.nitro",
+		"remove container old.nitro",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected the steps to match\ngot:\n%v\nwant:\n%v", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	// the image change is shown for the site
+	os.Setenv("NO_COLOR", "true")
+	defer os.Unsetenv("NO_COLOR")
+
+	buf := &bytes.Buffer{}
+	p.Print(buf)
+
+	for _, s := range []string{"~ update site current.nitro", "- docker.io/craftcms/nginx:7.4-dev", "+ docker.io/craftcms/nginx:8.0-dev", "Plan: 3 to create, 2 to update, 2 to remove."} {
+		if !strings.Contains(buf.String(), s) {
+			t.Errorf("expected the output to contain %q, got:\n%s", s, buf.String())
+		}
+	}
+}
+
+func TestPrint_NoChanges(t *testing.T) {
+	buf := &bytes.Buffer{}
+	(&Plan{}).Print(buf)
+
+	if got := buf.String(); got != "No changes, the environment matches the config.\n" {
+		t.Errorf("unexpected output %q", got)
+	}
+}
+
+type mockClient struct {
+	client.CommonAPIClient
+
+	networks   []types.NetworkResource
+	containers []types.Container
+	details    map[string]types.ContainerJSON
+}
+
+func (c *mockClient) NetworkList(ctx context.Context, options types.NetworkListOptions) ([]types.NetworkResource, error) {
+	return c.networks, nil
+}
+
+func (c *mockClient) ContainerList(ctx context.Context, options types.ContainerListOptions) ([]types.Container, error) {
+	var containers []types.Container
+	for _, container := range c.containers {
+		if options.Filters.MatchKVList("label", container.Labels) {
+			containers = append(containers, container)
+		}
+	}
+
+	return containers, nil
+}
+
+func (c *mockClient) ContainerInspect(ctx context.Context, container string) (types.ContainerJSON, error) {
+	return c.details[container], nil
+}
@@ -0,0 +1,148 @@
+package preflight
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+
+	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/environment"
+	"github.com/craftcms/nitro/pkg/portavail"
+)
+
+// inUse checks if a port is used by another process, it is a variable for testing.
+var inUse = portavail.InUse
+
+// Conflict is a host port of the environment that is used by another process.
+type Conflict struct {
+	config.HostPort
+
+	// Next is the free port that is used instead when the ports are selected automatically
+	Next string
+
+	// Exists is true when the container was already created with the port, so the port
+	// can not be changed automatically
+	Exists bool
+}
+
+// ConflictError is returned by Ports with all of the ports that are in use, so they can be
+// fixed at once.
+type ConflictError struct {
+	Conflicts []Conflict
+}
+
+func (e *ConflictError) Error() string {
+	var b strings.Builder
+
+	b.WriteString("the following ports are already in use:")
+
+	for _, c := range e.Conflicts {
+		fmt.Fprintf(&b, "\n  - port %s for %s (set with %s)", c.Port, c.Container, c.Setting)
+
+		if c.Exists {
+			b.WriteString(", the container already exists")
+		}
+	}
+
+	b.WriteString("\nstop the processes using the ports, change the ports, or use --auto-ports to use the next free ports")
+
+	return b.String()
+}
+
+// Ports checks each of the host ports the containers of the environment bind before the
+// containers are created. The ports of the running containers of the environment are
+// skipped. When auto is true, the next free port is set in the config for each port that
+// is in use, and the conflicts are returned with the port that is used instead. Otherwise
+// an error with every conflict is returned. The ports of containers that already exist can
+// not be changed and are always returned as an error.
+func Ports(ctx context.Context, docker client.ContainerAPIClient, cfg *config.Config, auto bool) ([]Conflict, error) {
+	filter := filters.NewArgs()
+	filter.Add("label", containerlabels.Nitro+"="+environment.Label())
+
+	containers, err := docker.ContainerList(ctx, types.ContainerListOptions{All: true, Filters: filter})
+	if err != nil {
+		return nil, fmt.Errorf("unable to list the containers, %w", err)
+	}
+
+	// the ports of the running containers are used by the environment
+	exists := make(map[string]bool)
+	published := make(map[string]bool)
+	for _, c := range containers {
+		for _, n := range c.Names {
+			exists[strings.TrimLeft(n, "/")] = true
+		}
+
+		if c.State != "running" {
+			continue
+		}
+
+		for _, p := range c.Ports {
+			if p.PublicPort != 0 {
+				published[strconv.Itoa(int(p.PublicPort))] = true
+			}
+		}
+	}
+
+	ports := cfg.HostPorts()
+
+	// the next free ports can not be one of the other ports of the environment
+	reserved := make(map[string]bool)
+	for _, p := range ports {
+		reserved[p.Port] = true
+	}
+
+	var conflicts, unresolved []Conflict
+	for _, p := range ports {
+		if published[p.Port] || !inUse("", p.Port) {
+			continue
+		}
+
+		if !auto || exists[p.Container] {
+			unresolved = append(unresolved, Conflict{HostPort: p, Exists: exists[p.Container]})
+			continue
+		}
+
+		next, err := findNext(p.Port, reserved)
+		if err != nil {
+			return nil, err
+		}
+
+		reserved[next] = true
+		p.Set(next)
+
+		conflicts = append(conflicts, Conflict{HostPort: p, Next: next})
+	}
+
+	if len(unresolved) > 0 {
+		return conflicts, &ConflictError{Conflicts: unresolved}
+	}
+
+	return conflicts, nil
+}
+
+// findNext returns the next free port after the port that is not reserved for another
+// container of the environment.
+func findNext(port string, reserved map[string]bool) (string, error) {
+	p, err := strconv.Atoi(port)
+	if err != nil {
+		return "", fmt.Errorf("the port %q must be a number", port)
+	}
+
+	for p++; p <= 65535; p++ {
+		if reserved[strconv.Itoa(p)] {
+			continue
+		}
+
+		if !inUse("", strconv.Itoa(p)) {
+			return strconv.Itoa(p), nil
+		}
+	}
+
+	return "", fmt.Errorf("unable to find a free port after %s", port)
+}
@@ -0,0 +1,97 @@
+package preflight
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+
+	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/environment"
+	"github.com/craftcms/nitro/pkg/portavail"
+)
+
+func TestPorts(t *testing.T) {
+	used := map[string]bool{"80": true, "3306": true, "3307": true, "9200": true}
+	inUse = func(host, port string) bool { return used[port] }
+	defer func() { inUse = portavail.InUse }()
+
+	newConfig := func() *config.Config {
+		return &config.Config{
+			Databases:  []config.Database{{Engine: "mysql", Version: "8.0", Port: "3306"}},
+			Sites:      []config.Site{{Hostname: "craft.nitro", DatabaseEngine: "mysql-8.0-3306.database.nitro"}},
+			Containers: []config.Container{{Name: "elastic", Ports: []string{"9200:9200", "3308:9300"}}},
+		}
+	}
+
+	// the running proxy publishes port 80
+	running := types.Container{Names: []string{"/" + environment.Proxy()}, State: "running", Ports: []types.Port{{PublicPort: 80, PrivatePort: 80}}}
+
+	t.Run("every port in use is returned", func(t *testing.T) {
+		_, err := Ports(context.Background(), &mockClient{containers: []types.Container{running}}, newConfig(), false)
+
+		var conflicts *ConflictError
+		if !errors.As(err, &conflicts) {
+			t.Fatalf("expected the conflicts to be returned, got %v", err)
+		}
+
+		var ports []string
+		for _, c := range conflicts.Conflicts {
+			ports = append(ports, c.Port)
+		}
+
+		if strings.Join(ports, ",") != "3306,9200" {
+			t.Errorf("expected the ports of the database and container, got %v", ports)
+		}
+	})
+
+	t.Run("the next free ports are set in the config", func(t *testing.T) {
+		cfg := newConfig()
+
+		changed, err := Ports(context.Background(), &mockClient{containers: []types.Container{running}}, cfg, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(changed) != 2 || changed[0].Next != "3309" || changed[1].Next != "9201" {
+			t.Errorf("expected the ports after the used and reserved ports, got %v", changed)
+		}
+
+		if cfg.Databases[0].Port != "3309" || cfg.Sites[0].DatabaseEngine != "mysql-8.0-3309.database.nitro" {
+			t.Errorf("expected the database and the site to use the new port, got %v and %v", cfg.Databases[0], cfg.Sites[0].DatabaseEngine)
+		}
+
+		if cfg.Containers[0].Ports[0] != "9201:9200" {
+			t.Errorf("expected the host port of the container to change, got %v", cfg.Containers[0].Ports)
+		}
+	})
+
+	t.Run("the ports of existing containers are not changed", func(t *testing.T) {
+		cfg := newConfig()
+		stopped := types.Container{Names: []string{"/mysql-8.0-3306.database.nitro"}, State: "exited"}
+
+		_, err := Ports(context.Background(), &mockClient{containers: []types.Container{running, stopped}}, cfg, true)
+
+		var conflicts *ConflictError
+		if !errors.As(err, &conflicts) || len(conflicts.Conflicts) != 1 || !conflicts.Conflicts[0].Exists {
+			t.Fatalf("expected the database to be returned as a conflict, got %v", err)
+		}
+
+		if cfg.Databases[0].Port != "3306" {
+			t.Errorf("expected the port of the existing database to be kept, got %s", cfg.Databases[0].Port)
+		}
+	})
+}
+
+type mockClient struct {
+	client.ContainerAPIClient
+
+	containers []types.Container
+}
+
+func (c *mockClient) ContainerList(ctx context.Context, options types.ContainerListOptions) ([]types.Container, error) {
+	return c.containers, nil
+}
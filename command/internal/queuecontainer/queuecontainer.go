@@ -0,0 +1,202 @@
+package queuecontainer
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/client"
+
+	"github.com/craftcms/nitro/command/internal/match"
+	"github.com/craftcms/nitro/command/internal/sitecontainer"
+	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/environment"
+	"github.com/craftcms/nitro/pkg/limits"
+	"github.com/craftcms/nitro/pkg/logconfig"
+)
+
+// Name returns the name of the container for the queue worker of the site.
+func Name(hostname string, n int) string {
+	return fmt.Sprintf("%s-queue-%d", hostname, n)
+}
+
+// Command returns the command that runs the queue worker for the site.
+func Command(site config.Site) []string {
+	return []string{"php", path.Join("/app", site.GetContainerPath(), "craft"), "queue/listen", "--verbose"}
+}
+
+// Reconcile makes sure each site has a container for each of its queue workers and that the
+// containers match the config. Workers for sites that are not in the config, or that are no
+// longer needed, are removed. The workers run the image of the site and docker restarts them
+// when the worker fails.
+func Reconcile(ctx context.Context, docker client.CommonAPIClient, home, networkID string, cfg *config.Config) error {
+	filter := filters.NewArgs()
+	filter.Add("label", containerlabels.Nitro+"="+environment.Label())
+	filter.Add("label", containerlabels.Queue)
+
+	containers, err := docker.ContainerList(ctx, types.ContainerListOptions{All: true, Filters: filter})
+	if err != nil {
+		return fmt.Errorf("unable to list the queue workers, %w", err)
+	}
+
+	// the workers that are in the config
+	type worker struct {
+		site config.Site
+		n    int
+	}
+
+	wanted := make(map[string]worker)
+	for _, s := range cfg.Sites {
+		for n := 1; n <= int(s.Queue); n++ {
+			wanted[Name(s.Hostname, n)] = worker{site: s, n: n}
+		}
+	}
+
+	existing := make(map[string]types.Container)
+	for _, c := range containers {
+		name := strings.TrimLeft(c.Names[0], "/")
+
+		w, ok := wanted[name]
+		if ok {
+			details, err := docker.ContainerInspect(ctx, c.ID)
+			if err != nil {
+				return err
+			}
+
+			path, err := w.site.GetAbsPath(home)
+			if err != nil {
+				return err
+			}
+
+			_, mounted := match.Mount(path, w.site, details.Mounts)
+
+			ok = mounted && !changed(details, Config(home, w.site, cfg, w.n)) && !logconfig.Changed(cfg.SiteLogs(w.site), details) && !limits.Changed(w.site.Resources(), details)
+		}
+
+		// remove the workers that are not needed or out of date
+		if !ok {
+			if err := docker.ContainerRemove(ctx, c.ID, types.ContainerRemoveOptions{Force: true}); err != nil {
+				return fmt.Errorf("unable to remove the queue worker %s, %w", name, err)
+			}
+
+			continue
+		}
+
+		existing[name] = c
+	}
+
+	var names []string
+	for name := range wanted {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if c, ok := existing[name]; ok {
+			if c.State != "running" {
+				if err := docker.ContainerStart(ctx, c.ID, types.ContainerStartOptions{}); err != nil {
+					return fmt.Errorf("unable to start the queue worker %s, %w", name, err)
+				}
+			}
+
+			continue
+		}
+
+		w := wanted[name]
+		if err := create(ctx, docker, home, networkID, w.site, cfg, w.n); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Config returns the container config for the queue worker of the site.
+func Config(home string, site config.Site, cfg *config.Config, n int) *container.Config {
+	labels := map[string]string{
+		containerlabels.Nitro: environment.Label(),
+		containerlabels.Type:  "queue",
+		containerlabels.Queue: site.Hostname,
+	}
+
+	labels[containerlabels.Replica] = strconv.Itoa(n)
+
+	return &container.Config{
+		Image:      sitecontainer.Image(home, site),
+		Cmd:        Command(site),
+		Env:        sitecontainer.Envs(site, cfg),
+		WorkingDir: "/app",
+		Labels:     labels,
+		// nginx is not running in the worker
+		Healthcheck: &container.HealthConfig{Test: []string{"NONE"}},
+	}
+}
+
+// changed returns true when the image, command, or environment variables of the worker
+// do not match the config.
+func changed(details types.ContainerJSON, expected *container.Config) bool {
+	if details.Config == nil || details.Config.Image != expected.Image || strings.Join(details.Config.Cmd, " ") != strings.Join(expected.Cmd, " ") {
+		return true
+	}
+
+	envs := make(map[string]bool)
+	for _, e := range details.Config.Env {
+		envs[e] = true
+	}
+
+	for _, e := range expected.Env {
+		if !envs[e] {
+			return true
+		}
+	}
+
+	return false
+}
+
+func create(ctx context.Context, docker client.CommonAPIClient, home, networkID string, site config.Site, cfg *config.Config, n int) error {
+	binds, mounts, err := sitecontainer.Mounts(ctx, docker, home, site)
+	if err != nil {
+		return err
+	}
+
+	name := Name(site.Hostname, n)
+
+	resp, err := docker.ContainerCreate(
+		ctx,
+		Config(home, site, cfg, n),
+		&container.HostConfig{
+			Binds:         binds,
+			Mounts:        mounts,
+			ExtraHosts:    sitecontainer.ExtraHosts(site),
+			RestartPolicy: container.RestartPolicy{Name: "on-failure"},
+			LogConfig:     logconfig.New(cfg.SiteLogs(site)),
+			Resources:     limits.New(site.Resources()),
+		},
+		&network.NetworkingConfig{
+			EndpointsConfig: map[string]*network.EndpointSettings{
+				environment.Network(): {
+					NetworkID: networkID,
+				},
+			},
+		},
+		nil,
+		name,
+	)
+	if err != nil {
+		return fmt.Errorf("unable to create the queue worker %s, %w", name, err)
+	}
+
+	if err := docker.ContainerStart(ctx, resp.ID, types.ContainerStartOptions{}); err != nil {
+		return fmt.Errorf("unable to start the queue worker %s, %w", name, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,140 @@
+package seed
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/docker/client"
+
+	"github.com/craftcms/nitro/command/internal/databasecontainer"
+	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/containerexec"
+	"github.com/craftcms/nitro/pkg/database"
+	"github.com/craftcms/nitro/pkg/terminal"
+)
+
+// Run seeds the database of the site, the seed files are imported into the database of the
+// site and then the seed commands run with sh in the site container. It stops at the first
+// file or command that fails.
+func Run(ctx context.Context, docker client.ContainerAPIClient, home string, cfg *config.Config, site config.Site, output terminal.Outputer) error {
+	if site.Seed.IsZero() {
+		return nil
+	}
+
+	if len(site.Seed.Files) > 0 && site.Database == "" {
+		return fmt.Errorf("the site %s has seed files but no database, set the database of the site to import them", site.Hostname)
+	}
+
+	output.Info(fmt.Sprintf("Seeding %s…", site.Hostname))
+
+	if len(site.Seed.Files) > 0 {
+		db, err := cfg.SiteDatabase(site)
+		if err != nil {
+			return err
+		}
+
+		files, err := site.GetSeedFiles(home)
+		if err != nil {
+			return err
+		}
+
+		hostname, _ := db.GetHostname()
+		id, err := running(ctx, docker, hostname)
+		if err != nil {
+			return err
+		}
+
+		for _, f := range files {
+			output.Pending("importing", filepath.Base(f))
+
+			if err := importFile(ctx, docker, id, *db, site.Database, f); err != nil {
+				output.Warning()
+				return err
+			}
+
+			output.Done()
+		}
+	}
+
+	if len(site.Seed.Commands) > 0 {
+		id, err := running(ctx, docker, site.Hostname)
+		if err != nil {
+			return err
+		}
+
+		for _, c := range site.Seed.Commands {
+			output.Pending("running", c)
+
+			out, err := containerexec.Run(ctx, docker, id, []string{"sh", "-c", c})
+			if err != nil {
+				output.Warning()
+				return fmt.Errorf("the seed command %q failed in %s, %w", c, site.Hostname, err)
+			}
+
+			output.Done()
+
+			if out != "" {
+				output.Info(out)
+			}
+		}
+	}
+
+	return nil
+}
+
+// running returns the ID of the container when it is running.
+func running(ctx context.Context, docker client.ContainerAPIClient, name string) (string, error) {
+	details, err := docker.ContainerInspect(ctx, name)
+	if err != nil {
+		return "", fmt.Errorf("unable to find the container %s, %w", name, err)
+	}
+
+	if details.State == nil || !details.State.Running {
+		return "", fmt.Errorf("the container %s is not running, run `nitro start` to start it", name)
+	}
+
+	return details.ID, nil
+}
+
+// importFile streams the seed file into the client of the database engine in the container,
+// postgres custom format backups are restored from a copy of the file instead.
+func importFile(ctx context.Context, docker client.ContainerAPIClient, containerID string, db config.Database, name, file string) error {
+	custom, err := database.IsPostgresCustomFormat(file)
+	if err != nil {
+		return fmt.Errorf("unable to read the seed file %s, %w", file, err)
+	}
+
+	if custom && db.Engine != "postgres" {
+		return fmt.Errorf("%s is a postgres custom format backup and can only be imported into a postgres database", filepath.Base(file))
+	}
+
+	if custom {
+		if err := databasecontainer.Restore(ctx, docker, containerID, file, name, 0); err != nil {
+			return fmt.Errorf("unable to import the seed file %s, %w", filepath.Base(file), err)
+		}
+
+		return nil
+	}
+
+	rdr, err := database.Open(file)
+	if err != nil {
+		return err
+	}
+	defer rdr.Close()
+
+	stderr := &bytes.Buffer{}
+	code, err := containerexec.Interactive(ctx, docker, containerID, "", database.StdinImportCommand(db.Engine, db.Version, name), rdr, ioutil.Discard, stderr)
+	if err != nil {
+		return fmt.Errorf("unable to import the seed file %s, %w", filepath.Base(file), err)
+	}
+
+	if code != 0 {
+		return fmt.Errorf("unable to import the seed file %s into %s, %s", filepath.Base(file), name, strings.TrimSpace(stderr.String()))
+	}
+
+	return nil
+}
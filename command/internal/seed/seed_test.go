@@ -0,0 +1,26 @@
+package seed
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/terminal"
+)
+
+func TestRun(t *testing.T) {
+	cfg := &config.Config{}
+
+	// sites without a seed are skipped without docker
+	if err := Run(context.Background(), nil, "/home/nitro", cfg, config.Site{Hostname: "craft.nitro"}, terminal.New()); err != nil {
+		t.Errorf("expected sites without a seed to be skipped, got %v", err)
+	}
+
+	site := config.Site{Hostname: "store.nitro", Seed: config.Seed{Files: []string{"seed/store.sql"}}}
+
+	err := Run(context.Background(), nil, "/home/nitro", cfg, site, terminal.New())
+	if err == nil || !strings.Contains(err.Error(), "no database") {
+		t.Errorf("expected an error for seed files without a database, got %v", err)
+	}
+}
@@ -0,0 +1,323 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"runtime"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+
+	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/environment"
+	"github.com/craftcms/nitro/pkg/limits"
+	"github.com/craftcms/nitro/pkg/logconfig"
+	"github.com/craftcms/nitro/pkg/proxyroutes"
+	"github.com/craftcms/nitro/pkg/svc/blackfire"
+	"github.com/craftcms/nitro/pkg/svc/dynamodb"
+	"github.com/craftcms/nitro/pkg/svc/gotenberg"
+	"github.com/craftcms/nitro/pkg/svc/mailhog"
+	"github.com/craftcms/nitro/pkg/svc/minio"
+	"github.com/craftcms/nitro/pkg/svc/redis"
+	"github.com/craftcms/nitro/pkg/svc/redisinsight"
+	"github.com/craftcms/nitro/pkg/terminal"
+	"github.com/craftcms/nitro/protob"
+)
+
+// Service is a managed service that can be enabled in the config. Each service
+// package creates a labeled container on the network with default ports and volumes.
+type Service struct {
+	// Name is the name of the service in the config (e.g. redis)
+	Name string
+
+	// Host is the hostname of the service container
+	Host string
+
+	// Label is the type label on the service container
+	Label string
+
+	// Image is the image used for the service container
+	Image string
+
+	// VerifyCreated makes sure the container for the service exists and is started, the
+	// options of the service in the config are set when the container is created
+	VerifyCreated func(ctx context.Context, docker client.CommonAPIClient, networkID string, cfg *config.Config, output terminal.Outputer) (string, string, error)
+
+	// VerifyRemoved makes sure the container for the service is removed
+	VerifyRemoved func(ctx context.Context, docker client.CommonAPIClient, output terminal.Outputer) error
+}
+
+// Services are the managed services, sorted by name. The companion web interfaces are
+// sorted after the service they connect to.
+var Services = []Service{
+	{Name: "blackfire", Host: blackfire.Host, Label: blackfire.Label, Image: blackfire.Image, VerifyCreated: verifyBlackfire, VerifyRemoved: blackfire.VerifyRemoved},
+	{Name: "dynamodb", Host: dynamodb.Host, Label: dynamodb.Label, Image: dynamodb.Image, VerifyCreated: withLogs(dynamodb.VerifyCreated), VerifyRemoved: dynamodb.VerifyRemoved},
+	{Name: "gotenberg", Host: gotenberg.Host, Label: gotenberg.Label, Image: gotenberg.Image, VerifyCreated: withLogs(gotenberg.VerifyCreated), VerifyRemoved: gotenberg.VerifyRemoved},
+	{Name: "mailhog", Host: mailhog.Host, Label: mailhog.Label, Image: mailhog.Image, VerifyCreated: withLogs(mailhog.VerifyCreated), VerifyRemoved: mailhog.VerifyRemoved},
+	{Name: "minio", Host: minio.Host, Label: minio.Label, Image: minio.Image, VerifyCreated: verifyMinio, VerifyRemoved: minio.VerifyRemoved},
+	{Name: "redis", Host: redis.Host, Label: redis.Label, Image: redis.Image, VerifyCreated: withLogs(redis.VerifyCreated), VerifyRemoved: redis.VerifyRemoved},
+	{Name: "redisinsight", Host: redisinsight.Host, Label: redisinsight.Label, Image: redisinsight.Image, VerifyCreated: withLogs(redisinsight.VerifyCreated), VerifyRemoved: redisinsight.VerifyRemoved},
+}
+
+// withLogs returns the VerifyCreated func for services that only use the log options
+// from the config.
+func withLogs(verify func(ctx context.Context, docker client.CommonAPIClient, networkID string, logs container.LogConfig, output terminal.Outputer) (string, string, error)) func(ctx context.Context, docker client.CommonAPIClient, networkID string, cfg *config.Config, output terminal.Outputer) (string, string, error) {
+	return func(ctx context.Context, docker client.CommonAPIClient, networkID string, cfg *config.Config, output terminal.Outputer) (string, string, error) {
+		return verify(ctx, docker, networkID, logconfig.New(cfg.Logs), output)
+	}
+}
+
+// verifyBlackfire creates the blackfire agent with the server credentials from the config
+// or the environment.
+func verifyBlackfire(ctx context.Context, docker client.CommonAPIClient, networkID string, cfg *config.Config, output terminal.Outputer) (string, string, error) {
+	return blackfire.VerifyCreated(ctx, docker, networkID, cfg.Blackfire.Credentials(), logconfig.New(cfg.Logs), output)
+}
+
+// verifyMinio creates the minio container and the buckets from the config, the buckets are
+// created each time so buckets added to the config are created on the next apply.
+func verifyMinio(ctx context.Context, docker client.CommonAPIClient, networkID string, cfg *config.Config, output terminal.Outputer) (string, string, error) {
+	id, host, err := minio.VerifyCreated(ctx, docker, networkID, logconfig.New(cfg.Logs), output)
+	if err != nil {
+		return "", "", err
+	}
+
+	if err := minio.CreateBuckets(ctx, docker, id, cfg.Minio.Buckets); err != nil {
+		return "", "", err
+	}
+
+	return id, host, nil
+}
+
+// Find returns the service with the name.
+func Find(name string) (*Service, error) {
+	for _, s := range Services {
+		if s.Name == name {
+			return &s, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unknown service %q", name)
+}
+
+// Hostnames returns the hostnames of the services enabled in the config.
+func Hostnames(cfg *config.Config) []string {
+	var hostnames []string
+	for _, s := range Services {
+		if cfg.Services.IsEnabled(s.Name) {
+			hostnames = append(hostnames, s.Host)
+		}
+	}
+
+	return hostnames
+}
+
+// Reconcile creates or removes the container for each service based on the
+// config and returns the hostnames of the services that are running.
+func Reconcile(ctx context.Context, docker client.CommonAPIClient, networkID string, cfg *config.Config, output terminal.Outputer) ([]string, error) {
+	var hostnames []string
+	for _, s := range Services {
+		hostname, err := s.Reconcile(ctx, docker, networkID, cfg.Services.IsEnabled(s.Name), cfg, output)
+		if err != nil {
+			return nil, err
+		}
+
+		if hostname != "" {
+			hostnames = append(hostnames, hostname)
+		}
+	}
+
+	return hostnames, nil
+}
+
+// Reconcile creates the container for the service when it is enabled and removes
+// it when it is disabled. It returns the hostname when the container is running.
+func (s Service) Reconcile(ctx context.Context, docker client.CommonAPIClient, networkID string, enabled bool, cfg *config.Config, output terminal.Outputer) (string, error) {
+	output.Pending("checking", s.Name)
+
+	if !enabled {
+		if err := s.VerifyRemoved(ctx, docker, output); err != nil {
+			output.Warning()
+			return "", err
+		}
+
+		output.Done()
+
+		return "", nil
+	}
+
+	id, hostname, err := s.VerifyCreated(ctx, docker, networkID, cfg, output)
+	if err != nil {
+		output.Warning()
+		return "", err
+	}
+
+	if err := s.limit(ctx, docker, networkID, id, cfg, output); err != nil {
+		output.Warning()
+		return "", err
+	}
+
+	output.Done()
+
+	return hostname, nil
+}
+
+// limit applies the resource limits of the service to the container. Docker changes the
+// limits of a running container but cannot remove them, so the container is recreated
+// when a limit is removed from the config. The volumes of the service are kept.
+func (s Service) limit(ctx context.Context, docker client.CommonAPIClient, networkID, containerID string, cfg *config.Config, output terminal.Outputer) error {
+	r := cfg.ServiceResources(s.Name)
+
+	details, err := docker.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return err
+	}
+
+	if !limits.Changed(r, details) {
+		return nil
+	}
+
+	if limits.Removed(r, details) {
+		if err := docker.ContainerRemove(ctx, containerID, types.ContainerRemoveOptions{Force: true}); err != nil {
+			return fmt.Errorf("unable to remove the %s container, %w", s.Name, err)
+		}
+
+		id, _, err := s.VerifyCreated(ctx, docker, networkID, cfg, output)
+		if err != nil {
+			return err
+		}
+
+		return s.limit(ctx, docker, networkID, id, cfg, output)
+	}
+
+	if _, err := docker.ContainerUpdate(ctx, containerID, limits.Update(r)); err != nil {
+		return fmt.Errorf("unable to update the limits of the %s container, %w", s.Name, err)
+	}
+
+	return nil
+}
+
+// Toggle enables or disables the named service in the config and saves it, then
+// creates or removes the services container the same way apply does and updates
+// the proxy routes. This avoids running apply for every site and container.
+func Toggle(ctx context.Context, docker client.CommonAPIClient, nitrod protob.NitroClient, cfg *config.Config, name string, enabled bool, output terminal.Outputer) error {
+	s, err := Find(name)
+	if err != nil {
+		return err
+	}
+
+	// the blackfire agent cannot start without the server credentials
+	if enabled && s.Name == "blackfire" {
+		if creds := cfg.Blackfire.Credentials(); creds.ServerID == "" || creds.ServerToken == "" {
+			return fmt.Errorf("set the blackfire credentials with `nitro blackfire on` or the BLACKFIRE_SERVER_ID and BLACKFIRE_SERVER_TOKEN environment variables")
+		}
+	}
+
+	if err := cfg.Services.Set(s.Name, enabled); err != nil {
+		return err
+	}
+
+	// make sure the service does not bind a port that is already used before saving, the
+	// sites are not changed so their paths are not checked
+	if enabled {
+		if err := cfg.Validate(""); err != nil {
+			return err
+		}
+	}
+
+	// save the config file
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("unable to save config, %w", err)
+	}
+
+	// find the network
+	filter := filters.NewArgs()
+	filter.Add("name", environment.Network())
+
+	networks, err := docker.NetworkList(ctx, types.NetworkListOptions{Filters: filter})
+	if err != nil {
+		return fmt.Errorf("unable to list the docker networks, %w", err)
+	}
+
+	var networkID string
+	for _, n := range networks {
+		if n.Name == environment.Network() || strings.TrimLeft(n.Name, "/") == environment.Network() {
+			networkID = n.ID
+		}
+	}
+
+	if networkID == "" {
+		return fmt.Errorf("unable to find the network, run `nitro init` to create it")
+	}
+
+	// the companion web interfaces are removed with the service, and enabling a web
+	// interface also enables the service it connects to
+	for _, r := range Services {
+		related := r.Name == s.Name || config.Companions[r.Name] == s.Name || (enabled && config.Companions[s.Name] == r.Name)
+		if !related {
+			continue
+		}
+
+		if _, err := r.Reconcile(ctx, docker, networkID, cfg.Services.IsEnabled(r.Name), cfg, output); err != nil {
+			return err
+		}
+	}
+
+	// update the proxy routes for services with a web interface
+	sites := proxyroutes.Sites(cfg)
+	if err := proxyroutes.Update(ctx, nitrod, sites, proxyroutes.Dashboard(cfg)); err != nil {
+		return err
+	}
+
+	// the hosts file is only updated by apply since it requires sudo
+	if _, ok := sites[s.Host]; ok && enabled {
+		host := s.Host
+		if s.Name == "mailhog" {
+			host = cfg.MailHostname()
+		}
+
+		if h := cfg.UIHostname(s.Name); h != "" {
+			host = h
+		}
+
+		if b, err := ioutil.ReadFile(hostsFile()); err == nil && !strings.Contains(string(b), host) {
+			output.Info("Run `nitro apply` to add", host, "to your hosts file.")
+		}
+	}
+
+	// show the keys for the asset volumes that do not run in a site container
+	if enabled && s.Name == "minio" {
+		output.Info("Minio is available at", minio.Endpoint, "with the access key", minio.User, "and secret key", minio.Password)
+	}
+
+	// show where the web interface connects to the service
+	if parent, ok := config.Companions[s.Name]; ok && enabled {
+		p, _ := Find(parent)
+		output.Info("The", s.Name, "web interface is available at", "https://"+cfg.UIHostname(s.Name), "and connects to", parent, "at", p.Host)
+	}
+
+	// sites use environment variables for some services
+	if vars, ok := siteEnvVars[s.Name]; ok && len(cfg.Sites) > 0 {
+		output.Info("Run `nitro apply` to update the " + vars + " for your sites.")
+	}
+
+	return nil
+}
+
+// siteEnvVars are the environment variables set on the sites for the services.
+var siteEnvVars = map[string]string{
+	"gotenberg": "GOTENBERG_URL",
+	"mailhog":   "MAIL_HOST and MAIL_PORT",
+	"minio":     "MINIO_ENDPOINT, MINIO_ACCESS_KEY, and MINIO_SECRET_KEY",
+}
+
+func hostsFile() string {
+	if runtime.GOOS == "windows" {
+		return `C:\Windows\System32\Drivers\etc\hosts`
+	}
+
+	return "/etc/hosts"
+}
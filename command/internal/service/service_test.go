@@ -0,0 +1,51 @@
+package service
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/craftcms/nitro/pkg/config"
+)
+
+func TestServices(t *testing.T) {
+	var names []string
+	for _, s := range Services {
+		names = append(names, s.Name)
+	}
+
+	if !sort.StringsAreSorted(names) {
+		t.Errorf("expected the services to be sorted by name, got %v", names)
+	}
+
+	// every service in the config must be managed
+	if !reflect.DeepEqual(names, config.ServiceNames) {
+		t.Errorf("expected the services to match the config, got %v want %v", names, config.ServiceNames)
+	}
+}
+
+func TestHostnames(t *testing.T) {
+	cfg := &config.Config{Services: config.Services{Mailhog: true, Redis: true}}
+
+	got := Hostnames(cfg)
+	want := []string{"mailhog.service.nitro", "redis.service.nitro"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Hostnames() = %v, want %v", got, want)
+	}
+}
+
+func TestHostnames_Companions(t *testing.T) {
+	// the web interface is not created without the service
+	cfg := &config.Config{Services: config.Services{UI: map[string]bool{"redis": true}}}
+	if got := Hostnames(cfg); len(got) != 0 {
+		t.Errorf("Hostnames() = %v, want no hostnames", got)
+	}
+
+	cfg.Services.Redis = true
+
+	got := Hostnames(cfg)
+	want := []string{"redis.service.nitro", "redisinsight.service.nitro"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Hostnames() = %v, want %v", got, want)
+	}
+}
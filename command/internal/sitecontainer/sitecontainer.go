@@ -0,0 +1,580 @@
+package sitecontainer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/craftcms/nitro/command/internal/match"
+	"github.com/craftcms/nitro/command/internal/nginx"
+	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/envedit"
+	"github.com/craftcms/nitro/pkg/environment"
+	"github.com/craftcms/nitro/pkg/imagepull"
+	"github.com/craftcms/nitro/pkg/limits"
+	"github.com/craftcms/nitro/pkg/logconfig"
+	"github.com/craftcms/nitro/pkg/reconcile"
+	"github.com/craftcms/nitro/pkg/siteimage"
+	"github.com/craftcms/nitro/pkg/svc/blackfire"
+	"github.com/craftcms/nitro/pkg/svc/gotenberg"
+	"github.com/craftcms/nitro/pkg/svc/mailhog"
+	"github.com/craftcms/nitro/pkg/svc/minio"
+	"github.com/craftcms/nitro/pkg/terminal"
+	"github.com/craftcms/nitro/pkg/wsl"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/network"
+	volumetypes "github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/archive"
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+type command struct {
+	Name     string
+	Commands []string
+}
+
+var (
+	// NginxImage is the image used for sites, with the PHP version
+	NginxImage = "docker.io/craftcms/nginx:%s-dev"
+)
+
+const (
+	// BasePathEnvVar and BaseURLEnvVar are set for the sites that are routed by path prefix
+	// (e.g. /api and https://mysite.nitro/api), so the site can generate URLs with the prefix
+	BasePathEnvVar = "NITRO_BASE_PATH"
+	BaseURLEnvVar  = "NITRO_BASE_URL"
+)
+
+// StartOrCreate is responsible for finding a sites existing container or creating a new one based on the values from the configuration file.
+func StartOrCreate(ctx context.Context, docker client.CommonAPIClient, home, networkID string, site config.Site, cfg *config.Config, output terminal.Outputer) (string, error) {
+	// set filters for the container
+	filter := filters.NewArgs()
+	filter.Add("label", containerlabels.Nitro+"="+environment.Label())
+	filter.Add("label", containerlabels.Host+"="+site.Hostname)
+
+	// look for a container for the site
+	containers, err := docker.ContainerList(ctx, types.ContainerListOptions{All: true, Filters: filter})
+	if err != nil {
+		return "", fmt.Errorf("error getting a list of containers")
+	}
+
+	// make sure existing containers are started and on the network
+	containers, err = reconcile.Containers(ctx, docker, networkID, containers)
+	if err != nil {
+		return "", err
+	}
+
+	// if there are no containers we need to create one
+	if len(containers) == 0 {
+		return create(ctx, docker, home, networkID, site, cfg, output)
+	}
+
+	// there is a container, so inspect it and make sure it matched
+	container := containers[0]
+
+	// get the containers details that include environment variables
+	details, err := docker.ContainerInspect(ctx, container.ID)
+	if err != nil {
+		return "", err
+	}
+
+	// if the container is out of date
+	if changes := Changes(home, site, details, cfg); len(changes) > 0 {
+		fmt.Println("- updating…")
+
+		printChanges(changes)
+
+		// stop container
+		if err := docker.ContainerStop(ctx, container.ID, nil); err != nil {
+			return "", err
+		}
+
+		// remove container
+		if err := docker.ContainerRemove(ctx, container.ID, types.ContainerRemoveOptions{}); err != nil {
+			return "", err
+		}
+
+		return create(ctx, docker, home, networkID, site, cfg, output)
+	}
+
+	return container.ID, nil
+}
+
+func create(ctx context.Context, docker client.CommonAPIClient, home, networkID string, site config.Site, cfg *config.Config, output terminal.Outputer) (string, error) {
+	// create the container
+	image := fmt.Sprintf(NginxImage, site.Version)
+
+	// get the sites path
+	path, err := site.GetAbsPath(home)
+	if err != nil {
+		return "", err
+	}
+
+	// pull the image if we are not in a development environment
+	_, dev := os.LookupEnv("NITRO_DEVELOPMENT")
+	if !dev {
+		if err := imagepull.Pull(ctx, docker, image, output); err != nil {
+			return "", err
+		}
+	}
+
+	// build the derived image when the project has a Dockerfile
+	dockerfile, err := siteimage.Find(path)
+	if err != nil {
+		return "", err
+	}
+
+	if dockerfile != nil {
+		image, err = siteimage.Build(ctx, docker, site.Hostname, image, dockerfile)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	extraHosts := ExtraHosts(site)
+	envs := Envs(site, cfg)
+
+	binds, mounts, err := Mounts(ctx, docker, home, site)
+	if err != nil {
+		return "", err
+	}
+
+	// set the labels
+	labels := containerlabels.ForSite(site)
+
+	// add the debug banner for sites in devMode
+	banner := showBanner(home, site, cfg)
+	if banner {
+		labels[containerlabels.DebugBanner] = "true"
+	}
+
+	// track the nginx options to recreate the container when they change
+	if fingerprint := nginx.Fingerprint(site.Nginx); fingerprint != "" {
+		labels[containerlabels.Nginx] = fingerprint
+	}
+	// create the container
+	resp, err := docker.ContainerCreate(
+		ctx,
+		&container.Config{
+			Image:       image,
+			Labels:      labels,
+			Env:         envs,
+			Healthcheck: healthCheck(site),
+		},
+		&container.HostConfig{
+			Binds:      binds,
+			Mounts:     mounts,
+			ExtraHosts: extraHosts,
+			LogConfig:  logconfig.New(cfg.SiteLogs(site)),
+			Resources:  limits.New(site.Resources()),
+		},
+		&network.NetworkingConfig{
+			EndpointsConfig: map[string]*network.EndpointSettings{
+				environment.Network(): {
+					NetworkID: networkID,
+				},
+			},
+		},
+		nil,
+		site.Hostname,
+	)
+	if err != nil {
+		return "", fmt.Errorf("unable to create the container, %w", err)
+	}
+
+	// start the container
+	if err := docker.ContainerStart(ctx, resp.ID, types.ContainerStartOptions{}); err != nil {
+		return "", fmt.Errorf("unable to start the container, %w", err)
+	}
+
+	// post installation commands
+	var commands []command
+
+	// check for a custom root, banner, or nginx options and copy the template to the container
+	if opts := nginx.NewOptions(site, banner); !opts.IsDefault() {
+		// create the nginx file
+		conf := nginx.Generate(opts)
+
+		// create the temp file
+		tr, err := archive.Generate("default.conf", conf)
+		if err != nil {
+			return "", err
+		}
+
+		// copy the file into the container
+		if err := docker.CopyToContainer(ctx, resp.ID, "/tmp", tr, types.CopyToContainerOptions{AllowOverwriteDirWithFile: false}); err != nil {
+			return "", err
+		}
+
+		commands = append(commands, command{Commands: []string{"cp", "/tmp/default.conf", "/etc/nginx/conf.d/default.conf"}})
+		commands = append(commands, command{Commands: []string{"chmod", "0644", "/etc/nginx/conf.d/default.conf"}})
+	}
+
+	// check if there are custom extensions
+	for _, ext := range site.Extensions {
+		commands = append(commands, command{Name: "installing-" + ext + "-extension", Commands: []string{"docker-php-ext-install", ext}})
+	}
+
+	// run the commands
+	for _, c := range commands {
+		// create the exec
+		exec, err := docker.ContainerExecCreate(ctx, resp.ID, types.ExecConfig{
+			User:         "root",
+			AttachStdout: true,
+			AttachStderr: true,
+			Tty:          false,
+			Cmd:          c.Commands,
+		})
+		if err != nil {
+			return "", err
+		}
+
+		// attach to the container
+		attach, err := docker.ContainerExecAttach(ctx, exec.ID, types.ExecStartCheck{
+			Tty: false,
+		})
+		if err != nil {
+			return "", err
+		}
+		defer attach.Close()
+
+		// if the option is for a php extension, don't show output
+		if strings.Contains(c.Name, "-extension") {
+			// read the output to pull the image
+			fmt.Print("installing ", c.Commands[len(c.Commands)-1], "… ")
+
+			buf := &bytes.Buffer{}
+			if _, err := buf.ReadFrom(attach.Reader); err != nil {
+				return "", fmt.Errorf("unable to read output from container exec attach, %w", err)
+			}
+		} else {
+			// show the output to stdout and stderr
+			if _, err := stdcopy.StdCopy(os.Stdout, os.Stderr, attach.Reader); err != nil {
+				return "", fmt.Errorf("unable to copy the output of container, %w", err)
+			}
+		}
+
+		// start the exec
+		if err := docker.ContainerExecStart(ctx, exec.ID, types.ExecStartCheck{}); err != nil {
+			return "", fmt.Errorf("unable to start the container, %w", err)
+		}
+
+		// wait for the container exec to complete
+		waiting := true
+		for waiting {
+			resp, err := docker.ContainerExecInspect(ctx, exec.ID)
+			if err != nil {
+				return "", err
+			}
+
+			waiting = resp.Running
+		}
+
+		// start the container
+		if err := docker.ContainerStart(ctx, resp.ID, types.ContainerStartOptions{}); err != nil {
+			return "", fmt.Errorf("unable to start the container, %w", err)
+		}
+	}
+
+	return resp.ID, nil
+}
+
+// Changes returns the changes that require the container for the site to be recreated.
+func Changes(home string, site config.Site, details types.ContainerJSON, cfg *config.Config) []match.Change {
+	changes := match.SiteChanges(home, site, details, cfg.Blackfire)
+
+	// sites with a project Dockerfile use a derived image instead of the base image
+	if expected := Image(home, site); expected != fmt.Sprintf(NginxImage, site.Version) {
+		var filtered []match.Change
+		for _, c := range changes {
+			if c.Name != "image" {
+				filtered = append(filtered, c)
+			}
+		}
+
+		changes = filtered
+
+		if details.Config.Image != expected {
+			changes = append(changes, match.Change{Name: "image", Expected: expected, Actual: details.Config.Image})
+		}
+	}
+
+	// check if the debug banner has been toggled
+	banner := details.Config.Labels[containerlabels.DebugBanner] == "true"
+	if show := showBanner(home, site, cfg); banner != show {
+		changes = append(changes, match.Change{Name: "label " + containerlabels.DebugBanner, Expected: strconv.FormatBool(show), Actual: strconv.FormatBool(banner)})
+	}
+
+	// check if the nginx options have changed
+	if expected := nginx.Fingerprint(site.Nginx); details.Config.Labels[containerlabels.Nginx] != expected {
+		changes = append(changes, match.Change{Name: "label " + containerlabels.Nginx, Expected: expected, Actual: details.Config.Labels[containerlabels.Nginx]})
+	}
+
+	// check if the gotenberg service has been toggled
+	if url := env(details.Config.Env, gotenberg.EnvVar); url != gotenbergURL(cfg) {
+		changes = append(changes, match.Change{Name: "env " + gotenberg.EnvVar, Expected: gotenbergURL(cfg), Actual: url})
+	}
+
+	// check if the mailhog service has been toggled
+	if host := env(details.Config.Env, mailhog.HostEnvVar); host != mailHost(cfg) {
+		changes = append(changes, match.Change{Name: "env " + mailhog.HostEnvVar, Expected: mailHost(cfg), Actual: host})
+	}
+
+	// check if the minio service has been toggled
+	if endpoint := env(details.Config.Env, minio.EndpointEnvVar); endpoint != minioEndpoint(cfg) {
+		changes = append(changes, match.Change{Name: "env " + minio.EndpointEnvVar, Expected: minioEndpoint(cfg), Actual: endpoint})
+	}
+
+	// check if blackfire profiling has been toggled for the site
+	if socket := env(details.Config.Env, blackfire.SocketEnvVar); socket != blackfireSocket(site, cfg) {
+		changes = append(changes, match.Change{Name: "env " + blackfire.SocketEnvVar, Expected: blackfireSocket(site, cfg), Actual: socket})
+	}
+
+	// check if the health check has changed
+	var actual []string
+	if details.Config.Healthcheck != nil {
+		actual = details.Config.Healthcheck.Test
+	}
+
+	if expected := healthCheck(site).Test; strings.Join(actual, " ") != strings.Join(expected, " ") {
+		changes = append(changes, match.Change{Name: "health check", Expected: strings.Join(expected, " "), Actual: strings.Join(actual, " ")})
+	}
+
+	// check if the log options have changed
+	if logs := cfg.SiteLogs(site); logconfig.Changed(logs, details) {
+		changes = append(changes, match.Change{Name: "logs", Expected: fmt.Sprintf("%s %v", logs.Type(), logs.Options()), Actual: fmt.Sprintf("%s %v", details.HostConfig.LogConfig.Type, details.HostConfig.LogConfig.Config)})
+	}
+
+	return changes
+}
+
+// Mounts returns the bind mount of the site path, or the volume the site path is synced
+// into when the site uses a sync mode. The volume is created if it does not exist.
+func Mounts(ctx context.Context, docker client.VolumeAPIClient, home string, site config.Site) ([]string, []mount.Mount, error) {
+	if !site.UsesSync() {
+		path, err := site.GetAbsPath(home)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return []string{fmt.Sprintf("%s:/app:rw", path)}, nil, nil
+	}
+
+	volume, err := docker.VolumeCreate(ctx, volumetypes.VolumeCreateBody{
+		Driver: "local",
+		Name:   site.SyncVolume(),
+		Labels: map[string]string{
+			containerlabels.Nitro:  environment.Label(),
+			containerlabels.Volume: site.SyncVolume(),
+		},
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to create the sync volume for %s, %w", site.Hostname, err)
+	}
+
+	return nil, []mount.Mount{{Type: mount.TypeVolume, Source: volume.Name, Target: "/app"}}, nil
+}
+
+// ExtraHosts returns the hosts for the site itself and any aliases, and the docker host on linux.
+func ExtraHosts(site config.Site) []string {
+	extraHosts := []string{fmt.Sprintf("%s:%s", site.Hostname, "127.0.0.1")}
+	for _, s := range site.Aliases {
+		extraHosts = append(extraHosts, fmt.Sprintf("%s:%s", s, "127.0.0.1"))
+	}
+
+	// check if this is linux specific
+	if runtime.GOOS == "linux" && !wsl.IsWSL() {
+		extraHosts = append(extraHosts, fmt.Sprintf("%s:%s", "host.docker.internal", "host-gateway"))
+	}
+
+	return extraHosts
+}
+
+// Envs returns the environment variables for the site, the blackfire credentials, and the
+// endpoints of the enabled services.
+func Envs(site config.Site, cfg *config.Config) []string {
+	envs := site.AsEnvs("host.docker.internal")
+
+	// does the config have blackfire credentials
+	if cfg.Blackfire.ServerID != "" {
+		envs = append(envs, "BLACKFIRE_SERVER_ID="+cfg.Blackfire.ServerID)
+	}
+
+	if cfg.Blackfire.ServerToken != "" {
+		envs = append(envs, "BLACKFIRE_SERVER_TOKEN="+cfg.Blackfire.ServerToken)
+	}
+
+	// surface the endpoints for the enabled services
+	if url := gotenbergURL(cfg); url != "" {
+		envs = append(envs, gotenberg.EnvVar+"="+url)
+	}
+
+	// send mail to mailhog when the service is enabled
+	if host := mailHost(cfg); host != "" {
+		envs = append(envs, mailhog.HostEnvVar+"="+host, mailhog.PortEnvVar+"="+mailhog.SMTPPort)
+	}
+
+	// use the minio service for the asset volumes when the service is enabled
+	if endpoint := minioEndpoint(cfg); endpoint != "" {
+		envs = append(envs, minio.EndpointEnvVar+"="+endpoint, minio.AccessKeyEnvVar+"="+minio.User, minio.SecretKeyEnvVar+"="+minio.Password)
+	}
+
+	// connect the php probe to the blackfire agent when profiling the site
+	if socket := blackfireSocket(site, cfg); socket != "" {
+		envs = append(envs, blackfire.SocketEnvVar+"="+socket)
+	}
+
+	// let the site know the path it is served at on the hostname of another site
+	if host, prefix, err := site.GetRoute(); err == nil && host != "" {
+		envs = append(envs, BasePathEnvVar+"="+prefix, BaseURLEnvVar+"=https://"+host+prefix)
+	}
+
+	return envs
+}
+
+// Image returns the image the container for the site should use. When the project has a
+// Dockerfile, it is the name of the derived image built from the base image.
+func Image(home string, site config.Site) string {
+	base := fmt.Sprintf(NginxImage, site.Version)
+
+	path, err := site.GetAbsPath(home)
+	if err != nil {
+		return base
+	}
+
+	dockerfile, err := siteimage.Find(path)
+	if err != nil || dockerfile == nil {
+		return base
+	}
+
+	return siteimage.Name(site.Hostname, base, dockerfile)
+}
+
+// healthCheck returns the health check for the site container. Docker requests the
+// health check path from nginx inside the container and marks the container as
+// healthy once the response has the expected status code.
+func healthCheck(site config.Site) *container.HealthConfig {
+	if site.HealthCheck.Disabled {
+		return &container.HealthConfig{Test: []string{"NONE"}}
+	}
+
+	url := "http://127.0.0.1:8080" + site.HealthCheck.GetPath()
+	cmd := fmt.Sprintf(`test "$(curl --silent --output /dev/null --write-out '%%{http_code}' --header 'Host: %s' '%s')" = "%d"`, site.Hostname, url, site.HealthCheck.GetStatus())
+
+	return &container.HealthConfig{
+		Test:        []string{"CMD-SHELL", cmd},
+		Interval:    10 * time.Second,
+		Timeout:     10 * time.Second,
+		StartPeriod: 30 * time.Second,
+		Retries:     3,
+	}
+}
+
+// showBanner returns true when the proxy debug banner is enabled and the
+// site is in devMode based on the sites .env file.
+func showBanner(home string, site config.Site, cfg *config.Config) bool {
+	if !cfg.Proxy.DebugBanner {
+		return false
+	}
+
+	path, err := site.GetAbsPath(home)
+	if err != nil {
+		return false
+	}
+
+	env := filepath.Join(path, ".env")
+
+	switch {
+	case envedit.Value(env, "CRAFT_DEV_MODE") == "true", envedit.Value(env, "DEV_MODE") == "true", envedit.Value(env, "ENVIRONMENT") == "dev":
+		return true
+	}
+
+	return false
+}
+
+// printChanges shows why a container is being recreated, the values on the
+// container are shown in red and the values from the config in green. Colors
+// are disabled with --no-color or the NO_COLOR environment variable.
+func printChanges(changes []match.Change) {
+	for _, c := range changes {
+		actual, expected := c.Actual, c.Expected
+
+		// do not show credentials in the output
+		if strings.Contains(c.Name, "TOKEN") {
+			actual, expected = mask(actual), mask(expected)
+		}
+
+		fmt.Printf("      %s:\n", c.Name)
+		fmt.Printf("        %s\n", terminal.Color(terminal.Red, "- "+actual))
+		fmt.Printf("        %s\n", terminal.Color(terminal.Green, "+ "+expected))
+	}
+
+	fmt.Print("    ")
+}
+
+func mask(s string) string {
+	if s == "" {
+		return s
+	}
+
+	return "********"
+}
+
+// gotenbergURL returns the endpoint for the gotenberg service if it is enabled.
+func gotenbergURL(cfg *config.Config) string {
+	if !cfg.Services.Gotenberg {
+		return ""
+	}
+
+	return gotenberg.URL
+}
+
+// mailHost returns the hostname of the mailhog service if it is enabled.
+func mailHost(cfg *config.Config) string {
+	if !cfg.Services.Mailhog {
+		return ""
+	}
+
+	return mailhog.Host
+}
+
+// minioEndpoint returns the endpoint of the minio service if it is enabled.
+func minioEndpoint(cfg *config.Config) string {
+	if !cfg.Services.Minio {
+		return ""
+	}
+
+	return minio.Endpoint
+}
+
+// blackfireSocket returns the address of the blackfire agent if the service is enabled and
+// blackfire is enabled for the site.
+func blackfireSocket(site config.Site, cfg *config.Config) string {
+	if !cfg.Services.Blackfire || !site.Blackfire {
+		return ""
+	}
+
+	return blackfire.Socket
+}
+
+// env returns the value of the environment variable from a containers environment.
+func env(envs []string, name string) string {
+	for _, e := range envs {
+		if strings.HasPrefix(e, name+"=") {
+			return strings.TrimPrefix(e, name+"=")
+		}
+	}
+
+	return ""
+}
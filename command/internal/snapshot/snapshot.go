@@ -0,0 +1,261 @@
+package snapshot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/client"
+
+	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/environment"
+	"github.com/craftcms/nitro/pkg/terminal"
+)
+
+// File is the name of the snapshot in the nitro directory.
+const File = "apply-snapshot.json"
+
+// ErrNoSnapshot is returned when there is no snapshot to restore.
+var ErrNoSnapshot = fmt.Errorf("there is no snapshot to restore, a snapshot is created each time `nitro apply` runs")
+
+// Snapshot is the definition of each container before apply made changes to the environment.
+// Completed is set once apply has finished, a snapshot is only replaced when the apply that
+// created it was completed, so repeated failures do not replace the last working state.
+type Snapshot struct {
+	Created    time.Time             `json:"created"`
+	Completed  bool                  `json:"completed"`
+	Containers []types.ContainerJSON `json:"containers"`
+}
+
+// Path returns the path to the snapshot in the nitro directory.
+func Path(home string) string {
+	return filepath.Join(home, config.DirectoryName, File)
+}
+
+// Load returns the snapshot in the nitro directory.
+func Load(home string) (*Snapshot, error) {
+	content, err := ioutil.ReadFile(Path(home))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNoSnapshot
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var s Snapshot
+	if err := json.Unmarshal(content, &s); err != nil {
+		return nil, fmt.Errorf("unable to read the snapshot, %w", err)
+	}
+
+	return &s, nil
+}
+
+// Save writes the snapshot to the nitro directory.
+func (s *Snapshot) Save(home string) error {
+	content, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(Path(home), content, 0600)
+}
+
+// Take inspects each of the containers that apply manages and returns the snapshot.
+func Take(ctx context.Context, docker client.ContainerAPIClient) (*Snapshot, error) {
+	containers, err := list(ctx, docker)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Snapshot{Created: time.Now()}
+	for _, c := range containers {
+		details, err := docker.ContainerInspect(ctx, c.ID)
+		if err != nil {
+			return nil, fmt.Errorf("unable to inspect %s, %w", name(c), err)
+		}
+
+		s.Containers = append(s.Containers, details)
+	}
+
+	return s, nil
+}
+
+// Record takes a snapshot before apply makes changes. The snapshot is not replaced when
+// the previous apply did not complete, so it is possible to roll back to the state before
+// the first apply that failed.
+func Record(ctx context.Context, docker client.ContainerAPIClient, home string) error {
+	if prev, err := Load(home); err == nil && !prev.Completed {
+		return nil
+	}
+
+	s, err := Take(ctx, docker)
+	if err != nil {
+		return err
+	}
+
+	return s.Save(home)
+}
+
+// Complete marks the snapshot as completed once apply has finished making changes.
+func Complete(home string) error {
+	s, err := Load(home)
+	if err != nil {
+		return err
+	}
+
+	s.Completed = true
+
+	return s.Save(home)
+}
+
+// Restore recreates the containers in the snapshot that were changed or removed since the
+// snapshot was taken and removes the containers that were created since. Containers that
+// have not changed are kept and started if they were running. Volumes are never removed,
+// so the recreated containers use the same data.
+func Restore(ctx context.Context, docker client.ContainerAPIClient, s *Snapshot, output terminal.Outputer) error {
+	containers, err := list(ctx, docker)
+	if err != nil {
+		return err
+	}
+
+	current := make(map[string]types.Container)
+	for _, c := range containers {
+		current[name(c)] = c
+	}
+
+	for _, details := range s.Containers {
+		n := strings.TrimLeft(details.Name, "/")
+
+		output.Pending("restoring", n)
+
+		if c, ok := current[n]; ok {
+			delete(current, n)
+
+			// the container has not changed
+			if c.ID == details.ID {
+				if details.State != nil && details.State.Running && c.State != "running" {
+					if err := docker.ContainerStart(ctx, c.ID, types.ContainerStartOptions{}); err != nil {
+						output.Warning()
+						return fmt.Errorf("unable to start %s, %w", n, err)
+					}
+				}
+
+				output.Done()
+
+				continue
+			}
+
+			if err := remove(ctx, docker, c.ID); err != nil {
+				output.Warning()
+				return fmt.Errorf("unable to remove %s, %w", n, err)
+			}
+		}
+
+		if err := recreate(ctx, docker, details); err != nil {
+			output.Warning()
+			return fmt.Errorf("unable to recreate %s, %w", n, err)
+		}
+
+		output.Done()
+	}
+
+	// remove the containers apply created after the snapshot
+	for n, c := range current {
+		output.Pending("removing", n)
+
+		if err := remove(ctx, docker, c.ID); err != nil {
+			output.Warning()
+			return fmt.Errorf("unable to remove %s, %w", n, err)
+		}
+
+		output.Done()
+	}
+
+	return nil
+}
+
+// recreate creates the container from its definition in the snapshot and starts it if it
+// was running. The container uses the image ID, so it is the same image when a newer
+// version of the tag has been pulled since.
+func recreate(ctx context.Context, docker client.ContainerAPIClient, details types.ContainerJSON) error {
+	cfg := details.Config
+	if cfg == nil {
+		return fmt.Errorf("the snapshot does not have the config of the container")
+	}
+
+	if details.Image != "" {
+		cfg.Image = details.Image
+	}
+
+	endpoints := make(map[string]*network.EndpointSettings)
+	if details.NetworkSettings != nil {
+		for n, e := range details.NetworkSettings.Networks {
+			// docker adds the short ID as an alias to each container
+			var aliases []string
+			for _, a := range e.Aliases {
+				if len(a) != 12 || !strings.HasPrefix(details.ID, a) {
+					aliases = append(aliases, a)
+				}
+			}
+
+			endpoints[n] = &network.EndpointSettings{NetworkID: e.NetworkID, Aliases: aliases}
+		}
+	}
+
+	resp, err := docker.ContainerCreate(ctx, cfg, details.HostConfig, &network.NetworkingConfig{EndpointsConfig: endpoints}, nil, strings.TrimLeft(details.Name, "/"))
+	if err != nil {
+		return err
+	}
+
+	if details.State == nil || !details.State.Running {
+		return nil
+	}
+
+	return docker.ContainerStart(ctx, resp.ID, types.ContainerStartOptions{})
+}
+
+// remove stops and removes the container, the volumes of the container are kept.
+func remove(ctx context.Context, docker client.ContainerAPIClient, id string) error {
+	if err := docker.ContainerStop(ctx, id, nil); err != nil {
+		return err
+	}
+
+	return docker.ContainerRemove(ctx, id, types.ContainerRemoveOptions{})
+}
+
+// list returns the containers apply manages, the proxy and share tunnels are not included
+// since apply does not recreate them.
+func list(ctx context.Context, docker client.ContainerAPIClient) ([]types.Container, error) {
+	filter := filters.NewArgs()
+	filter.Add("label", containerlabels.Nitro+"="+environment.Label())
+
+	containers, err := docker.ContainerList(ctx, types.ContainerListOptions{All: true, Filters: filter})
+	if err != nil {
+		return nil, fmt.Errorf("unable to list the containers, %w", err)
+	}
+
+	var managed []types.Container
+	for _, c := range containers {
+		if c.Labels[containerlabels.Proxy] != "" || c.Labels[containerlabels.Type] == "share" {
+			continue
+		}
+
+		managed = append(managed, c)
+	}
+
+	return managed, nil
+}
+
+func name(c types.Container) string {
+	return strings.TrimLeft(c.Names[0], "/")
+}
@@ -0,0 +1,187 @@
+package snapshot
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/client"
+	specs "github.com/opencontainers/image-spec/specs-go/v1"
+
+	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/terminal"
+)
+
+func TestRecord(t *testing.T) {
+	home, err := ioutil.TempDir("", "nitro-snapshot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(home)
+
+	if err := os.MkdirAll(filepath.Join(home, config.DirectoryName), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	spy := &mockClient{
+		containers: []types.Container{
+			{ID: "proxy", Names: []string{"/nitro-proxy"}, Labels: map[string]string{containerlabels.Nitro: "true", containerlabels.Proxy: "true"}},
+			{ID: "site", Names: []string{"/craft.nitro"}, Labels: map[string]string{containerlabels.Nitro: "true"}},
+		},
+		details: map[string]types.ContainerJSON{
+			"site": {ContainerJSONBase: &types.ContainerJSONBase{ID: "site", Name: "/craft.nitro"}},
+		},
+	}
+
+	if err := Record(context.Background(), spy, home); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := Load(home)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(s.Containers) != 1 || s.Containers[0].ID != "site" {
+		t.Fatalf("expected only the site to be in the snapshot, got %v", s.Containers)
+	}
+
+	// the snapshot is kept until the apply is completed
+	spy.details["site"] = types.ContainerJSON{ContainerJSONBase: &types.ContainerJSONBase{ID: "changed", Name: "/craft.nitro"}}
+	if err := Record(context.Background(), spy, home); err != nil {
+		t.Fatal(err)
+	}
+
+	if s, _ := Load(home); s.Containers[0].ID != "site" {
+		t.Errorf("expected the snapshot to be kept when the previous apply did not complete")
+	}
+
+	if err := Complete(home); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Record(context.Background(), spy, home); err != nil {
+		t.Fatal(err)
+	}
+
+	if s, _ := Load(home); s.Containers[0].ID != "changed" {
+		t.Errorf("expected the snapshot to be replaced when the previous apply completed")
+	}
+}
+
+func TestRestore(t *testing.T) {
+	running := &types.ContainerState{Running: true}
+
+	s := &Snapshot{
+		Created: time.Now(),
+		Containers: []types.ContainerJSON{
+			{
+				ContainerJSONBase: &types.ContainerJSONBase{ID: "db", Name: "/mysql-8.0-3306.database.nitro", State: running, Image: "sha256:mysql"},
+				Config:            &container.Config{Image: "docker.io/library/mysql:8.0"},
+			},
+			{
+				ContainerJSONBase: &types.ContainerJSONBase{ID: "0123456789abcdef", Name: "/craft.nitro", State: running, Image: "sha256:nginx", HostConfig: &container.HostConfig{}},
+				Config:            &container.Config{Image: "docker.io/craftcms/nginx:7.4-dev"},
+				NetworkSettings: &types.NetworkSettings{Networks: map[string]*network.EndpointSettings{
+					"nitro-network": {NetworkID: "network-id", Aliases: []string{"0123456789ab", "craft.nitro"}},
+				}},
+			},
+		},
+	}
+
+	spy := &mockClient{
+		containers: []types.Container{
+			{ID: "db", Names: []string{"/mysql-8.0-3306.database.nitro"}, State: "exited", Labels: map[string]string{containerlabels.Nitro: "true"}},
+			{ID: "new-site-id", Names: []string{"/craft.nitro"}, State: "running", Labels: map[string]string{containerlabels.Nitro: "true"}},
+			{ID: "redis", Names: []string{"/redis.service.nitro"}, State: "running", Labels: map[string]string{containerlabels.Nitro: "true"}},
+		},
+	}
+
+	if err := Restore(context.Background(), spy, s, terminal.New()); err != nil {
+		t.Fatal(err)
+	}
+
+	sort.Strings(spy.removed)
+	if want := []string{"new-site-id", "redis"}; !reflect.DeepEqual(spy.removed, want) {
+		t.Errorf("expected the changed and new containers to be removed, got %v, want %v", spy.removed, want)
+	}
+
+	if len(spy.created) != 1 || spy.created[0].name != "craft.nitro" {
+		t.Fatalf("expected the site to be recreated, got %v", spy.created)
+	}
+
+	if got := spy.created[0].config.Image; got != "sha256:nginx" {
+		t.Errorf("expected the site to be recreated with the image ID, got %s", got)
+	}
+
+	if got := spy.created[0].network.EndpointsConfig["nitro-network"].Aliases; !reflect.DeepEqual(got, []string{"craft.nitro"}) {
+		t.Errorf("expected the short ID alias to be removed, got %v", got)
+	}
+
+	if want := []string{"db", "created-craft.nitro"}; !reflect.DeepEqual(spy.started, want) {
+		t.Errorf("expected the stopped database and recreated site to be started, got %v, want %v", spy.started, want)
+	}
+}
+
+type created struct {
+	name    string
+	config  *container.Config
+	network *network.NetworkingConfig
+}
+
+type mockClient struct {
+	client.ContainerAPIClient
+
+	containers []types.Container
+	details    map[string]types.ContainerJSON
+
+	created []created
+	removed []string
+	started []string
+}
+
+func (c *mockClient) ContainerList(ctx context.Context, options types.ContainerListOptions) ([]types.Container, error) {
+	var containers []types.Container
+	for _, container := range c.containers {
+		if options.Filters.MatchKVList("label", container.Labels) {
+			containers = append(containers, container)
+		}
+	}
+
+	return containers, nil
+}
+
+func (c *mockClient) ContainerInspect(ctx context.Context, container string) (types.ContainerJSON, error) {
+	return c.details[container], nil
+}
+
+func (c *mockClient) ContainerCreate(ctx context.Context, config *container.Config, hostConfig *container.HostConfig, networkingConfig *network.NetworkingConfig, platform *specs.Platform, containerName string) (container.ContainerCreateCreatedBody, error) {
+	c.created = append(c.created, created{name: containerName, config: config, network: networkingConfig})
+
+	return container.ContainerCreateCreatedBody{ID: "created-" + containerName}, nil
+}
+
+func (c *mockClient) ContainerStart(ctx context.Context, container string, options types.ContainerStartOptions) error {
+	c.started = append(c.started, container)
+
+	return nil
+}
+
+func (c *mockClient) ContainerStop(ctx context.Context, container string, timeout *time.Duration) error {
+	return nil
+}
+
+func (c *mockClient) ContainerRemove(ctx context.Context, container string, options types.ContainerRemoveOptions) error {
+	c.removed = append(c.removed, container)
+
+	return nil
+}
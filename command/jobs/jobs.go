@@ -0,0 +1,51 @@
+package jobs
+
+import (
+	"github.com/docker/docker/client"
+	"github.com/rodaine/table"
+	"github.com/spf13/cobra"
+
+	"github.com/craftcms/nitro/pkg/jobs"
+	"github.com/craftcms/nitro/pkg/terminal"
+)
+
+const exampleText = `  # show the status of the background jobs
+  nitro jobs
+
+  # show the output of a job
+  nitro jobs logs 3f2a9c1d`
+
+// NewCommand returns the command to show the status of the jobs that run in the
+// background, such as database imports started with the --background flag.
+func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outputer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "jobs",
+		Short:   "Shows the status of background jobs.",
+		Example: exampleText,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			all, err := jobs.Load(home)
+			if err != nil {
+				return err
+			}
+
+			if len(all) == 0 {
+				output.Info("There are no background jobs")
+
+				return nil
+			}
+
+			tbl := table.New("ID", "Description", "Container", "Started", "Status").WithWriter(cmd.OutOrStdout()).WithPadding(2)
+			for _, j := range all {
+				tbl.AddRow(j.ID, j.Description, j.Container, j.Started.Format("Jan 2 15:04"), jobs.Status(cmd.Context(), docker, j))
+			}
+
+			tbl.Print()
+
+			return nil
+		},
+	}
+
+	cmd.AddCommand(logsCommand(home, docker, output))
+
+	return cmd
+}
@@ -0,0 +1,81 @@
+package jobs
+
+import (
+	"fmt"
+
+	"github.com/docker/docker/client"
+	"github.com/spf13/cobra"
+
+	"github.com/craftcms/nitro/pkg/jobs"
+	"github.com/craftcms/nitro/pkg/prompt"
+	"github.com/craftcms/nitro/pkg/terminal"
+)
+
+func logsCommand(home string, docker client.CommonAPIClient, output terminal.Outputer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "logs [ID]",
+		Short: "Shows the output of a background job.",
+		Args:  cobra.MaximumNArgs(1),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			all, err := jobs.Load(home)
+			if err != nil {
+				return nil, cobra.ShellCompDirectiveDefault
+			}
+
+			var options []string
+			for _, j := range all {
+				options = append(options, j.ID)
+			}
+
+			return options, cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var id string
+			if len(args) > 0 {
+				id = args[0]
+			} else {
+				// ask which job to show when the id is not an argument
+				all, err := jobs.Load(home)
+				if err != nil {
+					return err
+				}
+
+				if len(all) == 0 {
+					return fmt.Errorf("there are no background jobs")
+				}
+
+				var options []string
+				for _, j := range all {
+					options = append(options, j.ID+" "+j.Description)
+				}
+
+				selected, err := prompt.Select(cmd.InOrStdin(), "Select a job: ", options, output)
+				if err != nil {
+					return err
+				}
+
+				id = all[selected].ID
+			}
+
+			job, err := jobs.Find(home, id)
+			if err != nil {
+				return fmt.Errorf("%w %s, run `nitro jobs` to see the jobs", err, id)
+			}
+
+			logs, err := jobs.Logs(cmd.Context(), docker, *job)
+			if err != nil {
+				return err
+			}
+
+			if logs != "" {
+				fmt.Fprintln(cmd.OutOrStdout(), logs)
+			}
+
+			output.Info(fmt.Sprintf("Job %s is %s", job.ID, jobs.Status(cmd.Context(), docker, *job)))
+
+			return nil
+		},
+	}
+
+	return cmd
+}
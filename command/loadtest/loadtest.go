@@ -0,0 +1,196 @@
+package loadtest
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rodaine/table"
+	"github.com/spf13/cobra"
+
+	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/dockercontext"
+	"github.com/craftcms/nitro/pkg/environment"
+	"github.com/craftcms/nitro/pkg/terminal"
+)
+
+const exampleText = `  # send requests to a site for 10 seconds with 10 concurrent requests
+  nitro loadtest tutorial.nitro
+
+  # send 50 concurrent requests for a minute
+  nitro loadtest tutorial.nitro --concurrency 50 --duration 1m
+
+  # request each of the paths in turn
+  nitro loadtest tutorial.nitro --path / --path /blog --path /api/entries.json
+
+  # use HTTP instead of HTTPS
+  nitro loadtest tutorial.nitro --http
+
+  # show the result as JSON
+  nitro loadtest tutorial.nitro --json`
+
+// NewCommand returns the loadtest command, which sends requests to a site through the proxy
+// and shows the latency percentiles and the error rate. It is used to compare the response
+// times of a site before and after changes, such as the opcache or caching settings.
+func NewCommand(home string, output terminal.Outputer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "loadtest <host>",
+		Short:   "Sends requests to a site and shows the response times.",
+		Args:    cobra.ExactArgs(1),
+		Example: exampleText,
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			cfg, err := config.Load(home)
+			if err != nil {
+				return nil, cobra.ShellCompDirectiveDefault
+			}
+
+			var options []string
+			for _, s := range cfg.Sites {
+				options = append(options, s.Hostname)
+			}
+
+			return options, cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			cfg, err := config.Load(home)
+			if err != nil {
+				return err
+			}
+
+			host := strings.TrimSpace(args[0])
+			if !known(cfg, host) {
+				return fmt.Errorf("unable to find the site %s, the host must be the hostname or an alias of a site", host)
+			}
+
+			concurrency, _ := cmd.Flags().GetInt("concurrency")
+			duration, _ := cmd.Flags().GetDuration("duration")
+			paths, _ := cmd.Flags().GetStringSlice("path")
+			useHTTP, _ := cmd.Flags().GetBool("http")
+
+			if concurrency < 1 {
+				return fmt.Errorf("the concurrency must be at least 1")
+			}
+
+			if duration <= 0 {
+				return fmt.Errorf("the duration must be greater than 0")
+			}
+
+			for i, p := range paths {
+				if !strings.HasPrefix(p, "/") {
+					paths[i] = "/" + p
+				}
+			}
+
+			scheme, port := "https", environment.Port("NITRO_HTTPS_PORT", environment.Ports.HTTPS)
+			if useHTTP {
+				scheme, port = "http", environment.Port("NITRO_HTTP_PORT", environment.Ports.HTTP)
+			}
+
+			if !terminal.JSON {
+				output.Info(fmt.Sprintf("Sending requests to %s://%s with %d concurrent requests for %s…", scheme, host, concurrency, duration))
+			}
+
+			// requests are sent to the proxy directly, so the hostname does not need to resolve
+			client := newClient(net.JoinHostPort(dockercontext.Current.Address(), port), host, concurrency)
+
+			res := run(ctx, client, scheme+"://"+host, options{Concurrency: concurrency, Duration: duration, Paths: paths})
+			res.Host = host
+
+			if terminal.JSON {
+				return output.JSON(res)
+			}
+
+			if res.Requests == 0 {
+				return fmt.Errorf("no requests were completed in %s", duration)
+			}
+
+			output.Info(fmt.Sprintf("%d requests, %.2f requests per second, %d errors (%.2f%%)", res.Requests, res.RequestsPerSecond, res.Errors, res.ErrorRate*100))
+
+			tbl := table.New("Min", "Mean", "P50", "P90", "P95", "P99", "Max").WithWriter(cmd.OutOrStdout()).WithPadding(2)
+			l := res.Latency
+			tbl.AddRow(ms(l.Min), ms(l.Mean), ms(l.P50), ms(l.P90), ms(l.P95), ms(l.P99), ms(l.Max))
+			tbl.Print()
+
+			// show the number of responses for each status code
+			var codes []int
+			for c := range res.Statuses {
+				codes = append(codes, c)
+			}
+			sort.Ints(codes)
+
+			var statuses []string
+			for _, c := range codes {
+				statuses = append(statuses, fmt.Sprintf("%d: %d", c, res.Statuses[c]))
+			}
+
+			if len(statuses) > 0 {
+				output.Info("Responses by status", strings.Join(statuses, ", "))
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().IntP("concurrency", "c", 10, "the number of concurrent requests")
+	cmd.Flags().DurationP("duration", "d", 10*time.Second, "how long to send requests for")
+	cmd.Flags().StringSliceP("path", "p", []string{"/"}, "the paths to request in turn")
+	cmd.Flags().Bool("http", false, "use HTTP instead of HTTPS")
+
+	return cmd
+}
+
+// known returns true when the host is the hostname or an alias of a site.
+func known(cfg *config.Config, host string) bool {
+	for _, s := range cfg.Sites {
+		if s.Hostname == host {
+			return true
+		}
+
+		for _, a := range s.Aliases {
+			if a == host {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// newClient returns a client that sends each request to the proxy address. The certificates
+// of the sites are not verified since the root certificate may not be trusted yet, and the
+// redirects are not followed so each request is timed on its own.
+func newClient(addr, host string, concurrency int) *http.Client {
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+
+	return &http.Client{
+		Timeout: 30 * time.Second,
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, network, _ string) (net.Conn, error) {
+				return dialer.DialContext(ctx, network, addr)
+			},
+			TLSClientConfig:     &tls.Config{ServerName: host, InsecureSkipVerify: true},
+			MaxIdleConns:        concurrency,
+			MaxIdleConnsPerHost: concurrency,
+			ForceAttemptHTTP2:   true,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// ms formats the milliseconds for the table.
+func ms(v float64) string {
+	return strconv.FormatFloat(v, 'f', 2, 64) + "ms"
+}
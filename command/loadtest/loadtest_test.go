@@ -0,0 +1,68 @@
+package loadtest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func Test_run(t *testing.T) {
+	var mu sync.Mutex
+	requested := make(map[string]int)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requested[r.URL.Path]++
+		mu.Unlock()
+
+		if r.URL.Path == "/broken" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	res := run(context.Background(), srv.Client(), srv.URL, options{Concurrency: 4, Duration: 100 * time.Millisecond, Paths: []string{"/", "/broken"}})
+
+	if res.Requests == 0 {
+		t.Fatal("expected requests to be sent")
+	}
+
+	if requested["/"] == 0 || requested["/broken"] == 0 {
+		t.Errorf("expected each of the paths to be requested, got %v", requested)
+	}
+
+	if res.Errors != res.Statuses[http.StatusInternalServerError] || res.Statuses[http.StatusOK]+res.Errors != res.Requests {
+		t.Errorf("expected the 5xx responses to be counted as errors, got %d errors and %v", res.Errors, res.Statuses)
+	}
+
+	if res.Latency.Min > res.Latency.P50 || res.Latency.P50 > res.Latency.P99 || res.Latency.P99 > res.Latency.Max {
+		t.Errorf("expected the percentiles to be in order, got %+v", res.Latency)
+	}
+}
+
+func Test_summarize(t *testing.T) {
+	var samples []sample
+	for i := 1; i <= 100; i++ {
+		samples = append(samples, sample{duration: time.Duration(i) * time.Millisecond, status: http.StatusOK})
+	}
+
+	samples[0].failed = true
+	samples[0].status = 0
+
+	res := summarize(samples, 2*time.Second)
+
+	want := latency{Min: 1, Mean: 50.5, P50: 50, P90: 90, P95: 95, P99: 99, Max: 100}
+	if res.Latency != want {
+		t.Errorf("summarize() latency = %+v, want %+v", res.Latency, want)
+	}
+
+	if res.Requests != 100 || res.Errors != 1 || res.ErrorRate != 0.01 || res.RequestsPerSecond != 50 || res.Statuses[http.StatusOK] != 99 {
+		t.Errorf("unexpected summary %+v", res)
+	}
+}
@@ -0,0 +1,176 @@
+package loadtest
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// options are the settings of a load test.
+type options struct {
+	Concurrency int
+	Duration    time.Duration
+	Paths       []string
+}
+
+// result is the summary of a load test, it is also the JSON output of the command.
+type result struct {
+	Host              string      `json:"host"`
+	Requests          int         `json:"requests"`
+	Errors            int         `json:"errors"`
+	ErrorRate         float64     `json:"error_rate"`
+	RequestsPerSecond float64     `json:"requests_per_second"`
+	Latency           latency     `json:"latency"`
+	Statuses          map[int]int `json:"statuses"`
+}
+
+// latency is the latency of the requests in milliseconds.
+type latency struct {
+	Min  float64 `json:"min"`
+	Mean float64 `json:"mean"`
+	P50  float64 `json:"p50"`
+	P90  float64 `json:"p90"`
+	P95  float64 `json:"p95"`
+	P99  float64 `json:"p99"`
+	Max  float64 `json:"max"`
+}
+
+// sample is the outcome of a single request.
+type sample struct {
+	duration time.Duration
+	status   int
+	failed   bool
+}
+
+// run sends requests to each of the paths of the base URL with the number of concurrent
+// workers until the duration has passed. The paths are requested in turn. Requests that
+// fail or respond with a 5xx status are counted as errors.
+func run(ctx context.Context, client *http.Client, baseURL string, opts options) result {
+	ctx, cancel := context.WithTimeout(ctx, opts.Duration)
+	defer cancel()
+
+	var next uint64
+	samples := make([][]sample, opts.Concurrency)
+
+	start := time.Now()
+
+	var wg sync.WaitGroup
+	for w := 0; w < opts.Concurrency; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+
+			for ctx.Err() == nil {
+				path := opts.Paths[atomic.AddUint64(&next, 1)%uint64(len(opts.Paths))]
+
+				s, ok := request(ctx, client, baseURL+path)
+				if !ok {
+					continue
+				}
+
+				samples[w] = append(samples[w], s)
+			}
+		}(w)
+	}
+
+	wg.Wait()
+
+	var all []sample
+	for _, s := range samples {
+		all = append(all, s...)
+	}
+
+	return summarize(all, time.Since(start))
+}
+
+// request sends a single request and returns false when the request was canceled at the
+// end of the load test, so it is not counted.
+func request(ctx context.Context, client *http.Client, url string) (sample, bool) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return sample{failed: true}, true
+	}
+
+	start := time.Now()
+
+	resp, err := client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return sample{}, false
+		}
+
+		return sample{duration: time.Since(start), failed: true}, true
+	}
+
+	// read the whole body so the time includes the response and the connection is reused
+	_, err = io.Copy(ioutil.Discard, resp.Body)
+	resp.Body.Close()
+
+	if err != nil && ctx.Err() != nil {
+		return sample{}, false
+	}
+
+	return sample{duration: time.Since(start), status: resp.StatusCode, failed: err != nil || resp.StatusCode >= 500}, true
+}
+
+// summarize returns the number of requests, errors, and the latency percentiles of the
+// samples.
+func summarize(samples []sample, elapsed time.Duration) result {
+	res := result{Requests: len(samples), Statuses: make(map[int]int)}
+	if len(samples) == 0 {
+		return res
+	}
+
+	durations := make([]float64, 0, len(samples))
+	var total float64
+	for _, s := range samples {
+		if s.failed {
+			res.Errors++
+		}
+
+		if s.status != 0 {
+			res.Statuses[s.status]++
+		}
+
+		ms := float64(s.duration) / float64(time.Millisecond)
+		durations = append(durations, ms)
+		total += ms
+	}
+
+	sort.Float64s(durations)
+
+	res.ErrorRate = round(float64(res.Errors) / float64(res.Requests))
+	res.RequestsPerSecond = round(float64(res.Requests) / elapsed.Seconds())
+	res.Latency = latency{
+		Min:  round(durations[0]),
+		Mean: round(total / float64(len(durations))),
+		P50:  round(percentile(durations, 50)),
+		P90:  round(percentile(durations, 90)),
+		P95:  round(percentile(durations, 95)),
+		P99:  round(percentile(durations, 99)),
+		Max:  round(durations[len(durations)-1]),
+	}
+
+	return res
+}
+
+// percentile returns the nearest-rank percentile of the sorted values.
+func percentile(sorted []float64, p float64) float64 {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+
+	return sorted[rank-1]
+}
+
+// round rounds the value to two decimals for the output.
+func round(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -1,8 +1,10 @@
 package logs
 
 import (
+	"fmt"
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/filters"
@@ -12,6 +14,8 @@ import (
 
 	"github.com/craftcms/nitro/pkg/config"
 	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/environment"
+	"github.com/craftcms/nitro/pkg/prompt"
 	"github.com/craftcms/nitro/pkg/terminal"
 )
 
@@ -21,18 +25,48 @@ const exampleText = `  # show logs from a site
   # show only the last 5 minutes
   nitro logs --since 5m
 
-  # show logs but don't follow
-  nitro logs --follow=false`
+  # show logs but don't follow, long logs are shown in $PAGER
+  nitro logs --follow=false
 
-// NewCommand returns the command to show a containers logs. It will check if the current working
-// directory is a known site and default to that container or provide the user with a list of sites
-// to view logs from. There are helpful flags such as since, timestamps, and follow that align with
+  # show the last 100 lines of logs for a site
+  nitro logs demo.nitro --tail 100
+
+  # show logs for a service
+  nitro logs mailhog
+
+  # show the requests slower than the slow_requests setting of the sites
+  nitro logs proxy --slow
+
+  # remove the logs of the containers
+  nitro logs prune`
+
+// NewCommand returns the command to show a containers logs. A site hostname or service can be
+// provided, otherwise it will check if the current working directory is a known site and default
+// to that container or provide the user with a list of sites to view logs from. There are helpful flags such as since, timestamps, and follow that align with
 // the docker logs API flags.
 func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outputer) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "logs",
 		Short:   "Displays container logs.",
 		Example: exampleText,
+		Args:    cobra.MaximumNArgs(1),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			cfg, err := config.Load(home)
+			if err != nil {
+				return nil, cobra.ShellCompDirectiveDefault
+			}
+
+			var options []string
+			for _, s := range cfg.Sites {
+				options = append(options, s.Hostname)
+			}
+
+			for _, c := range cfg.Containers {
+				options = append(options, c.Name)
+			}
+
+			return options, cobra.ShellCompDirectiveNoFileComp
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// get the current working directory
 			wd, err := os.Getwd()
@@ -48,7 +82,41 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 
 			// create a filter for the environment
 			filter := filters.NewArgs()
-			filter.Add("label", containerlabels.Nitro)
+			filter.Add("label", containerlabels.Nitro+"="+environment.Label())
+
+			// show the slow requests from the access log of the proxy
+			if cmd.Flag("slow").Value.String() == "true" {
+				if len(args) > 0 && strings.TrimSpace(args[0]) != "proxy" {
+					return fmt.Errorf("the --slow flag can only be used with the proxy logs")
+				}
+
+				containers, err := docker.ContainerList(cmd.Context(), types.ContainerListOptions{Filters: filter})
+				if err != nil {
+					return err
+				}
+
+				id := find(containers, "proxy")
+				if id == "" {
+					return fmt.Errorf("unable to find a running container for the proxy")
+				}
+
+				return slow(cmd, docker, id, thresholds(cfg))
+			}
+
+			// find the container by the hostname or service if provided
+			if len(args) > 0 {
+				containers, err := docker.ContainerList(cmd.Context(), types.ContainerListOptions{Filters: filter})
+				if err != nil {
+					return err
+				}
+
+				id := find(containers, strings.TrimSpace(args[0]))
+				if id == "" {
+					return fmt.Errorf("unable to find a running container for %s", args[0])
+				}
+
+				return show(cmd, docker, id)
+			}
 
 			// get a context aware list of sites
 			sites := cfg.ListOfSitesByDirectory(home, wd)
@@ -61,7 +129,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 
 			switch len(sites) {
 			case 0:
-				selected, err := output.Select(cmd.InOrStdin(), "Select a site: ", options)
+				selected, err := prompt.Select(cmd.InOrStdin(), "Select a site: ", options, output)
 				if err != nil {
 					return err
 				}
@@ -72,7 +140,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 
 				filter.Add("label", containerlabels.Host+"="+sites[0].Hostname)
 			default:
-				selected, err := output.Select(cmd.InOrStdin(), "Select a site: ", options)
+				selected, err := prompt.Select(cmd.InOrStdin(), "Select a site: ", options, output)
 				if err != nil {
 					return err
 				}
@@ -86,46 +154,95 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 				return err
 			}
 
-			// set the options for logging based on the command flags
-			opts := types.ContainerLogsOptions{
-				ShowStdout: true,
-				ShowStderr: true,
+			if len(containers) == 0 {
+				return fmt.Errorf("unable to find a running container for the site")
 			}
 
-			// parse the flags
-			timestamps, err := strconv.ParseBool(cmd.Flag("timestamps").Value.String())
-			if err != nil {
-				timestamps = false
-			}
-			opts.Timestamps = timestamps
-
-			follow, err := strconv.ParseBool(cmd.Flag("follow").Value.String())
-			if err != nil {
-				follow = true
-			}
-			opts.Follow = follow
-
-			if cmd.Flag("since").Value.String() != "" {
-				opts.Since = cmd.Flag("since").Value.String()
-			}
-
-			// get the containers logs
-			out, err := docker.ContainerLogs(cmd.Context(), containers[0].ID, opts)
-			if err != nil {
-				return err
-			}
-
-			// show the output
-			stdcopy.StdCopy(cmd.OutOrStdout(), cmd.ErrOrStderr(), out)
-
-			return nil
+			return show(cmd, docker, containers[0].ID)
 		},
 	}
 
+	cmd.AddCommand(pruneCommand(docker, output))
+
 	// set flags for the command
 	cmd.Flags().Bool("follow", true, "follow log output")
 	cmd.Flags().Bool("timestamps", false, "show timestamps")
 	cmd.Flags().String("since", "", "Show logs since timestamp (e.g. 2013-01-02T13:23:37Z) or relative (e.g. 42m for 42 minutes)")
+	cmd.Flags().String("tail", "all", "number of lines to show from the end of the logs")
+	cmd.Flags().Bool("slow", false, "only show slow requests from the proxy")
 
 	return cmd
 }
+
+// find takes a list of containers and returns the ID of the container that matches the name. The
+// name can be a site hostname (e.g. demo.nitro), a service (e.g. mailhog), a custom container, or
+// the proxy.
+func find(containers []types.Container, name string) string {
+	for _, candidate := range []string{name, name + ".service.nitro", name + ".containers.nitro", "nitro-" + name} {
+		for _, c := range containers {
+			for _, n := range c.Names {
+				if strings.TrimLeft(n, "/") == candidate {
+					return c.ID
+				}
+			}
+		}
+	}
+
+	return ""
+}
+
+// show streams the logs for the container using the command flags.
+func show(cmd *cobra.Command, docker client.CommonAPIClient, id string) error {
+	opts := options(cmd)
+
+	// get the containers logs
+	out, err := docker.ContainerLogs(cmd.Context(), id, opts)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	// page the logs unless they are followed
+	stdout := cmd.OutOrStdout()
+	if !opts.Follow {
+		pager := terminal.NewPager(stdout)
+		defer pager.Close()
+
+		stdout = pager
+	}
+
+	// show the output
+	_, err = stdcopy.StdCopy(stdout, cmd.ErrOrStderr(), out)
+
+	return err
+}
+
+// options returns the options for the logs based on the command flags.
+func options(cmd *cobra.Command) types.ContainerLogsOptions {
+	// set the options for logging based on the command flags
+	opts := types.ContainerLogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+	}
+
+	// parse the flags
+	timestamps, err := strconv.ParseBool(cmd.Flag("timestamps").Value.String())
+	if err != nil {
+		timestamps = false
+	}
+	opts.Timestamps = timestamps
+
+	follow, err := strconv.ParseBool(cmd.Flag("follow").Value.String())
+	if err != nil {
+		follow = true
+	}
+	opts.Follow = follow
+
+	if cmd.Flag("since").Value.String() != "" {
+		opts.Since = cmd.Flag("since").Value.String()
+	}
+
+	opts.Tail = cmd.Flag("tail").Value.String()
+
+	return opts
+}
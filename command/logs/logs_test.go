@@ -0,0 +1,36 @@
+package logs
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types"
+)
+
+func Test_find(t *testing.T) {
+	containers := []types.Container{
+		{ID: "site", Names: []string{"/demo.nitro"}},
+		{ID: "mailhog", Names: []string{"/mailhog.service.nitro"}},
+		{ID: "chrome", Names: []string{"/chrome.containers.nitro"}},
+		{ID: "proxy", Names: []string{"/nitro-proxy"}},
+	}
+
+	tests := []struct {
+		name string
+		arg  string
+		want string
+	}{
+		{name: "sites are found by the hostname", arg: "demo.nitro", want: "site"},
+		{name: "services are found by the name", arg: "mailhog", want: "mailhog"},
+		{name: "services are found by the hostname", arg: "mailhog.service.nitro", want: "mailhog"},
+		{name: "custom containers are found by the name", arg: "chrome", want: "chrome"},
+		{name: "the proxy is found by the name", arg: "proxy", want: "proxy"},
+		{name: "unknown names return an empty id", arg: "missing.nitro", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := find(containers, tt.arg); got != tt.want {
+				t.Errorf("find() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,247 @@
+package logs
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/rodaine/table"
+	"github.com/spf13/cobra"
+
+	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/environment"
+	"github.com/craftcms/nitro/pkg/terminal"
+)
+
+// PruneImage is used to truncate the log files on the docker host, the log files are not
+// on the host when docker runs in a virtual machine.
+const PruneImage = "docker.io/library/alpine:latest"
+
+// logFile is the json-file log of a container.
+type logFile struct {
+	Container string `json:"container"`
+	Path      string `json:"-"`
+	Size      int64  `json:"size"`
+}
+
+// pruned is the result of pruning the logs.
+type pruned struct {
+	Containers []logFile `json:"containers"`
+	Skipped    []string  `json:"skipped,omitempty"`
+	Reclaimed  int64     `json:"reclaimed"`
+}
+
+// pruneCommand returns the command to remove the logs of the containers. The log files are
+// truncated instead of removed because docker keeps the files open, so the logs of running
+// containers are removed without restarting the containers.
+func pruneCommand(docker client.CommonAPIClient, output terminal.Outputer) *cobra.Command {
+	return &cobra.Command{
+		Use:   "prune",
+		Short: "Removes the logs of the containers.",
+		Args:  cobra.NoArgs,
+		Example: `  # remove the logs of the containers and show the space reclaimed
+  nitro logs prune`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			filter := filters.NewArgs()
+			filter.Add("label", containerlabels.Nitro+"="+environment.Label())
+
+			containers, err := docker.ContainerList(ctx, types.ContainerListOptions{All: true, Filters: filter})
+			if err != nil {
+				return err
+			}
+
+			result := pruned{Containers: []logFile{}}
+			for _, c := range containers {
+				details, err := docker.ContainerInspect(ctx, c.ID)
+				if err != nil {
+					return err
+				}
+
+				name := strings.TrimLeft(details.Name, "/")
+
+				// only the json-file driver has a log path
+				if details.LogPath == "" {
+					result.Skipped = append(result.Skipped, name)
+					continue
+				}
+
+				result.Containers = append(result.Containers, logFile{Container: name, Path: details.LogPath})
+			}
+
+			sort.Slice(result.Containers, func(i, j int) bool {
+				return result.Containers[i].Container < result.Containers[j].Container
+			})
+
+			if len(result.Containers) > 0 {
+				output.Pending("pruning logs")
+
+				if err := truncate(cmd, docker, result.Containers); err != nil {
+					output.Warning()
+					return err
+				}
+
+				output.Done()
+			}
+
+			for _, f := range result.Containers {
+				result.Reclaimed += f.Size
+			}
+
+			if terminal.JSON {
+				return output.JSON(result)
+			}
+
+			if len(result.Containers) == 0 {
+				output.Info("There are no container logs to prune.")
+				return nil
+			}
+
+			tbl := table.New("Container", "Reclaimed").WithWriter(cmd.OutOrStdout()).WithPadding(2)
+			for _, f := range result.Containers {
+				tbl.AddRow(f.Container, size(f.Size))
+			}
+
+			tbl.Print()
+
+			if len(result.Skipped) > 0 {
+				output.Info("Skipped the containers that do not use the json-file log driver:", strings.Join(result.Skipped, ", "))
+			}
+
+			output.Info("Reclaimed", size(result.Reclaimed))
+
+			return nil
+		},
+	}
+}
+
+// truncate runs a container with the directory of each log file mounted and truncates the
+// log files, the log paths are paths on the docker host. The size of each file before it
+// was truncated is set on the files.
+func truncate(cmd *cobra.Command, docker client.CommonAPIClient, files []logFile) error {
+	ctx := cmd.Context()
+
+	rdr, err := docker.ImagePull(ctx, PruneImage, types.ImagePullOptions{})
+	if err != nil {
+		return fmt.Errorf("unable to pull the image %s, %w", PruneImage, err)
+	}
+
+	buf := &bytes.Buffer{}
+	if _, err := buf.ReadFrom(rdr); err != nil {
+		return fmt.Errorf("unable to read the output from pulling the image, %w", err)
+	}
+
+	var binds []string
+	for i, f := range files {
+		binds = append(binds, path.Dir(f.Path)+":"+path.Join("/logs", strconv.Itoa(i)))
+	}
+
+	resp, err := docker.ContainerCreate(
+		ctx,
+		&container.Config{
+			Image: PruneImage,
+			Cmd:   []string{"sh", "-c", script(files)},
+			Labels: map[string]string{
+				containerlabels.Nitro: environment.Label(),
+				containerlabels.Type:  "logs",
+			},
+		},
+		&container.HostConfig{Binds: binds},
+		nil,
+		nil,
+		"",
+	)
+	if err != nil {
+		return fmt.Errorf("unable to create the container to prune the logs, %w", err)
+	}
+
+	// always remove the container
+	defer docker.ContainerRemove(ctx, resp.ID, types.ContainerRemoveOptions{Force: true})
+
+	stream, err := docker.ContainerAttach(ctx, resp.ID, types.ContainerAttachOptions{
+		Stream: true,
+		Stdout: true,
+		Stderr: true,
+		Logs:   true,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to attach to the container, %w", err)
+	}
+	defer stream.Close()
+
+	if err := docker.ContainerStart(ctx, resp.ID, types.ContainerStartOptions{}); err != nil {
+		return fmt.Errorf("unable to start the container, %w", err)
+	}
+
+	out := &bytes.Buffer{}
+	if _, err := stdcopy.StdCopy(out, ioutil.Discard, stream.Reader); err != nil {
+		return fmt.Errorf("unable to read the output of the container, %w", err)
+	}
+
+	return sizes(out, files)
+}
+
+// script returns the commands that print the index and size of each log file before the
+// file is truncated. The directory of each log file is mounted in /logs by its index.
+func script(files []logFile) string {
+	var lines []string
+	for i, f := range files {
+		p := path.Join("/logs", strconv.Itoa(i), path.Base(f.Path))
+		lines = append(lines, fmt.Sprintf("echo %d $(stat -c %%s %s 2>/dev/null || echo 0) && truncate -s 0 %s 2>/dev/null", i, p, p))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// sizes reads the output of the script and sets the size of each log file.
+func sizes(r io.Reader, files []logFile) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+
+		i, err := strconv.Atoi(fields[0])
+		if err != nil || i < 0 || i >= len(files) {
+			continue
+		}
+
+		s, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+
+		files[i].Size = s
+	}
+
+	return scanner.Err()
+}
+
+// size returns the bytes in a human readable size (e.g. 1.5 MB).
+func size(b int64) string {
+	const unit = 1024
+	if b < unit {
+		return fmt.Sprintf("%d B", b)
+	}
+
+	div, exp := int64(unit), 0
+	for n := b / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %cB", float64(b)/float64(div), "KMGTPE"[exp])
+}
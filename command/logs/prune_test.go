@@ -0,0 +1,50 @@
+package logs
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_script(t *testing.T) {
+	files := []logFile{
+		{Container: "demo.nitro", Path: "/var/lib/docker/containers/abc/abc-json.log"},
+		{Container: "mailhog.service.nitro", Path: "/var/lib/docker/containers/def/def-json.log"},
+	}
+
+	want := `echo 0 $(stat -c %s /logs/0/abc-json.log 2>/dev/null || echo 0) && truncate -s 0 /logs/0/abc-json.log 2>/dev/null
+echo 1 $(stat -c %s /logs/1/def-json.log 2>/dev/null || echo 0) && truncate -s 0 /logs/1/def-json.log 2>/dev/null`
+
+	if got := script(files); got != want {
+		t.Errorf("script() = %q, want %q", got, want)
+	}
+}
+
+func Test_sizes(t *testing.T) {
+	files := []logFile{{Container: "demo.nitro"}, {Container: "mailhog.service.nitro"}, {Container: "nitro-proxy"}}
+
+	out := "0 1048576\n2 512\n7 100\nunexpected output\n"
+	if err := sizes(strings.NewReader(out), files); err != nil {
+		t.Fatal(err)
+	}
+
+	got := []int64{files[0].Size, files[1].Size, files[2].Size}
+	if want := []int64{1048576, 0, 512}; !reflect.DeepEqual(got, want) {
+		t.Errorf("sizes() = %v, want %v", got, want)
+	}
+}
+
+func Test_size(t *testing.T) {
+	tests := map[int64]string{
+		512:         "512 B",
+		1536:        "1.5 KB",
+		10485760:    "10.0 MB",
+		21474836480: "20.0 GB",
+	}
+
+	for b, want := range tests {
+		if got := size(b); got != want {
+			t.Errorf("size(%d) = %q, want %q", b, got, want)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package logs
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/spf13/cobra"
+
+	"github.com/craftcms/nitro/pkg/caddy"
+	"github.com/craftcms/nitro/pkg/config"
+)
+
+// entry is a request in the access log of the proxy.
+type entry struct {
+	Logger    string  `json:"logger"`
+	Timestamp float64 `json:"ts"`
+	Request   struct {
+		Method string `json:"method"`
+		Host   string `json:"host"`
+		URI    string `json:"uri"`
+	} `json:"request"`
+	// Duration is the time the proxy took to handle the request in seconds
+	Duration float64             `json:"duration"`
+	Status   int                 `json:"status"`
+	Headers  map[string][]string `json:"resp_headers"`
+}
+
+// thresholds returns the slow request threshold in milliseconds for each site that has
+// slow request logging enabled.
+func thresholds(cfg *config.Config) map[string]int {
+	t := make(map[string]int)
+	for _, s := range cfg.Sites {
+		if s.SlowRequests > 0 {
+			t[s.Hostname] = s.SlowRequests
+		}
+	}
+
+	return t
+}
+
+// slow streams the access log of the proxy and only shows the requests that took longer
+// than the threshold of the site.
+func slow(cmd *cobra.Command, docker client.CommonAPIClient, id string, thresholds map[string]int) error {
+	if len(thresholds) == 0 {
+		return fmt.Errorf("there are no sites with slow request logging, set slow_requests for a site and run `nitro apply`")
+	}
+
+	// timestamps are part of the access log
+	opts := options(cmd)
+	opts.Timestamps = false
+
+	out, err := docker.ContainerLogs(cmd.Context(), id, opts)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	// caddy writes the access log to stderr
+	pr, pw := io.Pipe()
+	go func() {
+		_, err := stdcopy.StdCopy(pw, pw, out)
+		pw.CloseWithError(err)
+	}()
+
+	scanner := bufio.NewScanner(pr)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if line, ok := parse(scanner.Bytes(), thresholds); ok {
+			fmt.Fprintln(cmd.OutOrStdout(), line)
+		}
+	}
+
+	return scanner.Err()
+}
+
+// parse returns the formatted request if the line is a request in the access log that took
+// longer than the threshold of the site.
+func parse(line []byte, thresholds map[string]int) (string, bool) {
+	var e entry
+	if err := json.Unmarshal(line, &e); err != nil {
+		return "", false
+	}
+
+	// the logger is named after the site
+	if !strings.HasPrefix(e.Logger, caddy.AccessLogger) {
+		return "", false
+	}
+
+	hostname := strings.TrimPrefix(e.Logger, caddy.AccessLogger)
+	threshold, ok := thresholds[hostname]
+	if !ok {
+		return "", false
+	}
+
+	duration := e.Duration * 1000
+	if duration < float64(threshold) {
+		return "", false
+	}
+
+	sec, frac := math.Modf(e.Timestamp)
+	ts := time.Unix(int64(sec), int64(frac*1e9)).Format("2006-01-02 15:04:05")
+
+	s := fmt.Sprintf("%s %6dms %d %s %s%s", ts, int(duration), e.Status, e.Request.Method, e.Request.Host, e.Request.URI)
+
+	// add the breakdown of the time from the Server-Timing headers
+	if t := timings(e.Headers["Server-Timing"]); len(t) > 0 {
+		s += " (" + strings.Join(t, ", ") + ")"
+	}
+
+	return s, true
+}
+
+// timings returns the durations in the Server-Timing headers (e.g. upstream;dur=120)
+// as a list of metrics and durations (e.g. upstream 120ms).
+func timings(headers []string) []string {
+	var t []string
+	for _, h := range headers {
+		for _, metric := range strings.Split(h, ",") {
+			parts := strings.Split(metric, ";")
+
+			name := strings.TrimSpace(parts[0])
+			for _, p := range parts[1:] {
+				p = strings.TrimSpace(p)
+				if strings.HasPrefix(p, "dur=") {
+					t = append(t, fmt.Sprintf("%s %sms", name, strings.TrimPrefix(p, "dur=")))
+				}
+			}
+		}
+	}
+
+	return t
+}
@@ -0,0 +1,53 @@
+package logs
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_parse(t *testing.T) {
+	thresholds := map[string]int{"craft.nitro": 500}
+
+	tests := []struct {
+		name string
+		line string
+		want string
+		ok   bool
+	}{
+		{
+			name: "slow requests are shown with the timings",
+			line: `{"level":"info","ts":1600000000.5,"logger":"http.log.access.craft.nitro","msg":"handled request","request":{"method":"GET","host":"craft.localhost","uri":"/admin"},"duration":1.2345,"status":200,"resp_headers":{"Server-Timing":["upstream;desc=\"Site\";dur=1200.5"]}}`,
+			want: "1234ms 200 GET craft.localhost/admin (upstream 1200.5ms)",
+			ok:   true,
+		},
+		{
+			name: "fast requests are not shown",
+			line: `{"logger":"http.log.access.craft.nitro","request":{"method":"GET","host":"craft.nitro","uri":"/"},"duration":0.1,"status":200}`,
+		},
+		{
+			name: "requests for sites without a threshold are not shown",
+			line: `{"logger":"http.log.access.other.nitro","request":{"method":"GET","host":"other.nitro","uri":"/"},"duration":3,"status":200}`,
+		},
+		{
+			name: "other logs are not shown",
+			line: `{"logger":"tls","msg":"certificate obtained","duration":3}`,
+		},
+		{
+			name: "lines that are not json are not shown",
+			line: `2021-01-01 12:00:00,000 INFO success: caddy entered RUNNING state`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parse([]byte(tt.line), thresholds)
+			if ok != tt.ok {
+				t.Fatalf("parse() ok = %v, want %v", ok, tt.ok)
+			}
+
+			// the timestamp depends on the local timezone
+			if !strings.HasSuffix(got, tt.want) {
+				t.Errorf("parse() = %q, want suffix %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,9 +1,11 @@
 package ls
 
 import (
+	"context"
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/filters"
@@ -11,8 +13,12 @@ import (
 	"github.com/rodaine/table"
 	"github.com/spf13/cobra"
 
+	"github.com/craftcms/nitro/pkg/certificate"
+	nitroclient "github.com/craftcms/nitro/pkg/client"
 	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/environment"
 	"github.com/craftcms/nitro/pkg/terminal"
+	"github.com/craftcms/nitro/protob"
 )
 
 const exampleText = `  # view information about your nitro environment
@@ -22,13 +28,29 @@ const exampleText = `  # view information about your nitro environment
   nitro ls --databases
 
   # show only sites
-  nitro ls --sites`
+  nitro ls --sites
+
+  # show the containers as JSON
+  nitro ls --json`
 
 var (
 	flagCustom, flagDatabases, flagProxy, flagServices, flagSites bool
 )
 
-func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outputer) *cobra.Command {
+// row is a container in the list, it is also the JSON output of the command.
+type row struct {
+	Name          string   `json:"name"`
+	Hostname      string   `json:"hostname,omitempty"`
+	Type          string   `json:"type"`
+	InternalPorts []string `json:"internal_ports"`
+	ExternalPorts []string `json:"external_ports"`
+	Status        string   `json:"status"`
+
+	// Proxied is set for sites when the proxy reports the sites it is routing
+	Proxied *bool `json:"proxied,omitempty"`
+}
+
+func NewCommand(home string, docker client.CommonAPIClient, nitrod protob.NitroClient, output terminal.Outputer) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "ls",
 		Short:   "Lists details for Nitro’s containers.",
@@ -37,7 +59,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 
 			// add filters to show only the environment and database containers
 			filter := filters.NewArgs()
-			filter.Add("label", containerlabels.Nitro)
+			filter.Add("label", containerlabels.Nitro+"="+environment.Label())
 
 			// get a list of all the databases
 			containers, err := docker.ContainerList(cmd.Context(), types.ContainerListOptions{All: true, Filters: filter})
@@ -50,15 +72,11 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 				return containers[i].Names[0] < containers[j].Names[0]
 			})
 
-			// define the table headers
-			tbl := table.New("Hostname", "Type", "Internal Ports", "External Ports", "Status").WithWriter(cmd.OutOrStdout()).WithPadding(2)
+			// ask the proxy which sites it is routing, older proxies do not support this
+			routes, _ := nitroclient.Sites(cmd.Context(), nitrod)
 
+			var rows []row
 			for _, c := range containers {
-				status := "running"
-				if c.State == "exited" {
-					status = "stopped"
-				}
-
 				// if we only want databases
 				if cmd.Flag("databases").Value.String() == "true" {
 					if c.Labels[containerlabels.Type] != "database" {
@@ -74,7 +92,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 				}
 
 				if cmd.Flag("services").Value.String() == "true" {
-					if c.Labels[containerlabels.Type] != "dynamodb" && c.Labels[containerlabels.Type] != "mailhog" && c.Labels[containerlabels.Type] != "redis" {
+					if c.Labels[containerlabels.Type] != "dynamodb" && c.Labels[containerlabels.Type] != "gotenberg" && c.Labels[containerlabels.Type] != "mailhog" && c.Labels[containerlabels.Type] != "redis" {
 						continue
 					}
 				}
@@ -91,47 +109,95 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 					}
 				}
 
-				// get the ports
-				var intPorts, extPorts []string
+				r := row{
+					Name:          strings.TrimLeft(c.Names[0], "/"),
+					Hostname:      c.Labels[containerlabels.Host],
+					Type:          containerlabels.Identify(c),
+					InternalPorts: []string{},
+					ExternalPorts: []string{},
+					Status:        status(c),
+				}
 
 				// get ports for the non-site containers
-				switch c.Labels[containerlabels.Host] == "" {
+				switch r.Hostname == "" {
 				case false:
-					intPorts = append(intPorts, "8080", "3000", "3001")
-					extPorts = append(extPorts, "(uses proxy ports)")
+					r.InternalPorts = append(r.InternalPorts, "8080", "3000", "3001")
+
+					// show sites the proxy is not sending requests to
+					if routes != nil {
+						proxied := routes[r.Hostname] != nil
+						r.Proxied = &proxied
+					}
 				default:
 					for _, p := range c.Ports {
 						// get the external ports and assign if not 0
 						e := p.PublicPort
 						if e != 0 {
-							extPorts = append(extPorts, fmt.Sprintf("%d", e))
+							r.ExternalPorts = append(r.ExternalPorts, fmt.Sprintf("%d", e))
 						}
 
 						// get the internal ports and assign if not 0
 						pr := p.PrivatePort
 						if e != 0 {
-							intPorts = append(intPorts, fmt.Sprintf("%d", pr))
+							r.InternalPorts = append(r.InternalPorts, fmt.Sprintf("%d", pr))
 						}
 					}
 				}
 
 				// sort the ports
-				sort.Slice(intPorts, func(i, j int) bool {
-					return intPorts[i] < intPorts[j]
-				})
+				sort.Strings(r.InternalPorts)
+				sort.Strings(r.ExternalPorts)
 
-				sort.Slice(extPorts, func(i, j int) bool {
-					return extPorts[i] < extPorts[j]
-				})
+				rows = append(rows, r)
+			}
 
-				internalPorts := strings.Join(intPorts, ",")
-				externalPorts := strings.Join(extPorts, ",")
+			if terminal.JSON {
+				if rows == nil {
+					rows = []row{}
+				}
 
-				tbl.AddRow(strings.TrimLeft(c.Names[0], "/"), containerlabels.Identify(c), internalPorts, externalPorts, status)
+				return output.JSON(rows)
+			}
+
+			// page the table when there are more containers than fit in the terminal
+			pager := terminal.NewPager(cmd.OutOrStdout())
+			defer pager.Close()
+
+			// define the table headers
+			tbl := table.New("Hostname", "Type", "Internal Ports", "External Ports", "Status").WithWriter(pager).WithPadding(2).WithWidthFunc(terminal.Width)
+
+			for _, r := range rows {
+				externalPorts := strings.Join(r.ExternalPorts, ",")
+				status := r.Status
+
+				if r.Hostname != "" {
+					externalPorts = "(uses proxy ports)"
+
+					if r.Proxied != nil && !*r.Proxied {
+						status += " (not proxied)"
+					}
+				}
+
+				// link the sites to their urls, pagers do not support hyperlinks
+				name := r.Name
+				if r.Hostname != "" && !pager.Paging() {
+					name = terminal.Hyperlink("https://"+r.Hostname, name)
+				}
+
+				tbl.AddRow(name, r.Type, strings.Join(r.InternalPorts, ","), externalPorts, status)
 			}
 
 			tbl.Print()
 
+			if err := pager.Close(); err != nil {
+				return err
+			}
+
+			// warn about site certificates that are about to expire
+			if cmd.Flag("sites").Value.String() == "true" || !filtered(cmd) {
+				warnExpiring(cmd.Context(), docker, nitrod, containers, output)
+			}
+
 			return nil
 		},
 	}
@@ -144,3 +210,69 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 
 	return cmd
 }
+
+// status returns the status to show for the container. Containers with a health check
+// show if the application inside the container is ready, not only if it is running.
+func status(c types.Container) string {
+	switch c.State {
+	case "exited":
+		return "stopped"
+	case "restarting":
+		// queue workers are restarted when they fail
+		return "restarting"
+	}
+
+	switch {
+	case strings.Contains(c.Status, "(health: starting)"):
+		return "starting"
+	case strings.Contains(c.Status, "(unhealthy)"):
+		return "unhealthy"
+	case strings.Contains(c.Status, "(healthy)"):
+		return "healthy"
+	}
+
+	return "running"
+}
+
+// filtered returns true when the containers are filtered by type.
+func filtered(cmd *cobra.Command) bool {
+	for _, f := range []string{"custom", "databases", "proxy", "services", "sites"} {
+		if cmd.Flag(f).Value.String() == "true" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// warnExpiring shows a warning for each site with a certificate that expires within
+// the renewal period. Certificates are only checked when the proxy is running.
+func warnExpiring(ctx context.Context, docker client.CommonAPIClient, nitrod protob.NitroClient, containers []types.Container, output terminal.Outputer) {
+	var proxyID string
+	var hostnames []string
+	for _, c := range containers {
+		if c.Labels[containerlabels.Type] == "proxy" && c.State == "running" {
+			proxyID = c.ID
+		}
+
+		if h := c.Labels[containerlabels.Host]; h != "" {
+			hostnames = append(hostnames, h)
+		}
+	}
+
+	if proxyID == "" || len(hostnames) == 0 {
+		return
+	}
+
+	expiries, err := nitroclient.Expiries(ctx, nitrod, hostnames)
+	if nitroclient.IsUnimplemented(err) {
+		expiries, err = certificate.Expiries(ctx, docker, proxyID, hostnames)
+	}
+	if err != nil {
+		return
+	}
+
+	for _, h := range certificate.ExpiringSoon(expiries, time.Now()) {
+		output.Info(fmt.Sprintf("The certificate for %s expires on %s, run `nitro apply` to renew it.", h, expiries[h].Format("January 2, 2006")))
+	}
+}
@@ -1,18 +1,26 @@
 package nitro
 
 import (
+	"errors"
+	"fmt"
 	"log"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
-	nitroclient "github.com/craftcms/nitro/client"
 	"github.com/craftcms/nitro/command/add"
+	"github.com/craftcms/nitro/command/adopt"
 	"github.com/craftcms/nitro/command/alias"
+	"github.com/craftcms/nitro/command/aliasshell"
 	"github.com/craftcms/nitro/command/apply"
 	"github.com/craftcms/nitro/command/blackfire"
 	"github.com/craftcms/nitro/command/bridge"
 	"github.com/craftcms/nitro/command/clean"
 	"github.com/craftcms/nitro/command/completion"
 	"github.com/craftcms/nitro/command/composer"
+	configcmd "github.com/craftcms/nitro/command/config"
 	"github.com/craftcms/nitro/command/container"
 	"github.com/craftcms/nitro/command/context"
 	"github.com/craftcms/nitro/command/craft"
@@ -20,74 +28,253 @@ import (
 	"github.com/craftcms/nitro/command/database"
 	"github.com/craftcms/nitro/command/destroy"
 	"github.com/craftcms/nitro/command/disable"
+	"github.com/craftcms/nitro/command/doctor"
 	"github.com/craftcms/nitro/command/edit"
 	"github.com/craftcms/nitro/command/enable"
+	"github.com/craftcms/nitro/command/env"
 	"github.com/craftcms/nitro/command/extensions"
 	"github.com/craftcms/nitro/command/hosts"
 	"github.com/craftcms/nitro/command/iniset"
 	"github.com/craftcms/nitro/command/initialize"
+	"github.com/craftcms/nitro/command/internal/environments"
+	"github.com/craftcms/nitro/command/jobs"
+	"github.com/craftcms/nitro/command/loadtest"
 	"github.com/craftcms/nitro/command/logs"
 	"github.com/craftcms/nitro/command/ls"
 	"github.com/craftcms/nitro/command/npm"
 	"github.com/craftcms/nitro/command/php"
 	"github.com/craftcms/nitro/command/portcheck"
+	"github.com/craftcms/nitro/command/proxy"
 	"github.com/craftcms/nitro/command/queue"
 	"github.com/craftcms/nitro/command/remove"
 	"github.com/craftcms/nitro/command/restart"
+	"github.com/craftcms/nitro/command/scan"
 	"github.com/craftcms/nitro/command/selfupdate"
+	"github.com/craftcms/nitro/command/service"
 	"github.com/craftcms/nitro/command/share"
 	"github.com/craftcms/nitro/command/ssh"
 	"github.com/craftcms/nitro/command/start"
 	"github.com/craftcms/nitro/command/stop"
 	"github.com/craftcms/nitro/command/trust"
+	"github.com/craftcms/nitro/command/uninstall"
 	"github.com/craftcms/nitro/command/update"
 	"github.com/craftcms/nitro/command/validate"
 	"github.com/craftcms/nitro/command/version"
+	"github.com/craftcms/nitro/command/workspace"
+	"github.com/craftcms/nitro/command/xdebug"
 	"github.com/craftcms/nitro/command/xoff"
 	"github.com/craftcms/nitro/command/xon"
+	"github.com/craftcms/nitro/command/xtunnel"
+	nitroclient "github.com/craftcms/nitro/pkg/client"
+	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/dockercache"
+	"github.com/craftcms/nitro/pkg/dockercontext"
 	"github.com/craftcms/nitro/pkg/downloader"
+	"github.com/craftcms/nitro/pkg/environment"
+	"github.com/craftcms/nitro/pkg/offline"
+	"github.com/craftcms/nitro/pkg/ownership"
+	"github.com/craftcms/nitro/pkg/releases"
 	"github.com/craftcms/nitro/pkg/terminal"
+	"github.com/craftcms/nitro/pkg/wsl"
+	"github.com/craftcms/nitro/protob"
 	"github.com/docker/docker/client"
 	"github.com/mitchellh/go-homedir"
+	"github.com/moby/term"
 	"github.com/spf13/cobra"
 )
 
+var (
+	// debug is set by the --debug flag or the NITRO_DEBUG environment variable
+	debug bool
+
+	// started is the time the command started executing
+	started time.Time
+
+	// cache is the docker client that caches list requests for the command
+	cache *dockercache.Client
+
+	// executed is the command that ran, it is used to skip the update notice
+	executed *cobra.Command
+)
+
+// updateCheckTimeout is how long to wait for the releases API when checking for a new version
+// after a command.
+const updateCheckTimeout = 2 * time.Second
+
 var rootCommand = &cobra.Command{
 	Use:   "nitro",
 	Short: "Speedy local dev environment for Craft CMS.",
 	Long: `Nitro is a console-based tool that manages Docker for local PHP development.
 
 Version: ` + version.Version,
-	RunE:         rootMain,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		started = time.Now()
+		executed = cmd
+
+		// the env var is honored when the flag is not set
+		if !debug {
+			debug, _ = strconv.ParseBool(os.Getenv("NITRO_DEBUG"))
+		}
+
+		if !offline.Enabled {
+			offline.Enabled, _ = strconv.ParseBool(os.Getenv("NITRO_OFFLINE"))
+		}
+	},
 	SilenceUsage: true,
 	Version:      version.Version,
 }
 
-func rootMain(command *cobra.Command, _ []string) error {
-	return command.Help()
+// rootMain returns the func that shows the help for the command. When nitro is run for the
+// first time without a config file and from a terminal, it starts the guided setup instead.
+func rootMain(home string, docker client.CommonAPIClient, nitrod protob.NitroClient, output terminal.Outputer) func(*cobra.Command, []string) error {
+	return func(command *cobra.Command, args []string) error {
+		if _, err := config.Load(home); !errors.Is(err, config.ErrNoConfigFile) || !term.IsTerminal(os.Stdin.Fd()) {
+			return command.Help()
+		}
+
+		// is the docker api alive?
+		if _, err := docker.Ping(command.Context()); err != nil {
+			return fmt.Errorf("Couldn’t connect to Docker; please make sure Docker is running.")
+		}
+
+		return initialize.Run(command.Context(), home, docker, nitrod, command.InOrStdin(), output)
+	}
+}
+
+// Debug returns true if the --debug flag or the NITRO_DEBUG
+// environment variable was set for the current command.
+func Debug() bool {
+	return debug
+}
+
+// ShowExecutionTime is called once the command has completed and will print
+// the total time it took to run the command when debugging is enabled.
+func ShowExecutionTime() {
+	if !debug || started.IsZero() {
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "completed in %s\n", time.Since(started).Round(time.Millisecond))
+
+	if cache != nil {
+		hits, misses := cache.Stats()
+		fmt.Fprintf(os.Stderr, "docker list requests: %d sent, %d cached\n", misses, hits)
+	}
+}
+
+// ShowUpdateNotice is called once the command has completed and prints a notice when there
+// is a newer version of nitro. The latest version is cached for a day in the nitro directory,
+// so the releases API is queried at most once a day. The notice is not shown for JSON output,
+// develop builds, offline mode, when stderr is not a terminal, or when the
+// NITRO_NO_UPDATE_CHECK environment variable is set.
+func ShowUpdateNotice() {
+	if executed == nil || terminal.JSON || offline.Enabled || version.Version == "develop" || !term.IsTerminal(os.Stderr.Fd()) {
+		return
+	}
+
+	if _, ok := os.LookupEnv("NITRO_NO_UPDATE_CHECK"); ok {
+		return
+	}
+
+	// the commands that check for updates themselves
+	switch executed.Name() {
+	case "version", "self-update", "completion", cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd:
+		return
+	}
+
+	home, err := homeDir()
+	if err != nil {
+		return
+	}
+
+	// the version is only cached once nitro is setup
+	dir := filepath.Join(home, config.DirectoryName)
+	if _, err := os.Stat(dir); err != nil {
+		return
+	}
+
+	latest, err := releases.Latest(releases.NewFinderWithTimeout(updateCheckTimeout), dir, false, time.Now())
+	if err != nil || !releases.Newer(latest, version.Version) {
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "Nitro %s is available (current version is %s), run `nitro self-update` to update.\n", latest, version.Version)
+}
+
+// homeDir returns the home directory with the nitro directory. The NITRO_HOME environment
+// variable is used when it is set so nitro in WSL and on windows can share the config, the
+// path can be written for either side.
+func homeDir() (string, error) {
+	if home := os.Getenv(environment.HomeEnvVar); home != "" {
+		return wsl.Path(home), nil
+	}
+
+	return homedir.Dir()
 }
 
 func NewCommand() *cobra.Command {
 	// get the users home directory
-	home, err := homedir.Dir()
+	home, err := homeDir()
 	if err != nil {
 		log.Fatal(err)
 	}
 
+	// the docker client is created before the flags are parsed, so the context is read from the arguments
+	endpoint, err := dockercontext.Resolve(home, flagValue(os.Args[1:], "--docker-context"))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	opts, err := endpoint.Opts()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	dockercontext.Current = endpoint
+
 	// create the docker client
-	docker, err := client.NewClientWithOpts(client.FromEnv)
+	dockerClient, err := client.NewClientWithOpts(append([]client.Opt{client.FromEnv}, opts...)...)
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	// get the port for the nitrod API
-	apiPort := "5000"
-	if os.Getenv("NITRO_API_PORT") != "" {
-		apiPort = os.Getenv("NITRO_API_PORT")
+	// cache list requests so commands do not repeat docker API calls
+	cache = dockercache.New(dockerClient)
+
+	// namespace the resources by owner when the docker daemon is shared with other developers
+	if _, err := environment.ResolveOwner(); err != nil {
+		log.Fatal(err)
 	}
 
+	// use local images when offline or the registry cannot be reached, and keep the
+	// resources of other developers on a shared daemon separate
+	docker := ownership.New(offline.New(cache))
+
+	// the environment is also read from the arguments, the client for the API uses its port
+	name := flagValue(os.Args[1:], "--environment")
+
+	// a config file outside of the nitro directory is its own environment unless one is selected
+	if file := flagValue(os.Args[1:], "--config"); file != "" {
+		if environment.File, err = filepath.Abs(wsl.Path(file)); err != nil {
+			log.Fatal(err)
+		}
+
+		if name == "" && os.Getenv(environment.EnvVar) == "" {
+			name = environment.NameFromFile(file)
+		}
+	}
+
+	if _, err := environment.Resolve(filepath.Join(home, config.DirectoryName), name); err != nil {
+		log.Fatal(err)
+	}
+
+	environment.Ports = config.Ports(home)
+
+	// get the port for the nitrod API
+	apiPort := environment.Port("NITRO_API_PORT", environment.Ports.API)
+
 	// create the nitrod gRPC API
-	nitrod, err := nitroclient.NewClient("127.0.0.1", apiPort)
+	nitrod, err := nitroclient.NewClient(endpoint.Address(), apiPort)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -101,49 +288,109 @@ func NewCommand() *cobra.Command {
 	// register all of the commands
 	commands := []*cobra.Command{
 		add.NewCommand(home, docker, term),
+		adopt.NewCommand(home, docker, term),
 		alias.NewCommand(home, docker, term),
+		aliasshell.NewCommand(home, term),
 		apply.NewCommand(home, docker, nitrod, term),
 		blackfire.NewCommand(home, docker, term),
 		bridge.NewCommand(home, docker, term),
 		clean.NewCommand(home, docker, term),
 		completion.NewCommand(),
-		composer.NewCommand(docker, term),
+		composer.NewCommand(home, docker, term),
+		configcmd.NewCommand(home, term),
 		container.NewCommand(home, docker, term),
 		context.NewCommand(home, docker, term),
 		craft.NewCommand(home, docker, term),
 		create.NewCommand(home, docker, downloader, term),
 		database.NewCommand(home, docker, nitrod, term),
 		destroy.NewCommand(home, docker, term),
-		disable.NewCommand(home, docker, term),
-		enable.NewCommand(home, docker, term),
+		disable.NewCommand(home, docker, nitrod, term),
+		doctor.NewCommand(home, docker, term),
+		enable.NewCommand(home, docker, nitrod, term),
 		edit.NewCommand(home, docker, term),
+		env.NewCommand(home, docker, term),
 		extensions.NewCommand(home, docker, term),
 		hosts.NewCommand(home, term),
 		iniset.NewCommand(home, docker, term),
-		initialize.NewCommand(home, docker, term),
+		initialize.NewCommand(home, docker, nitrod, term),
+		jobs.NewCommand(home, docker, term),
+		loadtest.NewCommand(home, term),
 		logs.NewCommand(home, docker, term),
-		ls.NewCommand(home, docker, term),
-		npm.NewCommand(docker, term),
+		ls.NewCommand(home, docker, nitrod, term),
+		npm.NewCommand(home, docker, nitrod, term),
 		php.NewCommand(home, docker, term),
 		portcheck.NewCommand(term),
+		proxy.NewCommand(nitrod, term),
 		queue.NewCommand(home, docker, term),
-		remove.NewCommand(home, docker, term),
+		remove.NewCommand(home, docker, nitrod, term),
 		restart.NewCommand(home, docker, term),
+		scan.NewCommand(home, docker, term),
 		selfupdate.NewCommand(term),
+		service.NewCommand(home, docker, term),
 		share.NewCommand(home, docker, term),
 		ssh.NewCommand(home, docker, term),
 		start.NewCommand(home, docker, term),
 		stop.NewCommand(home, docker, term),
-		trust.NewCommand(home, docker, term),
+		trust.NewCommand(home, docker, nitrod, term),
+		uninstall.NewCommand(home, docker, term),
 		update.NewCommand(home, docker, term),
 		validate.NewCommand(home, docker, term),
 		version.NewCommand(home, docker, nitrod, term),
+		workspace.NewCommand(home, docker, term),
+		xdebug.NewCommand(home, docker, term),
 		xon.NewCommand(home, docker, term),
 		xoff.NewCommand(home, docker, term),
+		xtunnel.NewCommand(home, docker, term),
 	}
 
 	// add the commands
 	rootCommand.AddCommand(commands...)
 
+	// start the guided setup when nitro runs for the first time
+	rootCommand.RunE = rootMain(home, docker, nitrod, term)
+
+	// add the global debug flag, which can also be set with NITRO_DEBUG=1
+	rootCommand.PersistentFlags().BoolVar(&debug, "debug", false, "show debug information such as the execution time")
+
+	// add the global offline flag, which can also be set with NITRO_OFFLINE=1
+	rootCommand.PersistentFlags().BoolVar(&offline.Enabled, "offline", false, "use local images instead of pulling them")
+
+	// add the global flag to run start, stop, and restart for every environment
+	rootCommand.PersistentFlags().BoolVar(&environments.All, "all-envs", false, "run start, stop, and restart for every environment in ~/.nitro")
+
+	// add the global environment flag, NITRO_ENVIRONMENT and nitro env use are also honored
+	rootCommand.PersistentFlags().String("environment", "", "the name of the environment to use")
+
+	// add the global config flag, the file is used instead of the config of the environment
+	rootCommand.PersistentFlags().String("config", "", "the path of a config file to use instead of the config in ~/.nitro")
+
+	// add the global docker context flag, DOCKER_HOST and DOCKER_CONTEXT are also honored
+	rootCommand.PersistentFlags().String("docker-context", "", "the name of the docker context to use")
+
+	// add the global output flags, colors can also be disabled with NO_COLOR
+	rootCommand.PersistentFlags().BoolVar(&terminal.NoColor, "no-color", false, "disable colors in the output")
+	rootCommand.PersistentFlags().BoolVar(&terminal.NoPager, "no-pager", false, "do not page long output")
+	rootCommand.PersistentFlags().BoolVar(&terminal.JSON, "json", false, "show the result as JSON for commands that support it")
+
 	return rootCommand
 }
+
+// flagValue returns the value of the flag from the arguments, it supports both the
+// --flag=value and --flag value forms.
+func flagValue(args []string, name string) string {
+	for i, a := range args {
+		if a == "--" {
+			break
+		}
+
+		if strings.HasPrefix(a, name+"=") {
+			return strings.TrimPrefix(a, name+"=")
+		}
+
+		if a == name && i+1 < len(args) {
+			return args[i+1]
+		}
+	}
+
+	return ""
+}
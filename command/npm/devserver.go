@@ -0,0 +1,51 @@
+package npm
+
+import (
+	"io"
+	"regexp"
+	"strconv"
+	"sync"
+)
+
+var (
+	// devScripts are the scripts that commonly start a dev server (e.g. vite or webpack)
+	devScripts = map[string]bool{"dev": true, "serve": true, "start": true, "hot": true, "watch": true}
+
+	// portPattern matches the local url a dev server shows when it is ready
+	portPattern = regexp.MustCompile(`(?:localhost|127\.0\.0\.1|0\.0\.0\.0|\[::\]):(\d{2,5})`)
+
+	// ansiPattern matches the color codes dev servers use in their output
+	ansiPattern = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+)
+
+// isDevServer returns true if the args run a script that starts a dev server.
+func isDevServer(tool string, args []string) bool {
+	if len(args) > 1 && args[0] == "run" {
+		return devScripts[args[1]]
+	}
+
+	// npm start and yarn can run scripts without run (e.g. yarn dev)
+	if len(args) > 0 && (args[0] == "start" || (tool == "yarn" && devScripts[args[0]])) {
+		return true
+	}
+
+	return false
+}
+
+// portWriter writes the output of a dev server and calls found with the port
+// the first time the dev server shows its url (e.g. http://localhost:5173).
+type portWriter struct {
+	w     io.Writer
+	found func(port int)
+	once  sync.Once
+}
+
+func (p *portWriter) Write(b []byte) (int, error) {
+	if m := portPattern.FindSubmatch(ansiPattern.ReplaceAll(b, nil)); m != nil {
+		if port, err := strconv.Atoi(string(m[1])); err == nil {
+			p.once.Do(func() { p.found(port) })
+		}
+	}
+
+	return p.w.Write(b)
+}
@@ -0,0 +1,54 @@
+package npm
+
+import (
+	"bytes"
+	"testing"
+)
+
+func Test_isDevServer(t *testing.T) {
+	tests := []struct {
+		name string
+		tool string
+		args []string
+		want bool
+	}{
+		{name: "npm run dev is a dev server", tool: "npm", args: []string{"run", "dev"}, want: true},
+		{name: "npm start is a dev server", tool: "npm", args: []string{"start"}, want: true},
+		{name: "yarn dev is a dev server", tool: "yarn", args: []string{"dev"}, want: true},
+		{name: "npm run build is not a dev server", tool: "npm", args: []string{"run", "build"}, want: false},
+		{name: "npm install is not a dev server", tool: "npm", args: []string{"install"}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isDevServer(tt.tool, tt.args); got != tt.want {
+				t.Errorf("isDevServer() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_portWriter(t *testing.T) {
+	var ports []int
+	buf := new(bytes.Buffer)
+	w := &portWriter{w: buf, found: func(port int) { ports = append(ports, port) }}
+
+	// vite shows the port in bold
+	output := []string{
+		"  VITE v4.0.0  ready in 300 ms\n",
+		"  ➜  Local:   http://localhost:\x1b[1m5173\x1b[22m/\n",
+		"  ➜  Network: http://0.0.0.0:5173/\n",
+	}
+	for _, o := range output {
+		if _, err := w.Write([]byte(o)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(ports) != 1 || ports[0] != 5173 {
+		t.Errorf("expected the port to be found once, got %v", ports)
+	}
+
+	if buf.Len() == 0 {
+		t.Errorf("expected the output to be written")
+	}
+}
@@ -5,8 +5,11 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/docker/docker/api/types/network"
 	volumetypes "github.com/docker/docker/api/types/volume"
@@ -19,10 +22,16 @@ import (
 	"github.com/docker/docker/pkg/stdcopy"
 	"github.com/spf13/cobra"
 
+	"github.com/craftcms/nitro/command/internal/ephemeral"
+	"github.com/craftcms/nitro/pkg/config"
 	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/environment"
 	"github.com/craftcms/nitro/pkg/pathexists"
+	"github.com/craftcms/nitro/pkg/proxyroutes"
 	"github.com/craftcms/nitro/pkg/terminal"
 	"github.com/craftcms/nitro/pkg/volumename"
+	"github.com/craftcms/nitro/pkg/workspace"
+	"github.com/craftcms/nitro/protob"
 )
 
 var (
@@ -37,13 +46,23 @@ const exampleText = `  # run npm install in a current directory
   nitro npm update
 
   # run a script
-  nitro npm run dev`
+  nitro npm run dev
 
-// NewCommand is the command used to run npm commands in a container.
-func NewCommand(docker client.CommonAPIClient, output terminal.Outputer) *cobra.Command {
+  # use a specific version of node, the flags for nitro are set before the command
+  nitro npm --node-version=16 install
+
+  # run yarn instead of npm
+  nitro yarn install`
+
+// NewCommand is the command used to run npm commands in a container. The command can also be
+// called as yarn to run yarn commands. Each path has a volume for the npm and yarn caches so
+// packages are not downloaded again on every run. When a dev server is started for a site, the
+// proxy routes dev.<hostname> to the dev server until the command exits.
+func NewCommand(home string, docker client.CommonAPIClient, nitrod protob.NitroClient, output terminal.Outputer) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "npm",
-		Short:   "Runs an npm command.",
+		Short:   "Runs an npm or yarn command.",
+		Aliases: []string{"yarn"},
 		Example: exampleText,
 		Args:    cobra.MinimumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -54,7 +73,13 @@ func NewCommand(docker client.CommonAPIClient, output terminal.Outputer) *cobra.
 				// context just in case.
 				ctx = context.Background()
 			}
-			version := cmd.Flag("version").Value.String()
+			version := cmd.Flag("node-version").Value.String()
+
+			// use yarn if the command was called as yarn
+			tool := "npm"
+			if cmd.CalledAs() == "yarn" {
+				tool = "yarn"
+			}
 
 			var path string
 			wd, err := os.Getwd()
@@ -86,7 +111,7 @@ func NewCommand(docker client.CommonAPIClient, output terminal.Outputer) *cobra.
 
 			// find the network
 			networkFilter := filters.NewArgs()
-			networkFilter.Add("name", "nitro-network")
+			networkFilter.Add("name", environment.Network())
 
 			// check if the network needs to be created
 			networks, err := docker.NetworkList(ctx, types.NetworkListOptions{Filters: networkFilter})
@@ -96,7 +121,7 @@ func NewCommand(docker client.CommonAPIClient, output terminal.Outputer) *cobra.
 
 			var networkID string
 			for _, n := range networks {
-				if n.Name == "nitro-network" || strings.TrimLeft(n.Name, "/") == "nitro-network" {
+				if n.Name == environment.Network() || strings.TrimLeft(n.Name, "/") == environment.Network() {
 					networkID = n.ID
 				}
 			}
@@ -142,9 +167,8 @@ func NewCommand(docker client.CommonAPIClient, output terminal.Outputer) *cobra.
 				return err
 			}
 
-			// set the volume name
-			// TODO(jasonmccallister) remove this hardcoded version
-			volumeName := volumename.FromPath(strings.Join([]string{path, "14"}, string(os.PathSeparator)))
+			// the cache volume is shared by every node version for the path
+			volumeName := volumename.FromPath(path)
 
 			var pathVolume types.Volume
 			switch len(volumes.Volumes) {
@@ -167,30 +191,60 @@ func NewCommand(docker client.CommonAPIClient, output terminal.Outputer) *cobra.
 				pathVolume = volume
 			}
 
-			commands := append([]string{"npm"}, args...)
+			commands := append([]string{tool}, args...)
+
+			envs := []string{
+				// keep the package caches in the volume
+				"npm_config_cache=/root/.npm",
+				"YARN_CACHE_FOLDER=/root/.cache/yarn",
+			}
+
+			// if this is a dev server for a site, name the container so the proxy can reach it
+			var containerName string
+			var site *config.Site
+			var cfg *config.Config
+			if isDevServer(tool, args) && networkID != "" {
+				if cfg, err = config.Load(home); err == nil {
+					site = workspace.Site(home, path, cfg.Sites)
+				}
+
+				if site != nil {
+					containerName = "dev." + site.Hostname
+
+					// remove a container left behind by a previous dev server
+					_ = docker.ContainerRemove(ctx, containerName, types.ContainerRemoveOptions{Force: true})
+
+					// dev servers need to listen on all interfaces for the proxy
+					envs = append(envs, "HOST=0.0.0.0")
+				}
+			}
 
 			networkConfig := &network.NetworkingConfig{}
 			if networkID != "" {
 				networkConfig = &network.NetworkingConfig{
 					EndpointsConfig: map[string]*network.EndpointSettings{
-						"nitro-network": {
+						environment.Network(): {
 							NetworkID: networkID,
 						},
 					},
 				}
 			}
 
+			// remove the containers left behind when a previous command was interrupted
+			_, _ = ephemeral.Collect(ctx, docker, time.Now())
+
 			// create the container
 			resp, err := docker.ContainerCreate(ctx,
 				&container.Config{
 					Image: image,
 					Cmd:   commands,
 					Tty:   false,
-					Labels: map[string]string{
-						containerlabels.Nitro: "true",
+					Env:   envs,
+					Labels: ephemeral.Label(map[string]string{
+						containerlabels.Nitro: environment.Label(),
 						containerlabels.Type:  "npm",
 						containerlabels.Path:  path,
-					},
+					}, time.Now()),
 					WorkingDir: "/home/node/app",
 				},
 
@@ -210,12 +264,12 @@ func NewCommand(docker client.CommonAPIClient, output terminal.Outputer) *cobra.
 				},
 				networkConfig,
 				nil,
-				"")
+				containerName)
 			if err != nil {
 				return fmt.Errorf("unable to create container\n%w", err)
 			}
 
-			output.Info("Running npm", action)
+			output.Info("Running", tool, action)
 
 			// attach to the container
 			stream, err := docker.ContainerAttach(ctx, resp.ID, types.ContainerAttachOptions{
@@ -234,12 +288,51 @@ func NewCommand(docker client.CommonAPIClient, output terminal.Outputer) *cobra.
 				return fmt.Errorf("unable to start the container, %w", err)
 			}
 
+			stdout := cmd.OutOrStdout()
+			if site != nil {
+				// stop the dev server when the command is interrupted
+				sig := make(chan os.Signal, 1)
+				signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+				defer signal.Stop(sig)
+
+				go func() {
+					if _, ok := <-sig; ok {
+						_ = docker.ContainerStop(context.Background(), resp.ID, nil)
+					}
+				}()
+
+				// route requests to the dev server once it shows the port
+				var routed bool
+				stdout = &portWriter{w: stdout, found: func(port int) {
+					sites := proxyroutes.Sites(cfg)
+					sites[containerName] = &protob.Site{Hostname: containerName, Port: int32(port)}
+
+					if err := proxyroutes.Update(ctx, nitrod, sites, proxyroutes.Dashboard(cfg)); err != nil {
+						output.Info("unable to add the dev server to the proxy,", err.Error())
+						return
+					}
+
+					routed = true
+
+					output.Info(fmt.Sprintf("Dev server available at https://%s (add %s to your hosts file if it does not resolve)", containerName, containerName))
+				}}
+
+				// remove the route when the dev server exits
+				defer func() {
+					if routed {
+						if err := proxyroutes.Update(context.Background(), nitrod, proxyroutes.Sites(cfg), proxyroutes.Dashboard(cfg)); err != nil {
+							output.Info("unable to remove the dev server from the proxy,", err.Error())
+						}
+					}
+				}()
+			}
+
 			// copy the stream to stdout
-			if _, err := stdcopy.StdCopy(cmd.OutOrStdout(), cmd.ErrOrStderr(), stream.Reader); err != nil {
+			if _, err := stdcopy.StdCopy(stdout, cmd.ErrOrStderr(), stream.Reader); err != nil {
 				return fmt.Errorf("unable to copy the output of the container logs, %w", err)
 			}
 
-			output.Info("npm", action, "complete 🤘")
+			output.Info(tool, action, "complete 🤘")
 
 			if err := docker.ContainerRemove(ctx, resp.ID, types.ContainerRemoveOptions{}); err != nil {
 				return err
@@ -249,8 +342,9 @@ func NewCommand(docker client.CommonAPIClient, output terminal.Outputer) *cobra.
 		},
 	}
 
-	// set flags for the command
-	cmd.Flags().String("version", "14", "which node version to use")
+	// the flags after the npm or yarn command are passed to the command
+	cmd.Flags().SetInterspersed(false)
+	cmd.Flags().String("node-version", "14", "which node version to use")
 
 	return cmd
 }
@@ -1,5 +1,67 @@
 package npm
 
+import (
+	"reflect"
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+func TestNewCommand_Flags(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		wantVersion string
+		wantArgs    []string
+		wantErr     error
+	}{
+		{
+			name:        "the default node version is used",
+			args:        []string{"install"},
+			wantVersion: "14",
+			wantArgs:    []string{"install"},
+		},
+		{
+			name:        "the node version is set before the command",
+			args:        []string{"--node-version", "16", "run", "dev"},
+			wantVersion: "16",
+			wantArgs:    []string{"run", "dev"},
+		},
+		{
+			name:        "flags after the command are passed to npm",
+			args:        []string{"--node-version=16", "run", "build", "--version", "--help"},
+			wantVersion: "16",
+			wantArgs:    []string{"run", "build", "--version", "--help"},
+		},
+		{
+			name:    "help is shown before the command",
+			args:    []string{"--help"},
+			wantErr: pflag.ErrHelp,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := NewCommand("", nil, nil, nil)
+
+			if err := cmd.ParseFlags(tt.args); err != tt.wantErr {
+				t.Fatalf("ParseFlags() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr != nil {
+				return
+			}
+
+			if version := cmd.Flag("node-version").Value.String(); version != tt.wantVersion {
+				t.Errorf("expected the node version %q, got %q", tt.wantVersion, version)
+			}
+
+			if args := cmd.Flags().Args(); !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("expected the args %v, got %v", tt.wantArgs, args)
+			}
+		})
+	}
+}
+
 // import (
 // 	"bufio"
 // 	"context"
@@ -12,6 +12,8 @@ import (
 
 	"github.com/craftcms/nitro/pkg/config"
 	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/environment"
+	"github.com/craftcms/nitro/pkg/prompt"
 	"github.com/craftcms/nitro/pkg/terminal"
 )
 
@@ -19,10 +21,15 @@ const exampleText = `  # run php version
   nitro php -v
 
   # view php info
-  nitro php -i`
+  nitro php -i
+
+  # run a script in a disposable container using a specific version of php
+  nitro php --php-version=8.1 script.php`
 
 // NewCommand returns the php command which allows users to pass php specific commands to a sites
 // container. Its context aware and will prompt the user for the site if its not in a directory.
+// When the php version is provided, or the directory is not a site, the commands are run in a
+// disposable container so users without php installed can run one-off scripts.
 func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outputer) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:                "php",
@@ -30,12 +37,22 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 		Example:            exampleText,
 		DisableFlagParsing: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			version, args, err := versionFromArgs(args)
+			if err != nil {
+				return err
+			}
+
 			// get the current working directory
 			wd, err := os.Getwd()
 			if err != nil {
 				return err
 			}
 
+			// run the php version in a disposable container
+			if version != "" {
+				return run(cmd, docker, output, wd, version, args)
+			}
+
 			// load the config
 			cfg, err := config.Load(home)
 			if err != nil {
@@ -44,7 +61,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 
 			// create a filter for the environment
 			filter := filters.NewArgs()
-			filter.Add("label", containerlabels.Nitro)
+			filter.Add("label", containerlabels.Nitro+"="+environment.Label())
 
 			// get a context aware list of sites
 			sites := cfg.ListOfSitesByDirectory(home, wd)
@@ -58,17 +75,8 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 			var site config.Site
 			switch len(sites) {
 			case 0:
-				// prompt for the site
-				selected, err := output.Select(cmd.InOrStdin(), "Select a site: ", options)
-				if err != nil {
-					return err
-				}
-
-				// set the site we selected
-				site = sites[selected]
-
-				// add the label to get the site
-				filter.Add("label", containerlabels.Host+"="+sites[selected].Hostname)
+				// the directory is not a site, so use a disposable container
+				return run(cmd, docker, output, wd, DefaultVersion, args)
 			case 1:
 				output.Info("connecting to", sites[0].Hostname)
 
@@ -79,7 +87,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 				filter.Add("label", containerlabels.Host+"="+sites[0].Hostname)
 			default:
 				// prompt for the site to ssh into
-				selected, err := output.Select(cmd.InOrStdin(), "Select a site: ", options)
+				selected, err := prompt.Select(cmd.InOrStdin(), "Select a site: ", options, output)
 				if err != nil {
 					return err
 				}
@@ -152,5 +160,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 		},
 	}
 
+	cmd.Flags().String("php-version", DefaultVersion, "which php version to use in a disposable container")
+
 	return cmd
 }
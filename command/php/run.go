@@ -0,0 +1,183 @@
+package php
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/user"
+	"runtime"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/spf13/cobra"
+
+	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/environment"
+	"github.com/craftcms/nitro/pkg/terminal"
+)
+
+// DefaultVersion is the version of php used for disposable containers when no version
+// is provided.
+const DefaultVersion = "7.4"
+
+// Versions are the versions of php that can be used for a disposable container.
+var Versions = []string{"8.1", "8.0", "7.4"}
+
+// run runs php with the args in a disposable container that has the path mounted
+// as the working directory. The container is removed once php exits.
+func run(cmd *cobra.Command, docker client.CommonAPIClient, output terminal.Outputer, path, version string, args []string) error {
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	// uses the same image as composer, so the image is often already pulled
+	image := fmt.Sprintf("docker.io/craftcms/%s:%s-dev", "cli", version)
+
+	// filter for the image ref
+	filter := filters.NewArgs()
+	filter.Add("reference", image)
+
+	// look for the image
+	images, err := docker.ImageList(ctx, types.ImageListOptions{Filters: filter})
+	if err != nil {
+		return fmt.Errorf("unable to get a list of images, %w", err)
+	}
+
+	// if we don't have the image, pull it
+	if len(images) == 0 {
+		output.Pending("pulling", image)
+
+		rdr, err := docker.ImagePull(ctx, image, types.ImagePullOptions{All: false})
+		if err != nil {
+			output.Warning()
+			return fmt.Errorf("unable to pull the docker image, %w", err)
+		}
+
+		buf := &bytes.Buffer{}
+		if _, err := buf.ReadFrom(rdr); err != nil {
+			output.Warning()
+			return fmt.Errorf("unable to read the output from pulling the image, %w", err)
+		}
+
+		output.Done()
+	}
+
+	containerUser := "www-data"
+	if runtime.GOOS == "linux" {
+		u, err := user.Current()
+		if err != nil {
+			return err
+		}
+		containerUser = fmt.Sprintf("%s:%s", u.Uid, u.Gid)
+	}
+
+	if len(args) == 0 {
+		args = []string{"-v"}
+	}
+
+	// create the container
+	c, err := docker.ContainerCreate(
+		ctx,
+		&container.Config{
+			Image:      image,
+			Cmd:        args,
+			Tty:        false,
+			Entrypoint: []string{"php"},
+			WorkingDir: "/app",
+			User:       containerUser,
+			Labels: map[string]string{
+				containerlabels.Nitro: environment.Label(),
+				containerlabels.Type:  "php",
+				containerlabels.Path:  path,
+			},
+		},
+		&container.HostConfig{
+			Binds: []string{fmt.Sprintf("%s:/app:rw", path)},
+		},
+		nil,
+		nil,
+		"",
+	)
+	if err != nil {
+		return fmt.Errorf("unable to create the php container\n%w", err)
+	}
+
+	// remove the container once php exits
+	defer docker.ContainerRemove(context.Background(), c.ID, types.ContainerRemoveOptions{Force: true})
+
+	// attach to the container
+	stream, err := docker.ContainerAttach(ctx, c.ID, types.ContainerAttachOptions{
+		Stream: true,
+		Stdout: true,
+		Stderr: true,
+		Logs:   true,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to attach to container, %w", err)
+	}
+	defer stream.Close()
+
+	// run the container
+	if err := docker.ContainerStart(ctx, c.ID, types.ContainerStartOptions{}); err != nil {
+		return fmt.Errorf("unable to start the container, %w", err)
+	}
+
+	// show the output to stdout and stderr
+	if _, err := stdcopy.StdCopy(cmd.OutOrStdout(), cmd.ErrOrStderr(), stream.Reader); err != nil {
+		return fmt.Errorf("unable to copy the output of the container logs, %w", err)
+	}
+
+	// return the exit code of php as an error
+	waitC, errC := docker.ContainerWait(ctx, c.ID, container.WaitConditionNotRunning)
+	select {
+	case status := <-waitC:
+		if status.StatusCode != 0 {
+			return fmt.Errorf("php exited with code %d", status.StatusCode)
+		}
+	case err := <-errC:
+		return fmt.Errorf("unable to wait for the php container, %w", err)
+	}
+
+	return nil
+}
+
+// versionFromArgs removes the --php-version flag from the args and returns the version.
+// The version is empty when the flag is not set.
+func versionFromArgs(args []string) (string, []string, error) {
+	var version string
+	var newArgs []string
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+
+		switch {
+		case strings.HasPrefix(a, "--php-version="):
+			version = strings.TrimPrefix(a, "--php-version=")
+		case a == "--php-version":
+			if i+1 >= len(args) {
+				return "", nil, fmt.Errorf("the --php-version flag requires a version")
+			}
+
+			version = args[i+1]
+			i++
+		default:
+			newArgs = append(newArgs, a)
+		}
+	}
+
+	if version == "" {
+		return "", newArgs, nil
+	}
+
+	for _, v := range Versions {
+		if v == version {
+			return version, newArgs, nil
+		}
+	}
+
+	return "", nil, fmt.Errorf("the PHP version %q is not valid, use one of %s", version, strings.Join(Versions, ", "))
+}
@@ -0,0 +1,60 @@
+package php
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestVersionFromArgs(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		wantVersion string
+		wantArgs    []string
+		wantErr     bool
+	}{
+		{
+			name:     "no version returns the args",
+			args:     []string{"script.php", "--verbose"},
+			wantArgs: []string{"script.php", "--verbose"},
+		},
+		{
+			name:        "version with an equals sign is removed",
+			args:        []string{"--php-version=8.1", "script.php"},
+			wantVersion: "8.1",
+			wantArgs:    []string{"script.php"},
+		},
+		{
+			name:        "version with a space is removed with its value",
+			args:        []string{"--php-version", "8.0", "-r", "echo 1;"},
+			wantVersion: "8.0",
+			wantArgs:    []string{"-r", "echo 1;"},
+		},
+		{
+			name:    "unknown versions return an error",
+			args:    []string{"--php-version=5.6", "script.php"},
+			wantErr: true,
+		},
+		{
+			name:    "missing versions return an error",
+			args:    []string{"script.php", "--php-version"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			version, args, err := versionFromArgs(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("versionFromArgs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if version != tt.wantVersion {
+				t.Errorf("versionFromArgs() version = %q, want %q", version, tt.wantVersion)
+			}
+
+			if !tt.wantErr && !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("versionFromArgs() args = %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+}
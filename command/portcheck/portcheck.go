@@ -14,7 +14,17 @@ const exampleText = `  # check if a port is in use
   nitro portcheck 8080
 
   # check a port on a specific hostname
-  nitro portcheck 8080 --hostname 192.168.7.241`
+  nitro portcheck 8080 --hostname 192.168.7.241
+
+  # show the result as JSON
+  nitro portcheck 8080 --json`
+
+// result is the JSON output of the command
+type result struct {
+	Hostname  string `json:"hostname"`
+	Port      string `json:"port"`
+	Available bool   `json:"available"`
+}
 
 // NewCommand returns the command to enable common nitro services. These services are provided as containers
 // and do not require a user to configure the ports/volumes or images.
@@ -29,7 +39,13 @@ func NewCommand(output terminal.Outputer) *cobra.Command {
 			port := args[0]
 
 			// check if the port is in use
-			if err := portavail.Check(Hostname, port); err != nil {
+			err := portavail.Check(Hostname, port)
+
+			if terminal.JSON {
+				return output.JSON(result{Hostname: Hostname, Port: port, Available: err == nil})
+			}
+
+			if err != nil {
 				output.Info("Port", port, "is already in use...")
 
 				return nil
@@ -0,0 +1,79 @@
+package proxy
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/craftcms/nitro/pkg/terminal"
+	"github.com/craftcms/nitro/protob"
+)
+
+const exampleText = `  # save the routes and certificates of the proxy to a file to attach to an issue
+  nitro proxy snapshot
+
+  # load the routes from a snapshot into the proxy
+  nitro proxy restore nitro-proxy-20210601120000.json`
+
+// Snapshot is the file with the state of the proxy. It has the Caddy config with the
+// routes of the sites, the hostnames and expiry of the certificates, and the dashboard.
+type Snapshot struct {
+	Created      time.Time             `json:"created"`
+	Environment  string                `json:"environment"`
+	Version      string                `json:"version"`
+	Caddy        json.RawMessage       `json:"caddy"`
+	Certificates []*protob.Certificate `json:"certificates"`
+	Dashboard    *protob.Dashboard     `json:"dashboard,omitempty"`
+}
+
+// NewCommand returns the command to save the routing state of the proxy to a file and to
+// restore it, so routing issues can be reproduced with the same state of the proxy.
+func NewCommand(nitrod protob.NitroClient, output terminal.Outputer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "proxy",
+		Short:   "Saves and restores the state of the proxy.",
+		Example: exampleText,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(
+		restoreCommand(nitrod, output),
+		snapshotCommand(nitrod, output),
+	)
+
+	return cmd
+}
+
+// write saves the snapshot to the file as indented JSON.
+func write(file string, s *Snapshot) error {
+	content, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(file, append(content, '\n'), 0644)
+}
+
+// read returns the snapshot from the file.
+func read(file string) (*Snapshot, error) {
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var s Snapshot
+	if err := json.Unmarshal(content, &s); err != nil {
+		return nil, fmt.Errorf("unable to read the snapshot %s, %w", file, err)
+	}
+
+	if len(s.Caddy) == 0 {
+		return nil, fmt.Errorf("the snapshot %s does not have a Caddy config", file)
+	}
+
+	return &s, nil
+}
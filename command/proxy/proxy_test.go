@@ -0,0 +1,75 @@
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"google.golang.org/grpc"
+
+	"github.com/craftcms/nitro/pkg/terminal"
+	"github.com/craftcms/nitro/protob"
+)
+
+func TestSnapshotRestore(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "proxy.json")
+
+	spy := &mockNitrod{snapshot: &protob.SnapshotResponse{
+		Version:      "2.0.8",
+		Caddy:        []byte(`{"apps":{"http":{}}}`),
+		Certificates: []*protob.Certificate{{Hostname: "craft.nitro", Expires: 1622548800}},
+		Dashboard:    &protob.Dashboard{Hostname: "nitro.nitro"},
+	}}
+
+	snapshot := snapshotCommand(spy, terminal.New())
+	if err := snapshot.RunE(snapshot, []string{file}); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := read(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if s.Version != "2.0.8" || s.Environment != "nitro" || len(s.Certificates) != 1 || s.Dashboard.GetHostname() != "nitro.nitro" {
+		t.Errorf("unexpected snapshot %v", s)
+	}
+
+	restore := restoreCommand(spy, terminal.New())
+	if err := restore.Flags().Set("force", "true"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := restore.RunE(restore, []string{file}); err != nil {
+		t.Fatal(err)
+	}
+
+	// the config is indented in the file
+	caddy := &bytes.Buffer{}
+	if err := json.Compact(caddy, spy.restore.GetCaddy()); err != nil {
+		t.Fatal(err)
+	}
+
+	if caddy.String() != `{"apps":{"http":{}}}` || spy.restore.GetDashboard().GetHostname() != "nitro.nitro" {
+		t.Errorf("expected the snapshot to be restored, got %v", spy.restore)
+	}
+}
+
+type mockNitrod struct {
+	protob.NitroClient
+
+	snapshot *protob.SnapshotResponse
+	restore  *protob.RestoreRequest
+}
+
+func (m *mockNitrod) Snapshot(ctx context.Context, in *protob.SnapshotRequest, opts ...grpc.CallOption) (*protob.SnapshotResponse, error) {
+	return m.snapshot, nil
+}
+
+func (m *mockNitrod) Restore(ctx context.Context, in *protob.RestoreRequest, opts ...grpc.CallOption) (*protob.RestoreResponse, error) {
+	m.restore = in
+
+	return &protob.RestoreResponse{}, nil
+}
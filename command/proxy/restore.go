@@ -0,0 +1,66 @@
+package proxy
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	nitroclient "github.com/craftcms/nitro/pkg/client"
+	"github.com/craftcms/nitro/pkg/terminal"
+	"github.com/craftcms/nitro/protob"
+)
+
+// restoreCommand returns the command to load the state from a snapshot into the proxy. The
+// routes are replaced until the next apply, which sets the routes from the config again.
+func restoreCommand(nitrod protob.NitroClient, output terminal.Outputer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "restore <file>",
+		Short: "Loads the state of the proxy from a snapshot.",
+		Example: `  # replace the routes of the proxy with the routes from a snapshot
+  nitro proxy restore nitro-proxy-20210601120000.json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			s, err := read(args[0])
+			if err != nil {
+				return err
+			}
+
+			output.Info(fmt.Sprintf("The snapshot was taken from the %s environment on %s with version %s of the proxy.", s.Environment, s.Created.Format("2006-01-02 15:04:05 MST"), s.Version))
+
+			if force, _ := cmd.Flags().GetBool("force"); !force {
+				confirm, err := output.Confirm("Replace the routes of the proxy with the snapshot?", false, "")
+				if err != nil {
+					return err
+				}
+
+				if !confirm {
+					output.Info("Skipping restore, the proxy was not changed")
+
+					return nil
+				}
+			}
+
+			output.Pending("restoring the proxy state")
+
+			_, err = nitrod.Restore(cmd.Context(), &protob.RestoreRequest{Caddy: s.Caddy, Dashboard: s.Dashboard})
+			if nitroclient.IsUnimplemented(err) {
+				output.Warning()
+				return fmt.Errorf("the proxy does not support snapshots, run `nitro update` to update the proxy")
+			}
+			if err != nil {
+				output.Warning()
+				return fmt.Errorf("unable to restore the state of the proxy, %w", err)
+			}
+
+			output.Done()
+
+			output.Info("Restored the proxy state, run `nitro apply` to use the routes from your config again.")
+
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolP("force", "f", false, "skip the confirmation")
+
+	return cmd
+}
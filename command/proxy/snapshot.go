@@ -0,0 +1,68 @@
+package proxy
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	nitroclient "github.com/craftcms/nitro/pkg/client"
+	"github.com/craftcms/nitro/pkg/environment"
+	"github.com/craftcms/nitro/pkg/terminal"
+	"github.com/craftcms/nitro/protob"
+)
+
+// snapshotCommand returns the command to save the state of the proxy to a file, the file is
+// named after the proxy and the time when a file is not provided.
+func snapshotCommand(nitrod protob.NitroClient, output terminal.Outputer) *cobra.Command {
+	return &cobra.Command{
+		Use:   "snapshot [file]",
+		Short: "Saves the state of the proxy to a file.",
+		Example: `  # save the state of the proxy to nitro-proxy-<time>.json
+  nitro proxy snapshot
+
+  # save the state of the proxy to a file
+  nitro proxy snapshot proxy.json`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			now := time.Now()
+
+			file := fmt.Sprintf("%s-%s.json", environment.Proxy(), now.Format("20060102150405"))
+			if len(args) == 1 {
+				file = args[0]
+			}
+
+			output.Pending("saving the proxy state")
+
+			resp, err := nitrod.Snapshot(cmd.Context(), &protob.SnapshotRequest{})
+			if nitroclient.IsUnimplemented(err) {
+				output.Warning()
+				return fmt.Errorf("the proxy does not support snapshots, run `nitro update` to update the proxy")
+			}
+			if err != nil {
+				output.Warning()
+				return fmt.Errorf("unable to get the state of the proxy, %w", err)
+			}
+
+			s := &Snapshot{
+				Created:      now.UTC(),
+				Environment:  environment.Name,
+				Version:      resp.GetVersion(),
+				Caddy:        resp.GetCaddy(),
+				Certificates: resp.GetCertificates(),
+				Dashboard:    resp.GetDashboard(),
+			}
+
+			if err := write(file, s); err != nil {
+				output.Warning()
+				return err
+			}
+
+			output.Done()
+
+			output.Info("Saved the proxy state to", file, "(the certificate keys are not included)")
+
+			return nil
+		},
+	}
+}
@@ -12,11 +12,16 @@ import (
 
 	"github.com/craftcms/nitro/pkg/config"
 	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/environment"
+	"github.com/craftcms/nitro/pkg/prompt"
 	"github.com/craftcms/nitro/pkg/terminal"
 )
 
 const exampleText = `  # execute the craft queue command for a site
-  nitro queue`
+  nitro queue
+
+  # show the queue workers that run in the background, set queue: true for a site to add one
+  nitro queue status`
 
 // NewCommand returns the command to run queue listen inside of a sites container. It will check if the
 // current working directory is a known site and auto-select or prompt a user for a list of sites.
@@ -40,7 +45,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 
 			// create a filter for the environment
 			filter := filters.NewArgs()
-			filter.Add("label", containerlabels.Nitro)
+			filter.Add("label", containerlabels.Nitro+"="+environment.Label())
 
 			// get a context aware list of sites
 			sites := cfg.ListOfSitesByDirectory(home, wd)
@@ -56,7 +61,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 			switch len(sites) {
 			case 0:
 				// prompt for the site
-				selected, err := output.Select(cmd.InOrStdin(), "Select a site: ", options)
+				selected, err := prompt.Select(cmd.InOrStdin(), "Select a site: ", options, output)
 				if err != nil {
 					return err
 				}
@@ -76,7 +81,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 				filter.Add("label", containerlabels.Host+"="+sites[0].Hostname)
 			default:
 				// prompt for the site to ssh into
-				selected, err := output.Select(cmd.InOrStdin(), "Select a site: ", options)
+				selected, err := prompt.Select(cmd.InOrStdin(), "Select a site: ", options, output)
 				if err != nil {
 					return err
 				}
@@ -167,5 +172,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 		},
 	}
 
+	cmd.AddCommand(statusCommand(docker, output))
+
 	return cmd
 }
@@ -0,0 +1,89 @@
+package queue
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+	"github.com/rodaine/table"
+	"github.com/spf13/cobra"
+
+	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/environment"
+	"github.com/craftcms/nitro/pkg/terminal"
+)
+
+// worker is the status of a queue worker container.
+type worker struct {
+	Name     string `json:"name"`
+	Site     string `json:"site"`
+	Status   string `json:"status"`
+	Restarts int    `json:"restarts"`
+}
+
+// statusCommand returns the command to show the queue workers that are managed by apply
+// and how often docker restarted them after a failure.
+func statusCommand(docker client.CommonAPIClient, output terminal.Outputer) *cobra.Command {
+	return &cobra.Command{
+		Use:   "status",
+		Short: "Shows the status of the queue workers.",
+		Args:  cobra.NoArgs,
+		Example: `  # show the queue workers for the sites
+  nitro queue status`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			filter := filters.NewArgs()
+			filter.Add("label", containerlabels.Nitro+"="+environment.Label())
+			filter.Add("label", containerlabels.Queue)
+
+			containers, err := docker.ContainerList(cmd.Context(), types.ContainerListOptions{All: true, Filters: filter})
+			if err != nil {
+				return err
+			}
+
+			workers := []worker{}
+			for _, c := range containers {
+				details, err := docker.ContainerInspect(cmd.Context(), c.ID)
+				if err != nil {
+					return err
+				}
+
+				status := c.State
+				if status == "exited" {
+					status = "stopped"
+				}
+
+				workers = append(workers, worker{
+					Name:     strings.TrimLeft(c.Names[0], "/"),
+					Site:     c.Labels[containerlabels.Queue],
+					Status:   status,
+					Restarts: details.RestartCount,
+				})
+			}
+
+			sort.Slice(workers, func(i, j int) bool {
+				return workers[i].Name < workers[j].Name
+			})
+
+			if terminal.JSON {
+				return output.JSON(workers)
+			}
+
+			if len(workers) == 0 {
+				output.Info("There are no queue workers, set `queue: true` for a site and run `nitro apply`.")
+				return nil
+			}
+
+			tbl := table.New("Worker", "Site", "Status", "Restarts").WithWriter(cmd.OutOrStdout()).WithPadding(2)
+			for _, w := range workers {
+				tbl.AddRow(w.Name, w.Site, w.Status, strconv.Itoa(w.Restarts))
+			}
+
+			tbl.Print()
+
+			return nil
+		},
+	}
+}
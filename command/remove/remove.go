@@ -1,21 +1,39 @@
 package remove
 
 import (
+	"context"
+	"fmt"
 	"os"
 	"strings"
 
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/client"
 	"github.com/spf13/cobra"
 
+	"github.com/craftcms/nitro/command/internal/databasecontainer"
 	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/environment"
 	"github.com/craftcms/nitro/pkg/prompt"
+	"github.com/craftcms/nitro/pkg/proxyroutes"
 	"github.com/craftcms/nitro/pkg/terminal"
+	"github.com/craftcms/nitro/protob"
 )
 
 const exampleText = `  # remove a site from the config
-  nitro remove`
+  nitro remove
 
-func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outputer) *cobra.Command {
+  # remove a site by the hostname
+  nitro remove craft.nitro
+
+  # remove a site and drop its database
+  nitro remove craft.nitro --with-database`
+
+// NewCommand returns the command to remove a site from the config. The site and queue
+// containers are removed and the proxy stops routing to the site, apply removes the
+// hostnames from the hosts file.
+func NewCommand(home string, docker client.CommonAPIClient, nitrod protob.NitroClient, output terminal.Outputer) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "remove",
 		Short:   "Removes a site.",
@@ -69,7 +87,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 			case true:
 				switch len(sites) {
 				case 0:
-					selected, err := output.Select(cmd.InOrStdin(), "Select a site: ", options)
+					selected, err := prompt.Select(cmd.InOrStdin(), "Select a site: ", options, output)
 					if err != nil {
 						return err
 					}
@@ -78,7 +96,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 				case 1:
 					site = &sites[0]
 				default:
-					selected, err := output.Select(cmd.InOrStdin(), "Select a site: ", options)
+					selected, err := prompt.Select(cmd.InOrStdin(), "Select a site: ", options, output)
 					if err != nil {
 						return err
 					}
@@ -92,7 +110,20 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 				}
 			}
 
-			output.Info("Removing", site.Hostname)
+			// the site points into the config, so it is copied before the site is removed
+			removed := *site
+
+			output.Info("Removing", removed.Hostname)
+
+			// find the database before the site is removed
+			var db *config.Database
+			if withDatabase, _ := cmd.Flags().GetBool("with-database"); withDatabase {
+				if removed.Database == "" {
+					output.Info("The site does not have a database in the config, skipping the database")
+				} else if db, err = cfg.SiteDatabase(removed); err != nil {
+					return err
+				}
+			}
 
 			// remove the site
 			if err := cfg.RemoveSite(site); err != nil {
@@ -104,9 +135,117 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 				return err
 			}
 
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			output.Pending("removing containers")
+
+			if err := removeContainers(ctx, docker, removed.Hostname); err != nil {
+				output.Warning()
+				return err
+			}
+
+			output.Done()
+
+			if db != nil {
+				if err := dropDatabase(ctx, docker, *db, removed.Database, output); err != nil {
+					return err
+				}
+			}
+
+			// stop routing requests to the site when the proxy is running
+			if running(ctx, docker) {
+				if err := proxyroutes.Update(ctx, nitrod, proxyroutes.Sites(cfg), proxyroutes.Dashboard(cfg)); err != nil {
+					output.Info("Unable to update the proxy routes,", err.Error())
+				}
+			}
+
 			return nil
 		},
 	}
 
+	cmd.Flags().Bool("with-database", false, "drop the database of the site")
+
 	return cmd
 }
+
+// removeContainers stops and removes the site container and the queue containers of the site.
+func removeContainers(ctx context.Context, docker client.CommonAPIClient, hostname string) error {
+	for _, label := range []string{containerlabels.Host, containerlabels.Queue} {
+		filter := filters.NewArgs()
+		filter.Add("label", containerlabels.Nitro+"="+environment.Label())
+		filter.Add("label", label+"="+hostname)
+
+		containers, err := docker.ContainerList(ctx, types.ContainerListOptions{All: true, Filters: filter})
+		if err != nil {
+			return fmt.Errorf("unable to list the containers, %w", err)
+		}
+
+		for _, c := range containers {
+			if err := docker.ContainerRemove(ctx, c.ID, types.ContainerRemoveOptions{Force: true}); err != nil {
+				return fmt.Errorf("unable to remove the container %s, %w", strings.TrimLeft(c.Names[0], "/"), err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// running returns true when the proxy container of the environment is running.
+func running(ctx context.Context, docker client.CommonAPIClient) bool {
+	filter := filters.NewArgs()
+	filter.Add("name", environment.Proxy())
+	filter.Add("status", "running")
+
+	containers, err := docker.ContainerList(ctx, types.ContainerListOptions{Filters: filter})
+
+	return err == nil && len(containers) > 0
+}
+
+// dropDatabase removes the database of the site from the database container. The databases
+// that are protected in the config are kept.
+func dropDatabase(ctx context.Context, docker client.CommonAPIClient, db config.Database, name string, output terminal.Outputer) error {
+	hostname, err := db.GetHostname()
+	if err != nil {
+		return err
+	}
+
+	if db.Protected {
+		output.Info("The database engine", hostname, "is protected, skipping the database", name)
+		return nil
+	}
+
+	filter := filters.NewArgs()
+	filter.Add("label", containerlabels.Nitro+"="+environment.Label())
+	filter.Add("label", containerlabels.Type+"=database")
+	filter.Add("name", hostname)
+
+	containers, err := docker.ContainerList(ctx, types.ContainerListOptions{Filters: filter})
+	if err != nil {
+		return fmt.Errorf("unable to list the database containers, %w", err)
+	}
+
+	var id string
+	for _, c := range containers {
+		if strings.TrimLeft(c.Names[0], "/") == hostname {
+			id = c.ID
+		}
+	}
+
+	if id == "" {
+		return fmt.Errorf("the database container %s is not running, start it with `nitro start` to remove the database %s", hostname, name)
+	}
+
+	output.Pending("removing database", name)
+
+	if err := databasecontainer.Drop(ctx, docker, id, db, name); err != nil {
+		output.Warning()
+		return err
+	}
+
+	output.Done()
+
+	return nil
+}
@@ -0,0 +1,89 @@
+package remove
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+
+	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/terminal"
+)
+
+func TestRemoveContainers(t *testing.T) {
+	spy := &mockClient{containers: map[string][]types.Container{
+		containerlabels.Host:  {{ID: "site", Names: []string{"/craft.nitro"}}},
+		containerlabels.Queue: {{ID: "queue-1", Names: []string{"/craft.nitro-queue-1"}}, {ID: "queue-2", Names: []string{"/craft.nitro-queue-2"}}},
+	}}
+
+	if err := removeContainers(context.Background(), spy, "craft.nitro"); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"site", "queue-1", "queue-2"}
+	if !reflect.DeepEqual(spy.removed, want) {
+		t.Errorf("expected the site and queue containers to be removed, got %v want %v", spy.removed, want)
+	}
+
+	for _, f := range spy.filters {
+		if !f.ExactMatch("label", containerlabels.Nitro+"=true") {
+			t.Errorf("expected the containers to be filtered by the environment, got %v", f)
+		}
+	}
+}
+
+func TestDropDatabase_Protected(t *testing.T) {
+	spy := &mockClient{}
+
+	db := config.Database{Engine: "mysql", Version: "8.0", Port: "3306", Protected: true}
+	if err := dropDatabase(context.Background(), spy, db, "craft", terminal.New()); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(spy.filters) != 0 {
+		t.Errorf("expected the protected database to be kept")
+	}
+}
+
+func TestDropDatabase_NotRunning(t *testing.T) {
+	spy := &mockClient{}
+
+	db := config.Database{Engine: "mysql", Version: "8.0", Port: "3306"}
+	if err := dropDatabase(context.Background(), spy, db, "craft", terminal.New()); err == nil {
+		t.Errorf("expected an error when the database container is not running")
+	}
+}
+
+type mockClient struct {
+	client.CommonAPIClient
+
+	// containers are the containers returned by the label of the filter
+	containers map[string][]types.Container
+
+	filters []filters.Args
+	removed []string
+}
+
+func (m *mockClient) ContainerList(ctx context.Context, options types.ContainerListOptions) ([]types.Container, error) {
+	m.filters = append(m.filters, options.Filters)
+
+	for label, containers := range m.containers {
+		for _, v := range options.Filters.Get("label") {
+			if v == label+"=craft.nitro" {
+				return containers, nil
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+func (m *mockClient) ContainerRemove(ctx context.Context, containerID string, options types.ContainerRemoveOptions) error {
+	m.removed = append(m.removed, containerID)
+
+	return nil
+}
@@ -0,0 +1,35 @@
+package restart
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+
+	"github.com/craftcms/nitro/command/internal/environments"
+	"github.com/craftcms/nitro/pkg/terminal"
+)
+
+// all restarts the containers of every environment in parallel, each environment restarts
+// its own proxy. Containers of environments without a config are not restarted.
+func all(ctx context.Context, home string, docker client.ContainerAPIClient, containers []types.Container, output terminal.Outputer) error {
+	envs, err := environments.List(home)
+	if err != nil {
+		return err
+	}
+
+	owned, _ := environments.Partition(envs, containers)
+
+	output.Info(fmt.Sprintf("Restarting %d environments…", len(envs)))
+
+	if err := environments.Run(envs, output, func(env environments.Environment, output terminal.Outputer) error {
+		return restart(ctx, docker, owned[env.Name], output)
+	}); err != nil {
+		return err
+	}
+
+	fmt.Println("Nitro restarted 🎉")
+
+	return nil
+}
@@ -1,6 +1,7 @@
 package restart
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"time"
@@ -10,6 +11,7 @@ import (
 	"github.com/docker/docker/client"
 	"github.com/spf13/cobra"
 
+	"github.com/craftcms/nitro/command/internal/environments"
 	"github.com/craftcms/nitro/pkg/config"
 	"github.com/craftcms/nitro/pkg/containerlabels"
 	"github.com/craftcms/nitro/pkg/terminal"
@@ -24,7 +26,10 @@ const exampleText = `  # restart all containers
   nitro restart
 
   # restart specific site
-  nitro restart tutorial.nitro`
+  nitro restart tutorial.nitro
+
+  # restart the containers of every environment in ~/.nitro
+  nitro --all-envs restart`
 
 // New returns the command to restart all of an environments containers
 func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outputer) *cobra.Command {
@@ -32,6 +37,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 		Use:     "restart",
 		Short:   "Restarts all containers.",
 		Example: exampleText,
+		Args:    environments.Args("restarted"),
 		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 			cfg, err := config.Load(home)
 			if err != nil {
@@ -56,7 +62,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 			// get all the containers using a filter, we only want to restart containers which
 			// have the label com.craftcms.nitro.environment=name
 			filter := filters.NewArgs()
-			filter.Add("label", containerlabels.Nitro)
+			filter.Add("label", environments.Label())
 
 			if site != "" {
 				// add the label to get the site
@@ -74,23 +80,14 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 				return ErrNoContainers
 			}
 
-			output.Info("Restarting Nitro…")
-
-			// set a timeout, consider making this a flag
-			timeout := time.Duration(5000) * time.Millisecond
-
-			// restart each container for the environment
-			for _, c := range containers {
-				n := strings.TrimLeft(c.Names[0], "/")
-
-				output.Pending("restarting", n)
+			if environments.All {
+				return all(ctx, home, docker, containers, output)
+			}
 
-				// restart the container
-				if err := docker.ContainerRestart(ctx, c.ID, &timeout); err != nil {
-					return fmt.Errorf("unable to restart container %s: %w", n, err)
-				}
+			output.Info("Restarting Nitro…")
 
-				output.Done()
+			if err := restart(ctx, docker, containers, output); err != nil {
+				return err
 			}
 
 			fmt.Println("Nitro restarted 🎉")
@@ -101,3 +98,27 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 
 	return cmd
 }
+
+// restart restarts the containers, the databases and services are restarted before the
+// proxy and sites that use them.
+func restart(ctx context.Context, docker client.ContainerAPIClient, containers []types.Container, output terminal.Outputer) error {
+	// set a timeout, consider making this a flag
+	timeout := time.Duration(5000) * time.Millisecond
+
+	containerlabels.StartOrder(containers)
+
+	for _, c := range containers {
+		n := strings.TrimLeft(c.Names[0], "/")
+
+		output.Pending("restarting", n)
+
+		if err := docker.ContainerRestart(ctx, c.ID, &timeout); err != nil {
+			output.Warning()
+			return fmt.Errorf("unable to restart container %s: %w", n, err)
+		}
+
+		output.Done()
+	}
+
+	return nil
+}
@@ -0,0 +1,160 @@
+package scan
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/rodaine/table"
+	"github.com/spf13/cobra"
+
+	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/pathexists"
+	"github.com/craftcms/nitro/pkg/phpversions"
+	"github.com/craftcms/nitro/pkg/projects"
+	"github.com/craftcms/nitro/pkg/prompt"
+	"github.com/craftcms/nitro/pkg/terminal"
+	"github.com/craftcms/nitro/pkg/validate"
+)
+
+const projectsExampleText = `  # find craft projects in a directory and add them as sites
+  nitro scan projects ~/dev`
+
+// projectsCommand returns the command that finds the Craft projects in a directory that
+// are not already sites and offers to add all of them as sites using the web root and PHP
+// version detected for each project.
+func projectsCommand(home string, output terminal.Outputer) *cobra.Command {
+	return &cobra.Command{
+		Use:     "projects <directory>",
+		Short:   "Scans a directory for Craft projects to add as sites.",
+		Example: projectsExampleText,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir := args[0]
+			if strings.HasPrefix(dir, "~") {
+				dir = home + strings.TrimPrefix(dir, "~")
+			}
+
+			if !pathexists.IsDirectory(dir) {
+				return fmt.Errorf("the directory %s does not exist", args[0])
+			}
+
+			return scanProjects(cmd, home, dir, output)
+		},
+	}
+}
+
+// projectHostname returns the hostname for a project directory using the same rules as
+// new sites, lowercase and without spaces or underscores.
+func projectHostname(path, tld string) (string, error) {
+	name := strings.ToLower(filepath.Base(path))
+	name = strings.NewReplacer(" ", "-", "_", "-").Replace(name)
+
+	hostname := fmt.Sprintf("%s.%s", name, tld)
+
+	v := validate.HostnameValidator{}
+	if err := v.Validate(hostname); err != nil {
+		return "", err
+	}
+
+	return hostname, nil
+}
+
+func scanProjects(cmd *cobra.Command, home, dir string, output terminal.Outputer) error {
+	cfg, err := config.Load(home)
+	if err != nil {
+		return err
+	}
+
+	output.Pending("scanning", dir)
+
+	found, err := projects.Find(dir)
+	if err != nil {
+		output.Warning()
+		return err
+	}
+
+	output.Done()
+
+	// ignore the projects that are already sites
+	existing := make(map[string]bool)
+	for _, s := range cfg.Sites {
+		if path, err := s.GetAbsPath(home); err == nil {
+			existing[path] = true
+		}
+	}
+
+	// set the default php version for projects that do not require a version
+	version := cfg.Defaults.PHP
+	if version == "" {
+		version = phpversions.Versions[0]
+	}
+
+	var sites []config.Site
+	for _, p := range found {
+		if existing[p.Path] {
+			continue
+		}
+
+		hostname, err := projectHostname(p.Path, cfg.GetTLD())
+		if err != nil {
+			output.Info("skipping", p.Path+",", err.Error())
+			continue
+		}
+
+		path := p.Path
+		if strings.HasPrefix(path, home) {
+			path = "~" + strings.TrimPrefix(path, home)
+		}
+
+		site := config.Site{
+			Hostname: hostname,
+			Path:     path,
+			Webroot:  p.Webroot,
+			Version:  version,
+		}
+
+		if p.PHP != "" {
+			site.Version = p.PHP
+		}
+
+		sites = append(sites, site)
+	}
+
+	if len(sites) == 0 {
+		output.Info("There are no new Craft projects in", dir)
+
+		return nil
+	}
+
+	tbl := table.New("Hostname", "Path", "Web Root", "PHP").WithWriter(cmd.OutOrStdout()).WithPadding(2)
+	for _, s := range sites {
+		tbl.AddRow(s.Hostname, s.Path, s.Webroot, s.Version)
+	}
+
+	tbl.Print()
+
+	add, err := output.Confirm(fmt.Sprintf("Add %d sites?", len(sites)), true, "")
+	if err != nil {
+		return err
+	}
+
+	if !add {
+		return nil
+	}
+
+	for _, s := range sites {
+		if err := cfg.AddSite(s); err != nil {
+			output.Info("skipping", s.Hostname+",", err.Error())
+			continue
+		}
+
+		output.Success("adding site", s.Hostname)
+	}
+
+	if err := cfg.Save(); err != nil {
+		return err
+	}
+
+	return prompt.RunApply(cmd, []string{}, false, output)
+}
@@ -0,0 +1,41 @@
+package scan
+
+import "testing"
+
+func TestProjectHostname(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "directory names are used as is",
+			path: "/home/nitro/dev/tutorial",
+			want: "tutorial.nitro",
+		},
+		{
+			name: "uppercase letters, spaces, and underscores are normalised",
+			path: "/home/nitro/dev/My Client_Site",
+			want: "my-client-site.nitro",
+		},
+		{
+			name:    "special characters are invalid",
+			path:    "/home/nitro/dev/site(old)",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := projectHostname(tt.path, "nitro")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("projectHostname() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if got != tt.want {
+				t.Errorf("projectHostname() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,269 @@
+package scan
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/mount"
+	volumetypes "github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/rodaine/table"
+	"github.com/spf13/cobra"
+
+	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/environment"
+	"github.com/craftcms/nitro/pkg/imagescan"
+	"github.com/craftcms/nitro/pkg/terminal"
+)
+
+const exampleText = `  # scan all images used by nitro for critical vulnerabilities
+  nitro scan
+
+  # scan only the image used by a site
+  nitro scan tutorial.nitro
+
+  # include high severity vulnerabilities and show each CVE
+  nitro scan --severity critical,high --details
+
+  # find craft projects in a directory and add them as sites
+  nitro scan projects ~/dev`
+
+// NewCommand returns the command used to scan the images nitro manages for known vulnerabilities. The
+// scanner runs in a disposable container with access to the docker socket, so images do not need to
+// be pushed or exported to be scanned. The projects subcommand scans a directory for Craft projects
+// that can be added as sites instead.
+func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outputer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "scan",
+		Short:   "Scans images for vulnerabilities.",
+		Example: exampleText,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			var site string
+			if len(args) > 0 {
+				site = args[0]
+			}
+
+			// get all of the containers for the environment
+			filter := filters.NewArgs()
+			filter.Add("label", containerlabels.Nitro+"="+environment.Label())
+
+			containers, err := docker.ContainerList(ctx, types.ContainerListOptions{All: true, Filters: filter})
+			if err != nil {
+				return fmt.Errorf("unable to get a list of the containers, %w", err)
+			}
+
+			// map each image to the containers that use it
+			images := make(map[string][]string)
+			for _, c := range containers {
+				name := strings.TrimLeft(c.Names[0], "/")
+
+				// skip disposable containers
+				switch c.Labels[containerlabels.Type] {
+				case "composer", "npm", "scan":
+					continue
+				}
+
+				// if the user wants a single site only, skip all of the other containers
+				if site != "" && c.Labels[containerlabels.Host] != site {
+					continue
+				}
+
+				images[c.Image] = append(images[c.Image], name)
+			}
+
+			if len(images) == 0 {
+				output.Info("There are no images to scan 😅")
+
+				return nil
+			}
+
+			severities := strings.Split(cmd.Flag("severity").Value.String(), ",")
+
+			// make sure we have the scanner image
+			imageFilter := filters.NewArgs()
+			imageFilter.Add("reference", imagescan.Image)
+
+			scanners, err := docker.ImageList(ctx, types.ImageListOptions{Filters: imageFilter})
+			if err != nil {
+				return fmt.Errorf("unable to get a list of images, %w", err)
+			}
+
+			if len(scanners) == 0 {
+				output.Pending("pulling", imagescan.Image)
+
+				rdr, err := docker.ImagePull(ctx, imagescan.Image, types.ImagePullOptions{})
+				if err != nil {
+					output.Warning()
+					return fmt.Errorf("unable to pull the scanner image, %w", err)
+				}
+
+				buf := &bytes.Buffer{}
+				if _, err := buf.ReadFrom(rdr); err != nil {
+					output.Warning()
+					return fmt.Errorf("unable to read the output from pulling the image, %w", err)
+				}
+
+				output.Done()
+			}
+
+			// create a volume to cache the vulnerability database
+			if _, err := docker.VolumeCreate(ctx, volumetypes.VolumeCreateBody{
+				Driver: "local",
+				Name:   imagescan.CacheVolume,
+				Labels: map[string]string{
+					containerlabels.Nitro:  environment.Label(),
+					containerlabels.Volume: imagescan.CacheVolume,
+				},
+			}); err != nil {
+				return fmt.Errorf("unable to create the volume, %w", err)
+			}
+
+			// sort the images so the output is consistent
+			var names []string
+			for image := range images {
+				names = append(names, image)
+			}
+			sort.Strings(names)
+
+			output.Info("Scanning images…")
+
+			var reports []imagescan.Report
+			var failed []string
+			for _, image := range names {
+				output.Pending("scanning", image)
+
+				report, err := scanImage(cmd, docker, image, severities)
+				if err != nil {
+					output.Warning()
+					output.Info(err.Error())
+					failed = append(failed, image)
+					continue
+				}
+
+				output.Done()
+
+				reports = append(reports, report)
+			}
+
+			tbl := table.New("Image", "Used By", "Vulnerabilities", "Fixable").WithWriter(cmd.OutOrStdout()).WithPadding(2)
+			for _, r := range reports {
+				tbl.AddRow(r.Image, strings.Join(images[r.Image], ","), len(r.Vulnerabilities), r.Fixable())
+			}
+
+			tbl.Print()
+
+			// show the details for each vulnerability
+			if cmd.Flag("details").Value.String() == "true" {
+				for _, r := range reports {
+					if len(r.Vulnerabilities) == 0 {
+						continue
+					}
+
+					output.Info("")
+					output.Info(r.Image)
+
+					details := table.New("ID", "Package", "Installed", "Fixed", "Severity").WithWriter(cmd.OutOrStdout()).WithPadding(2)
+					for _, v := range r.Vulnerabilities {
+						details.AddRow(v.ID, v.Package, v.InstalledVersion, v.FixedVersion, v.Severity)
+					}
+
+					details.Print()
+				}
+			}
+
+			// images that could not be scanned are not safe, so the command fails
+			if len(failed) > 0 {
+				return fmt.Errorf("unable to scan %d of %d images:\n  %s", len(failed), len(names), strings.Join(failed, "\n  "))
+			}
+
+			return nil
+		},
+	}
+
+	cmd.AddCommand(projectsCommand(home, output))
+
+	cmd.Flags().String("severity", "critical", "comma separated list of severities to report")
+	cmd.Flags().Bool("details", false, "show each vulnerability found")
+
+	return cmd
+}
+
+func scanImage(cmd *cobra.Command, docker client.CommonAPIClient, image string, severities []string) (imagescan.Report, error) {
+	ctx := cmd.Context()
+
+	// create the scanner container with access to the local images
+	resp, err := docker.ContainerCreate(
+		ctx,
+		&container.Config{
+			Image: imagescan.Image,
+			Cmd:   imagescan.Commands(image, severities),
+			Labels: map[string]string{
+				containerlabels.Nitro: environment.Label(),
+				containerlabels.Type:  "scan",
+			},
+		},
+		&container.HostConfig{
+			Binds: []string{"/var/run/docker.sock:/var/run/docker.sock"},
+			Mounts: []mount.Mount{
+				{
+					Type:   mount.TypeVolume,
+					Source: imagescan.CacheVolume,
+					Target: "/root/.cache",
+				},
+			},
+		},
+		nil,
+		nil,
+		"",
+	)
+	if err != nil {
+		return imagescan.Report{}, fmt.Errorf("unable to create the scanner container, %w", err)
+	}
+
+	// always remove the scanner container
+	defer docker.ContainerRemove(ctx, resp.ID, types.ContainerRemoveOptions{Force: true})
+
+	// attach to the container
+	stream, err := docker.ContainerAttach(ctx, resp.ID, types.ContainerAttachOptions{
+		Stream: true,
+		Stdout: true,
+		Stderr: true,
+		Logs:   true,
+	})
+	if err != nil {
+		return imagescan.Report{}, fmt.Errorf("unable to attach to container, %w", err)
+	}
+	defer stream.Close()
+
+	if err := docker.ContainerStart(ctx, resp.ID, types.ContainerStartOptions{}); err != nil {
+		return imagescan.Report{}, fmt.Errorf("unable to start the container, %w", err)
+	}
+
+	// the stdout contains the results and the stderr explains a failed scan
+	buf, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	if _, err := stdcopy.StdCopy(buf, stderr, stream.Reader); err != nil {
+		return imagescan.Report{}, fmt.Errorf("unable to read the scanner output, %w", err)
+	}
+
+	// a failed scan (e.g. no vulnerability database or a missing image) must not be reported as
+	// an image without vulnerabilities
+	waitC, errC := docker.ContainerWait(ctx, resp.ID, container.WaitConditionNotRunning)
+	select {
+	case status := <-waitC:
+		if status.StatusCode != 0 {
+			return imagescan.Report{}, fmt.Errorf("unable to scan %s, the scanner exited with code %d: %s", image, status.StatusCode, strings.TrimSpace(stderr.String()))
+		}
+	case err := <-errC:
+		return imagescan.Report{}, fmt.Errorf("unable to wait for the scanner container, %w", err)
+	}
+
+	return imagescan.Parse(image, buf)
+}
@@ -4,10 +4,12 @@ import (
 	"archive/tar"
 	"archive/zip"
 	"compress/gzip"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
 	"runtime"
+	"strings"
 
 	"github.com/minio/selfupdate"
 	"github.com/spf13/cobra"
@@ -22,16 +24,24 @@ var (
 	DevRelease bool
 
 	// LatestURL is the URL to the github releases
-	LatestURL = "https://api.github.com/repos/craftcms/nitro/releases/latest"
+	LatestURL = releases.LatestURL
 
 	// ReleasesURL is all releases including preview releases
 	ReleasesURL = "https://api.github.com/repos/craftcms/nitro/releases"
+
+	// ErrNoBinary is returned when the release archive does not contain the nitro binary
+	ErrNoBinary = fmt.Errorf("unable to find the nitro binary in the release")
 )
 
 const exampleText = `  # update to the latest version of the nitro CLI
-  nitro self-update`
+  nitro self-update
+
+  # check if there is a new version without updating
+  nitro self-update --check`
 
-// NewCommand is used to help update a nitro cli using the latest version.
+// NewCommand is used to help update a nitro cli using the latest version. The release
+// archive is verified with the checksums file of the release before the running binary
+// is replaced.
 func NewCommand(output terminal.Outputer) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "self-update",
@@ -52,127 +62,172 @@ func NewCommand(output terminal.Outputer) *cobra.Command {
 			}
 
 			// make sure the versions do not match
-			if release.Version == version.Version {
+			if releases.Current(release.Version, version.Version) {
 				output.Info("up to date!")
 				return nil
 			}
 
-			output.Pending("found version", release.Version, "updating")
+			if cmd.Flag("check").Value.String() == "true" {
+				output.Info("Nitro", release.Version, "is available (current version is "+version.Version+"), run `nitro self-update` to update.")
+				return nil
+			}
+
+			if release.ChecksumsURL == "" {
+				return fmt.Errorf("unable to verify the release %s, it does not have a checksums file", release.Version)
+			}
+
+			output.Pending("downloading", release.Version)
 
 			// create a temp file to save the release into
-			file, err := ioutil.TempFile(os.TempDir(), "nitro-release-download-")
+			file, err := download(release.URL, "nitro-release-download-")
 			if err != nil {
+				output.Warning()
 				return err
 			}
-			defer file.Close()
+			defer os.Remove(file)
 
-			// download the release
-			if err := releases.NewDownloader().Download(release.URL, file.Name()); err != nil {
+			checksums, err := download(release.ChecksumsURL, "nitro-release-checksums-")
+			if err != nil {
+				output.Warning()
 				return err
 			}
+			defer os.Remove(checksums)
 
-			switch release.ContentType {
-			case "application/gzip":
-				file, err := os.Open(file.Name())
-				if err != nil {
-					return err
-				}
-				defer file.Close()
+			content, err := ioutil.ReadFile(checksums)
+			if err != nil {
+				output.Warning()
+				return err
+			}
 
-				gz, err := gzip.NewReader(file)
-				if err != nil {
-					return err
-				}
-				defer gz.Close()
+			// verify the download before replacing the binary
+			if err := releases.Verify(file, release.Name, content); err != nil {
+				output.Warning()
+				return err
+			}
 
-				// untar the zip
-				tr := tar.NewReader(gz)
+			output.Done()
 
-				i := 0
-				for {
-					header, err := tr.Next()
+			bin, err := binary(file, release)
+			if err != nil {
+				return err
+			}
+			defer bin.Close()
 
-					if err == io.EOF {
-						break
-					}
+			output.Pending("updating to", release.Version)
 
-					if err != nil {
-						return err
-					}
+			// the binary is replaced with a rename, so the update is atomic
+			if err := selfupdate.Apply(bin, selfupdate.Options{}); err != nil {
+				output.Warning()
 
-					switch header.Typeflag {
-					case tar.TypeDir:
-						continue
-					case tar.TypeReg:
-						name := header.Name
+				if rerr := selfupdate.RollbackError(err); rerr != nil {
+					return fmt.Errorf("unable to update and restore the previous version, %w", rerr)
+				}
 
-						switch release.OperatingSystem {
-						case "windows":
-							if name == "nitro.exe" {
-								output.Done()
+				return fmt.Errorf("unable to update nitro, %w", err)
+			}
 
-								output.Info("Updating to Nitro", release.Version+"!")
+			output.Done()
 
-								// self update
-								if err := selfupdate.Apply(tr, selfupdate.Options{}); err != nil {
-									return err
-								}
+			output.Info("Updated to Nitro", release.Version+"!")
 
-								break
-							}
-						default:
-							if name == "nitro" {
-								output.Done()
+			return nil
+		},
+	}
 
-								output.Info("Updating to Nitro", release.Version+"!")
+	cmd.Flags().BoolVar(&DevRelease, "dev", false, "install the latest development release")
+	cmd.Flags().Bool("check", false, "only check if there is a new version")
 
-								// self update
-								if err := selfupdate.Apply(tr, selfupdate.Options{}); err != nil {
-									return err
-								}
+	return cmd
+}
 
-								break
-							}
-						}
-					}
+// download saves the url to a temp file and returns the path to the file.
+func download(url, prefix string) (string, error) {
+	file, err := ioutil.TempFile(os.TempDir(), prefix)
+	if err != nil {
+		return "", err
+	}
+	file.Close()
 
-					i++
-				}
-			case "application/zip":
-				// unzip
-				zr, err := zip.OpenReader(file.Name())
+	if err := releases.NewDownloader().Download(url, file.Name()); err != nil {
+		os.Remove(file.Name())
+		return "", err
+	}
+
+	return file.Name(), nil
+}
+
+// binary returns a reader for the nitro binary in the release archive.
+func binary(file string, release *releases.Release) (io.ReadCloser, error) {
+	name := "nitro"
+	if release.OperatingSystem == "windows" {
+		name = "nitro.exe"
+	}
+
+	if strings.HasSuffix(release.Name, ".zip") || release.ContentType == "application/zip" {
+		zr, err := zip.OpenReader(file)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, f := range zr.File {
+			if f.Name == name {
+				rdr, err := f.Open()
 				if err != nil {
-					return err
+					zr.Close()
+					return nil, err
 				}
 
-				for _, file := range zr.Reader.File {
-					switch release.OperatingSystem {
-					case "windows":
-						if file.Name == "nitro.exe" {
-							output.Done()
-
-							output.Info("Updated to Nitro", release.Version+"!")
-
-							// read the file
-							f, err := os.Open(file.FileInfo().Name())
-							if err != nil {
-								return err
-							}
-
-							// self update
-							if err := selfupdate.Apply(f, selfupdate.Options{}); err != nil {
-								return err
-							}
-						}
-					}
-				}
+				return closer{Reader: rdr, close: func() error {
+					rdr.Close()
+					return zr.Close()
+				}}, nil
 			}
+		}
 
-			return nil
-		},
+		zr.Close()
+
+		return nil, ErrNoBinary
 	}
 
-	cmd.Flags().BoolVar(&DevRelease, "dev", false, "install the latest development release")
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
 
-	return cmd
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+
+		if header.Typeflag == tar.TypeReg && header.Name == name {
+			return closer{Reader: tr, close: f.Close}, nil
+		}
+	}
+
+	f.Close()
+
+	return nil, ErrNoBinary
+}
+
+// closer closes the archive once the binary is read.
+type closer struct {
+	io.Reader
+	close func() error
+}
+
+func (c closer) Close() error {
+	return c.close()
 }
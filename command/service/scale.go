@@ -0,0 +1,76 @@
+package service
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/docker/docker/client"
+	"github.com/spf13/cobra"
+
+	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/prompt"
+	"github.com/craftcms/nitro/pkg/terminal"
+)
+
+var scaleExampleText = `  # run three replicas of a custom container
+  nitro service scale chrome 3
+
+  # go back to a single container
+  nitro service scale chrome 1`
+
+// scaleCommand sets the number of replicas for a custom container. Apply creates a container for
+// each replica and requests to the containers hostname are balanced between them.
+func scaleCommand(home string, docker client.CommonAPIClient, output terminal.Outputer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "scale",
+		Short:   "Scales a container to multiple replicas.",
+		Example: scaleExampleText,
+		Args:    cobra.ExactArgs(2),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) > 0 {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+
+			cfg, err := config.Load(home)
+			if err != nil {
+				return nil, cobra.ShellCompDirectiveDefault
+			}
+
+			var options []string
+			for _, c := range cfg.Containers {
+				options = append(options, c.Name)
+			}
+
+			return options, cobra.ShellCompDirectiveNoFileComp
+		},
+		PostRunE: func(cmd *cobra.Command, args []string) error {
+			return prompt.RunApply(cmd, args, false, output)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			replicas, err := strconv.Atoi(args[1])
+			if err != nil {
+				return fmt.Errorf("the number of replicas must be a number, %w", err)
+			}
+
+			// load the config
+			cfg, err := config.Load(home)
+			if err != nil {
+				return err
+			}
+
+			if err := cfg.ScaleContainer(args[0], replicas); err != nil {
+				return err
+			}
+
+			if err := cfg.Save(); err != nil {
+				return err
+			}
+
+			output.Info(fmt.Sprintf("Scaled %s to %d replicas", args[0], replicas))
+
+			return nil
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,30 @@
+package service
+
+import (
+	"github.com/docker/docker/client"
+	"github.com/spf13/cobra"
+
+	"github.com/craftcms/nitro/pkg/terminal"
+)
+
+const exampleText = `  # run three replicas of a custom container
+  nitro service scale chrome 3`
+
+// NewCommand returns the service commands for managing stateless helper
+// containers, such as a pool of headless Chrome containers.
+func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outputer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "service",
+		Short:   "Manages services.",
+		Example: exampleText,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(
+		scaleCommand(home, docker, output),
+	)
+
+	return cmd
+}
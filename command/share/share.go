@@ -1,32 +1,41 @@
 package share
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"os"
-	"os/exec"
+	"os/signal"
 	"strings"
+	"syscall"
 
 	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/network"
 	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
 	"github.com/spf13/cobra"
 
+	"github.com/craftcms/nitro/pkg/clipboard"
 	"github.com/craftcms/nitro/pkg/config"
 	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/environment"
+	"github.com/craftcms/nitro/pkg/prompt"
 	"github.com/craftcms/nitro/pkg/terminal"
 )
 
-var (
-	// execName is the name of the executable to search for. We make it a variable so we can replace it during tests.
-	execName = "ngrok"
-)
+const exampleText = `  # share a local site with ngrok (requires the NGROK_AUTHTOKEN environment variable)
+  nitro share
+
+  # share a local site with localtunnel
+  nitro share --tunnel localtunnel
 
-const exampleText = `  # share a local site with ngrok
-  nitro share`
+  # share a local site and copy the share link to the clipboard
+  nitro share --copy`
 
-// NewCommand is used to destroy all resources for an environment. It will prompt for
-// user verification and defaults to no. Part of the destroy process is to
-// perform a backup for all databases in each container database.
+// NewCommand returns the command to share a site through a public url. The tunnel runs in
+// a container on the nitro network, so ngrok does not need to be installed.
 func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outputer) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "share",
@@ -48,17 +57,22 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
 
-			// find ngrok
-			ngrok, err := exec.LookPath(execName)
+			// find the tunnel to share the site with
+			name, _ := cmd.Flags().GetString("tunnel")
+			tun, err := findTunnel(name)
 			if err != nil {
 				return err
 			}
 
-			// if ngrok is missing, return the error
-			if ngrok == "" {
-				output.Info("Ngrok is required to share sites, download ngrok from https://ngrok.com")
+			// check the tunnel has the required credentials
+			var envs []string
+			if tun.Env != "" {
+				value := os.Getenv(tun.Env)
+				if value == "" {
+					return fmt.Errorf("sharing with %s requires the %s environment variable, or use --tunnel localtunnel", name, tun.Env)
+				}
 
-				return nil
+				envs = append(envs, tun.Env+"="+value)
 			}
 
 			// get the current working directory
@@ -75,7 +89,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 
 			// create a filter for the environment
 			filter := filters.NewArgs()
-			filter.Add("label", containerlabels.Nitro)
+			filter.Add("label", containerlabels.Nitro+"="+environment.Label())
 
 			// get a context aware list of sites
 			sites := cfg.ListOfSitesByDirectory(home, wd)
@@ -108,7 +122,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 				switch len(sites) {
 				case 0:
 					// prompt for the site to ssh into
-					selected, err := output.Select(cmd.InOrStdin(), "Select a site: ", options)
+					selected, err := prompt.Select(cmd.InOrStdin(), "Select a site: ", options, output)
 					if err != nil {
 						return err
 					}
@@ -125,7 +139,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 					site = sites[0]
 				default:
 					// prompt for the site to ssh into
-					selected, err := output.Select(cmd.InOrStdin(), "Select a site: ", options)
+					selected, err := prompt.Select(cmd.InOrStdin(), "Select a site: ", options, output)
 					if err != nil {
 						return err
 					}
@@ -158,44 +172,157 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 				}
 			}
 
-			// parse the flags
-
-			ngrokArgs := []string{"http"}
-
-			// set the main hostname
-			ngrokArgs = append(ngrokArgs, "-host-header="+site.Hostname)
+			// find the network
+			networkFilter := filters.NewArgs()
+			networkFilter.Add("name", environment.Network())
 
-			// append the aliases
-			for _, a := range site.Aliases {
-				ngrokArgs = append(ngrokArgs, "-host-header="+a)
-			}
-
-			// set the region
-			region, err := cmd.Flags().GetString("region")
+			networks, err := docker.NetworkList(ctx, types.NetworkListOptions{Filters: networkFilter})
 			if err != nil {
-				region = "us"
+				return fmt.Errorf("unable to list the docker networks, %w", err)
 			}
-			ngrokArgs = append(ngrokArgs, "--region="+region)
 
-			// set the port
-			port, err := cmd.Flags().GetString("port")
-			if err != nil {
-				port = "80"
+			var networkID string
+			for _, n := range networks {
+				if n.Name == environment.Network() || strings.TrimLeft(n.Name, "/") == environment.Network() {
+					networkID = n.ID
+				}
 			}
-			ngrokArgs = append(ngrokArgs, port)
 
-			c := exec.Command(ngrok, ngrokArgs...)
+			if networkID == "" {
+				return fmt.Errorf("unable to find the network, run `nitro init` to create it")
+			}
 
-			c.Stderr = cmd.ErrOrStderr()
-			c.Stdout = cmd.OutOrStdout()
+			region, _ := cmd.Flags().GetString("region")
+			copyURL, _ := cmd.Flags().GetBool("copy")
 
-			return c.Run()
+			return share(ctx, docker, networkID, site, tun, envs, region, copyURL, output)
 		},
 	}
 
 	// add flags to the command
+	cmd.Flags().String("tunnel", "ngrok", "which tunnel to share the site with (ngrok or localtunnel)")
 	cmd.Flags().String("region", "us", "which ngrok region to use for sharing")
 	cmd.Flags().String("port", "80", "which port to use for ngrok")
+	cmd.Flags().Bool("copy", false, "copy the share link to the clipboard")
+	_ = cmd.Flags().MarkDeprecated("port", "the tunnel connects to the site container directly")
 
 	return cmd
 }
+
+// share starts a tunnel container on the nitro network that forwards requests for the
+// public url to the site container. It shows the public url once the tunnel is ready
+// and stops the tunnel when the command is interrupted.
+func share(ctx context.Context, docker client.CommonAPIClient, networkID string, site config.Site, tun tunnel, envs []string, region string, copyURL bool, output terminal.Outputer) error {
+	containerName := site.Hostname + ".share.nitro"
+
+	// remove a tunnel left over from a previous share
+	filter := filters.NewArgs()
+	filter.Add("label", containerlabels.Type+"=share")
+	filter.Add("name", containerName)
+
+	containers, err := docker.ContainerList(ctx, types.ContainerListOptions{All: true, Filters: filter})
+	if err != nil {
+		return err
+	}
+
+	for _, c := range containers {
+		if err := docker.ContainerRemove(ctx, c.ID, types.ContainerRemoveOptions{Force: true}); err != nil {
+			return fmt.Errorf("unable to remove the existing tunnel, %w", err)
+		}
+	}
+
+	output.Pending("pulling", tun.Image)
+
+	rdr, err := docker.ImagePull(ctx, tun.Image, types.ImagePullOptions{})
+	if err != nil {
+		output.Warning()
+		return fmt.Errorf("unable to pull the image, %w", err)
+	}
+
+	buf := &bytes.Buffer{}
+	if _, err := buf.ReadFrom(rdr); err != nil {
+		output.Warning()
+		return fmt.Errorf("unable to read the output from pulling the image, %w", err)
+	}
+
+	output.Done()
+
+	resp, err := docker.ContainerCreate(
+		ctx,
+		&container.Config{
+			Image: tun.Image,
+			Cmd:   tun.cmd(site.Hostname, region),
+			Env:   envs,
+			Labels: map[string]string{
+				containerlabels.Nitro: environment.Label(),
+				containerlabels.Type:  "share",
+			},
+		},
+		&container.HostConfig{AutoRemove: true},
+		&network.NetworkingConfig{
+			EndpointsConfig: map[string]*network.EndpointSettings{
+				environment.Network(): {
+					NetworkID: networkID,
+				},
+			},
+		},
+		nil,
+		containerName,
+	)
+	if err != nil {
+		return fmt.Errorf("unable to create the tunnel container, %w", err)
+	}
+
+	// attach to the container
+	stream, err := docker.ContainerAttach(ctx, resp.ID, types.ContainerAttachOptions{
+		Stream: true,
+		Stdout: true,
+		Stderr: true,
+		Logs:   true,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to attach to the tunnel container, %w", err)
+	}
+	defer stream.Close()
+
+	if err := docker.ContainerStart(ctx, resp.ID, types.ContainerStartOptions{}); err != nil {
+		return fmt.Errorf("unable to start the tunnel container, %w", err)
+	}
+
+	// stop the tunnel when the command is interrupted
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sig)
+
+	go func() {
+		if _, ok := <-sig; ok {
+			_ = docker.ContainerStop(context.Background(), resp.ID, nil)
+		}
+	}()
+
+	w := &urlWriter{pattern: tun.URL, found: func(url string) {
+		output.Info(fmt.Sprintf("Sharing %s at %s", site.Hostname, url))
+
+		if copyURL {
+			if err := clipboard.Copy(url); err != nil {
+				output.Info("unable to copy the share link,", err.Error())
+			} else {
+				output.Info("Copied the share link to the clipboard")
+			}
+		}
+
+		output.Info("Press Ctrl+C to stop sharing")
+	}}
+
+	if _, err := stdcopy.StdCopy(w, w, stream.Reader); err != nil {
+		return fmt.Errorf("unable to read the output of the tunnel container, %w", err)
+	}
+
+	if w.url == "" {
+		return fmt.Errorf("the tunnel stopped before sharing %s:\n%s", site.Hostname, w.tail(10))
+	}
+
+	output.Info("Stopped sharing", site.Hostname)
+
+	return nil
+}
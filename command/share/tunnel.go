@@ -0,0 +1,92 @@
+package share
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"sync"
+)
+
+// tunnel is a container that forwards requests from a public url to a site
+// container on the nitro network.
+type tunnel struct {
+	// Image is the image used for the tunnel container
+	Image string
+
+	// Env is the environment variable the tunnel requires, if any (e.g. an auth token)
+	Env string
+
+	// URL matches the public url in the output of the tunnel container
+	URL *regexp.Regexp
+
+	// cmd returns the command for the tunnel container
+	cmd func(hostname, region string) []string
+}
+
+var tunnels = map[string]tunnel{
+	"ngrok": {
+		Image: "docker.io/ngrok/ngrok:latest",
+		Env:   "NGROK_AUTHTOKEN",
+		URL:   regexp.MustCompile(`url=(https://\S+)`),
+		cmd: func(hostname, region string) []string {
+			return []string{"http", "--log=stdout", "--region=" + region, "--host-header=" + hostname, hostname + ":8080"}
+		},
+	},
+	"localtunnel": {
+		Image: "docker.io/efrecon/localtunnel:latest",
+		URL:   regexp.MustCompile(`your url is: (https://\S+)`),
+		cmd: func(hostname, region string) []string {
+			return []string{"--local-host", hostname, "--port", "8080"}
+		},
+	},
+}
+
+// findTunnel returns the tunnel by name or an error listing the available tunnels.
+func findTunnel(name string) (tunnel, error) {
+	t, ok := tunnels[name]
+	if !ok {
+		var names []string
+		for n := range tunnels {
+			names = append(names, n)
+		}
+
+		sort.Strings(names)
+
+		return tunnel{}, fmt.Errorf("unknown tunnel %q, valid options are %v", name, names)
+	}
+
+	return t, nil
+}
+
+// urlWriter keeps the output of a tunnel container and calls found with the public
+// url the first time the tunnel shows it.
+type urlWriter struct {
+	pattern *regexp.Regexp
+	found   func(url string)
+	buf     bytes.Buffer
+	once    sync.Once
+	url     string
+}
+
+func (u *urlWriter) Write(b []byte) (int, error) {
+	if m := u.pattern.FindSubmatch(b); m != nil {
+		u.once.Do(func() {
+			u.url = string(m[1])
+			u.found(u.url)
+		})
+	}
+
+	return u.buf.Write(b)
+}
+
+// tail returns the last lines of output from the tunnel container.
+func (u *urlWriter) tail(lines int) string {
+	out := bytes.TrimSpace(u.buf.Bytes())
+	split := bytes.Split(out, []byte("\n"))
+	if len(split) > lines {
+		split = split[len(split)-lines:]
+	}
+
+	return string(bytes.Join(split, []byte("\n")))
+}
@@ -0,0 +1,68 @@
+package share
+
+import (
+	"testing"
+)
+
+func Test_urlWriter(t *testing.T) {
+	tests := []struct {
+		name   string
+		tunnel string
+		output []string
+		want   string
+	}{
+		{
+			name:   "ngrok shows the url in the logs",
+			tunnel: "ngrok",
+			output: []string{
+				"t=2021-03-01T12:00:00+0000 lvl=info msg=\"starting web service\" obj=web addr=127.0.0.1:4040\n",
+				"t=2021-03-01T12:00:01+0000 lvl=info msg=\"started tunnel\" obj=tunnels name=command_line addr=http://tutorial.nitro:8080 url=https://abc123.ngrok.io\n",
+			},
+			want: "https://abc123.ngrok.io",
+		},
+		{
+			name:   "localtunnel shows the url",
+			tunnel: "localtunnel",
+			output: []string{"your url is: https://funny-cat-42.loca.lt\n"},
+			want:   "https://funny-cat-42.loca.lt",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tun, err := findTunnel(tt.tunnel)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var found []string
+			w := &urlWriter{pattern: tun.URL, found: func(url string) { found = append(found, url) }}
+
+			for _, o := range tt.output {
+				if _, err := w.Write([]byte(o)); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if len(found) != 1 || found[0] != tt.want {
+				t.Errorf("expected the url %q to be found once, got %v", tt.want, found)
+			}
+		})
+	}
+}
+
+func Test_urlWriter_tail(t *testing.T) {
+	w := &urlWriter{pattern: tunnels["ngrok"].URL, found: func(string) {}}
+	if _, err := w.Write([]byte("one\ntwo\nERROR: authentication failed\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := w.tail(2); got != "two\nERROR: authentication failed" {
+		t.Errorf("tail() = %q", got)
+	}
+}
+
+func Test_findTunnel(t *testing.T) {
+	if _, err := findTunnel("serveo"); err == nil {
+		t.Errorf("expected an error for an unknown tunnel")
+	}
+}
@@ -0,0 +1,52 @@
+package ssh
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/docker/docker/client"
+
+	"github.com/craftcms/nitro/pkg/containerexec"
+	"github.com/craftcms/nitro/pkg/terminal"
+)
+
+// sessionName is the name of the tmux session the persistent shells run in.
+const sessionName = "nitro"
+
+// installTmux installs tmux with the package manager of the image.
+const installTmux = `if command -v apk >/dev/null; then apk add --no-cache tmux; elif command -v apt-get >/dev/null; then apt-get update && apt-get install -y tmux; else echo "unable to find a package manager to install tmux" >&2; exit 1; fi`
+
+// ensureTmux installs tmux in the container as root when it is missing. The install is
+// lost when the container is recreated, it is installed again for the next session.
+func ensureTmux(ctx context.Context, docker client.ContainerAPIClient, containerID string, output terminal.Outputer) error {
+	if _, err := containerexec.Run(ctx, docker, containerID, []string{"sh", "-c", "command -v tmux"}); err == nil {
+		return nil
+	}
+
+	output.Pending("installing tmux")
+
+	if _, err := containerexec.RunAs(ctx, docker, containerID, "root", []string{"sh", "-c", installTmux}); err != nil {
+		output.Warning()
+
+		return fmt.Errorf("unable to install tmux for the session, %w", err)
+	}
+
+	output.Done()
+
+	return nil
+}
+
+// hasSession returns true when the user has a session in the container, the sessions of
+// each user are separate.
+func hasSession(ctx context.Context, docker client.ContainerAPIClient, containerID, user string) bool {
+	_, err := containerexec.RunAs(ctx, docker, containerID, user, []string{"tmux", "has-session", "-t", sessionName})
+
+	return err == nil
+}
+
+// sessionCommand returns the command that runs the shell in the session. The session is
+// created when it does not exist, otherwise the shell attaches to it and detaches the
+// clients of the connections that were lost.
+func sessionCommand() []string {
+	return []string{"tmux", "new-session", "-A", "-D", "-s", sessionName, "sh"}
+}
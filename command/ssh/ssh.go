@@ -1,11 +1,37 @@
 package ssh
 
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+	"github.com/moby/term"
+	"github.com/spf13/cobra"
+
+	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/containerexec"
+	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/environment"
+	"github.com/craftcms/nitro/pkg/prompt"
+	"github.com/craftcms/nitro/pkg/terminal"
+)
+
 var (
 	// RootUser is used to tell the container to run as root and not the default user www-data
 	RootUser bool
 
 	// ProxyContainer is used to ssh into the proxy container and is mostly used for troubleshooting
 	ProxyContainer bool
+
+	// Persist runs the shell in a tmux session in the container, so the session and the
+	// commands running in it survive when the connection is lost
+	Persist bool
+
+	// Reattach resumes the persistent session from a previous connection
+	Reattach bool
 )
 
 const exampleText = `  # ssh into a container - assuming its the current working directory
@@ -18,4 +44,178 @@ const exampleText = `  # ssh into a container - assuming its the current working
   nitro ssh --root
 
   # ssh into the proxy container
-  nitro ssh --proxy`
+  nitro ssh --proxy
+
+  # run the shell in a session that keeps running when the connection is lost
+  nitro ssh tutorial.nitro --persist
+
+  # resume the session after the connection was lost
+  nitro ssh tutorial.nitro --reattach`
+
+// NewCommand returns the ssh command to get a shell in a container. The command is context aware and if
+// it is not in a known project directory, it will provide a list of known sites to the user.
+func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outputer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "ssh",
+		Short:   "Opens a shell in a container.",
+		Example: exampleText,
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			cfg, err := config.Load(home)
+			if err != nil {
+				return nil, cobra.ShellCompDirectiveDefault
+			}
+
+			var options []string
+			for _, s := range cfg.Sites {
+				options = append(options, s.Hostname)
+			}
+
+			return options, cobra.ShellCompDirectiveNoFileComp
+		},
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			// is the docker api alive?
+			if _, err := docker.Ping(cmd.Context()); err != nil {
+				return fmt.Errorf("Couldn’t connect to Docker; please make sure Docker is running.")
+			}
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// get the current working directory
+			wd, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+
+			// load the config
+			cfg, err := config.Load(home)
+			if err != nil {
+				return err
+			}
+
+			var site string
+			if len(args) > 0 {
+				site = strings.TrimSpace(args[0])
+			}
+
+			// create a filter for the environment
+			filter := filters.NewArgs()
+			filter.Add("label", containerlabels.Nitro+"="+environment.Label())
+
+			switch ProxyContainer {
+			case true:
+				// file by the container name
+				filter.Add("name", environment.Proxy())
+			default:
+				// get a context aware list of sites
+				sites := cfg.ListOfSitesByDirectory(home, wd)
+
+				// create the options for the sites
+				var options []string
+				for _, s := range sites {
+					options = append(options, s.Hostname)
+				}
+
+				// did they ask for a specific site?
+				switch site != "" {
+				case true:
+					s, err := cfg.FindSiteByHostName(site)
+					if err != nil {
+						return err
+					}
+
+					// add the label to get the site
+					filter.Add("label", containerlabels.Host+"="+s.Hostname)
+				default:
+					// if there are found sites we want to show or connect to the first one, otherwise prompt for which site to connect to.
+					switch len(sites) {
+					case 1:
+						output.Info("connecting to", sites[0].Hostname)
+
+						// add the label to get the site
+						filter.Add("label", containerlabels.Host+"="+sites[0].Hostname)
+					default:
+						// prompt for the site to ssh into
+						selected, err := prompt.Select(cmd.InOrStdin(), "Select a site: ", options, output)
+						if err != nil {
+							return err
+						}
+
+						// add the label to get the site
+						filter.Add("label", containerlabels.Host+"="+sites[selected].Hostname)
+					}
+				}
+			}
+
+			// find the containers but limited to the site label
+			containers, err := docker.ContainerList(cmd.Context(), types.ContainerListOptions{Filters: filter, All: true})
+			if err != nil {
+				return err
+			}
+
+			// are there any containers??
+			if len(containers) == 0 {
+				return fmt.Errorf("unable to find an matching site")
+			}
+
+			// start the container if its not running
+			if containers[0].State != "running" {
+				for _, command := range cmd.Root().Commands() {
+					if command.Use == "start" {
+						if err := command.RunE(cmd, []string{}); err != nil {
+							return err
+						}
+					}
+				}
+			}
+
+			// check if the root user should be used
+			containerUser := "root"
+			if !RootUser && !ProxyContainer {
+				containerUser, err = defaultUser()
+				if err != nil {
+					return err
+				}
+			}
+
+			// show a notice about changes
+			if containerUser == "root" {
+				output.Info("using root… system changes are ephemeral…")
+			}
+
+			shell := []string{"sh"}
+			if Persist || Reattach {
+				// the multiplexer needs a terminal to attach to
+				if !term.IsTerminal(os.Stdin.Fd()) {
+					return fmt.Errorf("the --persist and --reattach flags need a terminal")
+				}
+
+				if err := ensureTmux(cmd.Context(), docker, containers[0].ID, output); err != nil {
+					return err
+				}
+
+				exists := hasSession(cmd.Context(), docker, containers[0].ID, containerUser)
+				if Reattach && !exists {
+					return fmt.Errorf("there is no session to reattach to, run `nitro ssh --persist` to start one")
+				}
+
+				if !exists {
+					output.Info("starting a persistent session… detach with ctrl+b d and run `nitro ssh --reattach` to resume it")
+				}
+
+				shell = sessionCommand()
+			}
+
+			_, err = containerexec.Interactive(cmd.Context(), docker, containers[0].ID, containerUser, shell, cmd.InOrStdin(), cmd.OutOrStdout(), cmd.ErrOrStderr())
+
+			return err
+		},
+	}
+
+	cmd.Flags().BoolVar(&RootUser, "root", false, "connect as root user")
+	cmd.Flags().BoolVar(&ProxyContainer, "proxy", false, "connect to proxy container")
+	cmd.Flags().BoolVar(&Persist, "persist", false, "run the shell in a session that survives disconnects")
+	cmd.Flags().BoolVar(&Reattach, "reattach", false, "resume the session from a previous connection")
+
+	return cmd
+}
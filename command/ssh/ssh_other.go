@@ -0,0 +1,8 @@
+// +build !linux
+
+package ssh
+
+// defaultUser returns the user to run the shell as.
+func defaultUser() (string, error) {
+	return "www-data", nil
+}
@@ -1,6 +1,7 @@
 package start
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
@@ -9,6 +10,7 @@ import (
 	"github.com/docker/docker/client"
 	"github.com/spf13/cobra"
 
+	"github.com/craftcms/nitro/command/internal/environments"
 	"github.com/craftcms/nitro/pkg/config"
 	"github.com/craftcms/nitro/pkg/containerlabels"
 	"github.com/craftcms/nitro/pkg/terminal"
@@ -20,14 +22,22 @@ var (
 )
 
 const exampleText = `  # start all containers
-  nitro start`
+  nitro start
 
-// NewCommand returns the command used to start all of the containers for an environment.
+  # start a single site and the containers it depends on
+  nitro start tutorial.nitro
+
+  # start the containers of every environment in ~/.nitro
+  nitro --all-envs start`
+
+// NewCommand returns the command used to start all of the containers for an environment. The
+// databases and services are started before the proxy and the sites.
 func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outputer) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "start",
 		Short:   "Starts containers.",
 		Example: exampleText,
+		Args:    environments.Args("started"),
 		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 			cfg, err := config.Load(home)
 			if err != nil {
@@ -60,7 +70,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 			// get all the containers using a filter, we only want to stop containers which
 			// have the environment label
 			filter := filters.NewArgs()
-			filter.Add("label", containerlabels.Nitro)
+			filter.Add("label", environments.Label())
 
 			// get all of the container
 			containers, err := docker.ContainerList(ctx, types.ContainerListOptions{All: true, Filters: filter})
@@ -73,46 +83,58 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 				return ErrNoContainers
 			}
 
+			if environments.All {
+				return all(ctx, home, docker, containers, output)
+			}
+
 			output.Info("Starting Nitro…")
 
-			// start each environment container
-			for _, c := range containers {
-				// don't start composer or npm containers
-				if c.Labels[containerlabels.Type] == "composer" || c.Labels[containerlabels.Type] == "npm" {
-					continue
-				}
+			if err := start(ctx, docker, containers, site, output); err != nil {
+				return err
+			}
+
+			output.Info("Nitro started 👍")
+
+			return nil
+		},
+	}
 
-				// identify the type of container
-				containerType := containerlabels.Identify(c)
+	return cmd
+}
 
-				hostname := strings.TrimLeft(c.Names[0], "/")
+// start starts the containers, the databases and services are started before the proxy
+// and sites that use them. When site is set, the containers of the other sites are skipped.
+func start(ctx context.Context, docker client.ContainerAPIClient, containers []types.Container, site string, output terminal.Outputer) error {
+	containerlabels.StartOrder(containers)
 
-				// if the user wants a single site only, skip all of the other sites
-				if site != "" && hostname != site && containerType == "site" {
-					continue
-				}
+	for _, c := range containers {
+		// don't start composer or npm containers
+		if c.Labels[containerlabels.Type] == "composer" || c.Labels[containerlabels.Type] == "npm" {
+			continue
+		}
 
-				// if the container is already running
-				if c.State == "running" {
-					output.Success(hostname)
-					continue
-				}
+		hostname := strings.TrimLeft(c.Names[0], "/")
 
-				output.Pending("starting", hostname)
+		// if the user wants a single site only, skip all of the other sites
+		if site != "" && c.Labels[containerlabels.Host] != "" && c.Labels[containerlabels.Host] != site {
+			continue
+		}
 
-				// start the container
-				if err := docker.ContainerStart(ctx, c.ID, types.ContainerStartOptions{}); err != nil {
-					return fmt.Errorf("unable to start container %s: %w", hostname, err)
-				}
+		// if the container is already running
+		if c.State == "running" {
+			output.Success(hostname)
+			continue
+		}
 
-				output.Done()
-			}
+		output.Pending("starting", hostname)
 
-			output.Info("Nitro started 👍")
+		if err := docker.ContainerStart(ctx, c.ID, types.ContainerStartOptions{}); err != nil {
+			output.Warning()
+			return fmt.Errorf("unable to start container %s: %w", hostname, err)
+		}
 
-			return nil
-		},
+		output.Done()
 	}
 
-	return cmd
+	return nil
 }
@@ -55,6 +55,10 @@ func (spy spyOutputer) Warning() {
 
 }
 
+func (spy spyOutputer) JSON(v interface{}) error {
+	return nil
+}
+
 // inspired by the following from the Docker docker package: https://github.com/moby/moby/blob/master/client/network_create_test.go
 func newMockDockerClient(networks []types.NetworkResource, containers []types.Container, volumes []*types.Volume) *mockDockerClient {
 	return &mockDockerClient{
@@ -6,6 +6,8 @@ import (
 	"testing"
 
 	"github.com/docker/docker/api/types"
+
+	"github.com/craftcms/nitro/pkg/containerlabels"
 )
 
 func TestStartSuccess(t *testing.T) {
@@ -95,3 +97,39 @@ func TestStartErrorsWhenThereAreNoContainers(t *testing.T) {
 		t.Errorf("expected the error to not be nil")
 	}
 }
+
+func TestStartOrdersContainers(t *testing.T) {
+	// Arrange
+	containers := []types.Container{
+		{ID: "site", Names: []string{"/craft.nitro"}, Labels: map[string]string{containerlabels.Host: "craft.nitro"}},
+		{ID: "other", Names: []string{"/other.nitro"}, Labels: map[string]string{containerlabels.Host: "other.nitro"}},
+		{ID: "proxy", Names: []string{"/nitro-proxy"}, Labels: map[string]string{containerlabels.Proxy: "true"}},
+		{ID: "redis", Names: []string{"/redis.service.nitro"}, Labels: map[string]string{containerlabels.Type: "redis"}},
+		{ID: "mysql", Names: []string{"/mysql-8.0-3306.database.nitro"}, Labels: map[string]string{containerlabels.DatabaseEngine: "mysql"}},
+	}
+	mock := newMockDockerClient(nil, containers, nil)
+	output := &spyOutputer{}
+	expected := []string{
+		"  … starting mysql-8.0-3306.database.nitro ",
+		"  … starting redis.service.nitro ",
+		"  … starting nitro-proxy ",
+		"  … starting craft.nitro ",
+	}
+	home, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	cmd := NewCommand(home, mock, output)
+	err = cmd.RunE(cmd, []string{"craft.nitro"})
+
+	// Assert
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(output.succesess, expected) {
+		t.Errorf("expected the containers to start in order, got \n%v\nwant:\n%v", output.succesess, expected)
+	}
+}
@@ -0,0 +1,39 @@
+package stop
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+
+	"github.com/craftcms/nitro/command/internal/environments"
+	"github.com/craftcms/nitro/pkg/terminal"
+)
+
+// all stops the containers of every environment in parallel. Containers of environments
+// without a config are stopped once every environment has stopped.
+func all(ctx context.Context, home string, docker client.ContainerAPIClient, containers []types.Container, output terminal.Outputer) error {
+	envs, err := environments.List(home)
+	if err != nil {
+		return err
+	}
+
+	owned, unowned := environments.Partition(envs, containers)
+
+	output.Info(fmt.Sprintf("Stopping %d environments…", len(envs)))
+
+	if err := environments.Run(envs, output, func(env environments.Environment, output terminal.Outputer) error {
+		return stop(ctx, docker, owned[env.Name], output)
+	}); err != nil {
+		return err
+	}
+
+	if err := stop(ctx, docker, unowned, output); err != nil {
+		return err
+	}
+
+	output.Info("Nitro shutdown 😴")
+
+	return nil
+}
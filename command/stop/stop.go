@@ -1,6 +1,7 @@
 package stop
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
@@ -9,6 +10,7 @@ import (
 	"github.com/docker/docker/client"
 	"github.com/spf13/cobra"
 
+	"github.com/craftcms/nitro/command/internal/environments"
 	"github.com/craftcms/nitro/pkg/config"
 	"github.com/craftcms/nitro/pkg/containerlabels"
 	"github.com/craftcms/nitro/pkg/terminal"
@@ -17,6 +19,9 @@ import (
 const exampleText = `  # stop all containers
   nitro stop
 
+  # stop the containers of every environment in ~/.nitro
+  nitro --all-envs stop
+
   # stop an individual site
   nitro stop tutorial.nitro`
 
@@ -27,6 +32,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 		Use:     "stop",
 		Short:   "Stops containers.",
 		Example: exampleText,
+		Args:    environments.Args("stopped"),
 		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 			cfg, err := config.Load(home)
 			if err != nil {
@@ -51,7 +57,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 			// get all the containers using a filter, we only want to stop containers which
 			// have the environment label
 			filter := filters.NewArgs()
-			filter.Add("label", containerlabels.Nitro)
+			filter.Add("label", environments.Label())
 
 			// get all of the container
 			containers, err := docker.ContainerList(ctx, types.ContainerListOptions{Filters: filter})
@@ -65,25 +71,26 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 				return nil
 			}
 
-			output.Info("Stopping Nitro…")
+			if environments.All {
+				return all(ctx, home, docker, containers, output)
+			}
 
-			// stop each environment container
-			for _, c := range containers {
-				hostname := strings.TrimLeft(c.Names[0], "/")
+			output.Info("Stopping Nitro…")
 
-				// if the user wants a single site only, skip all of the other sites
-				if site != "" && hostname != site {
-					continue
+			// if the user wants a single site only, skip all of the other sites
+			if site != "" {
+				var matched []types.Container
+				for _, c := range containers {
+					if strings.TrimLeft(c.Names[0], "/") == site {
+						matched = append(matched, c)
+					}
 				}
 
-				output.Pending("stopping", hostname)
-
-				// stop the container
-				if err := docker.ContainerStop(ctx, c.ID, nil); err != nil {
-					return fmt.Errorf("unable to stop container %s: %w", hostname, err)
-				}
+				containers = matched
+			}
 
-				output.Done()
+			if err := stop(ctx, docker, containers, output); err != nil {
+				return err
 			}
 
 			output.Info("Nitro shutdown 😴")
@@ -94,3 +101,25 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 
 	return cmd
 }
+
+// stop stops the containers, the sites are stopped before the proxy, services, and
+// databases they use.
+func stop(ctx context.Context, docker client.ContainerAPIClient, containers []types.Container, output terminal.Outputer) error {
+	containerlabels.StartOrder(containers)
+
+	for i := len(containers) - 1; i >= 0; i-- {
+		c := containers[i]
+		hostname := strings.TrimLeft(c.Names[0], "/")
+
+		output.Pending("stopping", hostname)
+
+		if err := docker.ContainerStop(ctx, c.ID, nil); err != nil {
+			output.Warning()
+			return fmt.Errorf("unable to stop container %s: %w", hostname, err)
+		}
+
+		output.Done()
+	}
+
+	return nil
+}
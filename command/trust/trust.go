@@ -1,8 +1,8 @@
 package trust
 
 import (
-	"archive/tar"
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -15,10 +15,15 @@ import (
 	"github.com/docker/docker/client"
 	"github.com/spf13/cobra"
 
+	"github.com/craftcms/nitro/pkg/certificate"
 	"github.com/craftcms/nitro/pkg/certinstall"
+	nitroclient "github.com/craftcms/nitro/pkg/client"
 	"github.com/craftcms/nitro/pkg/config"
 	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/environment"
+	"github.com/craftcms/nitro/pkg/proxycontainer"
 	"github.com/craftcms/nitro/pkg/terminal"
+	"github.com/craftcms/nitro/protob"
 )
 
 var (
@@ -26,16 +31,18 @@ var (
 	ErrNoContainers = fmt.Errorf("there are no running containers")
 )
 
-const (
-	certificatePath = "/data/caddy/pki/authorities/local/root.crt"
-	exampleText     = `  # get the root certificate for the proxy
-  nitro trust`
-)
+const exampleText = `  # trust the root certificate for the proxy
+  nitro trust
+
+  # show the root certificate without installing it
+  nitro trust --show`
 
 // NewCommand returns `trust` to retrieve the certificates from the nitro proxy and install on the
 // host machine. The CA is used to sign certificates for websites and adding the certificate
 // to the system allows TLS connections to be considered valid and trusted from the container.
-func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outputer) *cobra.Command {
+// The certificate is added to the macOS keychain, the Linux ca-certificates, or the Windows
+// certificate store, as well as the NSS databases used by Firefox and Chrome on Linux.
+func NewCommand(home string, docker client.CommonAPIClient, nitrod protob.NitroClient, output terminal.Outputer) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "trust",
 		Short:   "Trusts Nitro certificates on the host machine.",
@@ -49,113 +56,134 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 				ctx = cmd.Parent().Context()
 			}
 
-			// find the nitro proxy for the environment
-			filter := filters.NewArgs()
-			filter.Add("label", containerlabels.Nitro)
-			filter.Add("label", containerlabels.Proxy+"=true")
+			show := cmd.Flag("show").Value.String() == "true" || cmd.Flag("output-only").Value.String() == "true"
 
-			// find the container, should only be one
-			containers, err := docker.ContainerList(ctx, types.ContainerListOptions{All: true, Filters: filter})
-			if err != nil {
-				return fmt.Errorf("unable to get the list of containers, %w", err)
-			}
+			return Run(ctx, home, docker, nitrod, output, show)
+		},
+	}
 
-			// make sure there is at least one container
-			if len(containers) == 0 {
-				return ErrNoContainers
-			}
+	cmd.Flags().Bool("show", false, "show the certificate without installing")
+	cmd.Flags().Bool("output-only", false, "show the certificate without importing")
+	cmd.Flags().MarkDeprecated("output-only", "use --show instead")
 
-			containerID := containers[0].ID
+	return cmd
+}
 
-			// get the contents of the certificate from the container
-			output.Pending("getting Nitro’s root site certificate")
+// Run gets the root certificate from the proxy container and installs it on the host
+// machine. If outputOnly is true, the certificate is shown instead of being installed.
+func Run(ctx context.Context, home string, docker client.CommonAPIClient, nitrod protob.NitroClient, output terminal.Outputer, outputOnly bool) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
 
-			// verify the file exists in the container
-			for {
-				stat, err := docker.ContainerStatPath(ctx, containerID, certificatePath)
-				if err != nil {
-					continue
-				}
+	// find the nitro proxy for the environment
+	filter := filters.NewArgs()
+	filter.Add("label", containerlabels.Nitro+"="+environment.Label())
+	filter.Add("label", containerlabels.Proxy+"=true")
 
-				if stat.Name != "" {
-					break
-				}
-			}
+	// find the container, should only be one
+	containers, err := docker.ContainerList(ctx, types.ContainerListOptions{All: true, Filters: filter})
+	if err != nil {
+		return fmt.Errorf("unable to get the list of containers, %w", err)
+	}
 
-			// copy the file from the container
-			rdr, stat, err := docker.CopyFromContainer(ctx, containerID, certificatePath)
-			if err != nil || !stat.Mode.IsRegular() {
-				output.Warning()
-				return fmt.Errorf("unable to get the certificate from the container, %w", err)
-			}
+	// make sure there is at least one container
+	if len(containers) == 0 {
+		return ErrNoContainers
+	}
 
-			// the file is in a tar format
-			buf := new(bytes.Buffer)
-			tr := tar.NewReader(rdr)
-			for {
-				_, err := tr.Next()
-				// if end of tar archive
-				if err == io.EOF {
-					break
-				}
-				if err != nil {
-					return err
-				}
-
-				if _, err := buf.ReadFrom(tr); err != nil {
-					return err
-				}
-			}
+	containerID := containers[0].ID
 
-			// if we are only outputting the certificate to stdout
-			if cmd.Flag("output-only").Value.String() == "true" {
-				output.Done()
+	// get the contents of the certificate from the container
+	output.Pending("getting Nitro’s root site certificate")
 
-				output.Info(buf.String())
+	data, err := rootCertificate(ctx, docker, nitrod, containerID)
+	if err != nil {
+		output.Warning()
+		return err
+	}
 
-				return nil
-			}
+	buf := bytes.NewBuffer(data)
 
-			// create a temp file
-			temp, err := ioutil.TempFile(os.TempDir(), "nitro-local-root-ca")
-			if err != nil {
-				return fmt.Errorf("unable to create a temporary file, %w", err)
-			}
-			defer temp.Close()
+	// if we are only outputting the certificate to stdout
+	if outputOnly {
+		output.Done()
 
-			// write the certificate to the temporary file
-			if _, err := temp.Write(buf.Bytes()); err != nil {
-				return fmt.Errorf("unable to write the certificate to the temporary file, %w", err)
-			}
+		output.Info(buf.String())
 
-			output.Done()
+		return nil
+	}
 
-			// copy the certificate into the nitro dir
-			cert, err := os.Create(filepath.Join(home, config.DirectoryName, "nitro.crt"))
-			if err != nil {
-				return err
-			}
-			defer cert.Close()
+	// create a temp file
+	temp, err := ioutil.TempFile(os.TempDir(), "nitro-local-root-ca")
+	if err != nil {
+		return fmt.Errorf("unable to create a temporary file, %w", err)
+	}
+	defer temp.Close()
 
-			// copy the contents into the nitro directory
-			if _, err := io.Copy(cert, buf); err != nil {
-				return err
-			}
+	// write the certificate to the temporary file
+	if _, err := temp.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("unable to write the certificate to the temporary file, %w", err)
+	}
 
-			output.Info("Installing certificate (you might be prompted for your password)")
+	output.Done()
 
-			// install the certificate
-			if err := certinstall.Install(temp.Name(), runtime.GOOS); err != nil {
-				return err
-			}
+	// copy the certificate into the nitro dir
+	certPath := filepath.Join(home, config.DirectoryName, "nitro.crt")
+	cert, err := os.Create(certPath)
+	if err != nil {
+		return err
+	}
+	defer cert.Close()
 
-			output.Info("Nitro certificates are now trusted 🔒")
+	// copy the contents into the nitro directory
+	if _, err := io.Copy(cert, buf); err != nil {
+		return err
+	}
 
-			return nil
-		},
+	output.Info("Installing certificate (you might be prompted for your password)")
+
+	// install the certificate
+	if err := certinstall.Install(temp.Name(), runtime.GOOS); err != nil {
+		return err
 	}
 
-	cmd.Flags().Bool("output-only", false, "show the certificate without importing")
+	// add the certificate to the browsers that do not use the system certificates
+	if databases := certinstall.NSSDatabases(home, runtime.GOOS); len(databases) > 0 {
+		output.Pending("adding the certificate to Firefox")
 
-	return cmd
+		if err := certinstall.InstallNSS(certPath, databases); err != nil {
+			output.Warning()
+			output.Info("  " + err.Error())
+		} else {
+			output.Done()
+		}
+	}
+
+	output.Info("Nitro certificates are now trusted 🔒")
+
+	return nil
+}
+
+// rootCertificate asks the API in the proxy container for the root certificate. Proxies
+// that are not ready or were created by an older version do not return the certificate,
+// so it is copied from the proxy container instead.
+func rootCertificate(ctx context.Context, docker client.CommonAPIClient, nitrod protob.NitroClient, containerID string) ([]byte, error) {
+	if nitrod != nil {
+		if data, err := nitroclient.RootCertificate(ctx, nitrod); err == nil && len(data) > 0 {
+			return data, nil
+		}
+	}
+
+	// wait for the proxy to create the certificate
+	if err := proxycontainer.WaitForPath(ctx, docker, containerID, certificate.RootPath, proxycontainer.Timeout); err != nil {
+		return nil, fmt.Errorf("unable to find the certificate in the proxy container, %w", err)
+	}
+
+	data, err := certificate.Read(ctx, docker, containerID, certificate.RootPath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get the certificate from the container, %w", err)
+	}
+
+	return data, nil
 }
@@ -0,0 +1,296 @@
+package uninstall
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+	"github.com/spf13/cobra"
+
+	"github.com/craftcms/nitro/command/internal/environments"
+	"github.com/craftcms/nitro/pkg/certinstall"
+	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/environment"
+	"github.com/craftcms/nitro/pkg/ownership"
+	"github.com/craftcms/nitro/pkg/pathexists"
+	"github.com/craftcms/nitro/pkg/sudo"
+	"github.com/craftcms/nitro/pkg/terminal"
+)
+
+const exampleText = `  # remove everything nitro has created, except the protected volumes
+  nitro uninstall
+
+  # also remove the volumes marked as protected
+  nitro uninstall --include-protected`
+
+// NewCommand returns the command to remove all of nitro's containers, volumes, networks, images,
+// certificates, hosts file entries, and the config directory. It prompts for confirmation and
+// defaults to no. Unlike destroy, databases are not backed up. The volumes marked as protected
+// in the config of any environment are kept unless --include-protected is set.
+func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outputer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "uninstall",
+		Short:   "Removes everything Nitro has created.",
+		Example: exampleText,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			// is the docker api alive?
+			if _, err := docker.Ping(cmd.Context()); err != nil {
+				return fmt.Errorf("Couldn’t connect to Docker; please make sure Docker is running.")
+			}
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			dir := filepath.Join(home, config.DirectoryName)
+
+			// prompt the user for confirmation
+			confirm, err := output.Confirm(fmt.Sprintf("Are you sure? (This will remove all containers, volumes, networks, images, and %s without creating backups.)", dir), false, "")
+			if err != nil {
+				return err
+			}
+
+			if !confirm {
+				output.Info("skipping uninstall, Nitro will remain installed 😅")
+
+				return nil
+			}
+
+			// keep the protected volumes of every environment
+			protected := map[string]bool{}
+			if include, _ := cmd.Flags().GetBool("include-protected"); !include {
+				envs, err := environments.List(home)
+				if err != nil {
+					return err
+				}
+
+				for _, env := range envs {
+					for v := range env.Config.ProtectedVolumes() {
+						protected[ownership.Listed(environment.NameFromFile(env.File), v)] = true
+					}
+				}
+			}
+
+			if err := removeResources(ctx, docker, protected, output); err != nil {
+				return err
+			}
+
+			// remove the root certificate from the trust store
+			cert := filepath.Join(dir, "nitro.crt")
+			if pathexists.IsFile(cert) {
+				output.Info("Removing certificate (you might be prompted for your password)")
+
+				if err := certinstall.Uninstall(cert, runtime.GOOS); err != nil {
+					output.Info("Unable to remove the certificate,", err.Error())
+				}
+			}
+
+			if err := certinstall.UninstallNSS(certinstall.NSSDatabases(home, runtime.GOOS)); err != nil {
+				output.Info("Unable to remove the certificate from Firefox,", err.Error())
+			}
+
+			// remove the hosts file entries
+			if err := removeHosts(output); err != nil {
+				output.Info("Unable to remove the hosts file entries,", err.Error())
+			}
+
+			// remove the config directory
+			output.Pending("removing", dir)
+
+			if err := os.RemoveAll(dir); err != nil {
+				output.Warning()
+				return fmt.Errorf("unable to remove %s, %w", dir, err)
+			}
+
+			output.Done()
+
+			// the binary cannot remove itself on every platform, so show how to remove it
+			bin, err := os.Executable()
+			if err != nil {
+				bin = "nitro"
+			}
+
+			output.Info("Nitro has been uninstalled 👋")
+			output.Info("To finish, remove the nitro binary:")
+
+			switch runtime.GOOS {
+			case "windows":
+				output.Info(fmt.Sprintf("  del %q", bin))
+			default:
+				output.Info(fmt.Sprintf("  sudo rm %s", bin))
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().Bool("include-protected", false, "remove volumes marked as protected")
+
+	return cmd
+}
+
+// removeResources removes all of the containers, volumes, networks, and images for every
+// nitro environment. Images are removed last since they are used by the containers. The
+// protected volumes are not removed and are listed once the other volumes are removed.
+func removeResources(ctx context.Context, docker client.CommonAPIClient, protected map[string]bool, output terminal.Outputer) error {
+	filter := filters.NewArgs()
+	filter.Add("label", containerlabels.Nitro)
+
+	containers, err := docker.ContainerList(ctx, types.ContainerListOptions{All: true, Filters: filter})
+	if err != nil {
+		return fmt.Errorf("unable to list the containers, %w", err)
+	}
+
+	// track the images used by the containers
+	images := map[string]string{}
+
+	if len(containers) > 0 {
+		output.Info("Removing containers…")
+
+		timeout := 5 * time.Second
+		for _, c := range containers {
+			name := strings.TrimLeft(c.Names[0], "/")
+			images[c.ImageID] = c.Image
+
+			output.Pending("removing", name)
+
+			if c.State == "running" {
+				if err := docker.ContainerStop(ctx, c.ID, &timeout); err != nil {
+					output.Warning()
+					return fmt.Errorf("unable to stop the container, %w", err)
+				}
+			}
+
+			if err := docker.ContainerRemove(ctx, c.ID, types.ContainerRemoveOptions{Force: true}); err != nil {
+				output.Warning()
+				return fmt.Errorf("unable to remove the container, %w", err)
+			}
+
+			output.Done()
+		}
+	}
+
+	volumes, err := docker.VolumeList(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("unable to list the volumes, %w", err)
+	}
+
+	if len(volumes.Volumes) > 0 {
+		output.Info("Removing volumes…")
+
+		var skipped []string
+		for _, v := range volumes.Volumes {
+			if protected[v.Name] {
+				skipped = append(skipped, v.Name)
+				continue
+			}
+
+			output.Pending("removing", v.Name)
+
+			if err := docker.VolumeRemove(ctx, v.Name, true); err != nil {
+				output.Warning()
+				return fmt.Errorf("unable to remove the volume, %w", err)
+			}
+
+			output.Done()
+		}
+
+		if len(skipped) > 0 {
+			output.Info("Kept the protected volumes, use --include-protected to remove them:")
+
+			for _, v := range skipped {
+				output.Info("  " + v)
+			}
+		}
+	}
+
+	networks, err := docker.NetworkList(ctx, types.NetworkListOptions{Filters: filter})
+	if err != nil {
+		return fmt.Errorf("unable to list the networks, %w", err)
+	}
+
+	if len(networks) > 0 {
+		output.Info("Removing networks…")
+
+		for _, n := range networks {
+			output.Pending("removing", n.Name)
+
+			if err := docker.NetworkRemove(ctx, n.ID); err != nil {
+				output.Warning()
+				return fmt.Errorf("unable to remove the network, %w", err)
+			}
+
+			output.Done()
+		}
+	}
+
+	// add the images with the nitro label that are not used by a container
+	labeled, err := docker.ImageList(ctx, types.ImageListOptions{Filters: filter})
+	if err != nil {
+		return fmt.Errorf("unable to list the images, %w", err)
+	}
+
+	for _, i := range labeled {
+		name := i.ID
+		if len(i.RepoTags) > 0 {
+			name = i.RepoTags[0]
+		}
+
+		images[i.ID] = name
+	}
+
+	if len(images) > 0 {
+		output.Info("Removing images…")
+
+		for id, name := range images {
+			output.Pending("removing", name)
+
+			// images can be shared with other projects, so only warn if one can't be removed
+			if _, err := docker.ImageRemove(ctx, id, types.ImageRemoveOptions{PruneChildren: true}); err != nil {
+				output.Warning()
+				output.Info("  unable to remove", name, err.Error())
+				continue
+			}
+
+			output.Done()
+		}
+	}
+
+	return nil
+}
+
+// removeHosts removes the nitro entries from the hosts file using the hosts command.
+func removeHosts(output terminal.Outputer) error {
+	nitro, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("unable to locate the nitro path, %w", err)
+	}
+
+	switch runtime.GOOS {
+	case "windows":
+		// windows users should be running as admin, so just execute the hosts command as is
+		c := exec.Command(nitro, "hosts", "remove")
+
+		c.Stdout = os.Stdout
+		c.Stderr = os.Stderr
+
+		return c.Run()
+	default:
+		output.Info("Updating hosts file (you might be prompted for your password)")
+
+		return sudo.Run(nitro, "nitro", "hosts", "remove")
+	}
+}
@@ -0,0 +1,173 @@
+package uninstall
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	volumetypes "github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/client"
+
+	"github.com/craftcms/nitro/pkg/terminal"
+)
+
+func TestRemoveResources(t *testing.T) {
+	spy := &mockClient{
+		containers: []types.Container{
+			{ID: "proxy", Names: []string{"/nitro-proxy"}, Image: "craftcms/nitro-proxy:develop", ImageID: "proxy-image", State: "running"},
+			{ID: "site", Names: []string{"/craft.nitro"}, Image: "craftcms/nginx:8.0-dev", ImageID: "site-image", State: "exited"},
+		},
+		volumes:  []*types.Volume{{Name: "nitro"}, {Name: "mysql-8.0-3306.database.nitro"}},
+		networks: []types.NetworkResource{{ID: "network", Name: "nitro-network"}},
+		images:   []types.ImageSummary{{ID: "proxy-image", RepoTags: []string{"craftcms/nitro-proxy:develop"}}, {ID: "old-proxy-image"}},
+		imageRemoveErrors: map[string]error{
+			"site-image": fmt.Errorf("image is being used by another container"),
+		},
+	}
+
+	if err := removeResources(context.Background(), spy, nil, &spyOutputer{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"proxy"}; !reflect.DeepEqual(spy.stopped, want) {
+		t.Errorf("expected only running containers to be stopped, got %v want %v", spy.stopped, want)
+	}
+
+	if want := []string{"proxy", "site"}; !reflect.DeepEqual(spy.removedContainers, want) {
+		t.Errorf("expected the containers to be removed, got %v want %v", spy.removedContainers, want)
+	}
+
+	if want := []string{"nitro", "mysql-8.0-3306.database.nitro"}; !reflect.DeepEqual(spy.removedVolumes, want) {
+		t.Errorf("expected the volumes to be removed, got %v want %v", spy.removedVolumes, want)
+	}
+
+	if want := []string{"network"}; !reflect.DeepEqual(spy.removedNetworks, want) {
+		t.Errorf("expected the networks to be removed, got %v want %v", spy.removedNetworks, want)
+	}
+
+	// images that fail to be removed are still attempted
+	sort.Strings(spy.removedImages)
+	if want := []string{"old-proxy-image", "proxy-image", "site-image"}; !reflect.DeepEqual(spy.removedImages, want) {
+		t.Errorf("expected the images to be removed, got %v want %v", spy.removedImages, want)
+	}
+}
+
+func TestRemoveResources_Protected(t *testing.T) {
+	spy := &mockClient{
+		volumes: []*types.Volume{{Name: "nitro"}, {Name: "mysql-8.0-3306.database.nitro"}, {Name: "postgres-13-5432.database.nitro"}},
+	}
+	output := &spyOutputer{}
+
+	protected := map[string]bool{"mysql-8.0-3306.database.nitro": true}
+	if err := removeResources(context.Background(), spy, protected, output); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"nitro", "postgres-13-5432.database.nitro"}; !reflect.DeepEqual(spy.removedVolumes, want) {
+		t.Errorf("expected the protected volumes to be kept, got %v want %v", spy.removedVolumes, want)
+	}
+
+	want := []string{
+		"Removing volumes…",
+		"Kept the protected volumes, use --include-protected to remove them:",
+		"  mysql-8.0-3306.database.nitro",
+	}
+	if !reflect.DeepEqual(output.infos, want) {
+		t.Errorf("expected the kept volumes to be listed, got %q want %q", output.infos, want)
+	}
+}
+
+type mockClient struct {
+	client.CommonAPIClient
+
+	containers []types.Container
+	volumes    []*types.Volume
+	networks   []types.NetworkResource
+	images     []types.ImageSummary
+
+	imageRemoveErrors map[string]error
+
+	stopped           []string
+	removedContainers []string
+	removedVolumes    []string
+	removedNetworks   []string
+	removedImages     []string
+}
+
+func (c *mockClient) ContainerList(ctx context.Context, options types.ContainerListOptions) ([]types.Container, error) {
+	return c.containers, nil
+}
+
+func (c *mockClient) ContainerStop(ctx context.Context, containerID string, timeout *time.Duration) error {
+	c.stopped = append(c.stopped, containerID)
+	return nil
+}
+
+func (c *mockClient) ContainerRemove(ctx context.Context, containerID string, options types.ContainerRemoveOptions) error {
+	c.removedContainers = append(c.removedContainers, containerID)
+	return nil
+}
+
+func (c *mockClient) VolumeList(ctx context.Context, filter filters.Args) (volumetypes.VolumeListOKBody, error) {
+	return volumetypes.VolumeListOKBody{Volumes: c.volumes}, nil
+}
+
+func (c *mockClient) VolumeRemove(ctx context.Context, volumeID string, force bool) error {
+	c.removedVolumes = append(c.removedVolumes, volumeID)
+	return nil
+}
+
+func (c *mockClient) NetworkList(ctx context.Context, options types.NetworkListOptions) ([]types.NetworkResource, error) {
+	return c.networks, nil
+}
+
+func (c *mockClient) NetworkRemove(ctx context.Context, networkID string) error {
+	c.removedNetworks = append(c.removedNetworks, networkID)
+	return nil
+}
+
+func (c *mockClient) ImageList(ctx context.Context, options types.ImageListOptions) ([]types.ImageSummary, error) {
+	return c.images, nil
+}
+
+func (c *mockClient) ImageRemove(ctx context.Context, imageID string, options types.ImageRemoveOptions) ([]types.ImageDeleteResponseItem, error) {
+	c.removedImages = append(c.removedImages, imageID)
+	return nil, c.imageRemoveErrors[imageID]
+}
+
+type spyOutputer struct {
+	infos []string
+}
+
+func (spy spyOutputer) Ask(message, fallback, sep string, validator terminal.Validator) (string, error) {
+	return fallback, nil
+}
+
+func (spy spyOutputer) Confirm(message string, fallback bool, sep string) (bool, error) {
+	return true, nil
+}
+
+func (spy *spyOutputer) Info(s ...string) {
+	spy.infos = append(spy.infos, strings.Join(s, " "))
+}
+
+func (spy spyOutputer) Select(r io.Reader, msg string, opts []string) (int, error) {
+	return 0, nil
+}
+
+func (spy spyOutputer) Warning() {}
+
+func (spy spyOutputer) JSON(v interface{}) error { return nil }
+
+func (spy spyOutputer) Success(s ...string) {}
+
+func (spy spyOutputer) Pending(s ...string) {}
+
+func (spy spyOutputer) Done() {}
@@ -15,6 +15,7 @@ import (
 	"github.com/craftcms/nitro/command/version"
 	"github.com/craftcms/nitro/pkg/config"
 	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/environment"
 	"github.com/craftcms/nitro/pkg/terminal"
 )
 
@@ -109,7 +110,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 
 			// create a filter for nitro containers
 			filter := filters.NewArgs()
-			filter.Add("label", containerlabels.Nitro)
+			filter.Add("label", containerlabels.Nitro+"="+environment.Label())
 
 			// get a list of containers
 			containers, err := docker.ContainerList(ctx, types.ContainerListOptions{All: true, Filters: filter})
@@ -120,7 +121,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 			// check all of the containers
 			for _, container := range containers {
 				// is this a database, service, composer, or node container?
-				if container.Labels[containerlabels.Type] == "dynamodb" || container.Labels[containerlabels.Type] == "mailhog" || container.Labels[containerlabels.Type] == "minio" || container.Labels[containerlabels.Type] == "redis" || container.Labels[containerlabels.Type] == "database" {
+				if container.Labels[containerlabels.Type] == "dynamodb" || container.Labels[containerlabels.Type] == "gotenberg" || container.Labels[containerlabels.Type] == "mailhog" || container.Labels[containerlabels.Type] == "minio" || container.Labels[containerlabels.Type] == "redis" || container.Labels[containerlabels.Type] == "redisinsight" || container.Labels[containerlabels.Type] == "database" {
 					continue
 				}
 
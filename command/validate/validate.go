@@ -67,6 +67,11 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 					if err := phpvalidator.Validate(s.Version); err != nil {
 						siteErrs = append(siteErrs, fmt.Errorf("invalid php version %s", s.Version))
 					}
+
+					// validate the sync mode
+					if s.Sync != "" && !s.UsesSync() {
+						siteErrs = append(siteErrs, fmt.Errorf("invalid sync mode %s for %s, the only sync mode is %s", s.Sync, s.Hostname, config.SyncMutagen))
+					}
 				}
 
 				if len(siteErrs) > 0 {
@@ -2,12 +2,17 @@ package version
 
 import (
 	"fmt"
+	"path/filepath"
+	"time"
 
 	"github.com/docker/docker/client"
 	"github.com/spf13/cobra"
 
+	"github.com/craftcms/nitro/pkg/config"
 	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/environment"
 	"github.com/craftcms/nitro/pkg/prompt"
+	"github.com/craftcms/nitro/pkg/releases"
 	"github.com/craftcms/nitro/pkg/terminal"
 	"github.com/craftcms/nitro/protob"
 )
@@ -17,7 +22,33 @@ import (
 // container to use to verify the gRPC API is in sync.
 var Version = "develop"
 
-var exampleText = `nitro version`
+var exampleText = `  # show the versions
+  nitro version
+
+  # show the versions as JSON
+  nitro version --json
+
+  # check if there is a new version of nitro
+  nitro version --check`
+
+// versions is the JSON output of the command
+type versions struct {
+	CLI          string `json:"cli"`
+	GRPC         string `json:"grpc"`
+	ProxyImage   string `json:"proxy_image"`
+	DockerServer string `json:"docker_server"`
+	DockerAPI    string `json:"docker_api"`
+	DockerAPIMin string `json:"docker_api_min"`
+	DockerClient string `json:"docker_client"`
+	UpdateNeeded bool   `json:"update_needed"`
+}
+
+// check is the JSON output of the command with the --check flag
+type check struct {
+	CLI             string `json:"cli"`
+	Latest          string `json:"latest"`
+	UpdateAvailable bool   `json:"update_available"`
+}
 
 // NewCommand is used to show the cli and gRPC API client version
 func NewCommand(home string, client client.CommonAPIClient, nitrod protob.NitroClient, output terminal.Outputer) *cobra.Command {
@@ -26,9 +57,18 @@ func NewCommand(home string, client client.CommonAPIClient, nitrod protob.NitroC
 		Short:   "Displays version info.",
 		Example: exampleText,
 		PreRunE: func(cmd *cobra.Command, args []string) error {
+			// checking for updates does not need the environment
+			if cmd.Flag("check").Value.String() == "true" {
+				return nil
+			}
+
 			return prompt.VerifyInit(cmd, args, home, output)
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if cmd.Flag("check").Value.String() == "true" {
+				return checkForUpdate(home, output)
+			}
+
 			var vers string
 			nitro, err := nitrod.Version(cmd.Context(), &protob.VersionRequest{})
 			if err != nil {
@@ -37,12 +77,18 @@ func NewCommand(home string, client client.CommonAPIClient, nitrod protob.NitroC
 
 			vers = nitro.GetVersion()
 
+			// the image of the proxy is shown when the proxy container exists
+			var image string
+			details, ierr := client.ContainerInspect(cmd.Context(), environment.Proxy())
+			if ierr == nil && details.Config != nil {
+				image = details.Config.Image
+			}
+
 			// make sure the version is not empty
 			if vers == "" {
 				// look up the version from the container label
-				details, err := client.ContainerInspect(cmd.Context(), "nitro-proxy")
-				if err != nil {
-					return err
+				if ierr != nil {
+					return ierr
 				}
 
 				vers = details.Config.Labels[containerlabels.ProxyVersion]
@@ -53,10 +99,27 @@ func NewCommand(home string, client client.CommonAPIClient, nitrod protob.NitroC
 				return fmt.Errorf("unable to get docker server version, %w", err)
 			}
 
+			if terminal.JSON {
+				return output.JSON(versions{
+					CLI:          Version,
+					GRPC:         vers,
+					ProxyImage:   image,
+					DockerServer: ver.Version,
+					DockerAPI:    ver.APIVersion,
+					DockerAPIMin: ver.MinAPIVersion,
+					DockerClient: client.ClientVersion(),
+					UpdateNeeded: Version != vers,
+				})
+			}
+
 			output.Info(fmt.Sprintf("View the changelog at https://github.com/craftcms/nitro/blob/%s/CHANGELOG.md\n", Version))
 
 			output.Info("Nitro CLI: \t", Version)
 			output.Info("Nitro gRPC: \t", vers)
+			if image != "" {
+				output.Info("Proxy image: \t", image)
+			}
+			output.Info("Docker: \t", ver.Version)
 			output.Info("Docker API: \t", ver.APIVersion, "("+ver.MinAPIVersion+" min)")
 			output.Info("Docker CLI: \t", client.ClientVersion())
 
@@ -71,5 +134,31 @@ func NewCommand(home string, client client.CommonAPIClient, nitrod protob.NitroC
 		},
 	}
 
+	cmd.Flags().Bool("check", false, "check if there is a new version of nitro")
+
 	return cmd
 }
+
+// checkForUpdate queries the releases API for the latest version and updates the cached
+// version that is used for the update notice after each command.
+func checkForUpdate(home string, output terminal.Outputer) error {
+	latest, err := releases.Latest(releases.NewFinder(), filepath.Join(home, config.DirectoryName), true, time.Now())
+	if err != nil {
+		return fmt.Errorf("unable to check for a new version, %w", err)
+	}
+
+	available := releases.Newer(latest, Version)
+
+	if terminal.JSON {
+		return output.JSON(check{CLI: Version, Latest: latest, UpdateAvailable: available})
+	}
+
+	if !available {
+		output.Info("Nitro", Version, "is up to date")
+		return nil
+	}
+
+	output.Info("Nitro", latest, "is available (current version is "+Version+"), run `nitro self-update` to update.")
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package workspace
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/pathexists"
+	"github.com/craftcms/nitro/pkg/terminal"
+	"github.com/craftcms/nitro/pkg/workspace"
+)
+
+func addCommand(home string, output terminal.Outputer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "add",
+		Short: "Adds a workspace.",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// use the current directory if no directory is provided
+			dir, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+
+			if len(args) > 0 {
+				dir = strings.Replace(args[0], "~", home, 1)
+			}
+
+			dir, err = filepath.Abs(dir)
+			if err != nil {
+				return err
+			}
+
+			if !pathexists.IsDirectory(dir) {
+				return fmt.Errorf("unable to find the directory %s", dir)
+			}
+
+			// load the config
+			cfg, err := config.Load(home)
+			if err != nil {
+				return err
+			}
+
+			// store the path relative to the home directory
+			if err := cfg.AddWorkspace(strings.Replace(dir, home, "~", 1)); err != nil {
+				return err
+			}
+
+			if err := cfg.Save(); err != nil {
+				return err
+			}
+
+			repos, err := workspace.Repos(dir)
+			if err != nil {
+				return err
+			}
+
+			output.Info(fmt.Sprintf("Added workspace %s with %d repositories 🗂", dir, len(repos)))
+
+			return nil
+		},
+	}
+
+	return cmd
+}
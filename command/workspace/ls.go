@@ -0,0 +1,60 @@
+package workspace
+
+import (
+	"path/filepath"
+
+	"github.com/rodaine/table"
+	"github.com/spf13/cobra"
+
+	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/terminal"
+	"github.com/craftcms/nitro/pkg/workspace"
+)
+
+func lsCommand(home string, output terminal.Outputer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "ls",
+		Short: "Lists workspaces and their repositories.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// load the config
+			cfg, err := config.Load(home)
+			if err != nil {
+				return err
+			}
+
+			dirs, err := cfg.GetWorkspaces(home)
+			if err != nil {
+				return err
+			}
+
+			if len(dirs) == 0 {
+				output.Info("There are no workspaces, run `nitro workspace add` to create one")
+
+				return nil
+			}
+
+			tbl := table.New("Workspace", "Repository", "Site").WithWriter(cmd.OutOrStdout()).WithPadding(2)
+			for _, dir := range dirs {
+				repos, err := workspace.Repos(dir)
+				if err != nil {
+					return err
+				}
+
+				for _, r := range repos {
+					hostname := "-"
+					if s := workspace.Site(home, r, cfg.Sites); s != nil {
+						hostname = s.Hostname
+					}
+
+					tbl.AddRow(dir, filepath.Base(r), hostname)
+				}
+			}
+
+			tbl.Print()
+
+			return nil
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,65 @@
+package workspace
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/terminal"
+	"github.com/craftcms/nitro/pkg/workspace"
+)
+
+func pullCommand(home string, output terminal.Outputer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pull",
+		Short: "Pulls changes for each repository.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// load the config
+			cfg, err := config.Load(home)
+			if err != nil {
+				return err
+			}
+
+			repos, err := workspace.AllRepos(home, cfg)
+			if err != nil {
+				return err
+			}
+
+			if len(repos) == 0 {
+				output.Info("There are no repositories in the workspaces")
+
+				return nil
+			}
+
+			output.Info("Pulling repositories…")
+
+			var failed int
+			for _, r := range repos {
+				output.Pending("pulling", filepath.Base(r))
+
+				if _, err := workspace.Pull(r); err != nil {
+					output.Warning()
+					output.Info(err.Error())
+
+					failed++
+
+					continue
+				}
+
+				output.Done()
+			}
+
+			if failed > 0 {
+				return fmt.Errorf("unable to pull %d of %d repositories", failed, len(repos))
+			}
+
+			output.Info("Repositories are up to date 🤘")
+
+			return nil
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,58 @@
+package workspace
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/prompt"
+	"github.com/craftcms/nitro/pkg/terminal"
+)
+
+func removeCommand(home string, output terminal.Outputer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "remove",
+		Short: "Removes a workspace.",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// load the config
+			cfg, err := config.Load(home)
+			if err != nil {
+				return err
+			}
+
+			if len(cfg.Workspaces) == 0 {
+				output.Info("There are no workspaces to remove")
+
+				return nil
+			}
+
+			// prompt for the workspace if not provided
+			var dir string
+			switch len(args) {
+			case 1:
+				dir = args[0]
+			default:
+				selected, err := prompt.Select(cmd.InOrStdin(), "Select a workspace to remove: ", cfg.Workspaces, output)
+				if err != nil {
+					return err
+				}
+
+				dir = cfg.Workspaces[selected]
+			}
+
+			if err := cfg.RemoveWorkspace(dir); err != nil {
+				return err
+			}
+
+			if err := cfg.Save(); err != nil {
+				return err
+			}
+
+			output.Info("Removed workspace", dir)
+
+			return nil
+		},
+	}
+
+	return cmd
+}
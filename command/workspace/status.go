@@ -0,0 +1,94 @@
+package workspace
+
+import (
+	"path/filepath"
+	"strconv"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+	"github.com/rodaine/table"
+	"github.com/spf13/cobra"
+
+	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/environment"
+	"github.com/craftcms/nitro/pkg/terminal"
+	"github.com/craftcms/nitro/pkg/workspace"
+)
+
+func statusCommand(home string, docker client.CommonAPIClient, output terminal.Outputer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Shows the status of each repository.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// load the config
+			cfg, err := config.Load(home)
+			if err != nil {
+				return err
+			}
+
+			repos, err := workspace.AllRepos(home, cfg)
+			if err != nil {
+				return err
+			}
+
+			if len(repos) == 0 {
+				output.Info("There are no repositories in the workspaces")
+
+				return nil
+			}
+
+			// get the state of all the site containers
+			filter := filters.NewArgs()
+			filter.Add("label", containerlabels.Nitro+"="+environment.Label())
+
+			containers, err := docker.ContainerList(cmd.Context(), types.ContainerListOptions{All: true, Filters: filter})
+			if err != nil {
+				return err
+			}
+
+			states := make(map[string]string)
+			for _, c := range containers {
+				if host := c.Labels[containerlabels.Host]; host != "" {
+					states[host] = c.State
+				}
+			}
+
+			tbl := table.New("Repository", "Branch", "Changes", "Site", "Status").WithWriter(cmd.OutOrStdout()).WithPadding(2)
+			for _, r := range repos {
+				branch, err := workspace.Branch(r)
+				if err != nil {
+					branch = "-"
+				}
+
+				changes := "-"
+				if c, err := workspace.Changes(r); err == nil {
+					changes = strconv.Itoa(c)
+				}
+
+				hostname, status := "-", "-"
+				if s := workspace.Site(home, r, cfg.Sites); s != nil {
+					hostname = s.Hostname
+
+					switch states[s.Hostname] {
+					case "running":
+						status = "running"
+					case "":
+						status = "not created"
+					default:
+						status = "stopped"
+					}
+				}
+
+				tbl.AddRow(filepath.Base(r), branch, changes, hostname, status)
+			}
+
+			tbl.Print()
+
+			return nil
+		},
+	}
+
+	return cmd
+}
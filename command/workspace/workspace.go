@@ -0,0 +1,45 @@
+package workspace
+
+import (
+	"github.com/docker/docker/client"
+	"github.com/spf13/cobra"
+
+	"github.com/craftcms/nitro/pkg/terminal"
+)
+
+const exampleText = `  # register the current directory as a workspace
+  nitro workspace add
+
+  # register a directory of repositories as a workspace
+  nitro workspace add ~/clients
+
+  # pull the latest changes for every repository in the workspaces
+  nitro workspace pull
+
+  # show the status of every repository in the workspaces
+  nitro workspace status`
+
+// NewCommand returns the workspace commands. A workspace is a directory of
+// git repositories (e.g. one per client) that commands can operate on as a
+// group, such as pulling changes or running composer install for each.
+func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outputer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "workspace",
+		Short:   "Manages workspaces of repositories.",
+		Aliases: []string{"ws"},
+		Example: exampleText,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(
+		addCommand(home, output),
+		removeCommand(home, output),
+		lsCommand(home, output),
+		pullCommand(home, output),
+		statusCommand(home, docker, output),
+	)
+
+	return cmd
+}
@@ -0,0 +1,51 @@
+package xdebug
+
+import (
+	"github.com/docker/docker/client"
+	"github.com/spf13/cobra"
+
+	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/terminal"
+)
+
+var offExampleText = `  # disable xdebug for the site in the current directory
+  nitro xdebug off
+
+  # disable xdebug for a specific site
+  nitro xdebug off tutorial.nitro`
+
+// offCommand disables xdebug for a single site and recreates the sites container.
+func offCommand(home string, docker client.CommonAPIClient, output terminal.Outputer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "off",
+		Short:             "Disables Xdebug for a site.",
+		Example:           offExampleText,
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: validArgs(home),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load(home)
+			if err != nil {
+				return err
+			}
+
+			site, err := findSite(cmd, home, cfg, args, output)
+			if err != nil {
+				return err
+			}
+
+			if err := cfg.DisableXdebug(site.Hostname); err != nil {
+				return err
+			}
+
+			if err := recreate(cmd, home, docker, cfg, site.Hostname, output); err != nil {
+				return err
+			}
+
+			output.Info("Xdebug is disabled for", site.Hostname)
+
+			return nil
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,91 @@
+package xdebug
+
+import (
+	"fmt"
+
+	"github.com/docker/docker/client"
+	"github.com/spf13/cobra"
+
+	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/terminal"
+)
+
+var onExampleText = `  # enable xdebug for the site in the current directory
+  nitro xdebug on
+
+  # enable xdebug for a specific site
+  nitro xdebug on tutorial.nitro
+
+  # send debug sessions to an IDE on another machine
+  nitro xdebug on tutorial.nitro --client-host 192.168.1.20 --client-port 9003`
+
+// onCommand enables xdebug for a single site, recreates the sites container, and shows
+// the settings needed to listen for debug sessions in the IDE.
+func onCommand(home string, docker client.CommonAPIClient, output terminal.Outputer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "on",
+		Short:             "Enables Xdebug for a site.",
+		Example:           onExampleText,
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: validArgs(home),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load(home)
+			if err != nil {
+				return err
+			}
+
+			site, err := findSite(cmd, home, cfg, args, output)
+			if err != nil {
+				return err
+			}
+
+			// php 7.0 does not support xdebug
+			if site.Version == "7.0" {
+				return fmt.Errorf("Xdebug with PHP 7.0 is not supported")
+			}
+
+			// blackfire and xdebug cannot be used at the same time
+			if site.Blackfire {
+				if err := cfg.DisableBlackfire(site.Hostname); err != nil {
+					return err
+				}
+			}
+
+			if err := cfg.EnableXdebug(site.Hostname); err != nil {
+				return err
+			}
+
+			// set the client host and port if the IDE is on another machine
+			if cmd.Flag("client-host").Changed || cmd.Flag("client-port").Changed {
+				host, _ := cmd.Flags().GetString("client-host")
+				port, _ := cmd.Flags().GetInt("client-port")
+
+				if err := cfg.SetXdebugClient(site.Hostname, host, port); err != nil {
+					return err
+				}
+			}
+
+			if err := recreate(cmd, home, docker, cfg, site.Hostname, output); err != nil {
+				return err
+			}
+
+			// show the settings for the ide
+			site, err = cfg.FindSiteByHostName(site.Hostname)
+			if err != nil {
+				return err
+			}
+
+			output.Info("Xdebug is enabled for", site.Hostname)
+			output.Info("  IDE key:\t", config.DefaultEnvs["XDEBUG_SESSION"])
+			output.Info("  port:\t", fmt.Sprintf("%d", site.GetXdebugPort()))
+			output.Info("  server name:\t", site.Hostname)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().String("client-host", "", "the host of the IDE listening for debug sessions (defaults to host.docker.internal)")
+	cmd.Flags().Int("client-port", 0, "the port of the IDE listening for debug sessions (defaults to 9003 or 9000 for Xdebug 2)")
+
+	return cmd
+}
@@ -0,0 +1,143 @@
+package xdebug
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+	"github.com/spf13/cobra"
+
+	"github.com/craftcms/nitro/command/internal/sitecontainer"
+	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/environment"
+	"github.com/craftcms/nitro/pkg/prompt"
+	"github.com/craftcms/nitro/pkg/terminal"
+)
+
+const exampleText = `  # enable xdebug for the site in the current directory
+  nitro xdebug on
+
+  # disable xdebug for a specific site
+  nitro xdebug off tutorial.nitro`
+
+// NewCommand returns the xdebug commands, which toggle Xdebug for a single site and
+// only recreate that sites container instead of applying the entire environment.
+func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outputer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "xdebug",
+		Short:   "Manages Xdebug for a site.",
+		Example: exampleText,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(
+		onCommand(home, docker, output),
+		offCommand(home, docker, output),
+	)
+
+	return cmd
+}
+
+// validArgs returns the hostnames of the sites for completion.
+func validArgs(home string) func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		cfg, err := config.Load(home)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveDefault
+		}
+
+		var options []string
+		for _, s := range cfg.Sites {
+			options = append(options, s.Hostname)
+		}
+
+		return options, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// findSite returns the site from the argument, the current directory, or prompts the user.
+func findSite(cmd *cobra.Command, home string, cfg *config.Config, args []string, output terminal.Outputer) (*config.Site, error) {
+	if len(args) > 0 {
+		return cfg.FindSiteByHostName(strings.TrimSpace(args[0]))
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+
+	// get a context aware list of sites
+	sites := cfg.ListOfSitesByDirectory(home, wd)
+	if len(sites) == 0 {
+		return nil, fmt.Errorf("there are no sites in the config")
+	}
+
+	if len(sites) == 1 {
+		return &sites[0], nil
+	}
+
+	var options []string
+	for _, s := range sites {
+		options = append(options, s.Hostname)
+	}
+
+	selected, err := prompt.Select(cmd.InOrStdin(), "Select a site: ", options, output)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sites[selected], nil
+}
+
+// recreate saves the config and updates the container for the site using
+// the same code path as apply, the container is only recreated when the
+// environment variables for xdebug have changed.
+func recreate(cmd *cobra.Command, home string, docker client.CommonAPIClient, cfg *config.Config, hostname string, output terminal.Outputer) error {
+	ctx := cmd.Context()
+
+	if err := cfg.Save(); err != nil {
+		return err
+	}
+
+	// get the site with the changes
+	site, err := cfg.FindSiteByHostName(hostname)
+	if err != nil {
+		return err
+	}
+
+	// find the network
+	filter := filters.NewArgs()
+	filter.Add("name", environment.Network())
+
+	networks, err := docker.NetworkList(ctx, types.NetworkListOptions{Filters: filter})
+	if err != nil {
+		return fmt.Errorf("unable to list the docker networks, %w", err)
+	}
+
+	var networkID string
+	for _, n := range networks {
+		if n.Name == environment.Network() || strings.TrimLeft(n.Name, "/") == environment.Network() {
+			networkID = n.ID
+		}
+	}
+
+	if networkID == "" {
+		return fmt.Errorf("unable to find the network, run `nitro init` to create it")
+	}
+
+	output.Pending("updating", site.Hostname)
+
+	if _, err := sitecontainer.StartOrCreate(ctx, docker, home, networkID, *site, cfg, output); err != nil {
+		output.Warning()
+		return err
+	}
+
+	output.Done()
+
+	return nil
+}
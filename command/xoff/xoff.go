@@ -70,7 +70,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 			case true:
 				switch len(sites) {
 				case 0:
-					selected, err := output.Select(cmd.InOrStdin(), "Select a site: ", options)
+					selected, err := prompt.Select(cmd.InOrStdin(), "Select a site: ", options, output)
 					if err != nil {
 						return err
 					}
@@ -81,7 +81,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 
 					site = &sites[0]
 				default:
-					selected, err := output.Select(cmd.InOrStdin(), "Select a site: ", options)
+					selected, err := prompt.Select(cmd.InOrStdin(), "Select a site: ", options, output)
 					if err != nil {
 						return err
 					}
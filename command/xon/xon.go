@@ -14,7 +14,10 @@ import (
 )
 
 const exampleText = `  # example command
-  nitro xon`
+  nitro xon
+
+  # send debug sessions to an IDE on another machine
+  nitro xon --client-host 192.168.1.20 --client-port 9003`
 
 // NewCommand returns the command that is used to enable xdebug for a specific site. It will first check
 // if the current working directory or prompt the user for a site.
@@ -71,7 +74,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 			case true:
 				switch len(sites) {
 				case 0:
-					selected, err := output.Select(cmd.InOrStdin(), "Select a site: ", options)
+					selected, err := prompt.Select(cmd.InOrStdin(), "Select a site: ", options, output)
 					if err != nil {
 						return err
 					}
@@ -82,7 +85,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 
 					site = &sites[0]
 				default:
-					selected, err := output.Select(cmd.InOrStdin(), "Select a site: ", options)
+					selected, err := prompt.Select(cmd.InOrStdin(), "Select a site: ", options, output)
 					if err != nil {
 						return err
 					}
@@ -114,6 +117,16 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 				return err
 			}
 
+			// set the client host and port if the IDE is on another machine
+			if cmd.Flag("client-host").Changed || cmd.Flag("client-port").Changed {
+				host, _ := cmd.Flags().GetString("client-host")
+				port, _ := cmd.Flags().GetInt("client-port")
+
+				if err := cfg.SetXdebugClient(site.Hostname, host, port); err != nil {
+					return err
+				}
+			}
+
 			// save the config
 			if err := cfg.Save(); err != nil {
 				return err
@@ -123,5 +136,8 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 		},
 	}
 
+	cmd.Flags().String("client-host", "", "the host of the IDE listening for debug sessions (defaults to host.docker.internal)")
+	cmd.Flags().Int("client-port", 0, "the port of the IDE listening for debug sessions (defaults to 9003 or 9000 for Xdebug 2)")
+
 	return cmd
 }
@@ -0,0 +1,78 @@
+package xtunnel
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/docker/docker/client"
+	"github.com/spf13/cobra"
+
+	"github.com/craftcms/nitro/pkg/terminal"
+)
+
+var (
+	// execName is the name of the executable to search for. We make it a variable so we can replace it during tests.
+	execName = "ssh"
+)
+
+const exampleText = `  # forward xdebug sessions from this machine to an IDE on another machine
+  nitro xtunnel user@192.168.1.20
+
+  # forward a custom port
+  nitro xtunnel user@192.168.1.20 --port 9000
+
+  # listen on the docker bridge address when containers cannot reach localhost (e.g. on linux)
+  nitro xtunnel user@192.168.1.20 --bind-address 172.17.0.1`
+
+// NewCommand returns the command that opens an SSH tunnel so Xdebug can reach an IDE on
+// another machine. Xdebug connects to the port on this machine, which is forwarded to the
+// port the IDE is listening on.
+func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outputer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "xtunnel",
+		Short:   "Forwards Xdebug to an IDE on another machine.",
+		Example: exampleText,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ssh, err := exec.LookPath(execName)
+			if err != nil {
+				return fmt.Errorf("ssh is required to forward xdebug, %w", err)
+			}
+
+			port, err := cmd.Flags().GetInt("port")
+			if err != nil {
+				port = 9003
+			}
+
+			bind, err := cmd.Flags().GetString("bind-address")
+			if err != nil {
+				bind = "127.0.0.1"
+			}
+
+			remote := strings.TrimSpace(args[0])
+
+			output.Info(fmt.Sprintf("Forwarding port %d to %s, press ctrl+c to stop…", port, remote))
+
+			c := exec.Command(ssh, sshArgs(bind, port, remote)...)
+
+			c.Stdin = cmd.InOrStdin()
+			c.Stderr = cmd.ErrOrStderr()
+			c.Stdout = cmd.OutOrStdout()
+
+			return c.Run()
+		},
+	}
+
+	cmd.Flags().Int("port", 9003, "the port the IDE is listening on")
+	cmd.Flags().String("bind-address", "127.0.0.1", "the address on this machine to listen on, other machines on the network can use the tunnel when it is not localhost")
+
+	return cmd
+}
+
+// sshArgs returns the arguments for ssh to forward the port on this machine to the port on
+// the remote machine. The port only listens on the bind address, so the tunnel to the IDE is
+// not exposed to the rest of the network unless a wider address is chosen.
+func sshArgs(bind string, port int, remote string) []string {
+	return []string{"-N", "-L", fmt.Sprintf("%s:%d:localhost:%d", bind, port, port), remote}
+}
@@ -0,0 +1,42 @@
+package xtunnel
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_sshArgs(t *testing.T) {
+	type args struct {
+		bind   string
+		port   int
+		remote string
+	}
+	tests := []struct {
+		name string
+		args args
+		want []string
+	}{
+		{
+			name: "the local port is forwarded to the port on the remote machine",
+			args: args{bind: "127.0.0.1", port: 9003, remote: "user@192.168.1.20"},
+			want: []string{"-N", "-L", "127.0.0.1:9003:localhost:9003", "user@192.168.1.20"},
+		},
+		{
+			name: "custom ports are forwarded",
+			args: args{bind: "127.0.0.1", port: 9000, remote: "ide.local"},
+			want: []string{"-N", "-L", "127.0.0.1:9000:localhost:9000", "ide.local"},
+		},
+		{
+			name: "the bind address can be set for the docker bridge",
+			args: args{bind: "172.17.0.1", port: 9003, remote: "ide.local"},
+			want: []string{"-N", "-L", "172.17.0.1:9003:localhost:9003", "ide.local"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sshArgs(tt.args.bind, tt.args.port, tt.args.remote); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("sshArgs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -9,15 +9,22 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"os"
 	"os/exec"
+	"sort"
+	"strconv"
 	"strings"
 	"syscall"
 
 	"github.com/craftcms/nitro/pkg/caddy"
+	"github.com/craftcms/nitro/pkg/certificate"
+	"github.com/craftcms/nitro/pkg/dashboard"
 	"github.com/craftcms/nitro/pkg/database"
+	"github.com/craftcms/nitro/pkg/metrics"
 	"github.com/craftcms/nitro/pkg/portavail"
 	"github.com/craftcms/nitro/protob"
 	"google.golang.org/grpc/codes"
@@ -30,16 +37,22 @@ var Version string
 // implements the gRPC API used in the proxy container. The gRPC API is used to
 // handle making changes to the Caddy Server via its local API. If no addr is
 // provided, it will set the default addr to http://127.0.0.1:2019
-func NewService(addr string) protob.NitroServer {
+func NewService(addr string) *Service {
 	// set the nitro version on start
 	if env, ok := os.LookupEnv("NITRO_VERSION"); ok {
 		Version = env
 	}
 
+	d := &dashboard.Dashboard{Version: Version}
+
 	return &Service{
 		Addr:     addr,
 		HTTP:     http.DefaultClient,
 		Importer: database.NewImporter(),
+		Files:    os.DirFS("/"),
+
+		Dashboard: d,
+		Metrics:   &metrics.Metrics{Caddy: addr + metrics.Path, HTTP: http.DefaultClient, Dashboard: d},
 	}
 }
 
@@ -48,6 +61,15 @@ type Service struct {
 	Addr     string
 	HTTP     *http.Client
 	Importer database.Importer
+
+	// Files is the file system the certificates are read from, it defaults to the root directory
+	Files fs.FS
+
+	// Dashboard is updated with the dashboard in each apply request, it is served by nitrod
+	Dashboard *dashboard.Dashboard
+
+	// Metrics records the reports from the CLI, it is served on the API port by nitrod
+	Metrics *metrics.Metrics
 }
 
 // AddDatabase handle creating a new database for a hostname
@@ -113,7 +135,8 @@ func (svc *Service) Apply(ctx context.Context, request *protob.ApplyRequest) (*p
 	}
 
 	// convert each of the sites into a route
-	var siteRoutes, nodeRoutes, nodeAltRoutes []caddy.ServerRoute
+	var siteRoutes, pathRoutes, nodeRoutes, nodeAltRoutes []caddy.ServerRoute
+	loggers := make(map[string]string)
 	for k, site := range request.GetSites() {
 		// get all of the host names for the site
 		hosts := []string{site.GetHostname()}
@@ -121,18 +144,59 @@ func (svc *Service) Apply(ctx context.Context, request *protob.ApplyRequest) (*p
 			hosts = append(hosts, strings.Split(site.GetAliases(), ",")...)
 		}
 
-		// create the route for each of the sites
-		siteRoutes = append(siteRoutes, caddy.ServerRoute{
-			Handle: []caddy.RouteHandle{
+		// add the debug headers to the response
+		var handles []caddy.RouteHandle
+		if len(site.GetHeaders()) > 0 {
+			headers := caddy.Headers{Set: make(map[string][]string)}
+			for h, v := range site.GetHeaders() {
+				headers.Set[h] = []string{v}
+			}
+
+			handles = append(handles, caddy.RouteHandle{Handler: "headers", Response: &headers})
+		}
+
+		proxy := caddy.RouteHandle{
+			Handler: "reverse_proxy",
+			Upstreams: []caddy.Upstream{
 				{
-					Handler: "reverse_proxy",
-					Upstreams: []caddy.Upstream{
-						{
-							Dial: fmt.Sprintf("%s:%d", k, site.GetPort()),
-						},
-					},
+					Dial: fmt.Sprintf("%s:%d", k, site.GetPort()),
 				},
 			},
+		}
+
+		// balance the requests between the replicas
+		if len(site.GetUpstreams()) > 0 {
+			proxy.Upstreams = nil
+			for _, u := range site.GetUpstreams() {
+				proxy.Upstreams = append(proxy.Upstreams, caddy.Upstream{Dial: fmt.Sprintf("%s:%d", u, site.GetPort())})
+			}
+
+			proxy.LoadBalancing = &caddy.LoadBalancing{SelectionPolicy: caddy.SelectionPolicy{Policy: "round_robin"}}
+		}
+
+		// use HTTP/2 without TLS for the requests to the site
+		if site.GetHttp2() {
+			proxy.Transport = &caddy.Transport{Protocol: "http", Versions: []string{"h2c"}}
+		}
+
+		// time the requests to find slow requests, the logger is named after the site
+		if site.GetSlowRequests() > 0 {
+			proxy.Headers = &caddy.HeaderOps{
+				Response: &caddy.Headers{
+					Add: map[string][]string{
+						"Server-Timing": {caddy.ServerTiming},
+					},
+				},
+			}
+
+			for _, h := range hosts {
+				loggers[h] = site.GetHostname()
+			}
+		}
+
+		// create the route for each of the sites
+		siteRoutes = append(siteRoutes, caddy.ServerRoute{
+			Handle: append(handles, proxy),
 			Match: []caddy.Match{
 				{
 					Host: hosts,
@@ -141,6 +205,33 @@ func (svc *Service) Apply(ctx context.Context, request *protob.ApplyRequest) (*p
 			Terminal: true,
 		})
 
+		// serve the site at a path on the hostname of another site (e.g. mysite.nitro/api)
+		if host, prefix, ok := pathRoute(site.GetRoute()); ok {
+			p := proxy
+			p.Headers = &caddy.HeaderOps{
+				Request: &caddy.Headers{
+					Set: map[string][]string{
+						"X-Forwarded-Prefix": {prefix},
+					},
+				},
+			}
+
+			if proxy.Headers != nil {
+				p.Headers.Response = proxy.Headers.Response
+			}
+
+			pathRoutes = append(pathRoutes, caddy.ServerRoute{
+				Handle: append(append([]caddy.RouteHandle{}, handles...), caddy.RouteHandle{Handler: "rewrite", StripPathPrefix: prefix}, p),
+				Match: []caddy.Match{
+					{
+						Host: []string{host},
+						Path: []string{prefix, prefix + "/*"},
+					},
+				},
+				Terminal: true,
+			})
+		}
+
 		// add the node routes
 		nodeRoutes = append(nodeRoutes, caddy.ServerRoute{
 			Handle: []caddy.RouteHandle{
@@ -181,6 +272,36 @@ func (svc *Service) Apply(ctx context.Context, request *protob.ApplyRequest) (*p
 		})
 	}
 
+	// route the dashboard hostname to the dashboard served by nitrod
+	if h := request.GetDashboard().GetHostname(); h != "" {
+		siteRoutes = append(siteRoutes, caddy.ServerRoute{
+			Handle: []caddy.RouteHandle{
+				{
+					Handler:   "reverse_proxy",
+					Upstreams: []caddy.Upstream{{Dial: dashboard.Addr}},
+				},
+			},
+			Match: []caddy.Match{
+				{
+					Host: []string{h},
+				},
+			},
+			Terminal: true,
+		})
+	}
+
+	if svc.Dashboard != nil {
+		svc.Dashboard.Update(request.GetDashboard())
+	}
+
+	// the path routes are matched before the hostname of the site they are on, the longest
+	// paths first so a route for /api/v2 is not served by the route for /api
+	sort.SliceStable(pathRoutes, func(i, j int) bool {
+		return len(pathRoutes[i].Match[0].Path[0]) > len(pathRoutes[j].Match[0].Path[0])
+	})
+
+	siteRoutes = append(pathRoutes, siteRoutes...)
+
 	update := caddy.UpdateRequest{}
 
 	httpRoutes := append(siteRoutes, caddy.ServerRoute{
@@ -231,6 +352,14 @@ func (svc *Service) Apply(ctx context.Context, request *protob.ApplyRequest) (*p
 		Routes: siteRoutes,
 	}
 
+	// only log the requests for the sites with slow request logging
+	if len(loggers) > 0 {
+		logs := &caddy.ServerLogs{LoggerNames: loggers, SkipUnmappedHosts: true}
+
+		update.HTTP.Logs = logs
+		update.HTTPS.Logs = logs
+	}
+
 	content, err := json.Marshal(&update)
 	if err != nil {
 		return nil, err
@@ -260,6 +389,46 @@ func (svc *Service) Apply(ctx context.Context, request *protob.ApplyRequest) (*p
 	}, nil
 }
 
+// Certificates returns the root certificate and the expiry date of each site certificate the proxy
+// has issued. Caddy stores each certificate in a directory named after the hostname.
+func (svc *Service) Certificates(ctx context.Context, request *protob.CertificatesRequest) (*protob.CertificatesResponse, error) {
+	if svc.Files == nil {
+		svc.Files = os.DirFS("/")
+	}
+
+	root, err := fs.ReadFile(svc.Files, strings.TrimPrefix(certificate.RootPath, "/"))
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "unable to read the root certificate: %s", err)
+	}
+
+	resp := &protob.CertificatesResponse{Root: string(root)}
+
+	dirs, err := fs.ReadDir(svc.Files, strings.TrimPrefix(certificate.SitesDir, "/"))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, status.Errorf(codes.Internal, "unable to read the site certificates: %s", err)
+	}
+
+	for _, d := range dirs {
+		if !d.IsDir() {
+			continue
+		}
+
+		data, err := fs.ReadFile(svc.Files, strings.TrimPrefix(certificate.SitePath(d.Name()), "/"))
+		if err != nil {
+			continue
+		}
+
+		expires, err := certificate.Expiry(data)
+		if err != nil {
+			continue
+		}
+
+		resp.Certificates = append(resp.Certificates, &protob.Certificate{Hostname: d.Name(), Expires: expires.Unix()})
+	}
+
+	return resp, nil
+}
+
 // ImportDatabase is used to handle streaming requests from the client and import a
 // database from a backup into the remote database container.
 func (svc *Service) ImportDatabase(stream protob.Nitro_ImportDatabaseServer) error {
@@ -425,6 +594,93 @@ func (svc *Service) Ping(ctx context.Context, request *protob.PingRequest) (*pro
 	return &protob.PingResponse{Pong: "pong"}, nil
 }
 
+// Report records the duration of an apply and the state of the containers for the metrics.
+func (svc *Service) Report(ctx context.Context, request *protob.ReportRequest) (*protob.ReportResponse, error) {
+	if svc.Metrics != nil {
+		svc.Metrics.Record(request)
+	}
+
+	return &protob.ReportResponse{}, nil
+}
+
+// Snapshot returns the Caddy config with the routes of the sites, the certificates the proxy has
+// issued, and the dashboard. The private keys of the certificates are not included so the
+// snapshot can be attached to issues.
+func (svc *Service) Snapshot(ctx context.Context, request *protob.SnapshotRequest) (*protob.SnapshotResponse, error) {
+	// if there is no client, use the default
+	if svc.HTTP == nil {
+		svc.HTTP = http.DefaultClient
+	}
+
+	// set the addr if not provided
+	if svc.Addr == "" {
+		svc.Addr = "http://127.0.0.1:2019"
+	}
+
+	res, err := svc.HTTP.Get(svc.Addr + "/config/")
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "unable to get the Caddy config: %s", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, status.Errorf(codes.Internal, "received %d response from Caddy API", res.StatusCode)
+	}
+
+	content, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "unable to read the Caddy config: %s", err)
+	}
+
+	resp := &protob.SnapshotResponse{Version: Version, Caddy: content}
+
+	// the certificates are missing until the proxy has issued the root certificate
+	if certs, err := svc.Certificates(ctx, &protob.CertificatesRequest{}); err == nil {
+		resp.Certificates = certs.GetCertificates()
+	}
+
+	if svc.Dashboard != nil {
+		resp.Dashboard = svc.Dashboard.Current()
+	}
+
+	return resp, nil
+}
+
+// Restore loads the Caddy config from a snapshot and replaces the dashboard. The certificates
+// are issued by the proxy for the hostnames in the config when they are requested.
+func (svc *Service) Restore(ctx context.Context, request *protob.RestoreRequest) (*protob.RestoreResponse, error) {
+	// if there is no client, use the default
+	if svc.HTTP == nil {
+		svc.HTTP = http.DefaultClient
+	}
+
+	// set the addr if not provided
+	if svc.Addr == "" {
+		svc.Addr = "http://127.0.0.1:2019"
+	}
+
+	if !json.Valid(request.GetCaddy()) {
+		return nil, status.Error(codes.InvalidArgument, "the Caddy config is not valid JSON")
+	}
+
+	res, err := svc.HTTP.Post(svc.Addr+"/load", "application/json", bytes.NewReader(request.GetCaddy()))
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "unable to load the Caddy config: %s", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		msg, _ := ioutil.ReadAll(res.Body)
+		return nil, status.Errorf(codes.Internal, "received %d response from Caddy API: %s", res.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	if svc.Dashboard != nil {
+		svc.Dashboard.Update(request.GetDashboard())
+	}
+
+	return &protob.RestoreResponse{}, nil
+}
+
 // RemoveDatabase handles removing a specific database from a database container
 func (svc *Service) RemoveDatabase(ctx context.Context, req *protob.RemoveDatabaseRequest) (*protob.RemoveDatabaseResponse, error) {
 	// get the database info from the request
@@ -466,11 +722,135 @@ func (svc *Service) RemoveDatabase(ctx context.Context, req *protob.RemoveDataba
 	}, nil
 }
 
+// Sites returns the sites the proxy is routing requests to by reading the routes from the
+// HTTPS server in the Caddy config.
+func (svc *Service) Sites(ctx context.Context, request *protob.SitesRequest) (*protob.SitesResponse, error) {
+	// if there is no client, use the default
+	if svc.HTTP == nil {
+		svc.HTTP = http.DefaultClient
+	}
+
+	// set the addr if not provided
+	if svc.Addr == "" {
+		svc.Addr = "http://127.0.0.1:2019"
+	}
+
+	res, err := svc.HTTP.Get(svc.Addr + "/config/apps/http/servers/https")
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "unable to get the Caddy config: %s", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, status.Errorf(codes.Internal, "received %d response from Caddy API", res.StatusCode)
+	}
+
+	var server caddy.Server
+	if err := json.NewDecoder(res.Body).Decode(&server); err != nil {
+		return nil, status.Errorf(codes.Internal, "unable to decode the Caddy config: %s", err)
+	}
+
+	resp := &protob.SitesResponse{Sites: make(map[string]*protob.Site)}
+	routes := make(map[string]string)
+	for _, r := range server.Routes {
+		if len(r.Match) == 0 || len(r.Match[0].Host) == 0 {
+			continue
+		}
+
+		// the dashboard is not a site
+		if dashboardRoute(r) {
+			continue
+		}
+
+		// the path routes belong to the site with the same upstreams
+		if len(r.Match[0].Path) > 0 {
+			routes[upstreams(r)] = r.Match[0].Host[0] + r.Match[0].Path[0]
+			continue
+		}
+
+		hosts := r.Match[0].Host
+		site := &protob.Site{Hostname: hosts[0], Aliases: strings.Join(hosts[1:], ",")}
+
+		for _, h := range r.Handle {
+			switch h.Handler {
+			case "headers":
+				if h.Response != nil {
+					site.Headers = make(map[string]string)
+					for k, v := range h.Response.Set {
+						site.Headers[k] = strings.Join(v, ",")
+					}
+				}
+			case "reverse_proxy":
+				for _, u := range h.Upstreams {
+					host, port, err := net.SplitHostPort(u.Dial)
+					if err != nil {
+						continue
+					}
+
+					if p, err := strconv.Atoi(port); err == nil {
+						site.Port = int32(p)
+					}
+
+					site.Upstreams = append(site.Upstreams, host)
+				}
+
+				// a single upstream is the site container
+				if len(site.Upstreams) == 1 {
+					site.Upstreams = nil
+				}
+			}
+		}
+
+		site.Route = routes[upstreams(r)]
+
+		resp.Sites[site.Hostname] = site
+	}
+
+	return resp, nil
+}
+
 // Version is used to check the container image version with the CLI version
 func (svc *Service) Version(ctx context.Context, request *protob.VersionRequest) (*protob.VersionResponse, error) {
 	return &protob.VersionResponse{Version: Version}, nil
 }
 
+// pathRoute returns the hostname and the path prefix of a site route (e.g. mysite.nitro/api)
+// and false when the site is not routed by path.
+func pathRoute(route string) (string, string, bool) {
+	parts := strings.SplitN(route, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || strings.Trim(parts[1], "/") == "" {
+		return "", "", false
+	}
+
+	return parts[0], "/" + strings.Trim(parts[1], "/"), true
+}
+
+// upstreams returns the upstreams of the reverse proxy of the route, it is used to find the site
+// a path route belongs to.
+func upstreams(r caddy.ServerRoute) string {
+	var dials []string
+	for _, h := range r.Handle {
+		if h.Handler == "reverse_proxy" {
+			for _, u := range h.Upstreams {
+				dials = append(dials, u.Dial)
+			}
+		}
+	}
+
+	return strings.Join(dials, ",")
+}
+
+// dashboardRoute returns true if the route sends the requests to the dashboard.
+func dashboardRoute(r caddy.ServerRoute) bool {
+	for _, h := range r.Handle {
+		if h.Handler == "reverse_proxy" && len(h.Upstreams) == 1 && h.Upstreams[0].Dial == dashboard.Addr {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (svc *Service) exec(tool string, commands []string) error {
 	c := exec.Command(tool, commands...)
 
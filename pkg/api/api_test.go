@@ -2,10 +2,18 @@ package api
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
 	"reflect"
+	"strings"
 	"testing"
+	"testing/fstest"
 
+	"github.com/craftcms/nitro/pkg/caddy"
+	"github.com/craftcms/nitro/pkg/dashboard"
 	"github.com/craftcms/nitro/protob"
 )
 
@@ -92,3 +100,241 @@ func TestService_Version(t *testing.T) {
 		})
 	}
 }
+
+func TestService_Sites(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/config/apps/http/servers/https" {
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+
+		fmt.Fprint(w, `{"listen":[":443"],"routes":[
+			{"handle":[{"handler":"reverse_proxy","upstreams":[{"dial":"craft.nitro:8080"}]}],"match":[{"host":["craft.nitro","craft.localhost"]}],"terminal":true},
+			{"handle":[{"handler":"reverse_proxy","upstreams":[{"dial":"chrome.containers.nitro:9222"},{"dial":"chrome-2.containers.nitro:9222"}]}],"match":[{"host":["chrome.containers.nitro"]}],"terminal":true}
+		]}`)
+	}))
+	defer srv.Close()
+
+	svc := &Service{Addr: srv.URL, HTTP: srv.Client()}
+
+	got, err := svc.Sites(context.TODO(), &protob.SitesRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if site := got.GetSites()["craft.nitro"]; site.GetAliases() != "craft.localhost" || site.GetPort() != 8080 || len(site.GetUpstreams()) != 0 {
+		t.Errorf("unexpected site %v", site)
+	}
+
+	want := []string{"chrome.containers.nitro", "chrome-2.containers.nitro"}
+	if site := got.GetSites()["chrome.containers.nitro"]; !reflect.DeepEqual(site.GetUpstreams(), want) || site.GetPort() != 9222 {
+		t.Errorf("expected the replicas to be upstreams, got %v", site)
+	}
+}
+
+func TestService_Apply_SlowRequests(t *testing.T) {
+	var update caddy.UpdateRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+			t.Error(err)
+		}
+	}))
+	defer srv.Close()
+
+	svc := &Service{Addr: srv.URL, HTTP: srv.Client()}
+
+	_, err := svc.Apply(context.TODO(), &protob.ApplyRequest{Sites: map[string]*protob.Site{
+		"craft.nitro": {Hostname: "craft.nitro", Aliases: "craft.localhost", Port: 8080, SlowRequests: 500},
+		"other.nitro": {Hostname: "other.nitro", Port: 8080},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &caddy.ServerLogs{
+		LoggerNames:       map[string]string{"craft.nitro": "craft.nitro", "craft.localhost": "craft.nitro"},
+		SkipUnmappedHosts: true,
+	}
+	if !reflect.DeepEqual(update.HTTPS.Logs, want) {
+		t.Errorf("expected the access log for the site, got %v", update.HTTPS.Logs)
+	}
+
+	for _, r := range update.HTTPS.Routes {
+		proxy := r.Handle[len(r.Handle)-1]
+
+		timed := proxy.Headers != nil && proxy.Headers.Response.Add["Server-Timing"][0] == caddy.ServerTiming
+		if timed != (r.Match[0].Host[0] == "craft.nitro") {
+			t.Errorf("unexpected Server-Timing header for %s", r.Match[0].Host[0])
+		}
+	}
+}
+
+func TestService_Certificates(t *testing.T) {
+	root, err := ioutil.ReadFile("testdata/root.crt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	svc := &Service{Files: fstest.MapFS{
+		"data/caddy/pki/authorities/local/root.crt":                      {Data: root},
+		"data/caddy/certificates/local/craft.nitro/craft.nitro.crt":      {Data: root},
+		"data/caddy/certificates/local/craft.nitro/craft.nitro.key":      {Data: []byte("key")},
+		"data/caddy/certificates/local/pending.nitro/pending.nitro.json": {Data: []byte("{}")},
+	}}
+
+	got, err := svc.Certificates(context.TODO(), &protob.CertificatesRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got.GetRoot() != string(root) {
+		t.Errorf("expected the root certificate to be returned")
+	}
+
+	if len(got.GetCertificates()) != 1 || got.GetCertificates()[0].GetHostname() != "craft.nitro" {
+		t.Fatalf("expected only the issued certificate to be returned, got %v", got.GetCertificates())
+	}
+
+	if got.GetCertificates()[0].GetExpires() == 0 {
+		t.Errorf("expected the expiry to be set")
+	}
+}
+
+func TestService_Apply_Dashboard(t *testing.T) {
+	var update caddy.UpdateRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+			t.Error(err)
+		}
+	}))
+	defer srv.Close()
+
+	svc := &Service{Addr: srv.URL, HTTP: srv.Client(), Dashboard: &dashboard.Dashboard{}}
+
+	_, err := svc.Apply(context.TODO(), &protob.ApplyRequest{
+		Sites:     map[string]*protob.Site{"craft.nitro": {Hostname: "craft.nitro", Port: 8080}},
+		Dashboard: &protob.Dashboard{Hostname: "nitro.nitro"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var found bool
+	for _, r := range update.HTTPS.Routes {
+		if r.Match[0].Host[0] == "nitro.nitro" {
+			found = dashboardRoute(r)
+		}
+	}
+
+	if !found {
+		t.Errorf("expected the dashboard hostname to be routed to the dashboard, got %v", update.HTTPS.Routes)
+	}
+}
+
+func TestService_Apply_Route(t *testing.T) {
+	var update caddy.UpdateRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			_ = json.NewEncoder(w).Encode(update.HTTPS)
+			return
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+			t.Error(err)
+		}
+	}))
+	defer srv.Close()
+
+	svc := &Service{Addr: srv.URL, HTTP: srv.Client()}
+
+	_, err := svc.Apply(context.TODO(), &protob.ApplyRequest{Sites: map[string]*protob.Site{
+		"craft.nitro": {Hostname: "craft.nitro", Port: 8080},
+		"api.nitro":   {Hostname: "api.nitro", Port: 8080, Route: "craft.nitro/api"},
+		"v2.nitro":    {Hostname: "v2.nitro", Port: 8080, Route: "craft.nitro/api/v2"},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the longest path is matched first and before the hostname of the site
+	var paths []string
+	for _, r := range update.HTTPS.Routes[:3] {
+		paths = append(paths, strings.Join(r.Match[0].Path, ","))
+	}
+
+	want := []string{"/api/v2,/api/v2/*", "/api,/api/*", ""}
+	if !reflect.DeepEqual(paths, want) {
+		t.Errorf("expected the path routes before the sites, got %v", paths)
+	}
+
+	route := update.HTTPS.Routes[1]
+	if route.Match[0].Host[0] != "craft.nitro" || route.Handle[0].Handler != "rewrite" || route.Handle[0].StripPathPrefix != "/api" {
+		t.Errorf("expected the prefix to be stripped on the hostname of the other site, got %v", route)
+	}
+
+	proxy := route.Handle[1]
+	if proxy.Upstreams[0].Dial != "api.nitro:8080" || proxy.Headers.Request.Set["X-Forwarded-Prefix"][0] != "/api" {
+		t.Errorf("expected the request to be proxied to the site with the prefix, got %v", proxy)
+	}
+
+	if len(update.Node.Routes) != 3 {
+		t.Errorf("expected the path routes to be left out of the node routes, got %d routes", len(update.Node.Routes))
+	}
+
+	got, err := svc.Sites(context.TODO(), &protob.SitesRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(got.GetSites()) != 3 || got.GetSites()["api.nitro"].GetRoute() != "craft.nitro/api" || got.GetSites()["craft.nitro"].GetRoute() != "" {
+		t.Errorf("expected the route to be returned for the site, got %v", got.GetSites())
+	}
+}
+
+func TestService_SnapshotRestore(t *testing.T) {
+	config := `{"apps":{"http":{"servers":{"https":{"listen":[":443"]}}}}}`
+
+	var loaded []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/config/":
+			fmt.Fprint(w, config)
+		case r.Method == http.MethodPost && r.URL.Path == "/load":
+			loaded, _ = ioutil.ReadAll(r.Body)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	d := &dashboard.Dashboard{}
+	d.Update(&protob.Dashboard{Hostname: "nitro.nitro"})
+
+	svc := &Service{Addr: srv.URL, HTTP: srv.Client(), Files: fstest.MapFS{}, Dashboard: d}
+
+	snapshot, err := svc.Snapshot(context.TODO(), &protob.SnapshotRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(snapshot.GetCaddy()) != config || snapshot.GetDashboard().GetHostname() != "nitro.nitro" {
+		t.Errorf("unexpected snapshot %v", snapshot)
+	}
+
+	d.Update(nil)
+
+	if _, err := svc.Restore(context.TODO(), &protob.RestoreRequest{Caddy: snapshot.GetCaddy(), Dashboard: snapshot.GetDashboard()}); err != nil {
+		t.Fatal(err)
+	}
+
+	if string(loaded) != config {
+		t.Errorf("expected the config to be loaded, got %s", loaded)
+	}
+
+	if d.Current().GetHostname() != "nitro.nitro" {
+		t.Errorf("expected the dashboard to be restored")
+	}
+
+	if _, err := svc.Restore(context.TODO(), &protob.RestoreRequest{Caddy: []byte("{")}); err == nil {
+		t.Errorf("expected an error for an invalid config")
+	}
+}
@@ -3,6 +3,7 @@ package backup
 import (
 	"archive/tar"
 	"bytes"
+	"compress/gzip"
 	"context"
 	"fmt"
 	"io"
@@ -13,10 +14,12 @@ import (
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
 
 	"github.com/craftcms/nitro/pkg/config"
 	"github.com/craftcms/nitro/pkg/containerlabels"
 	"github.com/craftcms/nitro/pkg/helpers"
+	"github.com/craftcms/nitro/pkg/prompt"
 	"github.com/craftcms/nitro/pkg/terminal"
 )
 
@@ -64,7 +67,7 @@ func (o *Options) Validate() error {
 // as the first string, the database name, and the last return is an error.
 func Prompt(ctx context.Context, reader io.Reader, docker client.ContainerAPIClient, output terminal.Outputer, containers []types.Container, containerList []string) (string, string, string, string, error) {
 	// prompt the user for which database to backup
-	selected, err := output.Select(reader, "Which database engine? ", containerList)
+	selected, err := prompt.Select(reader, "Which database engine? ", containerList, output)
 	if err != nil {
 		return "", "", "", "", err
 	}
@@ -90,7 +93,7 @@ func Prompt(ctx context.Context, reader io.Reader, docker client.ContainerAPICli
 	case 0:
 		return "", "", "", "", fmt.Errorf("no databases found")
 	default:
-		selected, err := output.Select(os.Stdin, "Which database should we backup? ", databases)
+		selected, err := prompt.Select(os.Stdin, "Which database should we backup? ", databases, output)
 		if err != nil {
 			return "", "", "", "", err
 		}
@@ -257,3 +260,56 @@ func Perform(ctx context.Context, docker client.ContainerAPIClient, opts *Option
 
 	return nil
 }
+
+// Export runs the dump commands in the database container and streams the output of the
+// commands into the writer using gzip compression. Unlike Perform, the dump is never
+// written to the container so large databases do not need to fit in the container.
+func Export(ctx context.Context, docker client.ContainerAPIClient, containerID string, commands []string, w io.Writer) error {
+	// create the dump in the container
+	exec, err := docker.ContainerExecCreate(ctx, containerID, types.ExecConfig{
+		AttachStdout: true,
+		AttachStderr: true,
+		Tty:          false,
+		Cmd:          commands,
+	})
+	if err != nil {
+		return err
+	}
+
+	// attach to the container
+	resp, err := docker.ContainerExecAttach(ctx, exec.ID, types.ExecStartCheck{Tty: false})
+	if err != nil {
+		return err
+	}
+	defer resp.Close()
+
+	// compress stdout as it is read and keep stderr for errors
+	gz := gzip.NewWriter(w)
+	stderr := new(bytes.Buffer)
+	if _, err := stdcopy.StdCopy(gz, stderr, resp.Reader); err != nil {
+		return err
+	}
+
+	if err := gz.Close(); err != nil {
+		return err
+	}
+
+	// wait for the exec to complete and check the exit code of the dump
+	var info types.ContainerExecInspect
+	for {
+		info, err = docker.ContainerExecInspect(ctx, exec.ID)
+		if err != nil {
+			return err
+		}
+
+		if !info.Running {
+			break
+		}
+	}
+
+	if info.ExitCode != 0 {
+		return fmt.Errorf("the export exited with code %d, %s", info.ExitCode, strings.TrimSpace(stderr.String()))
+	}
+
+	return nil
+}
@@ -0,0 +1,106 @@
+package backup
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io/ioutil"
+	"net"
+	"reflect"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+func TestExport(t *testing.T) {
+	tests := []struct {
+		name     string
+		stdout   string
+		stderr   string
+		exitCode int
+		wantErr  bool
+	}{
+		{
+			name:   "the output of the commands is compressed",
+			stdout: "CREATE TABLE `users`;",
+		},
+		{
+			name:     "errors from the commands are returned",
+			stderr:   "mysqldump: Got error: 1049: Unknown database 'missing'",
+			exitCode: 2,
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spy := &mockClient{stdout: tt.stdout, stderr: tt.stderr, exitCode: tt.exitCode}
+			commands := []string{"mysqldump", "--user=nitro", "-pnitro", "nitro"}
+
+			buf := new(bytes.Buffer)
+			err := Export(context.Background(), spy, "some-id", commands, buf)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Export() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if !reflect.DeepEqual(spy.execConfig.Cmd, commands) {
+				t.Errorf("expected the commands to match, got %v want %v", spy.execConfig.Cmd, commands)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			gz, err := gzip.NewReader(buf)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := ioutil.ReadAll(gz)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if string(got) != tt.stdout {
+				t.Errorf("expected the export to match, got %q want %q", string(got), tt.stdout)
+			}
+		})
+	}
+}
+
+type mockClient struct {
+	client.ContainerAPIClient
+
+	execConfig types.ExecConfig
+	stdout     string
+	stderr     string
+	exitCode   int
+}
+
+func (c *mockClient) ContainerExecCreate(ctx context.Context, container string, config types.ExecConfig) (types.IDResponse, error) {
+	c.execConfig = config
+
+	return types.IDResponse{ID: "exec-id"}, nil
+}
+
+func (c *mockClient) ContainerExecAttach(ctx context.Context, execID string, config types.ExecStartCheck) (types.HijackedResponse, error) {
+	// multiplex the output the same way the docker API does
+	buf := new(bytes.Buffer)
+	if c.stdout != "" {
+		stdcopy.NewStdWriter(buf, stdcopy.Stdout).Write([]byte(c.stdout))
+	}
+	if c.stderr != "" {
+		stdcopy.NewStdWriter(buf, stdcopy.Stderr).Write([]byte(c.stderr))
+	}
+
+	conn, _ := net.Pipe()
+
+	return types.HijackedResponse{Conn: conn, Reader: bufio.NewReader(buf)}, nil
+}
+
+func (c *mockClient) ContainerExecInspect(ctx context.Context, execID string) (types.ContainerExecInspect, error) {
+	return types.ContainerExecInspect{ExitCode: c.exitCode}, nil
+}
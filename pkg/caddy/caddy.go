@@ -1,5 +1,14 @@
 package caddy
 
+const (
+	// ServerTiming is the Server-Timing header added to the responses of sites with slow
+	// request logging, it contains the time the site took to respond to the proxy.
+	ServerTiming = "upstream;desc=\"Site\";dur={http.reverse_proxy.upstream.latency_ms}"
+
+	// AccessLogger is the prefix of the logger names in the access log.
+	AccessLogger = "http.log.access."
+)
+
 type UpdateRequest struct {
 	HTTPS   Server `json:"https,omitempty"`
 	HTTP    Server `json:"http,omitempty"`
@@ -11,6 +20,14 @@ type Server struct {
 	Listen         []string       `json:"listen"`
 	Routes         []ServerRoute  `json:"routes"`
 	AutomaticHTTPS AutomaticHTTPS `json:"automatic_https"`
+	Logs           *ServerLogs    `json:"logs,omitempty"`
+}
+
+// ServerLogs enables the access log for the hosts in the logger names, the
+// name of the logger is appended to http.log.access.
+type ServerLogs struct {
+	LoggerNames       map[string]string `json:"logger_names,omitempty"`
+	SkipUnmappedHosts bool              `json:"skip_unmapped_hosts,omitempty"`
 }
 
 type AutomaticHTTPS struct {
@@ -25,14 +42,49 @@ type ServerRoute struct {
 }
 
 type RouteHandle struct {
-	Handler   string     `json:"handler"`
-	Root      string     `json:"root,omitempty"`
+	Handler string `json:"handler"`
+	Root    string `json:"root,omitempty"`
+
+	// StripPathPrefix removes the prefix from the request path with the rewrite handler
+	StripPathPrefix string `json:"strip_path_prefix,omitempty"`
+
 	Upstreams []Upstream `json:"upstreams,omitempty"`
 	Hide      []string   `json:"hide,omitempty"`
+	Response  *Headers   `json:"response,omitempty"`
+	Headers   *HeaderOps `json:"headers,omitempty"`
+	Transport *Transport `json:"transport,omitempty"`
+
+	LoadBalancing *LoadBalancing `json:"load_balancing,omitempty"`
+}
+
+// Transport is the protocol and the HTTP versions used for requests to the upstreams.
+type Transport struct {
+	Protocol string   `json:"protocol"`
+	Versions []string `json:"versions,omitempty"`
+}
+
+type LoadBalancing struct {
+	SelectionPolicy SelectionPolicy `json:"selection_policy"`
+}
+
+type SelectionPolicy struct {
+	Policy string `json:"policy"`
+}
+
+type Headers struct {
+	Add map[string][]string `json:"add,omitempty"`
+	Set map[string][]string `json:"set,omitempty"`
+}
+
+// HeaderOps changes the headers of the request to and the response from the upstream.
+type HeaderOps struct {
+	Request  *Headers `json:"request,omitempty"`
+	Response *Headers `json:"response,omitempty"`
 }
 
 type Match struct {
 	Host []string `json:"host"`
+	Path []string `json:"path,omitempty"`
 }
 
 type Upstream struct {
@@ -0,0 +1,165 @@
+package certificate
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+)
+
+const (
+	// RootPath is the path to the root certificate in the proxy container
+	RootPath = "/data/caddy/pki/authorities/local/root.crt"
+
+	// RenewWithin is how close to the expiry date a certificate is renewed
+	RenewWithin = 14 * 24 * time.Hour
+
+	// SitesDir is the directory the proxy stores site certificates in
+	SitesDir = "/data/caddy/certificates/local"
+)
+
+var (
+	// ErrNoCertificate is returned when the PEM data does not contain a certificate
+	ErrNoCertificate = fmt.Errorf("unable to find a certificate")
+)
+
+// SitePath returns the path to the certificate for the hostname in the proxy container.
+func SitePath(hostname string) string {
+	return path.Join(SitesDir, hostname, hostname+".crt")
+}
+
+// Expiry takes PEM encoded data and returns the expiry date of the first certificate.
+func Expiry(data []byte) (time.Time, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return time.Time{}, ErrNoCertificate
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("unable to parse the certificate, %w", err)
+	}
+
+	return cert.NotAfter, nil
+}
+
+// Expiring returns true if the expiry date is within the renewal period.
+func Expiring(notAfter, now time.Time) bool {
+	return notAfter.Sub(now) < RenewWithin
+}
+
+// Read copies the file at path from the container and returns the contents.
+func Read(ctx context.Context, docker client.ContainerAPIClient, containerID, path string) ([]byte, error) {
+	rdr, _, err := docker.CopyFromContainer(ctx, containerID, path)
+	if err != nil {
+		return nil, err
+	}
+	defer rdr.Close()
+
+	// the file is in a tar format
+	buf := new(bytes.Buffer)
+	tr := tar.NewReader(rdr)
+	for {
+		_, err := tr.Next()
+		// if end of tar archive
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := buf.ReadFrom(tr); err != nil {
+			return nil, err
+		}
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Expiries returns the expiry date of the certificate for each of the hostnames. Hostnames
+// without a certificate in the proxy container are not included.
+func Expiries(ctx context.Context, docker client.ContainerAPIClient, containerID string, hostnames []string) (map[string]time.Time, error) {
+	expiries := make(map[string]time.Time)
+	for _, h := range hostnames {
+		data, err := Read(ctx, docker, containerID, SitePath(h))
+		if err != nil {
+			// the proxy has not issued a certificate for the site yet
+			continue
+		}
+
+		notAfter, err := Expiry(data)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read the certificate for %s, %w", h, err)
+		}
+
+		expiries[h] = notAfter
+	}
+
+	return expiries, nil
+}
+
+// ExpiringSoon returns the hostnames, sorted by name, with a certificate that is within
+// the renewal period.
+func ExpiringSoon(expiries map[string]time.Time, now time.Time) []string {
+	var hostnames []string
+	for h, notAfter := range expiries {
+		if Expiring(notAfter, now) {
+			hostnames = append(hostnames, h)
+		}
+	}
+
+	sort.Strings(hostnames)
+
+	return hostnames
+}
+
+// Remove deletes the certificates for the hostnames from the proxy container. The proxy
+// issues a new certificate for each hostname the next time it is restarted.
+func Remove(ctx context.Context, docker client.ContainerAPIClient, containerID string, hostnames []string) error {
+	if len(hostnames) == 0 {
+		return nil
+	}
+
+	cmd := []string{"rm", "-rf"}
+	for _, h := range hostnames {
+		cmd = append(cmd, path.Join(SitesDir, h))
+	}
+
+	exec, err := docker.ContainerExecCreate(ctx, containerID, types.ExecConfig{Cmd: cmd})
+	if err != nil {
+		return fmt.Errorf("unable to remove the certificates, %w", err)
+	}
+
+	if err := docker.ContainerExecStart(ctx, exec.ID, types.ExecStartCheck{}); err != nil {
+		return fmt.Errorf("unable to remove the certificates, %w", err)
+	}
+
+	// wait for the exec to complete
+	for {
+		resp, err := docker.ContainerExecInspect(ctx, exec.ID)
+		if err != nil {
+			return err
+		}
+
+		if resp.Running {
+			time.Sleep(100 * time.Millisecond)
+			continue
+		}
+
+		if resp.ExitCode != 0 {
+			return fmt.Errorf("unable to remove the certificates, exit code %d", resp.ExitCode)
+		}
+
+		return nil
+	}
+}
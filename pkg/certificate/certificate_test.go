@@ -0,0 +1,137 @@
+package certificate
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+)
+
+func TestExpiries(t *testing.T) {
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	spy := &mockClient{files: map[string][]byte{
+		SitePath("expiring.nitro"): generate(t, now.Add(5*24*time.Hour)),
+		SitePath("valid.nitro"):    generate(t, now.Add(30*24*time.Hour)),
+		SitePath("expired.nitro"):  generate(t, now.Add(-24*time.Hour)),
+	}}
+
+	expiries, err := Expiries(context.Background(), spy, "proxy", []string{"expiring.nitro", "valid.nitro", "expired.nitro", "missing.nitro"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := expiries["missing.nitro"]; ok {
+		t.Errorf("expected sites without a certificate to be skipped")
+	}
+
+	if want := now.Add(30 * 24 * time.Hour); !expiries["valid.nitro"].Equal(want) {
+		t.Errorf("expected the expiry to be %v, got %v", want, expiries["valid.nitro"])
+	}
+
+	got := ExpiringSoon(expiries, now)
+	want := []string{"expired.nitro", "expiring.nitro"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ExpiringSoon() = %v, want %v", got, want)
+	}
+}
+
+func TestExpiry_InvalidData(t *testing.T) {
+	if _, err := Expiry([]byte("not a certificate")); err != ErrNoCertificate {
+		t.Errorf("expected ErrNoCertificate, got %v", err)
+	}
+}
+
+func TestRemove(t *testing.T) {
+	spy := &mockClient{}
+
+	if err := Remove(context.Background(), spy, "proxy", []string{"a.nitro", "b.nitro"}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"rm", "-rf", "/data/caddy/certificates/local/a.nitro", "/data/caddy/certificates/local/b.nitro"}
+	if !reflect.DeepEqual(spy.execCmd, want) {
+		t.Errorf("expected the exec command to be %v, got %v", want, spy.execCmd)
+	}
+}
+
+// generate returns a PEM encoded self-signed certificate that expires at notAfter.
+func generate(t *testing.T, notAfter time.Time) []byte {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "nitro"},
+		NotBefore:    notAfter.Add(-90 * 24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+type mockClient struct {
+	client.ContainerAPIClient
+
+	files   map[string][]byte
+	execCmd []string
+}
+
+func (c *mockClient) CopyFromContainer(ctx context.Context, container, srcPath string) (io.ReadCloser, types.ContainerPathStat, error) {
+	data, ok := c.files[srcPath]
+	if !ok {
+		return nil, types.ContainerPathStat{}, fmt.Errorf("no such file %s", srcPath)
+	}
+
+	buf := &bytes.Buffer{}
+	tw := tar.NewWriter(buf)
+	if err := tw.WriteHeader(&tar.Header{Name: "cert.crt", Mode: 0644, Size: int64(len(data))}); err != nil {
+		return nil, types.ContainerPathStat{}, err
+	}
+	if _, err := tw.Write(data); err != nil {
+		return nil, types.ContainerPathStat{}, err
+	}
+	if err := tw.Close(); err != nil {
+		return nil, types.ContainerPathStat{}, err
+	}
+
+	return ioutil.NopCloser(buf), types.ContainerPathStat{Name: "cert.crt", Mode: 0644}, nil
+}
+
+func (c *mockClient) ContainerExecCreate(ctx context.Context, container string, config types.ExecConfig) (types.IDResponse, error) {
+	c.execCmd = config.Cmd
+
+	return types.IDResponse{ID: "exec-id"}, nil
+}
+
+func (c *mockClient) ContainerExecStart(ctx context.Context, execID string, config types.ExecStartCheck) error {
+	return nil
+}
+
+func (c *mockClient) ContainerExecInspect(ctx context.Context, execID string) (types.ContainerExecInspect, error) {
+	return types.ContainerExecInspect{ExecID: execID}, nil
+}
@@ -3,7 +3,10 @@
 package certinstall
 
 import (
+	"crypto/sha1"
+	"encoding/pem"
 	"fmt"
+	"io/ioutil"
 
 	"github.com/craftcms/nitro/pkg/sudo"
 )
@@ -17,3 +20,25 @@ func Install(file, system string) error {
 
 	return nil
 }
+
+// Uninstall removes the root certificate installed by Install from the system keychain.
+func Uninstall(file, system string) error {
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return fmt.Errorf("unable to read the certificate, %w", err)
+	}
+
+	block, _ := pem.Decode(content)
+	if block == nil {
+		return fmt.Errorf("unable to decode the certificate %s", file)
+	}
+
+	// the keychain identifies certificates by the SHA-1 hash
+	hash := fmt.Sprintf("%X", sha1.Sum(block.Bytes))
+
+	if err := sudo.Run("security", "security", "delete-certificate", "-Z", hash, "-t", "/Library/Keychains/System.keychain"); err != nil {
+		return fmt.Errorf("unable to remove the certificate, %w", err)
+	}
+
+	return nil
+}
@@ -28,37 +28,9 @@ var (
 // Install is responsible for taking a path to a root certificate and the runtime.GOOS as the system
 // and finding the distribution and tools to install a root certificate.
 func Install(file, system string) error {
-	// find the release tool
-	lsb, _ := exec.LookPath("lsb_release")
-
-	var dist string
-	switch lsb == "" {
-	// lsb_release is not installed, so assume fedora or RHEL
-	case true:
-		dist = "fedora"
-	default:
-		// setup the command
-		cmd := exec.Command(lsb, "--description")
-
-		// capture the output into a temp file
-		buf := bytes.NewBufferString("")
-		cmd.Stdout = buf
-
-		if err := cmd.Start(); err != nil {
-			return err
-		}
-
-		if err := cmd.Wait(); err != nil {
-			return err
-		}
-
-		// find the linux distro
-		found, err := identify(buf.String())
-		if err != nil {
-			return err
-		}
-
-		dist = found
+	dist, err := distribution()
+	if err != nil {
+		return err
 	}
 
 	// get the certpath
@@ -92,6 +64,69 @@ func Install(file, system string) error {
 	return nil
 }
 
+// Uninstall removes the root certificate installed by Install and updates the trusted certificates.
+func Uninstall(file, system string) error {
+	dist, err := distribution()
+	if err != nil {
+		return err
+	}
+
+	certPath, ok := certificatePaths[dist]
+	if !ok {
+		return fmt.Errorf("unable to find the certificate path for %s", dist)
+	}
+
+	certTool, ok := certificateTools[dist]
+	if !ok {
+		return fmt.Errorf("unable to find the certificate tool for %s", dist)
+	}
+
+	if err := sudo.Run("rm", "rm", "-f", fmt.Sprintf("%s%s.crt", certPath, "nitro")); err != nil {
+		return fmt.Errorf("unable to remove the certificate, %w", err)
+	}
+
+	// update the ca certs
+	if err := sudo.Run(certTool, certTool); err != nil {
+		return err
+	}
+
+	// is this a wsl machine?
+	if _, exists := os.LookupEnv("WSL_DISTRO_NAME"); exists {
+		fmt.Println("Users on WSL will need to remove the Caddy Local Authority certificate from the Trusted Root Certification Authorities on Windows (certmgr.msc).")
+	}
+
+	return nil
+}
+
+// distribution returns the linux distribution used to find the certificate path and tool.
+func distribution() (string, error) {
+	// find the release tool
+	lsb, _ := exec.LookPath("lsb_release")
+
+	// lsb_release is not installed, so assume fedora or RHEL
+	if lsb == "" {
+		return "fedora", nil
+	}
+
+	// setup the command
+	cmd := exec.Command(lsb, "--description")
+
+	// capture the output into a temp file
+	buf := bytes.NewBufferString("")
+	cmd.Stdout = buf
+
+	if err := cmd.Start(); err != nil {
+		return "", err
+	}
+
+	if err := cmd.Wait(); err != nil {
+		return "", err
+	}
+
+	// find the linux distro
+	return identify(buf.String())
+}
+
 func identify(description string) (string, error) {
 	// detect arch systems
 	if strings.Contains(description, "Manjaro") || strings.Contains(description, "Arch Linux") {
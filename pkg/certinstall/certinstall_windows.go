@@ -0,0 +1,58 @@
+// +build windows
+
+package certinstall
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+)
+
+// Install is responsible for taking a path to a root certificate and the runtime.GOOS as the system
+// and adding the certificate to the trusted root store for the current user. Windows will show a
+// dialog to confirm the certificate should be trusted.
+func Install(file, system string) error {
+	if err := certutil("-user", "-addstore", "Root", file); err != nil {
+		return fmt.Errorf("unable to install the certificate, %w", err)
+	}
+
+	return nil
+}
+
+// Uninstall removes the root certificate installed by Install from the trusted root store
+// for the current user.
+func Uninstall(file, system string) error {
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return fmt.Errorf("unable to read the certificate, %w", err)
+	}
+
+	block, _ := pem.Decode(content)
+	if block == nil {
+		return fmt.Errorf("unable to decode the certificate %s", file)
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return fmt.Errorf("unable to parse the certificate %s, %w", file, err)
+	}
+
+	// the store identifies certificates by the serial number
+	if err := certutil("-user", "-delstore", "Root", fmt.Sprintf("%x", cert.SerialNumber)); err != nil {
+		return fmt.Errorf("unable to remove the certificate, %w", err)
+	}
+
+	return nil
+}
+
+func certutil(args ...string) error {
+	cmd := exec.Command("certutil", args...)
+
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	return cmd.Run()
+}
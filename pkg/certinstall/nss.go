@@ -0,0 +1,127 @@
+package certinstall
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// NSSName is the nickname of the root certificate in the NSS databases.
+const NSSName = "Nitro Local CA"
+
+var (
+	// ErrNoCertutil is returned when the NSS certutil tool is not installed
+	ErrNoCertutil = fmt.Errorf("unable to find certutil, install libnss3-tools (Debian and Ubuntu), nss-tools (Fedora), or nss (Homebrew) to trust the certificate in Firefox")
+
+	// nssProfiles are the directories, relative to the home directory, that contain
+	// Firefox profiles for each system
+	nssProfiles = map[string][]string{
+		"darwin": {"Library/Application Support/Firefox/Profiles"},
+		"linux":  {".mozilla/firefox", "snap/firefox/common/.mozilla/firefox"},
+	}
+
+	// certutilPaths are the locations certutil is installed by Homebrew, which
+	// does not link it into the path
+	certutilPaths = []string{"/usr/local/opt/nss/bin/certutil", "/opt/homebrew/opt/nss/bin/certutil"}
+)
+
+// NSSDatabases returns the NSS databases in the home directory. Firefox keeps a database
+// in each profile and Chrome on Linux uses the shared database in ~/.pki/nssdb. Windows
+// is not supported since Firefox can use the Windows certificate store.
+func NSSDatabases(home, system string) []string {
+	var dirs []string
+	if system == "linux" {
+		dirs = append(dirs, filepath.Join(home, ".pki", "nssdb"))
+	}
+
+	for _, p := range nssProfiles[system] {
+		profiles, err := filepath.Glob(filepath.Join(home, p, "*"))
+		if err != nil {
+			continue
+		}
+
+		dirs = append(dirs, profiles...)
+	}
+
+	var databases []string
+	for _, d := range dirs {
+		if db := nssDatabase(d); db != "" {
+			databases = append(databases, db)
+		}
+	}
+
+	return databases
+}
+
+// InstallNSS adds the root certificate to each of the NSS databases.
+func InstallNSS(file string, databases []string) error {
+	bin, err := certutilPath()
+	if err != nil {
+		return err
+	}
+
+	for _, db := range databases {
+		// remove a certificate from a previous proxy so the nickname is not reused
+		_ = exec.Command(bin, "-D", "-d", db, "-n", NSSName).Run()
+
+		if out, err := exec.Command(bin, "-A", "-d", db, "-t", "C,,", "-n", NSSName, "-i", file).CombinedOutput(); err != nil {
+			return fmt.Errorf("unable to add the certificate to %s, %w: %s", db, err, out)
+		}
+	}
+
+	return nil
+}
+
+// UninstallNSS removes the root certificate installed by InstallNSS from each of the NSS databases.
+func UninstallNSS(databases []string) error {
+	if len(databases) == 0 {
+		return nil
+	}
+
+	bin, err := certutilPath()
+	if err != nil {
+		return err
+	}
+
+	for _, db := range databases {
+		// a database might not contain the certificate
+		if err := exec.Command(bin, "-L", "-d", db, "-n", NSSName).Run(); err != nil {
+			continue
+		}
+
+		if out, err := exec.Command(bin, "-D", "-d", db, "-n", NSSName).CombinedOutput(); err != nil {
+			return fmt.Errorf("unable to remove the certificate from %s, %w: %s", db, err, out)
+		}
+	}
+
+	return nil
+}
+
+// nssDatabase returns the database for certutil in the directory, prefixed with the
+// database format, or an empty string if the directory does not contain a database.
+func nssDatabase(dir string) string {
+	if _, err := os.Stat(filepath.Join(dir, "cert9.db")); err == nil {
+		return "sql:" + dir
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "cert8.db")); err == nil {
+		return "dbm:" + dir
+	}
+
+	return ""
+}
+
+func certutilPath() (string, error) {
+	if p, err := exec.LookPath("certutil"); err == nil {
+		return p, nil
+	}
+
+	for _, p := range certutilPaths {
+		if _, err := os.Stat(p); err == nil {
+			return p, nil
+		}
+	}
+
+	return "", ErrNoCertutil
+}
@@ -0,0 +1,47 @@
+package certinstall
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestNSSDatabases(t *testing.T) {
+	home, err := ioutil.TempDir("", "nitro-nss")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(home)
+
+	files := []string{
+		".pki/nssdb/cert9.db",
+		".mozilla/firefox/abc.default/cert9.db",
+		".mozilla/firefox/def.legacy/cert8.db",
+		".mozilla/firefox/ghi.empty/prefs.js",
+	}
+	for _, f := range files {
+		p := filepath.Join(home, f)
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(p, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got := NSSDatabases(home, "linux")
+	want := []string{
+		"sql:" + filepath.Join(home, ".pki/nssdb"),
+		"sql:" + filepath.Join(home, ".mozilla/firefox/abc.default"),
+		"dbm:" + filepath.Join(home, ".mozilla/firefox/def.legacy"),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("NSSDatabases() = %v, want %v", got, want)
+	}
+
+	if got := NSSDatabases(home, "windows"); len(got) != 0 {
+		t.Errorf("expected no databases on windows, got %v", got)
+	}
+}
@@ -0,0 +1,72 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/craftcms/nitro/protob"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// NewClient is used for generating a new client to interact
+// with the gRPC API running in the proxy container
+func NewClient(ip, port string) (protob.NitroClient, error) {
+	cc, err := grpc.Dial(ip+":"+port, grpc.WithInsecure())
+	if err != nil {
+		return nil, fmt.Errorf("unable to create a gRPC client for nitrod, %w", err)
+	}
+
+	return protob.NewNitroClient(cc), nil
+}
+
+// IsUnimplemented returns true when the API in the proxy container does not support the
+// request, which happens when the proxy was created by an older version of nitro.
+func IsUnimplemented(err error) bool {
+	return status.Code(err) == codes.Unimplemented
+}
+
+// RootCertificate returns the PEM encoded root certificate from the proxy.
+func RootCertificate(ctx context.Context, nitrod protob.NitroClient) ([]byte, error) {
+	resp, err := nitrod.Certificates(ctx, &protob.CertificatesRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(resp.GetRoot()), nil
+}
+
+// Expiries returns the expiry date of the certificate the proxy has issued for each
+// of the hostnames. Hostnames without a certificate are not included.
+func Expiries(ctx context.Context, nitrod protob.NitroClient, hostnames []string) (map[string]time.Time, error) {
+	resp, err := nitrod.Certificates(ctx, &protob.CertificatesRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	wanted := make(map[string]bool)
+	for _, h := range hostnames {
+		wanted[h] = true
+	}
+
+	expiries := make(map[string]time.Time)
+	for _, c := range resp.GetCertificates() {
+		if wanted[c.GetHostname()] {
+			expiries[c.GetHostname()] = time.Unix(c.GetExpires(), 0)
+		}
+	}
+
+	return expiries, nil
+}
+
+// Sites returns the sites the proxy is routing requests to.
+func Sites(ctx context.Context, nitrod protob.NitroClient) (map[string]*protob.Site, error) {
+	resp, err := nitrod.Sites(ctx, &protob.SitesRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.GetSites(), nil
+}
@@ -0,0 +1,56 @@
+package clipboard
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/craftcms/nitro/pkg/wsl"
+)
+
+// Copy places the text on the system clipboard using the clipboard tool for
+// the operating system. On Linux, one of wl-copy, xclip, or xsel must be
+// installed.
+func Copy(text string) error {
+	name, args, err := command(runtime.GOOS, wsl.IsWSL(), exec.LookPath)
+	if err != nil {
+		return err
+	}
+
+	c := exec.Command(name, args...)
+	c.Stdin = strings.NewReader(text)
+
+	if out, err := c.CombinedOutput(); err != nil {
+		return fmt.Errorf("unable to copy to the clipboard, %s %w", strings.TrimSpace(string(out)), err)
+	}
+
+	return nil
+}
+
+// command returns the clipboard tool and arguments for the operating system.
+func command(goos string, isWSL bool, lookPath func(string) (string, error)) (string, []string, error) {
+	switch {
+	case goos == "darwin":
+		return "pbcopy", nil, nil
+	case goos == "windows", isWSL:
+		return "clip.exe", nil, nil
+	}
+
+	tools := []struct {
+		name string
+		args []string
+	}{
+		{name: "wl-copy"},
+		{name: "xclip", args: []string{"-selection", "clipboard"}},
+		{name: "xsel", args: []string{"--clipboard", "--input"}},
+	}
+
+	for _, t := range tools {
+		if _, err := lookPath(t.name); err == nil {
+			return t.name, t.args, nil
+		}
+	}
+
+	return "", nil, fmt.Errorf("unable to find a clipboard tool, install wl-copy, xclip, or xsel")
+}
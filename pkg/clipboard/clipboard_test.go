@@ -0,0 +1,82 @@
+package clipboard
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestCommand(t *testing.T) {
+	tests := []struct {
+		name      string
+		goos      string
+		wsl       bool
+		installed []string
+		wantName  string
+		wantArgs  []string
+		wantErr   bool
+	}{
+		{
+			name:     "macOS uses pbcopy",
+			goos:     "darwin",
+			wantName: "pbcopy",
+		},
+		{
+			name:     "windows uses clip",
+			goos:     "windows",
+			wantName: "clip.exe",
+		},
+		{
+			name:      "wsl uses the windows clipboard",
+			goos:      "linux",
+			wsl:       true,
+			installed: []string{"xclip"},
+			wantName:  "clip.exe",
+		},
+		{
+			name:      "linux prefers wl-copy",
+			goos:      "linux",
+			installed: []string{"xclip", "wl-copy"},
+			wantName:  "wl-copy",
+		},
+		{
+			name:      "linux falls back to xclip",
+			goos:      "linux",
+			installed: []string{"xsel", "xclip"},
+			wantName:  "xclip",
+			wantArgs:  []string{"-selection", "clipboard"},
+		},
+		{
+			name:    "linux without a clipboard tool returns an error",
+			goos:    "linux",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lookPath := func(name string) (string, error) {
+				for _, i := range tt.installed {
+					if i == name {
+						return "/usr/bin/" + name, nil
+					}
+				}
+
+				return "", fmt.Errorf("not found")
+			}
+
+			name, args, err := command(tt.goos, tt.wsl, lookPath)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("command() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if name != tt.wantName {
+				t.Errorf("command() name = %v, want %v", name, tt.wantName)
+			}
+
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("command() args = %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+}
@@ -1,14 +1,21 @@
 package config
 
 import (
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"regexp"
 	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/craftcms/nitro/pkg/environment"
 	"github.com/craftcms/nitro/pkg/helpers"
+	"github.com/craftcms/nitro/pkg/wsl"
 
 	"gopkg.in/yaml.v3"
 )
@@ -46,14 +53,24 @@ var (
 	}
 )
 
-// Config represents the nitro-dev.yaml users add for local development.
+// Config represents the nitro.yaml users add for local development.
 type Config struct {
-	Containers []Container `json:"containers,omitempty" yaml:"containers,omitempty"`
-	Blackfire  Blackfire   `json:"blackfire,omitempty" yaml:"blackfire,omitempty"`
-	Databases  []Database  `json:"databases,omitempty" yaml:"databases,omitempty"`
-	Services   Services    `json:"services" yaml:"services"`
-	Sites      []Site      `json:"sites,omitempty" yaml:"sites,omitempty"`
-	File       string      `json:"-" yaml:"-"`
+	Name       string            `json:"name,omitempty" yaml:"name,omitempty"`
+	Containers []Container       `json:"containers,omitempty" yaml:"containers,omitempty"`
+	Backups    Backups           `json:"backups,omitempty" yaml:"backups,omitempty"`
+	Blackfire  Blackfire         `json:"blackfire,omitempty" yaml:"blackfire,omitempty"`
+	Databases  []Database        `json:"databases,omitempty" yaml:"databases,omitempty"`
+	Defaults   Defaults          `json:"defaults,omitempty" yaml:"defaults,omitempty"`
+	Hooks      Hooks             `json:"hooks,omitempty" yaml:"hooks,omitempty"`
+	HostRoutes map[string]string `json:"host_routes,omitempty" yaml:"host_routes,omitempty"`
+	Logs       Logs              `json:"logs,omitempty" yaml:"logs,omitempty"`
+	Mail       Mail              `json:"mail,omitempty" yaml:"mail,omitempty"`
+	Minio      Minio             `json:"minio,omitempty" yaml:"minio,omitempty"`
+	Proxy      Proxy             `json:"proxy,omitempty" yaml:"proxy,omitempty"`
+	Services   Services          `json:"services" yaml:"services"`
+	Sites      []Site            `json:"sites,omitempty" yaml:"sites,omitempty"`
+	Workspaces []string          `json:"workspaces,omitempty" yaml:"workspaces,omitempty"`
+	File       string            `json:"-" yaml:"-"`
 
 	// rw sync.RWMutex
 }
@@ -75,6 +92,46 @@ func (c *Config) AllSitesWithHostnames(site Site, addr string) map[string][]stri
 	return hostnames
 }
 
+// Hostnames returns the sorted hostnames and aliases of the sites and the hostnames
+// of the custom containers, host routes, dashboard, mailhog, and companion web
+// interfaces, which are added to the hosts file.
+func (c *Config) Hostnames() []string {
+	seen := map[string]bool{}
+	var hostnames []string
+	add := func(names ...string) {
+		for _, n := range names {
+			if n != "" && !seen[n] {
+				seen[n] = true
+				hostnames = append(hostnames, n)
+			}
+		}
+	}
+
+	for _, s := range c.Sites {
+		add(s.Hostname)
+		add(s.Aliases...)
+	}
+
+	for _, ct := range c.Containers {
+		add(ct.GetHostnames()...)
+	}
+
+	for h := range c.HostRoutes {
+		add(h)
+	}
+
+	add(c.DashboardHostname())
+	add(c.MailHostname())
+
+	for name := range Companions {
+		add(c.UIHostname(name))
+	}
+
+	sort.Strings(hostnames)
+
+	return hostnames
+}
+
 // FindContainerByName takes a name and returns the container if name matches.
 func (c *Config) FindContainerByName(name string) (*Container, error) {
 	// find the site by the hostname
@@ -160,6 +217,21 @@ type Blackfire struct {
 	ServerToken string `json:"server_token,omitempty" yaml:"server_token,omitempty"`
 }
 
+// Credentials returns the server credentials, the BLACKFIRE_SERVER_ID and
+// BLACKFIRE_SERVER_TOKEN environment variables are used when the config
+// does not set them.
+func (b Blackfire) Credentials() Blackfire {
+	if b.ServerID == "" {
+		b.ServerID = os.Getenv("BLACKFIRE_SERVER_ID")
+	}
+
+	if b.ServerToken == "" {
+		b.ServerToken = os.Getenv("BLACKFIRE_SERVER_TOKEN")
+	}
+
+	return b
+}
+
 // Container represents a custom container to add to nitro. Containers can be
 // publicly hosted on Docker Hub.
 type Container struct {
@@ -172,91 +244,909 @@ type Container struct {
 	// Tag tells Nitro which docker image tag to use, it defaults to latest.
 	Tag string `json:"tag" yaml:"tag"`
 
-	// Ports is used to expose ports on the host machine to the
-	// containers port in the <host>:<container> syntax
-	Ports []string `json:"ports,omitempty" yaml:"ports,omitempty"`
+	// Ports is used to expose ports on the host machine to the
+	// containers port in the <host>:<container> syntax
+	Ports []string `json:"ports,omitempty" yaml:"ports,omitempty"`
+
+	// Volume stores the volumes we should create and maintain for the container (e.g. <name>_container_<vol>_nitro_volume)
+	Volumes []string `json:"volumes,omitempty" yaml:"volumes,omitempty"`
+
+	WebGui  int    `json:"web_gui,omitempty" yaml:"web_gui,omitempty"`
+	EnvFile string `json:"env_file,omitempty" yaml:"env_file,omitempty"`
+
+	// Protected prevents the containers volumes from being removed by destroy
+	Protected bool `json:"protected,omitempty" yaml:"protected,omitempty"`
+
+	// Replicas is the number of containers to run for stateless containers, requests
+	// to the containers hostname are balanced between the replicas. It defaults to 1.
+	Replicas int `json:"replicas,omitempty" yaml:"replicas,omitempty"`
+}
+
+// GetReplicas returns the number of containers to run for the container.
+func (c *Container) GetReplicas() int {
+	if c.Replicas < 1 {
+		return 1
+	}
+
+	return c.Replicas
+}
+
+// GetHostnames returns the hostname of each replica for the container. The first replica
+// uses the containers hostname (e.g. chrome.containers.nitro) and the other replicas
+// are numbered (e.g. chrome-2.containers.nitro).
+func (c *Container) GetHostnames() []string {
+	var hostnames []string
+	for n := 1; n <= c.GetReplicas(); n++ {
+		hostnames = append(hostnames, c.GetReplicaHostname(n))
+	}
+
+	return hostnames
+}
+
+// GetReplicaHostname returns the hostname for a replica of the container.
+func (c *Container) GetReplicaHostname(replica int) string {
+	if replica <= 1 {
+		return fmt.Sprintf("%s.containers.nitro", c.Name)
+	}
+
+	return fmt.Sprintf("%s-%d.containers.nitro", c.Name, replica)
+}
+
+// GetVolumeName takes a path in the container and returns the name
+// of the volume that is mounted to the path (e.g. nitro_elasticsearch__data).
+func (c *Container) GetVolumeName(path string) string {
+	return fmt.Sprintf("nitro_%s_%s", c.Name, strings.Replace(path, "/", "_", -1))
+}
+
+// AddContainer adds a new container config to an config. It will validate there are no other
+// container names to avoid colision or duplicate ports.
+func (c *Config) AddContainer(container Container) error {
+	for _, e := range c.Containers {
+		// check the containers name
+		if e.Name == container.Name {
+			return fmt.Errorf("an existing container %q already exists", e.Name)
+		}
+
+		// TODO(jasonmccallister) check is a port already is in use
+	}
+
+	c.Containers = append(c.Containers, container)
+
+	sort.SliceStable(c.Containers, func(i, j int) bool {
+		return c.Containers[i].Name < c.Containers[j].Name
+	})
+
+	return nil
+}
+
+// Database is the struct used to represent a database engine
+// that is a combination of a engine (e.g. mariadb, mysql, or
+// postgres), the version number, and the port. The engine
+// and version are directly related to the official docker
+// images on the docker hub.
+type Database struct {
+	Engine    string `json:"engine" yaml:"engine"`
+	Version   string `json:"version" yaml:"version"`
+	Port      string `json:"port" yaml:"port"`
+	Protected bool   `json:"protected,omitempty" yaml:"protected,omitempty"`
+
+	// Roles and Extensions are only used by postgres databases
+	Roles      []string `json:"roles,omitempty" yaml:"roles,omitempty"`
+	Extensions []string `json:"extensions,omitempty" yaml:"extensions,omitempty"`
+}
+
+// GetHostname returns a friendly and predictable name for a database
+// container. It is used for accessing a database by hostname. For
+// example, mysql-8.0-3306 would be the hostname to use in the .env
+// for DB_HOST.
+func (d *Database) GetHostname() (string, error) {
+	if d.Engine == "" || d.Version == "" || d.Port == "" {
+		return "", fmt.Errorf("the engine, version, and port must be defined for the database")
+	}
+
+	return fmt.Sprintf("%s-%s-%s.database.nitro", d.Engine, d.Version, d.Port), nil
+}
+
+// SiteDatabase returns the database container the database of the site is in, the
+// engine of the site is matched by hostname and defaults to the first database.
+func (c *Config) SiteDatabase(site Site) (*Database, error) {
+	if len(c.Databases) == 0 {
+		return nil, fmt.Errorf("the site %s uses the database %s but there are no database engines, run `nitro db new` to add one", site.Hostname, site.Database)
+	}
+
+	if site.DatabaseEngine == "" {
+		return &c.Databases[0], nil
+	}
+
+	for i, d := range c.Databases {
+		if h, err := d.GetHostname(); err == nil && h == site.DatabaseEngine {
+			return &c.Databases[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("unable to find the database engine %s for the site %s", site.DatabaseEngine, site.Hostname)
+}
+
+// ProtectedVolumes returns the names of all the volumes for databases
+// and custom containers that are marked as protected in the config.
+// Protected volumes should not be removed unless a user explicitly
+// asks to include them.
+func (c *Config) ProtectedVolumes() map[string]bool {
+	volumes := make(map[string]bool)
+
+	// the database volumes use the hostname of the database
+	for _, d := range c.Databases {
+		if !d.Protected {
+			continue
+		}
+
+		if h, err := d.GetHostname(); err == nil {
+			volumes[h] = true
+		}
+	}
+
+	for _, ct := range c.Containers {
+		if !ct.Protected {
+			continue
+		}
+
+		for _, v := range ct.Volumes {
+			volumes[ct.GetVolumeName(v)] = true
+		}
+	}
+
+	return volumes
+}
+
+// Defaults are the values used when adding new sites. TLD is appended to the
+// directory name to create the hostname and PHP is the default PHP version.
+type Defaults struct {
+	TLD string `json:"tld,omitempty" yaml:"tld,omitempty"`
+	PHP string `json:"php,omitempty" yaml:"php,omitempty"`
+}
+
+// HostGateway is the hostname containers use to connect to the host machine.
+const HostGateway = "host.docker.internal"
+
+// HostRoute is an application running on the host machine, or another host, that
+// the proxy forwards requests for a hostname to.
+type HostRoute struct {
+	Host string
+	Port int
+}
+
+// GetHostRoutes parses the host routes in the config, keyed by the hostname. The target of
+// each route is a port on the host machine (e.g. host:8080) or another host and port.
+func (c *Config) GetHostRoutes() (map[string]HostRoute, error) {
+	sites := make(map[string]bool)
+	for _, s := range c.Sites {
+		sites[s.Hostname] = true
+		for _, a := range s.Aliases {
+			sites[a] = true
+		}
+	}
+
+	routes := make(map[string]HostRoute)
+	for hostname, target := range c.HostRoutes {
+		if sites[hostname] {
+			return nil, fmt.Errorf("the host route %s is already used by a site", hostname)
+		}
+
+		i := strings.LastIndex(target, ":")
+		if i == -1 {
+			return nil, fmt.Errorf("the host route %s must use the host:<port> format, got %q", hostname, target)
+		}
+
+		port, err := strconv.Atoi(target[i+1:])
+		if err != nil || port < 1 || port > 65535 {
+			return nil, fmt.Errorf("the host route %s has an invalid port %q", hostname, target[i+1:])
+		}
+
+		host := target[:i]
+		if host == "" || host == "host" || host == "localhost" || host == "127.0.0.1" {
+			host = HostGateway
+		}
+
+		routes[hostname] = HostRoute{Host: host, Port: port}
+	}
+
+	return routes, nil
+}
+
+// Proxy is used to configure the debugging options for the proxy. DebugHeaders
+// adds headers (e.g. X-Nitro-Site) to each sites response and DebugBanner adds
+// a banner to HTML pages for sites that are in devMode. Dashboard serves a page
+// listing the sites and services at nitro.<tld>.
+type Proxy struct {
+	DebugHeaders bool `json:"debug_headers,omitempty" yaml:"debug_headers,omitempty"`
+	DebugBanner  bool `json:"debug_banner,omitempty" yaml:"debug_banner,omitempty"`
+	Dashboard    bool `json:"dashboard,omitempty" yaml:"dashboard,omitempty"`
+
+	// Ports are the host ports of the proxy, they are set for named environments so
+	// the proxies of the environments do not use the same ports
+	Ports environment.PortSet `json:"ports,omitempty" yaml:"ports,omitempty"`
+}
+
+// Backups schedules backups of every database. Schedule is a cron expression (e.g.
+// "0 */6 * * *") and Keep is the number of backups kept for each database, zero keeps
+// every backup.
+type Backups struct {
+	Schedule string `json:"schedule,omitempty" yaml:"schedule,omitempty"`
+	Keep     int    `json:"keep,omitempty" yaml:"keep,omitempty"`
+}
+
+// Enabled returns true if the backups have a schedule.
+func (b *Backups) Enabled() bool {
+	return b.Schedule != ""
+}
+
+// Validate checks that the schedule is a cron expression with five fields and that the
+// number of backups to keep is not negative.
+func (b *Backups) Validate() error {
+	if !b.Enabled() {
+		return nil
+	}
+
+	if len(strings.Fields(b.Schedule)) != 5 {
+		return fmt.Errorf("the backup schedule %q must be a cron expression with five fields (e.g. \"0 */6 * * *\")", b.Schedule)
+	}
+
+	if b.Keep < 0 {
+		return fmt.Errorf("the number of backups to keep must not be negative")
+	}
+
+	return nil
+}
+
+// Logs are the options of the log driver for the containers, the options are set when the
+// containers are created. Sites can override the options and the other containers use the
+// options in the config.
+type Logs struct {
+	// Driver is the log driver (json-file or local), json-file is used when only the
+	// options are set
+	Driver string `json:"driver,omitempty" yaml:"driver,omitempty"`
+
+	// MaxSize is the size of a log file before it is rotated (e.g. 10m)
+	MaxSize string `json:"max_size,omitempty" yaml:"max_size,omitempty"`
+
+	// MaxFile is the number of log files to keep when the logs are rotated
+	MaxFile int `json:"max_file,omitempty" yaml:"max_file,omitempty"`
+}
+
+// LogDrivers are the log drivers that support rotating the logs.
+var LogDrivers = []string{"json-file", "local"}
+
+var logSize = regexp.MustCompile(`^[0-9]+[kmg]?$`)
+
+// IsSet returns true if the log driver or any of the options are set.
+func (l Logs) IsSet() bool {
+	return l.Driver != "" || l.MaxSize != "" || l.MaxFile != 0
+}
+
+// Merge returns the options with the values that are set in the override.
+func (l Logs) Merge(override Logs) Logs {
+	if override.Driver != "" {
+		l.Driver = override.Driver
+	}
+
+	if override.MaxSize != "" {
+		l.MaxSize = override.MaxSize
+	}
+
+	if override.MaxFile != 0 {
+		l.MaxFile = override.MaxFile
+	}
+
+	return l
+}
+
+// Type returns the log driver, json-file is returned when only the options are set and
+// an empty string uses the default driver of docker.
+func (l Logs) Type() string {
+	if l.Driver == "" && l.IsSet() {
+		return "json-file"
+	}
+
+	return l.Driver
+}
+
+// Options returns the options for the log driver (e.g. max-size=10m and max-file=3).
+func (l Logs) Options() map[string]string {
+	opts := map[string]string{}
+	if l.MaxSize != "" {
+		opts["max-size"] = l.MaxSize
+	}
+
+	if l.MaxFile > 0 {
+		opts["max-file"] = strconv.Itoa(l.MaxFile)
+	}
+
+	return opts
+}
+
+// Validate checks that the driver supports rotating the logs and that the options are
+// valid for the driver.
+func (l Logs) Validate() error {
+	if !l.IsSet() {
+		return nil
+	}
+
+	supported := false
+	for _, d := range LogDrivers {
+		supported = supported || d == l.Type()
+	}
+
+	if !supported {
+		return fmt.Errorf("the log driver %q is not supported, use one of %s", l.Driver, strings.Join(LogDrivers, ", "))
+	}
+
+	if l.MaxSize != "" && !logSize.MatchString(l.MaxSize) {
+		return fmt.Errorf("the log max_size %q must be a number with an optional unit of k, m, or g (e.g. 10m)", l.MaxSize)
+	}
+
+	if l.MaxFile < 0 {
+		return fmt.Errorf("the log max_file must not be negative")
+	}
+
+	return nil
+}
+
+// SiteLogs returns the log options for the site, the options of the site override the
+// options in the config.
+func (c *Config) SiteLogs(site Site) Logs {
+	return c.Logs.Merge(site.Logs)
+}
+
+// Resources are the limits for the CPUs and memory of a container. CPUs is the number of
+// CPUs the container can use (e.g. 0.5) and Memory is a number with an optional unit of
+// k, m, or g (e.g. 512m).
+type Resources struct {
+	CPUs   float64 `json:"cpus,omitempty" yaml:"cpus,omitempty"`
+	Memory string  `json:"memory,omitempty" yaml:"memory,omitempty"`
+}
+
+var memorySize = regexp.MustCompile(`^([0-9]+)([kmg]?)$`)
+
+// minMemory is the smallest memory limit docker allows.
+const minMemory = 6 * 1024 * 1024
+
+// NanoCPUs returns the CPUs in the units docker uses, zero is not limited.
+func (r Resources) NanoCPUs() int64 {
+	return int64(r.CPUs * 1e9)
+}
+
+// MemoryBytes returns the memory limit in bytes, zero is not limited.
+func (r Resources) MemoryBytes() int64 {
+	m := memorySize.FindStringSubmatch(strings.ToLower(r.Memory))
+	if m == nil {
+		return 0
+	}
+
+	n, err := strconv.ParseInt(m[1], 10, 64)
+	if err != nil {
+		return 0
+	}
+
+	switch m[2] {
+	case "k":
+		n *= 1024
+	case "m":
+		n *= 1024 * 1024
+	case "g":
+		n *= 1024 * 1024 * 1024
+	}
+
+	return n
+}
+
+// Validate checks that the CPUs are not negative and the memory is at least the smallest
+// limit docker allows.
+func (r Resources) Validate() error {
+	if r.CPUs < 0 {
+		return fmt.Errorf("the cpus must not be negative")
+	}
+
+	if r.Memory == "" {
+		return nil
+	}
+
+	if !memorySize.MatchString(strings.ToLower(r.Memory)) {
+		return fmt.Errorf("the memory %q must be a number with an optional unit of k, m, or g (e.g. 512m)", r.Memory)
+	}
+
+	if r.MemoryBytes() < minMemory {
+		return fmt.Errorf("the memory %q must be at least 6m", r.Memory)
+	}
+
+	return nil
+}
+
+// ServiceResources returns the resource limits of the named service.
+func (c *Config) ServiceResources(name string) Resources {
+	return c.Services.Limits[name]
+}
+
+// ValidateLimits checks the resource limits of each site and service and that the limits
+// are for known services.
+func (c *Config) ValidateLimits() error {
+	for _, s := range c.Sites {
+		if err := s.Resources().Validate(); err != nil {
+			return fmt.Errorf("%s: %w", s.Hostname, err)
+		}
+	}
+
+	for name, r := range c.Services.Limits {
+		if c.Services.field(name) == nil {
+			return fmt.Errorf("unable to limit the unknown service %q", name)
+		}
+
+		if err := r.Validate(); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// Hooks are the commands that run when apply makes changes to the environment. The
+// pre_apply hooks run before any changes are made and a failure stops the apply.
+type Hooks struct {
+	PreApply       []Hook `json:"pre_apply,omitempty" yaml:"pre_apply,omitempty"`
+	PostApply      []Hook `json:"post_apply,omitempty" yaml:"post_apply,omitempty"`
+	PostSiteCreate []Hook `json:"post_site_create,omitempty" yaml:"post_site_create,omitempty"`
+	PostDBCreate   []Hook `json:"post_db_create,omitempty" yaml:"post_db_create,omitempty"`
+}
+
+// Hook is a shell command that runs on the host, or inside of the named container when
+// Container is set (e.g. craft.nitro).
+type Hook struct {
+	Run       string `json:"run" yaml:"run"`
+	Container string `json:"container,omitempty" yaml:"container,omitempty"`
+}
+
+// DashboardHostname returns the hostname the proxy serves the dashboard on, or an
+// empty string when the dashboard is not enabled.
+func (c *Config) DashboardHostname() string {
+	if !c.Proxy.Dashboard {
+		return ""
+	}
+
+	return "nitro." + c.GetTLD()
+}
+
+// Mail configures the mailhog service. Hostname is the hostname the proxy serves the web
+// interface on, it defaults to mailhog.<tld>.
+type Mail struct {
+	Hostname string `json:"hostname,omitempty" yaml:"hostname,omitempty"`
+}
+
+// MailHostname returns the hostname the proxy serves the mailhog web interface on, or an
+// empty string when mailhog is not enabled.
+func (c *Config) MailHostname() string {
+	if !c.Services.Mailhog {
+		return ""
+	}
+
+	if c.Mail.Hostname != "" {
+		return c.Mail.Hostname
+	}
+
+	return "mailhog." + c.GetTLD()
+}
+
+// UIHostname returns the hostname the proxy serves the companion web interface on (e.g.
+// redisinsight.nitro), or an empty string when the web interface is not enabled.
+func (c *Config) UIHostname(name string) string {
+	if _, ok := Companions[name]; !ok || !c.Services.IsEnabled(name) {
+		return ""
+	}
+
+	return name + "." + c.GetTLD()
+}
+
+// Minio configures the minio service. Buckets are created when the service starts so the
+// asset volumes of the sites can use them right away.
+type Minio struct {
+	Buckets []string `json:"buckets,omitempty" yaml:"buckets,omitempty"`
+}
+
+// bucketName matches the S3 rules for bucket names: 3 to 63 lowercase letters, numbers,
+// dots, and hyphens that start and end with a letter or number.
+var bucketName = regexp.MustCompile(`^[a-z0-9][a-z0-9.-]{1,61}[a-z0-9]$`)
+
+// Validate checks that each bucket has a valid name.
+func (m *Minio) Validate() error {
+	for _, b := range m.Buckets {
+		if !bucketName.MatchString(b) || strings.Contains(b, "..") {
+			return fmt.Errorf("the minio bucket %q must be 3 to 63 lowercase letters, numbers, dots, or hyphens", b)
+		}
+	}
+
+	return nil
+}
+
+// Services define common tools for development that should run as containers. We don't expose the volumes, ports, and
+// networking options for these types of services. We plan to support "custom" container options to make local users
+// development even better.
+type Services struct {
+	Blackfire bool `json:"blackfire"`
+	DynamoDB  bool `json:"dynamodb"`
+	Gotenberg bool `json:"gotenberg"`
+	Mailhog   bool `json:"mailhog"`
+	Minio     bool `json:"minio"`
+	Redis     bool `json:"redis"`
+
+	// Limits are the resource limits of the services by name (e.g. redis)
+	Limits map[string]Resources `json:"limits,omitempty" yaml:"limits,omitempty"`
+
+	// UI enables the companion web interface of the services by name (e.g. redis)
+	UI map[string]bool `json:"ui,omitempty" yaml:"ui,omitempty"`
+}
+
+// ServiceNames are the names of the services that can be enabled in the config, including
+// the companion web interfaces.
+var ServiceNames = []string{"blackfire", "dynamodb", "gotenberg", "mailhog", "minio", "redis", "redisinsight"}
+
+// Companions are the names of the companion web interfaces and the service they connect to.
+var Companions = map[string]string{
+	"redisinsight": "redis",
+}
+
+// IsEnabled returns true if the named service is enabled. A companion web interface is only
+// enabled when the service it connects to is enabled.
+func (s *Services) IsEnabled(name string) bool {
+	if parent, ok := Companions[name]; ok {
+		return s.UI[parent] && s.IsEnabled(parent)
+	}
+
+	if v := s.field(name); v != nil {
+		return *v
+	}
+
+	return false
+}
+
+// Set enables or disables the named service. Enabling a companion web interface also
+// enables the service it connects to. It returns an error if the service is unknown.
+func (s *Services) Set(name string, enabled bool) error {
+	if parent, ok := Companions[name]; ok {
+		if !enabled {
+			delete(s.UI, parent)
+			return nil
+		}
+
+		if s.UI == nil {
+			s.UI = make(map[string]bool)
+		}
+
+		s.UI[parent] = true
+
+		return s.Set(parent, true)
+	}
+
+	v := s.field(name)
+	if v == nil {
+		return fmt.Errorf("unknown service %q", name)
+	}
+
+	*v = enabled
+
+	return nil
+}
+
+func (s *Services) field(name string) *bool {
+	switch name {
+	case "blackfire":
+		return &s.Blackfire
+	case "dynamodb":
+		return &s.DynamoDB
+	case "gotenberg":
+		return &s.Gotenberg
+	case "mailhog":
+		return &s.Mailhog
+	case "minio":
+		return &s.Minio
+	case "redis":
+		return &s.Redis
+	}
+
+	return nil
+}
+
+// Site represents a web application. It has a hostname, aliases (which
+// are alternate domains), the local path to the site, additional mounts
+// to add to the container, and the directory the index.php is located.
+type Site struct {
+	Hostname   string   `json:"hostname" yaml:"hostname"`
+	Aliases    []string `json:"aliases,omitempty" yaml:"aliases,omitempty"`
+	Path       string   `json:"path" yaml:"path"`
+	Version    string   `json:"version" yaml:"version"`
+	PHP        PHP      `json:"php,omitempty" yaml:"php,omitempty"`
+	Extensions []string `json:"extensions,omitempty" yaml:"extensions,omitempty"`
+	Webroot    string   `json:"webroot" yaml:"webroot"`
+	Xdebug     bool     `json:"xdebug" yaml:"xdebug"`
+	Blackfire  bool     `json:"blackfire" yaml:"blackfire"`
+
+	// XdebugClient is used when the IDE is not on the docker host
+	XdebugClient XdebugClient `json:"xdebug_client,omitempty" yaml:"xdebug_client,omitempty"`
+
+	// HealthCheck overrides the request used to check if the site is ready
+	HealthCheck HealthCheck `json:"health_check,omitempty" yaml:"health_check,omitempty"`
+
+	// SlowRequests is the threshold in milliseconds for requests to be shown in
+	// the slow request log of the proxy, zero disables the slow request log
+	SlowRequests int `json:"slow_requests,omitempty" yaml:"slow_requests,omitempty"`
+
+	// Nginx changes the nginx config that is generated for the site
+	Nginx Nginx `json:"nginx,omitempty" yaml:"nginx,omitempty"`
+
+	// Queue is the number of queue workers to run for the site, true runs one worker
+	Queue Workers `json:"queue,omitempty" yaml:"queue,omitempty"`
+
+	// Logs overrides the log options in the config for the site container
+	Logs Logs `json:"logs,omitempty" yaml:"logs,omitempty"`
+
+	// ResolveSymlinks mounts the real path of the site when the path is or contains a
+	// symlink, so the container and the comparisons use the same path
+	ResolveSymlinks bool `json:"resolve_symlinks,omitempty" yaml:"resolve_symlinks,omitempty"`
+
+	// Sync is set to mutagen to sync the site path into a volume instead of using a
+	// bind mount, which is faster for large projects on macOS
+	Sync string `json:"sync,omitempty" yaml:"sync,omitempty"`
+
+	// Database is the name of the database the site uses, apply offers to create it when
+	// it does not exist. DatabaseEngine is the hostname of the database container (e.g.
+	// mysql-8.0-3306.database.nitro) and defaults to the first database in the config
+	Database       string `json:"database,omitempty" yaml:"database,omitempty"`
+	DatabaseEngine string `json:"database_engine,omitempty" yaml:"database_engine,omitempty"`
+
+	// Seed populates the database of the site after it is created by apply or reset with
+	// nitro db reset (e.g. the products and orders of a Commerce store)
+	Seed Seed `json:"seed,omitempty" yaml:"seed,omitempty"`
+
+	// CPUs and Memory limit the resources of the site container and its queue workers
+	// (e.g. 1.5 and 512m), the container is not limited when they are not set
+	CPUs   float64 `json:"cpus,omitempty" yaml:"cpus,omitempty"`
+	Memory string  `json:"memory,omitempty" yaml:"memory,omitempty"`
+
+	// Route also serves the site at a path on the hostname of another site (e.g.
+	// mysite.nitro/api), so sites in separate containers can share one hostname
+	Route string `json:"route,omitempty" yaml:"route,omitempty"`
+}
+
+// GetRoute returns the hostname and the path prefix of the route of the site (e.g.
+// mysite.nitro and /api), they are empty when the site does not have a route.
+func (s *Site) GetRoute() (string, string, error) {
+	if s.Route == "" {
+		return "", "", nil
+	}
+
+	route := strings.TrimPrefix(strings.TrimPrefix(s.Route, "https://"), "http://")
+
+	sp := strings.SplitN(route, "/", 2)
+	if len(sp) != 2 || sp[0] == "" || strings.Trim(sp[1], "/") == "" {
+		return "", "", fmt.Errorf("the route %q must be a hostname and a path (e.g. mysite.nitro/api)", s.Route)
+	}
+
+	return sp[0], "/" + strings.Trim(sp[1], "/"), nil
+}
+
+// Seed is the data the database of a site is seeded with. The files are SQL dumps (which
+// can be compressed) relative to the site path that are imported into the database of the
+// site, then the commands run with sh in the site container (e.g. php craft
+// commerce/example-data).
+type Seed struct {
+	Files    []string `json:"files,omitempty" yaml:"files,omitempty"`
+	Commands []string `json:"commands,omitempty" yaml:"commands,omitempty"`
+}
+
+// IsZero returns true when the site is not seeded.
+func (s Seed) IsZero() bool {
+	return len(s.Files) == 0 && len(s.Commands) == 0
+}
+
+// GetSeedFiles returns the absolute path of each of the seed files, relative files are
+// in the site path.
+func (s *Site) GetSeedFiles(home string) ([]string, error) {
+	if len(s.Seed.Files) == 0 {
+		return nil, nil
+	}
+
+	dir, err := s.GetAbsPath(home)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, f := range s.Seed.Files {
+		f = wsl.Path(f)
+		if !filepath.IsAbs(f) && !strings.HasPrefix(f, "~") {
+			f = filepath.Join(dir, f)
+		}
+
+		p, err := cleanPath(home, f)
+		if err != nil {
+			return nil, err
+		}
+
+		files = append(files, p)
+	}
+
+	return files, nil
+}
+
+// Resources returns the resource limits of the site.
+func (s *Site) Resources() Resources {
+	return Resources{CPUs: s.CPUs, Memory: s.Memory}
+}
+
+// SyncMutagen is the sync mode that uses a mutagen session to sync the site path
+// into a volume.
+const SyncMutagen = "mutagen"
+
+// UsesSync returns true when the site path is synced into a volume instead of
+// using a bind mount.
+func (s *Site) UsesSync() bool {
+	return s.Sync == SyncMutagen
+}
+
+// SyncVolume returns the name of the volume the site path is synced into.
+func (s *Site) SyncVolume() string {
+	return "nitro-sync-" + strings.ReplaceAll(s.Hostname, ".", "_")
+}
+
+// Workers is the number of queue workers for a site. In the config file it can
+// be a number or true for a single worker.
+type Workers int
+
+// UnmarshalYAML allows the number of workers to be a boolean.
+func (w *Workers) UnmarshalYAML(value *yaml.Node) error {
+	var enabled bool
+	if err := value.Decode(&enabled); err == nil {
+		*w = 0
+		if enabled {
+			*w = 1
+		}
+
+		return nil
+	}
+
+	var n int
+	if err := value.Decode(&n); err != nil {
+		return fmt.Errorf("queue must be true, false, or the number of workers")
+	}
+
+	*w = Workers(n)
+
+	return nil
+}
+
+// UnmarshalJSON allows the number of workers to be a boolean.
+func (w *Workers) UnmarshalJSON(data []byte) error {
+	var enabled bool
+	if err := json.Unmarshal(data, &enabled); err == nil {
+		*w = 0
+		if enabled {
+			*w = 1
+		}
+
+		return nil
+	}
+
+	var n int
+	if err := json.Unmarshal(data, &n); err != nil {
+		return fmt.Errorf("queue must be true, false, or the number of workers")
+	}
+
+	*w = Workers(n)
+
+	return nil
+}
+
+// Nginx is used to change the nginx config of a site. Websockets are proxied to
+// an upstream for each path, gzip changes the compression of responses, and the
+// directives are added to the server block as is.
+type Nginx struct {
+	// HTTP2 uses HTTP/2 for requests from the proxy to the site, it is ignored
+	// for sites with websockets as the upgrade requires HTTP/1.1
+	HTTP2      bool        `json:"http2,omitempty" yaml:"http2,omitempty"`
+	Gzip       Gzip        `json:"gzip,omitempty" yaml:"gzip,omitempty"`
+	Websockets []Websocket `json:"websockets,omitempty" yaml:"websockets,omitempty"`
+	Directives []string    `json:"directives,omitempty" yaml:"directives,omitempty"`
+}
+
+// NginxHTTP2Port is the port nginx listens on for HTTP/2 requests from the proxy.
+const NginxHTTP2Port = 8081
 
-	// Volume stores the volumes we should create and maintain for the container (e.g. <name>_container_<vol>_nitro_volume)
-	Volumes []string `json:"volumes,omitempty" yaml:"volumes,omitempty"`
+// IsEmpty returns true when the nginx config of the site is not changed.
+func (n Nginx) IsEmpty() bool {
+	return !n.HTTP2 && !n.Gzip.Enabled && len(n.Websockets) == 0 && len(n.Directives) == 0
+}
 
-	WebGui  int    `json:"web_gui,omitempty" yaml:"web_gui,omitempty"`
-	EnvFile string `json:"env_file,omitempty" yaml:"env_file,omitempty"`
+// UsesHTTP2 returns true when the proxy should use HTTP/2 for requests to the site.
+func (n Nginx) UsesHTTP2() bool {
+	return n.HTTP2 && len(n.Websockets) == 0
 }
 
-// AddContainer adds a new container config to an config. It will validate there are no other
-// container names to avoid colision or duplicate ports.
-func (c *Config) AddContainer(container Container) error {
-	for _, e := range c.Containers {
-		// check the containers name
-		if e.Name == container.Name {
-			return fmt.Errorf("an existing container %q already exists", e.Name)
-		}
+// Gzip enables the compression of responses with the level (1-9) and the
+// content types to compress.
+type Gzip struct {
+	Enabled bool     `json:"enabled,omitempty" yaml:"enabled,omitempty"`
+	Level   int      `json:"level,omitempty" yaml:"level,omitempty"`
+	Types   []string `json:"types,omitempty" yaml:"types,omitempty"`
+}
 
-		// TODO(jasonmccallister) check is a port already is in use
-	}
+// Websocket proxies the websocket connections for a path to the upstream
+// (e.g. 127.0.0.1:6001 or node.containers.nitro:6001).
+type Websocket struct {
+	Path     string `json:"path" yaml:"path"`
+	Upstream string `json:"upstream" yaml:"upstream"`
+}
 
-	c.Containers = append(c.Containers, container)
+// DefaultHealthCheckPath is the Craft action that responds once the application is ready.
+const DefaultHealthCheckPath = "/index.php?p=actions/app/health-check"
+
+// HealthCheck is the HTTP request docker makes to a site container to check if the
+// site is ready. By default it requests the Craft health check action and expects
+// a 200 response. Sites that are not running Craft can change the path or disable
+// the check.
+type HealthCheck struct {
+	Path     string `json:"path,omitempty" yaml:"path,omitempty"`
+	Status   int    `json:"status,omitempty" yaml:"status,omitempty"`
+	Disabled bool   `json:"disabled,omitempty" yaml:"disabled,omitempty"`
+}
 
-	sort.SliceStable(c.Containers, func(i, j int) bool {
-		return c.Containers[i].Name < c.Containers[j].Name
-	})
+// GetPath returns the path to request, it defaults to the Craft health check action.
+func (h *HealthCheck) GetPath() string {
+	if h.Path == "" {
+		return DefaultHealthCheckPath
+	}
 
-	return nil
-}
+	if !strings.HasPrefix(h.Path, "/") {
+		return "/" + h.Path
+	}
 
-// Database is the struct used to represent a database engine
-// that is a combination of a engine (e.g. mariadb, mysql, or
-// postgres), the version number, and the port. The engine
-// and version are directly related to the official docker
-// images on the docker hub.
-type Database struct {
-	Engine  string `json:"engine" yaml:"engine"`
-	Version string `json:"version" yaml:"version"`
-	Port    string `json:"port" yaml:"port"`
+	return h.Path
 }
 
-// GetHostname returns a friendly and predictable name for a database
-// container. It is used for accessing a database by hostname. For
-// example, mysql-8.0-3306 would be the hostname to use in the .env
-// for DB_HOST.
-func (d *Database) GetHostname() (string, error) {
-	if d.Engine == "" || d.Version == "" || d.Port == "" {
-		return "", fmt.Errorf("the engine, version, and port must be defined for the database")
+// GetStatus returns the expected response status code, it defaults to 200.
+func (h *HealthCheck) GetStatus() int {
+	if h.Status == 0 {
+		return 200
 	}
 
-	return fmt.Sprintf("%s-%s-%s.database.nitro", d.Engine, d.Version, d.Port), nil
+	return h.Status
 }
 
-// Services define common tools for development that should run as containers. We don't expose the volumes, ports, and
-// networking options for these types of services. We plan to support "custom" container options to make local users
-// development even better.
-type Services struct {
-	DynamoDB bool `json:"dynamodb"`
-	Mailhog  bool `json:"mailhog"`
-	Minio    bool `json:"minio"`
-	Redis    bool `json:"redis"`
+// GetXdebugPort returns the port the IDE should listen on for debug sessions from the site.
+func (s *Site) GetXdebugPort() int {
+	return xdebugPort(s.Version, s.XdebugClient.Port)
 }
 
-// Site represents a web application. It has a hostname, aliases (which
-// are alternate domains), the local path to the site, additional mounts
-// to add to the container, and the directory the index.php is located.
-type Site struct {
-	Hostname   string   `json:"hostname" yaml:"hostname"`
-	Aliases    []string `json:"aliases,omitempty" yaml:"aliases,omitempty"`
-	Path       string   `json:"path" yaml:"path"`
-	Version    string   `json:"version" yaml:"version"`
-	PHP        PHP      `json:"php,omitempty" yaml:"php,omitempty"`
-	Extensions []string `json:"extensions,omitempty" yaml:"extensions,omitempty"`
-	Webroot    string   `json:"webroot" yaml:"webroot"`
-	Xdebug     bool     `json:"xdebug" yaml:"xdebug"`
-	Blackfire  bool     `json:"blackfire" yaml:"blackfire"`
+// XdebugClient overrides the host and port Xdebug connects to. By default
+// Xdebug connects to host.docker.internal, which only works when the IDE is
+// running on the same machine as docker.
+type XdebugClient struct {
+	Host string `json:"host,omitempty" yaml:"host,omitempty"`
+	Port int    `json:"port,omitempty" yaml:"port,omitempty"`
 }
 
 // GetAbsPath gets the directory for a site.Path,
 // It is used to create the mount for a sites
-// container.
+// container. Sites with resolve_symlinks use the
+// real path of the directory.
 func (s *Site) GetAbsPath(home string) (string, error) {
-	return cleanPath(home, s.Path)
+	p, err := cleanPath(home, s.Path)
+	if err != nil || !s.ResolveSymlinks {
+		return p, err
+	}
+
+	// paths that do not exist yet are returned as is
+	if real, err := filepath.EvalSymlinks(p); err == nil {
+		return real, nil
+	}
+
+	return p, nil
 }
 
 // GetAbsContainerPath gets the directory for a site’s
@@ -295,7 +1185,12 @@ func (s *Site) AsEnvs(addr string) []string {
 	// set the php vars
 	envs = append(envs, phpVars(s.PHP, s.Version)...)
 
-	return append(envs, xdebugVars(s.PHP, s.Xdebug, s.Version, s.Hostname, addr)...)
+	// use the client host if the IDE is on another machine
+	if s.XdebugClient.Host != "" {
+		addr = s.XdebugClient.Host
+	}
+
+	return append(envs, xdebugVars(s.PHP, s.Xdebug, s.Version, s.Hostname, addr, s.XdebugClient.Port)...)
 }
 
 // SetPHPBoolSetting is used to set php settings that are bool. It will look
@@ -469,6 +1364,16 @@ type PHP struct {
 	UploadMaxFileSize         string `json:"upload_max_file_size,omitempty" yaml:"upload_max_file_size,omitempty"`
 }
 
+// GetUploadMaxFileSize returns the upload_max_filesize setting. Older configs used
+// max_file_upload for the setting, so it is used when upload_max_file_size is not set.
+func (p PHP) GetUploadMaxFileSize() string {
+	if p.UploadMaxFileSize != "" {
+		return p.UploadMaxFileSize
+	}
+
+	return p.MaxFileUpload
+}
+
 // Load is used to return the unmarshalled config, and
 // returns an error when trying to get the users home directory or
 // while marshalling the config.
@@ -478,13 +1383,19 @@ func Load(home string) (*Config, error) {
 		return nil, err
 	}
 
+	return LoadFile(file)
+}
+
+// LoadFile returns the unmarshalled config from the file, it is used to load the config of
+// an environment that is not the default.
+func LoadFile(file string) (*Config, error) {
 	// create the config
 	c := &Config{
 		File: file,
 	}
 
 	// read the file
-	data, err := ioutil.ReadFile(file)
+	data, err := read(file)
 	if err != nil {
 		return nil, err
 	}
@@ -498,10 +1409,56 @@ func Load(home string) (*Config, error) {
 	return c, nil
 }
 
-// IsEmpty is used to check if the config file is empty
+// cache holds the contents of the config files that were read during the
+// command. Commands load the config many times, so the file is only read
+// again when it has been modified.
+var cache = struct {
+	sync.Mutex
+	files map[string]cachedFile
+}{files: make(map[string]cachedFile)}
+
+type cachedFile struct {
+	modTime time.Time
+	size    int64
+	data    []byte
+}
+
+func read(file string) ([]byte, error) {
+	stat, err := os.Stat(file)
+	if err != nil {
+		return nil, err
+	}
+
+	cache.Lock()
+	defer cache.Unlock()
+
+	if cached, ok := cache.files[file]; ok && cached.modTime.Equal(stat.ModTime()) && cached.size == stat.Size() {
+		return cached.data, nil
+	}
+
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	cache.files[file] = cachedFile{modTime: stat.ModTime(), size: stat.Size(), data: data}
+
+	return data, nil
+}
+
+// IsEmpty is used to check if the config file is empty, it returns the file set with the
+// --config flag instead of the config file in the nitro directory when it is set.
 func IsEmpty(home string) (string, error) {
 	// verify the file exists
-	file := filepath.Join(home, DirectoryName, FileName)
+	name := FileName
+	if !environment.IsDefault() {
+		name = environment.ConfigFile()
+	}
+
+	file := filepath.Join(home, DirectoryName, name)
+	if environment.File != "" {
+		file = environment.File
+	}
 	stat, err := os.Stat(file)
 	if os.IsNotExist(err) {
 		return "", ErrNoConfigFile
@@ -515,6 +1472,218 @@ func IsEmpty(home string) (string, error) {
 	return file, nil
 }
 
+// Files returns the config file of each environment in the nitro directory. The default
+// config file is first, followed by the other config files sorted by name. Empty files are
+// skipped.
+func Files(home string) ([]string, error) {
+	matches, err := filepath.Glob(filepath.Join(home, DirectoryName, "*.yaml"))
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if filepath.Base(matches[i]) == FileName {
+			return true
+		}
+
+		if filepath.Base(matches[j]) == FileName {
+			return false
+		}
+
+		return matches[i] < matches[j]
+	})
+
+	var files []string
+	for _, m := range matches {
+		if stat, err := os.Stat(m); err != nil || stat.IsDir() || stat.Size() == 0 {
+			continue
+		}
+
+		files = append(files, m)
+	}
+
+	if len(files) == 0 {
+		return nil, ErrNoConfigFile
+	}
+
+	return files, nil
+}
+
+// Ports returns the ports of the proxy for the current environment. The ports in the config
+// are used when they are set, the default environment uses the default ports, and a named
+// environment without ports uses the next ports that are not used by another environment.
+func Ports(home string) environment.PortSet {
+	if cfg, err := Load(home); err == nil && !cfg.Proxy.Ports.IsZero() {
+		return cfg.Proxy.Ports
+	}
+
+	if environment.IsDefault() {
+		return environment.DefaultPorts
+	}
+
+	return NextPorts(home)
+}
+
+// NextPorts returns the ports for a new named environment, the ports of the first slot
+// that is not used by the config of another environment are returned.
+func NextPorts(home string) environment.PortSet {
+	used := make(map[int]bool)
+
+	files, _ := Files(home)
+	for _, f := range files {
+		if filepath.Base(f) == environment.ConfigFile() {
+			continue
+		}
+
+		if cfg, err := LoadFile(f); err == nil {
+			used[cfg.Proxy.Ports.HTTP] = true
+		}
+	}
+
+	for n := 1; ; n++ {
+		if p := environment.Slot(n); !used[p.HTTP] {
+			return p
+		}
+	}
+}
+
+// HostPort is a port on the host that a container of the environment binds.
+type HostPort struct {
+	Port string
+
+	// Container is the name of the container that binds the port
+	Container string
+
+	// Setting is the config key or the environment variable the port is set with
+	Setting string
+
+	set func(port string)
+}
+
+// Set changes the port in the config, or in the environment variable for the rest of the
+// command when the port is set with an environment variable.
+func (p HostPort) Set(port string) {
+	p.set(port)
+}
+
+// HostPorts returns the host ports of the proxy, databases, enabled services, and custom
+// containers in the order they are created.
+func (c *Config) HostPorts() []HostPort {
+	var ports []HostPort
+
+	// setEnv changes the environment variable, it is only used by the current command
+	setEnv := func(envVar string) func(string) {
+		return func(port string) {
+			_ = os.Setenv(envVar, port)
+		}
+	}
+
+	proxy := []struct {
+		key    string
+		envVar string
+		port   func(p *environment.PortSet) *int
+	}{
+		{key: "http", envVar: "NITRO_HTTP_PORT", port: func(p *environment.PortSet) *int { return &p.HTTP }},
+		{key: "https", envVar: "NITRO_HTTPS_PORT", port: func(p *environment.PortSet) *int { return &p.HTTPS }},
+		{key: "api", envVar: "NITRO_API_PORT", port: func(p *environment.PortSet) *int { return &p.API }},
+		{key: "node", envVar: "NITRO_NODE_PORT", port: func(p *environment.PortSet) *int { return &p.Node }},
+		{key: "alt_node", envVar: "NITRO_ALT_NODE_PORT", port: func(p *environment.PortSet) *int { return &p.AltNode }},
+	}
+
+	for _, pp := range proxy {
+		pp := pp
+		hp := HostPort{
+			Port:      environment.Port(pp.envVar, *pp.port(&environment.Ports)),
+			Container: environment.Proxy(),
+			Setting:   "proxy.ports." + pp.key,
+		}
+
+		switch _, defined := os.LookupEnv(pp.envVar); defined {
+		case true:
+			hp.Setting = pp.envVar
+			hp.set = setEnv(pp.envVar)
+		default:
+			// the ports of the environment are saved in the config so they do not change
+			hp.set = func(port string) {
+				if c.Proxy.Ports.IsZero() {
+					c.Proxy.Ports = environment.Ports
+				}
+
+				*pp.port(&c.Proxy.Ports), _ = strconv.Atoi(port)
+
+				environment.Ports = c.Proxy.Ports
+			}
+		}
+
+		ports = append(ports, hp)
+	}
+
+	for i, db := range c.Databases {
+		i := i
+		hostname, err := db.GetHostname()
+		if err != nil {
+			continue
+		}
+
+		ports = append(ports, HostPort{
+			Port:      db.Port,
+			Container: hostname,
+			Setting:   fmt.Sprintf("databases[%d].port", i),
+			set: func(port string) {
+				c.Databases[i].Port = port
+
+				// the hostname of the database includes the port, so update the sites that use it
+				renamed, _ := c.Databases[i].GetHostname()
+				for j, s := range c.Sites {
+					if s.DatabaseEngine == hostname {
+						c.Sites[j].DatabaseEngine = renamed
+					}
+				}
+			},
+		})
+	}
+
+	// sort the services so the ports are in the same order each time
+	var services []string
+	for name := range ServicePorts {
+		services = append(services, name)
+	}
+	sort.Strings(services)
+
+	for _, name := range services {
+		if !c.Services.IsEnabled(name) {
+			continue
+		}
+
+		for _, p := range ServicePorts[name] {
+			ports = append(ports, HostPort{Port: p.Get(), Container: name + ".service.nitro", Setting: p.EnvVar, set: setEnv(p.EnvVar)})
+		}
+	}
+
+	for i, ct := range c.Containers {
+		for j, p := range ct.Ports {
+			i, j := i, j
+
+			// the ports use the <host>:<container> syntax
+			parts := strings.SplitN(p, ":", 2)
+			if len(parts) != 2 {
+				continue
+			}
+
+			ports = append(ports, HostPort{
+				Port:      parts[0],
+				Container: ct.Name + ".containers.nitro",
+				Setting:   fmt.Sprintf("containers[%d].ports[%d]", i, j),
+				set: func(port string) {
+					c.Containers[i].Ports[j] = port + ":" + parts[1]
+				},
+			})
+		}
+	}
+
+	return ports
+}
+
 // AddSite takes a site and adds it to the config
 func (c *Config) AddSite(s Site) error {
 	// check existing sites
@@ -535,6 +1704,51 @@ func (c *Config) AddSite(s Site) error {
 	return nil
 }
 
+// GetTLD returns the TLD used for new sites. The NITRO_DEFAULT_TLD environment
+// variable takes precedence over the config and the TLD defaults to nitro.
+func (c *Config) GetTLD() string {
+	if tld := os.Getenv("NITRO_DEFAULT_TLD"); tld != "" {
+		return tld
+	}
+
+	if c.Defaults.TLD != "" {
+		return strings.TrimPrefix(c.Defaults.TLD, ".")
+	}
+
+	return "nitro"
+}
+
+// AddWorkspace takes a directory and adds it to the list of workspaces.
+// If the workspace already exists, it returns an error.
+func (c *Config) AddWorkspace(dir string) error {
+	for _, w := range c.Workspaces {
+		if w == dir {
+			return fmt.Errorf("workspace %q already exists", dir)
+		}
+	}
+
+	c.Workspaces = append(c.Workspaces, dir)
+
+	sort.Strings(c.Workspaces)
+
+	return nil
+}
+
+// GetWorkspaces returns the absolute path for each of the workspaces.
+func (c *Config) GetWorkspaces(home string) ([]string, error) {
+	var dirs []string
+	for _, w := range c.Workspaces {
+		dir, err := cleanPath(home, w)
+		if err != nil {
+			return nil, err
+		}
+
+		dirs = append(dirs, dir)
+	}
+
+	return dirs, nil
+}
+
 // RemoveContainer takes a name and will remove the container by its
 // name from the config file.
 func (c *Config) RemoveContainer(container *Container) error {
@@ -548,6 +1762,62 @@ func (c *Config) RemoveContainer(container *Container) error {
 	return fmt.Errorf("unknown container %q", container.Name)
 }
 
+// ScaleContainer takes the name of a container and sets the number of replicas to run. Only
+// stateless containers can be scaled, so containers with volumes or host ports return an error.
+func (c *Config) ScaleContainer(name string, replicas int) error {
+	if replicas < 1 {
+		return fmt.Errorf("replicas must be at least 1")
+	}
+
+	for i, container := range c.Containers {
+		if container.Name != name {
+			continue
+		}
+
+		if replicas > 1 && len(container.Volumes) > 0 {
+			return fmt.Errorf("the container %q has volumes and cannot be scaled", name)
+		}
+
+		if replicas > 1 && len(container.Ports) > 0 {
+			return fmt.Errorf("the container %q exposes ports on the host and cannot be scaled", name)
+		}
+
+		// a single replica is the default
+		if replicas == 1 {
+			replicas = 0
+		}
+
+		c.Containers[i].Replicas = replicas
+
+		return nil
+	}
+
+	return fmt.Errorf("unknown container %q", name)
+}
+
+// AddDatabase takes a database and adds it to the config. Each database is
+// bound to a port on the host, so the port must not be used by another database.
+func (c *Config) AddDatabase(database Database) error {
+	hostname, err := database.GetHostname()
+	if err != nil {
+		return err
+	}
+
+	for _, e := range c.Databases {
+		if h, _ := e.GetHostname(); h == hostname {
+			return fmt.Errorf("database %q already exists", hostname)
+		}
+
+		if e.Port == database.Port {
+			return fmt.Errorf("port %s is already used by the %s %s database", e.Port, e.Engine, e.Version)
+		}
+	}
+
+	c.Databases = append(c.Databases, database)
+
+	return nil
+}
+
 // RemoveDatabase is used to destroy or remove a database
 // engine from the config.
 func (c *Config) RemoveDatabase(database Database) error {
@@ -577,6 +1847,18 @@ func (c *Config) RemoveSite(site *Site) error {
 	return fmt.Errorf("unknown site %q", site.Hostname)
 }
 
+// RemoveWorkspace takes a directory and removes it from the list of workspaces.
+func (c *Config) RemoveWorkspace(dir string) error {
+	for i, w := range c.Workspaces {
+		if w == dir {
+			c.Workspaces = append(c.Workspaces[:i], c.Workspaces[i+1:]...)
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown workspace %q", dir)
+}
+
 // DisableBlackfire takes a sites hostname and sets the blackfire option
 // to false. If the site cannot be found, it returns an error.
 func (c *Config) DisableBlackfire(site string) error {
@@ -647,6 +1929,21 @@ func (c *Config) EnableXdebug(site string) error {
 	return fmt.Errorf("unknown site, %s", site)
 }
 
+// SetXdebugClient takes a sites hostname and sets the host and port
+// Xdebug should connect to. An empty host and zero port reset the
+// site to use the defaults.
+func (c *Config) SetXdebugClient(site, host string, port int) error {
+	for i, s := range c.Sites {
+		if s.Hostname == site {
+			c.Sites[i].XdebugClient = XdebugClient{Host: host, Port: port}
+
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown site, %s", site)
+}
+
 // Save takes a file path and marshals the config into a file.
 func (c *Config) Save() error {
 	// make sure the file exists
@@ -658,6 +1955,11 @@ func (c *Config) Save() error {
 		}
 	}
 
+	// the cached contents are no longer valid
+	cache.Lock()
+	delete(cache.files, c.File)
+	cache.Unlock()
+
 	// open the file
 	f, err := os.OpenFile(c.File, os.O_TRUNC|os.O_WRONLY, os.ModeAppend)
 	if err != nil {
@@ -670,12 +1972,16 @@ func (c *Config) Save() error {
 		return err
 	}
 
-	// write the content
-	if _, err := f.Write(data); err != nil {
+	// write the content with the schema header for editors
+	if _, err := f.Write(append([]byte(schemaHeader), data...)); err != nil {
+		return err
+	}
+
+	if err := f.Close(); err != nil {
 		return err
 	}
 
-	return f.Close()
+	return writeSchema(filepath.Dir(c.File))
 }
 
 func (c *Config) createFile(dir string) error {
@@ -728,10 +2034,10 @@ func phpVars(php PHP, version string) []string {
 		envs = append(envs, fmt.Sprintf("%s=%d", "PHP_MAX_EXECUTION_TIME", php.MaxExecutionTime))
 	}
 
-	if php.UploadMaxFileSize == "" {
+	if php.GetUploadMaxFileSize() == "" {
 		envs = append(envs, "PHP_UPLOAD_MAX_FILESIZE="+DefaultEnvs["PHP_UPLOAD_MAX_FILESIZE"])
 	} else {
-		envs = append(envs, "PHP_UPLOAD_MAX_FILESIZE="+php.UploadMaxFileSize)
+		envs = append(envs, "PHP_UPLOAD_MAX_FILESIZE="+php.GetUploadMaxFileSize())
 	}
 
 	if php.MaxInputVars == 0 {
@@ -760,7 +2066,7 @@ func phpVars(php PHP, version string) []string {
 	}
 
 	if php.OpcacheValidateTimestamps {
-		envs = append(envs, "PHP_OPCACHE_VALIDATE_TIMESTAMPS="+DefaultEnvs["PHP_OPCACHE_VALIDATE_TIMESTAMPS"])
+		envs = append(envs, "PHP_OPCACHE_VALIDATE_TIMESTAMPS=1")
 	} else {
 		envs = append(envs, "PHP_OPCACHE_VALIDATE_TIMESTAMPS="+DefaultEnvs["PHP_OPCACHE_VALIDATE_TIMESTAMPS"])
 	}
@@ -768,7 +2074,7 @@ func phpVars(php PHP, version string) []string {
 	return envs
 }
 
-func xdebugVars(php PHP, xdebug bool, version, hostname, addr string) []string {
+func xdebugVars(php PHP, xdebug bool, version, hostname, addr string, port int) []string {
 	envs := []string{}
 
 	// always set the session
@@ -782,21 +2088,39 @@ func xdebugVars(php PHP, xdebug bool, version, hostname, addr string) []string {
 		return append(envs, "XDEBUG_MODE=off")
 	}
 
+	port = xdebugPort(version, port)
+
 	switch version {
 	case "8.0", "7.4", "7.3", "7.2":
-		envs = append(envs, fmt.Sprintf(`XDEBUG_CONFIG=client_host=%s client_port=9003`, addr))
+		envs = append(envs, fmt.Sprintf(`XDEBUG_CONFIG=client_host=%s client_port=%d`, addr, port))
 		envs = append(envs, "XDEBUG_MODE=develop,debug")
 	default:
 		// use legacy xdebug settings to support older versions of php
-		envs = append(envs, fmt.Sprintf(`XDEBUG_CONFIG=idekey=PHPSTORM remote_host=%s profiler_enable=1 remote_port=9000 remote_autostart=1 remote_enable=1`, addr))
+		envs = append(envs, fmt.Sprintf(`XDEBUG_CONFIG=idekey=PHPSTORM remote_host=%s profiler_enable=1 remote_port=%d remote_autostart=1 remote_enable=1`, addr, port))
 		envs = append(envs, "XDEBUG_MODE=xdebug2")
 	}
 
 	return envs
 }
 
+// xdebugPort returns the port the IDE listens on for the version of php. Xdebug 3
+// uses port 9003 and older versions of php use Xdebug 2, which uses port 9000.
+func xdebugPort(version string, port int) int {
+	if port != 0 {
+		return port
+	}
+
+	switch version {
+	case "8.0", "7.4", "7.3", "7.2":
+		return 9003
+	}
+
+	return 9000
+}
+
 func cleanPath(home, path string) (string, error) {
-	p := path
+	// a config shared by WSL and windows can have paths from the other side
+	p := wsl.Path(path)
 	if strings.Contains(p, "~") {
 		p = strings.Replace(p, "~", home, -1)
 	}
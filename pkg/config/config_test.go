@@ -1,10 +1,17 @@
 package config
 
 import (
+	"encoding/json"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
+
+	"github.com/craftcms/nitro/pkg/environment"
+
+	"gopkg.in/yaml.v3"
 )
 
 func TestSite_AsEnvs(t *testing.T) {
@@ -16,6 +23,8 @@ func TestSite_AsEnvs(t *testing.T) {
 		PHP      PHP
 		Webroot  string
 		Xdebug   bool
+
+		XdebugClient XdebugClient
 	}
 	type args struct {
 		addr string
@@ -100,6 +109,34 @@ func TestSite_AsEnvs(t *testing.T) {
 				"XDEBUG_MODE=develop,debug",
 			},
 		},
+		{
+			name: "xdebug client host and port override the address",
+			fields: fields{
+				Hostname:     "somewebsite.nitro",
+				Version:      "7.4",
+				Xdebug:       true,
+				XdebugClient: XdebugClient{Host: "192.168.1.20", Port: 9009},
+			},
+			args: args{
+				addr: "host.docker.internal",
+			},
+			want: []string{
+				"COMPOSER_HOME=/tmp",
+				"PHP_DISPLAY_ERRORS=on",
+				"PHP_MEMORY_LIMIT=512M",
+				"PHP_MAX_EXECUTION_TIME=5000",
+				"PHP_UPLOAD_MAX_FILESIZE=512M",
+				"PHP_MAX_INPUT_VARS=5000",
+				"PHP_POST_MAX_SIZE=512M",
+				"PHP_OPCACHE_ENABLE=0",
+				"PHP_OPCACHE_REVALIDATE_FREQ=0",
+				"PHP_OPCACHE_VALIDATE_TIMESTAMPS=0",
+				"XDEBUG_SESSION=PHPSTORM",
+				"PHP_IDE_CONFIG=serverName=somewebsite.nitro",
+				"XDEBUG_CONFIG=client_host=192.168.1.20 client_port=9009",
+				"XDEBUG_MODE=develop,debug",
+			},
+		},
 		{
 			name: "defaults are overridden when set on the site",
 			fields: fields{
@@ -131,6 +168,31 @@ func TestSite_AsEnvs(t *testing.T) {
 				"XDEBUG_MODE=off",
 			},
 		},
+		{
+			name: "the legacy upload setting and opcache timestamps are used",
+			fields: fields{
+				Hostname: "somewebsite.nitro",
+				PHP: PHP{
+					MaxFileUpload:             "64M",
+					OpcacheValidateTimestamps: true,
+				},
+			},
+			want: []string{
+				"COMPOSER_HOME=/tmp",
+				"PHP_DISPLAY_ERRORS=on",
+				"PHP_MEMORY_LIMIT=512M",
+				"PHP_MAX_EXECUTION_TIME=5000",
+				"PHP_UPLOAD_MAX_FILESIZE=64M",
+				"PHP_MAX_INPUT_VARS=5000",
+				"PHP_POST_MAX_SIZE=512M",
+				"PHP_OPCACHE_ENABLE=0",
+				"PHP_OPCACHE_REVALIDATE_FREQ=0",
+				"PHP_OPCACHE_VALIDATE_TIMESTAMPS=1",
+				"XDEBUG_SESSION=PHPSTORM",
+				"PHP_IDE_CONFIG=serverName=somewebsite.nitro",
+				"XDEBUG_MODE=off",
+			},
+		},
 		{
 			name: "can get the defaults that are expected",
 			fields: fields{
@@ -163,6 +225,8 @@ func TestSite_AsEnvs(t *testing.T) {
 				PHP:      tt.fields.PHP,
 				Webroot:  tt.fields.Webroot,
 				Xdebug:   tt.fields.Xdebug,
+
+				XdebugClient: tt.fields.XdebugClient,
 			}
 			if got := s.AsEnvs(tt.args.addr); !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("Site.AsEnvs() = \ngot:\n%v, \nwant:\n%v", got, tt.want)
@@ -580,6 +644,46 @@ func TestSite_GetAbsPath(t *testing.T) {
 	}
 }
 
+func TestSite_GetAbsPath_ResolveSymlinks(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nitro-site")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// the temp dir can be behind a symlink itself (e.g. /var on macOS)
+	dir, err = filepath.EvalSymlinks(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	real := filepath.Join(dir, "craft")
+	if err := os.Mkdir(real, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	link := filepath.Join(dir, "link")
+	if err := os.Symlink(real, link); err != nil {
+		t.Skip("unable to create a symlink:", err)
+	}
+
+	site := Site{Path: link}
+	if got, _ := site.GetAbsPath(dir); got != link {
+		t.Errorf("GetAbsPath() = %v, want the path as configured %v", got, link)
+	}
+
+	site.ResolveSymlinks = true
+	if got, _ := site.GetAbsPath(dir); got != real {
+		t.Errorf("GetAbsPath() = %v, want the real path %v", got, real)
+	}
+
+	// paths that do not exist are not resolved
+	site.Path = filepath.Join(dir, "missing")
+	if got, _ := site.GetAbsPath(dir); got != site.Path {
+		t.Errorf("GetAbsPath() = %v, want %v", got, site.Path)
+	}
+}
+
 func TestConfig_SetPHPStrSetting(t *testing.T) {
 	type fields struct {
 		Sites []Site
@@ -1370,8 +1474,9 @@ func TestConfig_AllSitesWithHostnames(t *testing.T) {
 
 func TestConfig_IsEmpty(t *testing.T) {
 	type args struct {
-		home string
-		file string
+		home   string
+		file   string
+		config string
 	}
 	tests := []struct {
 		name     string
@@ -1395,6 +1500,23 @@ func TestConfig_IsEmpty(t *testing.T) {
 			wantErr:  false,
 			wantFile: filepath.Join("testdata", DirectoryName, "nitro.yaml"),
 		},
+		{
+			name: "the file from the config flag is used instead of the nitro directory",
+			args: args{
+				home:   filepath.Clean("does-not-exist"),
+				config: filepath.Join("testdata", DirectoryName, "nitro.yaml"),
+			},
+			wantErr:  false,
+			wantFile: filepath.Join("testdata", DirectoryName, "nitro.yaml"),
+		},
+		{
+			name: "a missing file from the config flag returns an error",
+			args: args{
+				home:   filepath.Clean("testdata"),
+				config: filepath.Join("testdata", "missing.yaml"),
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -1402,6 +1524,9 @@ func TestConfig_IsEmpty(t *testing.T) {
 				FileName = tt.args.file
 			}
 
+			environment.File = tt.args.config
+			defer func() { environment.File = "" }()
+
 			file, err := IsEmpty(tt.args.home)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("IsEmpty() error = %v, wantErr %v", err, tt.wantErr)
@@ -1416,3 +1541,722 @@ func TestConfig_IsEmpty(t *testing.T) {
 		})
 	}
 }
+
+func TestConfig_ProtectedVolumes(t *testing.T) {
+	type fields struct {
+		Containers []Container
+		Databases  []Database
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		want   map[string]bool
+	}{
+		{
+			name: "only protected databases and container volumes are returned",
+			fields: fields{
+				Containers: []Container{
+					{Name: "elasticsearch", Volumes: []string{"/usr/share/elasticsearch/data"}, Protected: true},
+					{Name: "rabbitmq", Volumes: []string{"/var/lib/rabbitmq"}},
+				},
+				Databases: []Database{
+					{Engine: "mysql", Version: "8.0", Port: "3306", Protected: true},
+					{Engine: "postgres", Version: "13", Port: "5432"},
+				},
+			},
+			want: map[string]bool{
+				"mysql-8.0-3306.database.nitro":                     true,
+				"nitro_elasticsearch__usr_share_elasticsearch_data": true,
+			},
+		},
+		{
+			name:   "nothing is returned when nothing is protected",
+			fields: fields{Databases: []Database{{Engine: "mysql", Version: "8.0", Port: "3306"}}},
+			want:   map[string]bool{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Config{
+				Containers: tt.fields.Containers,
+				Databases:  tt.fields.Databases,
+			}
+			if got := c.ProtectedVolumes(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ProtectedVolumes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfig_AddDatabase(t *testing.T) {
+	cfg := &Config{Databases: []Database{{Engine: "mysql", Version: "8.0", Port: "3306"}}}
+
+	tests := []struct {
+		name     string
+		database Database
+		wantErr  bool
+	}{
+		{name: "databases with a new port are added", database: Database{Engine: "postgres", Version: "14", Port: "5432"}},
+		{name: "the same engine can use another version and port", database: Database{Engine: "mysql", Version: "5.7", Port: "33061"}},
+		{name: "ports used by another database return an error", database: Database{Engine: "mariadb", Version: "10.6", Port: "3306"}, wantErr: true},
+		{name: "existing databases return an error", database: Database{Engine: "postgres", Version: "14", Port: "5432"}, wantErr: true},
+		{name: "databases without a port return an error", database: Database{Engine: "postgres", Version: "13"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := cfg.AddDatabase(tt.database); (err != nil) != tt.wantErr {
+				t.Errorf("AddDatabase() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if len(cfg.Databases) != 3 {
+		t.Errorf("expected 3 databases, got %d", len(cfg.Databases))
+	}
+}
+
+func TestLoad_ReadsModifiedFiles(t *testing.T) {
+	home, err := ioutil.TempDir("", "nitro-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(home)
+
+	if err := os.MkdirAll(filepath.Join(home, DirectoryName), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	file := filepath.Join(home, DirectoryName, FileName)
+	if err := ioutil.WriteFile(file, []byte("sites:\n- hostname: one.nitro\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(home)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// saving the config replaces the cached contents
+	cfg.Sites = append(cfg.Sites, Site{Hostname: "two.nitro"})
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err = Load(home)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(cfg.Sites) != 2 {
+		t.Errorf("expected the saved config to have 2 sites, got %d", len(cfg.Sites))
+	}
+
+	// modifying the file outside of nitro replaces the cached contents
+	if err := ioutil.WriteFile(file, []byte("sites:\n- hostname: one.nitro\n- hostname: two.nitro\n- hostname: three.nitro\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err = Load(home)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(cfg.Sites) != 3 {
+		t.Errorf("expected the modified config to have 3 sites, got %d", len(cfg.Sites))
+	}
+}
+
+func TestConfig_ScaleContainer(t *testing.T) {
+	tests := []struct {
+		name         string
+		container    Container
+		replicas     int
+		wantReplicas int
+		wantErr      bool
+	}{
+		{
+			name:         "stateless containers can be scaled",
+			container:    Container{Name: "chrome", Image: "browserless/chrome"},
+			replicas:     3,
+			wantReplicas: 3,
+		},
+		{
+			name:         "scaling to one replica resets to the default",
+			container:    Container{Name: "chrome", Image: "browserless/chrome", Replicas: 3},
+			replicas:     1,
+			wantReplicas: 0,
+		},
+		{
+			name:      "containers with volumes cannot be scaled",
+			container: Container{Name: "elasticsearch", Volumes: []string{"/usr/share/elasticsearch/data"}},
+			replicas:  2,
+			wantErr:   true,
+		},
+		{
+			name:      "containers with ports cannot be scaled",
+			container: Container{Name: "chrome", Ports: []string{"3000:3000"}},
+			replicas:  2,
+			wantErr:   true,
+		},
+		{
+			name:      "replicas must be at least one",
+			container: Container{Name: "chrome"},
+			replicas:  0,
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{Containers: []Container{tt.container}}
+
+			if err := cfg.ScaleContainer(tt.container.Name, tt.replicas); (err != nil) != tt.wantErr {
+				t.Errorf("ScaleContainer() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if !tt.wantErr && cfg.Containers[0].Replicas != tt.wantReplicas {
+				t.Errorf("expected the replicas to be %d, got %d", tt.wantReplicas, cfg.Containers[0].Replicas)
+			}
+		})
+	}
+}
+
+func TestConfig_GetTLD(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+		env  string
+		want string
+	}{
+		{
+			name: "defaults to nitro",
+			want: "nitro",
+		},
+		{
+			name: "uses the tld from the config",
+			cfg:  Config{Defaults: Defaults{TLD: ".test"}},
+			want: "test",
+		},
+		{
+			name: "the environment variable takes precedence",
+			cfg:  Config{Defaults: Defaults{TLD: "test"}},
+			env:  "local",
+			want: "local",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Setenv("NITRO_DEFAULT_TLD", tt.env)
+			defer os.Unsetenv("NITRO_DEFAULT_TLD")
+
+			if got := tt.cfg.GetTLD(); got != tt.want {
+				t.Errorf("GetTLD() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestServices_Set(t *testing.T) {
+	s := Services{}
+
+	for _, name := range ServiceNames {
+		if err := s.Set(name, true); err != nil {
+			t.Fatal(err)
+		}
+
+		if !s.IsEnabled(name) {
+			t.Errorf("expected %s to be enabled", name)
+		}
+	}
+
+	if !s.DynamoDB || !s.Gotenberg || !s.Mailhog || !s.Minio || !s.Redis {
+		t.Errorf("expected all of the services to be enabled, got %+v", s)
+	}
+
+	if err := s.Set("memcached", true); err == nil {
+		t.Errorf("expected unknown services to return an error")
+	}
+
+	if s.IsEnabled("memcached") {
+		t.Errorf("expected unknown services to not be enabled")
+	}
+}
+
+func TestSite_GetXdebugPort(t *testing.T) {
+	tests := []struct {
+		name string
+		site Site
+		want int
+	}{
+		{
+			name: "xdebug 3 uses port 9003",
+			site: Site{Version: "8.0"},
+			want: 9003,
+		},
+		{
+			name: "xdebug 2 uses port 9000",
+			site: Site{Version: "7.1"},
+			want: 9000,
+		},
+		{
+			name: "the client port takes precedence",
+			site: Site{Version: "7.4", XdebugClient: XdebugClient{Port: 9010}},
+			want: 9010,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.site.GetXdebugPort(); got != tt.want {
+				t.Errorf("GetXdebugPort() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHealthCheck_Defaults(t *testing.T) {
+	tests := []struct {
+		name       string
+		check      HealthCheck
+		wantPath   string
+		wantStatus int
+	}{
+		{
+			name:       "defaults to the craft health check",
+			check:      HealthCheck{},
+			wantPath:   DefaultHealthCheckPath,
+			wantStatus: 200,
+		},
+		{
+			name:       "custom paths are prefixed with a slash",
+			check:      HealthCheck{Path: "health", Status: 204},
+			wantPath:   "/health",
+			wantStatus: 204,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.check.GetPath(); got != tt.wantPath {
+				t.Errorf("GetPath() = %v, want %v", got, tt.wantPath)
+			}
+
+			if got := tt.check.GetStatus(); got != tt.wantStatus {
+				t.Errorf("GetStatus() = %v, want %v", got, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestConfig_Hostnames(t *testing.T) {
+	cfg := &Config{
+		Sites: []Site{
+			{Hostname: "craft.nitro", Aliases: []string{"www.craft.nitro", "another.nitro"}},
+			{Hostname: "another.nitro"},
+		},
+		Containers: []Container{{Name: "search", Replicas: 2}},
+	}
+
+	want := []string{"another.nitro", "craft.nitro", "search-2.containers.nitro", "search.containers.nitro", "www.craft.nitro"}
+	if got := cfg.Hostnames(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Hostnames() = %v, want %v", got, want)
+	}
+}
+
+func TestConfig_GetHostRoutes(t *testing.T) {
+	cfg := &Config{
+		Sites: []Site{{Hostname: "craft.nitro", Aliases: []string{"www.craft.nitro"}}},
+		HostRoutes: map[string]string{
+			"api.nitro":   "host:8080",
+			"local.nitro": "localhost:3000",
+			"other.nitro": "192.168.1.10:9000",
+		},
+	}
+
+	got, err := cfg.GetHostRoutes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]HostRoute{
+		"api.nitro":   {Host: HostGateway, Port: 8080},
+		"local.nitro": {Host: HostGateway, Port: 3000},
+		"other.nitro": {Host: "192.168.1.10", Port: 9000},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetHostRoutes() = %v, want %v", got, want)
+	}
+
+	for _, target := range []string{"8080", "host:http", "host:70000"} {
+		cfg.HostRoutes = map[string]string{"api.nitro": target}
+		if _, err := cfg.GetHostRoutes(); err == nil {
+			t.Errorf("expected an error for the target %q", target)
+		}
+	}
+
+	cfg.HostRoutes = map[string]string{"www.craft.nitro": "host:8080"}
+	if _, err := cfg.GetHostRoutes(); err == nil {
+		t.Errorf("expected an error when the hostname is used by a site")
+	}
+}
+
+func TestWorkers_Unmarshal(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		want    Workers
+		wantErr bool
+	}{
+		{name: "true runs a single worker", yaml: "queue: true", want: 1},
+		{name: "false does not run workers", yaml: "queue: false", want: 0},
+		{name: "numbers are the number of workers", yaml: "queue: 3", want: 3},
+		{name: "other values return an error", yaml: "queue: many", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var site Site
+			if err := yaml.Unmarshal([]byte(tt.yaml), &site); (err != nil) != tt.wantErr {
+				t.Fatalf("yaml.Unmarshal() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if site.Queue != tt.want {
+				t.Errorf("yaml.Unmarshal() queue = %d, want %d", site.Queue, tt.want)
+			}
+
+			var fromJSON Site
+			if err := json.Unmarshal([]byte(`{"queue": `+strings.TrimPrefix(tt.yaml, "queue: ")+`}`), &fromJSON); (err != nil) != tt.wantErr {
+				t.Fatalf("json.Unmarshal() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if fromJSON.Queue != tt.want {
+				t.Errorf("json.Unmarshal() queue = %d, want %d", fromJSON.Queue, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfig_DashboardHostname(t *testing.T) {
+	cfg := &Config{Sites: []Site{{Hostname: "craft.test"}}, Defaults: Defaults{TLD: "test"}}
+	if got := cfg.DashboardHostname(); got != "" {
+		t.Errorf("DashboardHostname() = %v, want an empty hostname when the dashboard is disabled", got)
+	}
+
+	cfg.Proxy.Dashboard = true
+	if got := cfg.DashboardHostname(); got != "nitro.test" {
+		t.Errorf("DashboardHostname() = %v, want nitro.test", got)
+	}
+
+	want := []string{"craft.test", "nitro.test"}
+	if got := cfg.Hostnames(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Hostnames() = %v, want %v", got, want)
+	}
+}
+
+func TestConfig_MailHostname(t *testing.T) {
+	cfg := &Config{Sites: []Site{{Hostname: "craft.test"}}, Defaults: Defaults{TLD: "test"}}
+	if got := cfg.MailHostname(); got != "" {
+		t.Errorf("MailHostname() = %v, want an empty hostname when mailhog is disabled", got)
+	}
+
+	cfg.Services.Mailhog = true
+	if got := cfg.MailHostname(); got != "mailhog.test" {
+		t.Errorf("MailHostname() = %v, want mailhog.test", got)
+	}
+
+	cfg.Mail.Hostname = "mail.craft.test"
+	want := []string{"craft.test", "mail.craft.test"}
+	if got := cfg.Hostnames(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Hostnames() = %v, want %v", got, want)
+	}
+}
+
+func TestConfig_UIHostname(t *testing.T) {
+	cfg := &Config{Sites: []Site{{Hostname: "craft.test"}}, Defaults: Defaults{TLD: "test"}}
+	if err := cfg.Services.Set("redisinsight", true); err != nil {
+		t.Fatal(err)
+	}
+
+	// the service is enabled with the web interface
+	if !cfg.Services.Redis || !cfg.Services.IsEnabled("redisinsight") {
+		t.Errorf("expected redis and the web interface to be enabled, got %v", cfg.Services)
+	}
+
+	want := []string{"craft.test", "redisinsight.test"}
+	if got := cfg.Hostnames(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Hostnames() = %v, want %v", got, want)
+	}
+
+	// the web interface is removed with the service
+	cfg.Services.Redis = false
+	if got := cfg.UIHostname("redisinsight"); got != "" {
+		t.Errorf("UIHostname() = %v, want an empty hostname when redis is disabled", got)
+	}
+
+	cfg.Services.Redis = true
+	if err := cfg.Services.Set("redisinsight", false); err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.Services.IsEnabled("redisinsight") || !cfg.Services.Redis {
+		t.Errorf("expected only the web interface to be disabled, got %v", cfg.Services)
+	}
+}
+
+func TestConfig_SiteDatabase(t *testing.T) {
+	cfg := &Config{}
+	site := Site{Hostname: "craft.test", Database: "projectx"}
+
+	if _, err := cfg.SiteDatabase(site); err == nil {
+		t.Errorf("expected an error without database engines")
+	}
+
+	cfg.Databases = []Database{
+		{Engine: "mysql", Version: "8.0", Port: "3306"},
+		{Engine: "postgres", Version: "14", Port: "5432"},
+	}
+
+	db, err := cfg.SiteDatabase(site)
+	if err != nil || db.Engine != "mysql" {
+		t.Errorf("expected the first database engine, got %v, %v", db, err)
+	}
+
+	site.DatabaseEngine = "postgres-14-5432.database.nitro"
+	db, err = cfg.SiteDatabase(site)
+	if err != nil || db.Engine != "postgres" {
+		t.Errorf("expected the postgres engine, got %v, %v", db, err)
+	}
+
+	site.DatabaseEngine = "mariadb-10.6-3306.database.nitro"
+	if _, err := cfg.SiteDatabase(site); err == nil {
+		t.Errorf("expected an error for an unknown engine")
+	}
+}
+
+func TestResources(t *testing.T) {
+	r := Resources{CPUs: 0.5, Memory: "512m"}
+	if r.NanoCPUs() != 500000000 {
+		t.Errorf("NanoCPUs() = %v, want 500000000", r.NanoCPUs())
+	}
+
+	if r.MemoryBytes() != 512*1024*1024 {
+		t.Errorf("MemoryBytes() = %v, want %v", r.MemoryBytes(), 512*1024*1024)
+	}
+
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr bool
+	}{
+		{name: "configs without limits are valid", cfg: Config{Sites: []Site{{Hostname: "craft.test"}}}},
+		{name: "site limits are valid", cfg: Config{Sites: []Site{{Hostname: "craft.test", CPUs: 1.5, Memory: "1G"}}}},
+		{name: "service limits are valid", cfg: Config{Services: Services{Limits: map[string]Resources{"redis": {Memory: "256m"}}}}},
+		{name: "negative cpus are invalid", cfg: Config{Sites: []Site{{Hostname: "craft.test", CPUs: -1}}}, wantErr: true},
+		{name: "memory with unknown units is invalid", cfg: Config{Sites: []Site{{Hostname: "craft.test", Memory: "512mb"}}}, wantErr: true},
+		{name: "memory below the docker minimum is invalid", cfg: Config{Sites: []Site{{Hostname: "craft.test", Memory: "4m"}}}, wantErr: true},
+		{name: "limits for unknown services are invalid", cfg: Config{Services: Services{Limits: map[string]Resources{"elasticsearch": {CPUs: 1}}}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.cfg.ValidateLimits(); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateLimits() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMinio_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		minio   Minio
+		wantErr bool
+	}{
+		{name: "minio without buckets is valid", minio: Minio{}},
+		{name: "bucket names with dots and hyphens are valid", minio: Minio{Buckets: []string{"assets", "craft-uploads.local"}}},
+		{name: "uppercase bucket names are invalid", minio: Minio{Buckets: []string{"Assets"}}, wantErr: true},
+		{name: "short bucket names are invalid", minio: Minio{Buckets: []string{"ab"}}, wantErr: true},
+		{name: "bucket names with consecutive dots are invalid", minio: Minio{Buckets: []string{"craft..assets"}}, wantErr: true},
+		{name: "bucket names with spaces are invalid", minio: Minio{Buckets: []string{"my assets"}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.minio.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBackups_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		backups Backups
+		wantErr bool
+	}{
+		{name: "backups without a schedule are valid", backups: Backups{}},
+		{name: "cron expressions are valid", backups: Backups{Schedule: "0 */6 * * *", Keep: 5}},
+		{name: "schedules without five fields are invalid", backups: Backups{Schedule: "@daily"}, wantErr: true},
+		{name: "negative keep is invalid", backups: Backups{Schedule: "0 0 * * *", Keep: -1}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.backups.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLogs_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		logs    Logs
+		wantErr bool
+	}{
+		{name: "logs without options are valid", logs: Logs{}},
+		{name: "sizes with units are valid", logs: Logs{MaxSize: "10m", MaxFile: 3}},
+		{name: "the local driver is valid", logs: Logs{Driver: "local", MaxSize: "512k"}},
+		{name: "drivers without rotation are invalid", logs: Logs{Driver: "syslog"}, wantErr: true},
+		{name: "sizes with unknown units are invalid", logs: Logs{MaxSize: "10mb"}, wantErr: true},
+		{name: "negative max files are invalid", logs: Logs{MaxFile: -1}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.logs.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfig_SiteLogs(t *testing.T) {
+	cfg := &Config{Logs: Logs{MaxSize: "10m", MaxFile: 3}}
+
+	got := cfg.SiteLogs(Site{Hostname: "craft.nitro", Logs: Logs{MaxSize: "50m"}})
+	if want := (Logs{MaxSize: "50m", MaxFile: 3}); got != want {
+		t.Errorf("SiteLogs() = %v, want %v", got, want)
+	}
+}
+
+func TestBlackfire_Credentials(t *testing.T) {
+	os.Setenv("BLACKFIRE_SERVER_ID", "env-id")
+	os.Setenv("BLACKFIRE_SERVER_TOKEN", "env-token")
+	defer os.Unsetenv("BLACKFIRE_SERVER_ID")
+	defer os.Unsetenv("BLACKFIRE_SERVER_TOKEN")
+
+	got := Blackfire{ServerID: "config-id"}.Credentials()
+	if want := (Blackfire{ServerID: "config-id", ServerToken: "env-token"}); got != want {
+		t.Errorf("Credentials() = %v, want %v", got, want)
+	}
+}
+
+func TestPorts(t *testing.T) {
+	defer func() { environment.Name = environment.Default }()
+
+	home, err := ioutil.TempDir("", "nitro-ports")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(home)
+
+	dir := filepath.Join(home, DirectoryName)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if p := Ports(home); p != environment.DefaultPorts {
+		t.Errorf("expected the default ports for the default environment, got %v", p)
+	}
+
+	// the first slot is used by another environment
+	if err := ioutil.WriteFile(filepath.Join(dir, "client-a.yaml"), []byte("proxy:\n  ports:\n    http: 8001\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	environment.Name = "client-b"
+
+	if p := Ports(home); p != environment.Slot(2) {
+		t.Errorf("expected the ports of the next slot, got %v", p)
+	}
+
+	// the ports in the config are used
+	if err := ioutil.WriteFile(filepath.Join(dir, "client-b.yaml"), []byte("proxy:\n  ports:\n    http: 9080\n    https: 9443\n    api: 9500\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if p := Ports(home); p.HTTP != 9080 || p.HTTPS != 9443 || p.API != 9500 {
+		t.Errorf("expected the ports from the config, got %v", p)
+	}
+}
+
+func TestSite_GetSeedFiles(t *testing.T) {
+	site := Site{Path: "/home/nitro/dev/store", Seed: Seed{Files: []string{"seed/store.sql", "/tmp/orders.sql.gz", "~/seeds/customers.sql"}}}
+
+	got, err := site.GetSeedFiles("/home/nitro")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"/home/nitro/dev/store/seed/store.sql", "/tmp/orders.sql.gz", "/home/nitro/seeds/customers.sql"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetSeedFiles() = %v, want %v", got, want)
+	}
+
+	if !(Seed{}).IsZero() || site.Seed.IsZero() {
+		t.Errorf("expected only the empty seed to be zero")
+	}
+}
+
+func TestSite_GetRoute(t *testing.T) {
+	tests := []struct {
+		route    string
+		wantHost string
+		wantPath string
+		wantErr  bool
+	}{
+		{route: ""},
+		{route: "mysite.nitro/api", wantHost: "mysite.nitro", wantPath: "/api"},
+		{route: "https://mysite.nitro/api/v1/", wantHost: "mysite.nitro", wantPath: "/api/v1"},
+		{route: "mysite.nitro", wantErr: true},
+		{route: "mysite.nitro/", wantErr: true},
+		{route: "/api", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.route, func(t *testing.T) {
+			s := Site{Route: tt.route}
+
+			host, path, err := s.GetRoute()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetRoute() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if host != tt.wantHost || path != tt.wantPath {
+				t.Errorf("GetRoute() = %q %q, want %q %q", host, path, tt.wantHost, tt.wantPath)
+			}
+		})
+	}
+}
+
+func TestConfig_HostPorts(t *testing.T) {
+	defer func() { environment.Ports = environment.DefaultPorts }()
+
+	environment.Ports = environment.Slot(1)
+
+	c := &Config{Services: Services{Redis: true}}
+
+	ports := c.HostPorts()
+
+	var got []string
+	for _, p := range ports {
+		got = append(got, p.Port+" "+p.Container)
+	}
+
+	want := []string{"8001 " + environment.Proxy(), "8401 " + environment.Proxy(), "5001 " + environment.Proxy(), "3002 " + environment.Proxy(), "3003 " + environment.Proxy(), "6379 redis.service.nitro"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("HostPorts() = %v, want %v", got, want)
+	}
+
+	// the proxy ports are saved in the config and used by the environment
+	ports[1].Set("8402")
+
+	if c.Proxy.Ports.HTTPS != 8402 || c.Proxy.Ports.HTTP != 8001 || environment.Ports.HTTPS != 8402 {
+		t.Errorf("expected the https port to change, got %v and %v", c.Proxy.Ports, environment.Ports)
+	}
+}
@@ -0,0 +1,63 @@
+package config
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFiles(t *testing.T) {
+	home, err := ioutil.TempDir("", "files")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(home)
+
+	dir := filepath.Join(home, DirectoryName)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Files(home); !errors.Is(err, ErrNoConfigFile) {
+		t.Errorf("expected ErrNoConfigFile without config files, got %v", err)
+	}
+
+	files := map[string]string{
+		"agency.yaml": "name: agency\n",
+		FileName:      "name: nitro-dev\n",
+		"client.yaml": "sites: []\n",
+		"empty.yaml":  "",
+		"jobs.json":   "[]",
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := Files(home)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		filepath.Join(dir, FileName),
+		filepath.Join(dir, "agency.yaml"),
+		filepath.Join(dir, "client.yaml"),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Files() = %v, want %v", got, want)
+	}
+
+	cfg, err := LoadFile(want[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.Name != "agency" || cfg.File != want[1] {
+		t.Errorf("LoadFile() = %q from %q, want agency from %q", cfg.Name, cfg.File, want[1])
+	}
+}
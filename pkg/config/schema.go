@@ -0,0 +1,125 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/craftcms/nitro/pkg/phpversions"
+)
+
+// SchemaFileName is the name of the JSON schema file written next to the config file.
+var SchemaFileName = "nitro.schema.json"
+
+// schemaHeader is added to the top of the config file so editors using the yaml
+// language server (e.g. VS Code) provide completion and validation.
+var schemaHeader = "# yaml-language-server: $schema=./" + SchemaFileName + "\n"
+
+// enums are the allowed values for fields in the config, keyed by the path of the field.
+var enums = map[string][]string{
+	"databases.engine": {"mariadb", "mysql", "postgres"},
+	"defaults.php":     phpversions.Versions,
+	"sites.sync":       {SyncMutagen},
+	"sites.version":    phpversions.Versions,
+}
+
+// Schema returns the JSON schema for the config file. The schema is generated
+// from the Config struct so it stays in sync with the fields nitro reads.
+func Schema() ([]byte, error) {
+	s := schemaFor(reflect.TypeOf(Config{}), "")
+	s["$schema"] = "http://json-schema.org/draft-07/schema#"
+	s["title"] = "Nitro"
+	s["description"] = "The configuration file for Nitro environments."
+
+	return json.MarshalIndent(s, "", "  ")
+}
+
+func schemaFor(t reflect.Type, path string) map[string]interface{} {
+	// the number of workers can also be a boolean
+	if t == reflect.TypeOf(Workers(0)) {
+		return map[string]interface{}{"oneOf": []interface{}{map[string]interface{}{"type": "boolean"}, map[string]interface{}{"type": "integer", "minimum": 0}}}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return schemaFor(t.Elem(), path)
+	case reflect.String:
+		s := map[string]interface{}{"type": "string"}
+		if e, ok := enums[path]; ok {
+			s["enum"] = e
+		}
+
+		return s
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Slice:
+		return map[string]interface{}{"type": "array", "items": schemaFor(t.Elem(), path)}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": schemaFor(t.Elem(), path)}
+	case reflect.Struct:
+		properties := make(map[string]interface{})
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if f.PkgPath != "" {
+				continue
+			}
+
+			name := fieldName(f)
+			if name == "" {
+				continue
+			}
+
+			p := name
+			if path != "" {
+				p = path + "." + name
+			}
+
+			properties[name] = schemaFor(f.Type, p)
+		}
+
+		return map[string]interface{}{
+			"type":                 "object",
+			"properties":           properties,
+			"additionalProperties": false,
+		}
+	}
+
+	return map[string]interface{}{}
+}
+
+// fieldName returns the name of the field in the yaml file, or
+// an empty string if the field is not written to the file.
+func fieldName(f reflect.StructField) string {
+	tag := strings.Split(f.Tag.Get("yaml"), ",")[0]
+	switch tag {
+	case "-":
+		return ""
+	case "":
+		return strings.ToLower(f.Name)
+	}
+
+	return tag
+}
+
+// writeSchema writes the schema to the directory of the config file
+// if the schema is missing or has changed.
+func writeSchema(dir string) error {
+	b, err := Schema()
+	if err != nil {
+		return err
+	}
+
+	file := filepath.Join(dir, SchemaFileName)
+	if existing, err := ioutil.ReadFile(file); err == nil && bytes.Equal(existing, b) {
+		return nil
+	}
+
+	return ioutil.WriteFile(file, b, 0644)
+}
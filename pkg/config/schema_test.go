@@ -0,0 +1,84 @@
+package config
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSchema(t *testing.T) {
+	b, err := Schema()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var schema struct {
+		Properties map[string]struct {
+			Type       string `json:"type"`
+			Properties map[string]struct {
+				Type string `json:"type"`
+			} `json:"properties"`
+			Items struct {
+				Properties map[string]struct {
+					Type string   `json:"type"`
+					Enum []string `json:"enum"`
+				} `json:"properties"`
+			} `json:"items"`
+		} `json:"properties"`
+	}
+	if err := json.Unmarshal(b, &schema); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := schema.Properties["file"]; ok {
+		t.Errorf("expected the file field to not be in the schema")
+	}
+
+	if got := schema.Properties["sites"].Type; got != "array" {
+		t.Errorf("expected sites to be an array, got %q", got)
+	}
+
+	if got := schema.Properties["sites"].Items.Properties["xdebug"].Type; got != "boolean" {
+		t.Errorf("expected sites.xdebug to be a boolean, got %q", got)
+	}
+
+	// services do not use yaml tags, so the lowercase field name is used
+	if got := schema.Properties["services"].Properties["dynamodb"].Type; got != "boolean" {
+		t.Errorf("expected services.dynamodb to be a boolean, got %q", got)
+	}
+
+	want := []string{"mariadb", "mysql", "postgres"}
+	if got := schema.Properties["databases"].Items.Properties["engine"].Enum; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected the database engines to be %v, got %v", want, got)
+	}
+}
+
+func TestSave_WritesSchema(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nitro-schema")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	cfg := &Config{File: filepath.Join(dir, FileName), Sites: []Site{{Hostname: "tutorial.nitro"}}}
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := ioutil.ReadFile(cfg.File)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.HasPrefix(string(content), "# yaml-language-server: $schema=./nitro.schema.json\n") {
+		t.Errorf("expected the config to start with the schema header, got %q", string(content))
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, SchemaFileName)); err != nil {
+		t.Errorf("expected the schema file to be written, %v", err)
+	}
+}
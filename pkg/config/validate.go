@@ -0,0 +1,431 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/craftcms/nitro/pkg/environment"
+	"github.com/craftcms/nitro/pkg/phpversions"
+	"github.com/craftcms/nitro/pkg/validate"
+)
+
+// ServicePorts are the host ports of the services, keyed by the name of the service, and
+// the environment variables that change them.
+var ServicePorts = map[string][]ServicePort{
+	"dynamodb":  {{EnvVar: "NITRO_DYNAMODB_PORT", Port: "8000"}},
+	"gotenberg": {{EnvVar: "NITRO_GOTENBERG_PORT", Port: "3030"}},
+	"mailhog":   {{EnvVar: "NITRO_MAILHOG_SMTP_PORT", Port: "1025"}, {EnvVar: "NITRO_MAILHOG_HTTP_PORT", Port: "8025"}},
+	"minio":     {{EnvVar: "NITRO_MINIO_PORT", Port: "9000"}},
+	"redis":     {{EnvVar: "NITRO_REDIS_PORT", Port: "6379"}},
+}
+
+// postgresName matches the role and extension names of postgres databases (e.g. uuid-ossp),
+// postgres limits the names to 63 characters.
+var postgresName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]{0,62}$`)
+
+// ServicePort is a host port a service binds.
+type ServicePort struct {
+	EnvVar string
+	Port   string
+}
+
+// Get returns the port from the environment variable when it is set.
+func (p ServicePort) Get() string {
+	if v := os.Getenv(p.EnvVar); v != "" {
+		return v
+	}
+
+	return p.Port
+}
+
+// Problem is an issue with the config, the line is the line of the config file the
+// problem is on or zero when it is not known.
+type Problem struct {
+	Line    int
+	Message string
+}
+
+// ValidationError is returned by Validate with all of the problems in the config, so they
+// can be fixed at once.
+type ValidationError struct {
+	File     string
+	Problems []Problem
+}
+
+func (e *ValidationError) Error() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "the config file %s is not valid:", e.File)
+	for _, p := range e.Problems {
+		b.WriteString("\n  ")
+		if p.Line > 0 {
+			fmt.Fprintf(&b, "line %d: ", p.Line)
+		}
+		b.WriteString(p.Message)
+	}
+
+	return b.String()
+}
+
+// Validate checks the config before any changes are made to the containers. It checks for
+// unknown keys, hostnames and aliases used more than once, PHP versions nitro does not
+// support, site paths that do not exist, host ports used more than once, and postgres role
+// and extension names that can not be created, along with
+// the options that are validated on their own (e.g. the backups and logs). The site paths
+// are not checked when home is empty, such as when docker runs on another machine. It
+// returns a ValidationError with every problem.
+func (c *Config) Validate(home string) error {
+	v := &validator{}
+
+	// the lines are only known when the config was loaded from a file
+	if c.File != "" {
+		if data, err := read(c.File); err == nil {
+			var doc yaml.Node
+			if err := yaml.Unmarshal(data, &doc); err == nil && len(doc.Content) > 0 {
+				v.root = doc.Content[0]
+				v.unknownKeys(v.root, reflect.TypeOf(Config{}), "")
+			}
+		}
+	}
+
+	v.hostnames(c)
+	v.routes(c)
+	v.php(c)
+	v.ports(c)
+	v.postgres(c)
+
+	if home != "" {
+		for i, s := range c.Sites {
+			p, err := s.GetAbsPath(home)
+			if err != nil {
+				v.add(v.line("sites", i, "path"), "%s: unable to find the path %s, %s", s.Hostname, s.Path, err)
+				continue
+			}
+
+			if _, err := os.Stat(p); os.IsNotExist(err) {
+				v.add(v.line("sites", i, "path"), "%s: the path %s does not exist", s.Hostname, p)
+			}
+		}
+	}
+
+	if _, err := c.GetHostRoutes(); err != nil {
+		v.add(v.line("host_routes"), "%s", err)
+	}
+
+	if err := c.Backups.Validate(); err != nil {
+		v.add(v.line("backups"), "%s", err)
+	}
+
+	if err := c.Minio.Validate(); err != nil {
+		v.add(v.line("minio"), "%s", err)
+	}
+
+	if err := c.Logs.Validate(); err != nil {
+		v.add(v.line("logs"), "%s", err)
+	}
+
+	if err := c.ValidateLimits(); err != nil {
+		v.add(0, "%s", err)
+	}
+
+	for i, s := range c.Sites {
+		if err := c.SiteLogs(s).Validate(); err != nil {
+			v.add(v.line("sites", i, "logs"), "%s: %s", s.Hostname, err)
+		}
+
+		// make sure the database of the site can be created
+		if s.Database != "" {
+			if err := (&validate.DatabaseName{}).Validate(s.Database); err != nil {
+				v.add(v.line("sites", i, "database"), "%s: %s", s.Hostname, err)
+			} else if _, err := c.SiteDatabase(s); err != nil {
+				v.add(v.line("sites", i, "database"), "%s", err)
+			}
+		}
+	}
+
+	if len(v.problems) == 0 {
+		return nil
+	}
+
+	sort.SliceStable(v.problems, func(i, j int) bool {
+		return v.problems[i].Line < v.problems[j].Line
+	})
+
+	return &ValidationError{File: c.File, Problems: v.problems}
+}
+
+type validator struct {
+	root     *yaml.Node
+	problems []Problem
+}
+
+func (v *validator) add(line int, format string, a ...interface{}) {
+	v.problems = append(v.problems, Problem{Line: line, Message: fmt.Sprintf(format, a...)})
+}
+
+// line returns the line of the value at the path in the config file, the path is made of
+// keys and indexes (e.g. "sites", 0, "path"). It returns the line of the closest parent
+// when the value is not in the file.
+func (v *validator) line(path ...interface{}) int {
+	if v.root == nil {
+		return 0
+	}
+
+	node, line := v.root, 0
+	for _, p := range path {
+		var next *yaml.Node
+		switch p := p.(type) {
+		case string:
+			if node.Kind == yaml.MappingNode {
+				for i := 0; i+1 < len(node.Content); i += 2 {
+					if node.Content[i].Value == p {
+						line, next = node.Content[i].Line, node.Content[i+1]
+						break
+					}
+				}
+			}
+		case int:
+			if node.Kind == yaml.SequenceNode && p < len(node.Content) {
+				next = node.Content[p]
+				line = next.Line
+			}
+		}
+
+		if next == nil {
+			break
+		}
+
+		node = next
+	}
+
+	return line
+}
+
+// unknownKeys adds a problem for each key in the node that is not a field of the type.
+func (v *validator) unknownKeys(node *yaml.Node, t reflect.Type, path string) {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch {
+	case t.Kind() == reflect.Struct && node.Kind == yaml.MappingNode:
+		fields := map[string]reflect.Type{}
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if name := fieldName(f); f.PkgPath == "" && name != "" {
+				fields[name] = f.Type
+			}
+		}
+
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i]
+
+			ft, ok := fields[key.Value]
+			if !ok {
+				if path == "" {
+					v.add(key.Line, "unknown key %q", key.Value)
+				} else {
+					v.add(key.Line, "unknown key %q in %s", key.Value, path)
+				}
+
+				continue
+			}
+
+			p := key.Value
+			if path != "" {
+				p = path + "." + key.Value
+			}
+
+			v.unknownKeys(node.Content[i+1], ft, p)
+		}
+	case t.Kind() == reflect.Slice && node.Kind == yaml.SequenceNode:
+		for i, item := range node.Content {
+			v.unknownKeys(item, t.Elem(), fmt.Sprintf("%s[%d]", path, i))
+		}
+	case t.Kind() == reflect.Map && node.Kind == yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			v.unknownKeys(node.Content[i+1], t.Elem(), path+"."+node.Content[i].Value)
+		}
+	}
+}
+
+// hostnames adds a problem for each hostname or alias that is used by more than one site.
+func (v *validator) hostnames(c *Config) {
+	used := map[string]string{}
+	for i, s := range c.Sites {
+		names := append([]string{s.Hostname}, s.Aliases...)
+		for j, n := range names {
+			if n == "" {
+				continue
+			}
+
+			line := v.line("sites", i, "hostname")
+			if j > 0 {
+				line = v.line("sites", i, "aliases", j-1)
+			}
+
+			if other, ok := used[n]; ok {
+				v.add(line, "the hostname %s of %s is already used by %s", n, s.Hostname, other)
+				continue
+			}
+
+			used[n] = s.Hostname
+		}
+	}
+}
+
+// routes adds a problem for each route that is not valid, is on a hostname that is not used
+// by another site, or is used by more than one site.
+func (v *validator) routes(c *Config) {
+	hosts := map[string]string{}
+	for _, s := range c.Sites {
+		for _, n := range append([]string{s.Hostname}, s.Aliases...) {
+			hosts[n] = s.Hostname
+		}
+	}
+
+	used := map[string]string{}
+	for i, s := range c.Sites {
+		host, path, err := s.GetRoute()
+		if err != nil {
+			v.add(v.line("sites", i, "route"), "%s: %s", s.Hostname, err)
+			continue
+		}
+
+		if host == "" {
+			continue
+		}
+
+		if owner, ok := hosts[host]; !ok || owner == s.Hostname {
+			v.add(v.line("sites", i, "route"), "%s: the route must be on the hostname of another site, %s is not", s.Hostname, host)
+			continue
+		}
+
+		if other, ok := used[host+path]; ok {
+			v.add(v.line("sites", i, "route"), "%s: the route %s is already used by %s", s.Hostname, host+path, other)
+			continue
+		}
+
+		used[host+path] = s.Hostname
+	}
+}
+
+// php adds a problem for each PHP version nitro does not support.
+func (v *validator) php(c *Config) {
+	supported := func(version string) bool {
+		for _, p := range phpversions.Versions {
+			if p == version {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	versions := strings.Join(phpversions.Versions, ", ")
+
+	if c.Defaults.PHP != "" && !supported(c.Defaults.PHP) {
+		v.add(v.line("defaults", "php"), "the default PHP version %s is not supported, use one of %s", c.Defaults.PHP, versions)
+	}
+
+	for i, s := range c.Sites {
+		if s.Version != "" && !supported(s.Version) {
+			v.add(v.line("sites", i, "version"), "%s: the PHP version %s is not supported, use one of %s", s.Hostname, s.Version, versions)
+		}
+	}
+}
+
+// ports adds a problem for each host port that is bound by more than one container.
+func (v *validator) ports(c *Config) {
+	used := map[string]string{}
+	bind := func(port, name string, line int) {
+		if port == "" {
+			return
+		}
+
+		if other, ok := used[port]; ok {
+			v.add(line, "the port %s of %s is already used by %s", port, name, other)
+			return
+		}
+
+		used[port] = name
+	}
+
+	proxy := environment.Proxy()
+	bind(environment.Port("NITRO_HTTP_PORT", environment.Ports.HTTP), proxy, v.line("proxy", "ports", "http"))
+	bind(environment.Port("NITRO_HTTPS_PORT", environment.Ports.HTTPS), proxy, v.line("proxy", "ports", "https"))
+	bind(environment.Port("NITRO_API_PORT", environment.Ports.API), proxy, v.line("proxy", "ports", "api"))
+	bind(environment.Port("NITRO_NODE_PORT", environment.Ports.Node), proxy, v.line("proxy", "ports", "node"))
+	bind(environment.Port("NITRO_ALT_NODE_PORT", environment.Ports.AltNode), proxy, v.line("proxy", "ports", "alt_node"))
+
+	for i, db := range c.Databases {
+		hostname, _ := db.GetHostname()
+		bind(db.Port, hostname, v.line("databases", i, "port"))
+	}
+
+	// sort the services so the problems are the same each time
+	var services []string
+	for name := range ServicePorts {
+		services = append(services, name)
+	}
+	sort.Strings(services)
+
+	for _, name := range services {
+		if !c.Services.IsEnabled(name) {
+			continue
+		}
+
+		for _, p := range ServicePorts[name] {
+			bind(p.Get(), name, v.line("services", name))
+		}
+	}
+
+	for i, ct := range c.Containers {
+		for j, p := range ct.Ports {
+			// the ports use the <host>:<container> syntax
+			parts := strings.Split(p, ":")
+			if len(parts) < 2 {
+				v.add(v.line("containers", i, "ports", j), "%s: the port %q must use the <host>:<container> syntax", ct.Name, p)
+				continue
+			}
+
+			if _, err := strconv.Atoi(parts[0]); err != nil {
+				v.add(v.line("containers", i, "ports", j), "%s: the host port %q must be a number", ct.Name, parts[0])
+				continue
+			}
+
+			bind(parts[0], ct.Name+".containers.nitro", v.line("containers", i, "ports", j))
+		}
+	}
+}
+
+// postgres adds a problem for each role or extension of a postgres database with a name that
+// can not be created.
+func (v *validator) postgres(c *Config) {
+	for i, db := range c.Databases {
+		if db.Engine != "postgres" {
+			continue
+		}
+
+		hostname, _ := db.GetHostname()
+
+		for j, r := range db.Roles {
+			if !postgresName.MatchString(r) {
+				v.add(v.line("databases", i, "roles", j), "%s: the role %q must start with a letter or underscore and only contain letters, numbers, underscores, and hyphens", hostname, r)
+			}
+		}
+
+		for j, e := range db.Extensions {
+			if !postgresName.MatchString(e) {
+				v.add(v.line("databases", i, "extensions", j), "%s: the extension %q must start with a letter or underscore and only contain letters, numbers, underscores, and hyphens", hostname, e)
+			}
+		}
+	}
+}
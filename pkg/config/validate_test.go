@@ -0,0 +1,128 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	home, err := ioutil.TempDir("", "nitro-validate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(home)
+
+	if err := os.MkdirAll(filepath.Join(home, "dev", "craft"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	file := filepath.Join(home, "nitro.yaml")
+	data := `databases:
+  - engine: mysql
+    version: "8.0"
+    port: "3306"
+  - engine: mariadb
+    version: "10.6"
+    port: "3306"
+sites:
+  - hostname: craft.nitro
+    path: ~/dev/craft
+    version: "8.0"
+    webroot: web
+  - hostname: store.nitro
+    aliases:
+      - craft.nitro
+    path: ~/dev/store
+    version: "5.6"
+    webroot: web
+    xdbug: true
+`
+	if err := ioutil.WriteFile(file, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = cfg.Validate(home)
+
+	verr, ok := err.(*ValidationError)
+	if !ok {
+		t.Fatalf("expected a validation error, got %v", err)
+	}
+
+	want := []Problem{
+		{Line: 7, Message: "the port 3306 of mariadb-10.6-3306.database.nitro is already used by mysql-8.0-3306.database.nitro"},
+		{Line: 15, Message: "the hostname craft.nitro of store.nitro is already used by craft.nitro"},
+		{Line: 16, Message: "store.nitro: the path " + filepath.Join(home, "dev", "store") + " does not exist"},
+		{Line: 17, Message: "store.nitro: the PHP version 5.6 is not supported, use one of 8.0, 7.4, 7.3, 7.2, 7.1, 7.0"},
+		{Line: 19, Message: `unknown key "xdbug" in sites[1]`},
+	}
+	if !reflect.DeepEqual(verr.Problems, want) {
+		t.Errorf("Validate() problems = %v, want %v", verr.Problems, want)
+	}
+
+	// the paths are not checked without the home directory
+	cfg.Sites = cfg.Sites[:1]
+	cfg.Databases = cfg.Databases[:1]
+	if err := ioutil.WriteFile(file, []byte("sites:\n  - hostname: craft.nitro\n    path: ~/dev/missing\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg.Sites[0].Path = "~/dev/missing"
+	if err := cfg.Validate(""); err != nil {
+		t.Errorf("expected the config to be valid without checking the paths, got %v", err)
+	}
+}
+
+func TestConfig_Validate_Routes(t *testing.T) {
+	cfg := &Config{Sites: []Site{
+		{Hostname: "mysite.nitro", Aliases: []string{"www.mysite.nitro"}},
+		{Hostname: "api.nitro", Route: "mysite.nitro/api"},
+		{Hostname: "admin.nitro", Route: "www.mysite.nitro/admin"},
+		{Hostname: "api-v2.nitro", Route: "mysite.nitro/api/"},
+		{Hostname: "self.nitro", Route: "self.nitro/app"},
+		{Hostname: "missing.nitro", Route: "unknown.nitro/app"},
+		{Hostname: "invalid.nitro", Route: "mysite.nitro"},
+	}}
+
+	verr, ok := cfg.Validate("").(*ValidationError)
+	if !ok {
+		t.Fatalf("expected a validation error")
+	}
+
+	want := []Problem{
+		{Message: "api-v2.nitro: the route mysite.nitro/api is already used by api.nitro"},
+		{Message: "self.nitro: the route must be on the hostname of another site, self.nitro is not"},
+		{Message: "missing.nitro: the route must be on the hostname of another site, unknown.nitro is not"},
+		{Message: `invalid.nitro: the route "mysite.nitro" must be a hostname and a path (e.g. mysite.nitro/api)`},
+	}
+	if !reflect.DeepEqual(verr.Problems, want) {
+		t.Errorf("Validate() problems = %v, want %v", verr.Problems, want)
+	}
+}
+
+func TestConfig_Validate_Postgres(t *testing.T) {
+	cfg := &Config{Databases: []Database{
+		{Engine: "postgres", Version: "13", Port: "5432", Roles: []string{"craft", "read-only", `bad"role`}, Extensions: []string{"uuid-ossp", "drop table;"}},
+		{Engine: "mysql", Version: "8.0", Port: "3306", Roles: []string{`ignored"role`}},
+	}}
+
+	verr, ok := cfg.Validate("").(*ValidationError)
+	if !ok {
+		t.Fatalf("expected a validation error")
+	}
+
+	want := []Problem{
+		{Message: `postgres-13-5432.database.nitro: the role "bad\"role" must start with a letter or underscore and only contain letters, numbers, underscores, and hyphens`},
+		{Message: `postgres-13-5432.database.nitro: the extension "drop table;" must start with a letter or underscore and only contain letters, numbers, underscores, and hyphens`},
+	}
+	if !reflect.DeepEqual(verr.Problems, want) {
+		t.Errorf("Validate() problems = %v, want %v", verr.Problems, want)
+	}
+}
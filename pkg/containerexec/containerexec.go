@@ -0,0 +1,135 @@
+package containerexec
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/moby/term"
+)
+
+// Interactive runs the commands in the container as the user and attaches the input and output
+// to the exec. If the input is a terminal, it is put into raw mode until the commands exit so keys
+// like ctrl+c are sent to the container. An empty user runs the commands as the containers default
+// user. It returns the exit code of the commands.
+func Interactive(ctx context.Context, docker client.ContainerAPIClient, containerID, user string, cmds []string, in io.Reader, out, errOut io.Writer) (int, error) {
+	fd, isTerminal := term.GetFdInfo(in)
+
+	exec, err := docker.ContainerExecCreate(ctx, containerID, types.ExecConfig{
+		User:         user,
+		AttachStdin:  true,
+		AttachStdout: true,
+		AttachStderr: true,
+		Tty:          isTerminal,
+		Env:          []string{"TERM=xterm"},
+		Cmd:          cmds,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	resp, err := docker.ContainerExecAttach(ctx, exec.ID, types.ExecStartCheck{Tty: isTerminal})
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Close()
+
+	if isTerminal {
+		state, err := term.SetRawTerminal(fd)
+		if err != nil {
+			return 0, err
+		}
+		defer term.RestoreTerminal(fd, state)
+
+		// match the size of the terminal
+		if size, err := term.GetWinsize(fd); err == nil {
+			_ = docker.ContainerExecResize(ctx, exec.ID, types.ResizeOptions{Height: uint(size.Height), Width: uint(size.Width)})
+		}
+	}
+
+	// send the input to the container
+	go func() {
+		_, _ = io.Copy(resp.Conn, in)
+		_ = resp.CloseWrite()
+	}()
+
+	// show the output until the commands exit, the output is only
+	// multiplexed when the exec does not use a terminal
+	if isTerminal {
+		_, err = io.Copy(out, resp.Reader)
+	} else {
+		_, err = stdcopy.StdCopy(out, errOut, resp.Reader)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	// wait for the exec to complete and get the exit code
+	for {
+		info, err := docker.ContainerExecInspect(ctx, exec.ID)
+		if err != nil {
+			return 0, err
+		}
+
+		if !info.Running {
+			return info.ExitCode, nil
+		}
+	}
+}
+
+// Run runs the commands in the container without a terminal and waits for them to exit. It
+// returns the combined output of the commands and an error if the commands exit with a
+// non-zero exit code.
+func Run(ctx context.Context, docker client.ContainerAPIClient, containerID string, cmds []string) (string, error) {
+	return RunAs(ctx, docker, containerID, "", cmds)
+}
+
+// RunAs runs the commands in the container as the user, an empty user runs the commands
+// as the containers default user. See Run.
+func RunAs(ctx context.Context, docker client.ContainerAPIClient, containerID, user string, cmds []string) (string, error) {
+	exec, err := docker.ContainerExecCreate(ctx, containerID, types.ExecConfig{
+		User:         user,
+		AttachStdout: true,
+		AttachStderr: true,
+		Cmd:          cmds,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := docker.ContainerExecAttach(ctx, exec.ID, types.ExecStartCheck{})
+	if err != nil {
+		return "", err
+	}
+	defer resp.Close()
+
+	buf := new(bytes.Buffer)
+	if _, err := stdcopy.StdCopy(buf, buf, resp.Reader); err != nil {
+		return "", err
+	}
+
+	output := strings.TrimSpace(buf.String())
+
+	// wait for the exec to complete and get the exit code
+	for {
+		info, err := docker.ContainerExecInspect(ctx, exec.ID)
+		if err != nil {
+			return "", err
+		}
+
+		if info.Running {
+			continue
+		}
+
+		if info.ExitCode != 0 {
+			return output, fmt.Errorf("the command exited with code %d: %s", info.ExitCode, output)
+		}
+
+		return output, nil
+	}
+}
@@ -0,0 +1,106 @@
+package containerexec
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+func TestInteractive(t *testing.T) {
+	server, conn := net.Pipe()
+	go io.Copy(ioutil.Discard, server)
+
+	spy := &mockClient{conn: conn, stdout: "Applying changes from your project config files … done\n", exitCode: 1}
+	cmds := []string{"php", "craft", "project-config/apply"}
+
+	stdout, stderr := new(bytes.Buffer), new(bytes.Buffer)
+	code, err := Interactive(context.Background(), spy, "some-id", "www-data", cmds, bytes.NewBufferString(""), stdout, stderr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if code != 1 {
+		t.Errorf("expected the exit code to be 1, got %d", code)
+	}
+
+	want := types.ExecConfig{
+		User:         "www-data",
+		AttachStdin:  true,
+		AttachStdout: true,
+		AttachStderr: true,
+		Env:          []string{"TERM=xterm"},
+		Cmd:          cmds,
+	}
+	if !reflect.DeepEqual(spy.execConfig, want) {
+		t.Errorf("expected the exec config to match, got %v want %v", spy.execConfig, want)
+	}
+
+	if stdout.String() != spy.stdout {
+		t.Errorf("expected the output to match, got %q want %q", stdout.String(), spy.stdout)
+	}
+}
+
+func TestRun(t *testing.T) {
+	server, conn := net.Pipe()
+	go io.Copy(ioutil.Discard, server)
+
+	cmds := []string{"psql", "--username=nitro", "--command=CREATE DATABASE craft;"}
+
+	spy := &mockClient{conn: conn, stdout: "CREATE DATABASE\n"}
+	out, err := Run(context.Background(), spy, "some-id", cmds)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if out != "CREATE DATABASE" {
+		t.Errorf("expected the output to match, got %q", out)
+	}
+
+	want := types.ExecConfig{AttachStdout: true, AttachStderr: true, Cmd: cmds}
+	if !reflect.DeepEqual(spy.execConfig, want) {
+		t.Errorf("expected the exec config to match, got %v want %v", spy.execConfig, want)
+	}
+
+	// non-zero exit codes return the output in the error
+	spy = &mockClient{conn: conn, stdout: "database \"craft\" already exists\n", exitCode: 1}
+	if _, err := Run(context.Background(), spy, "some-id", cmds); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("expected the error to include the output, got %v", err)
+	}
+}
+
+type mockClient struct {
+	client.ContainerAPIClient
+
+	conn       net.Conn
+	execConfig types.ExecConfig
+	stdout     string
+	exitCode   int
+}
+
+func (c *mockClient) ContainerExecCreate(ctx context.Context, container string, config types.ExecConfig) (types.IDResponse, error) {
+	c.execConfig = config
+
+	return types.IDResponse{ID: "exec-id"}, nil
+}
+
+func (c *mockClient) ContainerExecAttach(ctx context.Context, execID string, config types.ExecStartCheck) (types.HijackedResponse, error) {
+	// multiplex the output the same way the docker API does
+	buf := new(bytes.Buffer)
+	stdcopy.NewStdWriter(buf, stdcopy.Stdout).Write([]byte(c.stdout))
+
+	return types.HijackedResponse{Conn: c.conn, Reader: bufio.NewReader(buf)}, nil
+}
+
+func (c *mockClient) ContainerExecInspect(ctx context.Context, execID string) (types.ContainerExecInspect, error) {
+	return types.ContainerExecInspect{ExitCode: c.exitCode}, nil
+}
@@ -1,9 +1,11 @@
 package containerlabels
 
 import (
+	"sort"
 	"strings"
 
 	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/environment"
 	"github.com/docker/docker/api/types"
 )
 
@@ -17,6 +19,9 @@ const (
 	// NitroContainerPort is used to identify a custom containers port in the config
 	NitroContainerPort = "com.craftcms.nitro.container-port"
 
+	// ConfigDir is used to label the proxy container with the nitro directory of the config it was created for
+	ConfigDir = "com.craftcms.nitro.config-dir"
+
 	// DatabaseCompatibility is the compatibility of the database (e.g. mariadb and mysql are compatible)
 	DatabaseCompatibility = "com.craftcms.nitro.database-compatibility"
 
@@ -29,6 +34,12 @@ const (
 	// DatabaseVersion is the version of the database the container is running (e.g. 11, 12, 5.7)
 	DatabaseVersion = "com.craftcms.nitro.database-version"
 
+	// DebugBanner is used to identify a site container that adds the debug banner to HTML pages
+	DebugBanner = "com.craftcms.nitro.debug-banner"
+
+	// Ephemeral is used to label a container that runs a single command (e.g. composer, npm) with the unix time it was created
+	Ephemeral = "com.craftcms.nitro.ephemeral"
+
 	// Extensions is used for a list of comma seperated extensions for a site
 	Extensions = "com.craftcms.nitro.extensions"
 
@@ -41,15 +52,30 @@ const (
 	// Network is used to label a network for an environment
 	Network = "com.craftcms.nitro.network"
 
+	// Nginx is used to label a site container with the fingerprint of the nginx options of the site
+	Nginx = "com.craftcms.nitro.nginx"
+
 	// Volume is used to identify a volume for an environment
 	Volume = "com.craftcms.nitro.volume"
 
+	// Owner is used to label the containers, volumes, and networks with the developer they belong to on a shared docker daemon
+	Owner = "com.craftcms.nitro.owner"
+
+	// Platform is used to label the proxy container with the platform of nitro that created it (e.g. wsl, windows)
+	Platform = "com.craftcms.nitro.platform"
+
 	// Proxy is the label used to identify the proxy container
 	Proxy = "com.craftcms.nitro.proxy"
 
 	// ProxyVersion is used to label a proxy container with a specific version
 	ProxyVersion = "com.craftcms.nitro.proxy-version"
 
+	// Queue is used to identify a queue worker container by the hostname of the site
+	Queue = "com.craftcms.nitro.queue"
+
+	// Replica is used to number the containers of a custom container that is scaled to multiple replicas
+	Replica = "com.craftcms.nitro.replica"
+
 	// Type is used to identity the type of container
 	Type = "com.craftcms.nitro.type"
 
@@ -60,7 +86,7 @@ const (
 // ForSite takes a site and returns labels to use on the sites container.
 func ForSite(s config.Site) map[string]string {
 	labels := map[string]string{
-		Nitro:   "true",
+		Nitro:   environment.Label(),
 		Host:    s.Hostname,
 		Webroot: s.Webroot,
 	}
@@ -77,7 +103,7 @@ func ForSite(s config.Site) map[string]string {
 // applies the labels for the container.
 func ForCustomContainer(c config.Container) map[string]string {
 	return map[string]string{
-		Nitro:          "true",
+		Nitro:          environment.Label(),
 		Type:           "custom",
 		NitroContainer: c.Name,
 	}
@@ -98,5 +124,33 @@ func Identify(c types.Container) string {
 		return "proxy"
 	}
 
+	if c.Labels[Queue] != "" {
+		return "queue"
+	}
+
 	return "site"
 }
+
+// StartOrder sorts the containers in the order they should be started, databases first,
+// then services and custom containers, then the proxy, then the sites since they depend on
+// the other containers, and the queue workers last. Reverse the order to stop the containers.
+func StartOrder(containers []types.Container) {
+	rank := func(c types.Container) int {
+		switch {
+		case c.Labels[DatabaseEngine] != "":
+			return 0
+		case c.Labels[Proxy] != "":
+			return 2
+		case c.Labels[Host] != "":
+			return 3
+		case c.Labels[Queue] != "":
+			return 4
+		default:
+			return 1
+		}
+	}
+
+	sort.SliceStable(containers, func(i, j int) bool {
+		return rank(containers[i]) < rank(containers[j])
+	})
+}
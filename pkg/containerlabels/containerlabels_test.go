@@ -0,0 +1,31 @@
+package containerlabels
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+)
+
+func TestStartOrder(t *testing.T) {
+	containers := []types.Container{
+		{ID: "queue", Labels: map[string]string{Nitro: "true", Queue: "craft.nitro"}},
+		{ID: "site", Labels: map[string]string{Nitro: "true", Host: "craft.nitro"}},
+		{ID: "proxy", Labels: map[string]string{Nitro: "true", Proxy: "true"}},
+		{ID: "mailhog", Labels: map[string]string{Nitro: "true", Type: "mailhog"}},
+		{ID: "mysql", Labels: map[string]string{Nitro: "true", Type: "database", DatabaseEngine: "mysql"}},
+		{ID: "search", Labels: map[string]string{Nitro: "true", NitroContainer: "search"}},
+	}
+
+	StartOrder(containers)
+
+	var got []string
+	for _, c := range containers {
+		got = append(got, c.ID)
+	}
+
+	want := []string{"mysql", "mailhog", "search", "proxy", "site", "queue"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("StartOrder() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,186 @@
+package dashboard
+
+import (
+	"html/template"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/craftcms/nitro/protob"
+)
+
+// Addr is the address the dashboard listens on inside of the proxy container, Caddy
+// proxies the requests for the dashboard hostname to it.
+const Addr = "127.0.0.1:5050"
+
+// Kinds are the kinds of items shown on the dashboard, in the order they are shown.
+var Kinds = []string{"site", "service", "database", "container"}
+
+// Timeout is the time to wait for an item to accept a connection before it is shown as down.
+var Timeout = 500 * time.Millisecond
+
+// Dashboard is an http.Handler that shows the sites, services, databases, and containers
+// from the last config that was applied to the proxy. Each item is dialed when the page is
+// requested to show if the container is up.
+type Dashboard struct {
+	// Version is the version of the proxy shown on the dashboard
+	Version string
+
+	mu        sync.RWMutex
+	dashboard *protob.Dashboard
+}
+
+// Update replaces the items on the dashboard.
+func (d *Dashboard) Update(dashboard *protob.Dashboard) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.dashboard = dashboard
+}
+
+// Current returns the dashboard from the last config that was applied.
+func (d *Dashboard) Current() *protob.Dashboard {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	return d.dashboard
+}
+
+// item is an item on the dashboard and its status.
+type item struct {
+	Name    string
+	URL     string
+	Details string
+	Up      bool
+	Checked bool
+}
+
+// group is the items of a single kind.
+type group struct {
+	Kind  string
+	Items []item
+}
+
+func (d *Dashboard) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	items := d.Items()
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+
+	if err := page.Execute(w, struct {
+		Version string
+		Groups  []group
+	}{Version: d.Version, Groups: groups(items)}); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// Items returns the items on the dashboard.
+func (d *Dashboard) Items() []*protob.DashboardItem {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	return d.dashboard.GetItems()
+}
+
+// Up dials the address of each item in parallel and returns true for the items that accept
+// a connection. Items without an address are not checked and are not up.
+func Up(items []*protob.DashboardItem) []bool {
+	up := make([]bool, len(items))
+
+	var wg sync.WaitGroup
+	for i, it := range items {
+		if it.GetAddress() == "" {
+			continue
+		}
+
+		wg.Add(1)
+
+		go func(i int, address string) {
+			defer wg.Done()
+
+			conn, err := net.DialTimeout("tcp", address, Timeout)
+			if err != nil {
+				return
+			}
+			conn.Close()
+
+			up[i] = true
+		}(i, it.GetAddress())
+	}
+
+	wg.Wait()
+
+	return up
+}
+
+// groups checks the status of the items in parallel and returns the items by kind.
+func groups(items []*protob.DashboardItem) []group {
+	up := Up(items)
+
+	checked := make([]item, len(items))
+	for i, it := range items {
+		checked[i] = item{Name: it.GetName(), URL: it.GetUrl(), Details: it.GetDetails(), Up: up[i], Checked: it.GetAddress() != ""}
+	}
+
+	var grouped []group
+	for _, k := range Kinds {
+		g := group{Kind: k}
+		for i, it := range items {
+			if it.GetKind() == k {
+				g.Items = append(g.Items, checked[i])
+			}
+		}
+
+		if len(g.Items) > 0 {
+			grouped = append(grouped, g)
+		}
+	}
+
+	return grouped
+}
+
+var page = template.Must(template.New("dashboard").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>Craft Nitro</title>
+    <style>
+        body { margin: 0; padding: 2rem; background: #f1f5fd; color: #3f4d5a; font-family: -apple-system, BlinkMacSystemFont, Segoe UI, Roboto, Helvetica Neue, sans-serif; }
+        main { max-width: 48rem; margin: 0 auto; }
+        h1 { font-weight: 300; }
+        h2 { font-size: 1rem; text-transform: uppercase; letter-spacing: 0.05rem; color: #8f98a3; }
+        ul { list-style: none; padding: 0; background: #fff; border-radius: 0.5rem; box-shadow: 0 1px 3px rgba(0, 0, 0, 0.1); }
+        li { display: flex; align-items: center; padding: 0.75rem 1rem; border-bottom: 1px solid #e3e8ef; }
+        li:last-child { border-bottom: none; }
+        a { color: #e5422b; text-decoration: none; }
+        .status { width: 0.6rem; height: 0.6rem; margin-right: 0.75rem; border-radius: 50%; background: #cdd3da; }
+        .up { background: #27ab83; }
+        .down { background: #e5422b; }
+        .details { margin-left: auto; color: #8f98a3; }
+        footer { margin-top: 2rem; color: #8f98a3; font-size: 0.875rem; }
+    </style>
+</head>
+<body>
+<main>
+    <h1>Craft Nitro</h1>
+    {{- range .Groups }}
+    <h2>{{ .Kind }}s</h2>
+    <ul>
+        {{- range .Items }}
+        <li>
+            <span class="status{{ if .Checked }}{{ if .Up }} up{{ else }} down{{ end }}{{ end }}" title="{{ if .Checked }}{{ if .Up }}up{{ else }}down{{ end }}{{ end }}"></span>
+            {{ if .URL }}<a href="{{ .URL }}" target="_blank" rel="noopener">{{ .Name }}</a>{{ else }}{{ .Name }}{{ end }}
+            <span class="details">{{ .Details }}</span>
+        </li>
+        {{- end }}
+    </ul>
+    {{- else }}
+    <p>There is nothing to show yet, run <code>nitro apply</code> to add your sites.</p>
+    {{- end }}
+    <footer>Nitro {{ .Version }}</footer>
+</main>
+</body>
+</html>
+`))
@@ -0,0 +1,61 @@
+package dashboard
+
+import (
+	"net"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/craftcms/nitro/protob"
+)
+
+func TestDashboard_ServeHTTP(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lis.Close()
+
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	closed.Close()
+
+	d := &Dashboard{Version: "2.0.0"}
+	d.Update(&protob.Dashboard{Hostname: "nitro.nitro", Items: []*protob.DashboardItem{
+		{Name: "mysql-8.0-3306.database.nitro", Kind: "database", Address: closed.Addr().String()},
+		{Name: "craft.nitro", Kind: "site", Url: "https://craft.nitro", Details: "PHP 8.0", Address: lis.Addr().String()},
+		{Name: "search", Kind: "container"},
+	}})
+
+	rec := httptest.NewRecorder()
+	d.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+
+	body := rec.Body.String()
+	for _, want := range []string{
+		`<a href="https://craft.nitro" target="_blank" rel="noopener">craft.nitro</a>`,
+		`<span class="status up" title="up"></span>`,
+		`<span class="status down" title="down"></span>`,
+		"PHP 8.0",
+		"Nitro 2.0.0",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected the dashboard to contain %q", want)
+		}
+	}
+
+	// sites are shown before databases
+	if strings.Index(body, "sites</h2>") > strings.Index(body, "databases</h2>") {
+		t.Errorf("expected the sites to be shown first")
+	}
+}
+
+func TestDashboard_ServeHTTP_Empty(t *testing.T) {
+	rec := httptest.NewRecorder()
+	(&Dashboard{}).ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+
+	if !strings.Contains(rec.Body.String(), "nitro apply") {
+		t.Errorf("expected the empty dashboard to explain how to add sites")
+	}
+}
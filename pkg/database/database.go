@@ -20,12 +20,43 @@ import (
 // ErrUnknownDatabaseEngine is returned when we are unable to determine the engine type from a database backup file.
 var ErrUnknownDatabaseEngine = fmt.Errorf("unknown database engine detected from file")
 
+// postgresCustomFormatHeader is the header pg_dump writes to the start of custom format (-Fc) backups.
+var postgresCustomFormatHeader = []byte("PGDMP")
+
+// IsPostgresCustomFormat takes a file and checks if the file is a
+// postgres backup created using the custom format (pg_dump -Fc).
+// Custom format backups must be restored using pg_restore.
+func IsPostgresCustomFormat(file string) (bool, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	header := make([]byte, len(postgresCustomFormatHeader))
+	if _, err := io.ReadFull(f, header); err != nil {
+		// the file is smaller than the header
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return bytes.Equal(header, postgresCustomFormatHeader), nil
+}
+
 // DetermineEngine takes a file and will check if the
 // content of the file is for mysql or postgres db
 // imports. It will return the engine "mysql" or
 // "postgres" if it can determine the engine.
 // If it cannot, it will return an error.
 func DetermineEngine(file string) (string, error) {
+	// custom format backups are binary, so check the header first
+	if custom, err := IsPostgresCustomFormat(file); err == nil && custom {
+		return "postgres", nil
+	}
+
 	f, err := os.Open(file)
 	if err != nil {
 		return "", err
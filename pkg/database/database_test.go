@@ -32,6 +32,12 @@ func TestDetermineEngine(t *testing.T) {
 			want:    "postgres",
 			wantErr: false,
 		},
+		{
+			name:    "can detect postgres custom format backup files",
+			args:    args{file: "./testdata/postgres-custom-backup.dump"},
+			want:    "postgres",
+			wantErr: false,
+		},
 		{
 			name:    "non mysql or postgres files return an error",
 			args:    args{file: "./testdata/random.txt"},
@@ -95,3 +101,40 @@ func TestHasCreateStatement(t *testing.T) {
 		})
 	}
 }
+
+func TestIsPostgresCustomFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		want    bool
+		wantErr bool
+	}{
+		{
+			name: "custom format backups return true",
+			file: "./testdata/postgres-custom-backup.dump",
+			want: true,
+		},
+		{
+			name: "plain postgres backups return false",
+			file: "./testdata/postgres-backup.sql",
+			want: false,
+		},
+		{
+			name:    "missing files return an error",
+			file:    "./testdata/missing.dump",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := IsPostgresCustomFormat(tt.file)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("IsPostgresCustomFormat() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("IsPostgresCustomFormat() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
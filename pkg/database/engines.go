@@ -0,0 +1,152 @@
+package database
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Versions is the list of versions available for each database engine,
+// the first version is used as the default.
+var Versions = map[string][]string{
+	"mariadb":  {"10.6", "10.5", "10.4", "10.3", "10.2"},
+	"mysql":    {"8.0", "5.7", "5.6"},
+	"postgres": {"14", "13", "12", "11", "10", "9"},
+}
+
+// ClientCommand returns the mysql compatible client used inside of a database
+// container. MariaDB 10.5 renamed the tools and newer images may not provide
+// the mysql names.
+func ClientCommand(engine, version string) string {
+	if usesMariaDBTools(engine, version) {
+		return "mariadb"
+	}
+
+	return "mysql"
+}
+
+// DumpCommand returns the mysql compatible tool used to backup a database
+// inside of a database container.
+func DumpCommand(engine, version string) string {
+	if usesMariaDBTools(engine, version) {
+		return "mariadb-dump"
+	}
+
+	return "mysqldump"
+}
+
+// HealthCheck returns the command docker uses to check if the database in a
+// container is accepting connections.
+func HealthCheck(engine, version string) []string {
+	switch engine {
+	case "postgres":
+		return []string{"CMD", "pg_isready", "--username=nitro"}
+	}
+
+	admin := "mysqladmin"
+	if usesMariaDBTools(engine, version) {
+		admin = "mariadb-admin"
+	}
+
+	return []string{"CMD", admin, "ping", "--host=127.0.0.1", "--user=root", "--password=nitro"}
+}
+
+// CreateCommands returns the commands to run inside of a database container to create
+// the database and grant the nitro user access to it.
+func CreateCommands(engine, version, db string) [][]string {
+	if engine == "postgres" {
+		// postgres cannot create a database inside of a transaction, so each statement is a command
+		return [][]string{
+			{"psql", "--username=nitro", "--dbname=nitro", fmt.Sprintf(`--command=CREATE DATABASE "%s";`, db)},
+			{"psql", "--username=nitro", "--dbname=nitro", fmt.Sprintf(`--command=GRANT ALL PRIVILEGES ON DATABASE "%s" TO nitro;`, db)},
+		}
+	}
+
+	return [][]string{
+		{ClientCommand(engine, version), "-uroot", "-pnitro", "-e", fmt.Sprintf("CREATE DATABASE IF NOT EXISTS `%s`; GRANT ALL PRIVILEGES ON `%s`.* TO 'nitro'@'%%'; FLUSH PRIVILEGES;", db, db)},
+	}
+}
+
+// ExistsCommand returns the command to run inside of a database container that prints the
+// name of the database when it exists.
+func ExistsCommand(engine, version, db string) []string {
+	if engine == "postgres" {
+		return []string{"psql", "--username=nitro", "--dbname=nitro", "-At", fmt.Sprintf(`--command=SELECT datname FROM pg_database WHERE datname = '%s';`, db)}
+	}
+
+	return []string{ClientCommand(engine, version), "-uroot", "-pnitro", "-N", "-B", "-e", fmt.Sprintf("SHOW DATABASES LIKE '%s';", db)}
+}
+
+// DropCommands returns the commands to run inside of a database container to remove the database.
+func DropCommands(engine, version, db string) [][]string {
+	if engine == "postgres" {
+		return [][]string{
+			{"psql", "--username=nitro", "--dbname=nitro", fmt.Sprintf(`--command=DROP DATABASE IF EXISTS "%s";`, db)},
+		}
+	}
+
+	return [][]string{
+		{ClientCommand(engine, version), "-uroot", "-pnitro", "-e", fmt.Sprintf("DROP DATABASE IF EXISTS `%s`;", db)},
+	}
+}
+
+// ImportCommands returns the commands to run inside of a database container to import the
+// backup file into the database. The database is created first, postgres custom format
+// backups are restored with pg_restore using the number of parallel jobs.
+func ImportCommands(engine, version, db, file string, custom bool, jobs int) [][]string {
+	cmds := CreateCommands(engine, version, db)
+
+	switch {
+	case engine == "postgres" && custom:
+		return append(cmds, append([]string{PostgresRestoreCommand}, RestoreCommand(&ImportOptions{DatabaseName: db, File: file, Jobs: jobs})...))
+	case engine == "postgres":
+		return append(cmds, []string{"psql", "--username=nitro", "--dbname=" + db, "--file=" + file})
+	}
+
+	return append(cmds, []string{ClientCommand(engine, version), "-uroot", "-pnitro", db, "-e", "source " + file})
+}
+
+// ConnectionURL returns the URL used to connect to a database from the host machine
+// with the default nitro credentials. The engine is the compatibility of the
+// database container, either "mysql" or "postgres".
+func ConnectionURL(engine, port, db string) string {
+	scheme := "mysql"
+	if engine == "postgres" {
+		scheme = "postgresql"
+	}
+
+	return fmt.Sprintf("%s://nitro:nitro@127.0.0.1:%s/%s", scheme, port, db)
+}
+
+func usesMariaDBTools(engine, version string) bool {
+	if engine != "mariadb" {
+		return false
+	}
+
+	sp := strings.Split(version, ".")
+
+	major, err := strconv.Atoi(sp[0])
+	if err != nil {
+		// versions such as latest use the new tools
+		return true
+	}
+
+	switch {
+	case major > 10:
+		return true
+	case major < 10:
+		return false
+	}
+
+	// versions such as 10 use the latest 10.x image
+	if len(sp) == 1 {
+		return true
+	}
+
+	minor, err := strconv.Atoi(sp[1])
+	if err != nil {
+		return true
+	}
+
+	return minor >= 5
+}
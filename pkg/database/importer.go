@@ -1,17 +1,22 @@
 package database
 
 import (
+	"bytes"
 	"fmt"
 	"io/ioutil"
 	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
 	"syscall"
 
 	"github.com/craftcms/nitro/pkg/pathexists"
 )
 
 var (
-	MySQLImportCommand    = "mysql"
-	PostgresImportCommand = "psql"
+	MySQLImportCommand     = "mysql"
+	PostgresImportCommand  = "psql"
+	PostgresRestoreCommand = "pg_restore"
 )
 
 // Importer is an interface that is designed to import a database backup
@@ -31,6 +36,10 @@ type ImportOptions struct {
 	Port            string
 	DatabaseName    string
 	File            string
+
+	// Jobs is the number of parallel jobs used to restore postgres custom
+	// format backups, it defaults to the number of CPUs.
+	Jobs int
 }
 
 type importer struct{}
@@ -70,6 +79,24 @@ func (importer *importer) Import(opts *ImportOptions, find func(engine, version
 	case "postgres":
 		createCommand = []string{fmt.Sprintf("--host=%s", opts.Hostname), "--port=" + opts.Port, "--username=nitro", fmt.Sprintf(`-c CREATE DATABASE %s;`, opts.DatabaseName)}
 		importCommand = []string{fmt.Sprintf("--host=%s", opts.Hostname), "--port=" + opts.Port, "--username=nitro", opts.DatabaseName, "--file=" + opts.File}
+
+		// custom format backups are restored with pg_restore, which is next to psql
+		custom, err := IsPostgresCustomFormat(opts.File)
+		if err != nil {
+			return err
+		}
+
+		if custom {
+			// create the database using psql before switching tools, pg_restore
+			// needs the database to exist
+			if err := importer.exec(tool, createCommand); err != nil && !strings.Contains(err.Error(), "already exists") {
+				return fmt.Errorf("unable to create the database %s, %w", opts.DatabaseName, err)
+			}
+
+			createCommand = nil
+			tool = filepath.Join(filepath.Dir(tool), PostgresRestoreCommand)
+			importCommand = RestoreCommand(opts)
+		}
 	default:
 		createCommand = []string{"--user=nitro", fmt.Sprintf("--host=%s", opts.Hostname), "-pnitro", fmt.Sprintf(`-e CREATE DATABASE IF NOT EXISTS %s;`, opts.DatabaseName)}
 		// https://dev.mysql.com/doc/refman/8.0/en/mysql-command-options.html
@@ -92,10 +119,39 @@ func (importer *importer) Import(opts *ImportOptions, find func(engine, version
 	return nil
 }
 
+// RestoreCommand returns the pg_restore arguments used to restore a
+// postgres custom format backup in parallel. The host and port are left
+// out when they are empty, for restores inside of the database container.
+func RestoreCommand(opts *ImportOptions) []string {
+	jobs := opts.Jobs
+	if jobs <= 0 {
+		jobs = runtime.NumCPU()
+	}
+
+	var args []string
+	if opts.Hostname != "" {
+		args = append(args, fmt.Sprintf("--host=%s", opts.Hostname))
+	}
+
+	if opts.Port != "" {
+		args = append(args, "--port="+opts.Port)
+	}
+
+	return append(args,
+		"--username=nitro",
+		"--dbname="+opts.DatabaseName,
+		"--no-owner",
+		fmt.Sprintf("--jobs=%d", jobs),
+		opts.File,
+	)
+}
+
 func (importer *importer) exec(tool string, commands []string) error {
 	c := exec.Command(tool, commands...)
 
-	c.Stderr = ioutil.Discard
+	// keep stderr for errors, such as a database that already exists
+	stderr := new(bytes.Buffer)
+	c.Stderr = stderr
 	c.Stdout = ioutil.Discard
 
 	if err := c.Start(); err != nil {
@@ -106,7 +162,7 @@ func (importer *importer) exec(tool string, commands []string) error {
 		if exiterr, ok := err.(*exec.ExitError); ok {
 			// The program has exited with an exit code != 0
 			if status, ok := exiterr.Sys().(syscall.WaitStatus); ok {
-				return fmt.Errorf("Exit Status: %d", status.ExitStatus())
+				return fmt.Errorf("Exit Status: %d, %s", status.ExitStatus(), strings.TrimSpace(stderr.String()))
 			}
 		} else {
 			return err
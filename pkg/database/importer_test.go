@@ -2,6 +2,7 @@ package database
 
 import (
 	"os/exec"
+	"reflect"
 	"testing"
 )
 
@@ -93,3 +94,18 @@ func TestDefaultImportToolFinder(t *testing.T) {
 		})
 	}
 }
+
+func TestRestoreCommand(t *testing.T) {
+	opts := &ImportOptions{
+		Hostname:     "127.0.0.1",
+		Port:         "5432",
+		DatabaseName: "craft",
+		File:         "backup.dump",
+		Jobs:         4,
+	}
+
+	want := []string{"--host=127.0.0.1", "--port=5432", "--username=nitro", "--dbname=craft", "--no-owner", "--jobs=4", "backup.dump"}
+	if got := RestoreCommand(opts); !reflect.DeepEqual(got, want) {
+		t.Errorf("RestoreCommand() = %v, want %v", got, want)
+	}
+}
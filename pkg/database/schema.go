@@ -0,0 +1,133 @@
+package database
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Column is the definition of a column in a table.
+type Column struct {
+	Type     string
+	Nullable string
+	Default  string
+}
+
+// String returns the definition of the column (e.g. int(11) NOT NULL DEFAULT 0).
+func (c Column) String() string {
+	s := c.Type
+	if c.Nullable == "NO" {
+		s += " NOT NULL"
+	}
+
+	if c.Default != "NULL" {
+		s += " DEFAULT " + c.Default
+	}
+
+	return s
+}
+
+// Schema is the columns of each table in a database, keyed by the table and column name.
+type Schema map[string]map[string]Column
+
+// SchemaChange is a difference between two schemas. The column is empty when the
+// whole table is added or removed.
+type SchemaChange struct {
+	Action string
+	Table  string
+	Column string
+	Before string
+	After  string
+}
+
+const (
+	// SchemaAdded is the action for a table or column that only exists in the second schema
+	SchemaAdded = "add"
+
+	// SchemaRemoved is the action for a table or column that only exists in the first schema
+	SchemaRemoved = "remove"
+
+	// SchemaChanged is the action for a column with a different definition in each schema
+	SchemaChanged = "change"
+)
+
+// SchemaCommand returns the command to run inside of a database container to list the
+// columns of each table in the database. Each line of the output is the table, column,
+// type, nullable, and default separated by tabs.
+func SchemaCommand(engine, version, db string) []string {
+	if engine == "postgres" {
+		return []string{"psql", "--username=nitro", "--dbname=" + db, "--no-align", "--tuples-only", "--field-separator=\t",
+			"--command=SELECT table_name, column_name, data_type, is_nullable, coalesce(column_default, 'NULL') FROM information_schema.columns WHERE table_schema = 'public' ORDER BY table_name, ordinal_position;",
+		}
+	}
+
+	return []string{ClientCommand(engine, version), "-uroot", "-pnitro", "--batch", "--skip-column-names", "-e", fmt.Sprintf(
+		"SELECT TABLE_NAME, COLUMN_NAME, COLUMN_TYPE, IS_NULLABLE, IFNULL(COLUMN_DEFAULT, 'NULL') FROM information_schema.COLUMNS WHERE TABLE_SCHEMA = '%s' ORDER BY TABLE_NAME, ORDINAL_POSITION;", db,
+	)}
+}
+
+// ParseSchema takes the output of the schema command and returns the schema. Lines that
+// are not columns, such as warnings from the client, are ignored.
+func ParseSchema(output string) Schema {
+	schema := make(Schema)
+	for _, l := range strings.Split(output, "\n") {
+		fields := strings.Split(strings.TrimRight(l, "\r"), "\t")
+		if len(fields) != 5 {
+			continue
+		}
+
+		table := fields[0]
+		if _, ok := schema[table]; !ok {
+			schema[table] = make(map[string]Column)
+		}
+
+		schema[table][fields[1]] = Column{Type: fields[2], Nullable: fields[3], Default: fields[4]}
+	}
+
+	return schema
+}
+
+// CompareSchemas returns the tables and columns that are added, removed, or changed in the
+// after schema, sorted by the table and column.
+func CompareSchemas(before, after Schema) []SchemaChange {
+	var changes []SchemaChange
+	for table, columns := range before {
+		afterColumns, ok := after[table]
+		if !ok {
+			changes = append(changes, SchemaChange{Action: SchemaRemoved, Table: table})
+			continue
+		}
+
+		for name, c := range columns {
+			a, ok := afterColumns[name]
+			switch {
+			case !ok:
+				changes = append(changes, SchemaChange{Action: SchemaRemoved, Table: table, Column: name, Before: c.String()})
+			case a != c:
+				changes = append(changes, SchemaChange{Action: SchemaChanged, Table: table, Column: name, Before: c.String(), After: a.String()})
+			}
+		}
+
+		for name, a := range afterColumns {
+			if _, ok := columns[name]; !ok {
+				changes = append(changes, SchemaChange{Action: SchemaAdded, Table: table, Column: name, After: a.String()})
+			}
+		}
+	}
+
+	for table := range after {
+		if _, ok := before[table]; !ok {
+			changes = append(changes, SchemaChange{Action: SchemaAdded, Table: table})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Table != changes[j].Table {
+			return changes[i].Table < changes[j].Table
+		}
+
+		return changes[i].Column < changes[j].Column
+	})
+
+	return changes
+}
@@ -0,0 +1,69 @@
+package database
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSchema(t *testing.T) {
+	output := "mysql: [Warning] Using a password on the command line interface can be insecure.\n" +
+		"users\tid\tint(11)\tNO\tNULL\n" +
+		"users\temail\tvarchar(255)\tYES\t''\n" +
+		"sites\tid\tint(11)\tNO\tNULL\n"
+
+	want := Schema{
+		"users": {
+			"id":    {Type: "int(11)", Nullable: "NO", Default: "NULL"},
+			"email": {Type: "varchar(255)", Nullable: "YES", Default: "''"},
+		},
+		"sites": {
+			"id": {Type: "int(11)", Nullable: "NO", Default: "NULL"},
+		},
+	}
+
+	if got := ParseSchema(output); !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseSchema() = %v, want %v", got, want)
+	}
+}
+
+func TestCompareSchemas(t *testing.T) {
+	id := Column{Type: "int(11)", Nullable: "NO", Default: "NULL"}
+	tests := []struct {
+		name   string
+		before Schema
+		after  Schema
+		want   []SchemaChange
+	}{
+		{
+			name:   "identical schemas have no changes",
+			before: Schema{"users": {"id": id}},
+			after:  Schema{"users": {"id": id}},
+		},
+		{
+			name:   "tables that are added and removed are returned",
+			before: Schema{"users": {"id": id}, "old": {"id": id}},
+			after:  Schema{"users": {"id": id}, "new": {"id": id}},
+			want: []SchemaChange{
+				{Action: SchemaAdded, Table: "new"},
+				{Action: SchemaRemoved, Table: "old"},
+			},
+		},
+		{
+			name:   "columns that are added, removed, and changed are returned",
+			before: Schema{"users": {"id": id, "name": {Type: "varchar(100)", Nullable: "YES", Default: "NULL"}, "old": id}},
+			after:  Schema{"users": {"id": id, "name": {Type: "varchar(255)", Nullable: "NO", Default: "''"}, "email": id}},
+			want: []SchemaChange{
+				{Action: SchemaAdded, Table: "users", Column: "email", After: "int(11) NOT NULL"},
+				{Action: SchemaChanged, Table: "users", Column: "name", Before: "varchar(100)", After: "varchar(255) NOT NULL DEFAULT ''"},
+				{Action: SchemaRemoved, Table: "users", Column: "old", Before: "int(11) NOT NULL"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CompareSchemas(tt.before, tt.after); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CompareSchemas() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
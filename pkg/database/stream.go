@@ -0,0 +1,170 @@
+package database
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/craftcms/nitro/pkg/filetype"
+)
+
+// Open returns a reader for the backup in the file that decompresses the backup while it
+// is read, so the backup is never written to a temporary file or held in memory. Gzip
+// files can contain a plain backup or a tar archive, zip and tar archives use the first
+// .sql file in the archive. Postgres custom format backups cannot be streamed, they are
+// copied into the container with Archive and restored from the file.
+func Open(path string) (io.ReadCloser, error) {
+	if custom, err := IsPostgresCustomFormat(path); err != nil || custom {
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, fmt.Errorf("%s is a postgres custom format backup and must be restored from a file", filepath.Base(path))
+	}
+
+	kind, err := filetype.Determine(path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch kind {
+	case "zip":
+		zr, err := zip.OpenReader(path)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, file := range zr.File {
+			if strings.HasSuffix(file.Name, ".sql") {
+				rc, err := file.Open()
+				if err != nil {
+					zr.Close()
+					return nil, err
+				}
+
+				return readCloser{Reader: rc, close: func() error {
+					rc.Close()
+					return zr.Close()
+				}}, nil
+			}
+		}
+
+		zr.Close()
+
+		return nil, fmt.Errorf("unable to find a .sql file in the zip")
+	case "tar":
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+
+		// the gzip file is either a compressed backup or a compressed tar archive
+		br := bufio.NewReaderSize(gz, 1024)
+		if !isTar(br) {
+			return readCloser{Reader: br, close: f.Close}, nil
+		}
+
+		tr := tar.NewReader(br)
+		for {
+			header, err := tr.Next()
+			if err == io.EOF {
+				break
+			}
+
+			if err != nil {
+				f.Close()
+				return nil, err
+			}
+
+			if header.Typeflag == tar.TypeReg && strings.HasSuffix(header.Name, ".sql") {
+				return readCloser{Reader: tr, close: f.Close}, nil
+			}
+		}
+
+		f.Close()
+
+		return nil, fmt.Errorf("unable to find a .sql file in the archive")
+	}
+
+	return os.Open(path)
+}
+
+// StdinImportCommand returns the command to run inside of a database container to import
+// a plain backup from stdin into the database. The database must already exist.
+func StdinImportCommand(engine, version, db string) []string {
+	if engine == "postgres" {
+		return []string{"psql", "--username=nitro", "--dbname=" + db}
+	}
+
+	return []string{ClientCommand(engine, version), "-uroot", "-pnitro", db}
+}
+
+// Archive returns a tar archive that contains the file as name, for copying a backup into a
+// container with CopyToContainer. The file is written to the archive while it is read, so
+// large backups are not held in memory.
+func Archive(path, name string) (io.ReadCloser, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	pr, pw := io.Pipe()
+
+	go func() {
+		defer f.Close()
+
+		tw := tar.NewWriter(pw)
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: info.Size(), ModTime: info.ModTime(), Typeflag: tar.TypeReg}); err != nil {
+			pw.CloseWithError(err)
+			return
+		}
+
+		if _, err := io.Copy(tw, f); err != nil {
+			pw.CloseWithError(err)
+			return
+		}
+
+		pw.CloseWithError(tw.Close())
+	}()
+
+	return pr, nil
+}
+
+// isTar checks for the magic of a tar header without consuming the reader.
+func isTar(br *bufio.Reader) bool {
+	header, err := br.Peek(262)
+	if err != nil {
+		return false
+	}
+
+	return bytes.Equal(header[257:262], []byte("ustar"))
+}
+
+// readCloser closes the file once the backup is read.
+type readCloser struct {
+	io.Reader
+	close func() error
+}
+
+func (r readCloser) Close() error {
+	return r.close()
+}
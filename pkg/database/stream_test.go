@@ -0,0 +1,131 @@
+package database
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestOpen(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nitro-open")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	backup := []byte("-- MySQL dump 10.13\nCREATE TABLE `users` (`id` int);\n")
+
+	// a gzip compressed backup
+	gz := &bytes.Buffer{}
+	gw := gzip.NewWriter(gz)
+	gw.Write(backup)
+	gw.Close()
+
+	// a gzip compressed tar archive
+	tgz := &bytes.Buffer{}
+	tgw := gzip.NewWriter(tgz)
+	tw := tar.NewWriter(tgw)
+	tw.WriteHeader(&tar.Header{Name: "README.md", Mode: 0644, Size: 5, Typeflag: tar.TypeReg})
+	tw.Write([]byte("hello"))
+	tw.WriteHeader(&tar.Header{Name: "backup.sql", Mode: 0644, Size: int64(len(backup)), Typeflag: tar.TypeReg})
+	tw.Write(backup)
+	tw.Close()
+	tgw.Close()
+
+	// a zip archive
+	z := &bytes.Buffer{}
+	zw := zip.NewWriter(z)
+	f, _ := zw.Create("backup.sql")
+	f.Write(backup)
+	zw.Close()
+
+	files := map[string][]byte{
+		"backup.sql":     backup,
+		"backup.sql.gz":  gz.Bytes(),
+		"backup.tar.gz":  tgz.Bytes(),
+		"backup.sql.zip": z.Bytes(),
+	}
+	for name, content := range files {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(dir, name)
+			if err := ioutil.WriteFile(path, content, 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			rdr, err := Open(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer rdr.Close()
+
+			got, err := ioutil.ReadAll(rdr)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !bytes.Equal(got, backup) {
+				t.Errorf("Open() = %q, want %q", got, backup)
+			}
+		})
+	}
+}
+
+func TestStdinImportCommand(t *testing.T) {
+	tests := []struct {
+		engine, version string
+		want            []string
+	}{
+		{engine: "mysql", version: "8.0", want: []string{"mysql", "-uroot", "-pnitro", "craft"}},
+		{engine: "postgres", version: "13", want: []string{"psql", "--username=nitro", "--dbname=craft"}},
+	}
+	for _, tt := range tests {
+		if got := StdinImportCommand(tt.engine, tt.version, "craft"); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("StdinImportCommand(%s) = %v, want %v", tt.engine, got, tt.want)
+		}
+	}
+}
+
+func TestArchive(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nitro-archive")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	backup := []byte("PGDMP\x01\x0e\x00")
+	path := filepath.Join(dir, "backup.dump")
+	if err := ioutil.WriteFile(path, backup, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	rdr, err := Archive(path, "nitro-backup.dump")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rdr.Close()
+
+	tr := tar.NewReader(rdr)
+	header, err := tr.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if header.Name != "nitro-backup.dump" {
+		t.Errorf("expected the file to be named nitro-backup.dump, got %s", header.Name)
+	}
+
+	got, err := ioutil.ReadAll(tr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(got, backup) {
+		t.Errorf("Archive() = %q, want %q", got, backup)
+	}
+}
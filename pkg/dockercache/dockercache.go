@@ -0,0 +1,373 @@
+package dockercache
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/client"
+	specs "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// Client wraps a docker client and caches the results of listing containers, volumes,
+// images, and networks for the lifetime of a single command. Commands like apply list
+// the same resources with overlapping filters many times, which is slow when the docker
+// daemon is remote. Any call that changes the state of the daemon clears the cache.
+type Client struct {
+	client.CommonAPIClient
+
+	mu         sync.Mutex
+	containers map[string][]types.Container
+	volumes    map[string]volume.VolumeListOKBody
+	images     map[string][]types.ImageSummary
+	networks   map[string][]types.NetworkResource
+
+	hits, misses int
+}
+
+// New takes a docker client and returns a client that caches list requests.
+func New(docker client.CommonAPIClient) *Client {
+	c := &Client{CommonAPIClient: docker}
+	c.Invalidate()
+
+	return c
+}
+
+// Invalidator is implemented by the clients that cache the lists and the clients that wrap
+// them, so commands that run for a long time can clear the cache before they list the
+// resources again.
+type Invalidator interface {
+	Invalidate()
+}
+
+// Invalidate clears the cached results of the docker client when it caches the lists.
+func Invalidate(docker client.CommonAPIClient) {
+	if i, ok := docker.(Invalidator); ok {
+		i.Invalidate()
+	}
+}
+
+// Invalidate clears all of the cached results.
+func (c *Client) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.containers = make(map[string][]types.Container)
+	c.volumes = make(map[string]volume.VolumeListOKBody)
+	c.images = make(map[string][]types.ImageSummary)
+	c.networks = make(map[string][]types.NetworkResource)
+}
+
+// Stats returns the number of list requests that were served from the cache
+// and the number that were sent to the docker API.
+func (c *Client) Stats() (hits, misses int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.hits, c.misses
+}
+
+// ContainerList returns the cached containers for the options or lists the containers.
+func (c *Client) ContainerList(ctx context.Context, options types.ContainerListOptions) ([]types.Container, error) {
+	key, err := key(options.Filters, options.Quiet, options.Size, options.All, options.Latest, options.Since, options.Before, options.Limit)
+	if err != nil {
+		return c.CommonAPIClient.ContainerList(ctx, options)
+	}
+
+	c.mu.Lock()
+	if cached, ok := c.containers[key]; ok {
+		c.hits++
+		c.mu.Unlock()
+
+		return append([]types.Container(nil), cached...), nil
+	}
+	c.misses++
+	c.mu.Unlock()
+
+	containers, err := c.CommonAPIClient.ContainerList(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.containers[key] = containers
+	c.mu.Unlock()
+
+	return append([]types.Container(nil), containers...), nil
+}
+
+// VolumeList returns the cached volumes for the filter or lists the volumes.
+func (c *Client) VolumeList(ctx context.Context, filter filters.Args) (volume.VolumeListOKBody, error) {
+	key, err := key(filter)
+	if err != nil {
+		return c.CommonAPIClient.VolumeList(ctx, filter)
+	}
+
+	c.mu.Lock()
+	if cached, ok := c.volumes[key]; ok {
+		c.hits++
+		c.mu.Unlock()
+
+		cached.Volumes = append([]*types.Volume(nil), cached.Volumes...)
+
+		return cached, nil
+	}
+	c.misses++
+	c.mu.Unlock()
+
+	volumes, err := c.CommonAPIClient.VolumeList(ctx, filter)
+	if err != nil {
+		return volumes, err
+	}
+
+	c.mu.Lock()
+	c.volumes[key] = volumes
+	c.mu.Unlock()
+
+	volumes.Volumes = append([]*types.Volume(nil), volumes.Volumes...)
+
+	return volumes, nil
+}
+
+// ImageList returns the cached images for the options or lists the images.
+func (c *Client) ImageList(ctx context.Context, options types.ImageListOptions) ([]types.ImageSummary, error) {
+	key, err := key(options.Filters, options.All)
+	if err != nil {
+		return c.CommonAPIClient.ImageList(ctx, options)
+	}
+
+	c.mu.Lock()
+	if cached, ok := c.images[key]; ok {
+		c.hits++
+		c.mu.Unlock()
+
+		return append([]types.ImageSummary(nil), cached...), nil
+	}
+	c.misses++
+	c.mu.Unlock()
+
+	images, err := c.CommonAPIClient.ImageList(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.images[key] = images
+	c.mu.Unlock()
+
+	return append([]types.ImageSummary(nil), images...), nil
+}
+
+// NetworkList returns the cached networks for the options or lists the networks.
+func (c *Client) NetworkList(ctx context.Context, options types.NetworkListOptions) ([]types.NetworkResource, error) {
+	key, err := key(options.Filters)
+	if err != nil {
+		return c.CommonAPIClient.NetworkList(ctx, options)
+	}
+
+	c.mu.Lock()
+	if cached, ok := c.networks[key]; ok {
+		c.hits++
+		c.mu.Unlock()
+
+		return append([]types.NetworkResource(nil), cached...), nil
+	}
+	c.misses++
+	c.mu.Unlock()
+
+	networks, err := c.CommonAPIClient.NetworkList(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.networks[key] = networks
+	c.mu.Unlock()
+
+	return append([]types.NetworkResource(nil), networks...), nil
+}
+
+// ContainerCreate creates the container and clears the cache.
+func (c *Client) ContainerCreate(ctx context.Context, config *container.Config, hostConfig *container.HostConfig, networkingConfig *network.NetworkingConfig, platform *specs.Platform, containerName string) (container.ContainerCreateCreatedBody, error) {
+	defer c.Invalidate()
+
+	return c.CommonAPIClient.ContainerCreate(ctx, config, hostConfig, networkingConfig, platform, containerName)
+}
+
+// ContainerRemove removes the container and clears the cache.
+func (c *Client) ContainerRemove(ctx context.Context, containerID string, options types.ContainerRemoveOptions) error {
+	defer c.Invalidate()
+
+	return c.CommonAPIClient.ContainerRemove(ctx, containerID, options)
+}
+
+// ContainerStart starts the container and clears the cache.
+func (c *Client) ContainerStart(ctx context.Context, containerID string, options types.ContainerStartOptions) error {
+	defer c.Invalidate()
+
+	return c.CommonAPIClient.ContainerStart(ctx, containerID, options)
+}
+
+// ContainerStop stops the container and clears the cache.
+func (c *Client) ContainerStop(ctx context.Context, containerID string, timeout *time.Duration) error {
+	defer c.Invalidate()
+
+	return c.CommonAPIClient.ContainerStop(ctx, containerID, timeout)
+}
+
+// ContainerRestart restarts the container and clears the cache.
+func (c *Client) ContainerRestart(ctx context.Context, containerID string, timeout *time.Duration) error {
+	defer c.Invalidate()
+
+	return c.CommonAPIClient.ContainerRestart(ctx, containerID, timeout)
+}
+
+// ContainerUpdate updates the resources of the container and clears the cache.
+func (c *Client) ContainerUpdate(ctx context.Context, containerID string, updateConfig container.UpdateConfig) (container.ContainerUpdateOKBody, error) {
+	defer c.Invalidate()
+
+	return c.CommonAPIClient.ContainerUpdate(ctx, containerID, updateConfig)
+}
+
+// ContainerKill kills the container and clears the cache.
+func (c *Client) ContainerKill(ctx context.Context, containerID, signal string) error {
+	defer c.Invalidate()
+
+	return c.CommonAPIClient.ContainerKill(ctx, containerID, signal)
+}
+
+// ContainerRename renames the container and clears the cache.
+func (c *Client) ContainerRename(ctx context.Context, containerID, newContainerName string) error {
+	defer c.Invalidate()
+
+	return c.CommonAPIClient.ContainerRename(ctx, containerID, newContainerName)
+}
+
+// ContainersPrune removes stopped containers and clears the cache.
+func (c *Client) ContainersPrune(ctx context.Context, pruneFilters filters.Args) (types.ContainersPruneReport, error) {
+	defer c.Invalidate()
+
+	return c.CommonAPIClient.ContainersPrune(ctx, pruneFilters)
+}
+
+// ImagePull pulls the image and clears the cache. The image is pulled while the response
+// is read, so the cache is cleared again when the response is closed.
+func (c *Client) ImagePull(ctx context.Context, ref string, options types.ImagePullOptions) (io.ReadCloser, error) {
+	defer c.Invalidate()
+
+	rdr, err := c.CommonAPIClient.ImagePull(ctx, ref, options)
+	if err != nil {
+		return rdr, err
+	}
+
+	return &invalidatingBody{ReadCloser: rdr, client: c}, nil
+}
+
+// ImageBuild builds the image and clears the cache. The image is built while the response
+// is read, so the cache is cleared again when the response is closed.
+func (c *Client) ImageBuild(ctx context.Context, buildContext io.Reader, options types.ImageBuildOptions) (types.ImageBuildResponse, error) {
+	defer c.Invalidate()
+
+	resp, err := c.CommonAPIClient.ImageBuild(ctx, buildContext, options)
+	if err != nil {
+		return resp, err
+	}
+
+	resp.Body = &invalidatingBody{ReadCloser: resp.Body, client: c}
+
+	return resp, nil
+}
+
+// ImageRemove removes the image and clears the cache.
+func (c *Client) ImageRemove(ctx context.Context, image string, options types.ImageRemoveOptions) ([]types.ImageDeleteResponseItem, error) {
+	defer c.Invalidate()
+
+	return c.CommonAPIClient.ImageRemove(ctx, image, options)
+}
+
+// ImagesPrune removes unused images and clears the cache.
+func (c *Client) ImagesPrune(ctx context.Context, pruneFilter filters.Args) (types.ImagesPruneReport, error) {
+	defer c.Invalidate()
+
+	return c.CommonAPIClient.ImagesPrune(ctx, pruneFilter)
+}
+
+// NetworkCreate creates the network and clears the cache.
+func (c *Client) NetworkCreate(ctx context.Context, name string, options types.NetworkCreate) (types.NetworkCreateResponse, error) {
+	defer c.Invalidate()
+
+	return c.CommonAPIClient.NetworkCreate(ctx, name, options)
+}
+
+// NetworkRemove removes the network and clears the cache.
+func (c *Client) NetworkRemove(ctx context.Context, networkID string) error {
+	defer c.Invalidate()
+
+	return c.CommonAPIClient.NetworkRemove(ctx, networkID)
+}
+
+// NetworkConnect connects the container to the network and clears the cache.
+func (c *Client) NetworkConnect(ctx context.Context, networkID, containerID string, config *network.EndpointSettings) error {
+	defer c.Invalidate()
+
+	return c.CommonAPIClient.NetworkConnect(ctx, networkID, containerID, config)
+}
+
+// NetworkDisconnect disconnects the container from the network and clears the cache.
+func (c *Client) NetworkDisconnect(ctx context.Context, networkID, containerID string, force bool) error {
+	defer c.Invalidate()
+
+	return c.CommonAPIClient.NetworkDisconnect(ctx, networkID, containerID, force)
+}
+
+// VolumeCreate creates the volume and clears the cache.
+func (c *Client) VolumeCreate(ctx context.Context, options volume.VolumeCreateBody) (types.Volume, error) {
+	defer c.Invalidate()
+
+	return c.CommonAPIClient.VolumeCreate(ctx, options)
+}
+
+// VolumeRemove removes the volume and clears the cache.
+func (c *Client) VolumeRemove(ctx context.Context, volumeID string, force bool) error {
+	defer c.Invalidate()
+
+	return c.CommonAPIClient.VolumeRemove(ctx, volumeID, force)
+}
+
+// VolumesPrune removes unused volumes and clears the cache.
+func (c *Client) VolumesPrune(ctx context.Context, pruneFilter filters.Args) (types.VolumesPruneReport, error) {
+	defer c.Invalidate()
+
+	return c.CommonAPIClient.VolumesPrune(ctx, pruneFilter)
+}
+
+// invalidatingBody clears the cache when the body of a response is closed.
+type invalidatingBody struct {
+	io.ReadCloser
+
+	client *Client
+}
+
+func (b *invalidatingBody) Close() error {
+	defer b.client.Invalidate()
+
+	return b.ReadCloser.Close()
+}
+
+// key takes the filters and the remaining options for a list request
+// and returns a string that is used to look up the cached results.
+func key(f filters.Args, opts ...interface{}) (string, error) {
+	args, err := filters.ToJSON(f)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s%v", args, opts), nil
+}
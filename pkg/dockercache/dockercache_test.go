@@ -0,0 +1,198 @@
+package dockercache
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+)
+
+func TestClient_ContainerList(t *testing.T) {
+	ctx := context.Background()
+	spy := &mockClient{containers: []types.Container{{ID: "one"}}}
+	docker := New(spy)
+
+	nitro := filters.NewArgs()
+	nitro.Add("label", "com.craftcms.nitro")
+
+	// repeated lists with the same options are cached
+	for i := 0; i < 3; i++ {
+		if _, err := docker.ContainerList(ctx, types.ContainerListOptions{Filters: nitro, All: true}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if spy.containerListCalls != 1 {
+		t.Errorf("expected ContainerList to be called once, got %d", spy.containerListCalls)
+	}
+
+	// different options are not served from the cache
+	if _, err := docker.ContainerList(ctx, types.ContainerListOptions{Filters: nitro}); err != nil {
+		t.Fatal(err)
+	}
+
+	if spy.containerListCalls != 2 {
+		t.Errorf("expected ContainerList to be called twice, got %d", spy.containerListCalls)
+	}
+
+	// changing the daemon clears the cache
+	if err := docker.ContainerStart(ctx, "one", types.ContainerStartOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	containers, err := docker.ContainerList(ctx, types.ContainerListOptions{Filters: nitro, All: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if spy.containerListCalls != 3 {
+		t.Errorf("expected ContainerList to be called after starting a container, got %d", spy.containerListCalls)
+	}
+
+	// modifying the returned containers does not modify the cache
+	containers[0].State = "modified"
+	containers, err = docker.ContainerList(ctx, types.ContainerListOptions{Filters: nitro, All: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if containers[0].State == "modified" {
+		t.Errorf("expected the cached containers to be copied")
+	}
+
+	if hits, misses := docker.Stats(); hits != 3 || misses != 3 {
+		t.Errorf("Stats() = %d, %d, want 3, 3", hits, misses)
+	}
+}
+
+func TestClient_Invalidate(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		change func(docker *Client) error
+	}{
+		{
+			name: "building an image clears the cache",
+			change: func(docker *Client) error {
+				resp, err := docker.ImageBuild(ctx, strings.NewReader(""), types.ImageBuildOptions{})
+				if err != nil {
+					return err
+				}
+
+				return resp.Body.Close()
+			},
+		},
+		{
+			name: "pulling an image clears the cache",
+			change: func(docker *Client) error {
+				rdr, err := docker.ImagePull(ctx, "nginx", types.ImagePullOptions{})
+				if err != nil {
+					return err
+				}
+
+				return rdr.Close()
+			},
+		},
+		{
+			name: "updating a container clears the cache",
+			change: func(docker *Client) error {
+				_, err := docker.ContainerUpdate(ctx, "one", container.UpdateConfig{})
+				return err
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spy := &mockClient{containers: []types.Container{{ID: "one"}}}
+			docker := New(spy)
+
+			if _, err := docker.ContainerList(ctx, types.ContainerListOptions{All: true}); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := tt.change(docker); err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := docker.ContainerList(ctx, types.ContainerListOptions{All: true}); err != nil {
+				t.Fatal(err)
+			}
+
+			if spy.containerListCalls != 2 {
+				t.Errorf("expected ContainerList to be called after the change, got %d", spy.containerListCalls)
+			}
+		})
+	}
+
+	// the image is built or pulled while the response is read, so closing the response clears the cache
+	streams := map[string]func(docker *Client) (io.Closer, error){
+		"build": func(docker *Client) (io.Closer, error) {
+			resp, err := docker.ImageBuild(ctx, strings.NewReader(""), types.ImageBuildOptions{})
+			return resp.Body, err
+		},
+		"pull": func(docker *Client) (io.Closer, error) {
+			return docker.ImagePull(ctx, "nginx", types.ImagePullOptions{})
+		},
+	}
+	for name, stream := range streams {
+		spy := &mockClient{containers: []types.Container{{ID: "one"}}}
+		docker := New(spy)
+
+		body, err := stream(docker)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := docker.ContainerList(ctx, types.ContainerListOptions{All: true}); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := body.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := docker.ContainerList(ctx, types.ContainerListOptions{All: true}); err != nil {
+			t.Fatal(err)
+		}
+
+		if spy.containerListCalls != 2 {
+			t.Errorf("expected ContainerList to be called after the %s completed, got %d", name, spy.containerListCalls)
+		}
+	}
+}
+
+type mockClient struct {
+	client.CommonAPIClient
+
+	containers         []types.Container
+	containerListCalls int
+}
+
+func (c *mockClient) ContainerList(ctx context.Context, options types.ContainerListOptions) ([]types.Container, error) {
+	c.containerListCalls++
+
+	return append([]types.Container(nil), c.containers...), nil
+}
+
+func (c *mockClient) ContainerStart(ctx context.Context, container string, options types.ContainerStartOptions) error {
+	return nil
+}
+
+func (c *mockClient) ContainerUpdate(ctx context.Context, containerID string, updateConfig container.UpdateConfig) (container.ContainerUpdateOKBody, error) {
+	return container.ContainerUpdateOKBody{}, nil
+}
+
+func (c *mockClient) ImageBuild(ctx context.Context, buildContext io.Reader, options types.ImageBuildOptions) (types.ImageBuildResponse, error) {
+	return types.ImageBuildResponse{Body: ioutil.NopCloser(strings.NewReader(""))}, nil
+}
+
+func (c *mockClient) ImagePull(ctx context.Context, ref string, options types.ImagePullOptions) (io.ReadCloser, error) {
+	return ioutil.NopCloser(strings.NewReader("")), nil
+}
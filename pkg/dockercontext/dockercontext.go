@@ -0,0 +1,216 @@
+package dockercontext
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/docker/docker/client"
+
+	"github.com/craftcms/nitro/pkg/pathexists"
+)
+
+// Default is the name of the docker context that uses DOCKER_HOST or the local daemon.
+const Default = "default"
+
+// Current is the endpoint of the docker daemon nitro is using for the command.
+var Current Endpoint
+
+// Endpoint is the docker daemon of a docker context.
+type Endpoint struct {
+	// Name is the name of the docker context
+	Name string
+
+	// Host is the address of the daemon (e.g. tcp://192.168.1.10:2376), it is empty for
+	// the default context
+	Host string
+
+	// CA, Cert, and Key are the paths to the TLS files of the context, they are empty when
+	// the context does not use TLS
+	CA   string
+	Cert string
+	Key  string
+}
+
+// meta is the meta.json file of a docker context.
+type meta struct {
+	Name      string `json:"Name"`
+	Endpoints map[string]struct {
+		Host string `json:"Host"`
+	} `json:"Endpoints"`
+}
+
+// Resolve returns the endpoint for the docker context in the same order as the docker
+// CLI. The name (from the --docker-context flag) is used first, then DOCKER_HOST, then
+// DOCKER_CONTEXT, and then the current context in the docker config file.
+func Resolve(home, name string) (Endpoint, error) {
+	dir := configDir(home)
+
+	if name == "" && os.Getenv("DOCKER_HOST") == "" {
+		name = os.Getenv("DOCKER_CONTEXT")
+	}
+
+	if name == "" && os.Getenv("DOCKER_HOST") == "" {
+		name = currentContext(dir)
+	}
+
+	if name == "" || name == Default {
+		return Endpoint{Name: Default}, nil
+	}
+
+	id := fmt.Sprintf("%x", sha256.Sum256([]byte(name)))
+
+	b, err := ioutil.ReadFile(filepath.Join(dir, "contexts", "meta", id, "meta.json"))
+	if os.IsNotExist(err) {
+		return Endpoint{}, fmt.Errorf("unable to find the docker context %q", name)
+	}
+
+	if err != nil {
+		return Endpoint{}, err
+	}
+
+	var m meta
+	if err := json.Unmarshal(b, &m); err != nil {
+		return Endpoint{}, fmt.Errorf("unable to read the docker context %q, %w", name, err)
+	}
+
+	e := Endpoint{Name: name, Host: m.Endpoints["docker"].Host}
+
+	tls := filepath.Join(dir, "contexts", "tls", id, "docker")
+	if pathexists.IsFile(filepath.Join(tls, "ca.pem")) {
+		e.CA = filepath.Join(tls, "ca.pem")
+		e.Cert = filepath.Join(tls, "cert.pem")
+		e.Key = filepath.Join(tls, "key.pem")
+	}
+
+	return e, nil
+}
+
+// Opts returns the options for the docker client to connect to the endpoint. The default
+// context does not have any options, as the client uses DOCKER_HOST or the local daemon.
+func (e Endpoint) Opts() ([]client.Opt, error) {
+	if e.Host == "" {
+		return nil, nil
+	}
+
+	u, err := url.Parse(e.Host)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse the host %q of the docker context %q, %w", e.Host, e.Name, err)
+	}
+
+	if u.Scheme == "ssh" {
+		return nil, fmt.Errorf("the docker context %q uses ssh, which is not supported, use a tcp host instead", e.Name)
+	}
+
+	opts := []client.Opt{client.WithHost(e.Host)}
+	if e.CA != "" {
+		opts = append(opts, client.WithTLSClientConfig(e.CA, e.Cert, e.Key))
+	}
+
+	return opts, nil
+}
+
+// Remote returns true when the docker daemon is on another machine, so the paths of the
+// bind mounts and the ports of the containers are on that machine.
+func (e Endpoint) Remote() bool {
+	return hostname(e.host()) != ""
+}
+
+// Address returns the IP address to reach the containers of the daemon, it is 127.0.0.1
+// unless the daemon is remote.
+func (e Endpoint) Address() string {
+	h := hostname(e.host())
+	if h == "" {
+		return "127.0.0.1"
+	}
+
+	if ip := net.ParseIP(h); ip != nil {
+		return ip.String()
+	}
+
+	ips, err := net.LookupIP(h)
+	if err != nil {
+		return h
+	}
+
+	for _, ip := range ips {
+		if ip.To4() != nil {
+			return ip.String()
+		}
+	}
+
+	return h
+}
+
+// String returns the host of the daemon with the name of the context.
+func (e Endpoint) String() string {
+	host := e.host()
+	if host == "" {
+		host = client.DefaultDockerHost
+	}
+
+	if e.Name == "" || e.Name == Default {
+		return host
+	}
+
+	return fmt.Sprintf("%s (%s)", host, e.Name)
+}
+
+func (e Endpoint) host() string {
+	if e.Host != "" {
+		return e.Host
+	}
+
+	return os.Getenv("DOCKER_HOST")
+}
+
+// hostname returns the hostname of a remote docker host, it is empty for local sockets
+// and the loopback address.
+func hostname(host string) string {
+	u, err := url.Parse(host)
+	if err != nil || u.Scheme == "unix" || u.Scheme == "npipe" || u.Scheme == "" {
+		return ""
+	}
+
+	h := u.Hostname()
+	if h == "localhost" {
+		return ""
+	}
+
+	if ip := net.ParseIP(h); ip != nil && ip.IsLoopback() {
+		return ""
+	}
+
+	return h
+}
+
+// configDir returns the docker config directory, which can be changed with DOCKER_CONFIG.
+func configDir(home string) string {
+	if dir := os.Getenv("DOCKER_CONFIG"); dir != "" {
+		return dir
+	}
+
+	return filepath.Join(home, ".docker")
+}
+
+// currentContext returns the current context from the docker config file.
+func currentContext(dir string) string {
+	b, err := ioutil.ReadFile(filepath.Join(dir, "config.json"))
+	if err != nil {
+		return ""
+	}
+
+	var cfg struct {
+		CurrentContext string `json:"currentContext"`
+	}
+	if err := json.Unmarshal(b, &cfg); err != nil {
+		return ""
+	}
+
+	return cfg.CurrentContext
+}
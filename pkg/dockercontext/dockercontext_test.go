@@ -0,0 +1,101 @@
+package dockercontext
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolve(t *testing.T) {
+	home, err := ioutil.TempDir("", "dockercontext")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(home)
+
+	for _, env := range []string{"DOCKER_HOST", "DOCKER_CONTEXT", "DOCKER_CONFIG"} {
+		defer os.Setenv(env, os.Getenv(env))
+		os.Unsetenv(env)
+	}
+
+	id := fmt.Sprintf("%x", sha256.Sum256([]byte("remote")))
+	files := map[string]string{
+		filepath.Join(".docker", "config.json"):                             `{"currentContext": "remote"}`,
+		filepath.Join(".docker", "contexts", "meta", id, "meta.json"):       `{"Name": "remote", "Endpoints": {"docker": {"Host": "tcp://192.168.1.10:2376"}}}`,
+		filepath.Join(".docker", "contexts", "tls", id, "docker", "ca.pem"): "ca",
+	}
+	for f, content := range files {
+		path := filepath.Join(home, f)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the current context is used by default
+	e, err := Resolve(home, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if e.Name != "remote" || e.Host != "tcp://192.168.1.10:2376" || e.CA == "" {
+		t.Errorf("expected the remote context with TLS, got %+v", e)
+	}
+
+	if !e.Remote() || e.Address() != "192.168.1.10" {
+		t.Errorf("expected the context to be remote at 192.168.1.10, got %v %s", e.Remote(), e.Address())
+	}
+
+	// DOCKER_HOST takes precedence over the current context
+	os.Setenv("DOCKER_HOST", "unix:///var/run/docker.sock")
+	if e, err := Resolve(home, ""); err != nil || e.Name != Default || e.Remote() {
+		t.Errorf("expected the default local context when DOCKER_HOST is set, got %+v, %v", e, err)
+	}
+
+	// the flag takes precedence over DOCKER_HOST
+	if e, err := Resolve(home, "remote"); err != nil || e.Name != "remote" {
+		t.Errorf("expected the remote context from the flag, got %+v, %v", e, err)
+	}
+
+	if _, err := Resolve(home, "missing"); err == nil {
+		t.Errorf("expected an error for a missing context")
+	}
+}
+
+func TestEndpoint_Remote(t *testing.T) {
+	tests := []struct {
+		host string
+		want bool
+	}{
+		{host: "", want: false},
+		{host: "unix:///var/run/docker.sock", want: false},
+		{host: "npipe:////./pipe/docker_engine", want: false},
+		{host: "tcp://127.0.0.1:2375", want: false},
+		{host: "tcp://localhost:2375", want: false},
+		{host: "tcp://10.0.0.5:2376", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			if got := (Endpoint{Host: tt.host}).Remote(); got != tt.want {
+				t.Errorf("Remote() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEndpoint_Opts(t *testing.T) {
+	if _, err := (Endpoint{Name: "ssh", Host: "ssh://user@example.com"}).Opts(); err == nil {
+		t.Errorf("expected an error for ssh hosts")
+	}
+
+	opts, err := (Endpoint{Name: "remote", Host: "tcp://10.0.0.5:2376"}).Opts()
+	if err != nil || len(opts) != 1 {
+		t.Errorf("expected the host option, got %d options, %v", len(opts), err)
+	}
+}
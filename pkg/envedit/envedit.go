@@ -71,3 +71,65 @@ func EnvExists(file, key string) bool {
 
 	return false
 }
+
+// Value takes an existing env file and key and returns the value of the env var without quotes. If the file
+// does not exist or the env var is not defined, it will return an empty string.
+func Value(file, key string) string {
+	// read the file
+	f, err := ioutil.ReadFile(file)
+	if err != nil {
+		return ""
+	}
+
+	// split the file into multiple lines
+	lines := strings.Split(string(f), "\n")
+	for _, txt := range lines {
+		// split using the first =
+		sp := strings.SplitN(strings.TrimSpace(txt), "=", 2)
+
+		if sp[0] == key && len(sp) == 2 {
+			return strings.Trim(sp[1], `"'`)
+		}
+	}
+
+	return ""
+}
+
+// Parse takes an env file and returns the env vars in the file without quotes. Comments, empty
+// lines, and the export keyword are skipped. If the file does not exist, it returns ErrNoEnvFile.
+func Parse(file string) (map[string]string, error) {
+	f, err := ioutil.ReadFile(file)
+	if os.IsNotExist(err) {
+		return nil, ErrNoEnvFile
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	vars := map[string]string{}
+	for _, txt := range strings.Split(string(f), "\n") {
+		txt = strings.TrimSpace(txt)
+		if txt == "" || strings.HasPrefix(txt, "#") {
+			continue
+		}
+
+		// split using the first =
+		sp := strings.SplitN(strings.TrimPrefix(txt, "export "), "=", 2)
+		if len(sp) != 2 {
+			continue
+		}
+
+		value := strings.TrimSpace(sp[1])
+		switch {
+		case len(value) > 1 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0]:
+			value = value[1 : len(value)-1]
+		case strings.Contains(value, " #"):
+			// remove the comment after an unquoted value
+			value = strings.TrimSpace(value[:strings.Index(value, " #")])
+		}
+
+		vars[strings.TrimSpace(sp[0])] = value
+	}
+
+	return vars, nil
+}
@@ -118,3 +118,57 @@ func TestEnvExists(t *testing.T) {
 		})
 	}
 }
+
+func TestValue(t *testing.T) {
+	type args struct {
+		file string
+		key  string
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "existing env var in file returns the value",
+			args: args{file: "testdata/env-example-golden", key: "ENVIRONMENT"},
+			want: "dev",
+		},
+		{
+			name: "missing env var in file returns an empty string",
+			args: args{file: "testdata/env-example-golden", key: "MISSING"},
+			want: "",
+		},
+		{
+			name: "missing files returns an empty string",
+			args: args{file: "testdata/env-example-not-here", key: "ENVIRONMENT"},
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Value(tt.args.file, tt.args.key); got != tt.want {
+				t.Errorf("Value() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	vars, err := Parse("testdata/env-example-golden")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if vars["ENVIRONMENT"] != "dev" || vars["DB_PORT"] != "5432" {
+		t.Errorf("expected the env vars from the file, got %v", vars)
+	}
+
+	if v, ok := vars["SECURITY_KEY"]; !ok || v != "" {
+		t.Errorf("expected empty env vars to be included, got %q", v)
+	}
+
+	if _, err := Parse("testdata/env-example-not-here"); err != ErrNoEnvFile {
+		t.Errorf("expected ErrNoEnvFile for missing files, got %v", err)
+	}
+}
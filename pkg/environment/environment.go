@@ -0,0 +1,249 @@
+package environment
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/user"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	// Default is the name of the environment that is used when no environment is selected,
+	// it keeps the names nitro used before there were multiple environments.
+	Default = "nitro"
+
+	// EnvVar is the environment variable used to select the environment.
+	EnvVar = "NITRO_ENVIRONMENT"
+
+	// FileName is the name of the file in the nitro directory with the environment set by
+	// nitro env use.
+	FileName = "environment"
+
+	// SharedEnvVar is the environment variable that enables sharing the docker daemon with
+	// other developers, the resources are owned by the current user.
+	SharedEnvVar = "NITRO_SHARED"
+
+	// HomeEnvVar is the environment variable used to set the home directory with the nitro
+	// directory, it allows nitro in WSL and on windows to share the config.
+	HomeEnvVar = "NITRO_HOME"
+
+	// OwnerEnvVar is the environment variable used to set the owner of the resources on a
+	// shared docker daemon instead of the current user.
+	OwnerEnvVar = "NITRO_OWNER"
+)
+
+// Name is the name of the environment nitro is using for the command.
+var Name = Default
+
+// File is the config file set with the --config flag, it is used instead of the config
+// file of the environment in the nitro directory.
+var File string
+
+// Owner is the developer the containers, volumes, and networks belong to when the docker
+// daemon is shared, it is empty when the daemon is not shared.
+var Owner string
+
+// Ports are the ports of the proxy for the environment, they are set from the config of
+// the environment before the commands run.
+var Ports = DefaultPorts
+
+// DefaultPorts are the ports of the proxy in the default environment.
+var DefaultPorts = PortSet{HTTP: 80, HTTPS: 443, API: 5000, Node: 3000, AltNode: 3001}
+
+// PortSet is the host ports the proxy container binds.
+type PortSet struct {
+	HTTP    int `json:"http,omitempty" yaml:"http,omitempty"`
+	HTTPS   int `json:"https,omitempty" yaml:"https,omitempty"`
+	API     int `json:"api,omitempty" yaml:"api,omitempty"`
+	Node    int `json:"node,omitempty" yaml:"node,omitempty"`
+	AltNode int `json:"alt_node,omitempty" yaml:"alt_node,omitempty"`
+}
+
+// IsZero returns true when none of the ports are set.
+func (p PortSet) IsZero() bool {
+	return p == PortSet{}
+}
+
+// Slot returns the ports for the nth named environment, the ports are offset from the
+// default ports so they do not require root and do not overlap with the other slots.
+func Slot(n int) PortSet {
+	return PortSet{
+		HTTP:    8000 + n,
+		HTTPS:   8400 + n,
+		API:     5000 + n,
+		Node:    3000 + n*2,
+		AltNode: 3001 + n*2,
+	}
+}
+
+// Port returns the port from the environment variable when it is set, otherwise the
+// port is returned. The environment variables (e.g. NITRO_HTTP_PORT) take precedence
+// over the ports of the environment.
+func Port(envVar string, port int) string {
+	if v, defined := os.LookupEnv(envVar); defined {
+		return v
+	}
+
+	return strconv.Itoa(port)
+}
+
+var validName = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// Validate returns an error when the name can not be used for the docker network, proxy
+// container, and config file of an environment.
+func Validate(name string) error {
+	if !validName.MatchString(name) {
+		return fmt.Errorf("the environment name %q must only contain lowercase letters, numbers, and hyphens", name)
+	}
+
+	return nil
+}
+
+// Resolve sets and returns the name of the environment. The name (from the --environment
+// flag) is used first, then NITRO_ENVIRONMENT, then the environment set by nitro env use
+// in the nitro directory, and then the default environment.
+func Resolve(dir, name string) (string, error) {
+	if name == "" {
+		name = os.Getenv(EnvVar)
+	}
+
+	if name == "" {
+		if b, err := ioutil.ReadFile(filepath.Join(dir, FileName)); err == nil {
+			name = strings.TrimSpace(string(b))
+		}
+	}
+
+	if name == "" {
+		name = Default
+	}
+
+	if err := Validate(name); err != nil {
+		return "", err
+	}
+
+	Name = name
+
+	return name, nil
+}
+
+// ResolveOwner sets and returns the owner of the resources. The owner is set with
+// NITRO_OWNER, or is the current user when NITRO_SHARED is set, otherwise the daemon is
+// not shared and the owner is empty.
+func ResolveOwner() (string, error) {
+	owner := os.Getenv(OwnerEnvVar)
+
+	if shared, _ := strconv.ParseBool(os.Getenv(SharedEnvVar)); owner == "" && shared {
+		u, err := user.Current()
+		if err != nil {
+			return "", fmt.Errorf("unable to get the current user for the shared docker daemon, %w", err)
+		}
+
+		// the username on windows includes the domain (e.g. DOMAIN\name)
+		owner = invalidOwner.ReplaceAllString(strings.ToLower(filepath.Base(strings.ReplaceAll(u.Username, `\`, "/"))), "-")
+		owner = strings.Trim(owner, "-")
+	}
+
+	if owner != "" && !validName.MatchString(owner) {
+		return "", fmt.Errorf("the owner %q must only contain lowercase letters, numbers, and hyphens", owner)
+	}
+
+	Owner = owner
+
+	return owner, nil
+}
+
+var invalidOwner = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// Use saves the name as the environment used when no environment is selected with the
+// flag or environment variable.
+func Use(dir, name string) error {
+	if err := Validate(name); err != nil {
+		return err
+	}
+
+	file := filepath.Join(dir, FileName)
+	if name == Default {
+		if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+
+		return nil
+	}
+
+	return ioutil.WriteFile(file, []byte(name+"\n"), 0644)
+}
+
+// IsDefault returns true when the default environment is used.
+func IsDefault() bool {
+	return Name == Default
+}
+
+// Args returns the arguments that select the environment when nitro runs itself, such as
+// when the hosts file is updated with sudo.
+func Args() []string {
+	var args []string
+	if !IsDefault() {
+		args = append(args, "--environment="+Name)
+	}
+
+	if File != "" {
+		args = append(args, "--config="+File)
+	}
+
+	return args
+}
+
+// NameFromFile returns the name of the environment for a config file that is not in the
+// nitro directory, the name of the file without the extension is used so the file gets
+// its own network, proxy, and containers (e.g. staging-clone.yaml is staging-clone).
+func NameFromFile(file string) string {
+	name := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+
+	return strings.Trim(invalidOwner.ReplaceAllString(strings.ToLower(name), "-"), "-")
+}
+
+// ConfigFile returns the name of the config file for the environment (e.g. nitro.yaml).
+func ConfigFile() string {
+	return Name + ".yaml"
+}
+
+// Network returns the name of the docker network for the environment (e.g. nitro-network).
+func Network() string {
+	return Name + "-network"
+}
+
+// Proxy returns the name of the proxy container for the environment (e.g. nitro-proxy).
+func Proxy() string {
+	return Name + "-proxy"
+}
+
+// Volume returns the name of the volume for the proxy container of the environment.
+func Volume() string {
+	return Name
+}
+
+// Prefix returns the prefix of the names of the containers and volumes of the environment
+// with the name (e.g. agency.), so the environments can use the same hostnames without
+// sharing containers and volumes. The default environment does not have a prefix so the
+// containers and volumes created before there were multiple environments are kept.
+func Prefix(name string) string {
+	if name == Default || name == "" {
+		return ""
+	}
+
+	return name + "."
+}
+
+// Label returns the value of the nitro label for the containers, networks, and volumes of
+// the environment. The default environment uses "true" so existing containers are kept.
+func Label() string {
+	if IsDefault() {
+		return "true"
+	}
+
+	return Name
+}
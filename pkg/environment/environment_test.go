@@ -0,0 +1,124 @@
+package environment
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestResolve(t *testing.T) {
+	defer func() { Name = Default }()
+
+	dir, err := ioutil.TempDir("", "nitro-environment")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// without a flag, variable, or file the default is used
+	if name, err := Resolve(dir, ""); err != nil || name != Default {
+		t.Errorf("expected the default environment, got %q, %v", name, err)
+	}
+
+	if err := Use(dir, "client-a"); err != nil {
+		t.Fatal(err)
+	}
+
+	if name, _ := Resolve(dir, ""); name != "client-a" {
+		t.Errorf("expected the environment from nitro env use, got %q", name)
+	}
+
+	os.Setenv(EnvVar, "client-b")
+	defer os.Unsetenv(EnvVar)
+
+	if name, _ := Resolve(dir, ""); name != "client-b" {
+		t.Errorf("expected the environment from %s, got %q", EnvVar, name)
+	}
+
+	if name, _ := Resolve(dir, "client-c"); name != "client-c" {
+		t.Errorf("expected the environment from the flag, got %q", name)
+	}
+
+	if Name != "client-c" {
+		t.Errorf("expected the name to be set, got %q", Name)
+	}
+
+	if _, err := Resolve(dir, "Client C"); err == nil {
+		t.Errorf("expected an error for an invalid name")
+	}
+
+	// using the default removes the file
+	os.Unsetenv(EnvVar)
+	if err := Use(dir, Default); err != nil {
+		t.Fatal(err)
+	}
+
+	if name, _ := Resolve(dir, ""); name != Default {
+		t.Errorf("expected the default environment after using it, got %q", name)
+	}
+}
+
+func TestNames(t *testing.T) {
+	defer func() { Name = Default }()
+
+	Name = Default
+	if Network() != "nitro-network" || Proxy() != "nitro-proxy" || Label() != "true" || ConfigFile() != "nitro.yaml" || Args() != nil {
+		t.Errorf("expected the default environment to keep the existing names, got %s %s %s %s %v", Network(), Proxy(), Label(), ConfigFile(), Args())
+	}
+
+	Name = "client-a"
+	if Network() != "client-a-network" || Proxy() != "client-a-proxy" || Label() != "client-a" || ConfigFile() != "client-a.yaml" {
+		t.Errorf("expected the names of the environment, got %s %s %s %s", Network(), Proxy(), Label(), ConfigFile())
+	}
+
+	if args := Args(); len(args) != 1 || args[0] != "--environment=client-a" {
+		t.Errorf("expected the environment flag, got %v", args)
+	}
+
+	File = "/projects/site/staging-clone.yaml"
+	defer func() { File = "" }()
+
+	if args := Args(); len(args) != 2 || args[1] != "--config="+File {
+		t.Errorf("expected the config flag, got %v", args)
+	}
+}
+
+func TestNameFromFile(t *testing.T) {
+	tests := map[string]string{
+		"./environments/staging-clone.yaml": "staging-clone",
+		"/tmp/Test_Env.yml":                 "test-env",
+		"nitro.yaml":                        "nitro",
+	}
+
+	for file, want := range tests {
+		if got := NameFromFile(file); got != want {
+			t.Errorf("NameFromFile(%q) = %q, want %q", file, got, want)
+		}
+	}
+}
+
+func TestSlot(t *testing.T) {
+	seen := make(map[int]bool)
+	for _, p := range []PortSet{DefaultPorts, Slot(1), Slot(2)} {
+		for _, port := range []int{p.HTTP, p.HTTPS, p.API, p.Node, p.AltNode} {
+			if seen[port] {
+				t.Errorf("expected the port %d to only be used once", port)
+			}
+
+			seen[port] = true
+		}
+	}
+}
+
+func TestPort(t *testing.T) {
+	if p := Port("NITRO_TEST_PORT", 8001); p != "8001" {
+		t.Errorf("expected the port of the environment, got %s", p)
+	}
+
+	os.Setenv("NITRO_TEST_PORT", "9000")
+	defer os.Unsetenv("NITRO_TEST_PORT")
+
+	if p := Port("NITRO_TEST_PORT", 8001); p != "9000" {
+		t.Errorf("expected the port from the environment variable, got %s", p)
+	}
+}
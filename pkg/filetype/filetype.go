@@ -2,7 +2,7 @@ package filetype
 
 import (
 	"fmt"
-	"io/ioutil"
+	"io"
 	"net/http"
 	"os"
 )
@@ -23,14 +23,21 @@ func Determine(file string) (string, error) {
 		return "", fmt.Errorf("file provided is a directory")
 	}
 
-	// read the contents
-	data, err := ioutil.ReadFile(file)
+	f, err := os.Open(file)
 	if err != nil {
 		return "", err
 	}
+	defer f.Close()
+
+	// only the start of the file is used to detect the type, so large files are not read
+	data := make([]byte, 512)
+	n, err := io.ReadFull(f, data)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return "", err
+	}
 
 	// detect the type
-	kind := http.DetectContentType(data)
+	kind := http.DetectContentType(data[:n])
 
 	switch kind {
 	case "text/plain; charset=utf-8":
@@ -0,0 +1,112 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+)
+
+var (
+	// Timeout is how long to wait for the containers to be healthy before giving up
+	Timeout = 3 * time.Minute
+
+	// pollInterval is how long to wait between each check of the containers
+	pollInterval = time.Second
+)
+
+// Wait waits for each of the containers, by name or ID, to be healthy. Containers without a
+// health check are ready once they are running. It returns an error as soon as a container
+// is unhealthy or has stopped, or when the containers are not healthy before the timeout.
+// The progress func is called with the containers that are not healthy yet after each check.
+func Wait(ctx context.Context, docker client.ContainerAPIClient, containers []string, timeout time.Duration, progress func(waiting []string)) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	waiting := containers
+	for {
+		var next []string
+		for _, c := range waiting {
+			info, err := docker.ContainerInspect(ctx, c)
+			if err != nil && ctx.Err() != nil {
+				return timedOut(timeout, waiting)
+			}
+
+			if err != nil {
+				return fmt.Errorf("unable to inspect the container %s, %w", c, err)
+			}
+
+			ready, err := Status(info)
+			if err != nil {
+				return fmt.Errorf("%s %w", c, err)
+			}
+
+			if !ready {
+				next = append(next, c)
+			}
+		}
+
+		if len(next) == 0 {
+			return nil
+		}
+
+		waiting = next
+
+		if progress != nil {
+			progress(waiting)
+		}
+
+		select {
+		case <-ctx.Done():
+			return timedOut(timeout, waiting)
+		case <-ticker.C:
+		}
+	}
+}
+
+func timedOut(timeout time.Duration, waiting []string) error {
+	return fmt.Errorf("timed out after %s waiting for %s to be healthy, run `nitro logs` to see why", timeout, strings.Join(waiting, ", "))
+}
+
+// Status returns true when the container is healthy, or running when it does not have a
+// health check. It returns an error when the container is unhealthy with the output of the
+// last health check, or when the container is not running.
+func Status(info types.ContainerJSON) (bool, error) {
+	if info.ContainerJSONBase == nil || info.State == nil {
+		return false, nil
+	}
+
+	if !info.State.Running && info.State.Status != "created" {
+		return false, fmt.Errorf("is %s, run `nitro logs` to see why", info.State.Status)
+	}
+
+	if info.State.Health == nil {
+		return info.State.Running, nil
+	}
+
+	switch info.State.Health.Status {
+	case types.Healthy:
+		return true, nil
+	case types.Unhealthy:
+		msg := "is unhealthy"
+		if n := len(info.State.Health.Log); n > 0 {
+			if out := strings.TrimSpace(info.State.Health.Log[n-1].Output); out != "" {
+				msg += ": " + out
+			}
+		}
+
+		return false, fmt.Errorf("%s", msg)
+	}
+
+	return false, nil
+}
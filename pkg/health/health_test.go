@@ -0,0 +1,116 @@
+package health
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+)
+
+func TestStatus(t *testing.T) {
+	inspect := func(state *types.ContainerState) types.ContainerJSON {
+		return types.ContainerJSON{ContainerJSONBase: &types.ContainerJSONBase{State: state}}
+	}
+
+	tests := []struct {
+		name      string
+		info      types.ContainerJSON
+		wantReady bool
+		wantErr   string
+	}{
+		{
+			name:      "healthy containers are ready",
+			info:      inspect(&types.ContainerState{Status: "running", Running: true, Health: &types.Health{Status: types.Healthy}}),
+			wantReady: true,
+		},
+		{
+			name: "starting containers are not ready",
+			info: inspect(&types.ContainerState{Status: "running", Running: true, Health: &types.Health{Status: types.Starting}}),
+		},
+		{
+			name:      "running containers without a health check are ready",
+			info:      inspect(&types.ContainerState{Status: "running", Running: true}),
+			wantReady: true,
+		},
+		{
+			name: "unhealthy containers return the output of the last check",
+			info: inspect(&types.ContainerState{Status: "running", Running: true, Health: &types.Health{
+				Status: types.Unhealthy,
+				Log:    []*types.HealthcheckResult{{Output: "first"}, {Output: "connection refused\n"}},
+			}}),
+			wantErr: "is unhealthy: connection refused",
+		},
+		{
+			name:    "stopped containers return an error",
+			info:    inspect(&types.ContainerState{Status: "exited"}),
+			wantErr: "is exited",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ready, err := Status(tt.info)
+			if ready != tt.wantReady {
+				t.Errorf("Status() ready = %v, want %v", ready, tt.wantReady)
+			}
+
+			if (err == nil && tt.wantErr != "") || (err != nil && !strings.HasPrefix(err.Error(), tt.wantErr)) || (err != nil && tt.wantErr == "") {
+				t.Errorf("Status() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWait(t *testing.T) {
+	pollInterval = 10 * time.Millisecond
+	defer func() { pollInterval = time.Second }()
+
+	// the database is healthy on the third check
+	spy := &mockClient{checks: map[string]int{}, healthyAfter: map[string]int{"proxy": 1, "mysql": 3}}
+
+	var progress [][]string
+	err := Wait(context.Background(), spy, []string{"proxy", "mysql"}, time.Second, func(waiting []string) {
+		progress = append(progress, waiting)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(progress) != 2 || strings.Join(progress[1], ",") != "mysql" {
+		t.Errorf("expected to wait for the database twice, got %v", progress)
+	}
+
+	if spy.checks["proxy"] != 1 {
+		t.Errorf("expected the healthy proxy to be checked once, got %d", spy.checks["proxy"])
+	}
+
+	// a container that never becomes healthy times out
+	spy = &mockClient{checks: map[string]int{}, healthyAfter: map[string]int{"mysql": 1000}}
+
+	err = Wait(context.Background(), spy, []string{"mysql"}, 50*time.Millisecond, nil)
+	if err == nil || !strings.Contains(err.Error(), "timed out") || !strings.Contains(err.Error(), "mysql") {
+		t.Errorf("expected a timeout for the database, got %v", err)
+	}
+}
+
+type mockClient struct {
+	client.ContainerAPIClient
+
+	checks       map[string]int
+	healthyAfter map[string]int
+}
+
+func (c *mockClient) ContainerInspect(ctx context.Context, container string) (types.ContainerJSON, error) {
+	c.checks[container]++
+
+	status := types.Starting
+	if c.checks[container] >= c.healthyAfter[container] {
+		status = types.Healthy
+	}
+
+	state := &types.ContainerState{Status: "running", Running: true, Health: &types.Health{Status: status}}
+
+	return types.ContainerJSON{ContainerJSONBase: &types.ContainerJSONBase{State: state}}, nil
+}
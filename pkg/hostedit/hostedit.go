@@ -4,15 +4,67 @@ import (
 	"fmt"
 	"io/ioutil"
 	"strings"
-)
 
-const (
-	startText = "# <nitro>"
-	endText   = "# </nitro>"
+	"github.com/craftcms/nitro/pkg/environment"
 )
 
+// startText and endText return the comments around the entries of the environment (e.g.
+// # <nitro> and # </nitro>), so the environments do not replace each others entries.
+func startText() string {
+	return "# <" + environment.Name + ">"
+}
+
+func endText() string {
+	return "# </" + environment.Name + ">"
+}
+
 var ErrNotNitroEntries = fmt.Errorf("there are no nitro entries to remove from the hosts file")
 
+// File returns the path to the hosts file for the runtime.GOOS.
+func File(system string) string {
+	if system == "windows" {
+		return `C:\Windows\System32\Drivers\etc\hosts`
+	}
+
+	return "/etc/hosts"
+}
+
+// Changes compares the content of the hosts file before and after an update and returns
+// the lines that are removed, prefixed with "- ", and added, prefixed with "+ ".
+func Changes(before, after string) []string {
+	count := func(content string) map[string]int {
+		lines := make(map[string]int)
+		for _, l := range strings.Split(content, "\n") {
+			lines[l]++
+		}
+
+		return lines
+	}
+
+	old, updated := count(before), count(after)
+
+	var changes []string
+	for _, l := range strings.Split(before, "\n") {
+		if updated[l] > 0 {
+			updated[l]--
+			continue
+		}
+
+		changes = append(changes, "- "+l)
+	}
+
+	for _, l := range strings.Split(after, "\n") {
+		if old[l] > 0 {
+			old[l]--
+			continue
+		}
+
+		changes = append(changes, "+ "+l)
+	}
+
+	return changes
+}
+
 // Update takes a file, reads the content and updates or appends
 // the addr and hosts for the sites.
 func Update(file, addr string, hosts ...string) (content string, err error) {
@@ -29,13 +81,13 @@ func Update(file, addr string, hosts ...string) (content string, err error) {
 	var index int
 	for l, t := range lines {
 		// look for the beginning text
-		if strings.Contains(t, startText) {
+		if strings.Contains(t, startText()) {
 			// the next line is the empty line
 			index = l + 1
 		}
 
 		// look for the end text
-		if strings.Contains(t, endText) {
+		if strings.Contains(t, endText()) {
 			// we want the previous line
 			index = l - 1
 		}
@@ -44,9 +96,9 @@ func Update(file, addr string, hosts ...string) (content string, err error) {
 	switch index {
 	// if there is not a comment section, we need to create one
 	case 0:
-		lines = append(lines, startText)
+		lines = append(lines, startText())
 		lines = append(lines, fmt.Sprintf("%s\t%s", addr, strings.Join(hosts, " ")))
-		lines = append(lines, endText+"\n")
+		lines = append(lines, endText()+"\n")
 	default:
 		// replace the line between the start and end text with the contents of the address and hosts
 		lines[index] = fmt.Sprintf("%s\t%s", addr, strings.Join(hosts, " "))
@@ -118,14 +170,14 @@ func indexes(content []byte) (start, middle, end int) {
 	var s, m, e int
 	for l, t := range lines {
 		// look for the beginning text
-		if strings.Contains(t, startText) {
+		if strings.Contains(t, startText()) {
 			s = l
 			// the next line is the empty line
 			m = l + 1
 		}
 
 		// look for the end text
-		if strings.Contains(t, endText) {
+		if strings.Contains(t, endText()) {
 			// we want the previous line
 			e = l
 			m = l - 1
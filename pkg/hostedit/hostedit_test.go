@@ -2,6 +2,7 @@ package hostedit
 
 import (
 	"path/filepath"
+	"reflect"
 	"testing"
 )
 
@@ -246,3 +247,18 @@ func TestRemove(t *testing.T) {
 		})
 	}
 }
+
+func TestChanges(t *testing.T) {
+	before := "127.0.0.1\tlocalhost\n# <nitro>\n127.0.0.1\tone two\n# </nitro>\n"
+	after := "127.0.0.1\tlocalhost\n# <nitro>\n127.0.0.1\tone two three\n# </nitro>\n"
+
+	got := Changes(before, after)
+	want := []string{"- 127.0.0.1\tone two", "+ 127.0.0.1\tone two three"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Changes() = %q, want %q", got, want)
+	}
+
+	if got := Changes(before, before); len(got) != 0 {
+		t.Errorf("expected no changes, got %q", got)
+	}
+}
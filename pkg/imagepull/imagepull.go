@@ -0,0 +1,113 @@
+package imagepull
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/jsonmessage"
+	"github.com/docker/go-units"
+
+	"github.com/craftcms/nitro/pkg/terminal"
+)
+
+// QuietEnvVar is the environment variable that hides the progress of the pulls, the progress
+// is also hidden when the CI environment variable is set.
+const QuietEnvVar = "NITRO_QUIET_PULL"
+
+// Quiet hides the progress of the pulls, the output of the pull is still read so the pull
+// completes and the errors are returned.
+var Quiet bool
+
+// Pull pulls the image and shows each layer as it is downloaded. The progress is shown with
+// the output after the pending message of the caller, and ends with a pending message for
+// the image so the caller can finish it with Done or Warning. Nothing is shown when the
+// image is up to date, in quiet mode, or when the output is nil.
+func Pull(ctx context.Context, docker client.ImageAPIClient, image string, output terminal.Outputer) error {
+	rdr, err := docker.ImagePull(ctx, image, types.ImagePullOptions{All: false})
+	if err != nil {
+		return fmt.Errorf("unable to pull the image %s, %w", image, err)
+	}
+	defer rdr.Close()
+
+	if quiet() {
+		output = nil
+	}
+
+	return Read(rdr, image, output)
+}
+
+// Read reads the JSON stream of an image pull and shows each layer that is downloaded with
+// the output, a nil output does not show the progress. It returns the error from the stream,
+// which Docker sends after the pull has started (e.g. when the image does not exist).
+func Read(rdr io.Reader, image string, output terminal.Outputer) error {
+	// the size of each layer is known once the download starts
+	sizes := make(map[string]int64)
+	var downloaded int
+
+	dec := json.NewDecoder(rdr)
+	for {
+		var msg jsonmessage.JSONMessage
+		if err := dec.Decode(&msg); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			return fmt.Errorf("unable to read the output from pulling the image %s, %w", image, err)
+		}
+
+		if msg.Error != nil {
+			return fmt.Errorf("unable to pull the image %s, %s", image, msg.Error.Message)
+		}
+
+		switch msg.Status {
+		case "Downloading":
+			if msg.Progress != nil && msg.Progress.Total > 0 {
+				sizes[msg.ID] = msg.Progress.Total
+			}
+		case "Pull complete":
+			if output == nil {
+				continue
+			}
+
+			// end the pending message of the caller before the first layer
+			if downloaded == 0 {
+				output.Info("")
+			}
+
+			downloaded++
+
+			line := fmt.Sprintf("    %s downloaded", msg.ID)
+			if size, ok := sizes[msg.ID]; ok {
+				line += " (" + units.HumanSize(float64(size)) + ")"
+			}
+
+			output.Info(line)
+		}
+	}
+
+	if downloaded > 0 {
+		output.Pending("pulled", image)
+	}
+
+	return nil
+}
+
+// quiet returns true when the progress is hidden with the Quiet variable, the environment
+// variables, or when the output is JSON.
+func quiet() bool {
+	if Quiet || terminal.JSON {
+		return true
+	}
+
+	if _, ok := os.LookupEnv(QuietEnvVar); ok {
+		return true
+	}
+
+	return os.Getenv("CI") != ""
+}
@@ -0,0 +1,90 @@
+package imagepull
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+
+	"github.com/craftcms/nitro/pkg/terminal"
+)
+
+const stream = `{"status":"Pulling from craftcms/nginx","id":"8.0-dev"}
+{"status":"Already exists","progressDetail":{},"id":"a1b2c3d4e5f6"}
+{"status":"Pulling fs layer","progressDetail":{},"id":"b2c3d4e5f6a1"}
+{"status":"Downloading","progressDetail":{"current":1024,"total":2048000},"progress":"[>   ]","id":"b2c3d4e5f6a1"}
+{"status":"Download complete","progressDetail":{},"id":"b2c3d4e5f6a1"}
+{"status":"Pull complete","progressDetail":{},"id":"b2c3d4e5f6a1"}
+{"status":"Digest: sha256:abc"}
+{"status":"Status: Downloaded newer image for craftcms/nginx:8.0-dev"}
+`
+
+func TestRead(t *testing.T) {
+	spy := &spyOutputer{}
+
+	if err := Read(strings.NewReader(stream), "craftcms/nginx:8.0-dev", spy); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"info ", "info     b2c3d4e5f6a1 downloaded (2.048MB)", "pending pulled craftcms/nginx:8.0-dev"}
+	if strings.Join(spy.lines, "\n") != strings.Join(want, "\n") {
+		t.Errorf("expected the downloaded layer to be shown, got %q", spy.lines)
+	}
+
+	// the images that are up to date do not show any output
+	spy = &spyOutputer{}
+	if err := Read(strings.NewReader(`{"status":"Status: Image is up to date for craftcms/nginx:8.0-dev"}`), "craftcms/nginx:8.0-dev", spy); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(spy.lines) != 0 {
+		t.Errorf("expected no output, got %q", spy.lines)
+	}
+}
+
+func TestRead_Error(t *testing.T) {
+	err := Read(strings.NewReader(`{"errorDetail":{"message":"manifest unknown"},"error":"manifest unknown"}`), "craftcms/nginx:0.1-dev", nil)
+	if err == nil || !strings.Contains(err.Error(), "manifest unknown") {
+		t.Errorf("expected the error from the stream, got %v", err)
+	}
+}
+
+func TestPull_Quiet(t *testing.T) {
+	Quiet = true
+	defer func() { Quiet = false }()
+
+	spy := &spyOutputer{}
+	if err := Pull(context.Background(), &mockClient{}, "craftcms/nginx:8.0-dev", spy); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(spy.lines) != 0 {
+		t.Errorf("expected no output in quiet mode, got %q", spy.lines)
+	}
+}
+
+type mockClient struct {
+	client.ImageAPIClient
+}
+
+func (c *mockClient) ImagePull(ctx context.Context, ref string, options types.ImagePullOptions) (io.ReadCloser, error) {
+	return ioutil.NopCloser(strings.NewReader(stream)), nil
+}
+
+type spyOutputer struct {
+	terminal.Outputer
+
+	lines []string
+}
+
+func (spy *spyOutputer) Info(s ...string) {
+	spy.lines = append(spy.lines, "info "+strings.Join(s, " "))
+}
+
+func (spy *spyOutputer) Pending(s ...string) {
+	spy.lines = append(spy.lines, "pending "+strings.Join(s, " "))
+}
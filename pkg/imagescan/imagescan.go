@@ -0,0 +1,104 @@
+package imagescan
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+const (
+	// Image is the scanner image used to check images for vulnerabilities
+	Image = "docker.io/aquasec/trivy:latest"
+
+	// CacheVolume is the name of the volume used to store the vulnerability database between scans
+	CacheVolume = "nitro_trivy_cache"
+)
+
+// Vulnerability represents a single CVE that was found in an image.
+type Vulnerability struct {
+	ID               string `json:"VulnerabilityID"`
+	Package          string `json:"PkgName"`
+	InstalledVersion string `json:"InstalledVersion"`
+	FixedVersion     string `json:"FixedVersion"`
+	Severity         string `json:"Severity"`
+	Title            string `json:"Title"`
+}
+
+// Report is the result of scanning a single image.
+type Report struct {
+	Image           string
+	Vulnerabilities []Vulnerability
+}
+
+// Fixable returns the number of vulnerabilities that have a fixed version available.
+func (r Report) Fixable() int {
+	fixable := 0
+	for _, v := range r.Vulnerabilities {
+		if v.FixedVersion != "" {
+			fixable++
+		}
+	}
+
+	return fixable
+}
+
+// Commands returns the scanner commands for an image limited to the provided
+// severities (e.g. CRITICAL,HIGH).
+func Commands(image string, severities []string) []string {
+	if len(severities) == 0 {
+		severities = []string{"CRITICAL"}
+	}
+
+	return []string{
+		"image",
+		"--quiet",
+		"--format", "json",
+		"--severity", strings.ToUpper(strings.Join(severities, ",")),
+		image,
+	}
+}
+
+// Parse takes the image name and the JSON output from the scanner
+// and returns a report for the image. Duplicate vulnerabilities
+// across targets (e.g. the OS and language packages) are removed.
+// Empty output returns an error, as the scanner did not complete.
+func Parse(image string, r io.Reader) (Report, error) {
+	var output struct {
+		Results []struct {
+			Target          string          `json:"Target"`
+			Vulnerabilities []Vulnerability `json:"Vulnerabilities"`
+		} `json:"Results"`
+	}
+
+	if err := json.NewDecoder(r).Decode(&output); err != nil {
+		// the scanner always returns results, so empty output means the scan failed
+		if err == io.EOF {
+			return Report{}, fmt.Errorf("the scanner did not return results for %s", image)
+		}
+
+		return Report{}, fmt.Errorf("unable to parse the scan results for %s, %w", image, err)
+	}
+
+	report := Report{Image: image}
+	seen := map[string]bool{}
+	for _, result := range output.Results {
+		for _, v := range result.Vulnerabilities {
+			key := v.ID + v.Package
+			if seen[key] {
+				continue
+			}
+
+			seen[key] = true
+			report.Vulnerabilities = append(report.Vulnerabilities, v)
+		}
+	}
+
+	// sort by the id so the output is consistent
+	sort.SliceStable(report.Vulnerabilities, func(i, j int) bool {
+		return report.Vulnerabilities[i].ID < report.Vulnerabilities[j].ID
+	})
+
+	return report, nil
+}
@@ -0,0 +1,100 @@
+package imagescan
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	type args struct {
+		image  string
+		output string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    Report
+		wantErr bool
+	}{
+		{
+			name: "vulnerabilities are returned sorted and without duplicates",
+			args: args{
+				image: "docker.io/craftcms/nginx:7.4-dev",
+				output: `{
+  "Results": [
+    {
+      "Target": "docker.io/craftcms/nginx:7.4-dev (alpine 3.12.3)",
+      "Vulnerabilities": [
+        {"VulnerabilityID": "CVE-2021-2", "PkgName": "openssl", "InstalledVersion": "1.1.1i-r0", "FixedVersion": "1.1.1j-r0", "Severity": "CRITICAL"},
+        {"VulnerabilityID": "CVE-2021-1", "PkgName": "curl", "InstalledVersion": "7.69.1-r3", "Severity": "CRITICAL"}
+      ]
+    },
+    {
+      "Target": "composer.lock",
+      "Vulnerabilities": [
+        {"VulnerabilityID": "CVE-2021-2", "PkgName": "openssl", "InstalledVersion": "1.1.1i-r0", "FixedVersion": "1.1.1j-r0", "Severity": "CRITICAL"}
+      ]
+    }
+  ]
+}`,
+			},
+			want: Report{
+				Image: "docker.io/craftcms/nginx:7.4-dev",
+				Vulnerabilities: []Vulnerability{
+					{ID: "CVE-2021-1", Package: "curl", InstalledVersion: "7.69.1-r3", Severity: "CRITICAL"},
+					{ID: "CVE-2021-2", Package: "openssl", InstalledVersion: "1.1.1i-r0", FixedVersion: "1.1.1j-r0", Severity: "CRITICAL"},
+				},
+			},
+		},
+		{
+			name: "images without vulnerabilities return an empty report",
+			args: args{
+				image:  "docker.io/library/redis:latest",
+				output: `{"Results": [{"Target": "redis (debian 10.8)"}]}`,
+			},
+			want: Report{Image: "docker.io/library/redis:latest"},
+		},
+		{
+			name: "invalid output returns an error",
+			args: args{
+				image:  "docker.io/library/redis:latest",
+				output: `not json`,
+			},
+			wantErr: true,
+		},
+		{
+			name: "empty output returns an error",
+			args: args{
+				image:  "docker.io/library/redis:latest",
+				output: ``,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.args.image, strings.NewReader(tt.args.output))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) && !tt.wantErr {
+				t.Errorf("Parse() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReport_Fixable(t *testing.T) {
+	r := Report{
+		Vulnerabilities: []Vulnerability{
+			{ID: "CVE-2021-1"},
+			{ID: "CVE-2021-2", FixedVersion: "1.1.1j-r0"},
+		},
+	}
+
+	if got := r.Fixable(); got != 1 {
+		t.Errorf("Fixable() = %v, want %v", got, 1)
+	}
+}
@@ -0,0 +1,208 @@
+package jobs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+
+	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/containerexec"
+)
+
+const (
+	// FileName is the name of the file in the nitro directory that tracks the jobs
+	FileName = "jobs.json"
+
+	// Running is the status of a job that has not completed
+	Running = "running"
+
+	// Completed is the status of a job that exited without an error
+	Completed = "completed"
+
+	// Failed is the status of a job that exited with an error
+	Failed = "failed"
+
+	// Unknown is the status of a job when the container no longer exists
+	Unknown = "unknown"
+
+	// dir is the directory in the container that stores the output and exit code of the jobs
+	dir = "/tmp/nitro-jobs"
+)
+
+var (
+	// ErrNotFound is returned when there is no job with the ID
+	ErrNotFound = fmt.Errorf("unable to find the job")
+)
+
+// Job is a command that runs in the background in a container. The output and exit
+// code of the command are written to files in the container, so the status of the
+// job can be checked after the terminal that started it is closed.
+type Job struct {
+	ID          string    `json:"id"`
+	Description string    `json:"description"`
+	ContainerID string    `json:"container_id"`
+	Container   string    `json:"container"`
+	Started     time.Time `json:"started"`
+}
+
+// LogFile returns the path to the output of the job in the container.
+func (j *Job) LogFile() string {
+	return dir + "/" + j.ID + ".log"
+}
+
+// ExitFile returns the path to the exit code of the job in the container.
+func (j *Job) ExitFile() string {
+	return dir + "/" + j.ID + ".exit"
+}
+
+// Script returns the shell script that runs the commands for the job. The output of
+// the commands is written to the log file. The commands stop at the first command that
+// fails and its exit code is written to the exit file, so the job fails when any of the
+// commands fail. The cleanup commands run after the exit code is captured.
+func (j *Job) Script(cmds []string, cleanup ...string) string {
+	script := fmt.Sprintf("mkdir -p %s; { %s; } > %s 2>&1; code=$?;", dir, strings.Join(cmds, " && "), j.LogFile())
+	for _, c := range cleanup {
+		script += " " + c + ";"
+	}
+
+	return script + fmt.Sprintf(" echo $code > %s", j.ExitFile())
+}
+
+// New returns a job with a random ID for the container.
+func New(containerID, container, description string) (*Job, error) {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return nil, fmt.Errorf("unable to generate the job id, %w", err)
+	}
+
+	return &Job{
+		ID:          hex.EncodeToString(b),
+		Description: description,
+		ContainerID: containerID,
+		Container:   container,
+		Started:     time.Now(),
+	}, nil
+}
+
+// Start runs the commands for the job in the container without waiting for them to
+// complete and adds the job to the jobs file.
+func Start(ctx context.Context, docker client.ContainerAPIClient, home string, job *Job, cmds []string, cleanup ...string) error {
+	exec, err := docker.ContainerExecCreate(ctx, job.ContainerID, types.ExecConfig{
+		Detach: true,
+		Cmd:    []string{"sh", "-c", job.Script(cmds, cleanup...)},
+	})
+	if err != nil {
+		return fmt.Errorf("unable to create the job, %w", err)
+	}
+
+	if err := docker.ContainerExecStart(ctx, exec.ID, types.ExecStartCheck{Detach: true}); err != nil {
+		return fmt.Errorf("unable to start the job, %w", err)
+	}
+
+	jobs, err := Load(home)
+	if err != nil {
+		return err
+	}
+
+	return Save(home, append(jobs, *job))
+}
+
+// Status returns the status of the job by checking for the exit file in the container.
+func Status(ctx context.Context, docker client.ContainerAPIClient, job Job) string {
+	if _, err := docker.ContainerInspect(ctx, job.ContainerID); err != nil {
+		return Unknown
+	}
+
+	out, err := containerexec.Run(ctx, docker, job.ContainerID, []string{"cat", job.ExitFile()})
+	if err != nil {
+		// the exit file is written when the job completes
+		return Running
+	}
+
+	if code, err := strconv.Atoi(out); err != nil || code != 0 {
+		return Failed
+	}
+
+	return Completed
+}
+
+// Logs returns the output of the job from the container.
+func Logs(ctx context.Context, docker client.ContainerAPIClient, job Job) (string, error) {
+	out, err := containerexec.Run(ctx, docker, job.ContainerID, []string{"cat", job.LogFile()})
+	if err != nil {
+		return "", fmt.Errorf("unable to read the output of job %s, %w", job.ID, err)
+	}
+
+	return out, nil
+}
+
+// Find returns the job with the ID from the jobs file.
+func Find(home, id string) (*Job, error) {
+	jobs, err := Load(home)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, j := range jobs {
+		if j.ID == id {
+			return &j, nil
+		}
+	}
+
+	return nil, ErrNotFound
+}
+
+// Load returns the jobs from the jobs file in the nitro directory.
+func Load(home string) ([]Job, error) {
+	b, err := ioutil.ReadFile(filepath.Join(home, config.DirectoryName, FileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the jobs file, %w", err)
+	}
+
+	var jobs []Job
+	if err := json.Unmarshal(b, &jobs); err != nil {
+		return nil, fmt.Errorf("unable to parse the jobs file, %w", err)
+	}
+
+	return jobs, nil
+}
+
+// Save writes the jobs to the jobs file in the nitro directory.
+func Save(home string, jobs []Job) error {
+	b, err := json.MarshalIndent(jobs, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Join(home, config.DirectoryName)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(filepath.Join(dir, FileName), b, 0644)
+}
+
+// Command returns the arguments as a shell command, each argument is quoted.
+func Command(args ...string) string {
+	quoted := make([]string, len(args))
+	for i, a := range args {
+		quoted[i] = "'" + strings.ReplaceAll(a, "'", `'"'"'`) + "'"
+	}
+
+	return strings.Join(quoted, " ")
+}
@@ -0,0 +1,62 @@
+package jobs
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestSaveLoadFind(t *testing.T) {
+	home, err := ioutil.TempDir("", "nitro-jobs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(home)
+
+	// no jobs file returns no jobs
+	jobs, err := Load(home)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs) != 0 {
+		t.Errorf("expected no jobs, got %d", len(jobs))
+	}
+
+	job, err := New("container-id", "mysql-8.0-3306.database.nitro", "import dump.sql into craft")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Save(home, []Job{*job}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Find(home, job.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Description != job.Description || got.ContainerID != job.ContainerID {
+		t.Errorf("expected the job to be %v, got %v", job, got)
+	}
+
+	if _, err := Find(home, "missing"); err != ErrNotFound {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestScript(t *testing.T) {
+	job := Job{ID: "abc123"}
+
+	got := job.Script([]string{Command("mysql", "-e", "source /tmp/it's.sql")}, "rm -f /tmp/dump.sql")
+	want := `mkdir -p /tmp/nitro-jobs; { 'mysql' '-e' 'source /tmp/it'"'"'s.sql'; } > /tmp/nitro-jobs/abc123.log 2>&1; code=$?; rm -f /tmp/dump.sql; echo $code > /tmp/nitro-jobs/abc123.exit`
+	if got != want {
+		t.Errorf("Script() =\n%s\nwant\n%s", got, want)
+	}
+
+	// the commands stop at the first command that fails so its exit code is captured
+	got = job.Script([]string{Command("createdb", "craft"), Command("psql", "--file=/tmp/dump.sql")})
+	want = `mkdir -p /tmp/nitro-jobs; { 'createdb' 'craft' && 'psql' '--file=/tmp/dump.sql'; } > /tmp/nitro-jobs/abc123.log 2>&1; code=$?; echo $code > /tmp/nitro-jobs/abc123.exit`
+	if got != want {
+		t.Errorf("Script() =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,56 @@
+package limits
+
+import (
+	"fmt"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+
+	"github.com/craftcms/nitro/pkg/config"
+)
+
+// New returns the resources for a container with the limits, the container is not
+// limited when the limits are not set.
+func New(r config.Resources) container.Resources {
+	return container.Resources{NanoCPUs: r.NanoCPUs(), Memory: r.MemoryBytes()}
+}
+
+// Changed returns true when the container was created with different limits.
+func Changed(r config.Resources, details types.ContainerJSON) bool {
+	if details.ContainerJSONBase == nil || details.HostConfig == nil {
+		return r.NanoCPUs() != 0 || r.MemoryBytes() != 0
+	}
+
+	return details.HostConfig.NanoCPUs != r.NanoCPUs() || details.HostConfig.Memory != r.MemoryBytes()
+}
+
+// String returns the limits of the container to show in the changes.
+func String(details types.ContainerJSON) string {
+	if details.ContainerJSONBase == nil || details.HostConfig == nil {
+		return "no limits"
+	}
+
+	return fmt.Sprintf("cpus %v memory %d", float64(details.HostConfig.NanoCPUs)/1e9, details.HostConfig.Memory)
+}
+
+// Removed returns true when the container has a limit that is no longer set, docker can
+// change the limits of a container but cannot remove them.
+func Removed(r config.Resources, details types.ContainerJSON) bool {
+	if details.ContainerJSONBase == nil || details.HostConfig == nil {
+		return false
+	}
+
+	return (details.HostConfig.NanoCPUs != 0 && r.NanoCPUs() == 0) || (details.HostConfig.Memory != 0 && r.MemoryBytes() == 0)
+}
+
+// Update returns the config to change the limits of a running container. Swap is not
+// limited so the memory can be raised above the swap limit docker set when the container
+// was limited before.
+func Update(r config.Resources) container.UpdateConfig {
+	resources := New(r)
+	if resources.Memory != 0 {
+		resources.MemorySwap = -1
+	}
+
+	return container.UpdateConfig{Resources: resources}
+}
@@ -0,0 +1,51 @@
+package limits
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+
+	"github.com/craftcms/nitro/pkg/config"
+)
+
+func TestNew(t *testing.T) {
+	got := New(config.Resources{CPUs: 1.5, Memory: "512m"})
+	if got.NanoCPUs != 1500000000 || got.Memory != 512*1024*1024 {
+		t.Errorf("New() = %v, %v, want 1500000000 and %d", got.NanoCPUs, got.Memory, 512*1024*1024)
+	}
+
+	if got := New(config.Resources{}); got.NanoCPUs != 0 || got.Memory != 0 {
+		t.Errorf("expected no limits, got %v, %v", got.NanoCPUs, got.Memory)
+	}
+}
+
+func TestChanged(t *testing.T) {
+	limited := types.ContainerJSON{ContainerJSONBase: &types.ContainerJSONBase{HostConfig: &container.HostConfig{Resources: container.Resources{NanoCPUs: 1000000000, Memory: 1024 * 1024 * 1024}}}}
+	unlimited := types.ContainerJSON{ContainerJSONBase: &types.ContainerJSONBase{HostConfig: &container.HostConfig{}}}
+
+	tests := []struct {
+		name        string
+		resources   config.Resources
+		details     types.ContainerJSON
+		wantChanged bool
+		wantRemoved bool
+	}{
+		{name: "matching limits are not changed", resources: config.Resources{CPUs: 1, Memory: "1g"}, details: limited},
+		{name: "containers without limits are not changed", resources: config.Resources{}, details: unlimited},
+		{name: "new limits are changed", resources: config.Resources{Memory: "512m"}, details: unlimited, wantChanged: true},
+		{name: "different limits are changed", resources: config.Resources{CPUs: 2, Memory: "1g"}, details: limited, wantChanged: true},
+		{name: "removed limits are changed and removed", resources: config.Resources{CPUs: 1}, details: limited, wantChanged: true, wantRemoved: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Changed(tt.resources, tt.details); got != tt.wantChanged {
+				t.Errorf("Changed() = %v, want %v", got, tt.wantChanged)
+			}
+
+			if got := Removed(tt.resources, tt.details); got != tt.wantRemoved {
+				t.Errorf("Removed() = %v, want %v", got, tt.wantRemoved)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package logconfig
+
+import (
+	"reflect"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+
+	"github.com/craftcms/nitro/pkg/config"
+)
+
+// New returns the log config for a container with the log options, containers use the
+// default log driver of docker when the options are not set.
+func New(l config.Logs) container.LogConfig {
+	if !l.IsSet() {
+		return container.LogConfig{}
+	}
+
+	return container.LogConfig{Type: l.Type(), Config: l.Options()}
+}
+
+// Changed returns true when the log options are set and the container was created with
+// a different log driver or options. Containers created before the options were set use
+// the defaults of docker, so the container is only changed when the options are set.
+func Changed(l config.Logs, details types.ContainerJSON) bool {
+	if !l.IsSet() || details.HostConfig == nil {
+		return false
+	}
+
+	actual := details.HostConfig.LogConfig
+	if actual.Type != l.Type() {
+		return true
+	}
+
+	opts := map[string]string{}
+	for _, k := range []string{"max-size", "max-file"} {
+		if v, ok := actual.Config[k]; ok {
+			opts[k] = v
+		}
+	}
+
+	return !reflect.DeepEqual(opts, l.Options())
+}
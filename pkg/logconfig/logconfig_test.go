@@ -0,0 +1,65 @@
+package logconfig
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+
+	"github.com/craftcms/nitro/pkg/config"
+)
+
+func TestNew(t *testing.T) {
+	if got := New(config.Logs{}); !reflect.DeepEqual(got, container.LogConfig{}) {
+		t.Errorf("expected the default log config without options, got %v", got)
+	}
+
+	got := New(config.Logs{MaxSize: "10m", MaxFile: 3})
+	want := container.LogConfig{Type: "json-file", Config: map[string]string{"max-size": "10m", "max-file": "3"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("New() = %v, want %v", got, want)
+	}
+}
+
+func TestChanged(t *testing.T) {
+	details := func(l container.LogConfig) types.ContainerJSON {
+		return types.ContainerJSON{ContainerJSONBase: &types.ContainerJSONBase{HostConfig: &container.HostConfig{LogConfig: l}}}
+	}
+
+	tests := []struct {
+		name    string
+		logs    config.Logs
+		details types.ContainerJSON
+		want    bool
+	}{
+		{
+			name:    "containers are not changed without options",
+			details: details(container.LogConfig{Type: "json-file", Config: map[string]string{"max-size": "10m"}}),
+		},
+		{
+			name:    "matching options are not changed",
+			logs:    config.Logs{MaxSize: "10m", MaxFile: 3},
+			details: details(container.LogConfig{Type: "json-file", Config: map[string]string{"max-size": "10m", "max-file": "3", "mode": "blocking"}}),
+		},
+		{
+			name:    "containers with the default options are changed",
+			logs:    config.Logs{MaxSize: "10m"},
+			details: details(container.LogConfig{Type: "json-file"}),
+			want:    true,
+		},
+		{
+			name:    "a different driver is changed",
+			logs:    config.Logs{Driver: "local", MaxSize: "10m"},
+			details: details(container.LogConfig{Type: "json-file", Config: map[string]string{"max-size": "10m"}}),
+			want:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Changed(tt.logs, tt.details); got != tt.want {
+				t.Errorf("Changed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/craftcms/nitro/pkg/dashboard"
+	"github.com/craftcms/nitro/protob"
+)
+
+// Path is the path the metrics are served on by the API.
+const Path = "/metrics"
+
+// caddyPrefix is the prefix of the request metrics from caddy that are included.
+const caddyPrefix = "caddy_http_"
+
+// Metrics is an http.Handler that writes the metrics of the environment in the Prometheus
+// text format. The state of the containers and the apply durations are reported by the
+// CLI after each apply, the items on the dashboard are dialed when the metrics are
+// requested, and the request counts are read from the metrics of caddy.
+type Metrics struct {
+	// Caddy is the URL of the caddy metrics, they are not included when it is empty
+	Caddy string
+	HTTP  *http.Client
+
+	// Dashboard has the items that are dialed to check they are up
+	Dashboard *dashboard.Dashboard
+
+	mu         sync.RWMutex
+	applies    int64
+	duration   float64
+	last       time.Time
+	containers []*protob.ContainerState
+}
+
+// Record adds the duration of an apply and replaces the state of the containers.
+func (m *Metrics) Record(report *protob.ReportRequest) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.applies++
+	m.duration += report.GetDuration()
+	m.last = time.Now()
+	m.containers = report.GetContainers()
+}
+
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+
+	m.write(w)
+
+	if m.Caddy != "" {
+		if err := m.caddy(w); err != nil {
+			fmt.Fprintf(w, "# unable to read the proxy metrics: %s\n", err)
+		}
+	}
+}
+
+// write writes the metrics that are collected by nitro.
+func (m *Metrics) write(w io.Writer) {
+	if m.Dashboard != nil {
+		items := m.Dashboard.Items()
+		up := dashboard.Up(items)
+
+		header(w, "nitro_up", "gauge", "Whether the site, service, database, or container accepts connections.")
+		for i, it := range items {
+			if it.GetAddress() == "" {
+				continue
+			}
+
+			fmt.Fprintf(w, "nitro_up{kind=%s,name=%s} %d\n", quote(it.GetKind()), quote(it.GetName()), value(up[i]))
+		}
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	containers := make([]*protob.ContainerState, len(m.containers))
+	copy(containers, m.containers)
+	sort.Slice(containers, func(i, j int) bool {
+		return containers[i].GetName() < containers[j].GetName()
+	})
+
+	header(w, "nitro_container_running", "gauge", "Whether the container was running at the last apply.")
+	for _, c := range containers {
+		fmt.Fprintf(w, "nitro_container_running{name=%s,state=%s} %d\n", quote(c.GetName()), quote(c.GetState()), value(c.GetState() == "running"))
+	}
+
+	header(w, "nitro_container_restarts", "gauge", "The number of times docker restarted the container at the last apply.")
+	for _, c := range containers {
+		fmt.Fprintf(w, "nitro_container_restarts{name=%s} %d\n", quote(c.GetName()), c.GetRestarts())
+	}
+
+	header(w, "nitro_apply_duration_seconds", "summary", "The time taken by nitro apply.")
+	fmt.Fprintf(w, "nitro_apply_duration_seconds_sum %g\n", m.duration)
+	fmt.Fprintf(w, "nitro_apply_duration_seconds_count %d\n", m.applies)
+
+	if !m.last.IsZero() {
+		header(w, "nitro_apply_last_timestamp_seconds", "gauge", "The unix time of the last apply.")
+		fmt.Fprintf(w, "nitro_apply_last_timestamp_seconds %d\n", m.last.Unix())
+	}
+}
+
+// caddy copies the request metrics from caddy, the other metrics of caddy are about the
+// go runtime of caddy and are left out.
+func (m *Metrics) caddy(w io.Writer) error {
+	client := m.HTTP
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Get(m.Caddy)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		name := line
+		for _, p := range []string{"# HELP ", "# TYPE "} {
+			name = strings.TrimPrefix(name, p)
+		}
+
+		if strings.HasPrefix(name, caddyPrefix) {
+			fmt.Fprintln(w, line)
+		}
+	}
+
+	return scanner.Err()
+}
+
+func header(w io.Writer, name, kind, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+// quote returns the label value escaped for the text format.
+func quote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
+
+func value(b bool) int {
+	if b {
+		return 1
+	}
+
+	return 0
+}
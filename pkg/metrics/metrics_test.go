@@ -0,0 +1,77 @@
+package metrics
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/craftcms/nitro/pkg/dashboard"
+	"github.com/craftcms/nitro/protob"
+)
+
+func TestMetrics_ServeHTTP(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lis.Close()
+
+	caddy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "# HELP caddy_http_requests_total Counter of HTTP(S) requests made.")
+		fmt.Fprintln(w, "# TYPE caddy_http_requests_total counter")
+		fmt.Fprintln(w, `caddy_http_requests_total{handler="reverse_proxy",server="srv0"} 42`)
+		fmt.Fprintln(w, "# HELP go_goroutines Number of goroutines that currently exist.")
+		fmt.Fprintln(w, "go_goroutines 12")
+	}))
+	defer caddy.Close()
+
+	d := &dashboard.Dashboard{}
+	d.Update(&protob.Dashboard{Items: []*protob.DashboardItem{
+		{Name: "craft.nitro", Kind: "site", Address: lis.Addr().String()},
+		{Name: "search", Kind: "container"},
+	}})
+
+	m := &Metrics{Caddy: caddy.URL + Path, HTTP: caddy.Client(), Dashboard: d}
+	m.Record(&protob.ReportRequest{Duration: 1.5, Containers: []*protob.ContainerState{
+		{Name: "redis.service.nitro", State: "exited", Restarts: 3},
+		{Name: "craft.nitro", State: "running"},
+	}})
+	m.Record(&protob.ReportRequest{Duration: 2.5, Containers: []*protob.ContainerState{
+		{Name: "craft.nitro", State: "running", Restarts: 1},
+	}})
+
+	rec := httptest.NewRecorder()
+	m.ServeHTTP(rec, httptest.NewRequest("GET", Path, nil))
+
+	body := rec.Body.String()
+	for _, want := range []string{
+		"# TYPE nitro_up gauge",
+		`nitro_up{kind="site",name="craft.nitro"} 1`,
+		`nitro_container_running{name="craft.nitro",state="running"} 1`,
+		`nitro_container_restarts{name="craft.nitro"} 1`,
+		"nitro_apply_duration_seconds_sum 4",
+		"nitro_apply_duration_seconds_count 2",
+		"nitro_apply_last_timestamp_seconds ",
+		"# TYPE caddy_http_requests_total counter",
+		`caddy_http_requests_total{handler="reverse_proxy",server="srv0"} 42`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected the metrics to contain %q, got:\n%s", want, body)
+		}
+	}
+
+	for _, unwanted := range []string{`name="search"`, "redis.service.nitro", "go_goroutines"} {
+		if strings.Contains(body, unwanted) {
+			t.Errorf("expected the metrics to not contain %q, got:\n%s", unwanted, body)
+		}
+	}
+}
+
+func TestQuote(t *testing.T) {
+	if got := quote("a\"b\\c\nd"); got != `"a\"b\\c\nd"` {
+		t.Errorf("quote() = %s", got)
+	}
+}
@@ -0,0 +1,144 @@
+package mutagen
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/environment"
+	"github.com/craftcms/nitro/pkg/terminal"
+)
+
+// ErrNotInstalled is returned when a site uses mutagen but the mutagen CLI is not installed.
+var ErrNotInstalled = fmt.Errorf("sites with sync: mutagen require the mutagen CLI, install it from https://mutagen.io")
+
+// Binary is the name of the mutagen CLI.
+var Binary = "mutagen"
+
+// run executes the mutagen CLI with the arguments and returns the output, it is a
+// variable so tests do not need mutagen installed.
+var run = func(ctx context.Context, args ...string) (string, error) {
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+
+	c := exec.CommandContext(ctx, Binary, args...)
+	c.Stdout = stdout
+	c.Stderr = stderr
+
+	if err := c.Run(); err != nil {
+		return "", fmt.Errorf("%s %s: %s", Binary, args[0]+" "+args[1], strings.TrimSpace(stderr.String()))
+	}
+
+	return stdout.String(), nil
+}
+
+// lookPath returns an error when the mutagen CLI is not installed.
+var lookPath = func() error {
+	_, err := exec.LookPath(Binary)
+
+	return err
+}
+
+// Session returns the name of the sync session for the site. Session names can only
+// contain letters, numbers, and dashes.
+func Session(hostname string) string {
+	return "nitro-" + strings.ReplaceAll(hostname, ".", "-")
+}
+
+// Check returns ErrNotInstalled when a site uses mutagen and the mutagen CLI is not installed.
+func Check(cfg *config.Config) error {
+	for _, s := range cfg.Sites {
+		if s.UsesSync() && lookPath() != nil {
+			return ErrNotInstalled
+		}
+	}
+
+	return nil
+}
+
+// Reconcile makes sure each site that uses mutagen has a sync session between the site
+// path and the volume of the site container, and terminates the sessions of the sites that
+// no longer use mutagen. The sessions are labeled so other sessions of the user are not
+// changed. The site containers must be created before the sessions.
+func Reconcile(ctx context.Context, home string, cfg *config.Config, output terminal.Outputer) error {
+	wanted := make(map[string]config.Site)
+	for _, s := range cfg.Sites {
+		if s.UsesSync() {
+			wanted[Session(s.Hostname)] = s
+		}
+	}
+
+	if err := lookPath(); err != nil {
+		if len(wanted) > 0 {
+			return ErrNotInstalled
+		}
+
+		// there are no sessions to terminate without mutagen
+		return nil
+	}
+
+	out, err := run(ctx, "sync", "list", "--label-selector="+containerlabels.Nitro+"="+environment.Label(), "--template={{range .}}{{.Name}}\n{{end}}")
+	if err != nil {
+		return err
+	}
+
+	existing := make(map[string]bool)
+	for _, name := range strings.Fields(out) {
+		if _, ok := wanted[name]; !ok {
+			output.Pending("terminating sync", name)
+
+			if _, err := run(ctx, "sync", "terminate", name); err != nil {
+				output.Warning()
+				return err
+			}
+
+			output.Done()
+
+			continue
+		}
+
+		existing[name] = true
+	}
+
+	for _, s := range cfg.Sites {
+		name := Session(s.Hostname)
+		if !s.UsesSync() || existing[name] {
+			continue
+		}
+
+		path, err := s.GetAbsPath(home)
+		if err != nil {
+			return err
+		}
+
+		output.Pending("syncing", s.Hostname)
+
+		if _, err := run(ctx, createArgs(name, path, s.Hostname)...); err != nil {
+			output.Warning()
+			return err
+		}
+
+		output.Done()
+	}
+
+	return nil
+}
+
+// createArgs returns the arguments to create the sync session between the path and the
+// app directory of the site container. Changes on either side are synced and conflicts
+// are resolved with the files on the host. Version control directories are not synced.
+func createArgs(name, path, container string) []string {
+	return []string{
+		"sync", "create",
+		"--name=" + name,
+		"--label=" + containerlabels.Nitro + "=" + environment.Label(),
+		"--sync-mode=two-way-resolved",
+		"--ignore-vcs",
+		path,
+		"docker://" + container + "/app",
+	}
+}
@@ -0,0 +1,71 @@
+package mutagen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/terminal"
+)
+
+func TestSession(t *testing.T) {
+	if got := Session("craft.nitro"); got != "nitro-craft-nitro" {
+		t.Errorf("Session() = %q, want nitro-craft-nitro", got)
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	defer func(r func(context.Context, ...string) (string, error), l func() error) {
+		run, lookPath = r, l
+	}(run, lookPath)
+
+	lookPath = func() error { return nil }
+
+	var calls []string
+	run = func(ctx context.Context, args ...string) (string, error) {
+		calls = append(calls, strings.Join(args[:2], " ")+" "+args[len(args)-1])
+
+		if args[1] == "list" {
+			return "nitro-old-nitro\nnitro-craft-nitro\n", nil
+		}
+
+		return "", nil
+	}
+
+	cfg := &config.Config{Sites: []config.Site{
+		{Hostname: "craft.nitro", Path: "/tmp/craft", Sync: config.SyncMutagen},
+		{Hostname: "demo.nitro", Path: "/tmp/demo", Sync: config.SyncMutagen},
+		{Hostname: "plain.nitro", Path: "/tmp/plain"},
+	}}
+
+	if err := Reconcile(context.Background(), "/home", cfg, terminal.New()); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"sync list --template={{range .}}{{.Name}}\n{{end}}",
+		"sync terminate nitro-old-nitro",
+		"sync create docker://demo.nitro/app",
+	}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("expected the calls %q, got %q", want, calls)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	defer func(l func() error) { lookPath = l }(lookPath)
+
+	lookPath = func() error { return fmt.Errorf("not found") }
+
+	if err := Check(&config.Config{Sites: []config.Site{{Hostname: "plain.nitro"}}}); err != nil {
+		t.Errorf("expected no error without mutagen sites, got %v", err)
+	}
+
+	if err := Check(&config.Config{Sites: []config.Site{{Hostname: "craft.nitro", Sync: config.SyncMutagen}}}); !errors.Is(err, ErrNotInstalled) {
+		t.Errorf("expected ErrNotInstalled, got %v", err)
+	}
+}
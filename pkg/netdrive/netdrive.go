@@ -0,0 +1,11 @@
+package netdrive
+
+// Warning explains the known problems with sites on network drives, it is shown after
+// the filesystem of the path.
+const Warning = "file access from the container is slow and file changes may not be detected, consider moving the site to a local disk or setting `sync: mutagen` for the site"
+
+// Detect returns the type of the filesystem and true when the path is on a network drive
+// (e.g. NFS or SMB). Paths that cannot be checked are treated as local paths.
+func Detect(path string) (string, bool) {
+	return detect(path)
+}
@@ -0,0 +1,31 @@
+// +build darwin
+
+package netdrive
+
+import "syscall"
+
+// filesystems are the names of the network filesystems from statfs(2).
+var filesystems = map[string]bool{
+	"afpfs":  true,
+	"nfs":    true,
+	"smbfs":  true,
+	"webdav": true,
+}
+
+func detect(path string) (string, bool) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return "", false
+	}
+
+	var name []byte
+	for _, c := range st.Fstypename {
+		if c == 0 {
+			break
+		}
+
+		name = append(name, byte(c))
+	}
+
+	return string(name), filesystems[string(name)]
+}
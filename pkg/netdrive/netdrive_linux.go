@@ -0,0 +1,26 @@
+// +build linux
+
+package netdrive
+
+import "syscall"
+
+// filesystems are the magic numbers of the network filesystems from statfs(2), 9p is
+// used for the Windows drives in WSL.
+var filesystems = map[uint32]string{
+	0x6969:     "nfs",
+	0x517b:     "smb",
+	0xff534d42: "cifs",
+	0xfe534d42: "smb2",
+	0x01021997: "9p",
+}
+
+func detect(path string) (string, bool) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return "", false
+	}
+
+	fs, ok := filesystems[uint32(st.Type)]
+
+	return fs, ok
+}
@@ -0,0 +1,7 @@
+// +build !linux,!darwin,!windows
+
+package netdrive
+
+func detect(path string) (string, bool) {
+	return "", false
+}
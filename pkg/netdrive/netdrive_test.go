@@ -0,0 +1,16 @@
+package netdrive
+
+import (
+	"os"
+	"testing"
+)
+
+func TestDetect(t *testing.T) {
+	if fs, ok := Detect(os.TempDir()); ok {
+		t.Skipf("the temp directory is on a network drive (%s)", fs)
+	}
+
+	if _, ok := Detect("/path/that/does/not/exist"); ok {
+		t.Errorf("expected paths that cannot be checked to be local")
+	}
+}
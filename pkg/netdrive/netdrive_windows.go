@@ -0,0 +1,28 @@
+// +build windows
+
+package netdrive
+
+import (
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/sys/windows"
+)
+
+func detect(path string) (string, bool) {
+	// UNC paths are always on a share
+	if strings.HasPrefix(path, `\\`) {
+		return "smb", true
+	}
+
+	root, err := windows.UTF16PtrFromString(filepath.VolumeName(path) + `\`)
+	if err != nil {
+		return "", false
+	}
+
+	if windows.GetDriveType(root) == windows.DRIVE_REMOTE {
+		return "network drive", true
+	}
+
+	return "", false
+}
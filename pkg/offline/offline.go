@@ -0,0 +1,137 @@
+package offline
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+
+	"github.com/craftcms/nitro/pkg/dockercache"
+)
+
+// Enabled is set by the --offline flag or the NITRO_OFFLINE environment variable. When
+// enabled, images are never pulled and the local images are used instead.
+var Enabled bool
+
+// MissingImagesError is returned when images are not available locally and cannot be
+// pulled because nitro is offline.
+type MissingImagesError struct {
+	Images []string
+}
+
+func (e *MissingImagesError) Error() string {
+	return fmt.Sprintf("unable to continue offline, the following images are not available locally:\n  - %s\nconnect to the internet and run the command again to pull the images", strings.Join(e.Images, "\n  - "))
+}
+
+// Client wraps a docker client and skips pulling images that are available locally when
+// nitro is offline or the registry for the image cannot be reached.
+type Client struct {
+	client.CommonAPIClient
+
+	mu        sync.Mutex
+	reachable map[string]bool
+}
+
+// New takes a docker client and returns a client that avoids pulling images when offline.
+func New(docker client.CommonAPIClient) *Client {
+	return &Client{CommonAPIClient: docker, reachable: make(map[string]bool)}
+}
+
+// Invalidate clears the cached lists of the wrapped client.
+func (c *Client) Invalidate() {
+	dockercache.Invalidate(c.CommonAPIClient)
+}
+
+// timeout is how long to wait when checking if a registry can be reached.
+var timeout = 2 * time.Second
+
+// dial is used to check if a registry can be reached, it is replaced in tests.
+var dial = func(addr string) error {
+	conn, err := net.DialTimeout("tcp", addr, timeout)
+	if err != nil {
+		return err
+	}
+
+	return conn.Close()
+}
+
+// ImagePull pulls the image unless nitro is offline. When offline, or when the registry
+// cannot be reached, the pull is skipped if the image is available locally. Otherwise a
+// MissingImagesError is returned.
+func (c *Client) ImagePull(ctx context.Context, ref string, options types.ImagePullOptions) (io.ReadCloser, error) {
+	if !Enabled && c.online(Registry(ref)) {
+		return c.CommonAPIClient.ImagePull(ctx, ref, options)
+	}
+
+	if !c.exists(ctx, ref) {
+		return nil, &MissingImagesError{Images: []string{ref}}
+	}
+
+	// the callers read the output of the pull
+	return ioutil.NopCloser(strings.NewReader("")), nil
+}
+
+// Check returns a MissingImagesError with every image that is not available locally.
+func Check(ctx context.Context, docker client.ImageAPIClient, images []string) error {
+	var missing []string
+	for _, image := range images {
+		if _, _, err := docker.ImageInspectWithRaw(ctx, image); err != nil {
+			missing = append(missing, image)
+		}
+	}
+
+	if len(missing) == 0 {
+		return nil
+	}
+
+	sort.Strings(missing)
+
+	return &MissingImagesError{Images: missing}
+}
+
+// Registry returns the address of the registry for the image reference. Images without
+// a registry use Docker Hub.
+func Registry(ref string) string {
+	if i := strings.Index(ref, "/"); i > 0 {
+		domain := ref[:i]
+
+		if domain != "docker.io" && (strings.ContainsAny(domain, ".:") || domain == "localhost") {
+			if !strings.Contains(domain, ":") {
+				domain += ":443"
+			}
+
+			return domain
+		}
+	}
+
+	return "registry-1.docker.io:443"
+}
+
+// online returns true if the registry can be reached, the result is cached for the
+// lifetime of the command.
+func (c *Client) online(registry string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if ok, checked := c.reachable[registry]; checked {
+		return ok
+	}
+
+	c.reachable[registry] = dial(registry) == nil
+
+	return c.reachable[registry]
+}
+
+func (c *Client) exists(ctx context.Context, ref string) bool {
+	_, _, err := c.CommonAPIClient.ImageInspectWithRaw(ctx, ref)
+
+	return err == nil
+}
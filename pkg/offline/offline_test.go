@@ -0,0 +1,122 @@
+package offline
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+)
+
+func TestClient_ImagePull(t *testing.T) {
+	defer func(d func(string) error) {
+		Enabled = false
+		dial = d
+	}(dial)
+
+	tests := []struct {
+		name      string
+		enabled   bool
+		reachable bool
+		ref       string
+		wantPull  bool
+		wantErr   bool
+	}{
+		{name: "images are pulled when online", reachable: true, ref: "craftcms/nginx:8.0-dev", wantPull: true},
+		{name: "local images are used when offline", enabled: true, reachable: true, ref: "craftcms/nginx:8.0-dev"},
+		{name: "local images are used when the registry cannot be reached", ref: "craftcms/nginx:8.0-dev"},
+		{name: "missing images return an error when offline", enabled: true, ref: "mysql:8.0", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			Enabled = tt.enabled
+			dial = func(addr string) error {
+				if !tt.reachable {
+					return errors.New("unreachable")
+				}
+
+				return nil
+			}
+
+			spy := &mockClient{local: map[string]bool{"craftcms/nginx:8.0-dev": true}}
+
+			rdr, err := New(spy).ImagePull(context.Background(), tt.ref, types.ImagePullOptions{})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ImagePull() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			var missing *MissingImagesError
+			if tt.wantErr && !errors.As(err, &missing) {
+				t.Errorf("expected a MissingImagesError, got %v", err)
+			}
+
+			if rdr != nil {
+				rdr.Close()
+			}
+
+			if spy.pulled != tt.wantPull {
+				t.Errorf("ImagePull() pulled = %v, want %v", spy.pulled, tt.wantPull)
+			}
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	spy := &mockClient{local: map[string]bool{"craftcms/nginx:8.0-dev": true}}
+
+	err := Check(context.Background(), spy, []string{"redis:latest", "craftcms/nginx:8.0-dev", "mysql:8.0"})
+
+	var missing *MissingImagesError
+	if !errors.As(err, &missing) {
+		t.Fatalf("expected a MissingImagesError, got %v", err)
+	}
+
+	if want := []string{"mysql:8.0", "redis:latest"}; !reflect.DeepEqual(missing.Images, want) {
+		t.Errorf("Check() missing = %v, want %v", missing.Images, want)
+	}
+
+	if err := Check(context.Background(), spy, []string{"craftcms/nginx:8.0-dev"}); err != nil {
+		t.Errorf("expected no error when the images exist, got %v", err)
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	tests := map[string]string{
+		"mysql:8.0":                       "registry-1.docker.io:443",
+		"craftcms/nginx:8.0-dev":          "registry-1.docker.io:443",
+		"docker.io/craftcms/nginx:8.0":    "registry-1.docker.io:443",
+		"ghcr.io/craftcms/image:latest":   "ghcr.io:443",
+		"localhost:5000/craftcms/nginx:1": "localhost:5000",
+	}
+	for ref, want := range tests {
+		if got := Registry(ref); got != want {
+			t.Errorf("Registry(%q) = %q, want %q", ref, got, want)
+		}
+	}
+}
+
+type mockClient struct {
+	client.CommonAPIClient
+
+	local  map[string]bool
+	pulled bool
+}
+
+func (m *mockClient) ImagePull(ctx context.Context, ref string, options types.ImagePullOptions) (io.ReadCloser, error) {
+	m.pulled = true
+
+	return ioutil.NopCloser(strings.NewReader("{}")), nil
+}
+
+func (m *mockClient) ImageInspectWithRaw(ctx context.Context, image string) (types.ImageInspect, []byte, error) {
+	if m.local[image] {
+		return types.ImageInspect{ID: image}, nil, nil
+	}
+
+	return types.ImageInspect{}, nil, errors.New("no such image")
+}
@@ -0,0 +1,534 @@
+package ownership
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/client"
+	specs "github.com/opencontainers/image-spec/specs-go/v1"
+
+	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/dockercache"
+	"github.com/craftcms/nitro/pkg/environment"
+)
+
+// NotOwnerError is returned when a container, volume, or network that belongs to another
+// developer on a shared docker daemon is stopped or removed.
+type NotOwnerError struct {
+	Kind  string
+	Name  string
+	Owner string
+}
+
+func (e *NotOwnerError) Error() string {
+	if e.Owner == "" {
+		return fmt.Sprintf("the %s %s is not shared, it can only be changed without NITRO_SHARED and NITRO_OWNER", e.Kind, e.Name)
+	}
+
+	return fmt.Sprintf("the %s %s belongs to %s and can only be changed by them", e.Kind, e.Name, e.Owner)
+}
+
+// Client wraps a docker client so multiple developers can use the same docker daemon. When
+// there is an owner, the containers, volumes, and networks that are created are labeled with
+// the owner and their names are prefixed with the owner (e.g. alice.craft.nitro). The lists
+// only return the resources of the owner and the prefix is removed from the names, so the
+// commands use the same names as a daemon that is not shared. The containers, volumes, and
+// networks of another owner can not be stopped or removed. The names of the containers and
+// volumes of an environment other than the default are also prefixed with the environment
+// (e.g. alice.agency.craft.nitro), so each environment has its own containers and volumes.
+type Client struct {
+	client.CommonAPIClient
+}
+
+// New takes a docker client and returns a client that namespaces the resources by owner.
+func New(docker client.CommonAPIClient) *Client {
+	return &Client{CommonAPIClient: docker}
+}
+
+// Invalidate clears the cached lists of the wrapped client.
+func (c *Client) Invalidate() {
+	dockercache.Invalidate(c.CommonAPIClient)
+}
+
+// prefix is added to the names of the resources of the owner and the environment. The
+// network, proxy, and proxy volume of the environment already have the name of the
+// environment, so they only get the prefix of the owner.
+func prefix(name string) string {
+	var p string
+	if environment.Owner != "" {
+		p = environment.Owner + "."
+	}
+
+	switch name {
+	case environment.Network(), environment.Proxy(), environment.Volume():
+		return p
+	}
+
+	return p + environment.Prefix(environment.Name)
+}
+
+// Name returns the name of the resource on the docker daemon. IDs and names that already
+// have the prefix are returned as is.
+func Name(name string) string {
+	if name == "" || isID(name) {
+		return name
+	}
+
+	slash := strings.HasPrefix(name, "/")
+	name = strings.TrimPrefix(name, "/")
+	if p := prefix(name); !strings.HasPrefix(name, p) {
+		name = p + name
+	}
+
+	if slash {
+		return "/" + name
+	}
+
+	return name
+}
+
+// Strip returns the name without the prefix of the owner and the environment. Names of
+// another owner are returned as is.
+func Strip(name string) string {
+	slash := strings.HasPrefix(name, "/")
+	stripped := strings.TrimPrefix(name, "/")
+
+	if environment.Owner != "" {
+		if !strings.HasPrefix(stripped, environment.Owner+".") {
+			return name
+		}
+
+		stripped = strings.TrimPrefix(stripped, environment.Owner+".")
+	}
+
+	stripped = strings.TrimPrefix(stripped, environment.Prefix(environment.Name))
+
+	if slash {
+		return "/" + stripped
+	}
+
+	return stripped
+}
+
+// Listed returns the name of a resource of the environment the way the client lists it,
+// the resources of the other environments keep the prefix of their environment.
+func Listed(env, name string) string {
+	name = environment.Prefix(env) + name
+	if environment.Owner != "" {
+		name = environment.Owner + "." + name
+	}
+
+	return Strip(name)
+}
+
+// isID returns true when the value is a docker ID, or the short form of an ID.
+func isID(s string) bool {
+	if len(s) < 12 {
+		return false
+	}
+
+	for _, r := range s {
+		if !strings.ContainsRune("0123456789abcdef", r) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// owns returns true when the labels belong to the owner.
+func owns(labels map[string]string) bool {
+	return labels[containerlabels.Owner] == environment.Owner
+}
+
+// filter returns a copy of the filters that only matches the resources of the owner and
+// uses the prefixed names.
+func filter(f filters.Args) filters.Args {
+	args := f.Clone()
+
+	for _, n := range args.Get("name") {
+		args.Del("name", n)
+		args.Add("name", Name(n))
+	}
+
+	if environment.Owner == "" {
+		return args
+	}
+
+	args.Add("label", containerlabels.Owner+"="+environment.Owner)
+
+	return args
+}
+
+// label returns a copy of the labels with the owner.
+func label(labels map[string]string) map[string]string {
+	if environment.Owner == "" {
+		return labels
+	}
+
+	l := map[string]string{containerlabels.Owner: environment.Owner}
+	for k, v := range labels {
+		l[k] = v
+	}
+
+	return l
+}
+
+// networkMode returns the network mode with the prefixed network name, the modes that are not
+// user defined networks are returned as is.
+func networkMode(mode container.NetworkMode) container.NetworkMode {
+	if mode == "" || mode.IsDefault() || mode.IsBridge() || mode.IsHost() || mode.IsNone() || mode.IsContainer() {
+		return mode
+	}
+
+	return container.NetworkMode(Name(string(mode)))
+}
+
+// bind returns the bind with the prefixed volume name, binds of paths are returned as is.
+func bind(b string) string {
+	parts := strings.SplitN(b, ":", 2)
+	if len(parts) < 2 || strings.ContainsAny(parts[0], `/\`) || strings.HasPrefix(parts[0], ".") || strings.HasPrefix(parts[0], "~") {
+		return b
+	}
+
+	return Name(parts[0]) + ":" + parts[1]
+}
+
+// ContainerCreate creates the container with the owner label and the prefixed name. The
+// name of the container is added as an alias on the networks, so the containers can still
+// reach each other by the name without the prefix.
+func (c *Client) ContainerCreate(ctx context.Context, config *container.Config, hostConfig *container.HostConfig, networkingConfig *network.NetworkingConfig, platform *specs.Platform, containerName string) (container.ContainerCreateCreatedBody, error) {
+	if environment.Owner == "" && environment.IsDefault() {
+		return c.CommonAPIClient.ContainerCreate(ctx, config, hostConfig, networkingConfig, platform, containerName)
+	}
+
+	if config != nil {
+		cfg := *config
+		cfg.Labels = label(config.Labels)
+		config = &cfg
+	}
+
+	if hostConfig != nil {
+		hc := *hostConfig
+		hc.NetworkMode = networkMode(hostConfig.NetworkMode)
+
+		hc.Binds = nil
+		for _, b := range hostConfig.Binds {
+			hc.Binds = append(hc.Binds, bind(b))
+		}
+
+		hc.Mounts = nil
+		for _, m := range hostConfig.Mounts {
+			if m.Type == mount.TypeVolume {
+				m.Source = Name(m.Source)
+			}
+
+			hc.Mounts = append(hc.Mounts, m)
+		}
+
+		hostConfig = &hc
+	}
+
+	if networkingConfig != nil {
+		endpoints := make(map[string]*network.EndpointSettings)
+		for n, e := range networkingConfig.EndpointsConfig {
+			settings := network.EndpointSettings{}
+			if e != nil {
+				settings = *e
+			}
+
+			if containerName != "" {
+				settings.Aliases = append(append([]string{}, settings.Aliases...), strings.TrimPrefix(containerName, "/"))
+			}
+
+			endpoints[Name(n)] = &settings
+		}
+
+		networkingConfig = &network.NetworkingConfig{EndpointsConfig: endpoints}
+	}
+
+	return c.CommonAPIClient.ContainerCreate(ctx, config, hostConfig, networkingConfig, platform, Name(containerName))
+}
+
+// ContainerList returns the containers of the owner without the prefix in the names.
+func (c *Client) ContainerList(ctx context.Context, options types.ContainerListOptions) ([]types.Container, error) {
+	options.Filters = filter(options.Filters)
+
+	containers, err := c.CommonAPIClient.ContainerList(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range containers {
+		containers[i].Names = strip(containers[i].Names)
+	}
+
+	return containers, nil
+}
+
+func strip(names []string) []string {
+	stripped := make([]string, len(names))
+	for i, n := range names {
+		stripped[i] = Strip(n)
+	}
+
+	return stripped
+}
+
+// ContainerInspect returns the container without the prefix in the name.
+func (c *Client) ContainerInspect(ctx context.Context, containerID string) (types.ContainerJSON, error) {
+	details, err := c.CommonAPIClient.ContainerInspect(ctx, Name(containerID))
+	if err != nil {
+		return details, err
+	}
+
+	if details.ContainerJSONBase != nil {
+		details.Name = Strip(details.Name)
+	}
+
+	return details, nil
+}
+
+// guard returns a NotOwnerError when the container belongs to another owner.
+func (c *Client) guard(ctx context.Context, containerID string) error {
+	details, err := c.CommonAPIClient.ContainerInspect(ctx, Name(containerID))
+	if err != nil || details.Config == nil {
+		// the error is returned by the call that is guarded
+		return nil
+	}
+
+	if !owns(details.Config.Labels) {
+		return &NotOwnerError{Kind: "container", Name: Strip(strings.TrimPrefix(details.Name, "/")), Owner: details.Config.Labels[containerlabels.Owner]}
+	}
+
+	return nil
+}
+
+func (c *Client) ContainerStop(ctx context.Context, containerID string, timeout *time.Duration) error {
+	if err := c.guard(ctx, containerID); err != nil {
+		return err
+	}
+
+	return c.CommonAPIClient.ContainerStop(ctx, Name(containerID), timeout)
+}
+
+func (c *Client) ContainerRemove(ctx context.Context, containerID string, options types.ContainerRemoveOptions) error {
+	if err := c.guard(ctx, containerID); err != nil {
+		return err
+	}
+
+	return c.CommonAPIClient.ContainerRemove(ctx, Name(containerID), options)
+}
+
+func (c *Client) ContainerKill(ctx context.Context, containerID, signal string) error {
+	if err := c.guard(ctx, containerID); err != nil {
+		return err
+	}
+
+	return c.CommonAPIClient.ContainerKill(ctx, Name(containerID), signal)
+}
+
+func (c *Client) ContainerRestart(ctx context.Context, containerID string, timeout *time.Duration) error {
+	if err := c.guard(ctx, containerID); err != nil {
+		return err
+	}
+
+	return c.CommonAPIClient.ContainerRestart(ctx, Name(containerID), timeout)
+}
+
+func (c *Client) ContainerUpdate(ctx context.Context, containerID string, updateConfig container.UpdateConfig) (container.ContainerUpdateOKBody, error) {
+	if err := c.guard(ctx, containerID); err != nil {
+		return container.ContainerUpdateOKBody{}, err
+	}
+
+	return c.CommonAPIClient.ContainerUpdate(ctx, Name(containerID), updateConfig)
+}
+
+func (c *Client) ContainerRename(ctx context.Context, containerID, newContainerName string) error {
+	if err := c.guard(ctx, containerID); err != nil {
+		return err
+	}
+
+	return c.CommonAPIClient.ContainerRename(ctx, Name(containerID), Name(newContainerName))
+}
+
+func (c *Client) ContainerStart(ctx context.Context, containerID string, options types.ContainerStartOptions) error {
+	return c.CommonAPIClient.ContainerStart(ctx, Name(containerID), options)
+}
+
+func (c *Client) ContainerExecCreate(ctx context.Context, containerID string, config types.ExecConfig) (types.IDResponse, error) {
+	return c.CommonAPIClient.ContainerExecCreate(ctx, Name(containerID), config)
+}
+
+func (c *Client) ContainerLogs(ctx context.Context, containerID string, options types.ContainerLogsOptions) (io.ReadCloser, error) {
+	return c.CommonAPIClient.ContainerLogs(ctx, Name(containerID), options)
+}
+
+func (c *Client) ContainerAttach(ctx context.Context, containerID string, options types.ContainerAttachOptions) (types.HijackedResponse, error) {
+	return c.CommonAPIClient.ContainerAttach(ctx, Name(containerID), options)
+}
+
+func (c *Client) ContainerWait(ctx context.Context, containerID string, condition container.WaitCondition) (<-chan container.ContainerWaitOKBody, <-chan error) {
+	return c.CommonAPIClient.ContainerWait(ctx, Name(containerID), condition)
+}
+
+func (c *Client) CopyToContainer(ctx context.Context, containerID, path string, content io.Reader, options types.CopyToContainerOptions) error {
+	return c.CommonAPIClient.CopyToContainer(ctx, Name(containerID), path, content, options)
+}
+
+func (c *Client) CopyFromContainer(ctx context.Context, containerID, srcPath string) (io.ReadCloser, types.ContainerPathStat, error) {
+	return c.CommonAPIClient.CopyFromContainer(ctx, Name(containerID), srcPath)
+}
+
+func (c *Client) ContainerStatPath(ctx context.Context, containerID, path string) (types.ContainerPathStat, error) {
+	return c.CommonAPIClient.ContainerStatPath(ctx, Name(containerID), path)
+}
+
+// VolumeCreate creates the volume with the owner label and the prefixed name.
+func (c *Client) VolumeCreate(ctx context.Context, options volume.VolumeCreateBody) (types.Volume, error) {
+	options.Name = Name(options.Name)
+	options.Labels = label(options.Labels)
+
+	v, err := c.CommonAPIClient.VolumeCreate(ctx, options)
+	v.Name = Strip(v.Name)
+
+	return v, err
+}
+
+// VolumeList returns the volumes of the owner without the prefix in the names.
+func (c *Client) VolumeList(ctx context.Context, f filters.Args) (volume.VolumeListOKBody, error) {
+	volumes, err := c.CommonAPIClient.VolumeList(ctx, filter(f))
+	if err != nil {
+		return volumes, err
+	}
+
+	for _, v := range volumes.Volumes {
+		if v != nil {
+			v.Name = Strip(v.Name)
+		}
+	}
+
+	return volumes, nil
+}
+
+func (c *Client) VolumeInspect(ctx context.Context, volumeID string) (types.Volume, error) {
+	v, err := c.CommonAPIClient.VolumeInspect(ctx, Name(volumeID))
+	v.Name = Strip(v.Name)
+
+	return v, err
+}
+
+// VolumeRemove removes the volume when it belongs to the owner.
+func (c *Client) VolumeRemove(ctx context.Context, volumeID string, force bool) error {
+	if v, err := c.CommonAPIClient.VolumeInspect(ctx, Name(volumeID)); err == nil && !owns(v.Labels) {
+		return &NotOwnerError{Kind: "volume", Name: volumeID, Owner: v.Labels[containerlabels.Owner]}
+	}
+
+	return c.CommonAPIClient.VolumeRemove(ctx, Name(volumeID), force)
+}
+
+// NetworkCreate creates the network with the owner label and the prefixed name.
+func (c *Client) NetworkCreate(ctx context.Context, name string, options types.NetworkCreate) (types.NetworkCreateResponse, error) {
+	options.Labels = label(options.Labels)
+
+	return c.CommonAPIClient.NetworkCreate(ctx, Name(name), options)
+}
+
+// NetworkList returns the networks of the owner without the prefix in the names.
+func (c *Client) NetworkList(ctx context.Context, options types.NetworkListOptions) ([]types.NetworkResource, error) {
+	options.Filters = filter(options.Filters)
+
+	networks, err := c.CommonAPIClient.NetworkList(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range networks {
+		networks[i].Name = Strip(networks[i].Name)
+	}
+
+	return networks, nil
+}
+
+// NetworkRemove removes the network when it belongs to the owner.
+func (c *Client) NetworkRemove(ctx context.Context, networkID string) error {
+	if n, err := c.CommonAPIClient.NetworkInspect(ctx, Name(networkID), types.NetworkInspectOptions{}); err == nil && !owns(n.Labels) {
+		return &NotOwnerError{Kind: "network", Name: Strip(n.Name), Owner: n.Labels[containerlabels.Owner]}
+	}
+
+	return c.CommonAPIClient.NetworkRemove(ctx, Name(networkID))
+}
+
+// NetworkConnect connects the container with the name without the prefix as an alias, like
+// ContainerCreate, so the containers that are reconnected can still be reached by the name.
+func (c *Client) NetworkConnect(ctx context.Context, networkID, containerID string, config *network.EndpointSettings) error {
+	if environment.Owner == "" && environment.IsDefault() {
+		return c.CommonAPIClient.NetworkConnect(ctx, networkID, containerID, config)
+	}
+
+	name := containerID
+	if isID(containerID) {
+		name = ""
+		if details, err := c.CommonAPIClient.ContainerInspect(ctx, containerID); err == nil && details.ContainerJSONBase != nil {
+			name = details.Name
+		}
+	}
+
+	if name != "" {
+		settings := network.EndpointSettings{}
+		if config != nil {
+			settings = *config
+		}
+
+		settings.Aliases = append(append([]string{}, settings.Aliases...), strings.TrimPrefix(Strip(name), "/"))
+		config = &settings
+	}
+
+	return c.CommonAPIClient.NetworkConnect(ctx, Name(networkID), Name(containerID), config)
+}
+
+func (c *Client) NetworkDisconnect(ctx context.Context, networkID, containerID string, force bool) error {
+	return c.CommonAPIClient.NetworkDisconnect(ctx, Name(networkID), Name(containerID), force)
+}
+
+// DiskUsage returns the disk usage with only the containers and volumes of the owner, the
+// images are shared by the owners.
+func (c *Client) DiskUsage(ctx context.Context) (types.DiskUsage, error) {
+	usage, err := c.CommonAPIClient.DiskUsage(ctx)
+	if err != nil {
+		return usage, err
+	}
+
+	var containers []*types.Container
+	for _, ct := range usage.Containers {
+		if ct == nil || !owns(ct.Labels) {
+			continue
+		}
+
+		ct.Names = strip(ct.Names)
+		containers = append(containers, ct)
+	}
+
+	var volumes []*types.Volume
+	for _, v := range usage.Volumes {
+		if v == nil || !owns(v.Labels) {
+			continue
+		}
+
+		v.Name = Strip(v.Name)
+		volumes = append(volumes, v)
+	}
+
+	usage.Containers, usage.Volumes = containers, volumes
+
+	return usage, nil
+}
@@ -0,0 +1,228 @@
+package ownership
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/client"
+	specs "github.com/opencontainers/image-spec/specs-go/v1"
+
+	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/environment"
+)
+
+func TestName(t *testing.T) {
+	defer func() { environment.Owner = "" }()
+
+	if n := Name("craft.nitro"); n != "craft.nitro" {
+		t.Errorf("expected the name to be unchanged without an owner, got %s", n)
+	}
+
+	environment.Owner = "alice"
+
+	tests := map[string]string{
+		"craft.nitro":       "alice.craft.nitro",
+		"/craft.nitro":      "/alice.craft.nitro",
+		"alice.craft.nitro": "alice.craft.nitro",
+		"4f1bd2a7c8e9":      "4f1bd2a7c8e9",
+		"nitro-network":     "alice.nitro-network",
+		"":                  "",
+	}
+	for name, want := range tests {
+		if got := Name(name); got != want {
+			t.Errorf("Name(%q) = %q, want %q", name, got, want)
+		}
+	}
+
+	for name, want := range map[string]string{"alice.craft.nitro": "craft.nitro", "/alice.craft.nitro": "/craft.nitro", "bob.craft.nitro": "bob.craft.nitro"} {
+		if got := Strip(name); got != want {
+			t.Errorf("Strip(%q) = %q, want %q", name, got, want)
+		}
+	}
+
+	if b := bind("mysql_data:/var/lib/mysql"); b != "alice.mysql_data:/var/lib/mysql" {
+		t.Errorf("expected the volume of the bind to be prefixed, got %s", b)
+	}
+
+	if b := bind("/Users/alice/dev/craft:/app"); b != "/Users/alice/dev/craft:/app" {
+		t.Errorf("expected the path of the bind to be unchanged, got %s", b)
+	}
+}
+
+func TestName_Environment(t *testing.T) {
+	defer func() { environment.Owner, environment.Name = "", environment.Default }()
+
+	environment.Name = "agency"
+
+	tests := map[string]string{
+		"craft.nitro":        "agency.craft.nitro",
+		"agency.craft.nitro": "agency.craft.nitro",
+		"agency-network":     "agency-network",
+		"agency-proxy":       "agency-proxy",
+		"agency":             "agency",
+	}
+	for name, want := range tests {
+		if got := Name(name); got != want {
+			t.Errorf("Name(%q) = %q, want %q", name, got, want)
+		}
+	}
+
+	environment.Owner = "alice"
+
+	if n := Name("craft.nitro"); n != "alice.agency.craft.nitro" {
+		t.Errorf("expected the name to be prefixed with the owner and environment, got %s", n)
+	}
+
+	if n := Strip("/alice.agency.craft.nitro"); n != "/craft.nitro" {
+		t.Errorf("expected the owner and environment to be removed, got %s", n)
+	}
+
+	if n := Listed("nitro", "mysql.database.nitro"); n != "mysql.database.nitro" {
+		t.Errorf("expected the volume of the default environment to be listed without a prefix, got %s", n)
+	}
+
+	environment.Name = environment.Default
+
+	if n := Listed("agency", "mysql.database.nitro"); n != "agency.mysql.database.nitro" {
+		t.Errorf("expected the volume of another environment to keep its prefix, got %s", n)
+	}
+}
+
+func TestClient_ContainerCreate(t *testing.T) {
+	defer func() { environment.Owner = "" }()
+
+	environment.Owner = "alice"
+
+	spy := &mockClient{}
+	_, err := New(spy).ContainerCreate(
+		context.Background(),
+		&container.Config{Labels: map[string]string{containerlabels.Nitro: "true"}},
+		&container.HostConfig{Mounts: []mount.Mount{{Type: mount.TypeVolume, Source: "mysql.database.nitro", Target: "/var/lib/mysql"}}},
+		&network.NetworkingConfig{EndpointsConfig: map[string]*network.EndpointSettings{"nitro-network": {NetworkID: "abc"}}},
+		nil,
+		"mysql.database.nitro",
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if spy.name != "alice.mysql.database.nitro" {
+		t.Errorf("expected the name to be prefixed, got %s", spy.name)
+	}
+
+	if spy.config.Labels[containerlabels.Owner] != "alice" || spy.config.Labels[containerlabels.Nitro] != "true" {
+		t.Errorf("expected the owner label to be added, got %v", spy.config.Labels)
+	}
+
+	if spy.hostConfig.Mounts[0].Source != "alice.mysql.database.nitro" {
+		t.Errorf("expected the volume to be prefixed, got %s", spy.hostConfig.Mounts[0].Source)
+	}
+
+	endpoint, ok := spy.networking.EndpointsConfig["alice.nitro-network"]
+	if !ok {
+		t.Fatalf("expected the network to be prefixed, got %v", spy.networking.EndpointsConfig)
+	}
+
+	if !reflect.DeepEqual(endpoint.Aliases, []string{"mysql.database.nitro"}) {
+		t.Errorf("expected the name without the prefix as an alias, got %v", endpoint.Aliases)
+	}
+}
+
+func TestClient_ContainerRemove(t *testing.T) {
+	defer func() { environment.Owner = "" }()
+
+	tests := []struct {
+		name    string
+		owner   string
+		labels  map[string]string
+		wantErr bool
+	}{
+		{name: "containers of the owner are removed", owner: "alice", labels: map[string]string{containerlabels.Owner: "alice"}},
+		{name: "containers of another owner are not removed", owner: "alice", labels: map[string]string{containerlabels.Owner: "bob"}, wantErr: true},
+		{name: "shared containers are not removed without an owner", labels: map[string]string{containerlabels.Owner: "bob"}, wantErr: true},
+		{name: "containers are removed when the daemon is not shared", labels: map[string]string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			environment.Owner = tt.owner
+
+			spy := &mockClient{labels: tt.labels}
+			err := New(spy).ContainerRemove(context.Background(), "craft.nitro", types.ContainerRemoveOptions{})
+
+			var notOwner *NotOwnerError
+			if tt.wantErr != errors.As(err, &notOwner) {
+				t.Errorf("ContainerRemove() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if spy.removed == tt.wantErr {
+				t.Errorf("expected removed to be %v", !tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestClient_ContainerList(t *testing.T) {
+	defer func() { environment.Owner = "" }()
+
+	environment.Owner = "alice"
+
+	spy := &mockClient{containers: []types.Container{{Names: []string{"/alice.craft.nitro"}}}}
+	containers, err := New(spy).ContainerList(context.Background(), types.ContainerListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if containers[0].Names[0] != "/craft.nitro" {
+		t.Errorf("expected the prefix to be removed, got %s", containers[0].Names[0])
+	}
+
+	if !spy.filters.ExactMatch("label", containerlabels.Owner+"=alice") {
+		t.Errorf("expected the containers to be filtered by owner, got %v", spy.filters)
+	}
+}
+
+type mockClient struct {
+	client.CommonAPIClient
+
+	labels     map[string]string
+	containers []types.Container
+
+	name       string
+	config     *container.Config
+	hostConfig *container.HostConfig
+	networking *network.NetworkingConfig
+	removed    bool
+	filters    filters.Args
+}
+
+func (m *mockClient) ContainerCreate(ctx context.Context, config *container.Config, hostConfig *container.HostConfig, networkingConfig *network.NetworkingConfig, platform *specs.Platform, containerName string) (container.ContainerCreateCreatedBody, error) {
+	m.config, m.hostConfig, m.networking, m.name = config, hostConfig, networkingConfig, containerName
+
+	return container.ContainerCreateCreatedBody{ID: "abc"}, nil
+}
+
+func (m *mockClient) ContainerInspect(ctx context.Context, containerID string) (types.ContainerJSON, error) {
+	return types.ContainerJSON{
+		ContainerJSONBase: &types.ContainerJSONBase{Name: "/" + containerID},
+		Config:            &container.Config{Labels: m.labels},
+	}, nil
+}
+
+func (m *mockClient) ContainerRemove(ctx context.Context, containerID string, options types.ContainerRemoveOptions) error {
+	m.removed = true
+
+	return nil
+}
+
+func (m *mockClient) ContainerList(ctx context.Context, options types.ContainerListOptions) ([]types.Container, error) {
+	m.filters = options.Filters
+
+	return m.containers, nil
+}
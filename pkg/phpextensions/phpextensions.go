@@ -0,0 +1,123 @@
+package phpextensions
+
+import "strings"
+
+// Bundled are the extensions that are already included in the site images, they do not
+// need to be installed for a site.
+var Bundled = []string{
+	"ctype",
+	"curl",
+	"dom",
+	"fileinfo",
+	"filter",
+	"gd",
+	"hash",
+	"iconv",
+	"imagick",
+	"intl",
+	"json",
+	"libxml",
+	"mbstring",
+	"opcache",
+	"openssl",
+	"pcre",
+	"pdo",
+	"pdo_mysql",
+	"pdo_pgsql",
+	"phar",
+	"posix",
+	"readline",
+	"redis",
+	"reflection",
+	"session",
+	"simplexml",
+	"soap",
+	"sodium",
+	"spl",
+	"standard",
+	"tokenizer",
+	"xml",
+	"xmlreader",
+	"xmlwriter",
+	"zip",
+	"zlib",
+}
+
+// Installable are the extensions that can be installed for a site with docker-php-ext-install.
+var Installable = []string{
+	"bcmath",
+	"bz2",
+	"calendar",
+	"dba",
+	"enchant",
+	"exif",
+	"gettext",
+	"gmp",
+	"imap",
+	"interbase",
+	"ldap",
+	"mysqli",
+	"oci8",
+	"odbc",
+	"pcntl",
+	"pdo_dblib",
+	"pdo_firebird",
+	"pdo_oci",
+	"pdo_odbc",
+	"pdo_sqlite",
+	"recode",
+	"shmop",
+	"snmp",
+	"sockets",
+	"sysvmsg",
+	"sysvsem",
+	"sysvshm",
+	"tidy",
+	"wddx",
+	"xmlrpc",
+	"xsl",
+	"zend_test",
+}
+
+// Name returns the name of the extension for the composer package name of a platform
+// requirement (e.g. ext-zend-opcache). It returns an empty string if the package is not
+// an extension.
+func Name(pkg string) string {
+	pkg = strings.ToLower(strings.TrimSpace(pkg))
+	if !strings.HasPrefix(pkg, "ext-") {
+		return ""
+	}
+
+	name := strings.TrimPrefix(pkg, "ext-")
+	if name == "zend-opcache" {
+		return "opcache"
+	}
+
+	return strings.ReplaceAll(name, "-", "_")
+}
+
+// Split returns the extensions that need to be installed for a site and the extensions
+// that are not supported. Extensions that are bundled with the site images are skipped.
+func Split(extensions []string) (install []string, unsupported []string) {
+	for _, e := range extensions {
+		switch {
+		case contains(Bundled, e):
+		case contains(Installable, e):
+			install = append(install, e)
+		default:
+			unsupported = append(unsupported, e)
+		}
+	}
+
+	return install, unsupported
+}
+
+func contains(list []string, s string) bool {
+	for _, l := range list {
+		if l == s {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,18 @@
+package phpextensions
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplit(t *testing.T) {
+	install, unsupported := Split([]string{"bcmath", "intl", "mongodb", "pdo_mysql", "xsl"})
+
+	if want := []string{"bcmath", "xsl"}; !reflect.DeepEqual(install, want) {
+		t.Errorf("Split() install = %v, want %v", install, want)
+	}
+
+	if want := []string{"mongodb"}; !reflect.DeepEqual(unsupported, want) {
+		t.Errorf("Split() unsupported = %v, want %v", unsupported, want)
+	}
+}
@@ -1,8 +1,10 @@
 package portavail
 
 import (
+	"errors"
 	"fmt"
 	"net"
+	"os"
 	"strconv"
 )
 
@@ -32,6 +34,25 @@ func Check(host, port string) error {
 	return nil
 }
 
+// InUse returns true when the port is used by another process. Ports that can not be bound
+// without permission (e.g. the ports below 1024 on Linux) are not reported as in use, since
+// they are bound by the Docker daemon and not the user.
+func InUse(host, port string) bool {
+	hostname := "localhost"
+	if host != "" {
+		hostname = host
+	}
+
+	lis, err := net.Listen("tcp", hostname+":"+port)
+	if err != nil {
+		return !errors.Is(err, os.ErrPermission)
+	}
+
+	_ = lis.Close()
+
+	return false
+}
+
 // FindNext takes a host and port and will find the next available port
 func FindNext(host, port string) (string, error) {
 	// convert the port to an integer
@@ -40,6 +40,27 @@ func TestCheck(t *testing.T) {
 	}
 }
 
+func TestInUse(t *testing.T) {
+	lis, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lis.Close()
+
+	port := strconv.Itoa(lis.Addr().(*net.TCPAddr).Port)
+	if !InUse("", port) {
+		t.Errorf("expected port %s to be in use", port)
+	}
+
+	if err := lis.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if InUse("", port) {
+		t.Errorf("expected port %s to be free after the listener is closed", port)
+	}
+}
+
 func TestFindNext(t *testing.T) {
 	// find a random port
 	lis, err := net.Listen("tcp", "localhost:0")
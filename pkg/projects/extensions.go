@@ -0,0 +1,41 @@
+package projects
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/craftcms/nitro/pkg/phpextensions"
+)
+
+// Extensions returns the PHP extensions required by the composer.json in the directory,
+// sorted by name. It returns nil when the directory does not have a composer.json.
+func Extensions(dir string) ([]string, error) {
+	b, err := ioutil.ReadFile(filepath.Join(dir, "composer.json"))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var c composer
+	if err := json.Unmarshal(b, &c); err != nil {
+		return nil, fmt.Errorf("unable to parse the composer.json, %w", err)
+	}
+
+	var extensions []string
+	for pkg := range c.Require {
+		if name := phpextensions.Name(pkg); name != "" {
+			extensions = append(extensions, name)
+		}
+	}
+
+	sort.Strings(extensions)
+
+	return extensions, nil
+}
@@ -0,0 +1,36 @@
+package projects
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestExtensions(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nitro-extensions")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if got, err := Extensions(dir); err != nil || got != nil {
+		t.Errorf("expected no extensions without a composer.json, got %v, %v", got, err)
+	}
+
+	composer := `{"require": {"craftcms/cms": "^3.6", "php": "^7.2.5", "ext-zend-opcache": "*", "ext-bcmath": "*", "EXT-GMP": "*", "ext-pdo_mysql": "*"}}`
+	if err := ioutil.WriteFile(filepath.Join(dir, "composer.json"), []byte(composer), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Extensions(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"bcmath", "gmp", "opcache", "pdo_mysql"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Extensions() = %v, want %v", got, want)
+	}
+}
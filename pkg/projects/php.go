@@ -0,0 +1,152 @@
+package projects
+
+import (
+	"strconv"
+	"strings"
+)
+
+// PHPVersion takes a composer constraint for PHP (e.g. "^7.2.5 || ^8.0") and
+// returns the highest version in versions that satisfies the constraint. The
+// versions are major and minor versions (e.g. 8.0) sorted from newest to oldest.
+// If none of the versions satisfy the constraint, it returns an empty string.
+func PHPVersion(constraint string, versions []string) string {
+	constraint = strings.TrimSpace(constraint)
+	if constraint == "" {
+		return ""
+	}
+
+	for _, v := range versions {
+		if satisfies(v, constraint) {
+			return v
+		}
+	}
+
+	return ""
+}
+
+// satisfies checks if any patch release of the major and minor version
+// satisfies the constraint.
+func satisfies(version, constraint string) bool {
+	v, ok := parse(version)
+	if !ok {
+		return false
+	}
+
+	// each of the or constraints
+	for _, or := range strings.Split(strings.ReplaceAll(constraint, "||", "|"), "|") {
+		// each of the and constraints
+		and := strings.Fields(strings.ReplaceAll(or, ",", " "))
+		if len(and) == 0 {
+			continue
+		}
+
+		matched := true
+		for _, c := range and {
+			if !clause(v, c) {
+				matched = false
+				break
+			}
+		}
+
+		if matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+func clause(v [3]int, c string) bool {
+	// the highest and lowest patch releases of the version
+	high := [3]int{v[0], v[1], 999}
+	low := [3]int{v[0], v[1], 0}
+
+	switch {
+	case c == "*":
+		return true
+	case strings.HasPrefix(c, ">="):
+		min, ok := parse(c[2:])
+		return ok && compare(high, min) >= 0
+	case strings.HasPrefix(c, "<="):
+		max, ok := parse(c[2:])
+		return ok && compare(low, max) <= 0
+	case strings.HasPrefix(c, ">"):
+		min, ok := parse(c[1:])
+		return ok && compare(high, min) > 0
+	case strings.HasPrefix(c, "<"):
+		max, ok := parse(c[1:])
+		return ok && compare(low, max) < 0
+	case strings.HasPrefix(c, "^"):
+		min, ok := parse(c[1:])
+		return ok && compare(high, min) >= 0 && v[0] == min[0]
+	case strings.HasPrefix(c, "~"):
+		min, ok := parse(c[1:])
+		if !ok || compare(high, min) < 0 {
+			return false
+		}
+
+		// ~7.4.1 allows 7.4.x and ~7.4 allows 7.x
+		if strings.Count(c, ".") >= 2 {
+			return v[0] == min[0] && v[1] == min[1]
+		}
+
+		return v[0] == min[0]
+	}
+
+	// exact versions and wildcards (e.g. 7.4.* or 7.4.14) match the major and minor version
+	exact, ok := parse(strings.TrimPrefix(c, "="))
+	if !ok {
+		return false
+	}
+
+	if strings.Count(strings.TrimSuffix(c, ".*"), ".") == 0 {
+		return v[0] == exact[0]
+	}
+
+	return v[0] == exact[0] && v[1] == exact[1]
+}
+
+// parse returns the major, minor, and patch version. Wildcards
+// and missing parts of the version are set to zero.
+func parse(s string) ([3]int, bool) {
+	var v [3]int
+
+	s = strings.TrimPrefix(strings.TrimSpace(s), "v")
+	if s == "" {
+		return v, false
+	}
+
+	for i, p := range strings.SplitN(s, ".", 3) {
+		if p == "*" || p == "x" {
+			break
+		}
+
+		// ignore stability flags (e.g. 8.0.0-RC1)
+		if idx := strings.IndexAny(p, "-@+"); idx >= 0 {
+			p = p[:idx]
+		}
+
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			return v, false
+		}
+
+		v[i] = n
+	}
+
+	return v, true
+}
+
+func compare(a, b [3]int) int {
+	for i := range a {
+		if a[i] != b[i] {
+			if a[i] < b[i] {
+				return -1
+			}
+
+			return 1
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,32 @@
+package projects
+
+import "testing"
+
+func TestPHPVersion(t *testing.T) {
+	versions := []string{"8.0", "7.4", "7.3", "7.2", "7.1", "7.0"}
+
+	tests := []struct {
+		constraint string
+		want       string
+	}{
+		{constraint: "", want: ""},
+		{constraint: "^7.2.5", want: "7.4"},
+		{constraint: "^7.2.5 || ^8.0", want: "8.0"},
+		{constraint: ">=7.2.5", want: "8.0"},
+		{constraint: ">=7.1 <7.4", want: "7.3"},
+		{constraint: ">=7.1,<7.4", want: "7.3"},
+		{constraint: "~7.3.0", want: "7.3"},
+		{constraint: "~7.3", want: "7.4"},
+		{constraint: "7.2.*", want: "7.2"},
+		{constraint: "7.4.14", want: "7.4"},
+		{constraint: "^8.1", want: ""},
+		{constraint: "not-a-version", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.constraint, func(t *testing.T) {
+			if got := PHPVersion(tt.constraint, versions); got != tt.want {
+				t.Errorf("PHPVersion(%q) = %q, want %q", tt.constraint, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package projects
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/craftcms/nitro/pkg/phpversions"
+	"github.com/craftcms/nitro/pkg/webroot"
+)
+
+// maxDepth is the number of directories below the root that are searched for projects.
+const maxDepth = 3
+
+// Project is a Craft project found on the host machine.
+type Project struct {
+	// Path is the absolute path to the project
+	Path string
+
+	// Webroot is the name of the web root directory, it defaults to web
+	Webroot string
+
+	// PHP is the PHP version required by the project, it is empty if the
+	// project does not require a version that nitro supports
+	PHP string
+}
+
+// composer represents the parts of a composer.json file used to detect projects.
+type composer struct {
+	Require map[string]string `json:"require"`
+	Config  struct {
+		Platform map[string]string `json:"platform"`
+	} `json:"config"`
+}
+
+// Find walks the directory and returns the Craft projects it contains. A
+// directory is a Craft project if its composer.json requires craftcms/cms.
+// Dependency directories and the directories inside of a project are not
+// searched.
+func Find(dir string) ([]Project, error) {
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var found []Project
+	err = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			// skip directories that cannot be read
+			if info != nil && info.IsDir() && path != root {
+				return filepath.SkipDir
+			}
+
+			return err
+		}
+
+		if !info.IsDir() {
+			return nil
+		}
+
+		switch info.Name() {
+		case "vendor", "node_modules", ".git":
+			return filepath.SkipDir
+		}
+
+		if c, ok := craft(path); ok {
+			p := Project{Path: path, Webroot: "web"}
+			if w, err := webroot.Find(path); err == nil && w != "" {
+				p.Webroot = w
+			}
+
+			// the platform version is what the dependencies were resolved for
+			constraint := c.Require["php"]
+			if platform := c.Config.Platform["php"]; platform != "" {
+				constraint = platform
+			}
+
+			p.PHP = PHPVersion(constraint, phpversions.Versions)
+
+			found = append(found, p)
+
+			return filepath.SkipDir
+		}
+
+		// limit how deep the walk goes
+		if rel, err := filepath.Rel(root, path); err == nil && rel != "." && depth(rel) >= maxDepth {
+			return filepath.SkipDir
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(found, func(i, j int) bool {
+		return found[i].Path < found[j].Path
+	})
+
+	return found, nil
+}
+
+// IsCraft checks if the directory has a composer.json that requires craftcms/cms.
+func IsCraft(dir string) bool {
+	_, ok := craft(dir)
+
+	return ok
+}
+
+func craft(dir string) (*composer, bool) {
+	b, err := ioutil.ReadFile(filepath.Join(dir, "composer.json"))
+	if err != nil {
+		return nil, false
+	}
+
+	var c composer
+	if err := json.Unmarshal(b, &c); err != nil {
+		return nil, false
+	}
+
+	if _, ok := c.Require["craftcms/cms"]; !ok {
+		return nil, false
+	}
+
+	return &c, true
+}
+
+func depth(rel string) int {
+	n := 1
+	for _, r := range rel {
+		if r == os.PathSeparator {
+			n++
+		}
+	}
+
+	return n
+}
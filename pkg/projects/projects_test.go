@@ -0,0 +1,57 @@
+package projects
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFind(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nitro-projects")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	craft := `{"require": {"craftcms/cms": "^3.6"}}`
+	files := map[string]string{
+		"client-a/composer.json":                            `{"require": {"craftcms/cms": "^3.6", "php": "^7.2.5"}}`,
+		"client-a/vendor/some/package/composer.json":        craft,
+		"clients/client-b/composer.json":                    `{"require": {"craftcms/cms": "^3.6", "php": "^7.2.5 || ^8.0"}, "config": {"platform": {"php": "7.3.27"}}}`,
+		"laravel/composer.json":                             `{"require": {"laravel/framework": "^8.0"}}`,
+		"too/deep/to/find/composer.json":                    craft,
+		"broken/composer.json":                              `{`,
+		"client-a/plugins/some-plugin/composer.json":        craft,
+		"clients/client-b/node_modules/thing/composer.json": craft,
+	}
+	for f, content := range files {
+		path := filepath.Join(dir, f)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// client-b uses a public web root
+	if err := os.MkdirAll(filepath.Join(dir, "clients", "client-b", "public"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Find(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Project{
+		{Path: filepath.Join(dir, "client-a"), Webroot: "web", PHP: "7.4"},
+		{Path: filepath.Join(dir, "clients", "client-b"), Webroot: "public", PHP: "7.3"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Find() = %v, want %v", got, want)
+	}
+}
@@ -4,14 +4,20 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
 
 	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/containerexec"
 	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/database"
+	"github.com/craftcms/nitro/pkg/environment"
+	"github.com/craftcms/nitro/pkg/phpextensions"
 	"github.com/craftcms/nitro/pkg/phpversions"
+	"github.com/craftcms/nitro/pkg/projects"
 	"github.com/craftcms/nitro/pkg/terminal"
 	"github.com/craftcms/nitro/pkg/validate"
 	"github.com/craftcms/nitro/pkg/webroot"
@@ -41,7 +47,7 @@ func CreateDatabase(cmd *cobra.Command, docker client.CommonAPIClient, output te
 
 	// add filters to show only the environment and database containers
 	filter := filters.NewArgs()
-	filter.Add("label", containerlabels.Nitro)
+	filter.Add("label", containerlabels.Nitro+"="+environment.Label())
 	filter.Add("label", containerlabels.Type+"=database")
 
 	// get a list of all the databases
@@ -55,10 +61,8 @@ func CreateDatabase(cmd *cobra.Command, docker client.CommonAPIClient, output te
 		return containers[i].Names[0] < containers[j].Names[0]
 	})
 
-	// get all of the containers as a list
-	var engineOpts []string
+	// start the containers that are not running
 	for _, c := range containers {
-		// start the container if not running
 		if c.State != "running" {
 			for _, command := range cmd.Root().Commands() {
 				if command.Use == "start" {
@@ -68,24 +72,14 @@ func CreateDatabase(cmd *cobra.Command, docker client.CommonAPIClient, output te
 				}
 			}
 		}
-
-		engineOpts = append(engineOpts, strings.TrimLeft(c.Names[0], "/"))
 	}
 
 	// prompt the user for the engine to add the database
-	var containerID, databaseEngine string
-	selected, err := output.Select(os.Stdin, "Select the database engine: ", engineOpts)
+	selected, err := SelectContainer(os.Stdin, "Select the database engine: ", containers, output)
 	if err != nil {
 		return false, "", "", "", "", err
 	}
 
-	// set the container id and db engine
-	containerID = containers[selected].ID
-	databaseEngine = containers[selected].Labels[containerlabels.DatabaseCompatibility]
-	if containerID == "" {
-		return false, "", "", "", "", fmt.Errorf("unable to get the container")
-	}
-
 	// ask the user for the database to create
 	db, err := output.Ask("Enter the new database name", "", ":", &validate.DatabaseName{})
 	if err != nil {
@@ -94,78 +88,13 @@ func CreateDatabase(cmd *cobra.Command, docker client.CommonAPIClient, output te
 
 	output.Pending("creating database", db)
 
-	// set the commands based on the engine type
-	var cmds, privileges []string
-	switch databaseEngine {
-	case "mysql":
-		cmds = []string{"mysql", "-uroot", "-pnitro", fmt.Sprintf(`-e CREATE DATABASE IF NOT EXISTS %s;`, db)}
-		privileges = []string{"mysql", "-uroot", "-pnitro", fmt.Sprintf(`-e GRANT ALL PRIVILEGES ON * TO '%s'@'%s';`, "nitro", "%")}
-	default:
-		cmds = []string{"psql", "--username=nitro", "--host=127.0.0.1", fmt.Sprintf(`-c CREATE DATABASE %s;`, db)}
-	}
-
-	// create the exec
-	e, err := docker.ContainerExecCreate(ctx, containerID, types.ExecConfig{
-		AttachStdout: true,
-		AttachStderr: true,
-		Tty:          false,
-		Cmd:          cmds,
-	})
-	if err != nil {
-		return false, "", "", "", "", err
-	}
-
-	// attach to the container
-	resp, err := docker.ContainerExecAttach(ctx, e.ID, types.ExecStartCheck{
-		Tty: false,
-	})
-	if err != nil {
-		return false, "", "", "", "", err
-	}
-	defer resp.Close()
-
-	// start the exec
-	if err := docker.ContainerExecStart(ctx, e.ID, types.ExecStartCheck{}); err != nil {
-		return false, "", "", "", "", fmt.Errorf("unable to start the container, %w", err)
-	}
-
-	// check if we should grant privileges
-	if privileges != nil {
-		// create the exec
-		e, err := docker.ContainerExecCreate(ctx, containerID, types.ExecConfig{
-			AttachStdout: true,
-			AttachStderr: true,
-			Tty:          false,
-			Cmd:          privileges,
-		})
-		if err != nil {
-			return false, "", "", "", "", err
-		}
-
-		// attach to the container
-		resp, err := docker.ContainerExecAttach(ctx, e.ID, types.ExecStartCheck{
-			Tty: false,
-		})
-		if err != nil {
+	// create the database and grant the nitro user access
+	engine, version := containers[selected].Labels[containerlabels.DatabaseEngine], containers[selected].Labels[containerlabels.DatabaseVersion]
+	for _, c := range database.CreateCommands(engine, version, db) {
+		if _, err := containerexec.Run(ctx, docker, containers[selected].ID, c); err != nil {
+			output.Warning()
 			return false, "", "", "", "", err
 		}
-		defer resp.Close()
-
-		// start the exec
-		if err := docker.ContainerExecStart(ctx, e.ID, types.ExecStartCheck{}); err != nil {
-			return false, "", "", "", "", fmt.Errorf("unable to start the container, %w", err)
-		}
-
-		// wait for the container exec to complete
-		waiting := true
-		for waiting {
-			resp, err := docker.ContainerExecInspect(ctx, e.ID)
-			if err != nil {
-				return false, "", "", "", "", err
-			}
-
-			waiting = resp.Running
-		}
 	}
 
 	output.Done()
@@ -199,12 +128,36 @@ func CreateDatabase(cmd *cobra.Command, docker client.CommonAPIClient, output te
 	return true, hostname, db, port, driver, nil
 }
 
+// SelectContainer asks the user to select one of the containers and returns the index of
+// the selected container. When there is only one container, it is used without a prompt.
+func SelectContainer(in io.Reader, msg string, containers []types.Container, output terminal.Outputer) (int, error) {
+	switch len(containers) {
+	case 0:
+		return 0, fmt.Errorf("there are no database containers, run `nitro db new` to create one")
+	case 1:
+		return 0, nil
+	}
+
+	var opts []string
+	for _, c := range containers {
+		opts = append(opts, strings.TrimLeft(c.Names[0], "/"))
+	}
+
+	return Select(in, msg, opts, output)
+}
+
 // CreateSite takes the users home directory and the site path and walked the user
 // through adding a site to the config.
 func CreateSite(home, dir string, output terminal.Outputer) (*config.Site, error) {
 	// create a new site
 	site := config.Site{}
 
+	// load the config
+	cfg, err := config.Load(home)
+	if err != nil {
+		return nil, err
+	}
+
 	// get the hostname from the directory
 	// p := filepath.Join(dir)
 	sp := strings.Split(filepath.Join(dir), string(os.PathSeparator))
@@ -212,13 +165,7 @@ func CreateSite(home, dir string, output terminal.Outputer) (*config.Site, error
 
 	// append the test domain if there are no periods
 	if !strings.Contains(site.Hostname, ".") {
-		// set the default tld
-		tld := "nitro"
-		if os.Getenv("NITRO_DEFAULT_TLD") != "" {
-			tld = os.Getenv("NITRO_DEFAULT_TLD")
-		}
-
-		site.Hostname = fmt.Sprintf("%s.%s", site.Hostname, tld)
+		site.Hostname = fmt.Sprintf("%s.%s", site.Hostname, cfg.GetTLD())
 	}
 
 	// prompt for the hostname
@@ -262,9 +209,18 @@ func CreateSite(home, dir string, output terminal.Outputer) (*config.Site, error
 
 	output.Success("using web root", site.Webroot)
 
-	// prompt for the php version
+	// prompt for the php version, listing the default version first
 	versions := phpversions.Versions
-	selected, err := output.Select(os.Stdin, "Choose a PHP version: ", versions)
+	if cfg.Defaults.PHP != "" {
+		versions = []string{cfg.Defaults.PHP}
+		for _, v := range phpversions.Versions {
+			if v != cfg.Defaults.PHP {
+				versions = append(versions, v)
+			}
+		}
+	}
+
+	selected, err := Select(os.Stdin, "Choose a PHP version: ", versions, output)
 	if err != nil {
 		return nil, err
 	}
@@ -274,17 +230,49 @@ func CreateSite(home, dir string, output terminal.Outputer) (*config.Site, error
 
 	output.Success("setting PHP version", site.Version)
 
-	// load the config
-	cfg, err := config.Load(home)
+	// prompt for the aliases, which are optional
+	v := validate.MultipleHostnameValidator{Optional: true}
+	aliases, err := output.Ask("Enter the alias domains for the site (use commas to enter multiple, or leave empty)", "", ":", &v)
+	if err != nil {
+		return nil, err
+	}
+
+	site.Aliases, err = v.Parse(aliases)
 	if err != nil {
 		return nil, err
 	}
 
+	for _, a := range site.Aliases {
+		output.Success("adding alias", a)
+	}
+
+	// install the extensions the project requires that are not in the image
+	required, err := projects.Extensions(dir)
+	if err != nil {
+		output.Info("unable to detect the required PHP extensions,", err.Error())
+	}
+
+	install, unsupported := phpextensions.Split(required)
+	for _, ext := range install {
+		site.Extensions = append(site.Extensions, ext)
+
+		output.Success("adding PHP extension", ext)
+	}
+
+	for _, ext := range unsupported {
+		output.Info(fmt.Sprintf("  ✗ the project requires the PHP extension %s, which is not supported", ext))
+	}
+
 	// add the site to the config
 	if err := cfg.AddSite(site); err != nil {
 		return nil, err
 	}
 
+	// make sure the site does not conflict with the other sites before saving
+	if err := cfg.Validate(home); err != nil {
+		return nil, err
+	}
+
 	// save the config file
 	if err := cfg.Save(); err != nil {
 		return nil, err
@@ -0,0 +1,257 @@
+package prompt
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/moby/term"
+
+	"github.com/craftcms/nitro/pkg/terminal"
+)
+
+// ErrCancelled is returned when the selection is cancelled with ctrl+c or escape.
+var ErrCancelled = errors.New("the selection was cancelled")
+
+// maxRows is the number of options shown at once in the interactive prompt, the list
+// scrolls to keep the highlighted option visible.
+const maxRows = 10
+
+// Select asks the user to choose one of the options and returns the index of the selected
+// option. When the input and output are terminals the options can be filtered by typing and
+// chosen with the arrow keys, otherwise the options are shown as a numbered list so piped
+// input and scripts keep working. When there is only one option, it is used without a prompt.
+func Select(in io.Reader, msg string, opts []string, output terminal.Outputer) (int, error) {
+	switch len(opts) {
+	case 0:
+		return 0, fmt.Errorf("there are no options to select from")
+	case 1:
+		return 0, nil
+	}
+
+	fd, isTerminal := term.GetFdInfo(in)
+	if !isTerminal || terminal.JSON || !term.IsTerminal(os.Stdout.Fd()) {
+		selected, err := output.Select(in, msg, opts)
+		if errors.Is(err, io.EOF) {
+			return 0, fmt.Errorf("unable to prompt without input, pass the selection as an argument instead")
+		}
+
+		return selected, err
+	}
+
+	state, err := term.SetRawTerminal(fd)
+	if err != nil {
+		return output.Select(in, msg, opts)
+	}
+	defer term.RestoreTerminal(fd, state)
+
+	return newSelector(msg, opts).run(in, os.Stdout)
+}
+
+// selector is the state of the interactive prompt.
+type selector struct {
+	msg     string
+	opts    []string
+	query   string
+	matches []int
+	cursor  int
+	offset  int
+	lines   int
+}
+
+func newSelector(msg string, opts []string) *selector {
+	s := &selector{msg: strings.TrimSpace(msg), opts: opts}
+	s.filter()
+
+	return s
+}
+
+// run reads the keys from the input until an option is chosen or the prompt is cancelled.
+// The input is expected to be a terminal in raw mode.
+func (s *selector) run(in io.Reader, out io.Writer) (int, error) {
+	rdr := bufio.NewReader(in)
+
+	s.render(out)
+
+	for {
+		r, _, err := rdr.ReadRune()
+		if err != nil {
+			s.clear(out)
+			return 0, err
+		}
+
+		switch r {
+		case '\r', '\n':
+			if len(s.matches) == 0 {
+				continue
+			}
+
+			selected := s.matches[s.cursor]
+
+			s.clear(out)
+			fmt.Fprintf(out, "%s %s\r\n", s.msg, s.opts[selected])
+
+			return selected, nil
+		case 3: // ctrl+c
+			s.clear(out)
+			return 0, ErrCancelled
+		case 27: // escape, or the start of an arrow key
+			if rdr.Buffered() == 0 {
+				s.clear(out)
+				return 0, ErrCancelled
+			}
+
+			seq := make([]byte, 2)
+			if _, err := io.ReadFull(rdr, seq); err != nil {
+				s.clear(out)
+				return 0, err
+			}
+
+			switch string(seq) {
+			case "[A", "OA":
+				s.move(-1)
+			case "[B", "OB":
+				s.move(1)
+			}
+		case 16: // ctrl+p
+			s.move(-1)
+		case 14: // ctrl+n
+			s.move(1)
+		case 127, 8: // backspace
+			if q := []rune(s.query); len(q) > 0 {
+				s.query = string(q[:len(q)-1])
+				s.filter()
+			}
+		case 21: // ctrl+u
+			s.query = ""
+			s.filter()
+		default:
+			if unicode.IsPrint(r) {
+				s.query += string(r)
+				s.filter()
+			}
+		}
+
+		s.clear(out)
+		s.render(out)
+	}
+}
+
+// filter updates the matches for the query and moves the cursor to the best match.
+func (s *selector) filter() {
+	s.matches = Filter(s.query, s.opts)
+	s.cursor, s.offset = 0, 0
+}
+
+// move moves the cursor up or down and scrolls the visible options.
+func (s *selector) move(n int) {
+	if len(s.matches) == 0 {
+		return
+	}
+
+	s.cursor = (s.cursor + n + len(s.matches)) % len(s.matches)
+
+	if s.cursor < s.offset {
+		s.offset = s.cursor
+	}
+
+	if s.cursor >= s.offset+maxRows {
+		s.offset = s.cursor - maxRows + 1
+	}
+}
+
+// render writes the prompt and the visible options, the number of lines is kept so the
+// prompt can be cleared before it is rendered again.
+func (s *selector) render(out io.Writer) {
+	fmt.Fprintf(out, "%s %s\r\n", s.msg, s.query)
+	s.lines = 1
+
+	if len(s.matches) == 0 {
+		fmt.Fprint(out, "  no matches\r\n")
+		s.lines++
+	}
+
+	for i := s.offset; i < len(s.matches) && i < s.offset+maxRows; i++ {
+		prefix := "  "
+		if i == s.cursor {
+			prefix = "> "
+		}
+
+		fmt.Fprintf(out, "%s%s\r\n", prefix, s.opts[s.matches[i]])
+		s.lines++
+	}
+
+	fmt.Fprint(out, "  (type to filter, ↑/↓ to move, enter to select)")
+}
+
+// clear moves the cursor to the start of the prompt and erases it.
+func (s *selector) clear(out io.Writer) {
+	fmt.Fprintf(out, "\r\x1b[%dA\x1b[J", s.lines)
+}
+
+// Filter returns the indexes of the options that contain the characters of the query in
+// order, ignoring case. The options are sorted so consecutive characters and matches at
+// the start of an option come first, options that score the same keep their order. An
+// empty query matches every option.
+func Filter(query string, opts []string) []int {
+	type match struct {
+		index int
+		score int
+	}
+
+	q := []rune(strings.ToLower(query))
+
+	var matches []match
+	for i, o := range opts {
+		if score, ok := fuzzy(q, []rune(strings.ToLower(o))); ok {
+			matches = append(matches, match{index: i, score: score})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].score > matches[j].score
+	})
+
+	indexes := []int{}
+	for _, m := range matches {
+		indexes = append(indexes, m.index)
+	}
+
+	return indexes
+}
+
+// fuzzy returns the score of the option and true when the option contains the characters
+// of the query in order.
+func fuzzy(query, option []rune) (int, bool) {
+	score, last, i := 0, -1, 0
+	for pos, r := range option {
+		if i == len(query) {
+			break
+		}
+
+		if r != query[i] {
+			continue
+		}
+
+		switch {
+		case pos == 0:
+			score += 3
+		case pos == last+1:
+			score += 2
+		case !unicode.IsLetter(option[pos-1]) && !unicode.IsDigit(option[pos-1]):
+			score += 2
+		default:
+			score++
+		}
+
+		last = pos
+		i++
+	}
+
+	return score, i == len(query)
+}
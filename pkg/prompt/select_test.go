@@ -0,0 +1,92 @@
+package prompt
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/craftcms/nitro/pkg/terminal"
+)
+
+func TestFilter(t *testing.T) {
+	opts := []string{"craft-dev.nitro", "tutorial.nitro", "demo.nitro", "crafted.nitro"}
+
+	tests := []struct {
+		name  string
+		query string
+		want  []int
+	}{
+		{name: "empty queries match every option", query: "", want: []int{0, 1, 2, 3}},
+		{name: "prefixes are matched first", query: "craft", want: []int{0, 3}},
+		{name: "characters in order are matched", query: "tnit", want: []int{1, 0, 3}},
+		{name: "case is ignored", query: "DEMO", want: []int{2}},
+		{name: "consecutive characters rank higher", query: "tut", want: []int{1}},
+		{name: "queries without matches return no options", query: "xyz", want: []int{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Filter(tt.query, opts); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Filter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSelector_run(t *testing.T) {
+	opts := []string{"craft-dev.nitro", "tutorial.nitro", "demo.nitro"}
+
+	tests := []struct {
+		name    string
+		keys    string
+		want    int
+		wantErr error
+	}{
+		{name: "enter selects the first option", keys: "\r", want: 0},
+		{name: "arrow keys move the cursor", keys: "\x1b[B\x1b[B\x1b[A\r", want: 1},
+		{name: "the cursor wraps around", keys: "\x1b[A\r", want: 2},
+		{name: "typing filters the options", keys: "demo\r", want: 2},
+		{name: "backspace removes from the query", keys: "tx\x7fu\r", want: 1},
+		{name: "enter is ignored without matches", keys: "xyz\x15\x0e\r", want: 1},
+		{name: "ctrl+c cancels the selection", keys: "\x03", wantErr: ErrCancelled},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+
+			got, err := newSelector("Select a site:", opts).run(strings.NewReader(tt.keys), out)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("run() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSelect(t *testing.T) {
+	output := terminal.New()
+
+	// one option is selected without a prompt
+	got, err := Select(strings.NewReader(""), "Select a site:", []string{"craft-dev.nitro"}, output)
+	if err != nil || got != 0 {
+		t.Errorf("expected the only option to be selected, got %d, %v", got, err)
+	}
+
+	// the input is not a terminal so the options are a numbered list
+	got, err = Select(strings.NewReader("2\n"), "Select a site:", []string{"craft-dev.nitro", "demo.nitro"}, output)
+	if err != nil || got != 1 {
+		t.Errorf("expected the second option to be selected, got %d, %v", got, err)
+	}
+
+	if _, err := Select(strings.NewReader(""), "Select a site:", []string{"craft-dev.nitro", "demo.nitro"}, output); err == nil {
+		t.Errorf("expected an error without input")
+	}
+
+	if _, err := Select(strings.NewReader(""), "Select a site:", nil, output); err == nil {
+		t.Errorf("expected an error without options")
+	}
+}
@@ -1,16 +1,22 @@
 package proxycontainer
 
 import (
-	"bytes"
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
+	"runtime"
+	"time"
 
 	volumetypes "github.com/docker/docker/api/types/volume"
 
 	"github.com/craftcms/nitro/command/version"
+	"github.com/craftcms/nitro/pkg/config"
 	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/environment"
+	"github.com/craftcms/nitro/pkg/imagepull"
 	"github.com/craftcms/nitro/pkg/terminal"
+	"github.com/craftcms/nitro/pkg/wsl"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/filters"
@@ -24,20 +30,19 @@ var (
 	// ProxyImage is the docker hub image with the current CLI version
 	ProxyImage = fmt.Sprintf("craftcms/nitro-proxy:%s", version.Version)
 
-	// ProxyName is the name of the proxy container (e.g. nitro-proxy)
-	ProxyName = "nitro-proxy"
-
 	// ErrNoProxyContainer is returned when the proxy container is not found
 	ErrNoProxyContainer = fmt.Errorf("unable to locate the proxy container")
 )
 
-// Create is used to create a new proxy container for the nitro development environment.
-func Create(ctx context.Context, docker client.CommonAPIClient, output terminal.Outputer, networkID string) error {
+// Create is used to create a new proxy container for the nitro development environment. The dir is
+// the nitro directory with the config, it is used to label the proxy so nitro on the other side of
+// WSL does not use the proxy with a different config.
+func Create(ctx context.Context, docker client.CommonAPIClient, output terminal.Outputer, networkID, dir string) error {
 	if ctx == nil {
 		ctx = context.Background()
 	}
 	filter := filters.NewArgs()
-	filter.Add("label", containerlabels.Nitro+"=true")
+	filter.Add("label", containerlabels.Nitro+"="+environment.Label())
 	filter.Add("reference", ProxyImage)
 
 	// check for the proxy image
@@ -50,14 +55,9 @@ func Create(ctx context.Context, docker client.CommonAPIClient, output terminal.
 	if len(images) == 0 && os.Getenv("NITRO_DEVELOPMENT") != "true" {
 		output.Pending("pulling image")
 
-		rdr, err := docker.ImagePull(ctx, ProxyImage, types.ImagePullOptions{All: false})
-		if err != nil {
-			return fmt.Errorf("unable to pull the nitro-proxy from docker hub, %w", err)
-		}
-
-		buf := &bytes.Buffer{}
-		if _, err := buf.ReadFrom(rdr); err != nil {
-			return fmt.Errorf("unable to read the output from pulling the image, %w", err)
+		if err := imagepull.Pull(ctx, docker, ProxyImage, output); err != nil {
+			output.Warning()
+			return err
 		}
 
 		output.Done()
@@ -71,11 +71,11 @@ func Create(ctx context.Context, docker client.CommonAPIClient, output terminal.
 	}
 
 	// since the filter is fuzzy, do an exact match (e.g. filtering for
-	// `nitro-dev` will also return `nitro-dev-host`
+	// `nitro-network` will also return `nitro-network-host`
 	var skipVolume bool
 	var volume *types.Volume
 	for _, v := range volumes.Volumes {
-		if v.Name == "nitro" {
+		if v.Name == environment.Volume() {
 			skipVolume = true
 			volume = v
 		}
@@ -91,10 +91,10 @@ func Create(ctx context.Context, docker client.CommonAPIClient, output terminal.
 		// create a volume with the same name of the machine
 		resp, err := docker.VolumeCreate(ctx, volumetypes.VolumeCreateBody{
 			Driver: "local",
-			Name:   "nitro",
+			Name:   environment.Volume(),
 			Labels: map[string]string{
-				containerlabels.Nitro:  "true",
-				containerlabels.Volume: "nitro",
+				containerlabels.Nitro:  environment.Label(),
+				containerlabels.Volume: environment.Volume(),
 			},
 		})
 		if err != nil {
@@ -121,7 +121,11 @@ func Create(ctx context.Context, docker client.CommonAPIClient, output terminal.
 	// check the containers and verify its running
 	for _, c := range containers {
 		for _, n := range c.Names {
-			if n == "nitro-proxy" || n == "/nitro-proxy" {
+			if n == environment.Proxy() || n == "/"+environment.Proxy() {
+				if err := VerifyPlatform(c, dir); err != nil {
+					return err
+				}
+
 				// check if it is running
 				if c.State != "running" {
 					if err := docker.ContainerStart(ctx, c.ID, types.ContainerStartOptions{}); err != nil {
@@ -140,34 +144,19 @@ func Create(ctx context.Context, docker client.CommonAPIClient, output terminal.
 	output.Pending("creating proxy")
 
 	// check for a custom HTTP port
-	httpPort := "80"
-	if _, defined := os.LookupEnv("NITRO_HTTP_PORT"); defined {
-		httpPort = os.Getenv("NITRO_HTTP_PORT")
-	}
+	httpPort := environment.Port("NITRO_HTTP_PORT", environment.Ports.HTTP)
 
 	// check for a custom HTTPS port
-	httpsPort := "443"
-	if _, defined := os.LookupEnv("NITRO_HTTPS_PORT"); defined {
-		httpsPort = os.Getenv("NITRO_HTTPS_PORT")
-	}
+	httpsPort := environment.Port("NITRO_HTTPS_PORT", environment.Ports.HTTPS)
 
 	// check for a custom API port
-	apiPort := "5000"
-	if _, defined := os.LookupEnv("NITRO_API_PORT"); defined {
-		apiPort = os.Getenv("NITRO_API_PORT")
-	}
+	apiPort := environment.Port("NITRO_API_PORT", environment.Ports.API)
 
 	// check the first node port
-	nodePort := "3000"
-	if _, defined := os.LookupEnv("NITRO_NODE_PORT"); defined {
-		nodePort = os.Getenv("NITRO_NODE_PORT")
-	}
+	nodePort := environment.Port("NITRO_NODE_PORT", environment.Ports.Node)
 
 	// check the second node port
-	altNodePort := "3001"
-	if _, defined := os.LookupEnv("NITRO_ALT_NODE_PORT"); defined {
-		altNodePort = os.Getenv("NITRO_ALT_NODE_PORT")
-	}
+	altNodePort := environment.Port("NITRO_ALT_NODE_PORT", environment.Ports.AltNode)
 
 	httpPortNat, err := nat.NewPort("tcp", "80")
 	if err != nil {
@@ -194,6 +183,19 @@ func Create(ctx context.Context, docker client.CommonAPIClient, output terminal.
 		return fmt.Errorf("unable to set the second node port, %w", err)
 	}
 
+	labels := map[string]string{
+		containerlabels.Nitro:        environment.Label(),
+		containerlabels.Type:         "proxy",
+		containerlabels.Proxy:        "true",
+		containerlabels.ProxyVersion: version.Version,
+	}
+
+	// docker desktop is shared by WSL and windows, so label the proxy with the side and config that created it
+	if wsl.IsShared(wsl.Platform()) {
+		labels[containerlabels.Platform] = wsl.Platform()
+		labels[containerlabels.ConfigDir] = wsl.ToLinux(dir)
+	}
+
 	// create a container
 	resp, err := docker.ContainerCreate(ctx,
 		&container.Config{
@@ -205,16 +207,13 @@ func Create(ctx context.Context, docker client.CommonAPIClient, output terminal.
 				nodePortNat:    struct{}{},
 				altNodePortNat: struct{}{},
 			},
-			Labels: map[string]string{
-				containerlabels.Nitro:        "true",
-				containerlabels.Type:         "proxy",
-				containerlabels.Proxy:        "true",
-				containerlabels.ProxyVersion: version.Version,
-			},
-			Env: []string{"PGPASSWORD=nitro", "PGUSER=nitro", "NITRO_VERSION=" + version.Version},
+			Labels:      labels,
+			Env:         []string{"PGPASSWORD=nitro", "PGUSER=nitro", "NITRO_VERSION=" + version.Version},
+			Healthcheck: HealthCheck(),
 		},
 		&container.HostConfig{
 			NetworkMode: "default",
+			ExtraHosts:  ExtraHosts(),
 			Mounts: []mount.Mount{
 				{
 					Type:   mount.TypeVolume,
@@ -257,13 +256,13 @@ func Create(ctx context.Context, docker client.CommonAPIClient, output terminal.
 		},
 		&network.NetworkingConfig{
 			EndpointsConfig: map[string]*network.EndpointSettings{
-				"nitro-network": {
+				environment.Network(): {
 					NetworkID: networkID,
 				},
 			},
 		},
 		nil,
-		ProxyName,
+		environment.Proxy(),
 	)
 	if err != nil {
 		return fmt.Errorf("unable to create proxy container: %s\n%w", ProxyImage, err)
@@ -278,6 +277,18 @@ func Create(ctx context.Context, docker client.CommonAPIClient, output terminal.
 	return nil
 }
 
+// HealthCheck returns the health check for the proxy container, docker requests the config
+// from the caddy admin API that nitrod uses to update the routes.
+func HealthCheck() *container.HealthConfig {
+	return &container.HealthConfig{
+		Test:        []string{"CMD-SHELL", "wget --quiet --output-document=/dev/null http://127.0.0.1:2019/config/ || exit 1"},
+		Interval:    5 * time.Second,
+		Timeout:     5 * time.Second,
+		StartPeriod: 5 * time.Second,
+		Retries:     6,
+	}
+}
+
 // FindAndStart will look for the proxy container and verify the container is started. It will return the
 // ErrNoProxyContainer error if it is unable to locate the proxy container. It is NOT responsible for
 // creating the proxy container as that is handled in the initialize package.
@@ -294,7 +305,7 @@ func FindAndStart(ctx context.Context, docker client.ContainerAPIClient) (types.
 
 	for _, c := range containers {
 		for _, n := range c.Names {
-			if n == "nitro-proxy" || n == "/nitro-proxy" {
+			if n == environment.Proxy() || n == "/"+environment.Proxy() {
 				// check if it is running
 				if c.State != "running" {
 					if err := docker.ContainerStart(ctx, c.ID, types.ContainerStartOptions{}); err != nil {
@@ -310,3 +321,36 @@ func FindAndStart(ctx context.Context, docker client.ContainerAPIClient) (types.
 
 	return types.Container{}, ErrNoProxyContainer
 }
+
+// VerifyPlatform returns an error when the proxy container was created by nitro on the other side
+// of WSL with a different config. Docker Desktop shares the backend with WSL and windows, so both
+// would use the same proxy and containers with their own config. Proxies created before the
+// platform label was added are not checked.
+func VerifyPlatform(c types.Container, dir string) error {
+	platform, ok := c.Labels[containerlabels.Platform]
+	if !ok || platform == wsl.Platform() || !wsl.IsShared(platform) || !wsl.IsShared(wsl.Platform()) {
+		return nil
+	}
+
+	// the config dir is labeled as the path from WSL so it is the same for both
+	shared := c.Labels[containerlabels.ConfigDir]
+	if shared == wsl.ToLinux(dir) {
+		return nil
+	}
+
+	return fmt.Errorf(
+		"the proxy %s was created by nitro on %s with the config in %s\nset %s=%s to share the config, or use another environment with --environment",
+		environment.Proxy(), platform, shared, environment.HomeEnvVar, filepath.Dir(wsl.Path(shared)),
+	)
+}
+
+// ExtraHosts returns the hosts added to the proxy container so host routes can connect
+// to the host machine. Docker Desktop provides the host gateway, but Docker on linux
+// requires the host to be added.
+func ExtraHosts() []string {
+	if runtime.GOOS == "linux" && !wsl.IsWSL() {
+		return []string{config.HostGateway + ":host-gateway"}
+	}
+
+	return nil
+}
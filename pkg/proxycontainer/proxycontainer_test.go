@@ -0,0 +1,76 @@
+package proxycontainer
+
+import (
+	"os"
+	"runtime"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+
+	"github.com/craftcms/nitro/pkg/containerlabels"
+)
+
+func TestVerifyPlatform(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("the platform is only wsl on linux")
+	}
+
+	tests := []struct {
+		name    string
+		wsl     bool
+		labels  map[string]string
+		dir     string
+		wantErr bool
+	}{
+		{
+			name:   "proxies without the platform label are not checked",
+			wsl:    true,
+			labels: map[string]string{containerlabels.Proxy: "true"},
+			dir:    "/home/oli/.nitro",
+		},
+		{
+			name:    "proxies created on windows with another config return an error in wsl",
+			wsl:     true,
+			labels:  map[string]string{containerlabels.Platform: "windows", containerlabels.ConfigDir: "/mnt/c/Users/oli/.nitro"},
+			dir:     "/home/oli/.nitro",
+			wantErr: true,
+		},
+		{
+			name:   "proxies created on windows with the shared config are used in wsl",
+			wsl:    true,
+			labels: map[string]string{containerlabels.Platform: "windows", containerlabels.ConfigDir: "/mnt/c/Users/oli/.nitro"},
+			dir:    `C:\Users\oli\.nitro`,
+		},
+		{
+			name:   "proxies created in wsl are used in wsl",
+			wsl:    true,
+			labels: map[string]string{containerlabels.Platform: "wsl", containerlabels.ConfigDir: "/home/oli/.nitro"},
+			dir:    "/home/oli/other/.nitro",
+		},
+		{
+			name:   "proxies created on windows are not checked on linux",
+			labels: map[string]string{containerlabels.Platform: "windows", containerlabels.ConfigDir: "/mnt/c/Users/oli/.nitro"},
+			dir:    "/home/oli/.nitro",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, e := range []string{"WSL_DISTRO_NAME", "WSLENV"} {
+				if v, ok := os.LookupEnv(e); ok {
+					defer os.Setenv(e, v)
+				}
+				os.Unsetenv(e)
+			}
+
+			if tt.wsl {
+				os.Setenv("WSL_DISTRO_NAME", "Ubuntu")
+				defer os.Unsetenv("WSL_DISTRO_NAME")
+			}
+
+			err := VerifyPlatform(types.Container{Labels: tt.labels}, tt.dir)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("VerifyPlatform() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package proxycontainer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
+
+	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/protob"
+)
+
+var (
+	// Timeout is how long to wait for the proxy to be ready before giving up
+	Timeout = 2 * time.Minute
+
+	// ErrNotReady is returned when the proxy is not ready before the timeout
+	ErrNotReady = fmt.Errorf("the proxy is not ready")
+
+	// pollInterval is how long to wait between each readiness check
+	pollInterval = 500 * time.Millisecond
+
+	// logLines is the number of lines from the proxy logs to include with errors
+	logLines = "25"
+)
+
+// Ping waits for the nitrod API in the proxy container to respond to a ping request. It
+// returns ErrNotReady if the API does not respond before the timeout.
+func Ping(ctx context.Context, nitrod protob.NitroClient, timeout time.Duration) error {
+	return poll(ctx, timeout, func(ctx context.Context) error {
+		_, err := nitrod.Ping(ctx, &protob.PingRequest{})
+		return err
+	})
+}
+
+// WaitForAPI waits for the nitrod API in the proxy container to be ready. If the API is
+// not ready before the timeout, the last lines of the proxy container logs are added to
+// the error to help diagnose why the proxy did not start.
+func WaitForAPI(ctx context.Context, docker client.ContainerAPIClient, nitrod protob.NitroClient, timeout time.Duration) error {
+	if err := Ping(ctx, nitrod, timeout); err != nil {
+		return withLogs(ctx, docker, err)
+	}
+
+	return nil
+}
+
+// WaitForPath waits for the file at path to exist in the container. If the file does not
+// exist before the timeout, the last lines of the proxy container logs are added to the error.
+func WaitForPath(ctx context.Context, docker client.ContainerAPIClient, containerID, path string, timeout time.Duration) error {
+	err := poll(ctx, timeout, func(ctx context.Context) error {
+		stat, err := docker.ContainerStatPath(ctx, containerID, path)
+		if err != nil {
+			return err
+		}
+
+		if stat.Name == "" {
+			return fmt.Errorf("unable to find %s", path)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return withLogs(ctx, docker, err)
+	}
+
+	return nil
+}
+
+// Logs returns the last lines of the logs from the proxy container.
+func Logs(ctx context.Context, docker client.ContainerAPIClient) (string, error) {
+	// find the proxy container
+	filter := filters.NewArgs()
+	filter.Add("label", containerlabels.Type+"=proxy")
+
+	containers, err := docker.ContainerList(ctx, types.ContainerListOptions{Filters: filter, All: true})
+	if err != nil {
+		return "", fmt.Errorf("unable to list the containers: %w", err)
+	}
+
+	if len(containers) == 0 {
+		return "", ErrNoProxyContainer
+	}
+
+	rdr, err := docker.ContainerLogs(ctx, containers[0].ID, types.ContainerLogsOptions{ShowStdout: true, ShowStderr: true, Tail: logLines})
+	if err != nil {
+		return "", fmt.Errorf("unable to get the proxy logs: %w", err)
+	}
+	defer rdr.Close()
+
+	// the proxy does not use a tty, so the output is multiplexed
+	buf := &bytes.Buffer{}
+	if _, err := stdcopy.StdCopy(buf, buf, rdr); err != nil {
+		return "", fmt.Errorf("unable to read the proxy logs: %w", err)
+	}
+
+	return strings.TrimSpace(buf.String()), nil
+}
+
+// poll calls fn until it succeeds, the timeout is reached, or the context is canceled.
+func poll(ctx context.Context, timeout time.Duration, fn func(ctx context.Context) error) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	var last error
+	for {
+		if last = fn(ctx); last == nil {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w after %s: %v", ErrNotReady, timeout, last)
+		case <-ticker.C:
+		}
+	}
+}
+
+// withLogs adds the proxy container logs to the error, when they are available.
+func withLogs(ctx context.Context, docker client.ContainerAPIClient, err error) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	logs, lerr := Logs(ctx, docker)
+	if lerr != nil || logs == "" {
+		return err
+	}
+
+	return fmt.Errorf("%w\n\nproxy logs:\n%s", err, logs)
+}
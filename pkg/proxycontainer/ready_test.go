@@ -0,0 +1,107 @@
+package proxycontainer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
+	"google.golang.org/grpc"
+
+	"github.com/craftcms/nitro/protob"
+)
+
+func TestWaitForAPI(t *testing.T) {
+	pollInterval = time.Millisecond
+	defer func() { pollInterval = 500 * time.Millisecond }()
+
+	tests := []struct {
+		name      string
+		failures  int
+		timeout   time.Duration
+		wantErr   bool
+		wantLogs  bool
+		wantPings int
+	}{
+		{
+			name:      "returns once the api responds",
+			failures:  3,
+			timeout:   time.Second,
+			wantPings: 4,
+		},
+		{
+			name:     "returns the proxy logs when the api never responds",
+			failures: -1,
+			timeout:  20 * time.Millisecond,
+			wantErr:  true,
+			wantLogs: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nitrod := &mockNitrod{failures: tt.failures}
+			docker := &mockLogsClient{logs: "caddy failed to bind to :443"}
+
+			err := WaitForAPI(context.Background(), docker, nitrod, tt.timeout)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("WaitForAPI() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr && !errors.Is(err, ErrNotReady) {
+				t.Errorf("expected the error to be ErrNotReady, got %v", err)
+			}
+
+			if tt.wantLogs && !strings.Contains(err.Error(), "caddy failed to bind to :443") {
+				t.Errorf("expected the error to include the proxy logs, got %v", err)
+			}
+
+			if tt.wantPings != 0 && nitrod.pings != tt.wantPings {
+				t.Errorf("expected %d pings, got %d", tt.wantPings, nitrod.pings)
+			}
+		})
+	}
+}
+
+type mockNitrod struct {
+	protob.NitroClient
+
+	failures int
+	pings    int
+}
+
+func (m *mockNitrod) Ping(ctx context.Context, in *protob.PingRequest, opts ...grpc.CallOption) (*protob.PingResponse, error) {
+	m.pings++
+
+	if m.failures < 0 || m.pings <= m.failures {
+		return nil, fmt.Errorf("connection refused")
+	}
+
+	return &protob.PingResponse{Pong: "pong"}, nil
+}
+
+type mockLogsClient struct {
+	client.ContainerAPIClient
+
+	logs string
+}
+
+func (c *mockLogsClient) ContainerList(ctx context.Context, options types.ContainerListOptions) ([]types.Container, error) {
+	return []types.Container{{ID: "proxy", Names: []string{"/nitro-proxy"}}}, nil
+}
+
+func (c *mockLogsClient) ContainerLogs(ctx context.Context, container string, options types.ContainerLogsOptions) (io.ReadCloser, error) {
+	buf := &bytes.Buffer{}
+	if _, err := stdcopy.NewStdWriter(buf, stdcopy.Stderr).Write([]byte(c.logs + "\n")); err != nil {
+		return nil, err
+	}
+
+	return ioutil.NopCloser(buf), nil
+}
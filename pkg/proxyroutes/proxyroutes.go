@@ -0,0 +1,236 @@
+package proxyroutes
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/proxycontainer"
+	"github.com/craftcms/nitro/pkg/svc/blackfire"
+	"github.com/craftcms/nitro/pkg/svc/dynamodb"
+	"github.com/craftcms/nitro/pkg/svc/gotenberg"
+	"github.com/craftcms/nitro/pkg/svc/mailhog"
+	"github.com/craftcms/nitro/pkg/svc/minio"
+	"github.com/craftcms/nitro/pkg/svc/redis"
+	"github.com/craftcms/nitro/pkg/svc/redisinsight"
+	"github.com/craftcms/nitro/protob"
+)
+
+// service is a service shown on the dashboard, services with a web interface are linked.
+type service struct {
+	Name string
+	Host string
+	Port int
+	Web  bool
+}
+
+// services are the services shown on the dashboard when they are enabled, the port is
+// the port inside of the container.
+var services = []service{
+	{Name: "blackfire", Host: blackfire.Host, Port: 8307},
+	{Name: "dynamodb", Host: dynamodb.Host, Port: 8000},
+	{Name: "gotenberg", Host: gotenberg.Host, Port: 3000},
+	{Name: "mailhog", Host: mailhog.Host, Port: 8025, Web: true},
+	{Name: "minio", Host: minio.Host, Port: 9000, Web: true},
+	{Name: "redis", Host: redis.Host, Port: 6379},
+	{Name: "redisinsight", Host: redisinsight.Host, Port: redisinsight.Port, Web: true},
+}
+
+// Sites takes the config and returns the sites, services, custom containers, and host
+// routes the proxy should route requests to. The key is the hostname of the container
+// the requests are sent to.
+func Sites(cfg *config.Config) map[string]*protob.Site {
+	// convert the sites into the gRPC API Apply request
+	sites := make(map[string]*protob.Site)
+	for _, s := range cfg.Sites {
+		// create the site
+		sites[s.Hostname] = &protob.Site{
+			Hostname:     s.Hostname,
+			Aliases:      strings.Join(s.Aliases, ","),
+			Port:         8080,
+			SlowRequests: int32(s.SlowRequests),
+		}
+
+		// nginx listens on a separate port for HTTP/2
+		if s.Nginx.UsesHTTP2() {
+			sites[s.Hostname].Port = config.NginxHTTP2Port
+			sites[s.Hostname].Http2 = true
+		}
+
+		// serve the site at the path on the hostname of another site
+		if host, prefix, err := s.GetRoute(); err == nil && host != "" {
+			sites[s.Hostname].Route = host + prefix
+		}
+
+		// add the debug headers, the container name is the hostname
+		if cfg.Proxy.DebugHeaders {
+			sites[s.Hostname].Headers = map[string]string{
+				"X-Nitro-Site":        s.Hostname,
+				"X-Nitro-Php-Version": s.Version,
+				"X-Nitro-Container":   s.Hostname,
+			}
+		}
+	}
+
+	// check the mailhog service, the web interface is served on a friendly hostname
+	if cfg.Services.Mailhog {
+		sites[mailhog.Host] = &protob.Site{
+			Hostname: mailhog.Host,
+			Aliases:  cfg.MailHostname(),
+			Port:     8025,
+		}
+	}
+
+	// check the minio service
+	if cfg.Services.Minio {
+		sites["minio.service.nitro"] = &protob.Site{
+			Hostname: "minio.service.nitro",
+			Port:     9000,
+		}
+	}
+
+	// the companion web interfaces are served on a friendly hostname (e.g. redisinsight.nitro)
+	if cfg.Services.IsEnabled("redisinsight") {
+		sites[redisinsight.Host] = &protob.Site{
+			Hostname: redisinsight.Host,
+			Aliases:  cfg.UIHostname("redisinsight"),
+			Port:     redisinsight.Port,
+		}
+	}
+
+	// add any custom containers that need to be proxied
+	for _, c := range cfg.Containers {
+		if c.WebGui != 0 {
+			sites[fmt.Sprintf("%s.containers.nitro", c.Name)] = &protob.Site{
+				Hostname: fmt.Sprintf("%s.containers.nitro", c.Name),
+				Port:     int32(c.WebGui),
+			}
+
+			// balance the requests between the replicas
+			if c.GetReplicas() > 1 {
+				sites[fmt.Sprintf("%s.containers.nitro", c.Name)].Upstreams = c.GetHostnames()
+			}
+		}
+	}
+
+	// add the applications running on the host, invalid routes are reported by apply
+	routes, _ := cfg.GetHostRoutes()
+	for hostname, r := range routes {
+		sites[hostname] = &protob.Site{
+			Hostname:  hostname,
+			Port:      int32(r.Port),
+			Upstreams: []string{r.Host},
+		}
+	}
+
+	return sites
+}
+
+// Dashboard takes the config and returns the sites, services, databases, and custom
+// containers shown on the dashboard. It returns nil when the dashboard is not enabled.
+func Dashboard(cfg *config.Config) *protob.Dashboard {
+	hostname := cfg.DashboardHostname()
+	if hostname == "" {
+		return nil
+	}
+
+	dashboard := &protob.Dashboard{Hostname: hostname}
+
+	for _, s := range cfg.Sites {
+		port := 8080
+		if s.Nginx.UsesHTTP2() {
+			port = config.NginxHTTP2Port
+		}
+
+		dashboard.Items = append(dashboard.Items, &protob.DashboardItem{
+			Name:    s.Hostname,
+			Kind:    "site",
+			Url:     "https://" + s.Hostname,
+			Details: "PHP " + s.Version,
+			Address: fmt.Sprintf("%s:%d", s.Hostname, port),
+		})
+	}
+
+	for _, s := range services {
+		if !cfg.Services.IsEnabled(s.Name) {
+			continue
+		}
+
+		item := &protob.DashboardItem{Name: s.Name, Kind: "service", Details: s.Host, Address: fmt.Sprintf("%s:%d", s.Host, s.Port)}
+		if s.Web {
+			item.Url = "https://" + s.Host
+		}
+
+		if s.Name == "mailhog" {
+			item.Url = "https://" + cfg.MailHostname()
+		}
+
+		if h := cfg.UIHostname(s.Name); h != "" {
+			item.Url = "https://" + h
+		}
+
+		dashboard.Items = append(dashboard.Items, item)
+	}
+
+	for _, db := range cfg.Databases {
+		h, err := db.GetHostname()
+		if err != nil {
+			continue
+		}
+
+		port := "3306"
+		if db.Engine == "postgres" {
+			port = "5432"
+		}
+
+		dashboard.Items = append(dashboard.Items, &protob.DashboardItem{
+			Name:    h,
+			Kind:    "database",
+			Details: fmt.Sprintf("%s %s on port %s", db.Engine, db.Version, db.Port),
+			Address: h + ":" + port,
+		})
+	}
+
+	for _, c := range cfg.Containers {
+		item := &protob.DashboardItem{Name: c.Name, Kind: "container", Details: c.Image}
+		if c.Tag != "" {
+			item.Details += ":" + c.Tag
+		}
+
+		if c.WebGui != 0 {
+			item.Url = fmt.Sprintf("https://%s.containers.nitro", c.Name)
+			item.Address = fmt.Sprintf("%s:%d", c.GetReplicaHostname(1), c.WebGui)
+		}
+
+		dashboard.Items = append(dashboard.Items, item)
+	}
+
+	return dashboard
+}
+
+// Update waits for the nitrod API to be ready and replaces the routes
+// in the proxy with the sites and the dashboard.
+func Update(ctx context.Context, nitrod protob.NitroClient, sites map[string]*protob.Site, dashboard *protob.Dashboard) error {
+	// if there are no sites, we are done
+	if len(sites) == 0 && dashboard == nil {
+		return nil
+	}
+
+	// wait for the api to be ready
+	if err := proxycontainer.Ping(ctx, nitrod, proxycontainer.Timeout); err != nil {
+		return err
+	}
+
+	// configure the proxy with the sites
+	resp, err := nitrod.Apply(ctx, &protob.ApplyRequest{Sites: sites, Dashboard: dashboard})
+	if err != nil {
+		return err
+	}
+
+	if resp.Error {
+		return fmt.Errorf("unable to update the proxy, %s", resp.GetMessage())
+	}
+
+	return nil
+}
@@ -0,0 +1,116 @@
+package reconcile
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+
+	"github.com/craftcms/nitro/pkg/containerlabels"
+)
+
+// Containers takes a list of existing containers and makes sure each one can be used by
+// apply. If apply was stopped before it could finish, containers can be left created but
+// not started or missing the endpoint for the network. Those containers are started and
+// reconnected to the network. Containers that are half-created or dead are removed so the
+// caller can create a new container, any other error (e.g. a port that is in use) is
+// returned without removing the container. The returned list only includes containers
+// that are running and attached to the network.
+func Containers(ctx context.Context, docker client.CommonAPIClient, networkID string, containers []types.Container) ([]types.Container, error) {
+	var adopted []types.Container
+	for _, c := range containers {
+		ok, err := Container(ctx, docker, networkID, c)
+		if err != nil {
+			return nil, err
+		}
+
+		if ok {
+			c.State = "running"
+			adopted = append(adopted, c)
+		}
+	}
+
+	return adopted, nil
+}
+
+// Container makes sure a single container is running and attached to the network. It
+// returns false when the container was removed because it could not be recovered.
+func Container(ctx context.Context, docker client.CommonAPIClient, networkID string, c types.Container) (bool, error) {
+	// containers that are dead or being removed cannot be started
+	switch c.State {
+	case "dead", "removing":
+		return false, remove(ctx, docker, c)
+	}
+
+	if halfCreated(networkID, c) {
+		return false, remove(ctx, docker, c)
+	}
+
+	// make sure the container has an endpoint on the network
+	if networkID != "" && !connected(networkID, c) {
+		if err := docker.NetworkConnect(ctx, networkID, c.ID, nil); err != nil {
+			return false, fmt.Errorf("unable to connect the container %s to the network, %w", name(c), err)
+		}
+	}
+
+	if c.State == "running" {
+		return true, nil
+	}
+
+	// start containers that were created but never started or have stopped
+	if err := docker.ContainerStart(ctx, c.ID, types.ContainerStartOptions{}); err != nil {
+		return false, fmt.Errorf("unable to start the container %s, %w", name(c), err)
+	}
+
+	return true, nil
+}
+
+// halfCreated returns true for the containers apply was stopped in the middle of creating,
+// they were never started and are missing the network or the labels apply sets.
+func halfCreated(networkID string, c types.Container) bool {
+	if c.State != "created" {
+		return false
+	}
+
+	if c.Labels[containerlabels.Nitro] == "" {
+		return true
+	}
+
+	return networkID != "" && !connected(networkID, c)
+}
+
+// connected returns true when the container has an endpoint on the network, containers
+// without network settings are treated as connected as the endpoints are unknown.
+func connected(networkID string, c types.Container) bool {
+	if c.NetworkSettings == nil {
+		return true
+	}
+
+	for _, n := range c.NetworkSettings.Networks {
+		if n != nil && n.NetworkID == networkID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// name returns the name of the container for errors, or the id when it has no name.
+func name(c types.Container) string {
+	if len(c.Names) > 0 {
+		return strings.TrimPrefix(c.Names[0], "/")
+	}
+
+	return c.ID
+}
+
+func remove(ctx context.Context, docker client.CommonAPIClient, c types.Container) error {
+	// volumes are not removed so the data is kept for the new container
+	if err := docker.ContainerRemove(ctx, c.ID, types.ContainerRemoveOptions{Force: true}); err != nil {
+		return fmt.Errorf("unable to remove the container %s, %w", c.ID, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,176 @@
+package reconcile
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/client"
+
+	"github.com/craftcms/nitro/pkg/containerlabels"
+)
+
+// TestContainers simulates apply being stopped at each phase of creating a
+// container and verifies the next run recovers the container or removes it
+// so a new container can be created.
+func TestContainers(t *testing.T) {
+	attached := &types.SummaryNetworkSettings{
+		Networks: map[string]*network.EndpointSettings{
+			"nitro-network": {NetworkID: "some-network-id"},
+		},
+	}
+	detached := &types.SummaryNetworkSettings{
+		Networks: map[string]*network.EndpointSettings{},
+	}
+	labels := map[string]string{containerlabels.Nitro: "true"}
+
+	tests := []struct {
+		name       string
+		spy        *mockClient
+		containers []types.Container
+
+		wantIDs        []string
+		wantStartIDs   []string
+		wantConnectIDs []string
+		wantRemoveIDs  []string
+		wantErr        bool
+	}{
+		{
+			name:       "running containers on the network are adopted as is",
+			spy:        &mockClient{},
+			containers: []types.Container{{ID: "running", State: "running", Labels: labels, NetworkSettings: attached}},
+			wantIDs:    []string{"running"},
+		},
+		{
+			name:         "containers created but never started are started",
+			spy:          &mockClient{},
+			containers:   []types.Container{{ID: "created", State: "created", Labels: labels, NetworkSettings: attached}},
+			wantIDs:      []string{"created"},
+			wantStartIDs: []string{"created"},
+		},
+		{
+			name:         "exited containers are started",
+			spy:          &mockClient{},
+			containers:   []types.Container{{ID: "exited", State: "exited", Labels: labels, NetworkSettings: attached}},
+			wantIDs:      []string{"exited"},
+			wantStartIDs: []string{"exited"},
+		},
+		{
+			name:           "containers missing the network endpoint are reconnected",
+			spy:            &mockClient{},
+			containers:     []types.Container{{ID: "detached", State: "running", Labels: labels, NetworkSettings: detached}},
+			wantIDs:        []string{"detached"},
+			wantConnectIDs: []string{"detached"},
+		},
+		{
+			name:          "created containers missing the network endpoint are removed",
+			spy:           &mockClient{},
+			containers:    []types.Container{{ID: "partial", State: "created", Labels: labels, NetworkSettings: detached}},
+			wantRemoveIDs: []string{"partial"},
+		},
+		{
+			name:          "created containers missing the labels are removed",
+			spy:           &mockClient{},
+			containers:    []types.Container{{ID: "partial", State: "created", NetworkSettings: attached}},
+			wantRemoveIDs: []string{"partial"},
+		},
+		{
+			name:          "dead containers are removed",
+			spy:           &mockClient{},
+			containers:    []types.Container{{ID: "dead", State: "dead", Labels: labels, NetworkSettings: attached}},
+			wantRemoveIDs: []string{"dead"},
+		},
+		{
+			name:         "errors starting containers are returned without removing the container",
+			spy:          &mockClient{containerStartError: fmt.Errorf("port is already allocated")},
+			containers:   []types.Container{{ID: "exited", State: "exited", Labels: labels, NetworkSettings: attached}},
+			wantStartIDs: []string{"exited"},
+			wantErr:      true,
+		},
+		{
+			name:           "errors reconnecting containers are returned without removing the container",
+			spy:            &mockClient{networkConnectError: fmt.Errorf("unable to connect")},
+			containers:     []types.Container{{ID: "running", State: "running", Labels: labels, NetworkSettings: detached}},
+			wantConnectIDs: []string{"running"},
+			wantErr:        true,
+		},
+		{
+			name:          "errors removing containers are returned",
+			spy:           &mockClient{containerRemoveError: fmt.Errorf("unable to remove")},
+			containers:    []types.Container{{ID: "dead", State: "dead"}},
+			wantRemoveIDs: []string{"dead"},
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Containers(context.Background(), tt.spy, "some-network-id", tt.containers)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Containers() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			var ids []string
+			for _, c := range got {
+				if c.State != "running" {
+					t.Errorf("expected adopted container %s to be running, got %s", c.ID, c.State)
+				}
+
+				ids = append(ids, c.ID)
+			}
+
+			if !reflect.DeepEqual(ids, tt.wantIDs) {
+				t.Errorf("expected the adopted containers to match, got %v want %v", ids, tt.wantIDs)
+			}
+
+			if !reflect.DeepEqual(tt.spy.containerStartIDs, tt.wantStartIDs) {
+				t.Errorf("expected the started containers to match, got %v want %v", tt.spy.containerStartIDs, tt.wantStartIDs)
+			}
+
+			if !reflect.DeepEqual(tt.spy.networkConnectIDs, tt.wantConnectIDs) {
+				t.Errorf("expected the connected containers to match, got %v want %v", tt.spy.networkConnectIDs, tt.wantConnectIDs)
+			}
+
+			if !reflect.DeepEqual(tt.spy.containerRemoveIDs, tt.wantRemoveIDs) {
+				t.Errorf("expected the removed containers to match, got %v want %v", tt.spy.containerRemoveIDs, tt.wantRemoveIDs)
+			}
+		})
+	}
+}
+
+type mockClient struct {
+	client.CommonAPIClient
+
+	// mock start
+	containerStartIDs   []string
+	containerStartError error
+
+	// mock remove
+	containerRemoveIDs   []string
+	containerRemoveError error
+
+	// mock network connect
+	networkConnectIDs   []string
+	networkConnectError error
+}
+
+func (c *mockClient) ContainerStart(ctx context.Context, container string, options types.ContainerStartOptions) error {
+	c.containerStartIDs = append(c.containerStartIDs, container)
+
+	return c.containerStartError
+}
+
+func (c *mockClient) ContainerRemove(ctx context.Context, containerID string, opts types.ContainerRemoveOptions) error {
+	c.containerRemoveIDs = append(c.containerRemoveIDs, containerID)
+
+	return c.containerRemoveError
+}
+
+func (c *mockClient) NetworkConnect(ctx context.Context, networkID, containerID string, config *network.EndpointSettings) error {
+	c.networkConnectIDs = append(c.networkConnectIDs, containerID)
+
+	return c.networkConnectError
+}
@@ -0,0 +1,121 @@
+package releases
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// LatestURL is the URL of the latest stable release in the GitHub API
+	LatestURL = "https://api.github.com/repos/craftcms/nitro/releases/latest"
+
+	// CacheTTL is how long the latest release is cached before the releases API is
+	// queried again
+	CacheTTL = 24 * time.Hour
+)
+
+// CacheFile is the file in the nitro directory the latest release is cached in.
+const CacheFile = ".latest-release.json"
+
+// cache is the contents of the cache file.
+type cache struct {
+	Version   string    `json:"version"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// Latest returns the version of the latest release. The version is cached in the directory
+// for CacheTTL, so the releases API is queried at most once a day. When refresh is true the
+// cached version is ignored. Failed checks are cached as well so an unreachable API does not
+// slow down each command, the previous version is returned for them.
+func Latest(finder Finder, dir string, refresh bool, now time.Time) (string, error) {
+	file := filepath.Join(dir, CacheFile)
+
+	var c cache
+	if data, err := ioutil.ReadFile(file); err == nil {
+		_ = json.Unmarshal(data, &c)
+	}
+
+	if !refresh && !c.CheckedAt.IsZero() && now.Sub(c.CheckedAt) < CacheTTL {
+		return c.Version, nil
+	}
+
+	release, ferr := finder.Find(LatestURL, runtime.GOOS, runtime.GOARCH)
+	if ferr == nil {
+		c.Version = release.Version
+	}
+
+	c.CheckedAt = now
+
+	if data, err := json.Marshal(c); err == nil {
+		_ = ioutil.WriteFile(file, data, 0644)
+	}
+
+	if ferr != nil {
+		return c.Version, ferr
+	}
+
+	return c.Version, nil
+}
+
+// Newer returns true when the release version is newer than the version of the CLI. The
+// versions are compared by their major, minor, and patch numbers. It returns false for
+// versions that can not be compared, such as the develop builds.
+func Newer(release, cli string) bool {
+	r, ok := parse(release)
+	if !ok {
+		return false
+	}
+
+	c, ok := parse(cli)
+	if !ok {
+		return false
+	}
+
+	for i := range r {
+		if r[i] != c[i] {
+			return r[i] > c[i]
+		}
+	}
+
+	return false
+}
+
+// parse returns the major, minor, and patch numbers of the version (e.g. v2.0.8-beta.1).
+func parse(version string) ([3]int, bool) {
+	var parsed [3]int
+
+	version = strings.TrimPrefix(version, "v")
+	if i := strings.IndexAny(version, "-+"); i >= 0 {
+		version = version[:i]
+	}
+
+	parts := strings.Split(version, ".")
+	if len(parts) == 0 || len(parts) > 3 {
+		return parsed, false
+	}
+
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			return parsed, false
+		}
+
+		parsed[i] = n
+	}
+
+	return parsed, true
+}
+
+// NewFinderWithTimeout returns a new github release finder that gives up after the
+// timeout, it is used for the checks that run after each command.
+func NewFinderWithTimeout(timeout time.Duration) Finder {
+	return &githubReleaseFinder{
+		HTTPClient: &http.Client{Timeout: timeout},
+	}
+}
@@ -1,6 +1,8 @@
 package releases
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -22,12 +24,24 @@ type Downloader interface {
 	Download(url, file string) error
 }
 
+var (
+	// ErrNoChecksum is returned when the checksums file does not include the release
+	ErrNoChecksum = fmt.Errorf("unable to find the checksum")
+
+	// ErrChecksumMismatch is returned when the download does not match the checksum
+	ErrChecksumMismatch = fmt.Errorf("the checksum of the download does not match")
+)
+
 // Release represents the release asset
 type Release struct {
+	Name            string
 	URL             string
 	ContentType     string
 	OperatingSystem string
 	Version         string
+
+	// ChecksumsURL is the URL to the checksums file of the release
+	ChecksumsURL string
 }
 
 type githubReleases struct {
@@ -112,11 +126,6 @@ func (r *githubReleaseFinder) Find(url, system, arch string) (*Release, error) {
 		r.HTTPClient = http.DefaultClient
 	}
 
-	switch arch {
-	case "amd64":
-		arch = "x86_64"
-	}
-
 	// get the latest release
 	resp, err := r.HTTPClient.Get(url)
 	if err != nil {
@@ -135,21 +144,7 @@ func (r *githubReleaseFinder) Find(url, system, arch string) (*Release, error) {
 			return nil, err
 		}
 
-		version := found.TagName
-
-		// find the asset from the download
-		for _, asset := range found.Assets {
-			if strings.Contains(asset.Name, system) && strings.Contains(asset.Name, arch) {
-				return &Release{
-					URL:             asset.BrowserDownloadURL,
-					ContentType:     asset.ContentType,
-					OperatingSystem: system,
-					Version:         version,
-				}, nil
-			}
-		}
-
-		return nil, fmt.Errorf("unable to find the release")
+		return find(found, system, arch)
 	}
 
 	releases := []githubReleases{}
@@ -157,23 +152,80 @@ func (r *githubReleaseFinder) Find(url, system, arch string) (*Release, error) {
 		return nil, err
 	}
 
-	release := releases[0]
+	if len(releases) == 0 {
+		return nil, fmt.Errorf("unable to find a release")
+	}
+
+	return find(releases[0], system, arch)
+}
+
+// find returns the archive in the release for the system and arch, along with the
+// checksums file for the release.
+func find(found githubReleases, system, arch string) (*Release, error) {
+	switch arch {
+	case "amd64":
+		arch = "x86_64"
+	}
+
+	release := &Release{OperatingSystem: system, Version: found.TagName}
+	for _, asset := range found.Assets {
+		switch {
+		case strings.HasSuffix(asset.Name, "checksums.txt"):
+			release.ChecksumsURL = asset.BrowserDownloadURL
+		case !strings.HasSuffix(asset.Name, ".tar.gz") && !strings.HasSuffix(asset.Name, ".zip"):
+			// ignore the packages for the linux package managers
+			continue
+		case strings.Contains(asset.Name, system) && strings.Contains(asset.Name, arch):
+			release.Name = asset.Name
+			release.URL = asset.BrowserDownloadURL
+			release.ContentType = asset.ContentType
+		}
+	}
 
-	version := release.TagName
+	if release.URL == "" {
+		return nil, fmt.Errorf("unable to find a release for %s %s", system, arch)
+	}
+
+	return release, nil
+}
 
-	// find the asset from the download
-	for _, asset := range release.Assets {
-		if strings.Contains(asset.Name, system) && strings.Contains(asset.Name, arch) {
-			return &Release{
-				URL:             asset.BrowserDownloadURL,
-				ContentType:     asset.ContentType,
-				OperatingSystem: system,
-				Version:         version,
-			}, nil
+// Verify checks the file matches the sha256 checksum for the name in the checksums file
+// of a release. The checksums file has a checksum and file name on each line.
+func Verify(file, name string, checksums []byte) error {
+	var expected string
+	for _, l := range strings.Split(string(checksums), "\n") {
+		fields := strings.Fields(l)
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == name {
+			expected = fields[0]
 		}
 	}
 
-	return nil, fmt.Errorf("unable to find a release")
+	if expected == "" {
+		return fmt.Errorf("%w for %s", ErrNoChecksum, name)
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return err
+	}
+
+	if actual := hex.EncodeToString(h.Sum(nil)); !strings.EqualFold(actual, expected) {
+		return fmt.Errorf("%w, expected %s but the download is %s", ErrChecksumMismatch, expected, actual)
+	}
+
+	return nil
+}
+
+// Current returns true when the release version is the version of the CLI. Release tags
+// may have a v prefix, which is not part of the CLI version.
+func Current(release, cli string) bool {
+	return strings.TrimPrefix(release, "v") == strings.TrimPrefix(cli, "v")
 }
 
 // NewFinder returns a new github release finder with the default HTTP client.
@@ -0,0 +1,174 @@
+package releases
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+const latest = `{
+  "tag_name": "v2.0.5",
+  "assets": [
+    {"name": "nitro_2.0.5_checksums.txt", "browser_download_url": "https://example.com/nitro_2.0.5_checksums.txt"},
+    {"name": "nitro_2.0.5_linux_x86_64.deb", "browser_download_url": "https://example.com/nitro_2.0.5_linux_x86_64.deb"},
+    {"name": "nitro_darwin_arm64.tar.gz", "browser_download_url": "https://example.com/nitro_darwin_arm64.tar.gz", "content_type": "application/gzip"},
+    {"name": "nitro_linux_x86_64.tar.gz", "browser_download_url": "https://example.com/nitro_linux_x86_64.tar.gz", "content_type": "application/gzip"}
+  ]
+}`
+
+func TestFind(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, latest)
+	}))
+	defer srv.Close()
+
+	release, err := NewFinder().Find(srv.URL+"/releases/latest", "linux", "amd64")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := Release{
+		Name:            "nitro_linux_x86_64.tar.gz",
+		URL:             "https://example.com/nitro_linux_x86_64.tar.gz",
+		ContentType:     "application/gzip",
+		OperatingSystem: "linux",
+		Version:         "v2.0.5",
+		ChecksumsURL:    "https://example.com/nitro_2.0.5_checksums.txt",
+	}
+	if *release != want {
+		t.Errorf("Find() = %+v, want %+v", *release, want)
+	}
+
+	if _, err := NewFinder().Find(srv.URL+"/releases/latest", "windows", "amd64"); err == nil {
+		t.Errorf("expected an error when there is no release for the system")
+	}
+}
+
+func TestVerify(t *testing.T) {
+	f, err := ioutil.TempFile("", "release")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.WriteString("nitro"); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	// sha256 of "nitro"
+	sum := "7b0aaacd69a8b83a07fe186dbef1c704a3ca72e72eb8a03b0389eae32ed45c3f"
+
+	tests := []struct {
+		name      string
+		checksums string
+		err       error
+	}{
+		{
+			name:      "matching checksums are valid",
+			checksums: "abc  nitro_darwin_arm64.tar.gz\n" + sum + "  nitro_linux_x86_64.tar.gz\n",
+		},
+		{
+			name:      "missing checksums return an error",
+			checksums: "abc  nitro_darwin_arm64.tar.gz\n",
+			err:       ErrNoChecksum,
+		},
+		{
+			name:      "mismatched checksums return an error",
+			checksums: "abc  nitro_linux_x86_64.tar.gz\n",
+			err:       ErrChecksumMismatch,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Verify(f.Name(), "nitro_linux_x86_64.tar.gz", []byte(tt.checksums)); !errors.Is(err, tt.err) {
+				t.Errorf("Verify() error = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestCurrent(t *testing.T) {
+	if !Current("v2.0.5", "2.0.5") {
+		t.Errorf("expected the tag prefix to be ignored")
+	}
+
+	if Current("v2.0.5", "2.0.4") {
+		t.Errorf("expected different versions to not be current")
+	}
+}
+
+func TestLatest(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nitro-releases")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	finder := &mockFinder{version: "v2.0.5"}
+	now := time.Unix(1600000000, 0)
+
+	if v, err := Latest(finder, dir, false, now); err != nil || v != "v2.0.5" {
+		t.Fatalf("Latest() = %q, %v", v, err)
+	}
+
+	// the cached version is used for a day
+	finder.version = "v2.0.6"
+	if v, _ := Latest(finder, dir, false, now.Add(time.Hour)); v != "v2.0.5" || finder.calls != 1 {
+		t.Errorf("expected the cached version, got %q after %d calls", v, finder.calls)
+	}
+
+	if v, _ := Latest(finder, dir, true, now.Add(time.Hour)); v != "v2.0.6" || finder.calls != 2 {
+		t.Errorf("expected the cache to be ignored when refreshing, got %q after %d calls", v, finder.calls)
+	}
+
+	// failed checks return the previous version and are not retried until the cache expires
+	finder.err = errors.New("offline")
+	if v, err := Latest(finder, dir, false, now.Add(26*time.Hour)); err == nil || v != "v2.0.6" {
+		t.Errorf("expected the error and the previous version, got %q, %v", v, err)
+	}
+
+	if _, err := Latest(finder, dir, false, now.Add(27*time.Hour)); err != nil || finder.calls != 3 {
+		t.Errorf("expected the failed check to be cached, got %v after %d calls", err, finder.calls)
+	}
+}
+
+func TestNewer(t *testing.T) {
+	tests := []struct {
+		release, cli string
+		want         bool
+	}{
+		{release: "v2.0.6", cli: "2.0.5", want: true},
+		{release: "v2.1.0", cli: "2.0.12", want: true},
+		{release: "v2.0.5", cli: "2.0.5"},
+		{release: "v2.0.4", cli: "2.0.5"},
+		{release: "v2.0.6", cli: "develop"},
+		{release: "v2.0.6-beta.1", cli: "2.0.5", want: true},
+	}
+	for _, tt := range tests {
+		if got := Newer(tt.release, tt.cli); got != tt.want {
+			t.Errorf("Newer(%q, %q) = %v, want %v", tt.release, tt.cli, got, tt.want)
+		}
+	}
+}
+
+type mockFinder struct {
+	version string
+	err     error
+	calls   int
+}
+
+func (f *mockFinder) Find(url, system, arch string) (*Release, error) {
+	f.calls++
+
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	return &Release{Version: f.version}, nil
+}
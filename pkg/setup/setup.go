@@ -1,15 +1,23 @@
 package setup
 
 import (
+	"fmt"
 	"io"
 	"os"
 	"path/filepath"
 	"runtime"
 	"strconv"
+	"strings"
 
 	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/database"
+	"github.com/craftcms/nitro/pkg/environment"
+	"github.com/craftcms/nitro/pkg/phpversions"
 	"github.com/craftcms/nitro/pkg/portavail"
+	"github.com/craftcms/nitro/pkg/projects"
+	"github.com/craftcms/nitro/pkg/prompt"
 	"github.com/craftcms/nitro/pkg/terminal"
+	"github.com/craftcms/nitro/pkg/validate"
 )
 
 var (
@@ -18,14 +26,45 @@ var (
 )
 
 // FirstTime is used when there is no configuration file found in a users
-// home/.nitro directory. We do not prompt for input such as memory, cpu,
-// disk space in version 2 as that is defined and managed at the docker
-// level. If anything fails, we return an error.
+// home/.nitro directory. It walks the user through naming the environment,
+// the defaults for new sites, the databases and services to use, and
+// optionally imports existing Craft projects as sites. We do not prompt for
+// input such as memory, cpu, disk space in version 2 as that is defined and
+// managed at the docker level. If anything fails, we return an error.
 func FirstTime(home string, reader io.Reader, output terminal.Outputer) error {
-	c := config.Config{File: filepath.Join(home, config.DirectoryName, config.FileName)}
+	c := config.Config{File: filepath.Join(home, config.DirectoryName, environment.ConfigFile())}
 
 	output.Info("Setting up Nitro…")
 
+	// prompt for the name of the environment
+	name, err := output.Ask("Enter a name for the environment", environment.Name, ":", nil)
+	if err != nil {
+		return err
+	}
+
+	c.Name = name
+
+	// named environments use their own ports for the proxy
+	if !environment.IsDefault() {
+		c.Proxy.Ports = config.NextPorts(home)
+	}
+
+	// prompt for the tld used for new sites
+	tld, err := output.Ask("Enter the TLD for new sites", "nitro", ":", &validate.TLDValidator{})
+	if err != nil {
+		return err
+	}
+
+	c.Defaults.TLD = strings.TrimPrefix(tld, ".")
+
+	// prompt for the default php version
+	selected, err := prompt.Select(reader, "Select the default PHP version: ", phpversions.Versions, output)
+	if err != nil {
+		return err
+	}
+
+	c.Defaults.PHP = phpversions.Versions[selected]
+
 	// if this is running on Apple Silicon, we need to prompt for mariadb instead until this issue is resolved: https://docs.docker.com/docker-for-mac/apple-m1/
 	switch runtime.GOARCH == "arm64" || runtime.GOARCH == "arm" {
 	case true:
@@ -42,8 +81,8 @@ func FirstTime(home string, reader io.Reader, output terminal.Outputer) error {
 
 		if mariadb {
 			// prompt for the version
-			opts := []string{"10.5", "10.4", "10.3", "10.2", "10.1", "10"}
-			selected, err := output.Select(os.Stdin, "Select MariaDB version: ", opts)
+			opts := database.Versions["mariadb"]
+			selected, err := prompt.Select(os.Stdin, "Select MariaDB version: ", opts, output)
 			if err != nil {
 				return err
 			}
@@ -78,8 +117,8 @@ func FirstTime(home string, reader io.Reader, output terminal.Outputer) error {
 
 		if mysql {
 			// prompt for the version
-			opts := []string{"8.0", "5.7", "5.6"}
-			selected, err := output.Select(os.Stdin, "Select MySQL version: ", opts)
+			opts := database.Versions["mysql"]
+			selected, err := prompt.Select(os.Stdin, "Select MySQL version: ", opts, output)
 			if err != nil {
 				return err
 			}
@@ -115,8 +154,8 @@ func FirstTime(home string, reader io.Reader, output terminal.Outputer) error {
 
 	if postgres {
 		// prompt for the version
-		opts := []string{"13", "12", "11", "10", "9"}
-		selected, err := output.Select(os.Stdin, "Select PostgreSQL version: ", opts)
+		opts := database.Versions["postgres"]
+		selected, err := prompt.Select(os.Stdin, "Select PostgreSQL version: ", opts, output)
 		if err != nil {
 			return err
 		}
@@ -144,17 +183,43 @@ func FirstTime(home string, reader io.Reader, output terminal.Outputer) error {
 		})
 	}
 
-	redis, err := output.Confirm("Would you like to use Redis?", true, "")
-	if err != nil {
-		return err
+	// prompt for the services to enable
+	services := []struct {
+		name     string
+		fallback bool
+		enabled  *bool
+	}{
+		{name: "Redis", fallback: true, enabled: &c.Services.Redis},
+		{name: "Mailhog", enabled: &c.Services.Mailhog},
+		{name: "DynamoDB", enabled: &c.Services.DynamoDB},
+		{name: "MinIO", enabled: &c.Services.Minio},
+		{name: "Gotenberg", enabled: &c.Services.Gotenberg},
 	}
+	for _, svc := range services {
+		enable, err := output.Confirm("Would you like to use "+svc.name+"?", svc.fallback, "")
+		if err != nil {
+			return err
+		}
+
+		if enable {
+			output.Pending("adding", strings.ToLower(svc.name), "service")
 
-	if redis {
-		output.Pending("adding redis service")
+			*svc.enabled = true
+
+			output.Done()
+		}
+	}
 
-		c.Services.Redis = true
+	// prompt to import existing projects
+	scan, err := output.Confirm("Would you like to scan a directory for existing Craft projects?", false, "")
+	if err != nil {
+		return err
+	}
 
-		output.Done()
+	if scan {
+		if err := importProjects(home, &c, output); err != nil {
+			return err
+		}
 	}
 
 	// save the file
@@ -164,3 +229,67 @@ func FirstTime(home string, reader io.Reader, output terminal.Outputer) error {
 
 	return nil
 }
+
+// importProjects prompts for a directory and adds the Craft projects found in
+// the directory as sites, using the default TLD and PHP version.
+func importProjects(home string, c *config.Config, output terminal.Outputer) error {
+	dir, err := output.Ask("Enter the directory to scan", filepath.Join(home, "dev"), ":", nil)
+	if err != nil {
+		return err
+	}
+
+	// expand the home directory
+	if strings.HasPrefix(dir, "~") {
+		dir = strings.Replace(dir, "~", home, 1)
+	}
+
+	output.Pending("scanning", dir)
+
+	found, err := projects.Find(dir)
+	if err != nil {
+		output.Warning()
+
+		return err
+	}
+
+	output.Done()
+
+	if len(found) == 0 {
+		output.Info("No Craft projects found in", dir)
+
+		return nil
+	}
+
+	for _, p := range found {
+		site := config.Site{
+			Hostname: fmt.Sprintf("%s.%s", filepath.Base(p.Path), c.GetTLD()),
+			Path:     strings.Replace(p.Path, home, "~", 1),
+			Webroot:  p.Webroot,
+			Version:  c.Defaults.PHP,
+		}
+
+		// use the version required by the project
+		if p.PHP != "" {
+			site.Version = p.PHP
+		}
+
+		add, err := output.Confirm(fmt.Sprintf("Add %s as %s?", site.Path, site.Hostname), true, "")
+		if err != nil {
+			return err
+		}
+
+		if !add {
+			continue
+		}
+
+		if err := c.AddSite(site); err != nil {
+			output.Info("skipping", site.Hostname+",", err.Error())
+
+			continue
+		}
+
+		output.Success("adding site", site.Hostname)
+	}
+
+	return nil
+}
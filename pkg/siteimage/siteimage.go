@@ -0,0 +1,122 @@
+package siteimage
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/jsonmessage"
+
+	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/environment"
+	"github.com/craftcms/nitro/pkg/pathexists"
+)
+
+// Dockerfile is the name of the file in the root of a project that is used to build a
+// derived image for the site.
+const Dockerfile = "nitro.Dockerfile"
+
+// Find returns the content of the Dockerfile in the project directory. It returns nil
+// when the project does not have a Dockerfile.
+func Find(path string) ([]byte, error) {
+	file := filepath.Join(path, Dockerfile)
+	if !pathexists.IsFile(file) {
+		return nil, nil
+	}
+
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read %s, %w", file, err)
+	}
+
+	return content, nil
+}
+
+// Generate returns the Dockerfile to build from the content of the project Dockerfile.
+// The base image is added as the first instruction, unless the project Dockerfile has
+// its own FROM instruction. The base image is also available to the Dockerfile as the
+// NITRO_BASE_IMAGE build arg.
+func Generate(base string, content []byte) []byte {
+	for _, l := range strings.Split(string(content), "\n") {
+		l = strings.TrimSpace(l)
+		if l == "" || strings.HasPrefix(l, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(strings.ToUpper(l), "FROM ") || strings.HasPrefix(strings.ToUpper(l), "ARG ") {
+			return content
+		}
+
+		break
+	}
+
+	return append([]byte(fmt.Sprintf("FROM %s\n", base)), content...)
+}
+
+// Name returns the name of the derived image for the site. The tag is based on the base
+// image and the Dockerfile, so changing either of them results in a new image and the
+// container for the site is recreated.
+func Name(hostname, base string, content []byte) string {
+	sum := sha256.Sum256(append([]byte(base+"\n"), content...))
+
+	return fmt.Sprintf("nitro-site-%s:%x", hostname, sum[:6])
+}
+
+// Build builds the derived image for the site from the base image and the project
+// Dockerfile and returns the name of the image. The image is only built when it does not
+// exist, and the layers are cached between builds. The build context only contains the
+// Dockerfile, so it cannot copy files from the project.
+func Build(ctx context.Context, docker client.ImageAPIClient, hostname, base string, content []byte) (string, error) {
+	image := Name(hostname, base, content)
+
+	// the image was already built for the Dockerfile
+	if _, _, err := docker.ImageInspectWithRaw(ctx, image); err == nil {
+		return image, nil
+	}
+
+	dockerfile := Generate(base, content)
+
+	buf := &bytes.Buffer{}
+	tw := tar.NewWriter(buf)
+	if err := tw.WriteHeader(&tar.Header{Name: "Dockerfile", Mode: 0644, Size: int64(len(dockerfile))}); err != nil {
+		return "", err
+	}
+
+	if _, err := tw.Write(dockerfile); err != nil {
+		return "", err
+	}
+
+	if err := tw.Close(); err != nil {
+		return "", err
+	}
+
+	resp, err := docker.ImageBuild(ctx, buf, types.ImageBuildOptions{
+		Tags:        []string{image},
+		Dockerfile:  "Dockerfile",
+		Remove:      true,
+		ForceRemove: true,
+		BuildArgs:   map[string]*string{"NITRO_BASE_IMAGE": &base},
+		Labels: map[string]string{
+			containerlabels.Nitro: environment.Label(),
+			containerlabels.Host:  hostname,
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("unable to build the image for %s, %w", hostname, err)
+	}
+	defer resp.Body.Close()
+
+	// the build errors are part of the output
+	if err := jsonmessage.DisplayJSONMessagesStream(resp.Body, ioutil.Discard, 0, false, nil); err != nil {
+		return "", fmt.Errorf("unable to build the image for %s from %s, %w", hostname, Dockerfile, err)
+	}
+
+	return image, nil
+}
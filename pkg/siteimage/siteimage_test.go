@@ -0,0 +1,78 @@
+package siteimage
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	base := "docker.io/craftcms/nginx:8.0-dev"
+
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name:    "the base image is added to instructions",
+			content: "# install imagemagick\nRUN apk add --no-cache imagemagick\n",
+			want:    "FROM docker.io/craftcms/nginx:8.0-dev\n# install imagemagick\nRUN apk add --no-cache imagemagick\n",
+		},
+		{
+			name:    "a Dockerfile with its own base image is not changed",
+			content: "FROM craftcms/nginx:7.4-dev\nRUN apk add --no-cache imagemagick\n",
+			want:    "FROM craftcms/nginx:7.4-dev\nRUN apk add --no-cache imagemagick\n",
+		},
+		{
+			name:    "a Dockerfile using the base image arg is not changed",
+			content: "ARG NITRO_BASE_IMAGE\nFROM ${NITRO_BASE_IMAGE}\n",
+			want:    "ARG NITRO_BASE_IMAGE\nFROM ${NITRO_BASE_IMAGE}\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(Generate(base, []byte(tt.content))); got != tt.want {
+				t.Errorf("Generate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestName(t *testing.T) {
+	content := []byte("RUN apk add --no-cache imagemagick\n")
+
+	name := Name("craft.nitro", "docker.io/craftcms/nginx:8.0-dev", content)
+	if name != Name("craft.nitro", "docker.io/craftcms/nginx:8.0-dev", content) {
+		t.Errorf("expected the name to be the same for the same Dockerfile")
+	}
+
+	if name == Name("craft.nitro", "docker.io/craftcms/nginx:7.4-dev", content) {
+		t.Errorf("expected the name to change with the base image")
+	}
+
+	if name == Name("craft.nitro", "docker.io/craftcms/nginx:8.0-dev", []byte("RUN apk add --no-cache git\n")) {
+		t.Errorf("expected the name to change with the Dockerfile")
+	}
+}
+
+func TestFind(t *testing.T) {
+	dir, err := ioutil.TempDir("", "siteimage")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if content, err := Find(dir); err != nil || content != nil {
+		t.Errorf("expected no Dockerfile, got %q, %v", content, err)
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(dir, Dockerfile), []byte("RUN true\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if content, err := Find(dir); err != nil || string(content) != "RUN true\n" {
+		t.Errorf("expected the Dockerfile, got %q, %v", content, err)
+	}
+}
@@ -0,0 +1,194 @@
+package blackfire
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/environment"
+	"github.com/craftcms/nitro/pkg/reconcile"
+	"github.com/craftcms/nitro/pkg/terminal"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/client"
+)
+
+const (
+	// Image is the image to use for the blackfire agent container
+	Image = "docker.io/blackfire/blackfire:2"
+
+	// Host is the hostname for the blackfire container
+	Host = "blackfire.service.nitro"
+
+	// Label is the label value used to mark a container as a "blackfire" service
+	Label = "blackfire"
+
+	// Port is the port the agent listens on for the probes
+	Port = "8307"
+
+	// SocketEnvVar is the environment variable the PHP probe uses to connect to the agent
+	SocketEnvVar = "BLACKFIRE_AGENT_SOCKET"
+)
+
+// Socket is the address of the agent for the PHP probe in the site containers.
+var Socket = fmt.Sprintf("tcp://%s:%s", Host, Port)
+
+// Env returns the environment variables for the agent with the server credentials.
+func Env(creds config.Blackfire) []string {
+	return []string{
+		"BLACKFIRE_SERVER_ID=" + creds.ServerID,
+		"BLACKFIRE_SERVER_TOKEN=" + creds.ServerToken,
+		"BLACKFIRE_SOCKET=tcp://0.0.0.0:" + Port,
+	}
+}
+
+// VerifyCreated will verify that the blackfire agent container exists, is started, and uses
+// the server credentials. The container is recreated when the credentials change.
+func VerifyCreated(ctx context.Context, cli client.CommonAPIClient, networkID string, creds config.Blackfire, logs container.LogConfig, output terminal.Outputer) (string, string, error) {
+	if creds.ServerID == "" || creds.ServerToken == "" {
+		return "", "", fmt.Errorf("the blackfire service requires the server id and token, run `nitro blackfire on` or set BLACKFIRE_SERVER_ID and BLACKFIRE_SERVER_TOKEN")
+	}
+
+	// add the filter
+	filter := filters.NewArgs()
+	filter.Add("label", containerlabels.Nitro+"="+environment.Label())
+	filter.Add("label", containerlabels.Type+"="+Label)
+
+	// get a list of containers
+	containers, err := cli.ContainerList(ctx, types.ContainerListOptions{
+		All:     true,
+		Filters: filter,
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	// make sure existing containers are started and on the network
+	containers, err = reconcile.Containers(ctx, cli, networkID, containers)
+	if err != nil {
+		return "", "", err
+	}
+
+	// remove the container when the credentials changed
+	if len(containers) > 0 {
+		details, err := cli.ContainerInspect(ctx, containers[0].ID)
+		if err != nil {
+			return "", "", err
+		}
+
+		if details.Config != nil && !matches(details.Config.Env, Env(creds)) {
+			if err := VerifyRemoved(ctx, cli, output); err != nil {
+				return "", "", err
+			}
+
+			containers = nil
+		}
+	}
+
+	if len(containers) > 0 {
+		return containers[0].ID, Host, nil
+	}
+
+	// pull the image
+	r, err := cli.ImagePull(ctx, Image, types.ImagePullOptions{})
+	if err != nil {
+		return "", "", err
+	}
+
+	// read from the buffer to pull the image
+	buf := &bytes.Buffer{}
+	if _, err := buf.ReadFrom(r); err != nil {
+		return "", "", fmt.Errorf("unable to read output while pulling image, %w", err)
+	}
+
+	containerConfig := &container.Config{
+		Image: Image,
+		Env:   Env(creds),
+		Labels: map[string]string{
+			containerlabels.Nitro: environment.Label(),
+			containerlabels.Type:  Label,
+		},
+	}
+
+	hostconfig := &container.HostConfig{
+		LogConfig: logs,
+	}
+
+	networkConfig := &network.NetworkingConfig{
+		EndpointsConfig: map[string]*network.EndpointSettings{
+			environment.Network(): {
+				NetworkID: networkID,
+			},
+		},
+	}
+
+	// create the container
+	resp, err := cli.ContainerCreate(ctx, containerConfig, hostconfig, networkConfig, nil, Host)
+	if err != nil {
+		return "", "", fmt.Errorf("unable to create the container, %w", err)
+	}
+
+	// start the container
+	if err := cli.ContainerStart(ctx, resp.ID, types.ContainerStartOptions{}); err != nil {
+		return "", "", fmt.Errorf("unable to start the container, %w", err)
+	}
+
+	return resp.ID, Host, nil
+}
+
+// VerifyRemoved will verify the container is not created for the blackfire service.
+func VerifyRemoved(ctx context.Context, cli client.CommonAPIClient, output terminal.Outputer) error {
+	// add the filter
+	filter := filters.NewArgs()
+	filter.Add("label", containerlabels.Nitro+"="+environment.Label())
+	filter.Add("label", containerlabels.Type+"="+Label)
+
+	// get a list of containers
+	containers, err := cli.ContainerList(ctx, types.ContainerListOptions{
+		All:     true,
+		Filters: filter,
+	})
+	if err != nil {
+		return err
+	}
+
+	timeout := time.Duration(time.Second * 30)
+
+	// remove all of the containers
+	for _, c := range containers {
+		// stop the container if its running
+		if c.State == "running" {
+			if err := cli.ContainerStop(ctx, c.ID, &timeout); err != nil {
+				return err
+			}
+		}
+
+		// remove the container
+		if err := cli.ContainerRemove(ctx, c.ID, types.ContainerRemoveOptions{}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// matches returns true when each of the expected environment variables is set.
+func matches(env, expected []string) bool {
+	set := make(map[string]bool)
+	for _, e := range env {
+		set[e] = true
+	}
+
+	for _, e := range expected {
+		if !set[e] {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,136 @@
+package blackfire
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/client"
+	v1 "github.com/opencontainers/image-spec/specs-go/v1"
+
+	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/terminal"
+)
+
+func TestVerifyCreated(t *testing.T) {
+	creds := config.Blackfire{ServerID: "id", ServerToken: "token"}
+
+	tests := []struct {
+		name        string
+		creds       config.Blackfire
+		spy         *mockClient
+		wantCreated bool
+		wantRemoved string
+		wantErr     bool
+	}{
+		{
+			name:    "credentials are required",
+			creds:   config.Blackfire{ServerID: "id"},
+			spy:     &mockClient{},
+			wantErr: true,
+		},
+		{
+			name:        "the agent is created when it does not exist",
+			creds:       creds,
+			spy:         &mockClient{},
+			wantCreated: true,
+		},
+		{
+			name:  "the agent is kept when the credentials match",
+			creds: creds,
+			spy: &mockClient{
+				containers: []types.Container{{ID: "agent", State: "running"}},
+				env:        Env(creds),
+			},
+		},
+		{
+			name:  "the agent is recreated when the credentials change",
+			creds: creds,
+			spy: &mockClient{
+				containers: []types.Container{{ID: "agent", State: "running"}},
+				env:        Env(config.Blackfire{ServerID: "old", ServerToken: "token"}),
+			},
+			wantCreated: true,
+			wantRemoved: "agent",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, hostname, err := VerifyCreated(context.Background(), tt.spy, "network", tt.creds, container.LogConfig{}, terminal.New())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("VerifyCreated() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			if hostname != Host {
+				t.Errorf("expected the hostname %s, got %s", Host, hostname)
+			}
+
+			if created := tt.spy.config != nil; created != tt.wantCreated {
+				t.Fatalf("expected the container to be created %v, got %v", tt.wantCreated, created)
+			}
+
+			if tt.wantCreated && !reflect.DeepEqual(tt.spy.config.Env, Env(tt.creds)) {
+				t.Errorf("expected the agent env %v, got %v", Env(tt.creds), tt.spy.config.Env)
+			}
+
+			if tt.spy.removed != tt.wantRemoved {
+				t.Errorf("expected the container %q to be removed, got %q", tt.wantRemoved, tt.spy.removed)
+			}
+		})
+	}
+}
+
+type mockClient struct {
+	client.CommonAPIClient
+
+	containers []types.Container
+	env        []string
+
+	config  *container.Config
+	removed string
+}
+
+func (c *mockClient) ContainerList(ctx context.Context, options types.ContainerListOptions) ([]types.Container, error) {
+	if c.removed != "" {
+		return nil, nil
+	}
+
+	return c.containers, nil
+}
+
+func (c *mockClient) ContainerInspect(ctx context.Context, containerID string) (types.ContainerJSON, error) {
+	return types.ContainerJSON{Config: &container.Config{Env: c.env}}, nil
+}
+
+func (c *mockClient) ContainerStop(ctx context.Context, containerID string, timeout *time.Duration) error {
+	return nil
+}
+
+func (c *mockClient) ContainerRemove(ctx context.Context, containerID string, options types.ContainerRemoveOptions) error {
+	c.removed = containerID
+	return nil
+}
+
+func (c *mockClient) ImagePull(ctx context.Context, ref string, options types.ImagePullOptions) (io.ReadCloser, error) {
+	return ioutil.NopCloser(strings.NewReader("")), nil
+}
+
+func (c *mockClient) ContainerCreate(ctx context.Context, config *container.Config, hostConfig *container.HostConfig, networkingConfig *network.NetworkingConfig, platform *v1.Platform, containerName string) (container.ContainerCreateCreatedBody, error) {
+	c.config = config
+	return container.ContainerCreateCreatedBody{ID: "created"}, nil
+}
+
+func (c *mockClient) ContainerStart(ctx context.Context, containerID string, options types.ContainerStartOptions) error {
+	return nil
+}
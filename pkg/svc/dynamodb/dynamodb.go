@@ -8,6 +8,8 @@ import (
 	"time"
 
 	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/environment"
+	"github.com/craftcms/nitro/pkg/reconcile"
 	"github.com/craftcms/nitro/pkg/terminal"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
@@ -29,10 +31,10 @@ const (
 )
 
 // VerifyCreated will verify that the dynamodb service container exists and is started
-func VerifyCreated(ctx context.Context, cli client.CommonAPIClient, networkID string, output terminal.Outputer) (string, string, error) {
+func VerifyCreated(ctx context.Context, cli client.CommonAPIClient, networkID string, logs container.LogConfig, output terminal.Outputer) (string, string, error) {
 	// add the filter
 	filter := filters.NewArgs()
-	filter.Add("label", containerlabels.Nitro+"=true")
+	filter.Add("label", containerlabels.Nitro+"="+environment.Label())
 	filter.Add("label", containerlabels.Type+"="+Label)
 
 	// get a list of containers
@@ -44,6 +46,12 @@ func VerifyCreated(ctx context.Context, cli client.CommonAPIClient, networkID st
 		return "", "", err
 	}
 
+	// make sure existing containers are started and on the network
+	containers, err = reconcile.Containers(ctx, cli, networkID, containers)
+	if err != nil {
+		return "", "", err
+	}
+
 	// if there is not a container, create one
 	if len(containers) == 0 {
 		// pull the image
@@ -72,7 +80,7 @@ func VerifyCreated(ctx context.Context, cli client.CommonAPIClient, networkID st
 		containerConfig := &container.Config{
 			Image: Image,
 			Labels: map[string]string{
-				containerlabels.Nitro: "true",
+				containerlabels.Nitro: environment.Label(),
 				containerlabels.Type:  Label,
 			},
 			ExposedPorts: nat.PortSet{
@@ -82,6 +90,7 @@ func VerifyCreated(ctx context.Context, cli client.CommonAPIClient, networkID st
 		}
 
 		hostconfig := &container.HostConfig{
+			LogConfig: logs,
 			PortBindings: map[nat.Port][]nat.PortBinding{
 				httpPortNat: {
 					{
@@ -94,7 +103,7 @@ func VerifyCreated(ctx context.Context, cli client.CommonAPIClient, networkID st
 
 		networkConfig := &network.NetworkingConfig{
 			EndpointsConfig: map[string]*network.EndpointSettings{
-				"nitro-network": {
+				environment.Network(): {
 					NetworkID: networkID,
 				},
 			},
@@ -131,7 +140,7 @@ func VerifyCreated(ctx context.Context, cli client.CommonAPIClient, networkID st
 func VerifyRemoved(ctx context.Context, cli client.CommonAPIClient, output terminal.Outputer) error {
 	// add the filter
 	filter := filters.NewArgs()
-	filter.Add("label", containerlabels.Nitro+"=true")
+	filter.Add("label", containerlabels.Nitro+"="+environment.Label())
 	filter.Add("label", containerlabels.Type+"="+Label)
 
 	// get a list of containers
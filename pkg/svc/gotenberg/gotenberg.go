@@ -0,0 +1,186 @@
+package gotenberg
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/environment"
+	"github.com/craftcms/nitro/pkg/reconcile"
+	"github.com/craftcms/nitro/pkg/terminal"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/client"
+	"github.com/docker/go-connections/nat"
+)
+
+const (
+	// Image is the image to use for the gotenberg container
+	Image = "docker.io/gotenberg/gotenberg:8"
+
+	// Host is the hostname for the gotenberg container
+	Host = "gotenberg.service.nitro"
+
+	// Label is the label value used to mark a container as a "gotenberg" service
+	Label = "gotenberg"
+
+	// EnvVar is the environment variable sites use to find the gotenberg API
+	EnvVar = "GOTENBERG_URL"
+
+	// URL is the endpoint for the gotenberg API from the site containers
+	URL = "http://" + Host + ":3000"
+)
+
+// VerifyCreated will verify that the gotenberg service container exists and is started
+func VerifyCreated(ctx context.Context, cli client.CommonAPIClient, networkID string, logs container.LogConfig, output terminal.Outputer) (string, string, error) {
+	// add the filter
+	filter := filters.NewArgs()
+	filter.Add("label", containerlabels.Nitro+"="+environment.Label())
+	filter.Add("label", containerlabels.Type+"="+Label)
+
+	// get a list of containers
+	containers, err := cli.ContainerList(ctx, types.ContainerListOptions{
+		All:     true,
+		Filters: filter,
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	// make sure existing containers are started and on the network
+	containers, err = reconcile.Containers(ctx, cli, networkID, containers)
+	if err != nil {
+		return "", "", err
+	}
+
+	// if there is not a container, create one
+	if len(containers) == 0 {
+		// pull the image
+		r, err := cli.ImagePull(ctx, Image, types.ImagePullOptions{})
+		if err != nil {
+			return "", "", err
+		}
+
+		// read from the buffer to pull the image
+		buf := &bytes.Buffer{}
+		if _, err := buf.ReadFrom(r); err != nil {
+			return "", "", fmt.Errorf("unable to read output while pulling image, %w", err)
+		}
+
+		// set the nitro env overrides
+		// the proxy uses port 3000 on the host for node, so use 3030 by default
+		httpPort := "3030"
+		if os.Getenv("NITRO_GOTENBERG_PORT") != "" {
+			httpPort = os.Getenv("NITRO_GOTENBERG_PORT")
+		}
+
+		httpPortNat, err := nat.NewPort("tcp", "3000")
+		if err != nil {
+			return "", "", fmt.Errorf("unable to create the port, %w", err)
+		}
+
+		containerConfig := &container.Config{
+			Image: Image,
+			Labels: map[string]string{
+				containerlabels.Nitro: environment.Label(),
+				containerlabels.Type:  Label,
+			},
+			ExposedPorts: nat.PortSet{
+				httpPortNat: struct{}{},
+			},
+		}
+
+		hostconfig := &container.HostConfig{
+			LogConfig: logs,
+			PortBindings: map[nat.Port][]nat.PortBinding{
+				httpPortNat: {
+					{
+						HostIP:   "127.0.0.1",
+						HostPort: httpPort,
+					},
+				},
+			},
+		}
+
+		networkConfig := &network.NetworkingConfig{
+			EndpointsConfig: map[string]*network.EndpointSettings{
+				environment.Network(): {
+					NetworkID: networkID,
+				},
+			},
+		}
+
+		// create the container
+		resp, err := cli.ContainerCreate(ctx, containerConfig, hostconfig, networkConfig, nil, Host)
+		if err != nil {
+			return "", "", fmt.Errorf("unable to create the container, %w", err)
+		}
+
+		// start the container
+		if err := cli.ContainerStart(ctx, resp.ID, types.ContainerStartOptions{}); err != nil {
+			return "", "", fmt.Errorf("unable to start the container, %w", err)
+		}
+
+		return resp.ID, Host, nil
+	}
+
+	// start each of the containers, there should only be one so the final return is an error
+	for _, c := range containers {
+		// start the container
+		if c.Status != "running" {
+			if err := cli.ContainerStart(ctx, c.ID, types.ContainerStartOptions{}); err != nil {
+				return "", "", fmt.Errorf("unable to start the container, %w", err)
+			}
+		}
+	}
+
+	return containers[0].ID, Host, nil
+}
+
+// VerifyRemoved will try verify the container is not created for the gotenberg service. If we find any containers that are
+func VerifyRemoved(ctx context.Context, cli client.CommonAPIClient, output terminal.Outputer) error {
+	// add the filter
+	filter := filters.NewArgs()
+	filter.Add("label", containerlabels.Nitro+"="+environment.Label())
+	filter.Add("label", containerlabels.Type+"="+Label)
+
+	// get a list of containers
+	containers, err := cli.ContainerList(ctx, types.ContainerListOptions{
+		All:     true,
+		Filters: filter,
+	})
+	if err != nil {
+		return err
+	}
+
+	// we are all good, nothing to do
+	if len(containers) == 0 {
+		return nil
+	}
+
+	timeout := time.Duration(time.Second * 30)
+
+	// remove all of the containers
+	for _, c := range containers {
+		// stop the container if its running
+		if c.State == "running" {
+			if err := cli.ContainerStop(ctx, c.ID, &timeout); err != nil {
+				return err
+			}
+		}
+
+		// remove the container
+		if err := cli.ContainerRemove(ctx, c.ID, types.ContainerRemoveOptions{
+			RemoveVolumes: true,
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
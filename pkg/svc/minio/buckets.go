@@ -0,0 +1,42 @@
+package minio
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/docker/docker/client"
+
+	"github.com/craftcms/nitro/pkg/containerexec"
+)
+
+// alias is the name of the mc alias for the server in the container.
+const alias = "nitro"
+
+// BucketScript returns the shell script that waits for the server to accept connections
+// and creates each bucket with the mc client in the minio image. Buckets that exist are
+// left alone so the script can run each time the service starts.
+func BucketScript(buckets []string) string {
+	lines := []string{
+		fmt.Sprintf("for i in $(seq 1 30); do mc alias set %s http://127.0.0.1:9000 %s %s >/dev/null 2>&1 && break; sleep 1; done", alias, User, Password),
+	}
+
+	for _, b := range buckets {
+		lines = append(lines, fmt.Sprintf("mc mb --ignore-existing %s/%s", alias, b))
+	}
+
+	return strings.Join(lines, " && ")
+}
+
+// CreateBuckets creates the buckets in the minio container.
+func CreateBuckets(ctx context.Context, cli client.ContainerAPIClient, containerID string, buckets []string) error {
+	if len(buckets) == 0 {
+		return nil
+	}
+
+	if _, err := containerexec.Run(ctx, cli, containerID, []string{"sh", "-c", BucketScript(buckets)}); err != nil {
+		return fmt.Errorf("unable to create the minio buckets, %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,20 @@
+package minio
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBucketScript(t *testing.T) {
+	got := BucketScript([]string{"assets", "uploads"})
+
+	for _, want := range []string{
+		"mc alias set nitro http://127.0.0.1:9000 nitro nitropassword",
+		"mc mb --ignore-existing nitro/assets",
+		"mc mb --ignore-existing nitro/uploads",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected the script to contain %q, got:\n%s", want, got)
+		}
+	}
+}
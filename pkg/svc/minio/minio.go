@@ -8,11 +8,15 @@ import (
 	"time"
 
 	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/environment"
+	"github.com/craftcms/nitro/pkg/reconcile"
 	"github.com/craftcms/nitro/pkg/terminal"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/mount"
 	"github.com/docker/docker/api/types/network"
+	volumetypes "github.com/docker/docker/api/types/volume"
 	"github.com/docker/docker/client"
 	"github.com/docker/go-connections/nat"
 )
@@ -26,13 +30,28 @@ const (
 
 	// Label is the label value used to mark a container as a "minio" service
 	Label = "minio"
+
+	// User is the access key for the minio service
+	User = "nitro"
+
+	// Password is the secret key for the minio service
+	Password = "nitropassword"
+
+	// EndpointEnvVar, AccessKeyEnvVar, and SecretKeyEnvVar are the environment variables
+	// set on the sites for the asset volumes when the service is enabled
+	EndpointEnvVar  = "MINIO_ENDPOINT"
+	AccessKeyEnvVar = "MINIO_ACCESS_KEY"
+	SecretKeyEnvVar = "MINIO_SECRET_KEY"
 )
 
+// Endpoint is the address of the minio API for the site containers.
+var Endpoint = fmt.Sprintf("http://%s:9000", Host)
+
 // VerifyCreated will verify that the minio service container exists and is started
-func VerifyCreated(ctx context.Context, cli client.CommonAPIClient, networkID string, output terminal.Outputer) (string, string, error) {
+func VerifyCreated(ctx context.Context, cli client.CommonAPIClient, networkID string, logs container.LogConfig, output terminal.Outputer) (string, string, error) {
 	// add the filter
 	filter := filters.NewArgs()
-	filter.Add("label", containerlabels.Nitro+"=true")
+	filter.Add("label", containerlabels.Nitro+"="+environment.Label())
 	filter.Add("label", containerlabels.Type+"="+Label)
 
 	// get a list of containers
@@ -44,6 +63,12 @@ func VerifyCreated(ctx context.Context, cli client.CommonAPIClient, networkID st
 		return "", "", err
 	}
 
+	// make sure existing containers are started and on the network
+	containers, err = reconcile.Containers(ctx, cli, networkID, containers)
+	if err != nil {
+		return "", "", err
+	}
+
 	// if there is not a container, create one
 	if len(containers) == 0 {
 		// pull the image
@@ -69,20 +94,41 @@ func VerifyCreated(ctx context.Context, cli client.CommonAPIClient, networkID st
 			return "", "", fmt.Errorf("unable to create the port, %w", err)
 		}
 
+		// create the volume so the data is kept when the container is recreated
+		volume, err := cli.VolumeCreate(ctx, volumetypes.VolumeCreateBody{
+			Driver: "local",
+			Name:   Host,
+			Labels: map[string]string{
+				containerlabels.Nitro: environment.Label(),
+				containerlabels.Type:  Label,
+			},
+		})
+		if err != nil {
+			return "", "", fmt.Errorf("unable to create the volume, %w", err)
+		}
+
 		containerConfig := &container.Config{
 			Image: Image,
 			Labels: map[string]string{
-				containerlabels.Nitro: "true",
+				containerlabels.Nitro: environment.Label(),
 				containerlabels.Type:  Label,
 			},
 			ExposedPorts: nat.PortSet{
 				httpPortNat: struct{}{},
 			},
 			Cmd: []string{"server", "/data"},
-			Env: []string{"MINIO_ROOT_USER=nitro", "MINIO_ROOT_PASSWORD=nitropassword"},
+			Env: []string{"MINIO_ROOT_USER=" + User, "MINIO_ROOT_PASSWORD=" + Password},
 		}
 
 		hostconfig := &container.HostConfig{
+			LogConfig: logs,
+			Mounts: []mount.Mount{
+				{
+					Type:   mount.TypeVolume,
+					Source: volume.Name,
+					Target: "/data",
+				},
+			},
 			PortBindings: map[nat.Port][]nat.PortBinding{
 				httpPortNat: {
 					{
@@ -95,7 +141,7 @@ func VerifyCreated(ctx context.Context, cli client.CommonAPIClient, networkID st
 
 		networkConfig := &network.NetworkingConfig{
 			EndpointsConfig: map[string]*network.EndpointSettings{
-				"nitro-network": {
+				environment.Network(): {
 					NetworkID: networkID,
 				},
 			},
@@ -132,7 +178,7 @@ func VerifyCreated(ctx context.Context, cli client.CommonAPIClient, networkID st
 func VerifyRemoved(ctx context.Context, cli client.CommonAPIClient, output terminal.Outputer) error {
 	// add the filter
 	filter := filters.NewArgs()
-	filter.Add("label", containerlabels.Nitro+"=true")
+	filter.Add("label", containerlabels.Nitro+"="+environment.Label())
 	filter.Add("label", containerlabels.Type+"="+Label)
 
 	// get a list of containers
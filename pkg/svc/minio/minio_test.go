@@ -16,7 +16,9 @@ import (
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/mount"
 	"github.com/docker/docker/api/types/network"
+	volumetypes "github.com/docker/docker/api/types/volume"
 	"github.com/docker/docker/client"
 	"github.com/docker/go-connections/nat"
 	v1 "github.com/opencontainers/image-spec/specs-go/v1"
@@ -84,6 +86,13 @@ func TestVerifyCreated(t *testing.T) {
 					Env: []string{"MINIO_ROOT_USER=nitro", "MINIO_ROOT_PASSWORD=nitropassword"},
 				},
 				HostConfig: &container.HostConfig{
+					Mounts: []mount.Mount{
+						{
+							Type:   mount.TypeVolume,
+							Source: "minio.service.nitro",
+							Target: "/data",
+						},
+					},
 					PortBindings: map[nat.Port][]nat.PortBinding{
 						"9000/tcp": {
 							{
@@ -143,6 +152,13 @@ func TestVerifyCreated(t *testing.T) {
 					Env: []string{"MINIO_ROOT_USER=nitro", "MINIO_ROOT_PASSWORD=nitropassword"},
 				},
 				HostConfig: &container.HostConfig{
+					Mounts: []mount.Mount{
+						{
+							Type:   mount.TypeVolume,
+							Source: "minio.service.nitro",
+							Target: "/data",
+						},
+					},
 					PortBindings: map[nat.Port][]nat.PortBinding{
 						"9000/tcp": {
 							{
@@ -219,7 +235,7 @@ func TestVerifyCreated(t *testing.T) {
 		}
 
 		t.Run(tt.name, func(t *testing.T) {
-			id, hostname, err := VerifyCreated(tt.args.ctx, tt.args.spy, tt.args.networkID, tt.args.output)
+			id, hostname, err := VerifyCreated(tt.args.ctx, tt.args.spy, tt.args.networkID, container.LogConfig{}, tt.args.output)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("VerifyCreated() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -402,6 +418,9 @@ type mockClient struct {
 	containerListOptions types.ContainerListOptions
 	containerListError   error
 
+	// volume create
+	volumeCreateOptions volumetypes.VolumeCreateBody
+
 	// container create
 	containerCreateConfig   types.ContainerCreateConfig
 	containerCreateResponse container.ContainerCreateCreatedBody
@@ -442,6 +461,12 @@ func (c *mockClient) ContainerRemove(ctx context.Context, containerID string, op
 	return c.containerRemoveError
 }
 
+func (c *mockClient) VolumeCreate(ctx context.Context, options volumetypes.VolumeCreateBody) (types.Volume, error) {
+	c.volumeCreateOptions = options
+
+	return types.Volume{Name: options.Name}, nil
+}
+
 func (c *mockClient) ContainerCreate(ctx context.Context, config *container.Config, hostConfig *container.HostConfig, networkingConfig *network.NetworkingConfig, platform *v1.Platform, containerName string) (container.ContainerCreateCreatedBody, error) {
 	c.containerCreateConfig = types.ContainerCreateConfig{
 		Name:             containerName,
@@ -8,11 +8,15 @@ import (
 	"time"
 
 	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/environment"
+	"github.com/craftcms/nitro/pkg/reconcile"
 	"github.com/craftcms/nitro/pkg/terminal"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/mount"
 	"github.com/docker/docker/api/types/network"
+	volumetypes "github.com/docker/docker/api/types/volume"
 	"github.com/docker/docker/client"
 	"github.com/docker/go-connections/nat"
 )
@@ -29,10 +33,10 @@ const (
 )
 
 // VerifyCreated will verify that the redis service container exists and is started
-func VerifyCreated(ctx context.Context, cli client.CommonAPIClient, networkID string, output terminal.Outputer) (string, string, error) {
+func VerifyCreated(ctx context.Context, cli client.CommonAPIClient, networkID string, logs container.LogConfig, output terminal.Outputer) (string, string, error) {
 	// add the filter
 	filter := filters.NewArgs()
-	filter.Add("label", containerlabels.Nitro+"=true")
+	filter.Add("label", containerlabels.Nitro+"="+environment.Label())
 	filter.Add("label", containerlabels.Type+"="+Label)
 
 	// get a list of containers
@@ -44,6 +48,12 @@ func VerifyCreated(ctx context.Context, cli client.CommonAPIClient, networkID st
 		return "", "", err
 	}
 
+	// make sure existing containers are started and on the network
+	containers, err = reconcile.Containers(ctx, cli, networkID, containers)
+	if err != nil {
+		return "", "", err
+	}
+
 	// if there is not a container, create one
 	if len(containers) == 0 {
 		// pull the image
@@ -69,10 +79,23 @@ func VerifyCreated(ctx context.Context, cli client.CommonAPIClient, networkID st
 			return "", "", fmt.Errorf("unable to create the port, %w", err)
 		}
 
+		// create the volume so the data is kept when the container is recreated
+		volume, err := cli.VolumeCreate(ctx, volumetypes.VolumeCreateBody{
+			Driver: "local",
+			Name:   Host,
+			Labels: map[string]string{
+				containerlabels.Nitro: environment.Label(),
+				containerlabels.Type:  Label,
+			},
+		})
+		if err != nil {
+			return "", "", fmt.Errorf("unable to create the volume, %w", err)
+		}
+
 		containerConfig := &container.Config{
 			Image: Image,
 			Labels: map[string]string{
-				containerlabels.Nitro: "true",
+				containerlabels.Nitro: environment.Label(),
 				containerlabels.Type:  Label,
 			},
 			ExposedPorts: nat.PortSet{
@@ -81,6 +104,14 @@ func VerifyCreated(ctx context.Context, cli client.CommonAPIClient, networkID st
 		}
 
 		hostconfig := &container.HostConfig{
+			LogConfig: logs,
+			Mounts: []mount.Mount{
+				{
+					Type:   mount.TypeVolume,
+					Source: volume.Name,
+					Target: "/data",
+				},
+			},
 			PortBindings: map[nat.Port][]nat.PortBinding{
 				httpPortNat: {
 					{
@@ -93,7 +124,7 @@ func VerifyCreated(ctx context.Context, cli client.CommonAPIClient, networkID st
 
 		networkConfig := &network.NetworkingConfig{
 			EndpointsConfig: map[string]*network.EndpointSettings{
-				"nitro-network": {
+				environment.Network(): {
 					NetworkID: networkID,
 				},
 			},
@@ -130,7 +161,7 @@ func VerifyCreated(ctx context.Context, cli client.CommonAPIClient, networkID st
 func VerifyRemoved(ctx context.Context, cli client.CommonAPIClient, output terminal.Outputer) error {
 	// add the filter
 	filter := filters.NewArgs()
-	filter.Add("label", containerlabels.Nitro+"=true")
+	filter.Add("label", containerlabels.Nitro+"="+environment.Label())
 	filter.Add("label", containerlabels.Type+"="+Label)
 
 	// get a list of containers
@@ -16,7 +16,9 @@ import (
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/mount"
 	"github.com/docker/docker/api/types/network"
+	volumetypes "github.com/docker/docker/api/types/volume"
 	"github.com/docker/docker/client"
 	"github.com/docker/go-connections/nat"
 	v1 "github.com/opencontainers/image-spec/specs-go/v1"
@@ -81,6 +83,13 @@ func TestVerifyCreated(t *testing.T) {
 					},
 				},
 				HostConfig: &container.HostConfig{
+					Mounts: []mount.Mount{
+						{
+							Type:   mount.TypeVolume,
+							Source: "redis.service.nitro",
+							Target: "/data",
+						},
+					},
 					PortBindings: map[nat.Port][]nat.PortBinding{
 						"6379/tcp": {
 							{
@@ -138,6 +147,13 @@ func TestVerifyCreated(t *testing.T) {
 					},
 				},
 				HostConfig: &container.HostConfig{
+					Mounts: []mount.Mount{
+						{
+							Type:   mount.TypeVolume,
+							Source: "redis.service.nitro",
+							Target: "/data",
+						},
+					},
 					PortBindings: map[nat.Port][]nat.PortBinding{
 						"6379/tcp": {
 							{
@@ -214,7 +230,7 @@ func TestVerifyCreated(t *testing.T) {
 		}
 
 		t.Run(tt.name, func(t *testing.T) {
-			id, hostname, err := VerifyCreated(tt.args.ctx, tt.args.spy, tt.args.networkID, tt.args.output)
+			id, hostname, err := VerifyCreated(tt.args.ctx, tt.args.spy, tt.args.networkID, container.LogConfig{}, tt.args.output)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("VerifyCreated() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -397,6 +413,9 @@ type mockClient struct {
 	containerListOptions types.ContainerListOptions
 	containerListError   error
 
+	// volume create
+	volumeCreateOptions volumetypes.VolumeCreateBody
+
 	// container create
 	containerCreateConfig   types.ContainerCreateConfig
 	containerCreateResponse container.ContainerCreateCreatedBody
@@ -437,6 +456,12 @@ func (c *mockClient) ContainerRemove(ctx context.Context, containerID string, op
 	return c.containerRemoveError
 }
 
+func (c *mockClient) VolumeCreate(ctx context.Context, options volumetypes.VolumeCreateBody) (types.Volume, error) {
+	c.volumeCreateOptions = options
+
+	return types.Volume{Name: options.Name}, nil
+}
+
 func (c *mockClient) ContainerCreate(ctx context.Context, config *container.Config, hostConfig *container.HostConfig, networkingConfig *network.NetworkingConfig, platform *v1.Platform, containerName string) (container.ContainerCreateCreatedBody, error) {
 	c.containerCreateConfig = types.ContainerCreateConfig{
 		Name:             containerName,
@@ -0,0 +1,189 @@
+package redisinsight
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/environment"
+	"github.com/craftcms/nitro/pkg/reconcile"
+	"github.com/craftcms/nitro/pkg/terminal"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/network"
+	volumetypes "github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/client"
+	"github.com/docker/go-connections/nat"
+)
+
+const (
+	// Image is the image to use for the redisinsight container
+	Image = "docker.io/redislabs/redisinsight:latest"
+
+	// Host is the hostname for the redisinsight container
+	Host = "redisinsight.service.nitro"
+
+	// Label is the label value used to mark a container as a "redisinsight" service
+	Label = "redisinsight"
+
+	// Port is the port of the web interface in the container, it is only served by the proxy
+	Port = 8001
+)
+
+// VerifyCreated will verify that the redisinsight container exists and is started. The web
+// interface is not bound to a port on the host since it is served by the proxy.
+func VerifyCreated(ctx context.Context, cli client.CommonAPIClient, networkID string, logs container.LogConfig, output terminal.Outputer) (string, string, error) {
+	// add the filter
+	filter := filters.NewArgs()
+	filter.Add("label", containerlabels.Nitro+"="+environment.Label())
+	filter.Add("label", containerlabels.Type+"="+Label)
+
+	// get a list of containers
+	containers, err := cli.ContainerList(ctx, types.ContainerListOptions{
+		All:     true,
+		Filters: filter,
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	// make sure existing containers are started and on the network
+	containers, err = reconcile.Containers(ctx, cli, networkID, containers)
+	if err != nil {
+		return "", "", err
+	}
+
+	// if there is not a container, create one
+	if len(containers) == 0 {
+		// pull the image
+		r, err := cli.ImagePull(ctx, Image, types.ImagePullOptions{})
+		if err != nil {
+			return "", "", err
+		}
+
+		// read from the buffer to pull the image
+		buf := &bytes.Buffer{}
+		if _, err := buf.ReadFrom(r); err != nil {
+			return "", "", fmt.Errorf("unable to read output while pulling image, %w", err)
+		}
+
+		httpPortNat, err := nat.NewPort("tcp", fmt.Sprintf("%d", Port))
+		if err != nil {
+			return "", "", fmt.Errorf("unable to create the port, %w", err)
+		}
+
+		// create the volume so the saved connections are kept when the container is recreated
+		volume, err := cli.VolumeCreate(ctx, volumetypes.VolumeCreateBody{
+			Driver: "local",
+			Name:   Host,
+			Labels: map[string]string{
+				containerlabels.Nitro: environment.Label(),
+				containerlabels.Type:  Label,
+			},
+		})
+		if err != nil {
+			return "", "", fmt.Errorf("unable to create the volume, %w", err)
+		}
+
+		containerConfig := &container.Config{
+			Image: Image,
+			Labels: map[string]string{
+				containerlabels.Nitro: environment.Label(),
+				containerlabels.Type:  Label,
+			},
+			ExposedPorts: nat.PortSet{
+				httpPortNat: struct{}{},
+			},
+		}
+
+		hostconfig := &container.HostConfig{
+			LogConfig: logs,
+			Mounts: []mount.Mount{
+				{
+					Type:   mount.TypeVolume,
+					Source: volume.Name,
+					Target: "/db",
+				},
+			},
+		}
+
+		networkConfig := &network.NetworkingConfig{
+			EndpointsConfig: map[string]*network.EndpointSettings{
+				environment.Network(): {
+					NetworkID: networkID,
+				},
+			},
+		}
+
+		// create the container
+		resp, err := cli.ContainerCreate(ctx, containerConfig, hostconfig, networkConfig, nil, Host)
+		if err != nil {
+			return "", "", fmt.Errorf("unable to create the container, %w", err)
+		}
+
+		// start the container
+		if err := cli.ContainerStart(ctx, resp.ID, types.ContainerStartOptions{}); err != nil {
+			return "", "", fmt.Errorf("unable to start the container, %w", err)
+		}
+
+		return resp.ID, Host, nil
+	}
+
+	// start each of the containers, there should only be one so the final return is an error
+	for _, c := range containers {
+		// start the container
+		if c.Status != "running" {
+			if err := cli.ContainerStart(ctx, c.ID, types.ContainerStartOptions{}); err != nil {
+				return "", "", fmt.Errorf("unable to start the container, %w", err)
+			}
+		}
+	}
+
+	return containers[0].ID, Host, nil
+}
+
+// VerifyRemoved will verify the container is not created for the redisinsight service, the
+// volume with the saved connections is kept.
+func VerifyRemoved(ctx context.Context, cli client.CommonAPIClient, output terminal.Outputer) error {
+	// add the filter
+	filter := filters.NewArgs()
+	filter.Add("label", containerlabels.Nitro+"="+environment.Label())
+	filter.Add("label", containerlabels.Type+"="+Label)
+
+	// get a list of containers
+	containers, err := cli.ContainerList(ctx, types.ContainerListOptions{
+		All:     true,
+		Filters: filter,
+	})
+	if err != nil {
+		return err
+	}
+
+	// we are all good, nothing to do
+	if len(containers) == 0 {
+		return nil
+	}
+
+	timeout := time.Duration(time.Second * 30)
+
+	// remove all of the containers
+	for _, c := range containers {
+		// stop the container if its running
+		if c.State == "running" {
+			if err := cli.ContainerStop(ctx, c.ID, &timeout); err != nil {
+				return err
+			}
+		}
+
+		// remove the container
+		if err := cli.ContainerRemove(ctx, c.ID, types.ContainerRemoveOptions{}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
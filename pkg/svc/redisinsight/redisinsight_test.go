@@ -0,0 +1,193 @@
+package redisinsight
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/network"
+	volumetypes "github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/client"
+	"github.com/docker/go-connections/nat"
+	v1 "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+func TestVerifyCreated(t *testing.T) {
+	tests := []struct {
+		name string
+		spy  *mockClient
+
+		wantSpyContainerCreateConfig types.ContainerCreateConfig
+		wantSpyContainerStartID      string
+		wantID                       string
+		wantErr                      bool
+	}{
+		{
+			name: "container is created without a host port when it does not exist",
+			spy: &mockClient{
+				containerCreateResponse: container.ContainerCreateCreatedBody{ID: "someid"},
+			},
+			wantSpyContainerCreateConfig: types.ContainerCreateConfig{
+				Name: "redisinsight.service.nitro",
+				Config: &container.Config{
+					Image: "docker.io/redislabs/redisinsight:latest",
+					Labels: map[string]string{
+						containerlabels.Nitro: "true",
+						containerlabels.Type:  "redisinsight",
+					},
+					ExposedPorts: nat.PortSet{
+						"8001/tcp": struct{}{},
+					},
+				},
+				HostConfig: &container.HostConfig{
+					Mounts: []mount.Mount{
+						{
+							Type:   mount.TypeVolume,
+							Source: "redisinsight.service.nitro",
+							Target: "/db",
+						},
+					},
+				},
+				NetworkingConfig: &network.NetworkingConfig{
+					EndpointsConfig: map[string]*network.EndpointSettings{
+						"nitro-network": {
+							NetworkID: "some-network-id",
+						},
+					},
+				},
+			},
+			wantSpyContainerStartID: "someid",
+			wantID:                  "someid",
+		},
+		{
+			name: "existing containers are started",
+			spy: &mockClient{
+				containers: []types.Container{{ID: "existing-container-id", Status: "exited"}},
+			},
+			wantSpyContainerStartID: "existing-container-id",
+			wantID:                  "existing-container-id",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, hostname, err := VerifyCreated(context.Background(), tt.spy, "some-network-id", container.LogConfig{}, nil)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("VerifyCreated() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if id != tt.wantID {
+				t.Errorf("VerifyCreated() got = %v, want %v", id, tt.wantID)
+			}
+
+			if hostname != "redisinsight.service.nitro" {
+				t.Errorf("VerifyCreated() got1 = %v, want redisinsight.service.nitro", hostname)
+			}
+
+			want := types.ContainerListOptions{
+				All: true,
+				Filters: filters.NewArgs(
+					filters.KeyValuePair{Key: "label", Value: containerlabels.Nitro + "=true"},
+					filters.KeyValuePair{Key: "label", Value: containerlabels.Type + "=redisinsight"},
+				),
+			}
+			if !reflect.DeepEqual(want, tt.spy.containerListOptions) {
+				t.Errorf("expected the container list options to to match, got %v want %v", tt.spy.containerListOptions, want)
+			}
+
+			if !reflect.DeepEqual(tt.wantSpyContainerCreateConfig, tt.spy.containerCreateConfig) {
+				t.Errorf("expected the container create config to to match, got %v want %v", tt.spy.containerCreateConfig, tt.wantSpyContainerCreateConfig)
+			}
+
+			if tt.wantSpyContainerStartID != tt.spy.containerStartID {
+				t.Errorf("expected the container start ids to match, got %s want %s", tt.spy.containerStartID, tt.wantSpyContainerStartID)
+			}
+		})
+	}
+}
+
+func TestVerifyRemoved(t *testing.T) {
+	spy := &mockClient{containers: []types.Container{{ID: "some-random-id", State: "running"}}}
+
+	if err := VerifyRemoved(context.TODO(), spy, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if spy.containerStopID != "some-random-id" || spy.containerRemoveID != "some-random-id" {
+		t.Errorf("expected the container to be stopped and removed, got %s and %s", spy.containerStopID, spy.containerRemoveID)
+	}
+
+	// the volume with the saved connections is kept
+	if spy.containerRemoveOptions.RemoveVolumes {
+		t.Errorf("expected the volumes to be kept")
+	}
+}
+
+type mockClient struct {
+	client.CommonAPIClient
+
+	containers           []types.Container
+	containerListOptions types.ContainerListOptions
+
+	containerCreateConfig   types.ContainerCreateConfig
+	containerCreateResponse container.ContainerCreateCreatedBody
+
+	containerStartID string
+	containerStopID  string
+
+	containerRemoveID      string
+	containerRemoveOptions types.ContainerRemoveOptions
+}
+
+func (c *mockClient) ContainerList(ctx context.Context, options types.ContainerListOptions) ([]types.Container, error) {
+	c.containerListOptions = options
+
+	return c.containers, nil
+}
+
+func (c *mockClient) ContainerRemove(ctx context.Context, containerID string, opts types.ContainerRemoveOptions) error {
+	c.containerRemoveID = containerID
+	c.containerRemoveOptions = opts
+
+	return nil
+}
+
+func (c *mockClient) VolumeCreate(ctx context.Context, options volumetypes.VolumeCreateBody) (types.Volume, error) {
+	return types.Volume{Name: options.Name}, nil
+}
+
+func (c *mockClient) ContainerCreate(ctx context.Context, config *container.Config, hostConfig *container.HostConfig, networkingConfig *network.NetworkingConfig, platform *v1.Platform, containerName string) (container.ContainerCreateCreatedBody, error) {
+	c.containerCreateConfig = types.ContainerCreateConfig{
+		Name:             containerName,
+		Config:           config,
+		HostConfig:       hostConfig,
+		NetworkingConfig: networkingConfig,
+	}
+
+	return c.containerCreateResponse, nil
+}
+
+func (c *mockClient) ContainerStart(ctx context.Context, container string, options types.ContainerStartOptions) error {
+	c.containerStartID = container
+
+	return nil
+}
+
+func (c *mockClient) ContainerStop(ctx context.Context, containerID string, timeout *time.Duration) error {
+	c.containerStopID = containerID
+
+	return nil
+}
+
+func (c *mockClient) ImagePull(ctx context.Context, image string, opts types.ImagePullOptions) (io.ReadCloser, error) {
+	return ioutil.NopCloser(strings.NewReader("")), nil
+}
@@ -0,0 +1,57 @@
+package terminal
+
+import (
+	"os"
+
+	"github.com/moby/term"
+)
+
+const (
+	// Red is used for removed values
+	Red = "\033[31m"
+
+	// Green is used for added values
+	Green = "\033[32m"
+
+	// Yellow is used for changed values
+	Yellow = "\033[33m"
+
+	reset = "\033[0m"
+)
+
+// NoColor disables colors in the output, it is set by the global --no-color flag.
+var NoColor bool
+
+// stdoutIsTerminal checks if the output is shown in a terminal, it is a variable for testing.
+var stdoutIsTerminal = func() bool {
+	return term.IsTerminal(os.Stdout.Fd())
+}
+
+// Colors returns true when the output should use colors. Colors are disabled with
+// the --no-color flag, the NO_COLOR environment variable (https://no-color.org),
+// when the output is not a terminal (e.g. piped to a file), or when the terminal
+// is dumb.
+func Colors() bool {
+	if NoColor {
+		return false
+	}
+
+	if !stdoutIsTerminal() {
+		return false
+	}
+
+	if _, ok := os.LookupEnv("NO_COLOR"); ok {
+		return false
+	}
+
+	return os.Getenv("TERM") != "dumb"
+}
+
+// Color returns s in the color when colors are enabled, otherwise s is returned unchanged.
+func Color(color, s string) string {
+	if !Colors() {
+		return s
+	}
+
+	return color + s + reset
+}
@@ -0,0 +1,76 @@
+package terminal
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestColor(t *testing.T) {
+	os.Unsetenv("NO_COLOR")
+	os.Setenv("TERM", "xterm-256color")
+
+	isTerminal := stdoutIsTerminal
+	defer func() { stdoutIsTerminal = isTerminal }()
+	stdoutIsTerminal = func() bool { return true }
+
+	if got := Color(Red, "removed"); got != "\033[31mremoved\033[0m" {
+		t.Errorf("expected the text to be red, got %q", got)
+	}
+
+	NoColor = true
+	if got := Color(Red, "removed"); got != "removed" {
+		t.Errorf("expected --no-color to disable colors, got %q", got)
+	}
+	NoColor = false
+
+	os.Setenv("NO_COLOR", "")
+	defer os.Unsetenv("NO_COLOR")
+
+	if got := Color(Green, "added"); got != "added" {
+		t.Errorf("expected NO_COLOR to disable colors, got %q", got)
+	}
+	os.Unsetenv("NO_COLOR")
+
+	stdoutIsTerminal = func() bool { return false }
+	if got := Color(Yellow, "changed"); got != "changed" {
+		t.Errorf("expected output that is not a terminal to disable colors, got %q", got)
+	}
+}
+
+func TestNewPager_NotATerminal(t *testing.T) {
+	f, err := ioutil.TempFile("", "nitro-pager")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	p := NewPager(f)
+	if p.Paging() {
+		t.Errorf("expected files to not be paged")
+	}
+
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_pagerCommand(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want string
+	}{
+		{name: "defaults to less", env: map[string]string{}, want: "less"},
+		{name: "uses the pager env", env: map[string]string{"PAGER": "more"}, want: "more"},
+		{name: "nitro pager takes precedence", env: map[string]string{"PAGER": "more", "NITRO_PAGER": "bat"}, want: "bat"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pagerCommand(func(k string) string { return tt.env[k] }); got != tt.want {
+				t.Errorf("pagerCommand() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
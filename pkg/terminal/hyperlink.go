@@ -0,0 +1,69 @@
+package terminal
+
+import (
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/moby/term"
+)
+
+var escapes = regexp.MustCompile(`\x1b\]8;;[^\x1b]*\x1b\\|\x1b\[[0-9;]*m`)
+
+// Hyperlink returns the text as an OSC-8 hyperlink to the url when the terminal
+// supports hyperlinks, otherwise it returns the text unchanged. Hyperlinks can be
+// disabled by setting the NITRO_NO_HYPERLINKS environment variable.
+func Hyperlink(url, text string) string {
+	if !SupportsHyperlinks() {
+		return text
+	}
+
+	return "\x1b]8;;" + url + "\x1b\\" + text + "\x1b]8;;\x1b\\"
+}
+
+// SupportsHyperlinks checks if stdout is a terminal that is known to support
+// OSC-8 hyperlinks. Terminals that do not support them print the escape
+// sequences as is, so only known terminals are allowed.
+func SupportsHyperlinks() bool {
+	if _, ok := os.LookupEnv("NITRO_NO_HYPERLINKS"); ok {
+		return false
+	}
+
+	if !term.IsTerminal(os.Stdout.Fd()) {
+		return false
+	}
+
+	return supported(os.Getenv)
+}
+
+// Width returns the number of characters shown in the terminal for the string,
+// ignoring the escape sequences for hyperlinks and colors. It is used to align
+// tables that contain hyperlinks.
+func Width(s string) int {
+	return utf8.RuneCountInString(escapes.ReplaceAllString(s, ""))
+}
+
+func supported(getenv func(string) string) bool {
+	if getenv("TERM") == "dumb" {
+		return false
+	}
+
+	// windows terminal and kitty
+	if getenv("WT_SESSION") != "" || getenv("KITTY_WINDOW_ID") != "" {
+		return true
+	}
+
+	switch getenv("TERM_PROGRAM") {
+	case "iTerm.app", "WezTerm", "vscode", "Hyper":
+		return true
+	}
+
+	// gnome terminal and other vte based terminals added support in 0.50
+	if v, err := strconv.Atoi(getenv("VTE_VERSION")); err == nil && v >= 5000 {
+		return true
+	}
+
+	return strings.HasPrefix(getenv("TERM"), "xterm-kitty")
+}
@@ -0,0 +1,82 @@
+package terminal
+
+import "testing"
+
+func TestWidth(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want int
+	}{
+		{
+			name: "plain text returns the length",
+			s:    "tutorial.nitro",
+			want: 14,
+		},
+		{
+			name: "hyperlinks only count the text",
+			s:    "\x1b]8;;https://tutorial.nitro\x1b\\tutorial.nitro\x1b]8;;\x1b\\",
+			want: 14,
+		},
+		{
+			name: "colors are ignored",
+			s:    "\x1b[32mrunning\x1b[0m",
+			want: 7,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Width(tt.s); got != tt.want {
+				t.Errorf("Width() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSupported(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want bool
+	}{
+		{
+			name: "iterm supports hyperlinks",
+			env:  map[string]string{"TERM_PROGRAM": "iTerm.app"},
+			want: true,
+		},
+		{
+			name: "windows terminal supports hyperlinks",
+			env:  map[string]string{"WT_SESSION": "some-session"},
+			want: true,
+		},
+		{
+			name: "new vte versions support hyperlinks",
+			env:  map[string]string{"VTE_VERSION": "6003"},
+			want: true,
+		},
+		{
+			name: "old vte versions do not support hyperlinks",
+			env:  map[string]string{"VTE_VERSION": "4205"},
+			want: false,
+		},
+		{
+			name: "dumb terminals do not support hyperlinks",
+			env:  map[string]string{"TERM": "dumb", "TERM_PROGRAM": "iTerm.app"},
+			want: false,
+		},
+		{
+			name: "unknown terminals do not support hyperlinks",
+			env:  map[string]string{"TERM_PROGRAM": "Apple_Terminal"},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getenv := func(k string) string { return tt.env[k] }
+
+			if got := supported(getenv); got != tt.want {
+				t.Errorf("supported() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
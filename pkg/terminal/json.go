@@ -0,0 +1,35 @@
+package terminal
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+)
+
+// JSON enables machine-readable output, it is set by the global --json flag. Commands that
+// support it write their result with Outputer.JSON and the other output is suppressed, so
+// the output can be used by scripts and editors.
+var JSON bool
+
+// JSON writes v to stdout as indented JSON.
+func (t terminal) JSON(v interface{}) error {
+	return writeJSON(os.Stdout, v)
+}
+
+// out returns where prompts are written. When the output is JSON, prompts are written
+// to stderr so they do not become part of the result.
+func (t terminal) out() io.Writer {
+	if JSON {
+		return os.Stderr
+	}
+
+	return os.Stdout
+}
+
+func writeJSON(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(v)
+}
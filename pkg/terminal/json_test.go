@@ -0,0 +1,40 @@
+package terminal
+
+import (
+	"bytes"
+	"os"
+	"testing"
+)
+
+func TestWriteJSON(t *testing.T) {
+	buf := &bytes.Buffer{}
+
+	v := struct {
+		URL  string   `json:"url"`
+		Tags []string `json:"tags"`
+	}{URL: "https://craft.nitro/?a=1&b=2", Tags: []string{"site"}}
+
+	if err := writeJSON(buf, v); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "{\n  \"url\": \"https://craft.nitro/?a=1&b=2\",\n  \"tags\": [\n    \"site\"\n  ]\n}\n"
+	if got := buf.String(); got != want {
+		t.Errorf("expected indented JSON without escaped HTML, got %q", got)
+	}
+}
+
+func TestPromptsUseStderrForJSON(t *testing.T) {
+	term := New()
+
+	if got := term.out(); got != os.Stdout {
+		t.Errorf("expected prompts to be written to stdout")
+	}
+
+	JSON = true
+	defer func() { JSON = false }()
+
+	if got := term.out(); got != os.Stderr {
+		t.Errorf("expected prompts to be written to stderr when the output is JSON")
+	}
+}
@@ -0,0 +1,98 @@
+package terminal
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/moby/term"
+)
+
+// NoPager disables paging the output, it is set by the global --no-pager flag.
+var NoPager bool
+
+// Pager writes long output through the users pager. When the output is not a terminal,
+// or paging is disabled, the output is written as is.
+type Pager struct {
+	io.Writer
+
+	cmd  *exec.Cmd
+	pipe io.WriteCloser
+}
+
+// NewPager returns a pager for w. The pager uses the PAGER environment variable and
+// defaults to less, which exits right away when the output fits on one screen. Paging
+// is only used when w is stdout and stdout is a terminal. Close must be called once
+// all of the output is written.
+func NewPager(w io.Writer) *Pager {
+	p := &Pager{Writer: w}
+
+	if NoPager || w != os.Stdout || !term.IsTerminal(os.Stdout.Fd()) {
+		return p
+	}
+
+	args := strings.Fields(pagerCommand(os.Getenv))
+	if len(args) == 0 || args[0] == "cat" {
+		return p
+	}
+
+	path, err := exec.LookPath(args[0])
+	if err != nil {
+		return p
+	}
+
+	cmd := exec.Command(path, args[1:]...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	// show colors and exit when the output fits on one screen, unless the user set the options
+	if _, ok := os.LookupEnv("LESS"); !ok {
+		cmd.Env = append(os.Environ(), "LESS=FRX")
+	}
+
+	pipe, err := cmd.StdinPipe()
+	if err != nil {
+		return p
+	}
+
+	if err := cmd.Start(); err != nil {
+		return p
+	}
+
+	p.Writer, p.cmd, p.pipe = pipe, cmd, pipe
+
+	return p
+}
+
+// Paging returns true when the output is written to the pager.
+func (p *Pager) Paging() bool {
+	return p.cmd != nil
+}
+
+// Close waits for the user to exit the pager.
+func (p *Pager) Close() error {
+	if p.cmd == nil {
+		return nil
+	}
+
+	p.pipe.Close()
+
+	err := p.cmd.Wait()
+	p.cmd = nil
+
+	return err
+}
+
+// pagerCommand returns the command for the pager, NITRO_PAGER takes precedence over PAGER.
+func pagerCommand(getenv func(string) string) string {
+	if p := getenv("NITRO_PAGER"); p != "" {
+		return p
+	}
+
+	if p := getenv("PAGER"); p != "" {
+		return p
+	}
+
+	return "less"
+}
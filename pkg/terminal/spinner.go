@@ -0,0 +1,106 @@
+package terminal
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/moby/term"
+)
+
+// spinnerFrames are the frames of the spinner animation.
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// Spinner shows a message with an animation while nitro waits on docker, such as for the
+// containers to be healthy. The animation is only shown when stdout is a terminal, otherwise
+// the message is shown once like Pending.
+type Spinner struct {
+	w       io.Writer
+	animate bool
+
+	mu   sync.Mutex
+	msg  string
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewSpinner shows the message with the spinner until Stop is called.
+func NewSpinner(msg string) *Spinner {
+	return newSpinner(os.Stdout, term.IsTerminal(os.Stdout.Fd()), msg)
+}
+
+func newSpinner(w io.Writer, animate bool, msg string) *Spinner {
+	s := &Spinner{w: w, animate: animate && !JSON, msg: msg, stop: make(chan struct{}), done: make(chan struct{})}
+
+	if !s.animate {
+		close(s.done)
+
+		if !JSON {
+			fmt.Fprintf(w, "  … %s ", msg)
+		}
+
+		return s
+	}
+
+	go s.run()
+
+	return s
+}
+
+// Update changes the message of the spinner, it is only shown when the spinner is animated.
+func (s *Spinner) Update(msg string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.msg = msg
+}
+
+// Stop stops the animation and shows the last message with a check mark, or a cross when ok
+// is false.
+func (s *Spinner) Stop(ok bool) {
+	if s.animate {
+		close(s.stop)
+	}
+
+	<-s.done
+
+	if JSON {
+		return
+	}
+
+	mark := "✓"
+	if !ok {
+		mark = "✗"
+	}
+
+	if s.animate {
+		s.mu.Lock()
+		fmt.Fprintf(s.w, "\r\033[K  … %s %s\n", s.msg, mark)
+		s.mu.Unlock()
+
+		return
+	}
+
+	fmt.Fprintln(s.w, mark)
+}
+
+func (s *Spinner) run() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+
+	for i := 0; ; i++ {
+		s.mu.Lock()
+		fmt.Fprintf(s.w, "\r\033[K  %s %s", spinnerFrames[i%len(spinnerFrames)], s.msg)
+		s.mu.Unlock()
+
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package terminal
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestSpinner(t *testing.T) {
+	buf := &bytes.Buffer{}
+
+	// the message is shown once when the output is not a terminal
+	s := newSpinner(buf, false, "waiting for containers")
+	s.Update("waiting for mysql-8.0-3306.database.nitro")
+	s.Stop(true)
+
+	if got := buf.String(); got != "  … waiting for containers ✓\n" {
+		t.Errorf("expected the message with a check mark, got %q", got)
+	}
+
+	buf.Reset()
+
+	s = newSpinner(buf, true, "waiting for containers")
+	s.Update("waiting for tutorial.nitro")
+	s.Stop(false)
+
+	if got := buf.String(); !strings.HasSuffix(got, "\r\033[K  … waiting for tutorial.nitro ✗\n") {
+		t.Errorf("expected the last message with a cross, got %q", got)
+	}
+}
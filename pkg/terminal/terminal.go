@@ -20,6 +20,7 @@ type Outputer interface {
 	Select(r io.Reader, msg string, opts []string) (int, error)
 	Warning()
 	Done()
+	JSON(v interface{}) error
 }
 
 type Asker interface {
@@ -109,8 +110,6 @@ func (t *terminal) Confirm(message string, fallback bool, sep string) (bool, err
 		default:
 			return fallback, nil
 		}
-
-		break
 	}
 	if err := s.Err(); err != nil {
 		return fallback, err
@@ -121,20 +120,20 @@ func (t *terminal) Confirm(message string, fallback bool, sep string) (bool, err
 
 func (t *terminal) printBoolMessage(message string, fallback bool, sep string) {
 	if fallback {
-		fmt.Printf("%s [Y/n]%s ", message, sep)
+		fmt.Fprintf(t.out(), "%s [Y/n]%s ", message, sep)
 		return
 	}
 
-	fmt.Printf("%s [y/N]%s ", message, sep)
+	fmt.Fprintf(t.out(), "%s [y/N]%s ", message, sep)
 }
 
 func (t *terminal) printStrMessage(message, fallback, sep string) {
 	if fallback == "" {
-		fmt.Fprintf(os.Stdout, "%s%s ", message, sep)
+		fmt.Fprintf(t.out(), "%s%s ", message, sep)
 		return
 	}
 
-	fmt.Fprintf(os.Stdout, "%s [%s]%s ", message, fallback, sep)
+	fmt.Fprintf(t.out(), "%s [%s]%s ", message, fallback, sep)
 }
 
 func (t *terminal) printValidatorError(err error) {
@@ -142,22 +141,42 @@ func (t *terminal) printValidatorError(err error) {
 }
 
 func (t terminal) Info(s ...string) {
+	if JSON {
+		return
+	}
+
 	fmt.Printf("%s\n", strings.Join(s, " "))
 }
 
 func (t terminal) Success(s ...string) {
+	if JSON {
+		return
+	}
+
 	fmt.Printf("  \u2713 %s\n", strings.Join(s, " "))
 }
 
 func (t terminal) Pending(s ...string) {
+	if JSON {
+		return
+	}
+
 	fmt.Printf("  … %s ", strings.Join(s, " "))
 }
 
 func (t terminal) Done() {
+	if JSON {
+		return
+	}
+
 	fmt.Print("\u2713\n")
 }
 
 func (t terminal) Warning() {
+	if JSON {
+		return
+	}
+
 	fmt.Print("\u2717\n")
 }
 
@@ -168,14 +187,14 @@ func (t terminal) Select(r io.Reader, msg string, opts []string) (int, error) {
 	}
 
 	// show the message
-	fmt.Println(msg)
+	fmt.Fprintln(t.out(), msg)
 
 	// show all the options
 	for k, v := range opts {
-		fmt.Printf("  %d. %s\n", k+1, v)
+		fmt.Fprintf(t.out(), "  %d. %s\n", k+1, v)
 	}
 
-	fmt.Print("Enter your selection: ")
+	fmt.Fprint(t.out(), "Enter your selection: ")
 
 	// create for loop until the input is valid
 	var selection int
@@ -194,13 +213,13 @@ func (t terminal) Select(r io.Reader, msg string, opts []string) (int, error) {
 		s, err := strconv.Atoi(char)
 		if err != nil || len(opts) < s {
 			wait = true
-			fmt.Println("Please choose a valid option:")
+			fmt.Fprintln(t.out(), "Please choose a valid option:")
 
 			for k, v := range opts {
-				fmt.Printf("  %d. %s\n", k+1, v)
+				fmt.Fprintf(t.out(), "  %d. %s\n", k+1, v)
 			}
 
-			fmt.Print(msg)
+			fmt.Fprint(t.out(), msg)
 		} else {
 			// take away one from the selection
 			selection = s - 1
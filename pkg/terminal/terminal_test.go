@@ -0,0 +1,53 @@
+package terminal
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestConfirm(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		fallback bool
+		want     bool
+	}{
+		{name: "yes returns true", input: "y\n", fallback: false, want: true},
+		{name: "no returns false", input: "No\n", fallback: true, want: false},
+		{name: "blank input returns the fallback", input: "\n", fallback: true, want: true},
+		{name: "other input returns the fallback", input: "maybe\ny\n", fallback: false, want: false},
+		{name: "no input returns the fallback", input: "", fallback: true, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stdin := os.Stdin
+			defer func() { os.Stdin = stdin }()
+
+			f, err := ioutil.TempFile(t.TempDir(), "stdin")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+
+			if _, err := f.WriteString(tt.input); err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := f.Seek(0, 0); err != nil {
+				t.Fatal(err)
+			}
+
+			os.Stdin = f
+
+			got, err := New().Confirm("continue?", tt.fallback, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got != tt.want {
+				t.Errorf("Confirm() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
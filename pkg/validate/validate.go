@@ -55,6 +55,25 @@ func (v *HostnameValidator) Validate(input string) error {
 	return nil
 }
 
+// TLDValidator is used to validate the top level domain used for new sites
+type TLDValidator struct{}
+
+func (v *TLDValidator) Validate(input string) error {
+	input = strings.TrimPrefix(input, ".")
+
+	if len(input) < 2 {
+		return fmt.Errorf("tld must be at least 2 characters")
+	}
+
+	for _, r := range input {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' && r != '.' {
+			return fmt.Errorf("tld must only include lowercase letters, numbers, hyphens, and periods")
+		}
+	}
+
+	return nil
+}
+
 // IntegerValidator validates if the input is a valid integer
 type IntegerValidator struct{}
 
@@ -66,8 +85,11 @@ func (v *IntegerValidator) Validate(input string) error {
 	return nil
 }
 
-// MultipleHostnameValidator validates a comma separated list of hostnames
-type MultipleHostnameValidator struct{}
+// MultipleHostnameValidator validates a comma separated list of hostnames, an empty list
+// is valid when Optional is set.
+type MultipleHostnameValidator struct {
+	Optional bool
+}
 
 func (v *MultipleHostnameValidator) Validate(input string) error {
 	_, err := v.Parse(input)
@@ -75,6 +97,10 @@ func (v *MultipleHostnameValidator) Validate(input string) error {
 }
 
 func (v *MultipleHostnameValidator) Parse(input string) ([]string, error) {
+	if v.Optional && strings.TrimSpace(input) == "" {
+		return nil, nil
+	}
+
 	rawHosts := strings.Split(input, ",")
 	hostV := &HostnameValidator{}
 	var hosts []string
@@ -1,6 +1,7 @@
 package validate
 
 import (
+	"reflect"
 	"testing"
 )
 
@@ -180,3 +181,89 @@ func TestIsBoolean_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestTLDValidator_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{
+			name:  "valid tlds do not return an err",
+			input: "nitro",
+		},
+		{
+			name:  "leading periods are ignored",
+			input: ".test",
+		},
+		{
+			name:    "single characters return an err",
+			input:   "t",
+			wantErr: true,
+		},
+		{
+			name:    "uppercase letters return an err",
+			input:   "Nitro",
+			wantErr: true,
+		},
+		{
+			name:    "spaces return an err",
+			input:   "my tld",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &TLDValidator{}
+			if err := v.Validate(tt.input); (err != nil) != tt.wantErr {
+				t.Errorf("TLDValidator.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMultipleHostnameValidator_Parse(t *testing.T) {
+	tests := []struct {
+		name    string
+		v       *MultipleHostnameValidator
+		input   string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:  "comma separated hostnames are trimmed",
+			v:     &MultipleHostnameValidator{},
+			input: "craft.nitro, www.craft.nitro",
+			want:  []string{"craft.nitro", "www.craft.nitro"},
+		},
+		{
+			name:    "empty input returns an err",
+			v:       &MultipleHostnameValidator{},
+			input:   "",
+			wantErr: true,
+		},
+		{
+			name:  "empty input is valid when optional",
+			v:     &MultipleHostnameValidator{Optional: true},
+			input: " ",
+		},
+		{
+			name:    "invalid hostnames return an err when optional",
+			v:       &MultipleHostnameValidator{Optional: true},
+			input:   "craft.nitro,bad host",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.v.Parse(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("MultipleHostnameValidator.Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) && !tt.wantErr {
+				t.Errorf("MultipleHostnameValidator.Parse() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
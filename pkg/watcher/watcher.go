@@ -0,0 +1,116 @@
+package watcher
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// Delay is how long the files must stop changing before the func is called, so an editor
+// that saves a file in several writes or a git checkout that changes many files only calls
+// the func once.
+var Delay = 500 * time.Millisecond
+
+// Watch calls fn each time the file, or the files in the directory and its subdirectories,
+// change until the context is done or the command is interrupted. A file is watched using
+// its directory, so editors that replace the file when saving it are handled. The changes
+// made while fn runs are ignored. The context passed to fn is done once the command is
+// interrupted.
+func Watch(ctx context.Context, path string, delay time.Duration, fn func(ctx context.Context)) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+
+	file := ""
+	if info.IsDir() {
+		err = add(w, path)
+	} else {
+		file = filepath.Clean(path)
+		err = w.Add(filepath.Dir(file))
+	}
+	if err != nil {
+		return err
+	}
+
+	timer := time.NewTimer(delay)
+	timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-w.Errors:
+			return err
+		case e := <-w.Events:
+			if file != "" && filepath.Clean(e.Name) != file {
+				continue
+			}
+
+			// watch the directories that are created
+			if file == "" && e.Op&fsnotify.Create != 0 {
+				if info, err := os.Stat(e.Name); err == nil && info.IsDir() {
+					_ = add(w, e.Name)
+				}
+			}
+
+			if !timer.Stop() {
+				select {
+				case <-timer.C:
+				default:
+				}
+			}
+
+			timer.Reset(delay)
+		case <-timer.C:
+			// wait for the file to be written when it was removed
+			if _, err := os.Stat(path); err != nil {
+				continue
+			}
+
+			fn(ctx)
+
+			drain(w)
+		}
+	}
+}
+
+// add watches the directory and its subdirectories.
+func add(w *fsnotify.Watcher, dir string) error {
+	return filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !info.IsDir() {
+			return nil
+		}
+
+		return w.Add(p)
+	})
+}
+
+// drain discards the events that are waiting, such as the changes made by the func.
+func drain(w *fsnotify.Watcher) {
+	for {
+		select {
+		case <-w.Events:
+		default:
+			return
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package watcher
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWatch_File(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nitro-watch-file")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "nitro.yaml")
+	if err := ioutil.WriteFile(file, []byte("sites: []\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	changed := make(chan struct{}, 10)
+	done := make(chan error)
+	go func() {
+		done <- Watch(ctx, file, 50*time.Millisecond, func(context.Context) { changed <- struct{}{} })
+	}()
+
+	// let the watcher start
+	time.Sleep(50 * time.Millisecond)
+
+	// changes to the other files in the directory are ignored
+	if err := ioutil.WriteFile(filepath.Join(dir, "other.yaml"), []byte("sites: []\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// an editor that removes the file before writing it only calls the func once
+	if err := os.Remove(file); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ioutil.WriteFile(file, []byte("sites:\n  - hostname: tutorial.nitro\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-changed:
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected the func to be called after the change")
+	}
+
+	time.Sleep(100 * time.Millisecond)
+
+	cancel()
+
+	if err := <-done; err != nil {
+		t.Errorf("expected no error when the context is done, got %v", err)
+	}
+
+	if len(changed) != 0 {
+		t.Errorf("expected the func to be called once, got %d more", len(changed))
+	}
+}
+
+func TestWatch_Directory(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nitro-watch-dir")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := os.Mkdir(filepath.Join(dir, "sections"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	changed := make(chan struct{}, 10)
+	done := make(chan error)
+	go func() {
+		done <- Watch(ctx, dir, 50*time.Millisecond, func(context.Context) { changed <- struct{}{} })
+	}()
+
+	// let the watcher start
+	time.Sleep(50 * time.Millisecond)
+
+	// several changes at once, including in subdirectories, only call the func once
+	for _, f := range []string{"project.yaml", filepath.Join("sections", "news.yaml")} {
+		if err := ioutil.WriteFile(filepath.Join(dir, f), []byte("changed"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	select {
+	case <-changed:
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected the func to be called after the change")
+	}
+
+	time.Sleep(100 * time.Millisecond)
+
+	cancel()
+
+	if err := <-done; err != nil {
+		t.Errorf("expected no error when the context is done, got %v", err)
+	}
+
+	if len(changed) != 0 {
+		t.Errorf("expected the func to be called once, got %d more", len(changed))
+	}
+}
@@ -0,0 +1,115 @@
+package workspace
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/pathexists"
+)
+
+// Repos takes a workspace directory and returns the absolute path of
+// each git repository in the directory. Only the directories in the
+// root of the workspace are checked.
+func Repos(dir string) ([]string, error) {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the workspace %s, %w", dir, err)
+	}
+
+	var repos []string
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+
+		path := filepath.Join(dir, e.Name())
+		if pathexists.IsDirectory(filepath.Join(path, ".git")) {
+			repos = append(repos, path)
+		}
+	}
+
+	sort.Strings(repos)
+
+	return repos, nil
+}
+
+// AllRepos returns the git repositories for all of the workspaces in the config.
+func AllRepos(home string, cfg *config.Config) ([]string, error) {
+	dirs, err := cfg.GetWorkspaces(home)
+	if err != nil {
+		return nil, err
+	}
+
+	var repos []string
+	for _, dir := range dirs {
+		r, err := Repos(dir)
+		if err != nil {
+			return nil, err
+		}
+
+		repos = append(repos, r...)
+	}
+
+	return repos, nil
+}
+
+// Site takes a repository path and returns the site that uses the
+// repository. If there is no site for the repository, it returns nil.
+func Site(home, repo string, sites []config.Site) *config.Site {
+	for _, s := range sites {
+		path, err := s.GetAbsPath(home)
+		if err != nil {
+			continue
+		}
+
+		if path == repo {
+			return &s
+		}
+	}
+
+	return nil
+}
+
+// Branch returns the current branch for the repository.
+func Branch(repo string) (string, error) {
+	out, err := git(repo, "rev-parse", "--abbrev-ref", "HEAD")
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(out), nil
+}
+
+// Changes returns the number of uncommitted changes in the repository.
+func Changes(repo string) (int, error) {
+	out, err := git(repo, "status", "--porcelain")
+	if err != nil {
+		return 0, err
+	}
+
+	out = strings.TrimSpace(out)
+	if out == "" {
+		return 0, nil
+	}
+
+	return len(strings.Split(out, "\n")), nil
+}
+
+// Pull runs a fast-forward only pull for the repository.
+func Pull(repo string) (string, error) {
+	return git(repo, "pull", "--ff-only")
+}
+
+func git(repo string, args ...string) (string, error) {
+	out, err := exec.Command("git", append([]string{"-C", repo}, args...)...).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("%s, %w", strings.TrimSpace(string(out)), err)
+	}
+
+	return string(out), nil
+}
@@ -0,0 +1,61 @@
+package workspace
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/craftcms/nitro/pkg/config"
+)
+
+func TestRepos(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nitro-workspace")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// create two repos, a directory that is not a repo, and a file
+	for _, d := range []string{"client-b/.git", "client-a/.git", "not-a-repo"} {
+		if err := os.MkdirAll(filepath.Join(dir, d), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "README.md"), []byte("notes"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Repos(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{filepath.Join(dir, "client-a"), filepath.Join(dir, "client-b")}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Repos() = %v, want %v", got, want)
+	}
+
+	if _, err := Repos(filepath.Join(dir, "missing")); err == nil {
+		t.Errorf("expected missing workspaces to return an error")
+	}
+}
+
+func TestSite(t *testing.T) {
+	home := "/home/nitro"
+	sites := []config.Site{
+		{Hostname: "client-a.nitro", Path: "~/dev/client-a"},
+		{Hostname: "client-b.nitro", Path: "~/dev/client-b"},
+	}
+
+	got := Site(home, "/home/nitro/dev/client-b", sites)
+	if got == nil || got.Hostname != "client-b.nitro" {
+		t.Errorf("Site() = %v, want client-b.nitro", got)
+	}
+
+	if got := Site(home, "/home/nitro/dev/client-c", sites); got != nil {
+		t.Errorf("Site() = %v, want nil", got)
+	}
+}
@@ -1,6 +1,11 @@
 package wsl
 
-import "os"
+import (
+	"os"
+	"regexp"
+	"runtime"
+	"strings"
+)
 
 // IsWSL will check for environment variable and determine if the
 // system is a WSL installation.
@@ -13,3 +18,78 @@ func IsWSL() bool {
 
 	return false
 }
+
+// Platform returns the platform nitro is running on, it is the operating system
+// (e.g. windows, darwin) or wsl when running inside WSL.
+func Platform() string {
+	return platform(runtime.GOOS, IsWSL())
+}
+
+func platform(goos string, isWSL bool) string {
+	if goos == "linux" && isWSL {
+		return "wsl"
+	}
+
+	return goos
+}
+
+// IsShared returns true when the platform shares the Docker Desktop backend
+// with the other side of WSL.
+func IsShared(platform string) bool {
+	return platform == "wsl" || platform == "windows"
+}
+
+// Path translates a path written on the other side of WSL to the form of the
+// current platform, so a config shared by WSL and Windows can be used for bind
+// mounts on both. Paths of the current platform are returned as is.
+func Path(p string) string {
+	return translate(Platform(), p)
+}
+
+func translate(platform, p string) string {
+	switch platform {
+	case "wsl":
+		return ToLinux(p)
+	case "windows":
+		return ToWindows(p)
+	}
+
+	return p
+}
+
+var (
+	drive = regexp.MustCompile(`^([A-Za-z]):([\\/]|$)`)
+	mount = regexp.MustCompile(`^/mnt/([a-z])(/|$)`)
+	share = regexp.MustCompile(`^(?i)[\\/]{2}wsl(\$|\.localhost)[\\/][^\\/]+`)
+)
+
+// ToLinux returns the path as seen from WSL. Windows drive paths are mounted
+// under /mnt (e.g. C:\Users\oli becomes /mnt/c/Users/oli) and WSL network
+// shares (e.g. \\wsl$\Ubuntu\home\oli) become the path in the distribution.
+func ToLinux(p string) string {
+	switch {
+	case drive.MatchString(p):
+		m := drive.FindStringSubmatch(p)
+		return strings.TrimSuffix("/mnt/"+strings.ToLower(m[1])+"/"+strings.ReplaceAll(p[len(m[0]):], `\`, "/"), "/")
+	case share.MatchString(p):
+		rest := strings.ReplaceAll(p[len(share.FindString(p)):], `\`, "/")
+		if rest == "" {
+			return "/"
+		}
+
+		return rest
+	}
+
+	return p
+}
+
+// ToWindows returns the path as seen from Windows, paths mounted under /mnt
+// (e.g. /mnt/c/Users/oli) become drive paths (e.g. C:\Users\oli). Other paths
+// are returned as is.
+func ToWindows(p string) string {
+	if m := mount.FindStringSubmatch(p); m != nil {
+		return strings.ToUpper(m[1]) + `:\` + strings.ReplaceAll(p[len(m[0]):], "/", `\`)
+	}
+
+	return p
+}
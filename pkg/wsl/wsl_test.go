@@ -47,3 +47,46 @@ func TestIsWSL(t *testing.T) {
 		})
 	}
 }
+
+func TestPath(t *testing.T) {
+	tests := []struct {
+		platform string
+		path     string
+		want     string
+	}{
+		{platform: "wsl", path: `C:\Users\oli\dev\craft`, want: "/mnt/c/Users/oli/dev/craft"},
+		{platform: "wsl", path: `d:/sites`, want: "/mnt/d/sites"},
+		{platform: "wsl", path: `C:\`, want: "/mnt/c"},
+		{platform: "wsl", path: `\\wsl$\Ubuntu\home\oli\dev`, want: "/home/oli/dev"},
+		{platform: "wsl", path: `\\wsl.localhost\Ubuntu\home\oli`, want: "/home/oli"},
+		{platform: "wsl", path: "/home/oli/dev", want: "/home/oli/dev"},
+		{platform: "windows", path: "/mnt/c/Users/oli/dev/craft", want: `C:\Users\oli\dev\craft`},
+		{platform: "windows", path: `C:\Users\oli`, want: `C:\Users\oli`},
+		{platform: "windows", path: "/home/oli/dev", want: "/home/oli/dev"},
+		{platform: "linux", path: `C:\Users\oli`, want: `C:\Users\oli`},
+		{platform: "darwin", path: "/mnt/c/Users/oli", want: "/mnt/c/Users/oli"},
+	}
+	for _, tt := range tests {
+		if got := translate(tt.platform, tt.path); got != tt.want {
+			t.Errorf("translate(%q, %q) = %q, want %q", tt.platform, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestPlatform(t *testing.T) {
+	tests := []struct {
+		goos  string
+		isWSL bool
+		want  string
+	}{
+		{goos: "linux", isWSL: true, want: "wsl"},
+		{goos: "linux", want: "linux"},
+		{goos: "windows", isWSL: true, want: "windows"},
+		{goos: "darwin", want: "darwin"},
+	}
+	for _, tt := range tests {
+		if got := platform(tt.goos, tt.isWSL); got != tt.want {
+			t.Errorf("platform(%q, %v) = %q, want %q", tt.goos, tt.isWSL, got, tt.want)
+		}
+	}
+}